@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// grpcBindTimeout is how long to retry binding GRPC_LISTEN_ADDR. Unlike the
+// HTTP listener it is not handed off on upgrade, so the new process waits
+// for the old one to release it.
+const grpcBindTimeout = 10 * time.Second
+
+// serveGRPC serves srv on addr in the background. A failure to listen is
+// fatal; the returned function stops the server, waiting for in-flight
+// calls until ctx is done.
+func serveGRPC(srv *grpc.Server, addr string) func(ctx context.Context) {
+	ln, err := net.Listen("tcp", addr)
+	for deadline := time.Now().Add(grpcBindTimeout); err != nil && time.Now().Before(deadline); {
+		time.Sleep(250 * time.Millisecond)
+		ln, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		fatalf("failed to listen for gRPC on %s: %v", addr, err)
+	}
+	slog.Info("Overture gRPC is running", "addr", displayAddr("grpc", ln.Addr().String()))
+	go func() {
+		if err := srv.Serve(ln); err != nil {
+			slog.Error("gRPC server failed", "error", err)
+		}
+	}()
+	return func(ctx context.Context) {
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			srv.Stop()
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// listenerFDEnv tells a freshly exec'd process which inherited file
+// descriptor holds the listening socket during a SIGUSR2 handoff.
+const listenerFDEnv = "OVERTURE_LISTENER_FD"
+
+// readyFDEnv tells a freshly exec'd process which inherited file descriptor
+// to write to once it is serving, so the old process knows it can drain.
+const readyFDEnv = "OVERTURE_READY_FD"
+
+// handoffReadyTimeout is how long the new process has to load its
+// configuration, assemble the app and start serving before the handoff is
+// abandoned.
+const handoffReadyTimeout = time.Minute
+
+// systemdFirstFD is SD_LISTEN_FDS_START: the first descriptor passed by
+// systemd socket activation.
+const systemdFirstFD = 3
+
+// inheritedListener returns a listener passed in by systemd socket activation
+// or by a parent process handing off during an upgrade. ok is false when the
+// process was started normally and should open its own listener.
+func inheritedListener() (ln net.Listener, ok bool, err error) {
+	fd := 0
+	switch {
+	case os.Getenv(listenerFDEnv) != "":
+		fd, err = strconv.Atoi(os.Getenv(listenerFDEnv))
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid %s: %w", listenerFDEnv, err)
+		}
+		_ = os.Unsetenv(listenerFDEnv)
+	case os.Getenv("LISTEN_PID") == strconv.Itoa(os.Getpid()):
+		count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+		if err != nil || count < 1 {
+			return nil, false, fmt.Errorf("invalid LISTEN_FDS %q", os.Getenv("LISTEN_FDS"))
+		}
+		fd = systemdFirstFD
+		// Unset so child processes don't mistake the descriptors for their own.
+		_ = os.Unsetenv("LISTEN_PID")
+		_ = os.Unsetenv("LISTEN_FDS")
+		_ = os.Unsetenv("LISTEN_FDNAMES")
+	default:
+		return nil, false, nil
+	}
+
+	f := os.NewFile(uintptr(fd), "inherited-listener")
+	defer f.Close()
+	ln, err = net.FileListener(f)
+	if err != nil {
+		return nil, false, fmt.Errorf("inherit listener fd %d: %w", fd, err)
+	}
+	return ln, true, nil
+}
+
+// notifyReady tells the process that handed its listener off to this one,
+// if any, that this one is serving.
+func notifyReady() error {
+	v := os.Getenv(readyFDEnv)
+	if v == "" {
+		return nil
+	}
+	_ = os.Unsetenv(readyFDEnv)
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", readyFDEnv, err)
+	}
+	f := os.NewFile(uintptr(fd), "handoff-ready")
+	defer f.Close()
+	if _, err := f.Write([]byte{1}); err != nil {
+		return fmt.Errorf("signal readiness: %w", err)
+	}
+	return nil
+}
+
+// awaitReady waits up to timeout for the new process to write to ready. It
+// fails early when the process exits first, closing its end of the pipe.
+func awaitReady(ready *os.File, timeout time.Duration) error {
+	if err := ready.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	_, err := ready.Read(make([]byte, 1))
+	switch {
+	case errors.Is(err, io.EOF):
+		return errors.New("exited before serving")
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		return fmt.Errorf("not serving after %v", timeout)
+	}
+	return err
+}
+
+// handoff starts a new copy of the running binary that inherits ln and
+// returns once it is serving, so this process can drain in-flight requests
+// while it accepts connections. If the new process fails to start or to get
+// ready within timeout, it is stopped and this process keeps serving.
+func handoff(ln net.Listener, timeout time.Duration) (*os.Process, error) {
+	type filer interface {
+		File() (*os.File, error)
+	}
+	fl, ok := ln.(filer)
+	if !ok {
+		return nil, fmt.Errorf("listener %T cannot be handed off", ln)
+	}
+	f, err := fl.File()
+	if err != nil {
+		return nil, fmt.Errorf("duplicate listener fd: %w", err)
+	}
+	defer f.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locate executable: %w", err)
+	}
+	ready, readyW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("create readiness pipe: %w", err)
+	}
+	defer ready.Close()
+
+	// #nosec G204 -- re-executes this same binary with its original arguments
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{f, readyW} // become fds 3 and 4 in the child
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("%s=%d", listenerFDEnv, systemdFirstFD),
+		fmt.Sprintf("%s=%d", readyFDEnv, systemdFirstFD+1))
+	err = cmd.Start()
+	// Only the child holds the write end now, so its exit ends the wait.
+	_ = readyW.Close()
+	if err != nil {
+		return nil, fmt.Errorf("start new process: %w", err)
+	}
+	if err := awaitReady(ready, timeout); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("new process: %w", err)
+	}
+
+	// The socket file now belongs to the new process; closing our listener
+	// during shutdown must not unlink it. Until the new process is serving
+	// it stays ours, so a failed handoff leaves shutdown as it was.
+	if ul, ok := ln.(*net.UnixListener); ok {
+		ul.SetUnlinkOnClose(false)
+	}
+	return cmd.Process, nil
+}
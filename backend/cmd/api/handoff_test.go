@@ -0,0 +1,162 @@
+//go:build unix
+
+package main
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// passFD returns a descriptor of f that inheritedListener or notifyReady may
+// take over and close, leaving f to the test.
+func passFD(t *testing.T, f *os.File) string {
+	t.Helper()
+	fd, err := syscall.Dup(int(f.Fd()))
+	if err != nil {
+		t.Fatalf("dup: %v", err)
+	}
+	return strconv.Itoa(fd)
+}
+
+func TestInheritedListener_Env(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{name: "started normally"},
+		{name: "another process's sockets", env: map[string]string{"LISTEN_PID": strconv.Itoa(os.Getpid() + 1), "LISTEN_FDS": "1"}},
+		{name: "invalid handoff fd", env: map[string]string{listenerFDEnv: "three"}, wantErr: true},
+		{name: "no sockets", env: map[string]string{"LISTEN_PID": pid, "LISTEN_FDS": "0"}, wantErr: true},
+		{name: "invalid LISTEN_FDS", env: map[string]string{"LISTEN_PID": pid, "LISTEN_FDS": "one"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{listenerFDEnv, "LISTEN_PID", "LISTEN_FDS"} {
+				t.Setenv(key, tt.env[key])
+			}
+			ln, ok, err := inheritedListener()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected err=%v, got %v", tt.wantErr, err)
+			}
+			if ok || ln != nil {
+				t.Fatalf("expected no listener, got %v", ln)
+			}
+		})
+	}
+}
+
+func TestInheritedListener_Handoff(t *testing.T) {
+	parent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer parent.Close()
+	f, err := parent.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("file: %v", err)
+	}
+	defer f.Close()
+	t.Setenv(listenerFDEnv, passFD(t, f))
+
+	ln, ok, err := inheritedListener()
+	if err != nil || !ok {
+		t.Fatalf("expected the handed off listener, got %v, %v", ok, err)
+	}
+	defer ln.Close()
+	if ln.Addr().String() != parent.Addr().String() {
+		t.Fatalf("expected %s, got %s", parent.Addr(), ln.Addr())
+	}
+	if v, set := os.LookupEnv(listenerFDEnv); set {
+		t.Fatalf("expected %s cleared for child processes, got %q", listenerFDEnv, v)
+	}
+}
+
+// TestInheritedListener_Systemd runs TestInheritedListener_SystemdHelper in a
+// child process with a listener at fd 3, as systemd passes it.
+func TestInheritedListener_Systemd(t *testing.T) {
+	parent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer parent.Close()
+	f, err := parent.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("file: %v", err)
+	}
+	defer f.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestInheritedListener_SystemdHelper$", "-test.v")
+	cmd.ExtraFiles = []*os.File{f}
+	cmd.Env = append(os.Environ(), "LISTEN_FDS=1", "OVERTURE_TEST_WANT_ADDR="+parent.Addr().String())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("helper failed: %v\n%s", err, out)
+	}
+}
+
+func TestInheritedListener_SystemdHelper(t *testing.T) {
+	want := os.Getenv("OVERTURE_TEST_WANT_ADDR")
+	if want == "" {
+		t.Skip("run by TestInheritedListener_Systemd")
+	}
+	// systemd sets LISTEN_PID to the pid it started, which only the child
+	// knows.
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+
+	ln, ok, err := inheritedListener()
+	if err != nil || !ok {
+		t.Fatalf("expected the socket-activated listener, got %v, %v", ok, err)
+	}
+	defer ln.Close()
+	if ln.Addr().String() != want {
+		t.Fatalf("expected %s, got %s", want, ln.Addr())
+	}
+	for _, key := range []string{"LISTEN_PID", "LISTEN_FDS"} {
+		if v, set := os.LookupEnv(key); set {
+			t.Fatalf("expected %s cleared for child processes, got %q", key, v)
+		}
+	}
+}
+
+func TestAwaitReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		child   func(t *testing.T, w *os.File)
+		wantErr bool
+	}{
+		{
+			name: "ready",
+			child: func(t *testing.T, w *os.File) {
+				t.Setenv(readyFDEnv, passFD(t, w))
+				if err := notifyReady(); err != nil {
+					t.Fatalf("notify: %v", err)
+				}
+			},
+		},
+		{name: "exited", child: func(*testing.T, *os.File) {}, wantErr: true},
+		{name: "timed out", child: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("pipe: %v", err)
+			}
+			defer r.Close()
+			defer w.Close()
+			if tt.child != nil {
+				tt.child(t, w)
+				_ = w.Close()
+			}
+			if err := awaitReady(r, 50*time.Millisecond); (err != nil) != tt.wantErr {
+				t.Fatalf("expected err=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
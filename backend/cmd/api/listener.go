@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+)
+
+// unixSocketPrefix marks LISTEN_ADDR values that name a unix domain socket,
+// e.g. unix:///run/overture/backend.sock.
+const unixSocketPrefix = "unix://"
+
+// newListener opens a TCP listener, or a unix socket listener when addr uses
+// the unix:// scheme. A stale socket file left behind by a previous process
+// is removed before binding; any other file at the path is left alone and
+// fails the listen.
+func newListener(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, unixSocketPrefix)
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("unix socket path is empty")
+	}
+
+	info, err := os.Lstat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("check stale socket: %w", err)
+	case info.Mode().Type() != fs.ModeSocket:
+		return nil, fmt.Errorf("%s exists and is not a socket", path)
+	default:
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	// Group access lets a sidecar running under a different user connect.
+	if err := os.Chmod(path, 0o660); err != nil { // #nosec G302 -- socket must be group-writable for sidecars
+		_ = ln.Close()
+		return nil, fmt.Errorf("chmod socket: %w", err)
+	}
+	return ln, nil
+}
+
+// displayAddr renders the listen address for startup logs.
+func displayAddr(scheme, addr string) string {
+	if strings.HasPrefix(addr, unixSocketPrefix) {
+		return addr
+	}
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	return scheme + "://" + addr
+}
@@ -0,0 +1,107 @@
+//go:build unix
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewListener_TCP(t *testing.T) {
+	ln, err := newListener("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	if ln.Addr().Network() != "tcp" {
+		t.Fatalf("expected a tcp listener, got %s", ln.Addr().Network())
+	}
+}
+
+func TestNewListener_Unix(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing func(t *testing.T, path string)
+	}{
+		{name: "new socket"},
+		{
+			name: "stale socket",
+			existing: func(t *testing.T, path string) {
+				old, err := net.Listen("unix", path)
+				if err != nil {
+					t.Fatalf("listen: %v", err)
+				}
+				// A process that died leaves its socket file behind.
+				old.(*net.UnixListener).SetUnlinkOnClose(false)
+				_ = old.Close()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "api.sock")
+			if tt.existing != nil {
+				tt.existing(t, path)
+			}
+
+			ln, err := newListener(unixSocketPrefix + path)
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			defer ln.Close()
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("stat: %v", err)
+			}
+			if perm := info.Mode().Perm(); perm != 0o660 {
+				t.Fatalf("expected mode 0660, got %o", perm)
+			}
+			conn, err := net.Dial("unix", path)
+			if err != nil {
+				t.Fatalf("expected the socket to accept connections, got %v", err)
+			}
+			_ = conn.Close()
+		})
+	}
+}
+
+func TestNewListener_UnixRefusesOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.sock")
+	if err := os.WriteFile(path, []byte("not a socket"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if ln, err := newListener(unixSocketPrefix + path); err == nil {
+		_ = ln.Close()
+		t.Fatal("expected an error for a regular file at the socket path")
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "not a socket" {
+		t.Fatalf("expected the file left alone, got %q, %v", data, err)
+	}
+}
+
+func TestNewListener_UnixEmptyPath(t *testing.T) {
+	if ln, err := newListener(unixSocketPrefix); err == nil {
+		_ = ln.Close()
+		t.Fatal("expected an error for an empty socket path")
+	}
+}
+
+func TestDisplayAddr(t *testing.T) {
+	tests := []struct {
+		scheme, addr string
+		want         string
+	}{
+		{scheme: "http", addr: ":8080", want: "http://localhost:8080"},
+		{scheme: "https", addr: "0.0.0.0:8443", want: "https://0.0.0.0:8443"},
+		{scheme: "grpc", addr: "127.0.0.1:9090", want: "grpc://127.0.0.1:9090"},
+		{scheme: "http", addr: "unix:///run/overture/backend.sock", want: "unix:///run/overture/backend.sock"},
+	}
+	for _, tt := range tests {
+		if got := displayAddr(tt.scheme, tt.addr); got != tt.want {
+			t.Fatalf("displayAddr(%q, %q) = %q, want %q", tt.scheme, tt.addr, got, tt.want)
+		}
+	}
+}
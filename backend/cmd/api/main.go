@@ -2,113 +2,150 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/ewilliams-labs/overture/backend/internal/adapters/ollama"
-	"github.com/ewilliams-labs/overture/backend/internal/adapters/rest"
-	"github.com/ewilliams-labs/overture/backend/internal/adapters/spotify"
-	"github.com/ewilliams-labs/overture/backend/internal/adapters/sqlite"
-	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
-	"github.com/ewilliams-labs/overture/backend/internal/core/services"
-	"github.com/ewilliams-labs/overture/backend/internal/worker"
+	"github.com/ewilliams-labs/overture/backend/internal/app"
+	"github.com/ewilliams-labs/overture/backend/internal/config"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
 )
 
+// version is the build version, set with -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
-	// 1. Configuration (Environment Variables)
-	// It's best practice to crash early if required config is missing.
-	clientID := os.Getenv("SPOTIFY_CLIENT_ID")
-	clientSecret := os.Getenv("SPOTIFY_CLIENT_SECRET")
-	fmt.Printf("DEBUG: Client ID length: %d\n", len(clientID))
-	fmt.Printf("DEBUG: Client Secret length: %d\n", len(clientSecret))
-	if clientID == "" || clientSecret == "" {
-		log.Fatal("FATAL: SPOTIFY_CLIENT_ID and SPOTIFY_CLIENT_SECRET environment variables are required")
+	// 1. Configuration (defaults, CONFIG_FILE, environment, flags)
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
-
-	// 2. Initialize "Driven" Adapters (The Tools)
-	// -- Database Adapter
-	storageDriver := os.Getenv("STORAGE_DRIVER")
-	if storageDriver == "" {
-		storageDriver = "sqlite"
+	if err != nil {
+		fatalf("invalid configuration: %v", err)
 	}
-
-	var repo ports.PlaylistRepository
-	var repoCloser func() error
-
-	switch storageDriver {
-	case "sqlite":
-		dbAdapter, err := sqlite.NewAdapter("overture.db")
-		if err != nil {
-			log.Fatalf("FATAL: Failed to initialize database: %v", err)
-		}
-		repo = dbAdapter
-		repoCloser = dbAdapter.Close
-	case "postgres":
-		log.Fatal("Postgres driver not yet implemented")
-	default:
-		log.Fatalf("Unknown storage driver: %s", storageDriver) // #nosec G706
+	// It's best practice to crash early if the configuration is unusable.
+	if err := cfg.Validate(); err != nil {
+		fatalf("invalid configuration: %v", err)
 	}
-	defer repoCloser()
-
-	// -- Spotify Adapter
-	spotifyClient := spotify.NewClient(clientID, clientSecret)
-
-	// 3. Initialize Core Logic (The Driver)
-	// This is Dependency Injection in action.
-	// We inject the specific adapters into the agnostic service.
-	// The compiler guarantees that dbAdapter implements ports.PlaylistRepository
-	// and spotifyClient implements ports.SpotifyClient.
-	intentCompiler := ollama.NewClient(os.Getenv("OLLAMA_HOST"))
-	svc := services.NewOrchestrator(spotifyClient, repo, intentCompiler)
+	setupLogging(cfg.Logging)
+	cfg.App.Outbound.Version = version
 
-	// 4. Initialize "Driving" Adapter (The Interface)
-	// The HTTP handler talks to the Service.
-	pool := worker.NewPool(repo, 2, 100)
-	pool.Start(2)
-	defer pool.Stop()
-
-	handler := rest.NewHandler(svc, pool)
+	// 2. Assemble adapters, the core service and workers.
+	application, err := app.New(cfg.App, app.WithDebugConfig(cfg.Redacted()))
+	if err != nil {
+		fatalf("%v", err)
+	}
+	defer application.Close()
 
-	// 5. Start the Server
-	log.Println("------------------------------------------------")
-	log.Println("🎶 Overture API is running on http://localhost:8080")
-	log.Println("------------------------------------------------")
+	// Background components share a context that is canceled on shutdown.
+	bgCtx, cancelBackground := context.WithCancel(context.Background())
+	defer cancelBackground()
+	application.Start(bgCtx)
 
+	// 3. Start the Server
+	addr := cfg.Server.ListenAddr
 	srv := &http.Server{
-		Addr:              ":8080",
-		Handler:           handler,
+		Handler:           application.Handler(),
 		ReadHeaderTimeout: 15 * time.Second,
 	}
+	serve := configureServer(srv, cfg.Server.TLS)
+	ln, inherited, err := inheritedListener()
+	if err != nil {
+		fatalf("failed to inherit listener: %v", err)
+	}
+	if inherited {
+		addr = ln.Addr().String()
+		slog.Info("inherited listener", "addr", addr)
+	} else if ln, err = newListener(addr); err != nil {
+		fatalf("failed to listen on %s: %v", addr, err)
+	}
+
+	slog.Info("Overture API is running", "addr", displayAddr(cfg.Server.TLS.Scheme(), addr), "version", version)
 
 	serverErr := make(chan error, 1)
 	go func() {
-		err := srv.ListenAndServe()
+		err := serve(ln)
 		if err != nil && err != http.ErrServerClosed {
 			serverErr <- err
 			return
 		}
 		serverErr <- nil
 	}()
+	// Let a process handing off to this one start draining. It keeps the
+	// gRPC port until then, so this comes before binding it.
+	if err := notifyReady(); err != nil {
+		slog.Warn("could not signal readiness to the previous process", "error", err)
+	}
+
+	stopGRPC := func(context.Context) {}
+	if cfg.Server.GRPCAddr != "" {
+		stopGRPC = serveGRPC(application.GRPCServer(), cfg.Server.GRPCAddr)
+	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	select {
-	case err := <-serverErr:
-		if err != nil {
-			log.Fatal(err)
-		}
-	case <-ctx.Done():
-		log.Println("Shutting down server...")
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		if err := srv.Shutdown(shutdownCtx); err != nil {
-			log.Printf("shutdown error: %v", err)
+	upgrade := make(chan os.Signal, 1)
+	if sigs := upgradeSignals(); len(sigs) > 0 {
+		signal.Notify(upgrade, sigs...)
+	}
+
+	// A handoff drains for longer than a plain shutdown so in-flight SSE
+	// intent streams (up to 120s of model reasoning) can finish.
+	shutdownTimeout := 10 * time.Second
+	for waiting := true; waiting; {
+		select {
+		case err := <-serverErr:
+			if err != nil {
+				fatalf("server error: %v", err)
+			}
+			return
+		case <-upgrade:
+			proc, err := handoff(ln, handoffReadyTimeout)
+			if err != nil {
+				slog.Warn("listener handoff failed", "error", err)
+				continue
+			}
+			slog.Info("handed off listener, draining connections", "pid", proc.Pid)
+			shutdownTimeout = cfg.Server.DrainTimeout
+			// Release the gRPC port for the new process now; in-flight calls
+			// drain alongside the HTTP ones.
+			go stopGRPC(context.Background())
+			waiting = false
+		case <-ctx.Done():
+			waiting = false
 		}
 	}
+
+	slog.Info("shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("shutdown failed", "error", err)
+	}
+	stopGRPC(shutdownCtx)
+}
+
+// setupLogging makes the default logger write records at cfg.Level to
+// stderr as cfg.Format. Packages still using the log package go through it
+// at info level.
+func setupLogging(cfg logging.Config) {
+	logger, err := logging.New(os.Stderr, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+}
+
+// fatalf logs an error and exits.
+func fatalf(format string, args ...any) {
+	slog.Error(fmt.Sprintf(format, args...))
+	os.Exit(1)
 }
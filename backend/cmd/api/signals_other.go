@@ -0,0 +1,11 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// upgradeSignals returns nothing on platforms without SIGUSR2; upgrades fall
+// back to a regular restart.
+func upgradeSignals() []os.Signal {
+	return nil
+}
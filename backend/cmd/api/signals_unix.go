@@ -0,0 +1,13 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// upgradeSignals are the signals that trigger a zero-downtime listener handoff.
+func upgradeSignals() []os.Signal {
+	return []os.Signal{syscall.SIGUSR2}
+}
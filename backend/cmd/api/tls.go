@@ -0,0 +1,42 @@
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/config"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// configureServer applies the TLS and HTTP/2 settings to srv and returns the
+// function that starts serving on a listener. HTTP/2 is negotiated via ALPN
+// when TLS is on; without TLS, h2c is only enabled on request since most
+// proxies expect HTTP/1.1. config.Config.Validate has already checked s.
+func configureServer(srv *http.Server, s config.TLSConfig) func(net.Listener) error {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(s.H2C)
+	srv.Protocols = protocols
+
+	switch {
+	case s.CertFile != "":
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		return func(ln net.Listener) error { return srv.ServeTLS(ln, s.CertFile, s.KeyFile) }
+	case len(s.AutocertDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(s.AutocertDomains...),
+			Cache:      autocert.DirCache(s.AutocertCacheDir),
+			Email:      s.AutocertEmail,
+		}
+		// manager.TLSConfig advertises h2 and acme-tls/1, so certificates are
+		// obtained through TLS-ALPN-01 without a separate port 80 listener.
+		srv.TLSConfig = manager.TLSConfig()
+		srv.TLSConfig.MinVersion = tls.VersionTLS12
+		return func(ln net.Listener) error { return srv.ServeTLS(ln, "", "") }
+	default:
+		return srv.Serve
+	}
+}
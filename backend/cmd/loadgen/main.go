@@ -0,0 +1,225 @@
+// Command loadgen drives a running backend with playlist traffic and reports
+// throughput, latency percentiles and worker saturation. Point it at a
+// server started with LOAD_TEST=true to avoid calling Spotify:
+//
+//	LOAD_TEST=true go run ./cmd/api
+//	go run ./cmd/loadgen -duration 30s -concurrency 16
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type sample struct {
+	op      string
+	latency time.Duration
+	status  int
+	err     error
+}
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:8080", "backend base URL")
+	duration := flag.Duration("duration", 30*time.Second, "how long to generate load")
+	concurrency := flag.Int("concurrency", 8, "number of concurrent clients")
+	artists := flag.Int("artists", 50, "distinct artists to draw tracks from")
+	titles := flag.Int("titles", 200, "distinct titles per artist")
+	readEvery := flag.Int("read-every", 10, "fetch the playlist after every N track additions (0 disables)")
+	flag.Parse()
+
+	base := strings.TrimRight(*baseURL, "/")
+	client := &http.Client{Timeout: 30 * time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+
+	samples := make(chan sample, 1024)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			runClient(ctx, client, base, id, *artists, *titles, *readEvery, samples)
+		}(i)
+	}
+
+	// Sample queue depth while the load runs to catch saturation peaks.
+	peakDepth := make(chan float64, 1)
+	go func() {
+		peak := 0.0
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				peakDepth <- peak
+				return
+			case <-ticker.C:
+				if m, err := scrapeWorkerMetrics(client, base); err == nil && m["overture_worker_queue_depth"] > peak {
+					peak = m["overture_worker_queue_depth"]
+				}
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(samples)
+	}()
+
+	start := time.Now()
+	byOp := map[string][]sample{}
+	for s := range samples {
+		byOp[s.op] = append(byOp[s.op], s)
+	}
+	elapsed := time.Since(start)
+
+	report(os.Stdout, byOp, elapsed)
+	fmt.Printf("\npeak worker queue depth: %.0f\n", <-peakDepth)
+	if m, err := scrapeWorkerMetrics(client, base); err == nil {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Printf("%s %g\n", k, m[k])
+		}
+	} else {
+		log.Printf("WARN loadgen: could not scrape /metrics: %v", err)
+	}
+}
+
+// runClient creates a playlist and keeps adding tracks to it, reading it
+// back periodically, until ctx is done.
+func runClient(ctx context.Context, client *http.Client, base string, id, artists, titles, readEvery int, out chan<- sample) {
+	var playlist struct {
+		ID string `json:"id"`
+	}
+	s := do(ctx, client, "create_playlist", http.MethodPost, base+"/playlists",
+		map[string]string{"name": fmt.Sprintf("loadgen-%d-%d", id, time.Now().UnixNano())}, &playlist)
+	out <- s
+	if s.err != nil || playlist.ID == "" {
+		return
+	}
+
+	for n := 1; ctx.Err() == nil; n++ {
+		track := map[string]string{
+			"title":  fmt.Sprintf("Track %d", rand.IntN(titles)+1),   // #nosec G404 -- load generation
+			"artist": fmt.Sprintf("Artist %d", rand.IntN(artists)+1), // #nosec G404 -- load generation
+		}
+		if s := do(ctx, client, "add_track", http.MethodPost, base+"/playlists/"+playlist.ID+"/tracks", track, nil); ctx.Err() == nil {
+			out <- s
+		}
+		if readEvery > 0 && n%readEvery == 0 {
+			if s := do(ctx, client, "get_playlist", http.MethodGet, base+"/playlists/"+playlist.ID, nil, nil); ctx.Err() == nil {
+				out <- s
+			}
+		}
+	}
+}
+
+func do(ctx context.Context, client *http.Client, op, method, url string, body any, into any) sample {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return sample{op: op, err: err}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req) // #nosec G107,G704 -- URL comes from the operator's -url flag
+	if err != nil {
+		return sample{op: op, latency: time.Since(start), err: err}
+	}
+	defer resp.Body.Close()
+	if into != nil && resp.StatusCode < 300 {
+		err = json.NewDecoder(resp.Body).Decode(into)
+	}
+	s := sample{op: op, latency: time.Since(start), status: resp.StatusCode, err: err}
+	if s.err == nil && resp.StatusCode >= 500 {
+		s.err = fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return s
+}
+
+func report(w io.Writer, byOp map[string][]sample, elapsed time.Duration) {
+	ops := make([]string, 0, len(byOp))
+	for op := range byOp {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	fmt.Fprintf(w, "%-16s %8s %8s %8s %10s %10s %10s\n", "operation", "count", "errors", "rps", "p50", "p95", "p99")
+	for _, op := range ops {
+		samples := byOp[op]
+		latencies := make([]time.Duration, 0, len(samples))
+		errs := 0
+		for _, s := range samples {
+			if s.err != nil {
+				errs++
+			}
+			latencies = append(latencies, s.latency)
+		}
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		fmt.Fprintf(w, "%-16s %8d %8d %8.1f %10s %10s %10s\n", op, len(samples), errs,
+			float64(len(samples))/elapsed.Seconds(),
+			percentile(latencies, 0.50), percentile(latencies, 0.95), percentile(latencies, 0.99))
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(p*float64(len(sorted)-1))].Round(time.Microsecond)
+}
+
+// scrapeWorkerMetrics returns every overture_worker_* series except
+// histogram buckets from the backend's /metrics endpoint, keyed by series.
+func scrapeWorkerMetrics(client *http.Client, base string) (map[string]float64, error) {
+	resp, err := client.Get(base + "/metrics") // #nosec G107 -- URL comes from the operator's -url flag
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	values := map[string]float64{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "overture_worker_") || strings.Contains(line, "_bucket") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		var v float64
+		if _, err := fmt.Sscan(value, &v); err != nil {
+			continue
+		}
+		values[name] = v
+	}
+	return values, scanner.Err()
+}
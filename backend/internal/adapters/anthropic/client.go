@@ -0,0 +1,303 @@
+// Package anthropic provides an intent compiler backed by the Anthropic
+// Messages API (https://docs.anthropic.com/en/api/messages), for deployments
+// that do not run Ollama. The model is forced to answer through a tool whose
+// input schema is the IntentObject shape, so replies are structured JSON
+// rather than free text.
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+	"github.com/ewilliams-labs/overture/backend/internal/prompt"
+)
+
+const (
+	defaultBaseURL = "https://api.anthropic.com"
+	defaultModel   = "claude-sonnet-4-5"
+	apiVersion     = "2023-06-01"
+	maxTokens      = 1024
+)
+
+// toolName is the tool the model must call; its input is the intent.
+const toolName = "compile_intent"
+
+// intentSchema is the JSON schema of domain.IntentObject.
+const intentSchema = `{
+	"type": "object",
+	"properties": {
+		"intent_type": {"type": "string", "enum": ["CREATE", "MODIFY"], "description": "What the user wants done: CREATE adds tracks, MODIFY changes the playlist."},
+		"entities": {
+			"type": "object",
+			"properties": {
+				"artists": {"type": "array", "items": {"type": "string"}},
+				"genres": {"type": "array", "items": {"type": "string"}}
+			},
+			"required": ["artists", "genres"]
+		},
+		"vibe_constraints": {
+			"type": "object",
+			"properties": {
+				"energy": {"$ref": "#/$defs/constraint"},
+				"valence": {"$ref": "#/$defs/constraint"},
+				"acousticness": {"$ref": "#/$defs/constraint"},
+				"instrumentalness": {"$ref": "#/$defs/constraint"}
+			}
+		},
+		"era": {
+			"type": "object",
+			"description": "Release years the tracks must fall within, for requests such as '90s only'.",
+			"properties": {
+				"min_year": {"type": "integer"},
+				"max_year": {"type": "integer"},
+				"decade": {"type": "string", "description": "A decade such as 90s or 1990s, used when the years are unset."}
+			}
+		},
+		"popularity": {
+			"type": "object",
+			"description": "Spotify popularity bounds, for requests such as 'deep cuts only' (a low max) or 'hits' (a high min).",
+			"properties": {
+				"min": {"type": "integer", "minimum": 0, "maximum": 100},
+				"max": {"type": "integer", "minimum": 0, "maximum": 100}
+			}
+		},
+		"duration": {
+			"type": "object",
+			"description": "How long the playlist should run, for requests such as 'a 45-minute running mix'.",
+			"properties": {
+				"minutes": {"type": "integer", "minimum": 1, "maximum": 1440},
+				"tolerance_minutes": {"type": "integer", "minimum": 0}
+			},
+			"required": ["minutes"]
+		},
+		"sequence": {
+			"type": "object",
+			"properties": {
+				"pattern": {"type": "string", "description": "How energy should move through the playlist: LINEAR (builds), PEAK (builds to a peak, then winds down), WAVE (rises and falls repeatedly) or SHUFFLE."},
+				"description": {"type": "string"}
+			}
+		},
+		"explanation": {"type": "string", "description": "One sentence on how the request was interpreted."}
+	},
+	"required": ["intent_type", "entities", "vibe_constraints", "explanation"],
+	"$defs": {
+		"constraint": {
+			"type": "object",
+			"properties": {
+				"target": {"type": "number", "minimum": 0, "maximum": 1},
+				"min": {"type": "number", "minimum": 0, "maximum": 1},
+				"max": {"type": "number", "minimum": 0, "maximum": 1},
+				"weight": {"type": "string", "enum": ["LOW", "MEDIUM", "HIGH"]}
+			}
+		}
+	}
+}`
+
+// Client implements ports.ConversationalCompiler.
+type Client struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+	prompts    *prompt.Set
+}
+
+// NewClient returns a Client using apiKey and model (default
+// claude-sonnet-4-5) against baseURL, or the public API when it is empty.
+func NewClient(apiKey, model, baseURL string) *Client {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	if model == "" {
+		model = defaultModel
+	}
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		model:      model,
+		httpClient: httpx.NewClient("anthropic", 60*time.Second, retryPolicy),
+		prompts:    prompt.Default(),
+	}
+}
+
+// EnablePrompts renders the system prompt from the "anthropic" template
+// of prompts instead of the embedded default.
+func (c *Client) EnablePrompts(prompts *prompt.Set) {
+	c.prompts = prompts
+}
+
+// retryPolicy retries rate limiting and overload (529) but not other
+// failures, as a rejected request would be rejected again.
+var retryPolicy = httpx.Policy{
+	MaxAttempts: 3,
+	BaseBackoff: time.Second,
+	MaxBackoff:  10 * time.Second,
+	Retryable: func(resp *http.Response, err error) bool {
+		if err != nil {
+			return true
+		}
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout, 529:
+			return true
+		}
+		return false
+	},
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type toolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type messagesRequest struct {
+	Model      string     `json:"model"`
+	MaxTokens  int        `json:"max_tokens"`
+	System     string     `json:"system"`
+	Messages   []message  `json:"messages"`
+	Tools      []tool     `json:"tools"`
+	ToolChoice toolChoice `json:"tool_choice"`
+}
+
+type contentBlock struct {
+	Type  string          `json:"type"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+type messagesResponse struct {
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// AnalyzeIntent implements ports.IntentCompiler.
+func (c *Client) AnalyzeIntent(ctx context.Context, msg string) (domain.IntentObject, error) {
+	return c.analyze(ctx, []message{{Role: "user", Content: msg}})
+}
+
+// AnalyzeIntentTurn implements ports.ConversationalCompiler. Each turn of
+// history is replayed as the user's message and the intent recorded for
+// it; the Messages API does not stream here, so onDelta is ignored.
+func (c *Client) AnalyzeIntentTurn(ctx context.Context, history []domain.IntentTurn, msg string, _ func(domain.IntentDelta)) (domain.IntentObject, error) {
+	messages := make([]message, 0, 2*len(history)+1)
+	for _, turn := range history {
+		intent, err := json.Marshal(turn.Intent)
+		if err != nil {
+			return domain.IntentObject{}, fmt.Errorf("anthropic: marshal history: %w", err)
+		}
+		messages = append(messages,
+			message{Role: "user", Content: turn.Message},
+			message{Role: "assistant", Content: "Recorded intent: " + string(intent)},
+		)
+	}
+	return c.analyze(ctx, append(messages, message{Role: "user", Content: msg}))
+}
+
+// analyze asks the model to record the intent behind the conversation in
+// messages.
+func (c *Client) analyze(ctx context.Context, messages []message) (domain.IntentObject, error) {
+	system, err := c.prompts.Render("anthropic", ports.PlaylistSummaryFrom(ctx))
+	if err != nil {
+		return domain.IntentObject{}, fmt.Errorf("anthropic: %w", err)
+	}
+	payload := messagesRequest{
+		Model:     c.model,
+		MaxTokens: maxTokens,
+		System:    system,
+		Messages:  messages,
+		Tools: []tool{{
+			Name:        toolName,
+			Description: "Record the structured music intent behind the user's message.",
+			InputSchema: json.RawMessage(intentSchema),
+		}},
+		ToolChoice: toolChoice{Type: "tool", Name: toolName},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return domain.IntentObject{}, fmt.Errorf("anthropic: marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return domain.IntentObject{}, fmt.Errorf("anthropic: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", apiVersion)
+
+	start := time.Now()
+	intent, outcome, err := c.send(req)
+	requestDuration.Observe(time.Since(start).Seconds(), outcome)
+	if err != nil {
+		requestFailures.Inc(outcome)
+		return domain.IntentObject{}, err
+	}
+	return intent, nil
+}
+
+// send sends req and extracts the intent from the tool call in the reply.
+// outcome classifies the result for metrics: ok, network, status, decode
+// or schema.
+func (c *Client) send(req *http.Request) (domain.IntentObject, string, error) {
+	resp, err := c.httpClient.Do(req) // #nosec G107,G704
+	if err != nil {
+		return domain.IntentObject{}, "network", fmt.Errorf("anthropic: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr errorResponse
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Type != "" {
+			return domain.IntentObject{}, "status", fmt.Errorf("anthropic: unexpected status %d: %s", resp.StatusCode, apiErr.Error.Type)
+		}
+		return domain.IntentObject{}, "status", fmt.Errorf("anthropic: unexpected status %d", resp.StatusCode)
+	}
+
+	var parsed messagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return domain.IntentObject{}, "decode", fmt.Errorf("anthropic: decode response: %w", err)
+	}
+	if parsed.StopReason == "max_tokens" {
+		return domain.IntentObject{}, "schema", fmt.Errorf("anthropic: response truncated at %d tokens", maxTokens)
+	}
+	for _, block := range parsed.Content {
+		if block.Type != "tool_use" || block.Name != toolName {
+			continue
+		}
+		var intent domain.IntentObject
+		if err := json.Unmarshal(block.Input, &intent); err != nil {
+			return domain.IntentObject{}, "schema", fmt.Errorf("anthropic: decode intent: %w", err)
+		}
+		if intent.IntentType == "" {
+			return domain.IntentObject{}, "schema", fmt.Errorf("anthropic: intent has no intent_type")
+		}
+		return intent, "ok", nil
+	}
+	return domain.IntentObject{}, "schema", fmt.Errorf("anthropic: no %s call in response", toolName)
+}
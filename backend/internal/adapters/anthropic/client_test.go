@@ -0,0 +1,76 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_AnalyzeIntent(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantErr      bool
+		wantArtist   string
+		wantAcoustic float64
+	}{
+		{
+			name:         "Tool call",
+			status:       http.StatusOK,
+			body:         `{"content":[{"type":"text","text":"Sure."},{"type":"tool_use","id":"tu_1","name":"compile_intent","input":{"intent_type":"CREATE","entities":{"artists":["Willie Nelson"],"genres":[]},"vibe_constraints":{"acousticness":{"min":0.8,"weight":"HIGH"}},"explanation":"Test"}}],"stop_reason":"tool_use"}`,
+			wantArtist:   "Willie Nelson",
+			wantAcoustic: 0.8,
+		},
+		{name: "No tool call", status: http.StatusOK, body: `{"content":[{"type":"text","text":"I can't help."}],"stop_reason":"end_turn"}`, wantErr: true},
+		{name: "Missing intent type", status: http.StatusOK, body: `{"content":[{"type":"tool_use","name":"compile_intent","input":{"entities":{"artists":[],"genres":[]}}}],"stop_reason":"tool_use"}`, wantErr: true},
+		{name: "Mistyped field", status: http.StatusOK, body: `{"content":[{"type":"tool_use","name":"compile_intent","input":{"intent_type":"CREATE","entities":{"artists":"Willie"}}}],"stop_reason":"tool_use"}`, wantErr: true},
+		{name: "Truncated", status: http.StatusOK, body: `{"content":[],"stop_reason":"max_tokens"}`, wantErr: true},
+		{name: "Bad API key", status: http.StatusUnauthorized, body: `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, wantErr: true},
+		{name: "Malformed response", status: http.StatusOK, body: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got messagesRequest
+			var header http.Header
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header
+				if r.URL.Path != "/v1/messages" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			intent, err := NewClient("key-123", "", srv.URL).AnalyzeIntent(context.Background(), "some acoustic Willie Nelson")
+			if header.Get("x-api-key") != "key-123" || header.Get("anthropic-version") != apiVersion {
+				t.Fatalf("expected API key and version headers, got %v", header)
+			}
+			if got.Model != defaultModel || got.ToolChoice.Name != toolName || len(got.Tools) != 1 || !json.Valid(got.Tools[0].InputSchema) {
+				t.Fatalf("expected a forced %s call with a valid schema, got %+v", toolName, got)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", intent)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(intent.Entities.Artists) != 1 || intent.Entities.Artists[0] != tt.wantArtist {
+				t.Fatalf("expected artist %q, got %v", tt.wantArtist, intent.Entities.Artists)
+			}
+			if intent.VibeConstraints.Acoustic == nil || intent.VibeConstraints.Acoustic.Min != tt.wantAcoustic {
+				t.Fatalf("expected acousticness min %v, got %+v", tt.wantAcoustic, intent.VibeConstraints.Acoustic)
+			}
+		})
+	}
+}
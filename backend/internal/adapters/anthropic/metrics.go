@@ -0,0 +1,17 @@
+package anthropic
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var (
+	requestDuration = metrics.NewHistogramVec(
+		"overture_anthropic_request_duration_seconds",
+		"Latency of Anthropic Messages API calls, by outcome (ok, network, status, decode, schema).",
+		[]float64{0.5, 1, 2.5, 5, 10, 20, 30, 60},
+		"outcome",
+	)
+	requestFailures = metrics.NewCounterVec(
+		"overture_anthropic_failures_total",
+		"Failed Anthropic Messages API calls, by cause (network, status, decode, schema).",
+		"cause",
+	)
+)
@@ -0,0 +1,213 @@
+// Package applemusic provides a catalog adapter for the Apple Music API
+// (https://developer.apple.com/documentation/applemusicapi). It looks
+// tracks up so the provider registry can fill in and stand in for Spotify.
+package applemusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+	"github.com/ewilliams-labs/overture/backend/internal/match"
+)
+
+const (
+	defaultBaseURL    = "https://api.music.apple.com"
+	defaultStorefront = "us"
+	// coverSize is the edge in pixels of the artwork requested for covers.
+	coverSize = 640
+)
+
+// Config configures a Client. Token is a developer token signed with a
+// MusicKit key; Storefront is the catalog country (default "us") and URL
+// overrides the public API.
+type Config struct {
+	Token      string
+	Storefront string
+	URL        string
+}
+
+// Client implements ports.CatalogProvider.
+type Client struct {
+	baseURL    string
+	storefront string
+	token      string
+	httpClient *http.Client
+}
+
+var _ ports.CatalogProvider = (*Client)(nil)
+
+// NewClient returns a Client for cfg.
+func NewClient(cfg Config) *Client {
+	baseURL := strings.TrimRight(cfg.URL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	storefront := cfg.Storefront
+	if storefront == "" {
+		storefront = defaultStorefront
+	}
+	return &Client{
+		baseURL:    baseURL,
+		storefront: storefront,
+		token:      cfg.Token,
+		httpClient: httpx.NewClient("applemusic", 10*time.Second, httpx.DefaultPolicy()),
+	}
+}
+
+type song struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name             string `json:"name"`
+		ArtistName       string `json:"artistName"`
+		AlbumName        string `json:"albumName"`
+		DurationInMillis int    `json:"durationInMillis"`
+		ISRC             string `json:"isrc"`
+		ContentRating    string `json:"contentRating"`
+		Artwork          struct {
+			URL string `json:"url"`
+		} `json:"artwork"`
+		Previews []struct {
+			URL string `json:"url"`
+		} `json:"previews"`
+	} `json:"attributes"`
+}
+
+// track maps s to a domain track. IDs are prefixed with the source so they
+// cannot collide with Spotify IDs.
+func (s song) track() domain.Track {
+	a := s.Attributes
+	t := domain.Track{
+		ID:         domain.SourceAppleMusic + ":" + s.ID,
+		Title:      a.Name,
+		Artist:     a.ArtistName,
+		Album:      a.AlbumName,
+		DurationMs: a.DurationInMillis,
+		ISRC:       a.ISRC,
+		Explicit:   a.ContentRating == "explicit",
+		Source:     domain.SourceAppleMusic,
+	}
+	if a.Artwork.URL != "" {
+		size := strconv.Itoa(coverSize)
+		t.CoverURL = strings.NewReplacer("{w}", size, "{h}", size).Replace(a.Artwork.URL)
+	}
+	if len(a.Previews) > 0 {
+		t.PreviewURL = a.Previews[0].URL
+	}
+	return t
+}
+
+// GetTrack implements ports.CatalogProvider. It returns the first search
+// result by the artist whose title contains the requested one or is
+// contained in it.
+func (c *Client) GetTrack(ctx context.Context, title, artist string) (domain.Track, error) {
+	q := url.Values{}
+	q.Set("term", artist+" "+title)
+	q.Set("types", "songs")
+	q.Set("limit", "10")
+	var body struct {
+		Results struct {
+			Songs struct {
+				Data []song `json:"data"`
+			} `json:"songs"`
+		} `json:"results"`
+	}
+	if err := c.get(ctx, "search", "/search?"+q.Encode(), &body); err != nil {
+		return domain.Track{}, err
+	}
+	for _, s := range body.Results.Songs.Data {
+		if match.Default.Matches(title, artist, s.Attributes.Name, s.Attributes.ArtistName) {
+			return s.track(), nil
+		}
+	}
+	return domain.Track{}, ports.NoConfidentMatchError{Title: title, Artist: artist}
+}
+
+// GetArtistTopTracks implements ports.CatalogProvider with the top songs of
+// the first artist found by name.
+func (c *Client) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	q := url.Values{}
+	q.Set("term", artistName)
+	q.Set("types", "artists")
+	q.Set("limit", "5")
+	var search struct {
+		Results struct {
+			Artists struct {
+				Data []struct {
+					ID         string `json:"id"`
+					Attributes struct {
+						Name string `json:"name"`
+					} `json:"attributes"`
+				} `json:"data"`
+			} `json:"artists"`
+		} `json:"results"`
+	}
+	if err := c.get(ctx, "search", "/search?"+q.Encode(), &search); err != nil {
+		return nil, err
+	}
+	var artistID string
+	for _, a := range search.Results.Artists.Data {
+		if match.Simplify(a.Attributes.Name) == match.Simplify(artistName) {
+			artistID = a.ID
+			break
+		}
+	}
+	if artistID == "" {
+		return nil, fmt.Errorf("applemusic: artist %q: %w", artistName, domain.ErrNotFound)
+	}
+
+	var top struct {
+		Data []song `json:"data"`
+	}
+	if err := c.get(ctx, "top_songs", "/artists/"+url.PathEscape(artistID)+"/view/top-songs", &top); err != nil {
+		return nil, err
+	}
+	tracks := make([]domain.Track, 0, len(top.Data))
+	for _, s := range top.Data {
+		tracks = append(tracks, s.track())
+	}
+	return tracks, nil
+}
+
+// get fetches path below the storefront's catalog into dst.
+func (c *Client) get(ctx context.Context, endpoint, path string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/catalog/"+url.PathEscape(c.storefront)+path, nil)
+	if err != nil {
+		return fmt.Errorf("applemusic: build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	start := time.Now()
+	outcome, err := c.do(req, dst)
+	requestDuration.Observe(time.Since(start).Seconds(), endpoint, outcome)
+	return err
+}
+
+// do sends req and decodes the reply. outcome classifies the result for
+// metrics.
+func (c *Client) do(req *http.Request, dst any) (string, error) {
+	resp, err := c.httpClient.Do(req) // #nosec G107,G704 -- base URL comes from configuration
+	if err != nil {
+		return "network", fmt.Errorf("applemusic: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "not_found", fmt.Errorf("applemusic: %w", domain.ErrNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "status", fmt.Errorf("applemusic: unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return "decode", fmt.Errorf("applemusic: decode response: %w", err)
+	}
+	return "ok", nil
+}
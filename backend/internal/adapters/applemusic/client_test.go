@@ -0,0 +1,114 @@
+package applemusic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+const searchSongs = `{"results":{"songs":{"data":[
+	{"id":"10","attributes":{"name":"Song (Cover)","artistName":"Someone Else"}},
+	{"id":"11","attributes":{"name":"Song - Remastered","artistName":"The Band","albumName":"Album",
+	 "durationInMillis":210000,"isrc":"USABC1234567",
+	 "artwork":{"url":"https://art/{w}x{h}bb.jpg"},"previews":[{"url":"https://audio/preview.m4a"}]}}
+]}}}`
+
+func TestClient_GetTrack(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantID       string
+		wantNoMatch  bool
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{name: "Success", status: http.StatusOK, body: searchSongs, wantID: "apple_music:11"},
+		{name: "No confident match", status: http.StatusOK, body: `{"results":{}}`, wantErr: true, wantNoMatch: true},
+		{name: "Not found", status: http.StatusNotFound, body: `{}`, wantErr: true, wantNotFound: true},
+		{name: "Server error", status: http.StatusInternalServerError, body: `{}`, wantErr: true},
+		{name: "Malformed response", status: http.StatusOK, body: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := NewClient(Config{Token: "dev-token", Storefront: "gb", URL: srv.URL})
+			track, err := client.GetTrack(context.Background(), "Song", "The Band")
+
+			if got.URL.Path != "/v1/catalog/gb/search" || got.URL.Query().Get("types") != "songs" {
+				t.Errorf("unexpected request %s", got.URL)
+			}
+			if auth := got.Header.Get("Authorization"); auth != "Bearer dev-token" {
+				t.Errorf("expected the developer token, got %q", auth)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				var noMatch ports.NoConfidentMatchError
+				if errors.As(err, &noMatch) != tt.wantNoMatch {
+					t.Errorf("expected no-match %v, got %v", tt.wantNoMatch, err)
+				}
+				if errors.Is(err, domain.ErrNotFound) != tt.wantNotFound {
+					t.Errorf("expected not-found %v, got %v", tt.wantNotFound, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := domain.Track{
+				ID: "apple_music:11", Title: "Song - Remastered", Artist: "The Band", Album: "Album",
+				DurationMs: 210000, ISRC: "USABC1234567", CoverURL: "https://art/640x640bb.jpg",
+				PreviewURL: "https://audio/preview.m4a", Source: domain.SourceAppleMusic,
+			}
+			if track.ID != want.ID || track.ISRC != want.ISRC || track.CoverURL != want.CoverURL ||
+				track.PreviewURL != want.PreviewURL || track.Source != want.Source || track.DurationMs != want.DurationMs {
+				t.Errorf("expected %+v, got %+v", want, track)
+			}
+		})
+	}
+}
+
+func TestClient_GetArtistTopTracks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/catalog/us/search":
+			_, _ = w.Write([]byte(`{"results":{"artists":{"data":[
+				{"id":"1","attributes":{"name":"The Band Tribute"}},
+				{"id":"2","attributes":{"name":"The Band"}}
+			]}}}`))
+		case "/v1/catalog/us/artists/2/view/top-songs":
+			_, _ = w.Write([]byte(`{"data":[{"id":"21","attributes":{"name":"Hit","artistName":"The Band","isrc":"USABC0000001"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{URL: srv.URL})
+	tracks, err := client.GetArtistTopTracks(context.Background(), "the band")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].ID != "apple_music:21" || tracks[0].Source != domain.SourceAppleMusic {
+		t.Errorf("unexpected tracks %+v", tracks)
+	}
+
+	if _, err := client.GetArtistTopTracks(context.Background(), "Nobody"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected not found for an unknown artist, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+package applemusic
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var requestDuration = metrics.NewHistogramVec(
+	"overture_applemusic_request_duration_seconds",
+	"Latency of Apple Music API calls, by endpoint (search, top_songs) and outcome (ok, not_found, network, status, decode).",
+	nil,
+	"endpoint", "outcome",
+)
@@ -0,0 +1,174 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// fakeS3 is an in-memory, path-style S3 endpoint for a single bucket.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/bucket":
+		type content struct {
+			Key          string
+			Size         int64
+			LastModified time.Time
+		}
+		var result struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []content
+		}
+		keys := make([]string, 0, len(f.objects))
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			result.Contents = append(result.Contents, content{Key: k, Size: int64(len(f.objects[k])), LastModified: time.Now()})
+		}
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+	case r.Method == http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestBlobStores(t *testing.T) {
+	tests := []struct {
+		name     string
+		newStore func(t *testing.T) ports.BlobStore
+	}{
+		{
+			name: "local",
+			newStore: func(t *testing.T) ports.BlobStore {
+				store, err := NewLocalStore(t.TempDir())
+				if err != nil {
+					t.Fatalf("new local store: %v", err)
+				}
+				return store
+			},
+		},
+		{
+			name: "s3",
+			newStore: func(t *testing.T) ports.BlobStore {
+				srv := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+				t.Cleanup(srv.Close)
+				store, err := NewS3Store(S3Config{
+					Endpoint:  srv.URL,
+					Bucket:    "bucket",
+					AccessKey: "AKID",
+					SecretKey: "secret",
+					Prefix:    "overture/",
+				})
+				if err != nil {
+					t.Fatalf("new s3 store: %v", err)
+				}
+				return store
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := tt.newStore(t)
+			ctx := context.Background()
+
+			for key, body := range map[string]string{
+				"backups/a.db": "first",
+				"backups/b.db": "second",
+				"covers/c.jpg": "image",
+			} {
+				if err := store.Put(ctx, key, strings.NewReader(body), int64(len(body))); err != nil {
+					t.Fatalf("put %s: %v", key, err)
+				}
+			}
+
+			rc, err := store.Get(ctx, "backups/b.db")
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			got, _ := io.ReadAll(rc)
+			rc.Close()
+			if !bytes.Equal(got, []byte("second")) {
+				t.Fatalf("get: got %q, want %q", got, "second")
+			}
+
+			if _, err := store.Get(ctx, "backups/missing.db"); !errors.Is(err, domain.ErrNotFound) {
+				t.Fatalf("get missing: got %v, want ErrNotFound", err)
+			}
+
+			if err := store.Delete(ctx, "backups/a.db"); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			if err := store.Delete(ctx, "backups/a.db"); err != nil {
+				t.Fatalf("delete missing: %v", err)
+			}
+
+			list, err := store.List(ctx, "backups/")
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(list) != 1 || list[0].Key != "backups/b.db" || list[0].Size != int64(len("second")) {
+				t.Fatalf("list: unexpected result %+v", list)
+			}
+		})
+	}
+}
+
+func TestLocalStore_RejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local store: %v", err)
+	}
+	for _, key := range []string{"", "/", "dir/"} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1); err == nil {
+			t.Errorf("Put(%q): expected error", key)
+		}
+	}
+	// Traversal is confined to the root rather than rejected.
+	if err := store.Put(context.Background(), "../../escape.txt", strings.NewReader("x"), 1); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	list, err := store.List(context.Background(), "")
+	if err != nil || len(list) != 1 || list[0].Key != "escape.txt" {
+		t.Fatalf("expected traversal key to land inside root, got %+v (err %v)", list, err)
+	}
+}
@@ -0,0 +1,288 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+)
+
+// S3Config identifies a bucket on any S3-compatible service. GCS works
+// through its interoperability endpoint (https://storage.googleapis.com)
+// with HMAC keys.
+type S3Config struct {
+	Endpoint  string // e.g. https://s3.us-east-1.amazonaws.com; defaults from Region
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// Prefix is prepended to every key so several deployments can share a bucket.
+	Prefix string
+}
+
+// S3Store implements ports.BlobStore over the S3 REST API using path-style
+// addressing and Signature Version 4.
+type S3Store struct {
+	cfg    S3Config
+	base   *url.URL
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store validates cfg and creates a store.
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("blob: s3 bucket and credentials are required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	base, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("blob: invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	return &S3Store{
+		cfg:    cfg,
+		base:   base,
+		client: httpx.NewClient("s3", 5*time.Minute, httpx.DefaultPolicy()),
+		now:    time.Now,
+	}, nil
+}
+
+func (s *S3Store) objectURL(key string) *url.URL {
+	u := *s.base
+	u.Path = u.Path + "/" + s.cfg.Bucket + "/" + s.cfg.Prefix + key
+	// Send exactly the encoding that is signed; net/url would leave
+	// characters such as '+' unescaped.
+	u.RawPath = canonicalURI(u.Path)
+	return &u
+}
+
+// Put implements ports.BlobStore. size must be the exact length of r.
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), r)
+	if err != nil {
+		return fmt.Errorf("blob: failed to build request: %w", err)
+	}
+	req.ContentLength = size
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("blob: failed to put %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get implements ports.BlobStore.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("blob: failed to build request: %w", err)
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("blob: failed to get %s: %w", key, err)
+	}
+	return resp.Body, nil
+}
+
+// Delete implements ports.BlobStore.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return fmt.Errorf("blob: failed to build request: %w", err)
+	}
+	resp, err := s.do(req)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("blob: failed to delete %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List implements ports.BlobStore, following continuation tokens until the
+// listing is complete.
+func (s *S3Store) List(ctx context.Context, prefix string) ([]ports.BlobInfo, error) {
+	blobs := []ports.BlobInfo{}
+	token := ""
+	for {
+		u := *s.base
+		u.Path = u.Path + "/" + s.cfg.Bucket
+		q := url.Values{"list-type": {"2"}, "prefix": {s.cfg.Prefix + prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		u.RawQuery = q.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("blob: failed to build request: %w", err)
+		}
+		resp, err := s.do(req)
+		if err != nil {
+			return nil, fmt.Errorf("blob: failed to list %q: %w", prefix, err)
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("blob: failed to decode listing: %w", err)
+		}
+
+		for _, c := range page.Contents {
+			blobs = append(blobs, ports.BlobInfo{
+				Key:     strings.TrimPrefix(c.Key, s.cfg.Prefix),
+				Size:    c.Size,
+				ModTime: c.LastModified,
+			})
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Key < blobs[j].Key })
+	return blobs, nil
+}
+
+// do signs and sends req. Non-2xx responses are returned as errors, with 404
+// mapped to domain.ErrNotFound.
+func (s *S3Store) do(req *http.Request) (*http.Response, error) {
+	s.sign(req)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, domain.ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// unsignedPayload lets uploads stream without hashing the body up front;
+// integrity is still protected by TLS.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// sign adds AWS Signature Version 4 headers to req.
+func (s *S3Store) sign(req *http.Request) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + unsignedPayload + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalURI encodes each path segment per SigV4 (RFC 3986 unreserved
+// characters are left as is).
+func canonicalURI(p string) string {
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,272 @@
+// Package breaker wraps provider ports with circuit breakers. After a run
+// of consecutive failures a breaker opens and calls fail at once with a
+// ports.ProviderUnavailableError instead of waiting out the provider's
+// timeouts and retries. Once the cooldown has passed it lets a single probe
+// through: success closes it again, failure keeps it open for another
+// cooldown.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// Config configures a Breaker.
+type Config struct {
+	// Failures is how many calls in a row must fail to open the breaker.
+	Failures int
+	// Cooldown is how long the breaker stays open before probing.
+	Cooldown time.Duration
+}
+
+// State is the position of a breaker.
+type State int
+
+const (
+	// Closed passes calls through.
+	Closed State = iota
+	// HalfOpen passes a single probe through.
+	HalfOpen
+	// Open fails calls without making them.
+	Open
+)
+
+func (s State) String() string {
+	switch s {
+	case HalfOpen:
+		return "half_open"
+	case Open:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker tracks the health of one provider.
+type Breaker struct {
+	name string
+	cfg  Config
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+	// opened counts the times the breaker opened, so outcomes of calls
+	// started before the last time can be told apart.
+	opened uint64
+}
+
+// call is a call allowed through, as record needs to know it.
+type call struct {
+	opened uint64
+	probe  bool
+}
+
+// New returns a closed breaker for the provider called name. Failures is at
+// least 1.
+func New(name string, cfg Config) *Breaker {
+	if cfg.Failures < 1 {
+		cfg.Failures = 1
+	}
+	b := &Breaker{name: name, cfg: cfg, now: time.Now}
+	breakerState.Set(float64(Closed), name)
+	return b
+}
+
+// State returns the breaker's current position.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.cfg.Cooldown {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// Do calls fn unless the breaker is open, and records its outcome.
+func (b *Breaker) Do(ctx context.Context, fn func() error) error {
+	c, err := b.allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	b.record(ctx, c, err)
+	return err
+}
+
+// allow reports whether a call may go ahead, claiming the probe when the
+// cooldown is over.
+func (b *Breaker) allow() (call, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case Closed:
+		return call{opened: b.opened}, nil
+	case Open:
+		wait := b.cfg.Cooldown - b.now().Sub(b.openedAt)
+		if wait > 0 {
+			breakerRejected.Inc(b.name)
+			return call{}, ports.ProviderUnavailableError{Provider: b.name, RetryAfter: wait}
+		}
+		b.setState(HalfOpen)
+	}
+	if b.probing {
+		breakerRejected.Inc(b.name)
+		return call{}, ports.ProviderUnavailableError{Provider: b.name, RetryAfter: b.cfg.Cooldown}
+	}
+	b.probing = true
+	return call{opened: b.opened, probe: true}, nil
+}
+
+// record counts err against the provider unless it is an answer rather than
+// a failure, such as a track that does not exist. Calls the caller canceled
+// and calls started before the breaker last opened say nothing of the
+// provider now and leave the breaker as it is; a canceled probe only makes
+// way for the next one.
+func (b *Breaker) record(ctx context.Context, c call, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c.probe {
+		b.probing = false
+	}
+	if c.opened != b.opened || canceled(ctx, err) {
+		return
+	}
+	if err == nil || !failure(err) {
+		b.failures = 0
+		b.setState(Closed)
+		return
+	}
+	b.failures++
+	if c.probe || b.failures >= b.cfg.Failures {
+		b.openedAt = b.now()
+		b.opened++
+		b.setState(Open)
+	}
+}
+
+// setState moves the breaker to s. b.mu must be held.
+func (b *Breaker) setState(s State) {
+	if b.state == s {
+		return
+	}
+	b.state = s
+	breakerState.Set(float64(s), b.name)
+	breakerTransitions.Inc(b.name, s.String())
+}
+
+// canceled reports whether err comes from the caller giving up on ctx.
+func canceled(ctx context.Context, err error) bool {
+	return errors.Is(err, context.Canceled) && ctx.Err() != nil
+}
+
+// failure reports whether err says the provider is unhealthy.
+func failure(err error) bool {
+	return !errors.Is(err, domain.ErrNotFound) && !errors.Is(err, ports.ErrNoConfidentMatch)
+}
+
+// SpotifyProvider guards a ports.SpotifyProvider with a breaker.
+type SpotifyProvider struct {
+	next    ports.SpotifyProvider
+	breaker *Breaker
+}
+
+// WrapSpotify guards next with a breaker for the provider called name.
+func WrapSpotify(next ports.SpotifyProvider, name string, cfg Config) *SpotifyProvider {
+	return &SpotifyProvider{next: next, breaker: New(name, cfg)}
+}
+
+// GetTrackByMetadata implements ports.SpotifyProvider.
+func (s *SpotifyProvider) GetTrackByMetadata(ctx context.Context, title, artist string) (domain.Track, error) {
+	var track domain.Track
+	err := s.breaker.Do(ctx, func() (err error) {
+		track, err = s.next.GetTrackByMetadata(ctx, title, artist)
+		return err
+	})
+	return track, err
+}
+
+// GetTrack implements ports.SpotifyProvider.
+func (s *SpotifyProvider) GetTrack(ctx context.Context, title, artist string) (domain.Track, error) {
+	var track domain.Track
+	err := s.breaker.Do(ctx, func() (err error) {
+		track, err = s.next.GetTrack(ctx, title, artist)
+		return err
+	})
+	return track, err
+}
+
+// GetArtistTopTracks implements ports.SpotifyProvider.
+func (s *SpotifyProvider) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	var tracks []domain.Track
+	err := s.breaker.Do(ctx, func() (err error) {
+		tracks, err = s.next.GetArtistTopTracks(ctx, artistName)
+		return err
+	})
+	return tracks, err
+}
+
+// IntentCompiler guards a ports.IntentCompiler with a breaker.
+type IntentCompiler struct {
+	next    ports.IntentCompiler
+	breaker *Breaker
+}
+
+// WrapIntentCompiler guards next with a breaker for the provider called
+// name. The result is a ports.ConversationalCompiler when next is one.
+func WrapIntentCompiler(next ports.IntentCompiler, name string, cfg Config) ports.IntentCompiler {
+	c := &IntentCompiler{next: next, breaker: New(name, cfg)}
+	if conv, ok := next.(ports.ConversationalCompiler); ok {
+		return &ConversationalCompiler{IntentCompiler: c, conv: conv}
+	}
+	return c
+}
+
+// AnalyzeIntent implements ports.IntentCompiler.
+func (c *IntentCompiler) AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error) {
+	var intent domain.IntentObject
+	err := c.breaker.Do(ctx, func() (err error) {
+		intent, err = c.next.AnalyzeIntent(ctx, message)
+		return err
+	})
+	return intent, err
+}
+
+// AnalyzeIntentStream implements ports.IntentStreamer, streaming when next
+// does.
+func (c *IntentCompiler) AnalyzeIntentStream(ctx context.Context, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	streamer, ok := c.next.(ports.IntentStreamer)
+	if !ok {
+		return c.AnalyzeIntent(ctx, message)
+	}
+	var intent domain.IntentObject
+	err := c.breaker.Do(ctx, func() (err error) {
+		intent, err = streamer.AnalyzeIntentStream(ctx, message, onDelta)
+		return err
+	})
+	return intent, err
+}
+
+// ConversationalCompiler guards a ports.ConversationalCompiler with a
+// breaker.
+type ConversationalCompiler struct {
+	*IntentCompiler
+	conv ports.ConversationalCompiler
+}
+
+// AnalyzeIntentTurn implements ports.ConversationalCompiler.
+func (c *ConversationalCompiler) AnalyzeIntentTurn(ctx context.Context, history []domain.IntentTurn, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	var intent domain.IntentObject
+	err := c.breaker.Do(ctx, func() (err error) {
+		intent, err = c.conv.AnalyzeIntentTurn(ctx, history, message, onDelta)
+		return err
+	})
+	return intent, err
+}
@@ -0,0 +1,199 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+var errOutage = errors.New("connection refused")
+
+type stubSpotify struct {
+	calls int
+	err   error
+}
+
+func (s *stubSpotify) GetTrackByMetadata(ctx context.Context, title, artist string) (domain.Track, error) {
+	return s.GetTrack(ctx, title, artist)
+}
+
+func (s *stubSpotify) GetTrack(ctx context.Context, title, artist string) (domain.Track, error) {
+	s.calls++
+	return domain.Track{ID: "t1", Title: title, Artist: artist}, s.err
+}
+
+func (s *stubSpotify) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	s.calls++
+	return nil, s.err
+}
+
+type stubConversational struct{}
+
+func (stubConversational) AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error) {
+	return domain.IntentObject{}, nil
+}
+
+func (stubConversational) AnalyzeIntentTurn(ctx context.Context, history []domain.IntentTurn, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	return domain.IntentObject{}, nil
+}
+
+func TestSpotifyProvider(t *testing.T) {
+	ctx := context.Background()
+	inner := &stubSpotify{err: errOutage}
+	p := WrapSpotify(inner, "spotify", Config{Failures: 2, Cooldown: time.Minute})
+	now := time.Now()
+	p.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.GetTrack(ctx, "Song", "Artist"); !errors.Is(err, errOutage) {
+			t.Fatalf("call %d: expected the provider's error, got %v", i, err)
+		}
+	}
+	if got := p.breaker.State(); got != Open {
+		t.Fatalf("expected the breaker open after 2 failures, got %s", got)
+	}
+
+	// Open: calls fail fast without reaching the provider.
+	_, err := p.GetArtistTopTracks(ctx, "Artist")
+	var unavailable ports.ProviderUnavailableError
+	if !errors.As(err, &unavailable) || unavailable.Provider != "spotify" || unavailable.RetryAfter != time.Minute {
+		t.Fatalf("expected spotify unavailable for a minute, got %v", err)
+	}
+	if inner.calls != 2 {
+		t.Fatalf("expected no call while open, provider called %d times", inner.calls)
+	}
+
+	// Half open: a failed probe reopens the breaker.
+	now = now.Add(time.Minute)
+	if _, err := p.GetTrack(ctx, "Song", "Artist"); !errors.Is(err, errOutage) {
+		t.Fatalf("expected the probe to reach the provider, got %v", err)
+	}
+	if _, err := p.GetTrack(ctx, "Song", "Artist"); !errors.Is(err, ports.ErrProviderUnavailable) {
+		t.Fatalf("expected the breaker reopened, got %v", err)
+	}
+
+	// A successful probe closes it.
+	now = now.Add(time.Minute)
+	inner.err = nil
+	if _, err := p.GetTrack(ctx, "Song", "Artist"); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if got := p.breaker.State(); got != Closed {
+		t.Fatalf("expected the breaker closed after a successful probe, got %s", got)
+	}
+}
+
+func TestSpotifyProvider_IgnoresAnswers(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "not found", err: domain.ErrNotFound},
+		{name: "no confident match", err: ports.NoConfidentMatchError{Title: "Song", Artist: "Artist"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := WrapSpotify(&stubSpotify{err: tt.err}, "spotify", Config{Failures: 1, Cooldown: time.Minute})
+			for i := 0; i < 3; i++ {
+				if _, err := p.GetTrack(context.Background(), "Song", "Artist"); !errors.Is(err, tt.err) {
+					t.Fatalf("call %d: expected %v, got %v", i, tt.err, err)
+				}
+			}
+			if got := p.breaker.State(); got != Closed {
+				t.Fatalf("expected the breaker closed, got %s", got)
+			}
+		})
+	}
+}
+
+func TestBreaker_CallerCanceled(t *testing.T) {
+	b := New("ollama", Config{Failures: 1, Cooldown: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Do(ctx, func() error { return ctx.Err() }); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancellation, got %v", err)
+	}
+	if got := b.State(); got != Closed {
+		t.Fatalf("expected a canceled call not to count, got %s", got)
+	}
+}
+
+func TestBreaker_CanceledProbe(t *testing.T) {
+	b := New("ollama", Config{Failures: 1, Cooldown: time.Minute})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	if err := b.Do(context.Background(), func() error { return errOutage }); !errors.Is(err, errOutage) {
+		t.Fatalf("expected the provider's error, got %v", err)
+	}
+
+	// The caller gives up on the probe: the breaker stays half open and
+	// lets the next probe through.
+	now = now.Add(time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Do(ctx, func() error { return ctx.Err() }); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancellation, got %v", err)
+	}
+	if got := b.State(); got != HalfOpen {
+		t.Fatalf("expected the breaker still half open, got %s", got)
+	}
+	if err := b.Do(context.Background(), func() error { return errOutage }); !errors.Is(err, errOutage) {
+		t.Fatalf("expected the next probe to reach the provider, got %v", err)
+	}
+	if got := b.State(); got != Open {
+		t.Fatalf("expected the failed probe to reopen the breaker, got %s", got)
+	}
+}
+
+func TestBreaker_StaleSuccess(t *testing.T) {
+	ctx := context.Background()
+	b := New("ollama", Config{Failures: 1, Cooldown: time.Minute})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	var slow [2]call
+	for i := range slow {
+		c, err := b.allow()
+		if err != nil {
+			t.Fatalf("allow: %v", err)
+		}
+		slow[i] = c
+	}
+	failed, err := b.allow()
+	if err != nil {
+		t.Fatalf("allow: %v", err)
+	}
+	b.record(ctx, failed, errOutage)
+
+	// A call from before the breaker opened succeeds while it is open, then
+	// while the probe is out: neither closes it nor frees the probe.
+	b.record(ctx, slow[0], nil)
+	if got := b.State(); got != Open {
+		t.Fatalf("expected the breaker still open, got %s", got)
+	}
+	now = now.Add(time.Minute)
+	probe, err := b.allow()
+	if err != nil {
+		t.Fatalf("expected the probe allowed, got %v", err)
+	}
+	b.record(ctx, slow[1], nil)
+	if _, err := b.allow(); !errors.Is(err, ports.ErrProviderUnavailable) {
+		t.Fatalf("expected the probe still out, got %v", err)
+	}
+
+	b.record(ctx, probe, nil)
+	if got := b.State(); got != Closed {
+		t.Fatalf("expected the probe's success to close the breaker, got %s", got)
+	}
+}
+
+func TestWrapIntentCompiler_KeepsConversation(t *testing.T) {
+	c := WrapIntentCompiler(stubConversational{}, "ollama", Config{Failures: 1, Cooldown: time.Minute})
+	if _, ok := c.(ports.ConversationalCompiler); !ok {
+		t.Fatalf("expected a conversational compiler to stay conversational")
+	}
+}
@@ -0,0 +1,21 @@
+package breaker
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var (
+	breakerState = metrics.NewGaugeVec(
+		"overture_breaker_state",
+		"Circuit breaker position by provider: 0 closed, 1 half open, 2 open.",
+		"provider",
+	)
+	breakerTransitions = metrics.NewCounterVec(
+		"overture_breaker_transitions_total",
+		"Circuit breaker state changes, by provider and the state entered.",
+		"provider", "state",
+	)
+	breakerRejected = metrics.NewCounterVec(
+		"overture_breaker_rejected_total",
+		"Calls failed without reaching the provider because its breaker was open.",
+		"provider",
+	)
+)
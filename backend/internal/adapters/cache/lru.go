@@ -0,0 +1,88 @@
+// Package cache provides ports.Cache implementations: an in-memory LRU for
+// a single process and Redis for sharing entries between replicas.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// LRU is an in-memory ports.Cache holding at most size entries. Once
+// full, setting a new key evicts the least recently used one.
+type LRU struct {
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // front is the most recently used
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+var _ ports.Cache = (*LRU)(nil)
+
+// NewLRU returns an empty LRU of size entries, at least one.
+func NewLRU(size int) *LRU {
+	if size < 1 {
+		size = 1
+	}
+	return &LRU{size: size, now: time.Now, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// Get implements ports.Cache.
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	e := el.Value.(*lruEntry)
+	if !c.now().Before(e.expiresAt) {
+		c.remove(el)
+		return nil, domain.ErrNotFound
+	}
+	c.order.MoveToFront(el)
+	return e.value, nil
+}
+
+// Set implements ports.Cache.
+func (c *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(ttl)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(el)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Len returns the number of entries held, expired ones included until
+// they are read or evicted.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRU) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRU(2)
+	c.now = func() time.Time { return now }
+
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a missing key, got %v", err)
+	}
+	_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = c.Set(ctx, "b", []byte("2"), time.Hour)
+	if got, err := c.Get(ctx, "a"); err != nil || string(got) != "1" {
+		t.Fatalf("get a: got %q (%v)", got, err)
+	}
+
+	// b is now the least recently used, so c evicts it.
+	_ = c.Set(ctx, "c", []byte("3"), time.Hour)
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected b to be evicted, got %v", err)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", c.Len())
+	}
+
+	// Replacing a key refreshes its value and TTL.
+	_ = c.Set(ctx, "c", []byte("4"), 2*time.Minute)
+	now = now.Add(90 * time.Second)
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a to expire, got %v", err)
+	}
+	if got, err := c.Get(ctx, "c"); err != nil || string(got) != "4" {
+		t.Fatalf("get c: got %q (%v)", got, err)
+	}
+	if c.Len() != 1 {
+		t.Fatalf("expected the expired entry to be dropped, got %d entries", c.Len())
+	}
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a ports.Cache shared by every replica connected to the same
+// server. Keys are stored under prefix and expire through Redis TTLs.
+type Redis struct {
+	client *redis.Client
+	prefix string
+}
+
+var _ ports.Cache = (*Redis)(nil)
+
+// NewRedis connects to url, e.g. redis://:password@redis:6379/0.
+func NewRedis(url, prefix string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("cache: invalid redis URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	// Surface connection problems at startup rather than as misses.
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("cache: redis ping: %w", err)
+	}
+	return &Redis{client: client, prefix: prefix}, nil
+}
+
+// Get implements ports.Cache.
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cache: redis get: %w", err)
+	}
+	return value, nil
+}
+
+// Set implements ports.Cache.
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.client.Set(ctx, c.prefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("cache: redis set: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,207 @@
+// Package chaos wraps provider ports with fault injection: added latency,
+// errors and malformed payloads. It exists to exercise resilience paths in
+// development and integration tests and must never be enabled in production.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// ErrInjected is returned for failures the decorator injects.
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Config sets fault probabilities, each between 0 and 1.
+type Config struct {
+	// Latency is added to a call with probability LatencyRate.
+	Latency     time.Duration
+	LatencyRate float64
+	// ErrorRate is the probability that a call fails with ErrInjected
+	// without reaching the wrapped implementation.
+	ErrorRate float64
+	// MalformedRate is the probability that a successful result is
+	// corrupted before being returned.
+	MalformedRate float64
+	// Seed makes the fault sequence reproducible; zero picks a random seed.
+	Seed uint64
+}
+
+// injector draws faults from a shared, seeded source.
+type injector struct {
+	cfg Config
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newInjector(cfg Config) *injector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64() // #nosec G404 -- fault injection, not security sensitive
+	}
+	return &injector{cfg: cfg, rng: rand.New(rand.NewPCG(seed, seed^0x5bd1e995))} // #nosec G404 -- fault injection
+}
+
+func (in *injector) roll(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.rng.Float64() < p
+}
+
+func (in *injector) intn(n int) int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.rng.IntN(n)
+}
+
+// before applies latency and error faults ahead of a call.
+func (in *injector) before(ctx context.Context) error {
+	if in.cfg.Latency > 0 && in.roll(in.cfg.LatencyRate) {
+		timer := time.NewTimer(in.cfg.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if in.roll(in.cfg.ErrorRate) {
+		return ErrInjected
+	}
+	return nil
+}
+
+// SpotifyProvider injects faults into a ports.SpotifyProvider.
+type SpotifyProvider struct {
+	next ports.SpotifyProvider
+	in   *injector
+}
+
+// WrapSpotify decorates next with the faults described by cfg.
+func WrapSpotify(next ports.SpotifyProvider, cfg Config) *SpotifyProvider {
+	return &SpotifyProvider{next: next, in: newInjector(cfg)}
+}
+
+// GetTrackByMetadata implements ports.SpotifyProvider.
+func (s *SpotifyProvider) GetTrackByMetadata(ctx context.Context, title, artist string) (domain.Track, error) {
+	if err := s.in.before(ctx); err != nil {
+		return domain.Track{}, err
+	}
+	track, err := s.next.GetTrackByMetadata(ctx, title, artist)
+	if err == nil && s.in.roll(s.in.cfg.MalformedRate) {
+		track = s.corruptTrack(track)
+	}
+	return track, err
+}
+
+// GetTrack implements ports.SpotifyProvider.
+func (s *SpotifyProvider) GetTrack(ctx context.Context, title, artist string) (domain.Track, error) {
+	if err := s.in.before(ctx); err != nil {
+		return domain.Track{}, err
+	}
+	track, err := s.next.GetTrack(ctx, title, artist)
+	if err == nil && s.in.roll(s.in.cfg.MalformedRate) {
+		track = s.corruptTrack(track)
+	}
+	return track, err
+}
+
+// GetArtistTopTracks implements ports.SpotifyProvider. Malformed results
+// corrupt one track, duplicate another, or come back empty.
+func (s *SpotifyProvider) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	if err := s.in.before(ctx); err != nil {
+		return nil, err
+	}
+	tracks, err := s.next.GetArtistTopTracks(ctx, artistName)
+	if err != nil || len(tracks) == 0 || !s.in.roll(s.in.cfg.MalformedRate) {
+		return tracks, err
+	}
+
+	tracks = append([]domain.Track(nil), tracks...)
+	switch s.in.intn(3) {
+	case 0:
+		i := s.in.intn(len(tracks))
+		tracks[i] = s.corruptTrack(tracks[i])
+	case 1:
+		tracks = append(tracks, tracks[s.in.intn(len(tracks))])
+	default:
+		tracks = []domain.Track{}
+	}
+	return tracks, nil
+}
+
+// corruptTrack returns a track with missing identity or out-of-range audio
+// features, as a misbehaving upstream might.
+func (s *SpotifyProvider) corruptTrack(t domain.Track) domain.Track {
+	switch s.in.intn(3) {
+	case 0:
+		t.ID = ""
+	case 1:
+		t.Title, t.Artist = "", ""
+	default:
+		t.Features = domain.AudioFeatures{Energy: -1, Valence: 2, Acousticness: 7, Instrumentalness: -3, Tempo: -120}
+	}
+	return t
+}
+
+// IntentCompiler injects faults into a ports.IntentCompiler.
+type IntentCompiler struct {
+	next ports.IntentCompiler
+	in   *injector
+}
+
+// WrapIntentCompiler decorates next with the faults described by cfg.
+func WrapIntentCompiler(next ports.IntentCompiler, cfg Config) *IntentCompiler {
+	return &IntentCompiler{next: next, in: newInjector(cfg)}
+}
+
+// AnalyzeIntent implements ports.IntentCompiler. Malformed results have
+// inverted or out-of-range constraints, no entities, or an unknown type.
+func (c *IntentCompiler) AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error) {
+	if err := c.in.before(ctx); err != nil {
+		return domain.IntentObject{}, err
+	}
+	intent, err := c.next.AnalyzeIntent(ctx, message)
+	return c.corrupt(intent, err)
+}
+
+// AnalyzeIntentStream implements ports.IntentStreamer, streaming when next
+// does. Faults apply as for AnalyzeIntent.
+func (c *IntentCompiler) AnalyzeIntentStream(ctx context.Context, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	streamer, ok := c.next.(ports.IntentStreamer)
+	if !ok {
+		return c.AnalyzeIntent(ctx, message)
+	}
+	if err := c.in.before(ctx); err != nil {
+		return domain.IntentObject{}, err
+	}
+	intent, err := streamer.AnalyzeIntentStream(ctx, message, onDelta)
+	return c.corrupt(intent, err)
+}
+
+// corrupt makes a successful result malformed at the configured rate.
+func (c *IntentCompiler) corrupt(intent domain.IntentObject, err error) (domain.IntentObject, error) {
+	if err != nil || !c.in.roll(c.in.cfg.MalformedRate) {
+		return intent, err
+	}
+
+	switch c.in.intn(3) {
+	case 0:
+		intent.VibeConstraints.Energy = &domain.VibeConstraint{Min: 0.9, Max: 0.1}
+		intent.VibeConstraints.Valence = &domain.VibeConstraint{Min: -5, Max: 42}
+	case 1:
+		intent.Entities.Artists = nil
+		intent.Entities.Genres = nil
+	default:
+		intent.IntentType = "UNKNOWN_CHAOS"
+	}
+	return intent, nil
+}
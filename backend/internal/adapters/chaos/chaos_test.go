@@ -0,0 +1,140 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/sqlite"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/synthetic"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/services"
+)
+
+type stubCompiler struct {
+	intent domain.IntentObject
+}
+
+func (s stubCompiler) AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error) {
+	return s.intent, nil
+}
+
+func TestSpotifyProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		wantErr     error
+		wantChanged bool
+		minDuration time.Duration
+	}{
+		{name: "passes through without faults", cfg: Config{Seed: 1}},
+		{name: "injects errors", cfg: Config{ErrorRate: 1, Seed: 1}, wantErr: ErrInjected},
+		{name: "corrupts payloads", cfg: Config{MalformedRate: 1, Seed: 1}, wantChanged: true},
+		{name: "adds latency", cfg: Config{Latency: 20 * time.Millisecond, LatencyRate: 1, Seed: 1}, minDuration: 20 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := synthetic.NewProvider(synthetic.Config{TracksPerArtist: 5})
+			want, _ := inner.GetArtistTopTracks(context.Background(), "Artist")
+
+			p := WrapSpotify(inner, tt.cfg)
+			start := time.Now()
+			got, err := p.GetArtistTopTracks(context.Background(), "Artist")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if time.Since(start) < tt.minDuration {
+				t.Fatalf("returned before injected latency of %v", tt.minDuration)
+			}
+			if err != nil {
+				return
+			}
+			if changed := !sameTracks(got, want); changed != tt.wantChanged {
+				t.Fatalf("payload changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestIntentCompiler_Reproducible(t *testing.T) {
+	intent := domain.IntentObject{IntentType: "CREATE"}
+	intent.Entities.Artists = []string{"Artist"}
+	cfg := Config{MalformedRate: 0.5, ErrorRate: 0.2, Seed: 42}
+
+	a := WrapIntentCompiler(stubCompiler{intent}, cfg)
+	b := WrapIntentCompiler(stubCompiler{intent}, cfg)
+	for i := 0; i < 20; i++ {
+		ia, ea := a.AnalyzeIntent(context.Background(), "msg")
+		ib, eb := b.AnalyzeIntent(context.Background(), "msg")
+		if (ea == nil) != (eb == nil) || ia.IntentType != ib.IntentType || len(ia.Entities.Artists) != len(ib.Entities.Artists) {
+			t.Fatalf("call %d diverged with the same seed: %+v/%v vs %+v/%v", i, ia, ea, ib, eb)
+		}
+	}
+}
+
+// TestOrchestrator_UnderChaos drives the real Orchestrator through faulty
+// providers to check the degraded paths hold up.
+func TestOrchestrator_UnderChaos(t *testing.T) {
+	tests := []struct {
+		name        string
+		spotify     Config
+		compiler    Config
+		wantErr     bool
+		wantAddedLE int
+	}{
+		{name: "provider outage degrades to no tracks", spotify: Config{ErrorRate: 1, Seed: 1}, wantAddedLE: 0},
+		{name: "malformed provider payloads", spotify: Config{MalformedRate: 1, Seed: 7}, wantAddedLE: 10},
+		{name: "malformed intents", compiler: Config{MalformedRate: 1, Seed: 3}, wantAddedLE: 10},
+		{name: "compiler outage fails the request", compiler: Config{ErrorRate: 1, Seed: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := sqlite.NewAdapter(filepath.Join(t.TempDir(), "chaos.db"))
+			if err != nil {
+				t.Fatalf("new adapter: %v", err)
+			}
+			defer repo.Close()
+
+			intent := domain.IntentObject{IntentType: "CREATE"}
+			intent.Entities.Artists = []string{"Artist A", "Artist B"}
+			svc := services.NewOrchestrator(
+				WrapSpotify(synthetic.NewProvider(synthetic.Config{TracksPerArtist: 5}), tt.spotify),
+				repo,
+				WrapIntentCompiler(stubCompiler{intent}, tt.compiler),
+				services.WithUnitOfWork(repo),
+			)
+			ctx := context.Background()
+			playlist, err := svc.CreatePlaylist(ctx, "Chaos")
+			if err != nil {
+				t.Fatalf("create playlist: %v", err)
+			}
+
+			result, err := svc.ProcessIntent(ctx, playlist.ID, "anything")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessIntent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result.TracksAdded > tt.wantAddedLE {
+				t.Fatalf("added %d tracks, want at most %d", result.TracksAdded, tt.wantAddedLE)
+			}
+			if _, err := svc.GetPlaylist(ctx, playlist.ID); err != nil {
+				t.Fatalf("playlist unreadable after chaos: %v", err)
+			}
+		})
+	}
+}
+
+func sameTracks(a, b []domain.Track) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
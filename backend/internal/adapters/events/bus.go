@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind by
+// before further events are dropped for it.
+const subscriberBuffer = 64
+
+var (
+	eventsTotal = metrics.NewCounterVec(
+		"overture_events_total",
+		"Events delivered to this instance's event bus by type.",
+		"type",
+	)
+	eventsDropped = metrics.NewCounterVec(
+		"overture_events_dropped_total",
+		"Events a subscriber missed because it fell behind, by type.",
+		"type",
+	)
+)
+
+// Bus fans events out to subscribers in the same process. It implements
+// ports.EventBus; events published on other replicas are not seen unless it
+// is fed by a RedisBus or NATSBus.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[chan domain.Event]struct{}
+}
+
+// NewBus returns a Bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{subs: map[chan domain.Event]struct{}{}}
+}
+
+// Publish implements ports.EventSink. It never blocks: subscribers whose
+// buffer is full miss the event.
+func (b *Bus) Publish(_ context.Context, event domain.Event) error {
+	eventsTotal.Inc(event.Type)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			eventsDropped.Inc(event.Type)
+			logger().Warn("subscriber is behind, dropped event", "type", event.Type, "event_id", event.ID)
+		}
+	}
+	return nil
+}
+
+// Subscribe implements ports.EventSubscriber.
+func (b *Bus) Subscribe(ctx context.Context) <-chan domain.Event {
+	ch := make(chan domain.Event, subscriberBuffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+		close(ch)
+	}()
+	return ch
+}
+
+// Multi publishes every event to each of its sinks in turn, returning the
+// errors of those that failed.
+type Multi []ports.EventSink
+
+// Publish implements ports.EventSink.
+func (m Multi) Publish(ctx context.Context, event domain.Event) error {
+	var errs []error
+	for _, sink := range m {
+		if err := sink.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
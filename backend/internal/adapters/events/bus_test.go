@@ -0,0 +1,128 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// testEvent returns an event with every field set, so encoding round-trips
+// cover them all.
+func testEvent(id string) domain.Event {
+	return domain.Event{
+		ID:         id,
+		Type:       domain.EventTracksAdded,
+		PlaylistID: "p1",
+		Payload:    json.RawMessage(`{"track_ids":["t1","t2"]}`),
+		OccurredAt: time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC),
+	}
+}
+
+// receive waits for the next event on ch.
+func receive(t *testing.T, ch <-chan domain.Event) domain.Event {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		if !ok {
+			t.Fatal("expected an event, got a closed channel")
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return domain.Event{}
+	}
+}
+
+// assertEvent fails unless got matches want field by field.
+func assertEvent(t *testing.T, got, want domain.Event) {
+	t.Helper()
+	if got.ID != want.ID || got.Type != want.Type || got.PlaylistID != want.PlaylistID ||
+		string(got.Payload) != string(want.Payload) || !got.OccurredAt.Equal(want.OccurredAt) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestBus_FanOut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewBus()
+	first, second := bus.Subscribe(ctx), bus.Subscribe(ctx)
+
+	want := testEvent("e1")
+	if err := bus.Publish(ctx, want); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	assertEvent(t, receive(t, first), want)
+	assertEvent(t, receive(t, second), want)
+}
+
+func TestBus_DropsForSlowSubscriber(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewBus()
+	slow, fast := bus.Subscribe(ctx), bus.Subscribe(ctx)
+
+	before := eventsDropped.Value(domain.EventTracksAdded)
+	for range subscriberBuffer + 1 {
+		if err := bus.Publish(ctx, testEvent("e1")); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		// The fast subscriber keeps up and misses nothing.
+		receive(t, fast)
+	}
+	if got := eventsDropped.Value(domain.EventTracksAdded) - before; got != 1 {
+		t.Fatalf("expected 1 dropped event, got %v", got)
+	}
+	if got := len(slow); got != subscriberBuffer {
+		t.Fatalf("expected the slow subscriber to hold %d events, got %d", subscriberBuffer, got)
+	}
+}
+
+func TestBus_UnsubscribesOnCancel(t *testing.T) {
+	bus := NewBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := bus.Subscribe(ctx)
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("expected no events after cancelling")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the channel to close")
+	}
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	if len(bus.subs) != 0 {
+		t.Fatalf("expected no subscribers left, got %d", len(bus.subs))
+	}
+}
+
+type failingSink struct{ err error }
+
+func (s failingSink) Publish(context.Context, domain.Event) error { return s.err }
+
+func TestMulti_JoinsErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewBus()
+	ch := bus.Subscribe(ctx)
+	errRedis, errNATS := errors.New("redis down"), errors.New("nats down")
+
+	m := Multi{failingSink{errRedis}, bus, failingSink{errNATS}}
+	err := m.Publish(ctx, testEvent("e1"))
+	if !errors.Is(err, errRedis) || !errors.Is(err, errNATS) {
+		t.Fatalf("expected both failures, got %v", err)
+	}
+	// A failing sink does not keep the event from the others.
+	assertEvent(t, receive(t, ch), testEvent("e1"))
+
+	if err := (Multi{bus}).Publish(ctx, testEvent("e2")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
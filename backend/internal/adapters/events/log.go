@@ -0,0 +1,29 @@
+// Package events provides implementations of the event sink port.
+package events
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+)
+
+// LogSink writes events to the process log. It is the default sink when no
+// external broker is configured.
+type LogSink struct {
+	// Logger receives the events; nil means the default logger.
+	Logger *slog.Logger
+}
+
+// Publish implements ports.EventSink.
+func (s LogSink) Publish(ctx context.Context, event domain.Event) error {
+	logging.Component(s.Logger, "events").InfoContext(ctx, "event",
+		"type", event.Type, "playlist_id", event.PlaylistID, "event_id", event.ID, "payload", string(event.Payload))
+	return nil
+}
+
+// logger returns the logger for the buses' warnings.
+func logger() *slog.Logger {
+	return logging.Component(nil, "events")
+}
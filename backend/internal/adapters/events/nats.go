@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSBus shares events between replicas over a core NATS subject. Each
+// replica receives every event, its own included, and fans it out to its
+// subscribers. Core NATS does not persist: replicas that are down miss
+// events.
+type NATSBus struct {
+	conn    *nats.Conn
+	subject string
+	local   *Bus
+}
+
+// NewNATSBus connects to url, e.g. nats://nats:4222, and starts receiving
+// from subject.
+func NewNATSBus(url, subject string) (*NATSBus, error) {
+	conn, err := nats.Connect(url, nats.Name("overture"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("events: nats connect: %w", err)
+	}
+	b := &NATSBus{conn: conn, subject: subject, local: NewBus()}
+	if _, err := conn.Subscribe(subject, b.receive); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("events: nats subscribe: %w", err)
+	}
+	return b, nil
+}
+
+func (b *NATSBus) receive(msg *nats.Msg) {
+	var event domain.Event
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		logger().Warn("dropping malformed nats message", "error", err)
+		return
+	}
+	_ = b.local.Publish(context.Background(), event)
+}
+
+// Publish implements ports.EventSink.
+func (b *NATSBus) Publish(_ context.Context, event domain.Event) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("events: encode event: %w", err)
+	}
+	if err := b.conn.Publish(b.subject, raw); err != nil {
+		return fmt.Errorf("events: nats publish: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements ports.EventSubscriber.
+func (b *NATSBus) Subscribe(ctx context.Context) <-chan domain.Event {
+	return b.local.Subscribe(ctx)
+}
+
+// Close delivers pending messages and closes the connection.
+func (b *NATSBus) Close() error {
+	return b.conn.Drain()
+}
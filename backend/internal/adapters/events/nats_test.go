@@ -0,0 +1,168 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeNATS speaks enough of the NATS client protocol for one NATSBus:
+// subscriptions, publishes and pings. Messages are delivered to every
+// matching subscription on every connection.
+type fakeNATS struct {
+	ln net.Listener
+
+	mu   sync.Mutex
+	subs map[*bufio.Writer]map[string]string // conn -> sid -> subject
+}
+
+func newFakeNATS(t *testing.T) *fakeNATS {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeNATS{ln: ln, subs: map[*bufio.Writer]map[string]string{}}
+	t.Cleanup(func() { _ = ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeNATS) url() string { return "nats://" + s.ln.Addr().String() }
+
+func (s *fakeNATS) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeNATS) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+	s.mu.Lock()
+	s.subs[w] = map[string]string{}
+	fmt.Fprintf(w, "INFO {\"server_id\":\"fake\",\"version\":\"2.10.0\",\"proto\":1,\"max_payload\":1048576}\r\n")
+	_ = w.Flush()
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subs, w)
+		s.mu.Unlock()
+	}()
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		args := strings.Fields(line)
+		if len(args) == 0 {
+			continue
+		}
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			s.write(w, "PONG\r\n")
+		case "SUB":
+			s.mu.Lock()
+			s.subs[w][args[len(args)-1]] = args[1]
+			s.mu.Unlock()
+		case "UNSUB":
+			s.mu.Lock()
+			delete(s.subs[w], args[1])
+			s.mu.Unlock()
+		case "PUB":
+			size, _ := strconv.Atoi(args[len(args)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			s.publish(args[1], payload[:size])
+		}
+	}
+}
+
+func (s *fakeNATS) write(w *bufio.Writer, msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = w.WriteString(msg)
+	_ = w.Flush()
+}
+
+func (s *fakeNATS) publish(subject string, payload []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for w, subs := range s.subs {
+		for sid, sub := range subs {
+			if sub == subject {
+				fmt.Fprintf(w, "MSG %s %s %d\r\n%s\r\n", subject, sid, len(payload), payload)
+				_ = w.Flush()
+			}
+		}
+	}
+}
+
+func TestNATSBus_RoundTrip(t *testing.T) {
+	srv := newFakeNATS(t)
+	first, err := NewNATSBus(srv.url(), "overture.events")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = first.Close() }()
+	second, err := NewNATSBus(srv.url(), "overture.events")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = second.Close() }()
+	// Wait until both subscriptions have reached the server.
+	for _, b := range []*NATSBus{first, second} {
+		if err := b.conn.Flush(); err != nil {
+			t.Fatalf("flush: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	own, other := first.Subscribe(ctx), second.Subscribe(ctx)
+
+	want := testEvent("e1")
+	if err := first.Publish(ctx, want); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	// Each replica receives every event, its own included.
+	assertEvent(t, receive(t, other), want)
+	assertEvent(t, receive(t, own), want)
+}
+
+func TestNATSBus_DropsMalformedMessages(t *testing.T) {
+	srv := newFakeNATS(t)
+	b, err := NewNATSBus(srv.url(), "overture.events")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+	if err := b.conn.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := b.Subscribe(ctx)
+
+	if err := b.conn.Publish("overture.events", []byte("not json")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	want := testEvent("e2")
+	if err := b.Publish(ctx, want); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	assertEvent(t, receive(t, ch), want)
+}
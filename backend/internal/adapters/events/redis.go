@@ -0,0 +1,81 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBus shares events between replicas over a Redis Pub/Sub channel. Each
+// replica receives every event, its own included, and fans it out to its
+// subscribers. Pub/Sub does not persist: replicas that are down miss events.
+type RedisBus struct {
+	client  *redis.Client
+	pubsub  *redis.PubSub
+	channel string
+	local   *Bus
+	done    chan struct{}
+}
+
+// NewRedisBus connects to url, e.g. redis://:password@redis:6379/0, and
+// starts receiving from channel.
+func NewRedisBus(url, channel string) (*RedisBus, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("events: invalid redis URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	pubsub := client.Subscribe(context.Background(), channel)
+	// Receive waits for the subscription to be confirmed, so connection
+	// problems surface at startup.
+	if _, err := pubsub.Receive(context.Background()); err != nil {
+		_ = pubsub.Close()
+		_ = client.Close()
+		return nil, fmt.Errorf("events: redis subscribe: %w", err)
+	}
+	b := &RedisBus{client: client, pubsub: pubsub, channel: channel, local: NewBus(), done: make(chan struct{})}
+	go b.receive()
+	return b, nil
+}
+
+func (b *RedisBus) receive() {
+	defer close(b.done)
+	for msg := range b.pubsub.Channel() {
+		var event domain.Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			logger().Warn("dropping malformed redis message", "error", err)
+			continue
+		}
+		_ = b.local.Publish(context.Background(), event)
+	}
+}
+
+// Publish implements ports.EventSink.
+func (b *RedisBus) Publish(ctx context.Context, event domain.Event) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("events: encode event: %w", err)
+	}
+	if err := b.client.Publish(ctx, b.channel, raw).Err(); err != nil {
+		return fmt.Errorf("events: redis publish: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements ports.EventSubscriber.
+func (b *RedisBus) Subscribe(ctx context.Context) <-chan domain.Event {
+	return b.local.Subscribe(ctx)
+}
+
+// Close unsubscribes and closes the connection.
+func (b *RedisBus) Close() error {
+	err := b.pubsub.Close()
+	<-b.done
+	if cerr := b.client.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
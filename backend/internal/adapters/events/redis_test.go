@@ -0,0 +1,188 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRedis speaks enough RESP2 for one RedisBus: Pub/Sub on channels and
+// PING. Other commands, including the client's HELLO handshake, are
+// refused, which the client takes as a server without RESP3.
+type fakeRedis struct {
+	ln net.Listener
+
+	mu   sync.Mutex
+	subs map[*bufio.Writer]map[string]bool // conn -> subscribed channels
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeRedis{ln: ln, subs: map[*bufio.Writer]map[string]bool{}}
+	t.Cleanup(func() { _ = ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeRedis) url() string { return "redis://" + s.ln.Addr().String() + "/0" }
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+	defer func() {
+		s.mu.Lock()
+		delete(s.subs, w)
+		s.mu.Unlock()
+	}()
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			if s.subs[w] != nil {
+				fmt.Fprint(w, "*2\r\n$4\r\npong\r\n$0\r\n\r\n")
+			} else {
+				fmt.Fprint(w, "+PONG\r\n")
+			}
+		case "SELECT":
+			fmt.Fprint(w, "+OK\r\n")
+		case "SUBSCRIBE":
+			if s.subs[w] == nil {
+				s.subs[w] = map[string]bool{}
+			}
+			for _, channel := range args[1:] {
+				s.subs[w][channel] = true
+				fmt.Fprintf(w, "*3\r\n$9\r\nsubscribe\r\n%s:%d\r\n", bulk(channel), len(s.subs[w]))
+			}
+		case "UNSUBSCRIBE":
+			for _, channel := range args[1:] {
+				delete(s.subs[w], channel)
+				fmt.Fprintf(w, "*3\r\n$11\r\nunsubscribe\r\n%s:%d\r\n", bulk(channel), len(s.subs[w]))
+			}
+		case "PUBLISH":
+			var receivers int
+			for sub, channels := range s.subs {
+				if channels[args[1]] {
+					receivers++
+					fmt.Fprintf(sub, "*3\r\n$7\r\nmessage\r\n%s%s", bulk(args[1]), bulk(args[2]))
+					_ = sub.Flush()
+				}
+			}
+			fmt.Fprintf(w, ":%d\r\n", receivers)
+		default:
+			fmt.Fprintf(w, "-ERR unknown command '%s'\r\n", args[0])
+		}
+		_ = w.Flush()
+		s.mu.Unlock()
+	}
+}
+
+// readCommand reads one RESP array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	n, err := readLength(r, '*')
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		size, err := readLength(r, '$')
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
+
+func readLength(r *bufio.Reader, prefix byte) (int, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	if len(line) < 3 || line[0] != prefix {
+		return 0, fmt.Errorf("unexpected line %q", line)
+	}
+	return strconv.Atoi(strings.TrimSpace(line[1:]))
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func TestRedisBus_RoundTrip(t *testing.T) {
+	srv := newFakeRedis(t)
+	first, err := NewRedisBus(srv.url(), "overture:events")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = first.Close() }()
+	second, err := NewRedisBus(srv.url(), "overture:events")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = second.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	own, other := first.Subscribe(ctx), second.Subscribe(ctx)
+
+	want := testEvent("e1")
+	if err := first.Publish(ctx, want); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	// Each replica receives every event, its own included.
+	assertEvent(t, receive(t, other), want)
+	assertEvent(t, receive(t, own), want)
+}
+
+func TestRedisBus_DropsMalformedMessages(t *testing.T) {
+	srv := newFakeRedis(t)
+	b, err := NewRedisBus(srv.url(), "overture:events")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := b.Subscribe(ctx)
+
+	if err := b.client.Publish(ctx, "overture:events", "not json").Err(); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	want := testEvent("e2")
+	if err := b.Publish(ctx, want); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	assertEvent(t, receive(t, ch), want)
+}
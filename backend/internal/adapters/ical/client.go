@@ -0,0 +1,101 @@
+// Package ical provides a calendar adapter for iCalendar (RFC 5545) feeds,
+// such as the secret ICS address of a Google calendar or the export URL of
+// a CalDAV collection. Credentials may be given in the URL's userinfo.
+package ical
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+)
+
+const (
+	// cacheTTL is how long a fetched feed is reused. Focus blocks are
+	// scheduled well ahead, so a few minutes of staleness is harmless.
+	cacheTTL = 5 * time.Minute
+	// maxFeedBytes bounds the feed download.
+	maxFeedBytes = 10 << 20
+)
+
+// Client implements ports.CalendarProvider.
+type Client struct {
+	url        string
+	loc        *time.Location
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	fetched time.Time
+	events  []event
+}
+
+// NewClient returns a Client reading the feed at feedURL. Times without a
+// zone are read as local time.
+func NewClient(feedURL string) *Client {
+	return &Client{url: feedURL, loc: time.Local, httpClient: httpx.NewClient("ical", 10*time.Second, httpx.DefaultPolicy())}
+}
+
+// Events implements ports.CalendarProvider.
+func (c *Client) Events(ctx context.Context, from, to time.Time) ([]domain.CalendarEvent, error) {
+	events, err := c.feed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []domain.CalendarEvent
+	for _, e := range events {
+		out = append(out, e.occurrences(from, to)...)
+	}
+	return out, nil
+}
+
+// feed returns the parsed feed, fetching it when the cached copy is stale.
+func (c *Client) feed(ctx context.Context) ([]event, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.fetched.IsZero() && time.Since(c.fetched) < cacheTTL {
+		return c.events, nil
+	}
+
+	start := time.Now()
+	events, outcome, err := c.fetch(ctx)
+	fetchDuration.Observe(time.Since(start).Seconds(), outcome)
+	if err != nil {
+		return nil, err
+	}
+	c.events, c.fetched = events, time.Now()
+	return events, nil
+}
+
+// fetch downloads and parses the feed. outcome classifies the result for
+// metrics.
+func (c *Client) fetch(ctx context.Context) ([]event, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, "network", fmt.Errorf("ical: build request: %w", err)
+	}
+	req.Header.Set("Accept", "text/calendar")
+	resp, err := c.httpClient.Do(req) // #nosec G107,G704
+	if err != nil {
+		// Feed URLs are secrets; keep them out of the error.
+		if uerr, ok := err.(*url.Error); ok {
+			err = uerr.Err
+		}
+		return nil, "network", fmt.Errorf("ical: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "status", fmt.Errorf("ical: unexpected status %d", resp.StatusCode)
+	}
+	events, err := parse(io.LimitReader(resp.Body, maxFeedBytes), c.loc)
+	if err != nil {
+		return nil, "decode", err
+	}
+	return events, "ok", nil
+}
@@ -0,0 +1,117 @@
+package ical
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const feed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Design review\r\n" +
+	"DTSTART:20260302T150000Z\r\n" +
+	"DTEND:20260302T160000Z\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"SUMMARY:Reminder\r\n" +
+	"TRIGGER:-PT10M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Deep work\\, no\r\n" +
+	"  meetings\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20260302T090000\r\n" +
+	"DURATION:PT2H\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=6\r\n" +
+	"EXDATE;TZID=Europe/Berlin:20260304T090000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Cancelled focus\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART:20260302T150000Z\r\n" +
+	"DTEND:20260302T160000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Offsite\r\n" +
+	"DTSTART;VALUE=DATE:20260305\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestClient_Events(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = w.Write([]byte(feed))
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL)
+	client.loc = time.UTC
+
+	day := func(d, hour int) time.Time { return time.Date(2026, 3, d, hour, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{name: "single event, alarm and cancellation ignored", from: day(2, 15), to: day(2, 16), want: []string{"Design review"}},
+		// 09:00 in Berlin is 08:00 UTC.
+		{name: "first occurrence in its zone", from: day(2, 8), to: day(2, 9), want: []string{"Deep work, no meetings"}},
+		{name: "excluded date", from: day(4, 0), to: day(4, 23)},
+		{name: "all-day event lasts the day", from: day(5, 22), to: day(5, 23), want: []string{"Offsite"}},
+		{name: "next week", from: day(9, 8), to: day(9, 9), want: []string{"Deep work, no meetings"}},
+		// COUNT=6 ends the series on Wednesday 18 March.
+		{name: "after count", from: day(23, 0), to: day(23, 23)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := client.Events(context.Background(), tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("events: %v", err)
+			}
+			var got []string
+			for _, e := range events {
+				got = append(got, e.Summary)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_Events_CachesFeed(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte(feed))
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Events(context.Background(), time.Now(), time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("events: %v", err)
+		}
+	}
+	if hits != 1 {
+		t.Fatalf("expected one download, got %d", hits)
+	}
+}
+
+func TestClient_Events_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+	secret := strings.Replace(srv.URL, "http://", "http://user:hunter2@", 1) + "/private-abc123/basic.ics"
+
+	_, err := NewClient(secret).Events(context.Background(), time.Now(), time.Now().Add(time.Hour))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(err.Error(), "hunter2") || strings.Contains(err.Error(), "abc123") {
+		t.Fatalf("error leaks the feed URL: %v", err)
+	}
+}
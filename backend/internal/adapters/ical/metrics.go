@@ -0,0 +1,10 @@
+package ical
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var fetchDuration = metrics.NewHistogramVec(
+	"overture_calendar_fetch_duration_seconds",
+	"Latency of calendar feed downloads, by outcome (ok, network, status, decode).",
+	nil,
+	"outcome",
+)
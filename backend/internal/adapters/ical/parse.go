@@ -0,0 +1,339 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// maxOccurrences bounds recurrence expansion, so an unbounded daily rule
+// started decades ago cannot stall a lookup.
+const maxOccurrences = 20000
+
+// event is a parsed VEVENT, possibly recurring.
+type event struct {
+	summary  string
+	start    time.Time
+	duration time.Duration
+	rule     *rule
+	exdates  map[int64]bool
+}
+
+// rule is the subset of RRULE that is supported: FREQ, INTERVAL, COUNT,
+// UNTIL, and BYDAY for weekly rules.
+type rule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+// property is a content line: NAME;PARAM=x:VALUE.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parse reads the VEVENTs of an iCalendar stream. Floating times are read
+// in loc. Cancelled events and events without a usable start are skipped.
+func parse(r io.Reader, loc *time.Location) ([]event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []event
+	var props []property
+	depth := 0 // nesting inside the current VEVENT, e.g. VALARM
+	inEvent := false
+	for _, line := range lines {
+		p, ok := parseLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT") && !inEvent:
+			inEvent = true
+			props = props[:0]
+		case !inEvent:
+		case p.name == "BEGIN":
+			depth++
+		case p.name == "END" && depth > 0:
+			depth--
+		case p.name == "END" && strings.EqualFold(p.value, "VEVENT"):
+			inEvent = false
+			if e, ok := buildEvent(props, loc); ok {
+				events = append(events, e)
+			}
+		case depth == 0:
+			props = append(props, p)
+		}
+	}
+	return events, nil
+}
+
+// unfold joins folded content lines (RFC 5545 section 3.1).
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ical: read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+func parseLine(line string) (property, bool) {
+	colon := strings.IndexByte(line, ':')
+	if colon < 0 {
+		return property{}, false
+	}
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	p := property{name: strings.ToUpper(parts[0]), value: value, params: map[string]string{}}
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return p, true
+}
+
+func buildEvent(props []property, loc *time.Location) (event, bool) {
+	e := event{exdates: map[int64]bool{}}
+	var end time.Time
+	allDay := false
+	for _, p := range props {
+		switch p.name {
+		case "SUMMARY":
+			e.summary = unescape(p.value)
+		case "STATUS":
+			if strings.EqualFold(p.value, "CANCELLED") {
+				return event{}, false
+			}
+		case "DTSTART":
+			t, date, err := parseTime(p, loc)
+			if err != nil {
+				return event{}, false
+			}
+			e.start, allDay = t, date
+		case "DTEND":
+			if t, _, err := parseTime(p, loc); err == nil {
+				end = t
+			}
+		case "DURATION":
+			if d, err := parseDuration(p.value); err == nil {
+				e.duration = d
+			}
+		case "RRULE":
+			if r, err := parseRule(p.value, loc); err == nil {
+				e.rule = r
+			}
+		case "EXDATE":
+			for _, v := range strings.Split(p.value, ",") {
+				if t, _, err := parseTime(property{params: p.params, value: v}, loc); err == nil {
+					e.exdates[t.Unix()] = true
+				}
+			}
+		}
+	}
+	if e.start.IsZero() {
+		return event{}, false
+	}
+	switch {
+	case !end.IsZero():
+		e.duration = end.Sub(e.start)
+	case e.duration == 0 && allDay:
+		e.duration = 24 * time.Hour
+	}
+	if e.duration <= 0 {
+		return event{}, false
+	}
+	return e, true
+}
+
+// parseTime parses a DATE or DATE-TIME value. It reports whether the value
+// was a date, which starts at midnight in loc.
+func parseTime(p property, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.value)
+	if p.params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	if tzid := p.params["TZID"]; tzid != "" {
+		// Unknown zones (e.g. Windows names) fall back to loc.
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseDuration parses a positive RFC 5545 duration such as PT1H30M or P1D.
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(value, "P") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, c := range value[1:] {
+		switch {
+		case c == 'T':
+			inTime = true
+		case c >= '0' && c <= '9':
+			num += string(c)
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			num = ""
+			switch {
+			case c == 'W':
+				d += time.Duration(n) * 7 * 24 * time.Hour
+			case c == 'D':
+				d += time.Duration(n) * 24 * time.Hour
+			case c == 'H' && inTime:
+				d += time.Duration(n) * time.Hour
+			case c == 'M' && inTime:
+				d += time.Duration(n) * time.Minute
+			case c == 'S' && inTime:
+				d += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+		}
+	}
+	return d, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRule(value string, loc *time.Location) (*rule, error) {
+	r := &rule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid interval %q", v)
+			}
+			r.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid count %q", v)
+			}
+			r.count = n
+		case "UNTIL":
+			t, _, err := parseTime(property{value: v}, loc)
+			if err != nil {
+				return nil, err
+			}
+			r.until = t
+		case "BYDAY":
+			for _, day := range strings.Split(v, ",") {
+				// Ordinals such as 1MO only apply to monthly rules.
+				if wd, ok := weekdays[strings.ToUpper(strings.TrimLeft(day, "+-0123456789"))]; ok {
+					r.byDay = append(r.byDay, wd)
+				}
+			}
+		}
+	}
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported frequency %q", r.freq)
+	}
+	return r, nil
+}
+
+// occurrences returns the occurrences of e overlapping [from, to).
+func (e event) occurrences(from, to time.Time) []domain.CalendarEvent {
+	var out []domain.CalendarEvent
+	add := func(start time.Time) {
+		end := start.Add(e.duration)
+		if e.exdates[start.Unix()] || !start.Before(to) || !end.After(from) {
+			return
+		}
+		out = append(out, domain.CalendarEvent{Summary: e.summary, Start: start, End: end})
+	}
+	if e.rule == nil {
+		add(e.start)
+		return out
+	}
+
+	r := e.rule
+	n := 0 // occurrences generated, for COUNT
+	for step := 0; step < maxOccurrences; step++ {
+		for _, start := range r.period(e.start, step) {
+			if start.Before(e.start) {
+				continue
+			}
+			if !start.Before(to) || (!r.until.IsZero() && start.After(r.until)) || (r.count > 0 && n >= r.count) {
+				return out
+			}
+			n++
+			add(start)
+		}
+	}
+	return out
+}
+
+// period returns the occurrence starts of the step-th period of the rule,
+// in order. Only weekly rules expand BYDAY; the others repeat the start.
+func (r *rule) period(start time.Time, step int) []time.Time {
+	k := step * r.interval
+	switch r.freq {
+	case "DAILY":
+		return []time.Time{start.AddDate(0, 0, k)}
+	case "MONTHLY":
+		return []time.Time{start.AddDate(0, k, 0)}
+	case "YEARLY":
+		return []time.Time{start.AddDate(k, 0, 0)}
+	}
+	if len(r.byDay) == 0 {
+		return []time.Time{start.AddDate(0, 0, 7*k)}
+	}
+	// Weeks start on Monday (the RFC 5545 default WKST).
+	monday := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)+7*k)
+	days := make([]time.Time, 0, len(r.byDay))
+	for _, wd := range r.byDay {
+		days = append(days, monday.AddDate(0, 0, (int(wd)+6)%7))
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
@@ -0,0 +1,212 @@
+// Package keywords provides a rule-based intent compiler. It understands
+// far less than a language model, picking artists out of phrases such as
+// "like Daft Punk", genres from a fixed list, vibe words such as "chill"
+// or "upbeat", decades, "deep cuts" or "hits" and lengths such as
+// "45-minute", but it needs no model and cannot be down, so it stands in
+// when the configured compiler fails.
+package keywords
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// artistPhrase matches the words introducing artists, capturing what
+// follows up to the end of the clause.
+var artistPhrase = regexp.MustCompile(`(?i)\b(?:like|by|from|similar to|such as|songs of|music of)\s+([^.;!?()]+)`)
+
+// clauseEnd cuts an artist phrase where the sentence moves on, as in "like
+// Daft Punk for a workout".
+var clauseEnd = regexp.MustCompile(`(?i)\s+(?:for|with|but|to|that|while|when|in|on|at|please)\s`)
+
+// artistSeparator splits a list of artists. A bare "&" is left alone, as
+// in "Simon & Garfunkel".
+var artistSeparator = regexp.MustCompile(`(?i)\s*,\s*(?:and\s+|or\s+)?|\s+(?:and|or)\s+`)
+
+// fillers start phrases that follow "like" without naming an artist, as in
+// "I'd like some chill music".
+var fillers = map[string]bool{
+	"a": true, "an": true, "some": true, "something": true, "more": true, "to": true,
+	"it": true, "this": true, "that": true, "my": true, "our": true, "music": true,
+	"songs": true, "tracks": true, "anything": true, "what": true,
+}
+
+// genres are recognized as whole words; variants map to one spelling.
+var genres = map[string]string{
+	"ambient": "ambient", "blues": "blues", "classical": "classical", "country": "country",
+	"disco": "disco", "edm": "electronic", "electronic": "electronic", "folk": "folk",
+	"funk": "funk", "hip hop": "hip hop", "hip-hop": "hip hop", "house": "house",
+	"indie": "indie", "jazz": "jazz", "k-pop": "k-pop", "latin": "latin",
+	"lo-fi": "lo-fi", "lofi": "lo-fi", "metal": "metal", "pop": "pop", "punk": "punk",
+	"r&b": "r&b", "rap": "hip hop", "reggae": "reggae", "rock": "rock", "soul": "soul",
+	"techno": "techno",
+}
+
+// vibe sets one constraint of an intent.
+type vibe func(*domain.IntentObject)
+
+func energy(c domain.VibeConstraint) vibe {
+	return func(i *domain.IntentObject) { i.VibeConstraints.Energy = &c }
+}
+
+func valence(c domain.VibeConstraint) vibe {
+	return func(i *domain.IntentObject) { i.VibeConstraints.Valence = &c }
+}
+
+func acoustic(c domain.VibeConstraint) vibe {
+	return func(i *domain.IntentObject) { i.VibeConstraints.Acoustic = &c }
+}
+
+func instrumental(c domain.VibeConstraint) vibe {
+	return func(i *domain.IntentObject) { i.VibeConstraints.Instrument = &c }
+}
+
+var (
+	high = domain.VibeConstraint{Min: 0.6, Max: 1, Weight: domain.WeightHigh}
+	low  = domain.VibeConstraint{Min: 0, Max: 0.4, Weight: domain.WeightHigh}
+)
+
+// vibes maps vibe words to the constraints they set. Later words in a
+// message override earlier ones on the same feature.
+var vibes = map[string]vibe{
+	"calm": energy(low), "chill": energy(low), "mellow": energy(low), "relaxing": energy(low),
+	"sleep": energy(low), "slow": energy(low), "quiet": energy(low),
+	"energetic": energy(high), "upbeat": energy(high), "hype": energy(high), "intense": energy(high),
+	"workout": energy(high), "party": energy(high), "dance": energy(high), "fast": energy(high),
+	"happy": valence(high), "cheerful": valence(high), "feel-good": valence(high), "sunny": valence(high),
+	"sad": valence(low), "melancholy": valence(low), "dark": valence(low), "moody": valence(low),
+	"acoustic": acoustic(high), "unplugged": acoustic(high),
+	"instrumental": instrumental(high), "study": instrumental(high), "focus": instrumental(high),
+}
+
+// popularities map words and phrases to the popularity they ask for.
+var popularities = map[string]domain.PopularityConstraint{
+	"deep cuts": {Max: 30}, "obscure": {Max: 30}, "underground": {Max: 30},
+	"hits": {Min: 70}, "popular": {Min: 70}, "mainstream": {Min: 70},
+}
+
+// decade matches decades such as "90s" or "1990s".
+var decade = regexp.MustCompile(`^(?:\d{2}|\d{3}0)s$`)
+
+// length matches lengths such as "45-minute", "90 minutes" or "2 hours".
+var length = regexp.MustCompile(`^(\d+)[- ](?:min|mins|minute|minutes|hr|hrs|hour|hours)$`)
+
+// sequences maps words to the sequence pattern they ask for.
+var sequences = map[string]string{
+	"build": domain.SequenceLinear, "builds": domain.SequenceLinear, "building": domain.SequenceLinear, "ramp": domain.SequenceLinear,
+	"peak": domain.SequencePeak, "arc": domain.SequencePeak,
+	"shuffle": domain.SequenceShuffle, "random": domain.SequenceShuffle,
+}
+
+// word matches the words of a message, keeping inner hyphens and
+// ampersands as in "lo-fi" or "r&b".
+var word = regexp.MustCompile(`[\p{L}\p{N}]+(?:[-&][\p{L}\p{N}]+)*`)
+
+// Compiler implements ports.IntentCompiler with keyword rules.
+type Compiler struct{}
+
+var _ ports.IntentCompiler = Compiler{}
+
+// NewCompiler returns a Compiler.
+func NewCompiler() Compiler {
+	return Compiler{}
+}
+
+// AnalyzeIntent implements ports.IntentCompiler. It never fails.
+func (Compiler) AnalyzeIntent(_ context.Context, message string) (domain.IntentObject, error) {
+	return Parse(message), nil
+}
+
+// Parse compiles message into an intent by keyword rules.
+func Parse(message string) domain.IntentObject {
+	intent := domain.IntentObject{IntentType: domain.IntentCreate}
+	intent.Entities.Artists = artists(message)
+	intent.Entities.Genres = []string{}
+	if intent.Entities.Artists == nil {
+		intent.Entities.Artists = []string{}
+	}
+
+	words := word.FindAllString(strings.ToLower(message), -1)
+	seen := map[string]bool{}
+	for i, w := range words {
+		genre, ok := genres[w]
+		if !ok && i+1 < len(words) {
+			genre, ok = genres[w+" "+words[i+1]]
+		}
+		if ok && !seen[genre] {
+			seen[genre] = true
+			intent.Entities.Genres = append(intent.Entities.Genres, genre)
+		}
+		popularity, ok := popularities[w]
+		if !ok && i+1 < len(words) {
+			popularity, ok = popularities[w+" "+words[i+1]]
+		}
+		if ok {
+			intent.Popularity = &popularity
+		}
+		if decade.MatchString(w) {
+			intent.Era = &domain.EraConstraint{Decade: w}
+		}
+		if d, ok := duration(w, words[i+1:]); ok {
+			intent.Duration = &d
+		}
+		if set, ok := vibes[w]; ok {
+			set(&intent)
+		}
+		if pattern, ok := sequences[w]; ok {
+			intent.Sequence.Pattern = pattern
+		}
+	}
+	intent.Explanation = "Compiled by keyword rules while the intent compiler is unavailable."
+	return intent
+}
+
+// duration reads a length from w, or w and the word after it.
+func duration(w string, rest []string) (domain.DurationConstraint, bool) {
+	m := length.FindStringSubmatch(w)
+	if m == nil && len(rest) > 0 {
+		m = length.FindStringSubmatch(w + " " + rest[0])
+	}
+	if m == nil {
+		return domain.DurationConstraint{}, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n == 0 {
+		return domain.DurationConstraint{}, false
+	}
+	if strings.HasPrefix(m[0][len(m[1])+1:], "h") {
+		n *= 60
+	}
+	return domain.DurationConstraint{Minutes: min(n, domain.MaxDurationMinutes)}, true
+}
+
+// artists returns the artists named after phrases such as "like" or "by",
+// in order and without repeats.
+func artists(message string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, m := range artistPhrase.FindAllStringSubmatch(message, -1) {
+		phrase := m[1]
+		if first, _, _ := strings.Cut(phrase, " "); fillers[strings.ToLower(first)] {
+			continue
+		}
+		if loc := clauseEnd.FindStringIndex(phrase + " "); loc != nil {
+			phrase = phrase[:loc[0]]
+		}
+		for _, name := range artistSeparator.Split(phrase, -1) {
+			name = strings.Trim(name, " \t\"'")
+			key := strings.ToLower(name)
+			if name == "" || seen[key] || genres[key] != "" || vibes[key] != nil {
+				continue
+			}
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,107 @@
+package keywords
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    string
+		artists    []string
+		genres     []string
+		energy     *domain.VibeConstraint
+		valence    *domain.VibeConstraint
+		era        *domain.EraConstraint
+		popularity *domain.PopularityConstraint
+		duration   *domain.DurationConstraint
+		sequence   string
+	}{
+		{
+			name:    "artists after like",
+			message: "Something chill like Bonobo, Tycho and Nujabes for studying",
+			artists: []string{"Bonobo", "Tycho", "Nujabes"},
+			genres:  []string{},
+			energy:  &low,
+		},
+		{
+			name:     "band names keep their ampersand",
+			message:  "upbeat folk by Simon & Garfunkel that builds",
+			artists:  []string{"Simon & Garfunkel"},
+			genres:   []string{"folk"},
+			energy:   &high,
+			sequence: domain.SequenceLinear,
+		},
+		{
+			name:    "like without an artist",
+			message: "I'd like some happy hip hop and lo-fi",
+			artists: []string{},
+			genres:  []string{"hip hop", "lo-fi"},
+			valence: &high,
+		},
+		{
+			name:       "decade and deep cuts",
+			message:    "90s rock deep cuts",
+			artists:    []string{},
+			genres:     []string{"rock"},
+			era:        &domain.EraConstraint{Decade: "90s"},
+			popularity: &domain.PopularityConstraint{Max: 30},
+		},
+		{
+			name:     "length in minutes",
+			message:  "a 45-minute workout mix",
+			artists:  []string{},
+			genres:   []string{},
+			energy:   &high,
+			duration: &domain.DurationConstraint{Minutes: 45},
+		},
+		{
+			name:     "length in hours",
+			message:  "2 hours of jazz",
+			artists:  []string{},
+			genres:   []string{"jazz"},
+			duration: &domain.DurationConstraint{Minutes: 120},
+		},
+		{
+			name:    "no keywords",
+			message: "surprise me",
+			artists: []string{},
+			genres:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.message)
+			if err := got.Validate(); err != nil {
+				t.Errorf("invalid intent: %v", err)
+			}
+			if !reflect.DeepEqual(got.Entities.Artists, tt.artists) {
+				t.Errorf("artists = %q, want %q", got.Entities.Artists, tt.artists)
+			}
+			if !reflect.DeepEqual(got.Entities.Genres, tt.genres) {
+				t.Errorf("genres = %q, want %q", got.Entities.Genres, tt.genres)
+			}
+			if !reflect.DeepEqual(got.VibeConstraints.Energy, tt.energy) {
+				t.Errorf("energy = %+v, want %+v", got.VibeConstraints.Energy, tt.energy)
+			}
+			if !reflect.DeepEqual(got.VibeConstraints.Valence, tt.valence) {
+				t.Errorf("valence = %+v, want %+v", got.VibeConstraints.Valence, tt.valence)
+			}
+			if !reflect.DeepEqual(got.Era, tt.era) {
+				t.Errorf("era = %+v, want %+v", got.Era, tt.era)
+			}
+			if !reflect.DeepEqual(got.Popularity, tt.popularity) {
+				t.Errorf("popularity = %+v, want %+v", got.Popularity, tt.popularity)
+			}
+			if !reflect.DeepEqual(got.Duration, tt.duration) {
+				t.Errorf("duration = %+v, want %+v", got.Duration, tt.duration)
+			}
+			if got.Sequence.Pattern != tt.sequence {
+				t.Errorf("sequence = %q, want %q", got.Sequence.Pattern, tt.sequence)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Package lastfm provides a similar-artist adapter for the Last.fm API
+// (https://www.last.fm/api).
+package lastfm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+)
+
+const defaultBaseURL = "https://ws.audioscrobbler.com"
+
+// errInvalidParameters is the Last.fm error code for an unknown artist.
+const errInvalidParameters = 6
+
+// Client implements ports.SimilarArtistProvider.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+var _ ports.SimilarArtistProvider = (*Client)(nil)
+
+// NewClient returns a Client authenticating with apiKey against baseURL,
+// or the public Last.fm API when it is empty.
+func NewClient(apiKey, baseURL string) *Client {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{baseURL: baseURL, apiKey: apiKey, httpClient: httpx.NewClient("lastfm", 10*time.Second, httpx.DefaultPolicy())}
+}
+
+type similarResponse struct {
+	// Error and Message are set instead of the result when the call fails,
+	// sometimes with a 200 status.
+	Error          int    `json:"error"`
+	Message        string `json:"message"`
+	SimilarArtists struct {
+		Artist []struct {
+			Name string `json:"name"`
+		} `json:"artist"`
+	} `json:"similarartists"`
+}
+
+// GetSimilarArtists implements ports.SimilarArtistProvider. Misspelled
+// artist names are corrected by Last.fm.
+func (c *Client) GetSimilarArtists(ctx context.Context, artist string, limit int) ([]string, error) {
+	q := url.Values{}
+	q.Set("method", "artist.getsimilar")
+	q.Set("artist", artist)
+	q.Set("autocorrect", "1")
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("api_key", c.apiKey)
+	q.Set("format", "json")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/2.0/?"+q.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: build request: %w", err)
+	}
+
+	start := time.Now()
+	parsed, outcome, err := c.get(req)
+	requestDuration.Observe(time.Since(start).Seconds(), outcome)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(parsed.SimilarArtists.Artist))
+	for _, a := range parsed.SimilarArtists.Artist {
+		if len(names) == limit {
+			break
+		}
+		names = append(names, a.Name)
+	}
+	return names, nil
+}
+
+// get sends req and decodes the similar artists it returns. Last.fm reports
+// errors in the body, with any status: an artist it does not know, or a
+// 404, is domain.ErrNotFound, and other errors keep Last.fm's code and
+// message. outcome is ok, network, not_found, api_error, status or decode.
+func (c *Client) get(req *http.Request) (similarResponse, string, error) {
+	resp, err := c.httpClient.Do(req) // #nosec G107,G704 -- base URL comes from configuration
+	if err != nil {
+		return similarResponse{}, "network", fmt.Errorf("lastfm: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var parsed similarResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&parsed)
+	if parsed.Error == errInvalidParameters || resp.StatusCode == http.StatusNotFound {
+		return similarResponse{}, "not_found", fmt.Errorf("lastfm: %w", domain.ErrNotFound)
+	}
+	if parsed.Error != 0 {
+		return similarResponse{}, "api_error", fmt.Errorf("lastfm: error %d: %s", parsed.Error, parsed.Message)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return similarResponse{}, "status", fmt.Errorf("lastfm: unexpected status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return similarResponse{}, "decode", fmt.Errorf("lastfm: decode response: %w", decodeErr)
+	}
+	return parsed, "ok", nil
+}
@@ -0,0 +1,69 @@
+package lastfm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestClient_GetSimilarArtists(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		want         []string
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{
+			name:   "Success",
+			status: http.StatusOK,
+			body:   `{"similarartists":{"artist":[{"name":"Waylon Jennings","match":"1"},{"name":"Merle Haggard","match":"0.9"},{"name":"Johnny Cash","match":"0.8"}]}}`,
+			want:   []string{"Waylon Jennings", "Merle Haggard"},
+		},
+		{name: "Unknown artist", status: http.StatusOK, body: `{"error":6,"message":"The artist you supplied could not be found"}`, wantErr: true, wantNotFound: true},
+		{name: "Invalid key", status: http.StatusForbidden, body: `{"error":10,"message":"Invalid API key"}`, wantErr: true},
+		{name: "Server error", status: http.StatusInternalServerError, body: `{}`, wantErr: true},
+		{name: "Malformed response", status: http.StatusOK, body: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			names, err := NewClient("key", srv.URL).GetSimilarArtists(context.Background(), "Willie Nelson", 2)
+
+			q := got.URL.Query()
+			if got.URL.Path != "/2.0/" || q.Get("method") != "artist.getsimilar" || q.Get("artist") != "Willie Nelson" ||
+				q.Get("limit") != "2" || q.Get("api_key") != "key" {
+				t.Errorf("unexpected request %s", got.URL)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if errors.Is(err, domain.ErrNotFound) != tt.wantNotFound {
+					t.Errorf("expected not-found %v, got %v", tt.wantNotFound, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("got %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package lastfm
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var requestDuration = metrics.NewHistogramVec(
+	"overture_lastfm_request_duration_seconds",
+	"Latency of Last.fm API calls, by outcome (ok, not_found, network, status, api_error, decode).",
+	nil,
+	"outcome",
+)
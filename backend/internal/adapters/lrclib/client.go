@@ -0,0 +1,99 @@
+// Package lrclib provides a lyrics adapter for LRCLib (https://lrclib.net),
+// a free database of synced and plain lyrics.
+package lrclib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+)
+
+const defaultBaseURL = "https://lrclib.net"
+
+// Client implements ports.LyricsProvider.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for baseURL, or the public LRCLib API when it
+// is empty.
+func NewClient(baseURL string) *Client {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{baseURL: baseURL, httpClient: httpx.NewClient("lrclib", 10*time.Second, httpx.DefaultPolicy())}
+}
+
+type lyricsResponse struct {
+	Instrumental bool   `json:"instrumental"`
+	PlainLyrics  string `json:"plainLyrics"`
+	SyncedLyrics string `json:"syncedLyrics"`
+}
+
+// GetLyrics implements ports.LyricsProvider. LRCLib matches on title,
+// artist, album and duration, so the track's metadata should come from the
+// same release.
+func (c *Client) GetLyrics(ctx context.Context, track domain.Track) (domain.Lyrics, error) {
+	q := url.Values{}
+	q.Set("track_name", track.Title)
+	q.Set("artist_name", track.Artist)
+	if track.Album != "" {
+		q.Set("album_name", track.Album)
+	}
+	if track.DurationMs > 0 {
+		q.Set("duration", strconv.Itoa((track.DurationMs+500)/1000))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/get?"+q.Encode(), nil)
+	if err != nil {
+		return domain.Lyrics{}, fmt.Errorf("lrclib: build request: %w", err)
+	}
+
+	start := time.Now()
+	parsed, outcome, err := c.get(req)
+	requestDuration.Observe(time.Since(start).Seconds(), outcome)
+	if err != nil {
+		return domain.Lyrics{}, err
+	}
+	return domain.Lyrics{
+		TrackID:      track.ID,
+		Plain:        parsed.PlainLyrics,
+		Synced:       parsed.SyncedLyrics,
+		Instrumental: parsed.Instrumental,
+		Source:       "lrclib",
+		FetchedAt:    time.Now().UTC(),
+	}, nil
+}
+
+// get sends req and decodes the lyrics it returns. LRCLIB answers 404 for
+// tracks it has no lyrics for, which is domain.ErrNotFound rather than a
+// failure. outcome is ok, network, not_found, status or decode.
+func (c *Client) get(req *http.Request) (lyricsResponse, string, error) {
+	resp, err := c.httpClient.Do(req) // #nosec G107,G704
+	if err != nil {
+		return lyricsResponse{}, "network", fmt.Errorf("lrclib: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return lyricsResponse{}, "not_found", fmt.Errorf("lrclib: %w", domain.ErrNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return lyricsResponse{}, "status", fmt.Errorf("lrclib: unexpected status %d", resp.StatusCode)
+	}
+
+	var parsed lyricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return lyricsResponse{}, "decode", fmt.Errorf("lrclib: decode response: %w", err)
+	}
+	return parsed, "ok", nil
+}
@@ -0,0 +1,68 @@
+package lrclib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestClient_GetLyrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantErr      bool
+		wantNotFound bool
+		wantPlain    string
+	}{
+		{
+			name:      "Success",
+			status:    http.StatusOK,
+			body:      `{"id":1,"instrumental":false,"plainLyrics":"Hello darkness","syncedLyrics":"[00:01.00] Hello darkness"}`,
+			wantPlain: "Hello darkness",
+		},
+		{name: "Not found", status: http.StatusNotFound, body: `{"code":404}`, wantErr: true, wantNotFound: true},
+		{name: "Server error", status: http.StatusInternalServerError, body: `{}`, wantErr: true},
+		{name: "Malformed response", status: http.StatusOK, body: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			track := domain.Track{ID: "t1", Title: "The Sound of Silence", Artist: "Simon & Garfunkel", Album: "Sounds of Silence", DurationMs: 185400}
+			lyrics, err := NewClient(srv.URL).GetLyrics(context.Background(), track)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected err=%v, got %v", tt.wantErr, err)
+			}
+			if errors.Is(err, domain.ErrNotFound) != tt.wantNotFound {
+				t.Fatalf("expected not found=%v, got %v", tt.wantNotFound, err)
+			}
+
+			q := got.URL.Query()
+			if got.URL.Path != "/api/get" || q.Get("track_name") != track.Title || q.Get("artist_name") != track.Artist ||
+				q.Get("album_name") != track.Album || q.Get("duration") != "185" {
+				t.Fatalf("unexpected request %s", got.URL)
+			}
+			if got.Header.Get("User-Agent") == "" {
+				t.Fatal("expected a User-Agent header")
+			}
+			if tt.wantErr {
+				return
+			}
+			if lyrics.TrackID != "t1" || lyrics.Plain != tt.wantPlain || lyrics.Synced == "" || lyrics.Source != "lrclib" {
+				t.Fatalf("unexpected lyrics %+v", lyrics)
+			}
+		})
+	}
+}
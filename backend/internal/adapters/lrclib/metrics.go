@@ -0,0 +1,10 @@
+package lrclib
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var requestDuration = metrics.NewHistogramVec(
+	"overture_lrclib_request_duration_seconds",
+	"Latency of LRCLib API calls, by outcome (ok, not_found, network, status, decode).",
+	nil,
+	"outcome",
+)
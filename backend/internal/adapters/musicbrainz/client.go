@@ -0,0 +1,202 @@
+// Package musicbrainz provides an audio features adapter that resolves a
+// recording's ISRC through MusicBrainz (https://musicbrainz.org) and reads
+// the features AcousticBrainz (https://acousticbrainz.org) measured for it.
+package musicbrainz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+)
+
+const (
+	defaultMusicBrainzURL    = "https://musicbrainz.org"
+	defaultAcousticBrainzURL = "https://acousticbrainz.org"
+	// musicBrainzInterval paces MusicBrainz requests to the one per second
+	// its rate limit allows.
+	musicBrainzInterval = time.Second
+	// maxRecordings bounds how many recordings sharing an ISRC are tried
+	// for features.
+	maxRecordings = 3
+)
+
+// Config configures a Client. Empty URLs use the public APIs.
+type Config struct {
+	MusicBrainzURL    string
+	AcousticBrainzURL string
+}
+
+// Client implements ports.FeatureProvider.
+type Client struct {
+	musicBrainzURL    string
+	acousticBrainzURL string
+	musicBrainz       *http.Client
+	acousticBrainz    *http.Client
+	pacer             *pacer
+}
+
+var _ ports.FeatureProvider = (*Client)(nil)
+
+// NewClient returns a Client for cfg.
+func NewClient(cfg Config) *Client {
+	c := &Client{
+		musicBrainzURL:    strings.TrimRight(cfg.MusicBrainzURL, "/"),
+		acousticBrainzURL: strings.TrimRight(cfg.AcousticBrainzURL, "/"),
+		musicBrainz:       &http.Client{Timeout: 10 * time.Second},
+		acousticBrainz:    httpx.NewClient("acousticbrainz", 10*time.Second, httpx.DefaultPolicy()),
+		pacer:             &pacer{interval: musicBrainzInterval},
+	}
+	if c.musicBrainzURL == "" {
+		c.musicBrainzURL = defaultMusicBrainzURL
+	}
+	if c.acousticBrainzURL == "" {
+		c.acousticBrainzURL = defaultAcousticBrainzURL
+	}
+	httpx.Wrap(c.musicBrainz, "musicbrainz", httpx.DefaultPolicy()).Hooks.Wait = c.pacer.wait
+	return c
+}
+
+// GetFeatures implements ports.FeatureProvider with the features of the
+// first recording with the ISRC that AcousticBrainz has analyzed.
+func (c *Client) GetFeatures(ctx context.Context, isrc string) (domain.AudioFeatures, error) {
+	isrc = strings.ToUpper(strings.TrimSpace(isrc))
+	if isrc == "" {
+		return domain.AudioFeatures{}, fmt.Errorf("musicbrainz: no ISRC: %w", domain.ErrNotFound)
+	}
+	var recordings struct {
+		Recordings []struct {
+			ID string `json:"id"`
+		} `json:"recordings"`
+	}
+	if err := c.get(ctx, c.musicBrainz, "isrc", c.musicBrainzURL+"/ws/2/isrc/"+url.PathEscape(isrc)+"?fmt=json", &recordings); err != nil {
+		return domain.AudioFeatures{}, err
+	}
+
+	for i, r := range recordings.Recordings {
+		if i == maxRecordings {
+			break
+		}
+		features, err := c.recordingFeatures(ctx, r.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		return features, err
+	}
+	return domain.AudioFeatures{}, fmt.Errorf("musicbrainz: no features for %s: %w", isrc, domain.ErrNotFound)
+}
+
+// probabilities is an AcousticBrainz classifier result: the probability of
+// each class, e.g. {"happy": 0.8, "not_happy": 0.2}.
+type probabilities struct {
+	All map[string]float64 `json:"all"`
+}
+
+// recordingFeatures maps the AcousticBrainz classifiers of the recording
+// with MusicBrainz ID mbid onto Spotify's feature scales.
+func (c *Client) recordingFeatures(ctx context.Context, mbid string) (domain.AudioFeatures, error) {
+	base := c.acousticBrainzURL + "/api/v1/" + url.PathEscape(mbid)
+	var high struct {
+		HighLevel map[string]probabilities `json:"highlevel"`
+	}
+	if err := c.get(ctx, c.acousticBrainz, "high_level", base+"/high-level", &high); err != nil {
+		return domain.AudioFeatures{}, err
+	}
+	var low struct {
+		Rhythm struct {
+			BPM float64 `json:"bpm"`
+		} `json:"rhythm"`
+	}
+	if err := c.get(ctx, c.acousticBrainz, "low_level", base+"/low-level", &low); err != nil {
+		return domain.AudioFeatures{}, err
+	}
+
+	p := func(classifier, class string) float64 {
+		return high.HighLevel[classifier].All[class]
+	}
+	return domain.AudioFeatures{
+		Danceability:     p("danceability", "danceable"),
+		Energy:           (p("mood_aggressive", "aggressive") + p("mood_party", "party") + 1 - p("mood_relaxed", "relaxed")) / 3,
+		Valence:          (p("mood_happy", "happy") + 1 - p("mood_sad", "sad")) / 2,
+		Tempo:            low.Rhythm.BPM,
+		Instrumentalness: p("voice_instrumental", "instrumental"),
+		Acousticness:     p("mood_acoustic", "acoustic"),
+	}, nil
+}
+
+// get fetches rawURL with client into dst.
+func (c *Client) get(ctx context.Context, client *http.Client, endpoint, rawURL string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("musicbrainz: build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	outcome, err := do(client, req, dst)
+	requestDuration.Observe(time.Since(start).Seconds(), endpoint, outcome)
+	return err
+}
+
+// do sends req and decodes the reply. outcome classifies the result for
+// metrics.
+func do(client *http.Client, req *http.Request, dst any) (string, error) {
+	resp, err := client.Do(req) // #nosec G107,G704 -- base URLs come from configuration
+	if err != nil {
+		return "network", fmt.Errorf("musicbrainz: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "not_found", fmt.Errorf("musicbrainz: %s: %w", req.URL.Host, domain.ErrNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "status", fmt.Errorf("musicbrainz: %s: unexpected status %d", req.URL.Host, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return "decode", fmt.Errorf("musicbrainz: %s: decode response: %w", req.URL.Host, err)
+	}
+	return "ok", nil
+}
+
+// pacer spaces requests at least interval apart.
+type pacer struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the caller's turn, or ctx ends.
+func (p *pacer) wait(ctx context.Context) error {
+	p.mu.Lock()
+	now := time.Now()
+	at := p.next
+	if at.Before(now) {
+		at = now
+	}
+	p.next = at.Add(p.interval)
+	p.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,124 @@
+package musicbrainz
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+const highLevel = `{"highlevel":{
+	"danceability":{"all":{"danceable":0.8,"not_danceable":0.2}},
+	"mood_aggressive":{"all":{"aggressive":0.3,"not_aggressive":0.7}},
+	"mood_party":{"all":{"party":0.6,"not_party":0.4}},
+	"mood_relaxed":{"all":{"relaxed":0.1,"not_relaxed":0.9}},
+	"mood_happy":{"all":{"happy":0.7,"not_happy":0.3}},
+	"mood_sad":{"all":{"sad":0.1,"not_sad":0.9}},
+	"voice_instrumental":{"all":{"instrumental":0.2,"voice":0.8}},
+	"mood_acoustic":{"all":{"acoustic":0.4,"not_acoustic":0.6}}
+}}`
+
+func TestClient_GetFeatures(t *testing.T) {
+	tests := []struct {
+		name         string
+		isrc         string
+		recordings   string
+		analyzed     map[string]bool
+		status       int
+		want         domain.AudioFeatures
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{
+			name:       "Features of the first analyzed recording",
+			isrc:       "usabc1234567",
+			recordings: `{"recordings":[{"id":"rec-1"},{"id":"rec-2"}]}`,
+			analyzed:   map[string]bool{"rec-2": true},
+			want: domain.AudioFeatures{
+				Danceability: 0.8, Energy: 0.6, Valence: 0.8, Tempo: 128,
+				Instrumentalness: 0.2, Acousticness: 0.4,
+			},
+		},
+		{name: "Unanalyzed recordings", isrc: "USABC1234567", recordings: `{"recordings":[{"id":"rec-1"}]}`, wantErr: true, wantNotFound: true},
+		{name: "Unknown ISRC", isrc: "USABC1234567", status: http.StatusNotFound, wantErr: true, wantNotFound: true},
+		{name: "No ISRC", isrc: "", wantErr: true, wantNotFound: true},
+		{name: "Server error", isrc: "USABC1234567", status: http.StatusBadRequest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/ws/2/isrc/USABC1234567":
+					if tt.status != 0 {
+						w.WriteHeader(tt.status)
+						return
+					}
+					_, _ = w.Write([]byte(tt.recordings))
+				case "/api/v1/rec-2/high-level":
+					_, _ = w.Write([]byte(highLevel))
+				case "/api/v1/rec-2/low-level":
+					_, _ = w.Write([]byte(`{"rhythm":{"bpm":128}}`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			client := NewClient(Config{MusicBrainzURL: srv.URL, AcousticBrainzURL: srv.URL})
+			client.pacer.interval = 0
+			got, err := client.GetFeatures(context.Background(), tt.isrc)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if errors.Is(err, domain.ErrNotFound) != tt.wantNotFound {
+					t.Errorf("expected not-found %v, got %v", tt.wantNotFound, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, f := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"danceability", got.Danceability, tt.want.Danceability},
+				{"energy", got.Energy, tt.want.Energy},
+				{"valence", got.Valence, tt.want.Valence},
+				{"tempo", got.Tempo, tt.want.Tempo},
+				{"instrumentalness", got.Instrumentalness, tt.want.Instrumentalness},
+				{"acousticness", got.Acousticness, tt.want.Acousticness},
+			} {
+				if math.Abs(f.got-f.want) > 1e-9 {
+					t.Errorf("expected %s %v, got %v", f.name, f.want, f.got)
+				}
+			}
+		})
+	}
+}
+
+func TestPacer(t *testing.T) {
+	p := &pacer{interval: 50 * time.Millisecond}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := p.wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected three requests to span two intervals, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the wait to end with the context, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+package musicbrainz
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var requestDuration = metrics.NewHistogramVec(
+	"overture_musicbrainz_request_duration_seconds",
+	"Latency of MusicBrainz and AcousticBrainz API calls, by endpoint (isrc, high_level, low_level) and outcome (ok, not_found, network, status, decode).",
+	nil,
+	"endpoint", "outcome",
+)
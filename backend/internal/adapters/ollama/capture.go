@@ -0,0 +1,77 @@
+package ollama
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// CaptureConfig controls the opt-in audit trail of intent compiler exchanges.
+type CaptureConfig struct {
+	Store ports.CaptureStore
+	// MaxAge and MaxEntries bound how many captures are kept; captures are
+	// pruned after each write. Zero disables the respective limit.
+	MaxAge     time.Duration
+	MaxEntries int
+}
+
+// EnableCapture records every prompt and raw response from now on.
+func (c *Client) EnableCapture(cfg CaptureConfig) {
+	c.capture = &cfg
+}
+
+var redactions = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)bearer\s+[a-z0-9._~+/=-]+`), "Bearer [REDACTED]"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[EMAIL]"},
+	{regexp.MustCompile(`https?://[^\s"']+`), "[URL]"},
+	{regexp.MustCompile(`\+?(?:\d[ -]?){9,}\d`), "[NUMBER]"},
+}
+
+// redact masks personal data and credentials users sometimes paste into
+// messages: bearer tokens, email addresses, URLs and long digit runs such
+// as phone or card numbers.
+func redact(s string) string {
+	for _, r := range redactions {
+		s = r.pattern.ReplaceAllString(s, r.replacement)
+	}
+	return s
+}
+
+// record stores one exchange. Failures are logged rather than returned so a
+// broken audit trail never fails intent analysis.
+func (c *Client) record(ctx context.Context, prompt []byte, response string, callErr error, elapsed time.Duration) {
+	if c.capture == nil || c.capture.Store == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	capture := domain.IntentCapture{
+		ID:         uuid.New().String(),
+		Model:      c.model,
+		Prompt:     redact(string(prompt)),
+		Response:   redact(response),
+		DurationMS: elapsed.Milliseconds(),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if callErr != nil {
+		capture.Error = redact(callErr.Error())
+	}
+	if err := c.capture.Store.SaveCapture(ctx, capture); err != nil {
+		c.logger.WarnContext(ctx, "failed to save intent capture", "error", err)
+		return
+	}
+
+	cutoff := time.Time{}
+	if c.capture.MaxAge > 0 {
+		cutoff = capture.CreatedAt.Add(-c.capture.MaxAge)
+	}
+	if _, err := c.capture.Store.PruneCaptures(ctx, cutoff, c.capture.MaxEntries); err != nil {
+		c.logger.WarnContext(ctx, "failed to prune intent captures", "error", err)
+	}
+}
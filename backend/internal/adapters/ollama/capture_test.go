@@ -0,0 +1,109 @@
+package ollama
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type memoryCaptures struct {
+	saved  []domain.IntentCapture
+	pruned int
+}
+
+func (m *memoryCaptures) SaveCapture(ctx context.Context, c domain.IntentCapture) error {
+	m.saved = append(m.saved, c)
+	return nil
+}
+
+func (m *memoryCaptures) ListCaptures(ctx context.Context, limit int) ([]domain.IntentCapture, error) {
+	return m.saved, nil
+}
+
+func (m *memoryCaptures) GetCapture(ctx context.Context, id string) (domain.IntentCapture, error) {
+	return domain.IntentCapture{}, domain.ErrNotFound
+}
+
+func (m *memoryCaptures) PruneCaptures(ctx context.Context, cutoff time.Time, keep int) (int64, error) {
+	m.pruned++
+	return 0, nil
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "email", in: "mail me at jane.doe@example.com", want: "mail me at [EMAIL]"},
+		{name: "phone", in: "call 555-123-4567 later", want: "call [NUMBER] later"},
+		{name: "bearer token", in: "Authorization: Bearer abc.def-123", want: "Authorization: Bearer [REDACTED]"},
+		{name: "url", in: "like https://open.spotify.com/track/abc?si=x", want: "like [URL]"},
+		{name: "tempo range untouched", in: "120 - 130 bpm, energy 0.8", want: "120 - 130 bpm, energy 0.8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redact(tt.in); got != tt.want {
+				t.Fatalf("redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Capture(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantErr   bool
+		wantReply string
+	}{
+		{
+			name:      "records prompt and raw response",
+			status:    http.StatusOK,
+			body:      `{"message":{"role":"assistant","content":"{\"intent_type\":\"CREATE\"}"}}`,
+			wantReply: `{"intent_type":"CREATE"}`,
+		},
+		{
+			name:    "records failures",
+			status:  http.StatusInternalServerError,
+			body:    `{"error":"bad"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			store := &memoryCaptures{}
+			client := NewClient(srv.URL)
+			client.EnableCapture(CaptureConfig{Store: store, MaxEntries: 10})
+			_, _ = client.AnalyzeIntent(context.Background(), "chill set for bob@example.com")
+
+			if len(store.saved) != 1 || store.pruned != 1 {
+				t.Fatalf("expected 1 capture and 1 prune, got %d and %d", len(store.saved), store.pruned)
+			}
+			got := store.saved[0]
+			if !strings.Contains(got.Prompt, systemPrompt[:40]) || !strings.Contains(got.Prompt, "chill set for [EMAIL]") {
+				t.Fatalf("prompt not captured or not redacted: %s", got.Prompt)
+			}
+			if got.Response != tt.wantReply {
+				t.Fatalf("response = %q, want %q", got.Response, tt.wantReply)
+			}
+			if (got.Error != "") != tt.wantErr {
+				t.Fatalf("error = %q, wantErr %v", got.Error, tt.wantErr)
+			}
+		})
+	}
+}
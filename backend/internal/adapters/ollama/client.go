@@ -8,27 +8,58 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/backend/internal/prompt"
 )
 
 const defaultBaseURL = "http://localhost:11434"
 
-const systemPrompt = "You are the Overture Music Intent Engine. Your goal is to translate abstract human desires into a structured JSON 'IntentObject'.\n\nRules:\nReasoning: Use your internal logic to map stylistic requests (e.g., 'no auto-tune') to technical constraints (e.g., 'acousticness.min: 0.8').\nEntities: Extract specific artists or genres mentioned.\nOutput: Return ONLY a valid JSON object. No conversational text.\nVibe Scaling: Energy and Valence are 0.0 to 1.0.\nExample Mapping: 'I want a sad acoustic set' -> { 'vibe_constraints': { 'valence': {'target': 0.2}, 'acousticness': {'min': 0.7} } }"
+// DefaultModel is the model a Client asks for unless WithModel says otherwise.
+const DefaultModel = "deepseek-r1:8b"
 
 type Client struct {
 	baseURL    string
 	model      string
 	httpClient *http.Client
+	capture    *CaptureConfig
+	prompts    *prompt.Set
+	logger     *slog.Logger
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithLogger logs through l instead of the default logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithModel asks Ollama for model instead of DefaultModel. An empty model
+// keeps the default.
+func WithModel(model string) Option {
+	return func(c *Client) {
+		if model != "" {
+			c.model = model
+		}
+	}
 }
 
 type chatMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// Thinking is the reasoning of thinking models such as deepseek-r1.
+	Thinking string `json:"thinking,omitempty"`
 }
 
 type chatRequest struct {
@@ -41,74 +72,233 @@ type chatRequest struct {
 type chatResponse struct {
 	Message chatMessage `json:"message"`
 	Error   string      `json:"error,omitempty"`
+	// Done marks the last chunk of a streamed reply.
+	Done bool `json:"done"`
 }
 
-func NewClient(baseURL string) *Client {
+func NewClient(baseURL string, opts ...Option) *Client {
 	baseURL = strings.TrimRight(baseURL, "/")
 	if baseURL == "" {
 		baseURL = defaultBaseURL
 	}
-	model := os.Getenv("OLLAMA_MODEL")
-	if model == "" {
-		model = "deepseek-r1:8b"
+	c := &Client{
+		baseURL:    baseURL,
+		model:      DefaultModel,
+		httpClient: httpx.NewClient("ollama", 120*time.Second, retryPolicy),
+		prompts:    prompt.Default(),
 	}
-	return &Client{
-		baseURL: baseURL,
-		model:   model,
-		httpClient: &http.Client{
-			Timeout: 120 * time.Second,
-		},
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.logger = logging.Component(c.logger, "ollama")
+	c.httpClient.Transport.(*httpx.Transport).Logger = c.logger
+	return c
+}
+
+// EnablePrompts renders the system prompt from the "ollama" template of
+// prompts instead of the embedded default.
+func (c *Client) EnablePrompts(prompts *prompt.Set) {
+	c.prompts = prompts
+}
+
+// retryPolicy retries only while Ollama is unreachable or still loading the
+// model; a 500 is a failed generation that would just fail again.
+var retryPolicy = httpx.Policy{
+	MaxAttempts: 2,
+	BaseBackoff: time.Second,
+	MaxBackoff:  5 * time.Second,
+	Retryable: func(resp *http.Response, err error) bool {
+		if err != nil {
+			return true
+		}
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	},
 }
 
 func (c *Client) AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error) {
+	req, body, err := c.newChatRequest(ctx, nil, message, false)
+	if err != nil {
+		return domain.IntentObject{}, err
+	}
+
+	start := time.Now()
+	parsed, outcome, err := c.chat(req)
+	elapsed := time.Since(start)
+	requestDuration.Observe(elapsed.Seconds(), "/api/chat", outcome)
+	c.record(ctx, body, parsed.Message.Content, err, elapsed)
+	if err != nil {
+		requestFailures.Inc("/api/chat", outcome)
+		return domain.IntentObject{}, err
+	}
+
+	return decodeIntent(parsed.Message.Content)
+}
+
+// AnalyzeIntentStream implements ports.IntentStreamer. Ollama streams the
+// reply as newline-delimited chunks; each one's thinking and content is
+// passed to onDelta, and the concatenated content is decoded as the intent
+// once the reply is done.
+func (c *Client) AnalyzeIntentStream(ctx context.Context, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	req, body, err := c.newChatRequest(ctx, nil, message, true)
+	if err != nil {
+		return domain.IntentObject{}, err
+	}
+
+	start := time.Now()
+	content, outcome, err := c.chatStream(req, onDelta)
+	elapsed := time.Since(start)
+	requestDuration.Observe(elapsed.Seconds(), "/api/chat", outcome)
+	c.record(ctx, body, content, err, elapsed)
+	if err != nil {
+		requestFailures.Inc("/api/chat", outcome)
+		return domain.IntentObject{}, err
+	}
+
+	return decodeIntent(content)
+}
+
+// AnalyzeIntentTurn implements ports.ConversationalCompiler. Each turn of
+// history is replayed as the user's message and the intent the model
+// answered with, so the model refines the last one.
+func (c *Client) AnalyzeIntentTurn(ctx context.Context, history []domain.IntentTurn, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	req, body, err := c.newChatRequest(ctx, history, message, onDelta != nil)
+	if err != nil {
+		return domain.IntentObject{}, err
+	}
+
+	start := time.Now()
+	var content, outcome string
+	if onDelta != nil {
+		content, outcome, err = c.chatStream(req, onDelta)
+	} else {
+		var parsed chatResponse
+		parsed, outcome, err = c.chat(req)
+		content = parsed.Message.Content
+	}
+	elapsed := time.Since(start)
+	requestDuration.Observe(elapsed.Seconds(), "/api/chat", outcome)
+	c.record(ctx, body, content, err, elapsed)
+	if err != nil {
+		requestFailures.Inc("/api/chat", outcome)
+		return domain.IntentObject{}, err
+	}
+
+	return decodeIntent(content)
+}
+
+// newChatRequest builds the /api/chat request for message following
+// history and returns it with its body, which captures record as the
+// prompt.
+func (c *Client) newChatRequest(ctx context.Context, history []domain.IntentTurn, message string, stream bool) (*http.Request, []byte, error) {
+	system, err := c.prompts.Render("ollama", ports.PlaylistSummaryFrom(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("ollama: %w", err)
+	}
+	messages := []chatMessage{{Role: "system", Content: system}}
+	for _, turn := range history {
+		intent, err := json.Marshal(turn.Intent)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ollama: marshal history: %w", err)
+		}
+		messages = append(messages,
+			chatMessage{Role: "user", Content: turn.Message},
+			chatMessage{Role: "assistant", Content: string(intent)},
+		)
+	}
 	payload := chatRequest{
-		Model:  c.model,
-		Stream: false,
-		Format: "json",
-		Messages: []chatMessage{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: message},
-		},
+		Model:    c.model,
+		Stream:   stream,
+		Format:   "json",
+		Messages: append(messages, chatMessage{Role: "user", Content: message}),
 	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return domain.IntentObject{}, fmt.Errorf("ollama: marshal request: %w", err)
+		return nil, nil, fmt.Errorf("ollama: marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/chat", bytes.NewReader(body))
 	if err != nil {
-		return domain.IntentObject{}, fmt.Errorf("ollama: build request: %w", err)
+		return nil, nil, fmt.Errorf("ollama: build request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	return req, body, nil
+}
+
+// decodeIntent parses the model's JSON reply.
+func decodeIntent(content string) (domain.IntentObject, error) {
+	if strings.TrimSpace(content) == "" {
+		return domain.IntentObject{}, fmt.Errorf("ollama: empty response")
+	}
+
+	var intent domain.IntentObject
+	if err := json.Unmarshal([]byte(content), &intent); err != nil {
+		return domain.IntentObject{}, fmt.Errorf("ollama: decode intent: %w", err)
+	}
+
+	return intent, nil
+}
 
+// chat sends req and decodes the reply. outcome classifies the result for
+// metrics: ok, network, status or decode.
+func (c *Client) chat(req *http.Request) (chatResponse, string, error) {
 	resp, err := c.httpClient.Do(req) // #nosec G107,G704
 	if err != nil {
-		return domain.IntentObject{}, fmt.Errorf("ollama: request failed: %w", err)
+		return chatResponse{}, "network", fmt.Errorf("ollama: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return domain.IntentObject{}, fmt.Errorf("ollama: unexpected status %d", resp.StatusCode)
+		return chatResponse{}, "status", fmt.Errorf("ollama: unexpected status %d", resp.StatusCode)
 	}
 
 	var parsed chatResponse
 	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		return domain.IntentObject{}, fmt.Errorf("ollama: decode response: %w", err)
+		return chatResponse{}, "decode", fmt.Errorf("ollama: decode response: %w", err)
 	}
 	if parsed.Error != "" {
-		return domain.IntentObject{}, fmt.Errorf("ollama: %s", parsed.Error)
+		return chatResponse{}, "status", fmt.Errorf("ollama: %s", parsed.Error)
 	}
+	return parsed, "ok", nil
+}
 
-	if strings.TrimSpace(parsed.Message.Content) == "" {
-		return domain.IntentObject{}, fmt.Errorf("ollama: empty response")
+// chatStream sends a streaming req, passing each chunk to onDelta, and
+// returns the concatenated content. outcome classifies the result as chat
+// does.
+func (c *Client) chatStream(req *http.Request, onDelta func(domain.IntentDelta)) (string, string, error) {
+	resp, err := c.httpClient.Do(req) // #nosec G107,G704
+	if err != nil {
+		return "", "network", fmt.Errorf("ollama: request failed: %w", err)
 	}
+	defer resp.Body.Close()
 
-	var intent domain.IntentObject
-	if err := json.Unmarshal([]byte(parsed.Message.Content), &intent); err != nil {
-		return domain.IntentObject{}, fmt.Errorf("ollama: decode intent: %w", err)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", "status", fmt.Errorf("ollama: unexpected status %d", resp.StatusCode)
 	}
 
-	return intent, nil
+	var content strings.Builder
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var chunk chatResponse
+		if err := dec.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				return content.String(), "decode", fmt.Errorf("ollama: stream ended before done")
+			}
+			return content.String(), "decode", fmt.Errorf("ollama: decode stream: %w", err)
+		}
+		if chunk.Error != "" {
+			return content.String(), "status", fmt.Errorf("ollama: %s", chunk.Error)
+		}
+		content.WriteString(chunk.Message.Content)
+		if chunk.Message.Content != "" || chunk.Message.Thinking != "" {
+			onDelta(domain.IntentDelta{Thinking: chunk.Message.Thinking, Content: chunk.Message.Content})
+		}
+		if chunk.Done {
+			return content.String(), "ok", nil
+		}
+	}
 }
@@ -6,26 +6,42 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/prompt"
 )
 
+// systemPrompt is the default prompt every request starts with.
+var systemPrompt, _ = prompt.Default().Render("ollama", nil)
+
 func TestClient_AnalyzeIntent(t *testing.T) {
 	tests := []struct {
 		name         string
 		status       int
 		responseBody string
 		wantErr      bool
+		wantOutcome  string
 	}{
 		{
 			name:         "Success",
 			status:       http.StatusOK,
 			responseBody: `{"message":{"role":"assistant","content":"{\"intent_type\":\"CREATE\",\"entities\":{\"artists\":[\"Willie Nelson\"],\"genres\":[]},\"vibe_constraints\":{\"acousticness\":{\"min\":0.8,\"weight\":\"HIGH\"}},\"sequence\":{\"pattern\":\"LINEAR\",\"description\":\"steady\"},\"explanation\":\"Test\"}"}}`,
 			wantErr:      false,
+			wantOutcome:  "ok",
 		},
 		{
 			name:         "Server error",
 			status:       http.StatusInternalServerError,
 			responseBody: `{"error":"bad"}`,
 			wantErr:      true,
+			wantOutcome:  "status",
+		},
+		{
+			name:         "Malformed response",
+			status:       http.StatusOK,
+			responseBody: `not json`,
+			wantErr:      true,
+			wantOutcome:  "decode",
 		},
 	}
 
@@ -52,13 +68,22 @@ func TestClient_AnalyzeIntent(t *testing.T) {
 			}))
 			defer srv.Close()
 
+			observedBefore := requestDuration.Count("/api/chat", tt.wantOutcome)
+			failuresBefore := requestFailures.Value("/api/chat", tt.wantOutcome)
+
 			client := NewClient(srv.URL)
 			intent, err := client.AnalyzeIntent(context.Background(), "test message")
 
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("expected err=%v, got %v", tt.wantErr, err)
 			}
+			if got := requestDuration.Count("/api/chat", tt.wantOutcome) - observedBefore; got != 1 {
+				t.Fatalf("expected 1 latency observation with outcome %q, got %d", tt.wantOutcome, got)
+			}
 			if tt.wantErr {
+				if got := requestFailures.Value("/api/chat", tt.wantOutcome) - failuresBefore; got != 1 {
+					t.Fatalf("expected 1 failure with cause %q, got %v", tt.wantOutcome, got)
+				}
 				return
 			}
 			// Model should be either env var or default
@@ -83,3 +108,134 @@ func TestClient_AnalyzeIntent(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_AnalyzeIntentStream(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		chunks      []string
+		wantErr     bool
+		wantOutcome string
+		wantDeltas  int
+	}{
+		{
+			name:   "Success",
+			status: http.StatusOK,
+			chunks: []string{
+				`{"message":{"role":"assistant","content":"","thinking":"Outlaw country, so acoustic."},"done":false}`,
+				`{"message":{"role":"assistant","content":"{\"intent_type\":\"CREATE\","},"done":false}`,
+				`{"message":{"role":"assistant","content":"\"explanation\":\"Test\"}"},"done":false}`,
+				`{"message":{"role":"assistant","content":""},"done":true}`,
+			},
+			wantOutcome: "ok",
+			wantDeltas:  3,
+		},
+		{
+			name:        "Error mid-stream",
+			status:      http.StatusOK,
+			chunks:      []string{`{"message":{"role":"assistant","content":"{"},"done":false}`, `{"error":"model crashed"}`},
+			wantErr:     true,
+			wantOutcome: "status",
+			wantDeltas:  1,
+		},
+		{
+			name:        "Stream cut off",
+			status:      http.StatusOK,
+			chunks:      []string{`{"message":{"role":"assistant","content":"{"},"done":false}`},
+			wantErr:     true,
+			wantOutcome: "decode",
+			wantDeltas:  1,
+		},
+		{name: "Server error", status: http.StatusInternalServerError, chunks: []string{`{"error":"bad"}`}, wantErr: true, wantOutcome: "status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRequest chatRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&gotRequest); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/x-ndjson")
+				w.WriteHeader(tt.status)
+				for _, chunk := range tt.chunks {
+					_, _ = w.Write([]byte(chunk + "\n"))
+					w.(http.Flusher).Flush()
+				}
+			}))
+			defer srv.Close()
+
+			observedBefore := requestDuration.Count("/api/chat", tt.wantOutcome)
+
+			var deltas []domain.IntentDelta
+			intent, err := NewClient(srv.URL).AnalyzeIntentStream(context.Background(), "test message", func(d domain.IntentDelta) {
+				deltas = append(deltas, d)
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected err=%v, got %v", tt.wantErr, err)
+			}
+			if !gotRequest.Stream {
+				t.Fatal("expected a streaming request")
+			}
+			if got := requestDuration.Count("/api/chat", tt.wantOutcome) - observedBefore; got != 1 {
+				t.Fatalf("expected 1 latency observation with outcome %q, got %d", tt.wantOutcome, got)
+			}
+			if len(deltas) != tt.wantDeltas {
+				t.Fatalf("expected %d deltas, got %+v", tt.wantDeltas, deltas)
+			}
+			if tt.wantErr {
+				return
+			}
+			if deltas[0].Thinking == "" || deltas[0].Content != "" {
+				t.Fatalf("expected the first delta to carry thinking only, got %+v", deltas[0])
+			}
+			if intent.IntentType != "CREATE" || intent.Explanation != "Test" {
+				t.Fatalf("unexpected intent %+v", intent)
+			}
+		})
+	}
+}
+
+func TestClient_AnalyzeIntentTurn(t *testing.T) {
+	var gotRequest chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotRequest); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"{\"intent_type\":\"MODIFY\"}"}}`))
+	}))
+	defer srv.Close()
+
+	prior := domain.IntentObject{IntentType: "CREATE"}
+	prior.Entities.Genres = []string{"jazz"}
+	history := []domain.IntentTurn{{Message: "chill jazz", Intent: prior}}
+
+	intent, err := NewClient(srv.URL).AnalyzeIntentTurn(context.Background(), history, "make it more upbeat", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeIntentTurn: %v", err)
+	}
+	if intent.IntentType != "MODIFY" {
+		t.Errorf("got intent type %q, want MODIFY", intent.IntentType)
+	}
+	if gotRequest.Stream {
+		t.Error("expected a non-streaming request without onDelta")
+	}
+
+	msgs := gotRequest.Messages
+	if len(msgs) != 4 {
+		t.Fatalf("expected system, history and the new message, got %+v", msgs)
+	}
+	if msgs[1].Role != "user" || msgs[1].Content != "chill jazz" {
+		t.Errorf("expected the earlier message as a user turn, got %+v", msgs[1])
+	}
+	var replayed domain.IntentObject
+	if msgs[2].Role != "assistant" || json.Unmarshal([]byte(msgs[2].Content), &replayed) != nil || replayed.Entities.Genres[0] != "jazz" {
+		t.Errorf("expected the earlier intent as the assistant's reply, got %+v", msgs[2])
+	}
+	if msgs[3].Role != "user" || msgs[3].Content != "make it more upbeat" {
+		t.Errorf("expected the follow-up last, got %+v", msgs[3])
+	}
+}
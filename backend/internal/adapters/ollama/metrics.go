@@ -0,0 +1,18 @@
+package ollama
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var (
+	requestDuration = metrics.NewHistogramVec(
+		"overture_ollama_request_duration_seconds",
+		"Latency of Ollama API calls, by outcome (ok, network, status, decode).",
+		// Model reasoning takes seconds to minutes, well past the default buckets.
+		[]float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 90, 120},
+		"endpoint", "outcome",
+	)
+	requestFailures = metrics.NewCounterVec(
+		"overture_ollama_failures_total",
+		"Failed Ollama API calls, by cause (network, status, decode).",
+		"endpoint", "cause",
+	)
+)
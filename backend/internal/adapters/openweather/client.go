@@ -0,0 +1,129 @@
+// Package openweather provides a weather adapter for the OpenWeather
+// current weather API (https://openweathermap.org/current).
+package openweather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+)
+
+const defaultBaseURL = "https://api.openweathermap.org"
+
+// Client implements ports.WeatherProvider.
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client using apiKey against baseURL, or the public
+// OpenWeather API when it is empty.
+func NewClient(apiKey, baseURL string) *Client {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{apiKey: apiKey, baseURL: baseURL, httpClient: httpx.NewClient("openweather", 10*time.Second, httpx.DefaultPolicy())}
+}
+
+type weatherResponse struct {
+	Weather []struct {
+		Main        string `json:"main"`
+		Description string `json:"description"`
+	} `json:"weather"`
+	Main struct {
+		Temp float64 `json:"temp"`
+	} `json:"main"`
+	Dt  int64 `json:"dt"`
+	Sys struct {
+		Sunrise int64 `json:"sunrise"`
+		Sunset  int64 `json:"sunset"`
+	} `json:"sys"`
+}
+
+// CurrentWeather implements ports.WeatherProvider.
+func (c *Client) CurrentWeather(ctx context.Context, latitude, longitude float64) (domain.Weather, error) {
+	q := url.Values{}
+	q.Set("lat", strconv.FormatFloat(latitude, 'f', 4, 64))
+	q.Set("lon", strconv.FormatFloat(longitude, 'f', 4, 64))
+	q.Set("units", "metric")
+	q.Set("appid", c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/data/2.5/weather?"+q.Encode(), nil)
+	if err != nil {
+		return domain.Weather{}, fmt.Errorf("openweather: build request: %w", err)
+	}
+
+	start := time.Now()
+	parsed, outcome, err := c.get(req)
+	requestDuration.Observe(time.Since(start).Seconds(), outcome)
+	if err != nil {
+		return domain.Weather{}, err
+	}
+
+	w := domain.Weather{
+		TemperatureC: parsed.Main.Temp,
+		Daylight:     parsed.Dt >= parsed.Sys.Sunrise && parsed.Dt < parsed.Sys.Sunset,
+		ObservedAt:   time.Unix(parsed.Dt, 0).UTC(),
+	}
+	if len(parsed.Weather) > 0 {
+		w.Condition = condition(parsed.Weather[0].Main)
+		w.Description = parsed.Weather[0].Description
+	}
+	return w, nil
+}
+
+// condition maps OpenWeather's condition groups to domain conditions.
+// The "atmosphere" group (mist, haze, dust, ...) counts as fog.
+func condition(main string) string {
+	switch main {
+	case "Clear":
+		return domain.WeatherClear
+	case "Clouds":
+		return domain.WeatherClouds
+	case "Rain", "Drizzle":
+		return domain.WeatherRain
+	case "Thunderstorm":
+		return domain.WeatherStorm
+	case "Snow":
+		return domain.WeatherSnow
+	case "":
+		return ""
+	default:
+		return domain.WeatherFog
+	}
+}
+
+// get sends req and decodes the current conditions it returns. There is no
+// not-found answer for coordinates, so every non-2xx status is an error,
+// and network errors drop the request URL, which carries the API key.
+// outcome is ok, network, status or decode.
+func (c *Client) get(req *http.Request) (weatherResponse, string, error) {
+	resp, err := c.httpClient.Do(req) // #nosec G107,G704
+	if err != nil {
+		// The URL carries the API key; keep it out of the error.
+		if uerr, ok := err.(*url.Error); ok {
+			err = uerr.Err
+		}
+		return weatherResponse{}, "network", fmt.Errorf("openweather: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return weatherResponse{}, "status", fmt.Errorf("openweather: unexpected status %d", resp.StatusCode)
+	}
+
+	var parsed weatherResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return weatherResponse{}, "decode", fmt.Errorf("openweather: decode response: %w", err)
+	}
+	return parsed, "ok", nil
+}
@@ -0,0 +1,80 @@
+package openweather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestClient_CurrentWeather(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantErr   bool
+		wantCond  string
+		wantDay   bool
+		wantTempC float64
+	}{
+		{
+			name:      "Rainy evening",
+			status:    http.StatusOK,
+			body:      `{"weather":[{"main":"Drizzle","description":"light drizzle"}],"main":{"temp":11.5},"dt":1700060000,"sys":{"sunrise":1700000000,"sunset":1700040000}}`,
+			wantCond:  domain.WeatherRain,
+			wantTempC: 11.5,
+		},
+		{
+			name:      "Clear day",
+			status:    http.StatusOK,
+			body:      `{"weather":[{"main":"Clear","description":"clear sky"}],"main":{"temp":24},"dt":1700020000,"sys":{"sunrise":1700000000,"sunset":1700040000}}`,
+			wantCond:  domain.WeatherClear,
+			wantDay:   true,
+			wantTempC: 24,
+		},
+		{
+			name:      "Haze counts as fog",
+			status:    http.StatusOK,
+			body:      `{"weather":[{"main":"Haze"}],"main":{"temp":30},"dt":1700020000,"sys":{"sunrise":1700000000,"sunset":1700040000}}`,
+			wantCond:  domain.WeatherFog,
+			wantDay:   true,
+			wantTempC: 30,
+		},
+		{name: "Bad API key", status: http.StatusUnauthorized, body: `{"cod":401}`, wantErr: true},
+		{name: "Malformed response", status: http.StatusOK, body: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			weather, err := NewClient("key-123", srv.URL).CurrentWeather(context.Background(), 47.6062, -122.3321)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected err=%v, got %v", tt.wantErr, err)
+			}
+			q := got.URL.Query()
+			if got.URL.Path != "/data/2.5/weather" || q.Get("lat") != "47.6062" || q.Get("lon") != "-122.3321" ||
+				q.Get("appid") != "key-123" || q.Get("units") != "metric" {
+				t.Fatalf("unexpected request %s", got.URL)
+			}
+			if tt.wantErr {
+				if strings.Contains(err.Error(), "key-123") {
+					t.Fatalf("error leaks the API key: %v", err)
+				}
+				return
+			}
+			if weather.Condition != tt.wantCond || weather.Daylight != tt.wantDay || weather.TemperatureC != tt.wantTempC || weather.ObservedAt.IsZero() {
+				t.Fatalf("unexpected weather %+v", weather)
+			}
+		})
+	}
+}
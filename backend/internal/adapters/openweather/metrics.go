@@ -0,0 +1,10 @@
+package openweather
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var requestDuration = metrics.NewHistogramVec(
+	"overture_openweather_request_duration_seconds",
+	"Latency of OpenWeather API calls, by outcome (ok, network, status, decode).",
+	nil,
+	"outcome",
+)
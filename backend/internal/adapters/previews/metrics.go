@@ -0,0 +1,10 @@
+package previews
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var lookupDuration = metrics.NewHistogramVec(
+	"overture_preview_lookup_duration_seconds",
+	"Latency of fallback preview lookups, by source (deezer_isrc, itunes, deezer) and outcome (ok, not_found, error).",
+	nil,
+	"source", "outcome",
+)
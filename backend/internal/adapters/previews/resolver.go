@@ -0,0 +1,181 @@
+// Package previews resolves preview clips for tracks Spotify returns without
+// one, using the public iTunes Search and Deezer APIs.
+package previews
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+	"github.com/ewilliams-labs/overture/backend/internal/match"
+)
+
+const (
+	defaultITunesURL = "https://itunes.apple.com"
+	defaultDeezerURL = "https://api.deezer.com"
+)
+
+// Config overrides the API base URLs; empty fields use the public APIs.
+type Config struct {
+	ITunesURL string
+	DeezerURL string
+}
+
+// Resolver implements ports.PreviewResolver. It tries Deezer by ISRC first,
+// since that identifies the exact recording, then searches iTunes and
+// Deezer by title and artist.
+type Resolver struct {
+	itunesURL  string
+	deezerURL  string
+	httpClient *http.Client
+}
+
+// NewResolver returns a Resolver for cfg.
+func NewResolver(cfg Config) *Resolver {
+	return &Resolver{
+		itunesURL:  baseURL(cfg.ITunesURL, defaultITunesURL),
+		deezerURL:  baseURL(cfg.DeezerURL, defaultDeezerURL),
+		httpClient: httpx.NewClient("previews", 10*time.Second, httpx.DefaultPolicy()),
+	}
+}
+
+func baseURL(u, def string) string {
+	u = strings.TrimRight(u, "/")
+	if u == "" {
+		return def
+	}
+	return u
+}
+
+// lookup is one way of finding a preview; it returns "" when it has none.
+type lookup struct {
+	source string
+	find   func(ctx context.Context, track domain.Track) (string, error)
+}
+
+// ResolvePreview implements ports.PreviewResolver. A failing source is
+// skipped; the first error is returned only if no source has a preview.
+func (r *Resolver) ResolvePreview(ctx context.Context, track domain.Track) (string, error) {
+	lookups := []lookup{
+		{"deezer_isrc", r.deezerByISRC},
+		{"itunes", r.itunesSearch},
+		{"deezer", r.deezerSearch},
+	}
+	var firstErr error
+	for _, l := range lookups {
+		start := time.Now()
+		preview, err := l.find(ctx, track)
+		outcome := "ok"
+		switch {
+		case err != nil:
+			outcome = "error"
+			if firstErr == nil {
+				firstErr = err
+			}
+		case preview == "":
+			outcome = "not_found"
+		}
+		lookupDuration.Observe(time.Since(start).Seconds(), l.source, outcome)
+		if preview != "" {
+			return preview, nil
+		}
+	}
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return "", fmt.Errorf("previews: no preview for %q by %q: %w", track.Title, track.Artist, domain.ErrNotFound)
+}
+
+type deezerTrack struct {
+	Title   string `json:"title"`
+	Preview string `json:"preview"`
+	Artist  struct {
+		Name string `json:"name"`
+	} `json:"artist"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// deezerByISRC looks the recording up directly. Deezer answers unknown
+// ISRCs with 200 and an error object.
+func (r *Resolver) deezerByISRC(ctx context.Context, track domain.Track) (string, error) {
+	if track.ISRC == "" {
+		return "", nil
+	}
+	var body deezerTrack
+	if err := r.getJSON(ctx, r.deezerURL+"/track/isrc:"+url.PathEscape(track.ISRC), &body); err != nil {
+		return "", fmt.Errorf("previews: deezer isrc lookup: %w", err)
+	}
+	if body.Error != nil {
+		return "", nil
+	}
+	return body.Preview, nil
+}
+
+func (r *Resolver) deezerSearch(ctx context.Context, track domain.Track) (string, error) {
+	q := url.Values{}
+	q.Set("q", fmt.Sprintf("artist:%q track:%q", track.Artist, track.Title))
+	q.Set("limit", "5")
+	var body struct {
+		Data []deezerTrack `json:"data"`
+	}
+	if err := r.getJSON(ctx, r.deezerURL+"/search?"+q.Encode(), &body); err != nil {
+		return "", fmt.Errorf("previews: deezer search: %w", err)
+	}
+	for _, d := range body.Data {
+		if d.Preview != "" && match.Default.Matches(track.Title, track.Artist, d.Title, d.Artist.Name) {
+			return d.Preview, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *Resolver) itunesSearch(ctx context.Context, track domain.Track) (string, error) {
+	q := url.Values{}
+	q.Set("term", track.Artist+" "+track.Title)
+	q.Set("media", "music")
+	q.Set("entity", "song")
+	q.Set("limit", "5")
+	var body struct {
+		Results []struct {
+			TrackName  string `json:"trackName"`
+			ArtistName string `json:"artistName"`
+			PreviewURL string `json:"previewUrl"`
+		} `json:"results"`
+	}
+	if err := r.getJSON(ctx, r.itunesURL+"/search?"+q.Encode(), &body); err != nil {
+		return "", fmt.Errorf("previews: itunes search: %w", err)
+	}
+	for _, res := range body.Results {
+		if res.PreviewURL != "" && match.Default.Matches(track.Title, track.Artist, res.TrackName, res.ArtistName) {
+			return res.PreviewURL, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *Resolver) getJSON(ctx context.Context, rawURL string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	resp, err := r.httpClient.Do(req) // #nosec G107,G704 -- base URLs come from configuration
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package previews
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestResolver_ResolvePreview(t *testing.T) {
+	tests := []struct {
+		name         string
+		isrc         string
+		deezerISRC   string
+		itunes       string
+		itunesStatus int
+		deezer       string
+		want         string
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{
+			name:       "ISRC match wins",
+			isrc:       "USUM71703861",
+			deezerISRC: `{"title":"Redbone","preview":"https://cdn.deezer.com/isrc.mp3","artist":{"name":"Childish Gambino"}}`,
+			want:       "https://cdn.deezer.com/isrc.mp3",
+		},
+		{
+			name:       "unknown ISRC falls back to iTunes",
+			isrc:       "XX0000000000",
+			deezerISRC: `{"error":{"type":"DataException","message":"no data","code":800}}`,
+			itunes:     `{"results":[{"trackName":"Redbone","artistName":"Childish Gambino","previewUrl":"https://audio.itunes.com/redbone.m4a"}]}`,
+			want:       "https://audio.itunes.com/redbone.m4a",
+		},
+		{
+			name:   "iTunes cover is ignored in favor of Deezer",
+			itunes: `{"results":[{"trackName":"Redbone","artistName":"Karaoke Stars","previewUrl":"https://audio.itunes.com/cover.m4a"}]}`,
+			deezer: `{"data":[{"title":"Redbone (Remastered)","preview":"https://cdn.deezer.com/search.mp3","artist":{"name":"Childish Gambino"}}]}`,
+			want:   "https://cdn.deezer.com/search.mp3",
+		},
+		{
+			name:         "no source has one",
+			itunes:       `{"results":[]}`,
+			deezer:       `{"data":[]}`,
+			wantNotFound: true,
+			wantErr:      true,
+		},
+		{
+			name:         "source failure surfaces when nothing matches",
+			itunesStatus: http.StatusServiceUnavailable,
+			deezer:       `{"data":[]}`,
+			wantErr:      true,
+		},
+		{
+			name:         "source failure is skipped when another matches",
+			itunesStatus: http.StatusServiceUnavailable,
+			deezer:       `{"data":[{"title":"Redbone","preview":"https://cdn.deezer.com/search.mp3","artist":{"name":"Childish Gambino"}}]}`,
+			want:         "https://cdn.deezer.com/search.mp3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			itunes := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/search" || r.URL.Query().Get("term") != "Childish Gambino Redbone" {
+					t.Errorf("unexpected iTunes request %s", r.URL)
+				}
+				if tt.itunesStatus != 0 {
+					w.WriteHeader(tt.itunesStatus)
+				}
+				_, _ = w.Write([]byte(tt.itunes))
+			}))
+			defer itunes.Close()
+			deezer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/track/isrc:"+tt.isrc:
+					_, _ = w.Write([]byte(tt.deezerISRC))
+				case r.URL.Path == "/search":
+					if q := r.URL.Query().Get("q"); !strings.Contains(q, `artist:"Childish Gambino"`) || !strings.Contains(q, `track:"Redbone"`) {
+						t.Errorf("unexpected Deezer query %q", q)
+					}
+					_, _ = w.Write([]byte(tt.deezer))
+				default:
+					t.Errorf("unexpected Deezer request %s", r.URL)
+				}
+			}))
+			defer deezer.Close()
+
+			resolver := NewResolver(Config{ITunesURL: itunes.URL, DeezerURL: deezer.URL})
+			track := domain.Track{ID: "t1", Title: "Redbone", Artist: "Childish Gambino", ISRC: tt.isrc}
+			got, err := resolver.ResolvePreview(context.Background(), track)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected err=%v, got %v", tt.wantErr, err)
+			}
+			if errors.Is(err, domain.ErrNotFound) != tt.wantNotFound {
+				t.Fatalf("expected not found=%v, got %v", tt.wantNotFound, err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Package reporting provides implementations of the error reporter port.
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+)
+
+// Nop discards every report. It is the default when no DSN is configured.
+type Nop struct{}
+
+// Report implements ports.ErrorReporter.
+func (Nop) Report(context.Context, error, map[string]string) {}
+
+// Sentry sends reports to a Sentry-compatible ingestion endpoint (Sentry,
+// GlitchTip, ...) using the envelope protocol.
+type Sentry struct {
+	dsn         string
+	endpoint    string
+	auth        string
+	environment string
+	release     string
+	client      *http.Client
+	sem         chan struct{} // bounds in-flight sends
+}
+
+// NewSentry parses a DSN of the form https://<key>@<host>/<project>.
+func NewSentry(dsn, environment, release string) (*Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.Host == "" {
+		return nil, fmt.Errorf("reporting: invalid sentry DSN")
+	}
+	project := strings.Trim(u.Path, "/")
+	if project == "" {
+		return nil, fmt.Errorf("reporting: sentry DSN has no project ID")
+	}
+	endpoint := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/api/" + project + "/envelope/"}
+	return &Sentry{
+		dsn:         dsn,
+		endpoint:    endpoint.String(),
+		auth:        "Sentry sentry_version=7, sentry_client=overture/1.0, sentry_key=" + u.User.Username(),
+		environment: environment,
+		release:     release,
+		client:      httpx.NewClient("sentry", 5*time.Second, httpx.Policy{MaxAttempts: 2, BaseBackoff: 500 * time.Millisecond, MaxBackoff: 2 * time.Second}),
+		sem:         make(chan struct{}, 8),
+	}, nil
+}
+
+type sentryException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   float64           `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Exception   struct {
+		Values []sentryException `json:"values"`
+	} `json:"exception"`
+}
+
+// Report implements ports.ErrorReporter. The event is sent in the
+// background; when too many sends are in flight it is dropped and logged.
+func (s *Sentry) Report(ctx context.Context, err error, fields map[string]string) {
+	if err == nil {
+		return
+	}
+	event := s.event(err, fields)
+
+	select {
+	case s.sem <- struct{}{}:
+	default:
+		logging.Component(nil, "reporting").Warn("dropping error report, too many in flight", "event_id", event.EventID)
+		return
+	}
+	go func() {
+		defer func() { <-s.sem }()
+		if err := s.send(event); err != nil {
+			logging.Component(nil, "reporting").Warn("failed to send error report", "event_id", event.EventID, "error", err)
+		}
+	}()
+}
+
+func (s *Sentry) event(err error, fields map[string]string) sentryEvent {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	host, _ := os.Hostname()
+
+	event := sentryEvent{
+		EventID:     hex.EncodeToString(id[:]),
+		Timestamp:   float64(time.Now().UnixNano()) / 1e9,
+		Level:       "error",
+		Platform:    "go",
+		ServerName:  host,
+		Environment: s.environment,
+		Release:     s.release,
+		Tags:        fields,
+	}
+	// Report the chain innermost-last, the order Sentry expects.
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		event.Exception.Values = append([]sentryException{{
+			Type:  fmt.Sprintf("%T", e),
+			Value: e.Error(),
+		}}, event.Exception.Values...)
+	}
+	return event
+}
+
+func (s *Sentry) send(event sentryEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	header, _ := json.Marshal(map[string]string{"event_id": event.EventID, "dsn": s.dsn})
+	item, _ := json.Marshal(map[string]any{"type": "event", "length": len(payload)})
+
+	var body bytes.Buffer
+	body.Write(header)
+	body.WriteByte('\n')
+	body.Write(item)
+	body.WriteByte('\n')
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", s.auth)
+
+	resp, err := s.client.Do(req) // #nosec G107,G704 -- endpoint comes from operator-supplied DSN
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package reporting
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSentry(t *testing.T) {
+	tests := []struct {
+		name         string
+		dsn          string
+		wantErr      bool
+		wantEndpoint string
+	}{
+		{name: "valid", dsn: "https://abc@o1.ingest.sentry.io/42", wantEndpoint: "https://o1.ingest.sentry.io/api/42/envelope/"},
+		{name: "missing key", dsn: "https://o1.ingest.sentry.io/42", wantErr: true},
+		{name: "missing project", dsn: "https://abc@o1.ingest.sentry.io/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSentry(tt.dsn, "test", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSentry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.endpoint != tt.wantEndpoint {
+				t.Fatalf("endpoint: got %q, want %q", s.endpoint, tt.wantEndpoint)
+			}
+		})
+	}
+}
+
+func TestSentry_Report(t *testing.T) {
+	received := make(chan sentryEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("X-Sentry-Auth"), "sentry_key=public") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Envelope: header line, item header line, event payload.
+		sc := bufio.NewScanner(r.Body)
+		var lines []string
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		if len(lines) != 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var event sentryEvent
+		if err := json.Unmarshal([]byte(lines[2]), &event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- event
+	}))
+	defer srv.Close()
+
+	dsn := strings.Replace(srv.URL, "http://", "http://public@", 1) + "/7"
+	s, err := NewSentry(dsn, "test", "v1")
+	if err != nil {
+		t.Fatalf("new sentry: %v", err)
+	}
+
+	base := errors.New("connection refused")
+	s.Report(context.Background(), fmt.Errorf("service: failed to load playlist: %w", base), map[string]string{"playlist_id": "pl-1"})
+
+	select {
+	case event := <-received:
+		if event.Tags["playlist_id"] != "pl-1" || event.Environment != "test" || event.Release != "v1" {
+			t.Fatalf("unexpected event context: %+v", event)
+		}
+		if n := len(event.Exception.Values); n != 2 || event.Exception.Values[0].Value != "connection refused" {
+			t.Fatalf("unexpected exception chain: %+v", event.Exception.Values)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for report")
+	}
+}
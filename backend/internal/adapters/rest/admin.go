@@ -0,0 +1,233 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/prompt"
+)
+
+// requireAdmin rejects requests that don't carry the configured admin bearer
+// token. When no token is configured every request is rejected.
+func (h *Handler) requireAdmin(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if h.adminToken == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			writeError(w, http.StatusUnauthorized, "admin token required")
+			return
+		}
+		next(w, r)
+	})
+}
+
+// ListBackups handles GET /admin/backups.
+func (h *Handler) ListBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.backups.List(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, backups)
+}
+
+// CreateBackup handles POST /admin/backups by taking a snapshot immediately.
+func (h *Handler) CreateBackup(w http.ResponseWriter, r *http.Request) {
+	backup, err := h.backups.Create(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, backup)
+}
+
+// RunCleanup handles POST /admin/cleanup. With ?dry_run=true it only reports
+// what would be removed.
+func (h *Handler) RunCleanup(w http.ResponseWriter, r *http.Request) {
+	report, err := h.cleaner.Run(r.Context(), r.URL.Query().Get("dry_run") == "true")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// StartBackfill handles POST /admin/backfill. It starts a run in the
+// background and answers 202 with its status, or 200 with the progress of
+// the run already in progress.
+func (h *Handler) StartBackfill(w http.ResponseWriter, r *http.Request) {
+	report, started := h.backfill.Start(r.Context())
+	if !started {
+		writeJSON(w, http.StatusOK, report)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, report)
+}
+
+// BackfillStatus handles GET /admin/backfill, reporting the progress of the
+// current or most recent run.
+func (h *Handler) BackfillStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.backfill.Status())
+}
+
+// ListCaptures handles GET /admin/captures, newest first. ?limit caps the
+// number returned (default 50, max 500).
+func (h *Handler) ListCaptures(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(w, r, 50, 500)
+	if !ok {
+		return
+	}
+	captures, err := h.captures.ListCaptures(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, captures)
+}
+
+// GetCapture handles GET /admin/captures/{id}.
+func (h *Handler) GetCapture(w http.ResponseWriter, r *http.Request) {
+	capture, err := h.captures.GetCapture(r.Context(), r.PathValue("id"))
+	if errors.Is(err, domain.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "capture not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, capture)
+}
+
+// ReplayIntents handles POST /admin/replay. It replays the newest ?limit
+// (default 100, max 1000) recorded intent runs against the current matching
+// logic and reports how their selected tracks would differ.
+func (h *Handler) ReplayIntents(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasIntentRuns() {
+		writeError(w, http.StatusNotImplemented, "intent run recording not configured")
+		return
+	}
+	limit, ok := parseLimit(w, r, 100, 1000)
+	if !ok {
+		return
+	}
+	report, err := h.svc.ReplayIntentRuns(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// ExperimentReport handles GET /admin/experiments. It aggregates the newest
+// ?limit (default 1000, max 10000) recorded intent runs per experiment
+// variant, including how many added tracks were kept.
+func (h *Handler) ExperimentReport(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasIntentRuns() {
+		writeError(w, http.StatusNotImplemented, "intent run recording not configured")
+		return
+	}
+	limit, ok := parseLimit(w, r, 1000, 10000)
+	if !ok {
+		return
+	}
+	report, err := h.svc.ExperimentReport(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// IntentAnalytics handles GET /admin/analytics/intents. It aggregates the
+// newest ?limit (default 1000, max 10000) recorded intent runs into the most
+// requested artists and genres, average constraints, success rates and the
+// causes of runs that added nothing.
+func (h *Handler) IntentAnalytics(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasIntentRuns() {
+		writeError(w, http.StatusNotImplemented, "intent run recording not configured")
+		return
+	}
+	limit, ok := parseLimit(w, r, 1000, 10000)
+	if !ok {
+		return
+	}
+	analytics, err := h.svc.IntentAnalytics(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, analytics)
+}
+
+// ListFlags handles GET /admin/flags, reporting every feature flag and
+// whether it is enabled in this deployment.
+func (h *Handler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.flags.States())
+}
+
+// DebugConfig handles GET /debug/config, reporting the configuration the
+// server runs with. Secrets are redacted before WithDebugConfig.
+func (h *Handler) DebugConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.config)
+}
+
+// debugPromptResponse is a rendered system prompt.
+type debugPromptResponse struct {
+	Template  string   `json:"template"`
+	Templates []string `json:"templates"`
+	Prompt    string   `json:"prompt"`
+}
+
+// DebugPrompt handles GET /debug/prompt, rendering the system prompt the
+// intent compiler sends, or with ?template= another compiler's. With
+// ?playlist_id= it is the prompt sent for intents on that playlist.
+func (h *Handler) DebugPrompt(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("template")
+	if name == "" {
+		name = h.promptName
+	}
+	var summary *domain.PlaylistSummary
+	if playlistID := r.URL.Query().Get("playlist_id"); playlistID != "" {
+		playlist, err := h.svc.GetPlaylist(r.Context(), playlistID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		s := playlist.Summary()
+		summary = &s
+	}
+	rendered, err := h.prompts.Render(name, summary)
+	if err != nil {
+		if errors.Is(err, prompt.ErrUnknownTemplate) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, debugPromptResponse{Template: name, Templates: h.prompts.Names(), Prompt: rendered})
+}
+
+// parseLimit reads the optional ?limit query parameter, writing a 400 and
+// returning false when it is not an integer in [1, max].
+func parseLimit(w http.ResponseWriter, r *http.Request, def, max int) (int, bool) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > max {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", max))
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/worker"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type addAlbumRequest struct {
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+}
+
+// AddAlbum handles POST /playlists/{id}/albums, appending a whole album in
+// order and skipping tracks the playlist already has.
+func (h *Handler) AddAlbum(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasAlbums() {
+		writeError(w, http.StatusNotImplemented, "album provider not configured")
+		return
+	}
+
+	playlistID := r.PathValue("id")
+	if playlistID == "" {
+		writeError(w, http.StatusBadRequest, "playlist id is required")
+		return
+	}
+	var req addAlbumRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Title == "" || req.Artist == "" {
+		writeError(w, http.StatusBadRequest, "title and artist are required")
+		return
+	}
+
+	result, err := h.svc.AddAlbumToPlaylist(r.Context(), playlistID, req.Title, req.Artist)
+	if err != nil {
+		var matchErr *ports.NoConfidentMatchError
+		if errors.As(err, &matchErr) {
+			writeErrorWithCode(w, http.StatusUnprocessableEntity, matchErr.Error(), errCodeNoConfidentMatch)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrPlaylistFull) {
+			writeErrorWithCode(w, http.StatusConflict, "the playlist has reached its maximum number of tracks", errCodePlaylistFull)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if h.pool != nil {
+		for _, t := range result.Added {
+			h.pool.Submit(worker.Job{TrackID: t.ID, PreviewURL: t.PreviewURL, Trace: trace.SpanContextFromContext(r.Context())})
+		}
+	}
+
+	w.Header().Set("Location", "/playlists/"+playlistID)
+	writeJSON(w, http.StatusCreated, result)
+}
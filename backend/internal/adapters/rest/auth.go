@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type spotifyConnectedResponse struct {
+	Connected bool `json:"connected"`
+}
+
+// SpotifyLogin handles GET /auth/spotify/login, redirecting the user to
+// Spotify to connect their account.
+func (h *Handler) SpotifyLogin(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasSpotifyAuth() {
+		writeError(w, http.StatusNotImplemented, "spotify auth not configured")
+		return
+	}
+	authURL, err := h.svc.StartSpotifyLogin(r.Context(), domain.DefaultOwner)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// SpotifyCallback handles GET /auth/spotify/callback, where Spotify returns
+// the user with a code, or an error when they declined.
+func (h *Handler) SpotifyCallback(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasSpotifyAuth() {
+		writeError(w, http.StatusNotImplemented, "spotify auth not configured")
+		return
+	}
+	q := r.URL.Query()
+	if reason := q.Get("error"); reason != "" {
+		writeErrorWithCode(w, http.StatusForbidden, "spotify authorization failed: "+reason, "SPOTIFY_AUTH_DENIED")
+		return
+	}
+	if q.Get("state") == "" || q.Get("code") == "" {
+		writeError(w, http.StatusBadRequest, "state and code are required")
+		return
+	}
+
+	if err := h.svc.CompleteSpotifyLogin(r.Context(), q.Get("state"), q.Get("code")); err != nil {
+		if errors.Is(err, domain.ErrInvalidAuthState) {
+			writeErrorWithCode(w, http.StatusBadRequest, "login expired or already used; start again", "INVALID_AUTH_STATE")
+			return
+		}
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, spotifyConnectedResponse{Connected: true})
+}
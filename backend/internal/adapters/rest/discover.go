@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+const (
+	defaultDiscoverLimit = 20
+	maxDiscoverLimit     = 100
+)
+
+type discoverResponse struct {
+	Sort      domain.DiscoverSort     `json:"sort"`
+	Playlists []domain.PublicPlaylist `json:"playlists"`
+}
+
+type copyPlaylistRequest struct {
+	// Name defaults to the original playlist's.
+	Name string `json:"name"`
+}
+
+// Discover handles GET /discover, listing public playlists by ?sort of
+// recent (default), copied or trending.
+func (h *Handler) Discover(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasDiscovery() {
+		writeError(w, http.StatusNotImplemented, "discovery not configured")
+		return
+	}
+	sort, err := domain.ParseDiscoverSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "sort must be one of recent, copied, trending")
+		return
+	}
+	limit, ok := parseLimit(w, r, defaultDiscoverLimit, maxDiscoverLimit)
+	if !ok {
+		return
+	}
+
+	playlists, err := h.svc.Discover(r.Context(), sort, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if playlists == nil {
+		playlists = []domain.PublicPlaylist{}
+	}
+	writeJSON(w, http.StatusOK, discoverResponse{Sort: sort, Playlists: playlists})
+}
+
+// PublishPlaylist handles PUT /playlists/{id}/public, opting the playlist
+// into discovery.
+func (h *Handler) PublishPlaylist(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasDiscovery() {
+		writeError(w, http.StatusNotImplemented, "discovery not configured")
+		return
+	}
+	public, err := h.svc.PublishPlaylist(r.Context(), r.PathValue("id"), domain.DefaultOwner)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "playlist not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, public)
+}
+
+// UnpublishPlaylist handles DELETE /playlists/{id}/public.
+func (h *Handler) UnpublishPlaylist(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasDiscovery() {
+		writeError(w, http.StatusNotImplemented, "discovery not configured")
+		return
+	}
+	if err := h.svc.UnpublishPlaylist(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CopyPlaylist handles POST /playlists/{id}/copy, copying a public
+// playlist into the caller's library.
+func (h *Handler) CopyPlaylist(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasDiscovery() {
+		writeError(w, http.StatusNotImplemented, "discovery not configured")
+		return
+	}
+	var req copyPlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	playlist, err := h.svc.CopyPlaylist(r.Context(), r.PathValue("id"), req.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "public playlist not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Location", "/playlists/"+playlist.ID)
+	writeJSON(w, http.StatusCreated, playlist)
+}
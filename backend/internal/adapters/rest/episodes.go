@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type addEpisodeRequest struct {
+	Query string `json:"query"`
+	// Latest picks the most recently published match, e.g. today's news
+	// briefing, instead of the most relevant one.
+	Latest bool `json:"latest"`
+}
+
+// SearchEpisodes handles GET /episodes?q=...&limit=... (default 10, max 50).
+func (h *Handler) SearchEpisodes(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasPodcasts() {
+		writeError(w, http.StatusNotImplemented, "podcast provider not configured")
+		return
+	}
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	limit, ok := parseLimit(w, r, 10, 50)
+	if !ok {
+		return
+	}
+
+	episodes, err := h.svc.SearchEpisodes(r.Context(), query, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, episodes)
+}
+
+// AddEpisode handles POST /playlists/{id}/episodes, adding the best episode
+// matching the query that is not already in the playlist.
+func (h *Handler) AddEpisode(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasPodcasts() {
+		writeError(w, http.StatusNotImplemented, "podcast provider not configured")
+		return
+	}
+
+	playlistID := r.PathValue("id")
+	if playlistID == "" {
+		writeError(w, http.StatusBadRequest, "playlist id is required")
+		return
+	}
+	var req addEpisodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Query == "" {
+		writeError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	// Episodes are not queued for preview analysis: speech has no
+	// meaningful audio features.
+	episode, err := h.svc.AddEpisodeToPlaylist(r.Context(), playlistID, req.Query, req.Latest)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrPlaylistFull) {
+			writeErrorWithCode(w, http.StatusConflict, "the playlist has reached its maximum number of tracks", errCodePlaylistFull)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Location", "/playlists/"+playlistID)
+	writeJSON(w, http.StatusCreated, episode)
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// eventsHeartbeat keeps idle event feeds from being closed by proxies.
+const eventsHeartbeat = 15 * time.Second
+
+// PlaylistEvents handles GET /playlists/{id}/events, a Server-Sent Events
+// feed of the playlist's events: its own (tracks added, reordered, playback)
+// and track.features_updated for the tracks it contains, so clients need
+// not poll for analysis results. Each event is sent with its type as the
+// SSE event name and the domain.Event as data; clients deduplicate by ID.
+func (h *Handler) PlaylistEvents(w http.ResponseWriter, r *http.Request) {
+	playlistID := r.PathValue("id")
+
+	// Subscribe before loading the playlist so no track added in between
+	// goes unnoticed.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	feed := h.events.Subscribe(ctx)
+
+	playlist, err := h.svc.GetPlaylist(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	tracks := make(map[string]bool, len(playlist.Tracks))
+	for _, t := range playlist.Tracks {
+		tracks[t.ID] = true
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	rc := http.NewResponseController(w)
+	// The feed stays open far longer than any write timeout.
+	_ = rc.SetWriteDeadline(time.Time{})
+	if err := writeSSEEvent(w, rc, "status", sseStatus{Status: "subscribed"}); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(eventsHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := writeSSEEvent(w, rc, "status", sseStatus{Status: "heartbeat"}); err != nil {
+				return
+			}
+		case event, ok := <-feed:
+			if !ok {
+				return
+			}
+			if !concernsPlaylist(event, playlistID, tracks) {
+				continue
+			}
+			if err := writeSSEEvent(w, rc, event.Type, event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// concernsPlaylist reports whether event belongs in the playlist's feed,
+// keeping tracks up to date as tracks are added.
+func concernsPlaylist(event domain.Event, playlistID string, tracks map[string]bool) bool {
+	switch {
+	case event.Type == domain.EventFeaturesUpdated:
+		var payload domain.FeaturesUpdatedPayload
+		_ = json.Unmarshal(event.Payload, &payload)
+		return tracks[payload.TrackID]
+	case event.Type == domain.EventAnalysisFailed:
+		var payload domain.AnalysisFailedPayload
+		_ = json.Unmarshal(event.Payload, &payload)
+		return tracks[payload.TrackID]
+	case event.PlaylistID != playlistID:
+		return false
+	case event.Type == domain.EventTracksAdded:
+		var payload domain.TracksAddedPayload
+		_ = json.Unmarshal(event.Payload, &payload)
+		for _, id := range payload.TrackIDs {
+			tracks[id] = true
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/worker"
+)
+
+type exportStatusResponse struct {
+	Status worker.ExportStatus `json:"status"`
+}
+
+// ExportData handles GET /me/export. The archive is built on the worker pool:
+// the first request (or one with ?refresh=true) queues a build and returns
+// 202 until the archive is ready, after which it is downloaded directly. A
+// request after a failed build queues another.
+func (h *Handler) ExportData(w http.ResponseWriter, r *http.Request) {
+	status, err := h.exports.Status(r.Context())
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		h.logger.ErrorContext(r.Context(), "export status failed", "error", err)
+		writeError(w, http.StatusServiceUnavailable, "export unavailable, try again later")
+		return
+	}
+	if r.URL.Query().Get("refresh") == "true" || err != nil || status == worker.ExportFailed {
+		if status, err = h.exports.Request(r.Context()); err != nil {
+			h.logger.ErrorContext(r.Context(), "export request failed", "error", err)
+			writeError(w, http.StatusServiceUnavailable, "export unavailable, try again later")
+			return
+		}
+	}
+
+	if status == worker.ExportPending {
+		w.Header().Set("Retry-After", "5")
+		writeJSON(w, http.StatusAccepted, exportStatusResponse{Status: status})
+		return
+	}
+	archive, err := h.exports.Open(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "export open failed", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to open export")
+		return
+	}
+	defer archive.Close()
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="overture-export.zip"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, archive)
+}
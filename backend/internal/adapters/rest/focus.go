@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type focusStatus struct {
+	Active bool                  `json:"active"`
+	Block  *domain.CalendarEvent `json:"block,omitempty"`
+}
+
+type triggerFocusRequest struct {
+	Message string `json:"message"`
+	// Force applies the focus bias even outside a focus block.
+	Force bool `json:"force"`
+}
+
+// GetFocus handles GET /focus, reporting whether a focus block is in
+// progress.
+func (h *Handler) GetFocus(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasFocus() {
+		writeError(w, http.StatusNotImplemented, "focus mode not configured")
+		return
+	}
+	block, ok, err := h.svc.CurrentFocusBlock(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	status := focusStatus{Active: ok}
+	if ok {
+		status.Block = &block
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// TriggerFocus handles POST /playlists/{id}/focus, the focus automation:
+// during a focus block it adds tracks for message with the focus bias
+// applied. Outside one it does nothing and returns 409, so it is safe to
+// fire on a schedule.
+func (h *Handler) TriggerFocus(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasFocus() || !h.svc.HasIntentCompiler() {
+		writeError(w, http.StatusNotImplemented, "focus mode not configured")
+		return
+	}
+	var req triggerFocusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Message == "" {
+		writeError(w, http.StatusBadRequest, "message is required")
+		return
+	}
+
+	result, err := h.svc.TriggerFocus(r.Context(), r.PathValue("id"), req.Message, req.Force)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNoFocusBlock):
+			writeErrorWithCode(w, http.StatusConflict, "no focus block in progress", "NO_FOCUS_BLOCK")
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, "playlist not found")
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, newSSEComplete(result))
+}
@@ -4,27 +4,165 @@ package rest
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"mime"
 	"net/http"
+	"runtime/debug"
+	"strings"
 
-	"github.com/ewilliams-labs/overture/backend/internal/core/services"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/flags"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/backend/internal/prompt"
 	"github.com/ewilliams-labs/overture/backend/internal/worker"
 )
 
+// MarketHeader names the header a request sets to look tracks up in
+// another market than the configured one, as a two-letter country code.
+const MarketHeader = "X-Market"
+
 // Handler manages the HTTP interface for our application.
 type Handler struct {
-	svc    *services.Orchestrator // Dependency on the Core Service
+	svc    ports.PlaylistService // Dependency on the Core Service
 	pool   *worker.Pool
 	router *http.ServeMux // Standard library router
+
+	adminToken string
+	backups    *worker.Backups
+	exports    *worker.Exports
+	cleaner    *worker.Cleaner
+	backfill   *worker.Backfiller
+	reporter   ports.ErrorReporter
+	captures   ports.CaptureStore
+	jobs       ports.JobQueue
+	events     ports.EventSubscriber
+	flags      *flags.Set
+	config     any
+	prompts    *prompt.Set
+	promptName string
+	logger     *slog.Logger
+
+	rateLimit     RateLimit
+	apiKeys       map[string]bool
+	limiter       *limiter
+	intentLimiter *limiter
+}
+
+// Option configures optional Handler features.
+type Option func(*Handler)
+
+// WithAdminToken enables the /admin endpoints for requests that present token
+// as a bearer credential. Without it, admin endpoints reject every request.
+func WithAdminToken(token string) Option {
+	return func(h *Handler) {
+		h.adminToken = token
+	}
+}
+
+// WithBackups exposes on-demand database snapshots under /admin/backups.
+func WithBackups(backups *worker.Backups) Option {
+	return func(h *Handler) {
+		h.backups = backups
+	}
+}
+
+// WithExports enables GET /me/export.
+func WithExports(exports *worker.Exports) Option {
+	return func(h *Handler) {
+		h.exports = exports
+	}
+}
+
+// WithCleaner exposes on-demand cleanup runs under /admin/cleanup.
+func WithCleaner(cleaner *worker.Cleaner) Option {
+	return func(h *Handler) {
+		h.cleaner = cleaner
+	}
+}
+
+// WithBackfill exposes analysis backfill runs and their progress under
+// /admin/backfill.
+func WithBackfill(backfill *worker.Backfiller) Option {
+	return func(h *Handler) {
+		h.backfill = backfill
+	}
+}
+
+// WithCaptures exposes recorded intent compiler exchanges under
+// /admin/captures.
+func WithCaptures(store ports.CaptureStore) Option {
+	return func(h *Handler) {
+		h.captures = store
+	}
+}
+
+// WithJobs exposes the progress of persisted analysis jobs under /jobs and
+// /playlists/{id}/jobs.
+func WithJobs(queue ports.JobQueue) Option {
+	return func(h *Handler) {
+		h.jobs = queue
+	}
+}
+
+// WithEvents streams playlist changes and finished track analysis to
+// clients at /playlists/{id}/events.
+func WithEvents(sub ports.EventSubscriber) Option {
+	return func(h *Handler) {
+		h.events = sub
+	}
+}
+
+// WithFlags exposes the deployment's feature flags under /admin/flags.
+func WithFlags(set *flags.Set) Option {
+	return func(h *Handler) {
+		h.flags = set
+	}
+}
+
+// WithDebugConfig exposes cfg, the deployment's configuration with its
+// secrets already redacted, under /debug/config.
+func WithDebugConfig(cfg any) Option {
+	return func(h *Handler) {
+		h.config = cfg
+	}
+}
+
+// WithPrompts exposes the intent compiler's system prompt, as rendered
+// from prompts, under /debug/prompt. name is the template of the
+// configured compiler.
+func WithPrompts(prompts *prompt.Set, name string) Option {
+	return func(h *Handler) {
+		h.prompts = prompts
+		h.promptName = name
+	}
+}
+
+// WithErrorReporter reports recovered panics with request context.
+func WithErrorReporter(reporter ports.ErrorReporter) Option {
+	return func(h *Handler) {
+		h.reporter = reporter
+	}
+}
+
+// WithLogger logs through l instead of the default logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(h *Handler) {
+		h.logger = l
+	}
 }
 
 // NewHandler initializes the HTTP adapter and sets up routes.
-func NewHandler(svc *services.Orchestrator, pool *worker.Pool) *Handler {
+func NewHandler(svc ports.PlaylistService, pool *worker.Pool, opts ...Option) *Handler {
 	h := &Handler{
 		svc:    svc,
 		pool:   pool,
 		router: http.NewServeMux(),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.logger = logging.Component(h.logger, "rest")
 
 	// Register Routes
 	h.routes()
@@ -33,21 +171,139 @@ func NewHandler(svc *services.Orchestrator, pool *worker.Pool) *Handler {
 }
 
 // ServeHTTP satisfies the http.Handler interface.
-// It acts as a proxy, passing the request to our internal router.
+// It acts as a proxy, passing the request to our internal router, and turns
+// handler panics into 500 responses instead of dropped connections.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			h.recovered(r, rec)
+			writeError(w, http.StatusInternalServerError, "internal server error")
+		}
+	}()
+	if r.URL.Path != "/health" && !h.limit(h.limiter, w, r) {
+		return
+	}
+	if market := r.Header.Get(MarketHeader); market != "" {
+		market = strings.ToUpper(strings.TrimSpace(market))
+		if !domain.ValidMarket(market) {
+			writeError(w, http.StatusBadRequest, MarketHeader+" must be a two-letter country code such as US")
+			return
+		}
+		r = r.WithContext(ports.WithMarket(r.Context(), market))
+	}
 	h.router.ServeHTTP(w, r)
 }
 
+// recovered logs and reports a panic raised while serving r.
+func (h *Handler) recovered(r *http.Request, rec any) {
+	err := fmt.Errorf("panic: %v", rec)
+	h.logger.ErrorContext(r.Context(), "handler panicked", "method", r.Method, "path", r.URL.Path, "error", err, "stack", string(debug.Stack()))
+	if h.reporter != nil {
+		h.reporter.Report(r.Context(), err, map[string]string{
+			"http.method": r.Method,
+			"http.path":   r.URL.Path,
+		})
+	}
+}
+
 // routes defines the mapping between URLs and methods.
 func (h *Handler) routes() {
 	// Health Check
 	h.router.HandleFunc("GET /health", h.HealthCheck)
 	// Playlist Management
 	h.router.HandleFunc("POST /playlists", h.CreatePlaylist)
+	h.router.HandleFunc("POST /playlists/import", h.ImportPlaylist)
 	h.router.HandleFunc("GET /playlists/{id}", h.GetPlaylist)
 	h.router.HandleFunc("POST /playlists/{id}/tracks", h.AddTrack)
+	h.router.HandleFunc("PUT /playlists/{id}/tracks/order", h.ReorderTracks)
+	h.router.HandleFunc("GET /playlists/{id}/settings", h.GetPlaylistSettings)
+	h.router.HandleFunc("PATCH /playlists/{id}/settings", h.UpdatePlaylistSettings)
+	h.router.HandleFunc("GET /playlists/{id}/revisions", h.ListRevisions)
+	h.router.HandleFunc("POST /playlists/{id}/revert/{rev}", h.RevertPlaylist)
+	h.router.HandleFunc("GET /playlists/{id}/export", h.ExportPlaylist)
+	h.router.HandleFunc("POST /playlists/{id}/sync", h.SyncPlaylist)
 	h.router.HandleFunc("GET /playlists/{id}/analysis", h.GetPlaylistAnalysis)
-	h.router.HandleFunc("POST /playlists/{id}/intent", h.AnalyzeIntent)
+	h.router.HandleFunc("GET /playlists/{id}/similar", h.GetSimilarPlaylists)
+	h.router.HandleFunc("GET /playlists/{id}/arc", h.GetPlaylistArc)
+	h.router.HandleFunc("GET /playlists/{id}/recommendations", h.GetRecommendations)
+	h.router.HandleFunc("POST /playlists/{id}/intent", h.limitIntent(h.AnalyzeIntent))
+	h.router.HandleFunc("POST /playlists/{id}/templates/running", h.GenerateRunningPlaylist)
+	h.router.HandleFunc("POST /playlists/{id}/albums", h.AddAlbum)
+	h.router.HandleFunc("POST /playlists/{id}/episodes", h.AddEpisode)
+	h.router.HandleFunc("GET /playlists/{id}/playback", h.GetPlayback)
+	h.router.HandleFunc("PUT /playlists/{id}/playback", h.UpdatePlayback)
+	// Playback control on the user's active device
+	h.router.HandleFunc("POST /playlists/{id}/play", h.PlayPlaylist)
+	h.router.HandleFunc("POST /playlists/{id}/queue", h.QueueTrack)
+	h.router.HandleFunc("POST /player/pause", h.PausePlayback)
+	h.router.HandleFunc("POST /player/resume", h.ResumePlayback)
+	h.router.HandleFunc("POST /player/next", h.SkipTrack)
+	// Up-next queue, separate from playlists
+	h.router.HandleFunc("GET /queue", h.GetQueue)
+	h.router.HandleFunc("POST /queue", h.AddToQueue)
+	h.router.HandleFunc("POST /queue/pop", h.PopQueue)
+	h.router.HandleFunc("DELETE /queue", h.ClearQueue)
+	// Per-user settings
+	h.router.HandleFunc("GET /settings/weather", h.GetWeatherSettings)
+	h.router.HandleFunc("PUT /settings/weather", h.UpdateWeatherSettings)
+	h.router.HandleFunc("GET /me/mood", h.GetMoodHistory)
+	h.router.HandleFunc("POST /me/mood", h.RecordMood)
+	// Focus mode
+	h.router.HandleFunc("GET /focus", h.GetFocus)
+	h.router.HandleFunc("POST /playlists/{id}/focus", h.TriggerFocus)
+	// Public playlists
+	h.router.HandleFunc("GET /discover", h.Discover)
+	h.router.HandleFunc("PUT /playlists/{id}/public", h.PublishPlaylist)
+	h.router.HandleFunc("DELETE /playlists/{id}/public", h.UnpublishPlaylist)
+	h.router.HandleFunc("POST /playlists/{id}/copy", h.CopyPlaylist)
+	h.router.HandleFunc("GET /auth/spotify/login", h.SpotifyLogin)
+	h.router.HandleFunc("GET /auth/spotify/callback", h.SpotifyCallback)
+	h.router.HandleFunc("GET /tracks", h.ListTracks)
+	h.router.HandleFunc("GET /tracks/{id}", h.GetTrack)
+	h.router.HandleFunc("GET /tracks/{id}/lyrics", h.GetTrackLyrics)
+	h.router.HandleFunc("GET /tracks/{id}/also-added", h.GetAlsoAdded)
+	h.router.HandleFunc("GET /episodes", h.SearchEpisodes)
+	// Analysis progress
+	if h.jobs != nil {
+		h.router.HandleFunc("GET /jobs/{id}", h.GetJob)
+		h.router.HandleFunc("GET /playlists/{id}/jobs", h.GetPlaylistJobs)
+	}
+	if h.events != nil {
+		h.router.HandleFunc("GET /playlists/{id}/events", h.PlaylistEvents)
+	}
+	// Data portability
+	if h.exports != nil {
+		h.router.HandleFunc("GET /me/export", h.ExportData)
+	}
+	// Administration
+	if h.backups != nil {
+		h.router.Handle("GET /admin/backups", h.requireAdmin(h.ListBackups))
+		h.router.Handle("POST /admin/backups", h.requireAdmin(h.CreateBackup))
+	}
+	if h.cleaner != nil {
+		h.router.Handle("POST /admin/cleanup", h.requireAdmin(h.RunCleanup))
+	}
+	if h.backfill != nil {
+		h.router.Handle("GET /admin/backfill", h.requireAdmin(h.BackfillStatus))
+		h.router.Handle("POST /admin/backfill", h.requireAdmin(h.StartBackfill))
+	}
+	h.router.Handle("POST /admin/replay", h.requireAdmin(h.ReplayIntents))
+	h.router.Handle("GET /admin/experiments", h.requireAdmin(h.ExperimentReport))
+	h.router.Handle("GET /admin/analytics/intents", h.requireAdmin(h.IntentAnalytics))
+	if h.captures != nil {
+		h.router.Handle("GET /admin/captures", h.requireAdmin(h.ListCaptures))
+		h.router.Handle("GET /admin/captures/{id}", h.requireAdmin(h.GetCapture))
+	}
+	h.router.Handle("GET /admin/flags", h.requireAdmin(h.ListFlags))
+	if h.config != nil {
+		h.router.Handle("GET /debug/config", h.requireAdmin(h.DebugConfig))
+	}
+	if h.prompts != nil {
+		h.router.Handle("GET /debug/prompt", h.requireAdmin(h.DebugPrompt))
+	}
 }
 
 // HealthCheck is a simple endpoint to verify the API is running.
@@ -1,42 +1,50 @@
 package rest
 
 import (
+	"archive/zip"
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/blob"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/events"
 	"github.com/ewilliams-labs/overture/backend/internal/adapters/sqlite"
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
 	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
 	"github.com/ewilliams-labs/overture/backend/internal/core/services"
+	"github.com/ewilliams-labs/overture/backend/internal/flags"
+	"github.com/ewilliams-labs/overture/backend/internal/prompt"
 	"github.com/ewilliams-labs/overture/backend/internal/worker"
 )
 
 // --- Mocks ---
 
-// MockService satisfies the Orchestrator logic needed by the Handler.
-// Note: In a real integration test, we might mock the ports, but here we mock the Service struct methods directly
-// if we were using an interface. Since Orchestrator is a struct, we technically can't "mock" it easily
-// without an interface.
-//
-// However, since we are injecting the *Service* into the Handler, and the Service is a concrete struct,
-// unit testing the Handler in isolation is hard without mocking the *dependencies* of the Service.
-//
-// BUT, for this test to work with your current architecture (Handler -> *Service),
-// we actually need to create a REAL Service with MOCK Adapters.
+// Most tests below build a real services.Orchestrator over mock adapters,
+// which also covers the service logic behind each endpoint. Handler-only
+// behavior can instead be tested against fakeService, which implements
+// ports.PlaylistService directly.
 
 type mockSpotify struct {
 	err   error
 	track domain.Track
+	// market is the market of the last lookup.
+	market string
 }
 
 func (m *mockSpotify) GetTrackByMetadata(ctx context.Context, title, artist string) (domain.Track, error) {
+	m.market = ports.MarketFrom(ctx)
 	if m.err != nil {
 		return domain.Track{}, m.err
 	}
@@ -63,6 +71,7 @@ func (m *mockSpotify) GetArtistTopTracks(ctx context.Context, artistName string)
 
 type mockRepo struct {
 	shouldFailSave bool
+	saveErr        error
 	getErr         error
 	playlist       domain.Playlist
 	audioErr       error
@@ -79,11 +88,21 @@ func (m *mockRepo) GetByID(ctx context.Context, id string) (domain.Playlist, err
 	return domain.Playlist{ID: id, Name: "Test Playlist", Tracks: []domain.Track{}}, nil
 }
 
+func (m *mockRepo) ListPlaylists(ctx context.Context) ([]domain.Playlist, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	if m.playlist.ID != "" {
+		return []domain.Playlist{m.playlist}, nil
+	}
+	return []domain.Playlist{}, nil
+}
+
 func (m *mockRepo) Save(ctx context.Context, p domain.Playlist) error {
 	if m.shouldFailSave {
 		return errors.New("db error")
 	}
-	return nil
+	return m.saveErr
 }
 
 func (m *mockRepo) GetPlaylistAudioFeatures(ctx context.Context, playlistID string) (domain.AudioFeatures, error) {
@@ -104,6 +123,13 @@ func (m *mockRepo) AddTracksToPlaylist(ctx context.Context, playlistID string, t
 	return nil
 }
 
+func (m *mockRepo) ReorderTracks(ctx context.Context, playlistID string, trackIDs []string) error {
+	if m.shouldFailSave {
+		return errors.New("db error")
+	}
+	return nil
+}
+
 type mockIntentCompiler struct {
 	intent        domain.IntentObject
 	err           error
@@ -120,6 +146,43 @@ func (m *mockIntentCompiler) AnalyzeIntent(ctx context.Context, message string)
 	return m.intent, nil
 }
 
+// stubSimilarArtists suggests the artists listed for each name.
+type stubSimilarArtists map[string][]string
+
+func (s stubSimilarArtists) GetSimilarArtists(ctx context.Context, artist string, limit int) ([]string, error) {
+	names, ok := s[artist]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return names[:min(limit, len(names))], nil
+}
+
+// streamingIntentCompiler streams its intent in the given pieces.
+type streamingIntentCompiler struct {
+	mockIntentCompiler
+	deltas []domain.IntentDelta
+}
+
+func (m *streamingIntentCompiler) AnalyzeIntentStream(ctx context.Context, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	for _, d := range m.deltas {
+		onDelta(d)
+	}
+	return m.AnalyzeIntent(ctx, message)
+}
+
+// staticRecordings maps track IDs to recording IDs.
+type staticRecordings map[string]string
+
+func (s staticRecordings) RecordingIDs(ctx context.Context, trackIDs []string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, id := range trackIDs {
+		if rec, ok := s[id]; ok {
+			out[id] = rec
+		}
+	}
+	return out, nil
+}
+
 // --- Tests ---
 
 func TestHandler_AddTrack(t *testing.T) {
@@ -128,6 +191,8 @@ func TestHandler_AddTrack(t *testing.T) {
 		body           map[string]string // Use map to control JSON keys explicitly
 		spotifyErr     error
 		mockRepoFail   bool
+		saveErr        error
+		recordings     staticRecordings
 		expectedStatus int
 		expectedBody   string
 	}{
@@ -161,6 +226,36 @@ func TestHandler_AddTrack(t *testing.T) {
 			expectedStatus: http.StatusUnprocessableEntity,
 			expectedBody:   "\"code\":\"NO_CONFIDENT_MATCH\"",
 		},
+		{
+			name: "Conflict: same recording already in playlist",
+			body: map[string]string{
+				"title":  "Song One",
+				"artist": "Artist A",
+			},
+			recordings:     staticRecordings{"t0": "rec-1", "t1": "rec-1"},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "\"code\":\"DUPLICATE_TRACK\"",
+		},
+		{
+			name: "Unavailable: spotify breaker open",
+			body: map[string]string{
+				"title":  "Song One",
+				"artist": "Artist A",
+			},
+			spotifyErr:     ports.ProviderUnavailableError{Provider: "spotify", RetryAfter: 30 * time.Second},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "\"code\":\"PROVIDER_UNAVAILABLE\"",
+		},
+		{
+			name: "Conflict: playlist changed concurrently",
+			body: map[string]string{
+				"title":  "Song One",
+				"artist": "Artist A",
+			},
+			saveErr:        domain.ErrVersionConflict,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "\"code\":\"VERSION_CONFLICT\"",
+		},
 		{
 			name: "Service Error: orchestrator returns error -> StatusInternalServerError",
 			body: map[string]string{
@@ -178,8 +273,13 @@ func TestHandler_AddTrack(t *testing.T) {
 			// 1. Setup Dependencies
 			// Since Handler depends on concrete *Orchestrator, we build a real one with mock adapters
 			spotify := &mockSpotify{err: tt.spotifyErr}
-			repo := &mockRepo{shouldFailSave: tt.mockRepoFail}
-			svc := services.NewOrchestrator(spotify, repo, nil)
+			repo := &mockRepo{shouldFailSave: tt.mockRepoFail, saveErr: tt.saveErr}
+			var opts []services.Option
+			if tt.recordings != nil {
+				repo.playlist = domain.Playlist{ID: "p1", Name: "Test Playlist", Tracks: []domain.Track{{ID: "t0"}}}
+				opts = append(opts, services.WithRecordings(tt.recordings))
+			}
+			svc := services.NewOrchestrator(spotify, repo, nil, opts...)
 
 			// 2. Setup Handler
 			h := NewHandler(svc, nil)
@@ -352,6 +452,65 @@ func TestHandler_GetPlaylist(t *testing.T) {
 	}
 }
 
+func TestHandler_ReorderTracks(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	tracks := []domain.Track{{ID: "a1", Title: "One", Artist: "A"}, {ID: "a2", Title: "Two", Artist: "A"}, {ID: "a3", Title: "Three", Artist: "A"}}
+	if err := store.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: tracks}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+
+	// Cases run in order against the same store.
+	tests := []struct {
+		name       string
+		playlistID string
+		body       string
+		wantStatus int
+		wantOrder  string
+	}{
+		{name: "invalid body", playlistID: "pl-1", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "unknown playlist", playlistID: "missing", body: `{"track_ids":["a1"]}`, wantStatus: http.StatusNotFound},
+		{name: "missing track", playlistID: "pl-1", body: `{"track_ids":["a2","a1"]}`, wantStatus: http.StatusUnprocessableEntity, wantOrder: "a1,a2,a3"},
+		{name: "unknown track", playlistID: "pl-1", body: `{"track_ids":["a2","a1","x"]}`, wantStatus: http.StatusUnprocessableEntity, wantOrder: "a1,a2,a3"},
+		{name: "reorders", playlistID: "pl-1", body: `{"track_ids":["a3","a1","a2"]}`, wantStatus: http.StatusOK, wantOrder: "a3,a1,a2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(services.NewOrchestrator(&mockSpotify{}, store, nil), nil)
+
+			req := httptest.NewRequest(http.MethodPut, "/playlists/"+tt.playlistID+"/tracks/order", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantOrder == "" {
+				return
+			}
+			// The stored order is what later reads return.
+			rec = httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/playlists/pl-1", nil))
+			var pl domain.Playlist
+			if err := json.NewDecoder(rec.Body).Decode(&pl); err != nil {
+				t.Fatalf("decode playlist: %v", err)
+			}
+			ids := make([]string, len(pl.Tracks))
+			for i, tr := range pl.Tracks {
+				ids[i] = tr.ID
+			}
+			if got := strings.Join(ids, ","); got != tt.wantOrder {
+				t.Fatalf("expected order %q, got %q", tt.wantOrder, got)
+			}
+		})
+	}
+}
+
 func TestHandler_GetPlaylistAnalysis(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -434,6 +593,133 @@ func TestHandler_GetPlaylistAnalysis(t *testing.T) {
 	}
 }
 
+func TestHandler_GetPlaylistAnalysis_Detail(t *testing.T) {
+	playlist := domain.Playlist{ID: "pl-1", Tracks: []domain.Track{
+		{ID: "t1", Features: domain.AudioFeatures{Energy: 0.4, Tempo: 95}},
+		{ID: "t2", Features: domain.AudioFeatures{Energy: 0.8, Tempo: 125}},
+	}}
+	tests := []struct {
+		name       string
+		svc        *fakeService
+		query      string
+		wantStatus int
+	}{
+		{name: "full", svc: &fakeService{playlist: playlist}, query: "?detail=full", wantStatus: http.StatusOK},
+		{name: "unknown detail", svc: &fakeService{playlist: playlist}, query: "?detail=verbose", wantStatus: http.StatusBadRequest},
+		{name: "missing playlist", svc: &fakeService{err: domain.ErrNotFound}, query: "?detail=full", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.svc, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/playlists/pl-1/analysis"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got domain.PlaylistAnalysis
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode analysis: %v", err)
+			}
+			energy := got.Features["energy"]
+			if got.TrackCount != 2 || energy.Min != 0.4 || energy.Max != 0.8 || len(got.TempoHistogram) != 3 {
+				t.Fatalf("unexpected analysis %+v", got)
+			}
+		})
+	}
+}
+
+func TestHandler_GetPlaylistArc(t *testing.T) {
+	playlist := domain.Playlist{ID: "pl-1", Tracks: []domain.Track{
+		{ID: "t1", Features: domain.AudioFeatures{Energy: 0.2}},
+		{ID: "t2", Features: domain.AudioFeatures{Energy: 0.6}},
+		{ID: "t3", Features: domain.AudioFeatures{Energy: 0.4}},
+	}}
+	tests := []struct {
+		name       string
+		svc        *fakeService
+		query      string
+		wantStatus int
+		wantWindow int
+	}{
+		{name: "default window", svc: &fakeService{playlist: playlist}, wantStatus: http.StatusOK, wantWindow: domain.DefaultArcWindow},
+		{name: "custom window", svc: &fakeService{playlist: playlist}, query: "?window=3", wantStatus: http.StatusOK, wantWindow: 3},
+		{name: "window out of range", svc: &fakeService{playlist: playlist}, query: "?window=0", wantStatus: http.StatusBadRequest},
+		{name: "missing playlist", svc: &fakeService{err: domain.ErrNotFound}, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.svc, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/playlists/pl-1/arc"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got domain.PlaylistArc
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode arc: %v", err)
+			}
+			if got.Window != tt.wantWindow || len(got.Tracks) != 3 || len(got.Smoothed) != 3 || got.Tracks[1].TrackID != "t2" {
+				t.Fatalf("unexpected arc %+v", got)
+			}
+		})
+	}
+}
+
+func TestHandler_GetRecommendations(t *testing.T) {
+	recs := []domain.PlaylistRecommendation{
+		{Track: domain.Track{ID: "r1"}, Score: 0.9},
+		{Track: domain.Track{ID: "r2"}, Score: 0.7},
+	}
+	tests := []struct {
+		name       string
+		svc        *fakeService
+		query      string
+		wantStatus int
+		wantCount  int
+	}{
+		{name: "lists recommendations", svc: &fakeService{recommendations: recs}, wantStatus: http.StatusOK, wantCount: 2},
+		{name: "limit", svc: &fakeService{recommendations: recs}, query: "?limit=1", wantStatus: http.StatusOK, wantCount: 1},
+		{name: "limit out of range", svc: &fakeService{recommendations: recs}, query: "?limit=500", wantStatus: http.StatusBadRequest},
+		{name: "missing playlist", svc: &fakeService{recommendations: recs, err: domain.ErrNotFound}, wantStatus: http.StatusNotFound},
+		{name: "not configured", svc: &fakeService{}, wantStatus: http.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.svc, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/playlists/pl-1/recommendations"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got []domain.PlaylistRecommendation
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode recommendations: %v", err)
+			}
+			if len(got) != tt.wantCount || tt.svc.calledID != "pl-1" {
+				t.Fatalf("expected %d recommendations for pl-1, got %+v for %q", tt.wantCount, got, tt.svc.calledID)
+			}
+		})
+	}
+}
+
 func TestHandler_AnalyzeIntent(t *testing.T) {
 	intent := domain.IntentObject{}
 	intent.Explanation = "test"
@@ -485,6 +771,32 @@ func TestHandler_AnalyzeIntent(t *testing.T) {
 		}
 	})
 
+	t.Run("Success: relays streamed output as deltas", func(t *testing.T) {
+		compiler := &streamingIntentCompiler{
+			mockIntentCompiler: mockIntentCompiler{intent: intent},
+			deltas:             []domain.IntentDelta{{Thinking: "Willie means outlaw country"}, {Content: `{"intent_type":`}, {Content: `"CREATE"}`}},
+		}
+		svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, compiler)
+		h := NewHandler(svc, nil)
+
+		bodyBytes, _ := json.Marshal(map[string]string{"message": "Give me Willie Nelson style songs"})
+		req := httptest.NewRequest(http.MethodPost, "/playlists/p1/intent", bytes.NewBuffer(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		body := rec.Body.String()
+		if got := strings.Count(body, "event: delta"); got != 3 {
+			t.Fatalf("expected 3 delta events, got %d in %q", got, body)
+		}
+		thinking := strings.Index(body, `"thinking":"Willie means outlaw country"`)
+		content := strings.Index(body, `"content":"\"CREATE\"}"`)
+		complete := strings.Index(body, "event: complete")
+		if thinking < 0 || content < thinking || complete < content {
+			t.Fatalf("expected deltas in order before complete, got %q", body)
+		}
+	})
+
 	t.Run("Bad Request: missing message", func(t *testing.T) {
 		compiler := &mockIntentCompiler{intent: intent}
 		repo := &mockRepo{}
@@ -505,6 +817,99 @@ func TestHandler_AnalyzeIntent(t *testing.T) {
 		}
 	})
 
+	t.Run("Success: fills a target duration", func(t *testing.T) {
+		compiler := &mockIntentCompiler{intent: intent}
+		spotify := &mockSpotify{track: domain.Track{ID: "t1", Title: "Song", Artist: "Willie Nelson", DurationMs: 240000}}
+		svc := services.NewOrchestrator(spotify, &mockRepo{}, compiler)
+		h := NewHandler(svc, nil)
+
+		bodyBytes, _ := json.Marshal(map[string]any{"message": "fill five minutes", "target_duration_ms": 300000})
+		req := httptest.NewRequest(http.MethodPost, "/playlists/p1/intent", bytes.NewBuffer(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		body := rec.Body.String()
+		if !strings.Contains(body, `"target_duration_ms":300000`) || !strings.Contains(body, `"duration_ms":240000`) {
+			t.Errorf("Response should report target and final duration, got %q", body)
+		}
+	})
+
+	t.Run("Bad Request: negative target duration", func(t *testing.T) {
+		compiler := &mockIntentCompiler{intent: intent}
+		svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, compiler)
+		h := NewHandler(svc, nil)
+
+		bodyBytes, _ := json.Marshal(map[string]any{"message": "test", "target_duration_ms": -1})
+		req := httptest.NewRequest(http.MethodPost, "/playlists/p1/intent", bytes.NewBuffer(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Status Code: got %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+		if compiler.called {
+			t.Error("expected compiler not to be called")
+		}
+	})
+
+	t.Run("Success: expands similar artists", func(t *testing.T) {
+		compiler := &mockIntentCompiler{intent: intent}
+		spotify := &mockSpotify{track: domain.Track{ID: "t1", Title: "Song", Artist: "Willie Nelson"}}
+		similar := stubSimilarArtists{"Willie Nelson": {"Waylon Jennings", "Kris Kristofferson"}}
+		svc := services.NewOrchestrator(spotify, &mockRepo{}, compiler, services.WithSimilarArtists(similar))
+		h := NewHandler(svc, nil)
+
+		bodyBytes, _ := json.Marshal(map[string]any{"message": "outlaw country", "expand_similar": 2})
+		req := httptest.NewRequest(http.MethodPost, "/playlists/p1/intent", bytes.NewBuffer(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if body := rec.Body.String(); !strings.Contains(body, `"similar_artists":["Waylon Jennings","Kris Kristofferson"]`) {
+			t.Errorf("Response should name the similar artists, got %q", body)
+		}
+	})
+
+	t.Run("Bad Request: expand_similar out of range", func(t *testing.T) {
+		compiler := &mockIntentCompiler{intent: intent}
+		svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, compiler)
+		h := NewHandler(svc, nil)
+
+		bodyBytes, _ := json.Marshal(map[string]any{"message": "test", "expand_similar": domain.MaxExpandSimilar + 1})
+		req := httptest.NewRequest(http.MethodPost, "/playlists/p1/intent", bytes.NewBuffer(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "expand_similar") {
+			t.Errorf("got %d %q, want 400 naming expand_similar", rec.Code, rec.Body.String())
+		}
+		if compiler.called {
+			t.Error("expected compiler not to be called")
+		}
+	})
+
+	t.Run("Bad Request: session_id too long", func(t *testing.T) {
+		compiler := &mockIntentCompiler{intent: intent}
+		svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, compiler)
+		h := NewHandler(svc, nil)
+
+		bodyBytes, _ := json.Marshal(map[string]any{"message": "test", "session_id": strings.Repeat("s", domain.MaxSessionIDLength+1)})
+		req := httptest.NewRequest(http.MethodPost, "/playlists/p1/intent", bytes.NewBuffer(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "session_id") {
+			t.Errorf("got %d %q, want 400 naming session_id", rec.Code, rec.Body.String())
+		}
+		if compiler.called {
+			t.Error("expected compiler not to be called")
+		}
+	})
+
 	t.Run("Unsupported Media Type", func(t *testing.T) {
 		compiler := &mockIntentCompiler{intent: intent}
 		repo := &mockRepo{}
@@ -579,8 +984,8 @@ func TestHandler_AnalyzeIntent(t *testing.T) {
 
 func TestHandler_AsyncAudioAnalysis(t *testing.T) {
 	origAnalyze := worker.AnalyzePreviewFunc
-	worker.AnalyzePreviewFunc = func(url string) (float64, error) {
-		return 0.95, nil
+	worker.AnalyzePreviewFunc = func(url string) (domain.AudioFeatures, error) {
+		return domain.AudioFeatures{Energy: 0.95, Tempo: 118, Danceability: 0.7}, nil
 	}
 	defer func() { worker.AnalyzePreviewFunc = origAnalyze }()
 
@@ -630,6 +1035,9 @@ func TestHandler_AsyncAudioAnalysis(t *testing.T) {
 			t.Fatalf("decode playlist: %v", err)
 		}
 		if len(got.Tracks) > 0 && got.Tracks[0].Features.Energy != 0 {
+			if f := got.Tracks[0].Features; f.Tempo != 118 || f.Danceability != 0.7 {
+				t.Fatalf("expected every analyzed feature to be stored, got %+v", f)
+			}
 			return
 		}
 		time.Sleep(500 * time.Millisecond)
@@ -637,3 +1045,2593 @@ func TestHandler_AsyncAudioAnalysis(t *testing.T) {
 
 	t.Fatalf("timed out waiting for async audio analysis")
 }
+
+func TestHandler_JobStatus(t *testing.T) {
+	origAnalyze := worker.AnalyzePreviewFunc
+	worker.AnalyzePreviewFunc = func(url string) (domain.AudioFeatures, error) {
+		return domain.AudioFeatures{Energy: 0.6}, nil
+	}
+	defer func() { worker.AnalyzePreviewFunc = origAnalyze }()
+
+	repo, err := sqlite.NewAdapter("file:jobstatus?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer repo.Close()
+
+	track := domain.Track{ID: "t-job", Title: "Heat Waves", Artist: "Glass Animals", PreviewURL: "http://example.com/preview.mp3"}
+	svc := services.NewOrchestrator(&mockSpotify{track: track}, repo, nil)
+	pool := worker.NewPool(repo, 1, 10)
+	pool.SetJobQueue(repo, "test")
+	pool.Start(1)
+	defer pool.Stop()
+	h := NewHandler(svc, pool, WithJobs(repo))
+
+	playlist, err := svc.CreatePlaylist(context.Background(), "Jobs")
+	if err != nil {
+		t.Fatalf("create playlist: %v", err)
+	}
+	body, _ := json.Marshal(map[string]string{"title": "Heat Waves", "artist": "Glass Animals"})
+	req := httptest.NewRequest(http.MethodPost, "/playlists/"+playlist.ID+"/tracks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var added addTrackResponse
+	if err := json.NewDecoder(rec.Body).Decode(&added); err != nil || rec.Code != http.StatusCreated || added.JobID == "" {
+		t.Fatalf("expected a job ID, got %d %+v (%v)", rec.Code, added, err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/"+added.JobID, nil))
+		var job domain.Job
+		if err := json.NewDecoder(rec.Body).Decode(&job); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("get job: %d (%v)", rec.Code, err)
+		}
+		if job.State == domain.JobDone {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for job, last %+v", job)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/playlists/"+playlist.ID+"/jobs", nil))
+	var progress playlistJobsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&progress); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("playlist jobs: %d (%v)", rec.Code, err)
+	}
+	if progress.Total != 1 || progress.Done != 1 || progress.Pending != 0 || progress.Jobs[0].TrackID != "t-job" {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+
+	for _, path := range []string{"/jobs/missing", "/playlists/missing/jobs"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestHandler_PlaylistEvents(t *testing.T) {
+	origAnalyze := worker.AnalyzePreviewFunc
+	worker.AnalyzePreviewFunc = func(url string) (domain.AudioFeatures, error) {
+		return domain.AudioFeatures{Energy: 0.9}, nil
+	}
+	defer func() { worker.AnalyzePreviewFunc = origAnalyze }()
+
+	repo, err := sqlite.NewAdapter("file:playlistevents?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer repo.Close()
+
+	track := domain.Track{ID: "t-ev", Title: "Dreams", Artist: "Fleetwood Mac", PreviewURL: "http://example.com/preview.mp3"}
+	svc := services.NewOrchestrator(&mockSpotify{track: track}, repo, nil)
+	bus := events.NewBus()
+	pool := worker.NewPool(repo, 1, 10)
+	pool.SetEventSink(bus)
+	pool.Start(1)
+	defer pool.Stop()
+	srv := httptest.NewServer(NewHandler(svc, pool, WithEvents(bus)))
+	defer srv.Close()
+
+	playlist, err := svc.CreatePlaylist(context.Background(), "Events")
+	if err != nil {
+		t.Fatalf("create playlist: %v", err)
+	}
+	if _, _, _, err := svc.AddTrackToPlaylist(context.Background(), playlist.ID, "Dreams", "Fleetwood Mac"); err != nil {
+		t.Fatalf("add track: %v", err)
+	}
+
+	resp, err := http.Get(srv.URL + "/playlists/missing/events")
+	if err != nil {
+		t.Fatalf("get missing feed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("missing playlist: expected 404, got %d", resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/playlists/"+playlist.ID+"/events", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get feed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type: got %q", ct)
+	}
+	lines := bufio.NewScanner(resp.Body)
+	next := func() string {
+		for lines.Scan() {
+			if name, ok := strings.CutPrefix(lines.Text(), "event: "); ok {
+				return name
+			}
+		}
+		t.Fatalf("feed ended: %v", lines.Err())
+		return ""
+	}
+	if got := next(); got != "status" {
+		t.Fatalf("first event: got %q, want status", got)
+	}
+
+	// Neither concerns the playlist.
+	_ = bus.Publish(ctx, domain.Event{ID: "e1", Type: domain.EventTracksAdded, PlaylistID: "other"})
+	unrelated, _ := json.Marshal(domain.FeaturesUpdatedPayload{TrackID: "t-other"})
+	_ = bus.Publish(ctx, domain.Event{ID: "e2", Type: domain.EventFeaturesUpdated, Payload: unrelated})
+
+	pool.Submit(worker.Job{TrackID: track.ID, PreviewURL: track.PreviewURL})
+	if got := next(); got != domain.EventFeaturesUpdated {
+		t.Fatalf("got %q, want %s", got, domain.EventFeaturesUpdated)
+	}
+	added, _ := json.Marshal(domain.TracksAddedPayload{TrackIDs: []string{"t-new"}})
+	_ = bus.Publish(ctx, domain.Event{ID: "e3", Type: domain.EventTracksAdded, PlaylistID: playlist.ID, Payload: added})
+	if got := next(); got != domain.EventTracksAdded {
+		t.Fatalf("got %q, want %s", got, domain.EventTracksAdded)
+	}
+	// Tracks added while subscribed are followed too.
+	followed, _ := json.Marshal(domain.FeaturesUpdatedPayload{TrackID: "t-new"})
+	_ = bus.Publish(ctx, domain.Event{ID: "e4", Type: domain.EventFeaturesUpdated, Payload: followed})
+	if got := next(); got != domain.EventFeaturesUpdated {
+		t.Fatalf("got %q, want %s", got, domain.EventFeaturesUpdated)
+	}
+	for lines.Scan() {
+		if data, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+			var event domain.Event
+			if err := json.Unmarshal([]byte(data), &event); err != nil || event.ID != "e4" {
+				t.Fatalf("data: got %s (%v), want event e4", data, err)
+			}
+			break
+		}
+	}
+}
+
+func TestHandler_AnalyzeIntent_HTTP2(t *testing.T) {
+	intent := domain.IntentObject{Explanation: "h2"}
+	compiler := &mockIntentCompiler{intent: intent}
+	svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, compiler)
+
+	ts := httptest.NewUnstartedServer(NewHandler(svc, nil))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	bodyBytes, _ := json.Marshal(map[string]string{"message": "test"})
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/playlists/p1/intent", bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("expected HTTP/2, got %s", resp.Proto)
+	}
+	if resp.Header.Get("Connection") != "" {
+		t.Errorf("Connection header must not be sent over HTTP/2, got %q", resp.Header.Get("Connection"))
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if !strings.Contains(buf.String(), "event: complete") {
+		t.Errorf("Response should contain 'event: complete', got %q", buf.String())
+	}
+}
+
+// fakeSnapshotter writes a placeholder file instead of copying a database.
+type fakeSnapshotter struct {
+	err error
+}
+
+func (f *fakeSnapshotter) Snapshot(ctx context.Context, path string) error {
+	if f.err != nil {
+		return f.err
+	}
+	return os.WriteFile(path, []byte("snapshot"), 0o600)
+}
+
+func TestHandler_Backups(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		auth       string
+		snapErr    error
+		wantStatus int
+	}{
+		{name: "create requires token", method: http.MethodPost, auth: "", wantStatus: http.StatusUnauthorized},
+		{name: "create rejects wrong token", method: http.MethodPost, auth: "Bearer nope", wantStatus: http.StatusUnauthorized},
+		{name: "create snapshot", method: http.MethodPost, auth: "Bearer secret", wantStatus: http.StatusCreated},
+		{name: "create snapshot failure", method: http.MethodPost, auth: "Bearer secret", snapErr: errors.New("disk full"), wantStatus: http.StatusInternalServerError},
+		{name: "list snapshots", method: http.MethodGet, auth: "Bearer secret", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil)
+			store, err := blob.NewLocalStore(t.TempDir())
+			if err != nil {
+				t.Fatalf("new store: %v", err)
+			}
+			backups := worker.NewBackups(&fakeSnapshotter{err: tt.snapErr}, store, 2)
+			h := NewHandler(svc, nil, WithAdminToken("secret"), WithBackups(backups))
+
+			req := httptest.NewRequest(tt.method, "/admin/backups", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_Backups_Retention(t *testing.T) {
+	dir := t.TempDir()
+	store, err := blob.NewLocalStore(dir)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil)
+	h := NewHandler(svc, nil, WithAdminToken("secret"), WithBackups(worker.NewBackups(&fakeSnapshotter{}, store, 2)))
+
+	// Seed older snapshots; names carry the timestamp used for ordering.
+	if err := os.MkdirAll(filepath.Join(dir, "backups"), 0o750); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	for _, name := range []string{"overture-20240101T000000Z.db", "overture-20240102T000000Z.db", "unrelated.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, "backups", name), nil, 0o600); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	do := func(method string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/admin/backups", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var created worker.Backup
+	if err := json.NewDecoder(do(http.MethodPost).Body).Decode(&created); err != nil {
+		t.Fatalf("decode created: %v", err)
+	}
+	var list []worker.Backup
+	if err := json.NewDecoder(do(http.MethodGet).Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+
+	if len(list) != 2 {
+		t.Fatalf("expected 2 retained snapshots, got %d", len(list))
+	}
+	if list[0].Name != created.Name || list[1].Name != "overture-20240102T000000Z.db" {
+		t.Fatalf("unexpected retained snapshots: %+v", list)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "backups", "unrelated.txt")); err != nil {
+		t.Fatalf("retention removed an unrelated file: %v", err)
+	}
+}
+
+func TestHandler_ExportData(t *testing.T) {
+	playlist := domain.Playlist{ID: "pl-1", Name: "Exported", Tracks: []domain.Track{{ID: "t1", Title: "Song", Artist: "Artist"}}}
+	svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{playlist: playlist}, nil)
+	store, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	pool := worker.NewPool(&mockRepo{}, 1, 10)
+	pool.Start(1)
+	defer pool.Stop()
+	h := NewHandler(svc, pool, WithExports(worker.NewExports(svc.ExportUserData, store, pool)))
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me/export", nil))
+		return rec
+	}
+
+	if rec := get(); rec.Code != http.StatusAccepted {
+		t.Fatalf("first request: expected status %d, got %d", http.StatusAccepted, rec.Code)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		rec := get()
+		if rec.Code == http.StatusAccepted {
+			time.Sleep(20 * time.Millisecond)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/zip" {
+			t.Fatalf("expected application/zip, got %q", ct)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		if err != nil {
+			t.Fatalf("open archive: %v", err)
+		}
+		f, err := zr.Open("overture-export.json")
+		if err != nil {
+			t.Fatalf("open document: %v", err)
+		}
+		defer f.Close()
+		var doc domain.Export
+		if err := json.NewDecoder(f).Decode(&doc); err != nil {
+			t.Fatalf("decode document: %v", err)
+		}
+		if doc.Format != domain.ExportFormat || len(doc.Playlists) != 1 || doc.Playlists[0].Name != "Exported" {
+			t.Fatalf("unexpected export document: %+v", doc)
+		}
+		return
+	}
+	t.Fatalf("timed out waiting for export")
+}
+
+func TestHandler_ExportDataRetriesFailure(t *testing.T) {
+	store, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	pool := worker.NewPool(&mockRepo{}, 1, 10)
+	pool.Start(1)
+	defer pool.Stop()
+	var builds atomic.Int32
+	build := func(context.Context) (domain.Export, error) {
+		if builds.Add(1) == 1 {
+			return domain.Export{}, errors.New("secret database path /var/lib/overture.db")
+		}
+		return domain.Export{Format: domain.ExportFormat, ExportedAt: time.Now()}, nil
+	}
+	h := NewHandler(&fakeService{}, pool, WithExports(worker.NewExports(build, store, pool)))
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me/export", nil))
+		if strings.Contains(rec.Body.String(), "secret") {
+			t.Fatalf("expected the build error to stay internal, got %s", rec.Body.String())
+		}
+		if rec.Code == http.StatusOK {
+			if n := builds.Load(); n != 2 {
+				t.Fatalf("expected the failed build to be retried once, got %d builds", n)
+			}
+			return
+		}
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for export")
+}
+
+// fakeOrphanCleaner reports a fixed set of orphans and records deletions.
+type fakeOrphanCleaner struct {
+	orphans []string
+	deleted bool
+}
+
+func (f *fakeOrphanCleaner) OrphanedTracks(ctx context.Context, cutoff time.Time) ([]string, error) {
+	return f.orphans, nil
+}
+
+func (f *fakeOrphanCleaner) DeleteOrphanedTracks(ctx context.Context, cutoff time.Time) (int64, error) {
+	f.deleted = true
+	return int64(len(f.orphans)), nil
+}
+
+func TestHandler_RunCleanup(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		wantDeleted bool
+		wantRemoved int64
+	}{
+		{name: "dry run only reports", query: "?dry_run=true", wantDeleted: false, wantRemoved: 0},
+		{name: "removes orphans", query: "", wantDeleted: true, wantRemoved: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orphans := &fakeOrphanCleaner{orphans: []string{"t1", "t2"}}
+			svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil)
+			h := NewHandler(svc, nil, WithAdminToken("secret"), WithCleaner(worker.NewCleaner(orphans, nil, time.Hour, 0)))
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/cleanup"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+			var report worker.CleanupReport
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatalf("decode report: %v", err)
+			}
+			if len(report.OrphanedTracks) != 2 || report.TracksRemoved != tt.wantRemoved || orphans.deleted != tt.wantDeleted {
+				t.Fatalf("unexpected report %+v (deleted=%v)", report, orphans.deleted)
+			}
+		})
+	}
+}
+
+// fakeBacklog serves a fixed set of tracks missing analysis.
+type fakeBacklog struct {
+	tracks    []domain.Track
+	attempted []string
+}
+
+func (f *fakeBacklog) TracksMissingAnalysis(ctx context.Context, limit int) ([]domain.Track, error) {
+	return f.tracks, nil
+}
+
+func (f *fakeBacklog) MarkBackfillAttempted(ctx context.Context, trackIDs []string, at time.Time) error {
+	f.attempted = append(f.attempted, trackIDs...)
+	return nil
+}
+
+func TestHandler_Backfill(t *testing.T) {
+	backlog := &fakeBacklog{tracks: []domain.Track{
+		{ID: "t-resolvable"},
+		{ID: "t-unresolvable"},
+		{ID: "t-unanalyzed", PreviewURL: "https://p.scdn.co/a.mp3"},
+		{ID: "t-has-features", Features: domain.AudioFeatures{Energy: 0.4}},
+		{ID: "t-measured", PreviewURL: "https://p.scdn.co/m.mp3"},
+	}}
+	// The pool is never started, so queued jobs stay queued.
+	backfill := worker.NewBackfiller(backlog, worker.NewPool(&mockRepo{}, 1, 10), 50)
+	backfill.SetPreviewFallback(func(ctx context.Context, trackID string) (string, bool) {
+		if trackID == "t-unresolvable" {
+			return "", false
+		}
+		return "https://cdn.deezer.com/" + trackID + ".mp3", true
+	})
+	backfill.SetFeatureLookup(func(ctx context.Context, trackID string) (domain.AudioFeatures, bool) {
+		return domain.AudioFeatures{Energy: 0.8}, trackID == "t-measured"
+	})
+	svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil)
+	h := NewHandler(svc, nil, WithAdminToken("secret"), WithBackfill(backfill))
+
+	do := func(method string) (int, worker.BackfillReport) {
+		t.Helper()
+		req := httptest.NewRequest(method, "/admin/backfill", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var report worker.BackfillReport
+		if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+			t.Fatalf("decode report: %v", err)
+		}
+		return rec.Code, report
+	}
+
+	code, report := do(http.MethodPost)
+	if code != http.StatusAccepted || !report.Running || report.StartedAt == nil {
+		t.Fatalf("expected an accepted running backfill, got %d %+v", code, report)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for report.Running && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		code, report = do(http.MethodGet)
+		if code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, code)
+		}
+	}
+	want := worker.BackfillReport{Found: 5, Processed: 5, FeaturesResolved: 1, PreviewsResolved: 2, Unresolved: 1, Enqueued: 2}
+	if report.Running || report.FinishedAt == nil || report.Error != "" ||
+		report.Found != want.Found || report.Processed != want.Processed || report.FeaturesResolved != want.FeaturesResolved ||
+		report.PreviewsResolved != want.PreviewsResolved ||
+		report.Unresolved != want.Unresolved || report.Enqueued != want.Enqueued || report.Dropped != 0 {
+		t.Fatalf("expected %+v, got %+v", want, report)
+	}
+	if len(backlog.attempted) != 5 {
+		t.Fatalf("expected every track marked attempted, got %v", backlog.attempted)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/backfill", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d without a token, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+type fakeReporter struct {
+	mu      sync.Mutex
+	reports []map[string]string
+}
+
+func (f *fakeReporter) Report(ctx context.Context, err error, fields map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reports = append(f.reports, fields)
+}
+
+// panicRepo panics on reads to exercise the handler's recovery.
+type panicRepo struct {
+	mockRepo
+}
+
+func (p *panicRepo) GetByID(ctx context.Context, id string) (domain.Playlist, error) {
+	panic("boom")
+}
+
+func TestHandler_RecoversPanics(t *testing.T) {
+	reporter := &fakeReporter{}
+	svc := services.NewOrchestrator(&mockSpotify{}, &panicRepo{}, nil)
+	h := NewHandler(svc, nil, WithErrorReporter(reporter))
+
+	req := httptest.NewRequest(http.MethodGet, "/playlists/pl-1", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	if len(reporter.reports) != 1 {
+		t.Fatalf("expected 1 report, got %d", len(reporter.reports))
+	}
+	if got := reporter.reports[0]["http.path"]; got != "/playlists/pl-1" {
+		t.Fatalf("expected http.path /playlists/pl-1, got %q", got)
+	}
+}
+
+func TestHandler_Market(t *testing.T) {
+	tests := []struct {
+		name       string
+		market     string
+		wantStatus int
+		wantMarket string
+	}{
+		{name: "configured market", wantStatus: http.StatusCreated},
+		{name: "request market", market: "gb", wantStatus: http.StatusCreated, wantMarket: "GB"},
+		{name: "unknown market", market: "Britain", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spotify := &mockSpotify{}
+			h := NewHandler(services.NewOrchestrator(spotify, &mockRepo{}, nil), nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/playlists/pl-1/tracks", strings.NewReader(`{"title":"Song","artist":"Artist"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.market != "" {
+				req.Header.Set(MarketHeader, tt.market)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if spotify.market != tt.wantMarket {
+				t.Fatalf("expected the track looked up in %q, got %q", tt.wantMarket, spotify.market)
+			}
+		})
+	}
+}
+
+func TestHandler_RateLimit(t *testing.T) {
+	h := NewHandler(&fakeService{}, nil, WithRateLimit(RateLimit{Rate: 1, Burst: 3, IntentRate: 0.1, IntentBurst: 1, TrustProxy: true, APIKeys: []string{"key-1"}}))
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	h.limiter.now = func() time.Time { return now }
+	h.intentLimiter.now = func() time.Time { return now }
+
+	do := func(method, path, client, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"message":"mellow"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", "198.51.100.7, "+client)
+		if apiKey != "" {
+			req.Header.Set(APIKeyHeader, apiKey)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 3; i++ {
+		if rec := do(http.MethodGet, "/playlists/p1", "203.0.113.1", ""); rec.Code == http.StatusTooManyRequests {
+			t.Fatalf("request %d: expected the burst to be allowed", i+1)
+		}
+	}
+	rec := do(http.MethodGet, "/playlists/p1", "203.0.113.1", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once the burst is spent, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("expected Retry-After 1, got %q", got)
+	}
+	if rec := do(http.MethodGet, "/health", "203.0.113.1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected /health to be exempt, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/playlists/p1", "203.0.113.2", ""); rec.Code == http.StatusTooManyRequests {
+		t.Fatal("expected another address to have its own bucket")
+	}
+	if rec := do(http.MethodGet, "/playlists/p1", "203.0.113.1", "key-1"); rec.Code == http.StatusTooManyRequests {
+		t.Fatal("expected an API key to have its own bucket")
+	}
+	for i, key := range []string{"", "rotated-1", "rotated-2"} {
+		if rec := do(http.MethodGet, "/playlists/p1", "203.0.113.4", key); rec.Code == http.StatusTooManyRequests {
+			t.Fatalf("request %d: expected the burst to be allowed", i+1)
+		}
+	}
+	if rec := do(http.MethodGet, "/playlists/p1", "203.0.113.4", "rotated-3"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected unknown API keys to share the address's bucket, got %d", rec.Code)
+	}
+
+	now = now.Add(time.Second)
+	if rec := do(http.MethodGet, "/playlists/p1", "203.0.113.1", ""); rec.Code == http.StatusTooManyRequests {
+		t.Fatal("expected a token to refill after a second")
+	}
+
+	if rec := do(http.MethodPost, "/playlists/p1/intent", "203.0.113.3", ""); rec.Code == http.StatusTooManyRequests {
+		t.Fatal("expected the first intent to be allowed")
+	}
+	rec = do(http.MethodPost, "/playlists/p1/intent", "203.0.113.3", "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "10" {
+		t.Fatalf("expected the intent limit to apply with Retry-After 10, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestHandler_Captures(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	if err := store.SaveCapture(context.Background(), domain.IntentCapture{ID: "cap-1", Model: "m", Prompt: "p", Response: "{}", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("save capture: %v", err)
+	}
+
+	svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil)
+	h := NewHandler(svc, nil, WithAdminToken("secret"), WithCaptures(store))
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{name: "requires admin token", path: "/admin/captures", wantStatus: http.StatusUnauthorized},
+		{name: "lists captures", path: "/admin/captures", token: "secret", wantStatus: http.StatusOK},
+		{name: "rejects bad limit", path: "/admin/captures?limit=0", token: "secret", wantStatus: http.StatusBadRequest},
+		{name: "gets capture", path: "/admin/captures/cap-1", token: "secret", wantStatus: http.StatusOK},
+		{name: "unknown capture", path: "/admin/captures/nope", token: "secret", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ReplayIntents(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	if err := store.SaveIntentRun(context.Background(), domain.IntentRun{ID: "run-1", PlaylistID: "pl-1", Added: []string{"t1"}, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("save run: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		wantStatus int
+		wantRuns   int
+	}{
+		{name: "recording disabled", wantStatus: http.StatusNotImplemented},
+		{name: "replays recorded runs", opts: []services.Option{services.WithIntentRuns(store)}, wantStatus: http.StatusOK, wantRuns: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, tt.opts...)
+			h := NewHandler(svc, nil, WithAdminToken("secret"))
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/replay", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var report domain.ReplayReport
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatalf("decode report: %v", err)
+			}
+			if report.Runs != tt.wantRuns || report.Changed != 1 {
+				t.Fatalf("unexpected report %+v", report)
+			}
+		})
+	}
+}
+
+func TestHandler_ListFlags(t *testing.T) {
+	set, err := flags.New(map[string]bool{domain.FlagTargetScoring: true})
+	if err != nil {
+		t.Fatalf("new flags: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		token       string
+		wantStatus  int
+		wantEnabled []string
+	}{
+		{name: "requires admin token", opts: []Option{WithFlags(set)}, wantStatus: http.StatusUnauthorized},
+		{name: "lists configured flags", opts: []Option{WithFlags(set)}, token: "secret", wantStatus: http.StatusOK, wantEnabled: []string{domain.FlagTargetScoring}},
+		{name: "unconfigured flags are off", token: "secret", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&fakeService{}, nil, append(tt.opts, WithAdminToken("secret"))...)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/flags", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var states []flags.State
+			if err := json.NewDecoder(rec.Body).Decode(&states); err != nil {
+				t.Fatalf("decode flags: %v", err)
+			}
+			if len(states) != 1 {
+				t.Fatalf("expected 1 flag, got %+v", states)
+			}
+			enabled := []string{}
+			for _, st := range states {
+				if st.Enabled {
+					enabled = append(enabled, st.Name)
+				}
+			}
+			if strings.Join(enabled, ",") != strings.Join(tt.wantEnabled, ",") {
+				t.Fatalf("expected enabled %v, got %v", tt.wantEnabled, enabled)
+			}
+		})
+	}
+}
+
+func TestHandler_DebugConfig(t *testing.T) {
+	cfg := map[string]any{"ListenAddr": ":8080", "SpotifyClientSecret": "[REDACTED]"}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		token      string
+		wantStatus int
+	}{
+		{name: "requires admin token", opts: []Option{WithDebugConfig(cfg)}, wantStatus: http.StatusUnauthorized},
+		{name: "reports the configuration", opts: []Option{WithDebugConfig(cfg)}, token: "secret", wantStatus: http.StatusOK},
+		{name: "absent without a configuration", token: "secret", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&fakeService{}, nil, append(tt.opts, WithAdminToken("secret"))...)
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/config", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got map[string]any
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode config: %v", err)
+			}
+			if got["ListenAddr"] != ":8080" || got["SpotifyClientSecret"] != "[REDACTED]" {
+				t.Fatalf("expected the configuration as given, got %v", got)
+			}
+		})
+	}
+}
+
+func TestHandler_DebugPrompt(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []Option
+		svc          *fakeService
+		query        string
+		wantStatus   int
+		wantTemplate string
+		wantPlaylist string
+	}{
+		{name: "renders the compiler's prompt", opts: []Option{WithPrompts(prompt.Default(), "ollama")}, wantStatus: http.StatusOK, wantTemplate: "ollama"},
+		{name: "renders another template", opts: []Option{WithPrompts(prompt.Default(), "ollama")}, query: "?template=anthropic", wantStatus: http.StatusOK, wantTemplate: "anthropic"},
+		{name: "unknown template", opts: []Option{WithPrompts(prompt.Default(), "ollama")}, query: "?template=gpt", wantStatus: http.StatusNotFound},
+		{name: "absent without prompts", wantStatus: http.StatusNotFound},
+		{
+			name:         "renders a playlist's prompt",
+			opts:         []Option{WithPrompts(prompt.Default(), "ollama")},
+			svc:          &fakeService{playlist: domain.Playlist{ID: "pl-1", Name: "Run", Tracks: []domain.Track{{ID: "t1"}}}},
+			query:        "?playlist_id=pl-1",
+			wantStatus:   http.StatusOK,
+			wantTemplate: "ollama",
+			wantPlaylist: "the playlist 'Run' with 1 tracks",
+		},
+		{
+			name:       "unknown playlist",
+			opts:       []Option{WithPrompts(prompt.Default(), "ollama")},
+			svc:        &fakeService{err: domain.ErrNotFound},
+			query:      "?playlist_id=missing",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := tt.svc
+			if svc == nil {
+				svc = &fakeService{}
+			}
+			h := NewHandler(svc, nil, append(tt.opts, WithAdminToken("secret"))...)
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/prompt"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got debugPromptResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode prompt: %v", err)
+			}
+			if got.Template != tt.wantTemplate || !strings.HasPrefix(got.Prompt, "You are the Overture Music Intent Engine.") {
+				t.Fatalf("expected the %s prompt, got %+v", tt.wantTemplate, got)
+			}
+			if strings.Contains(got.Prompt, "Playlist:") != (tt.wantPlaylist != "") || !strings.Contains(got.Prompt, tt.wantPlaylist) {
+				t.Fatalf("expected the prompt to describe %q, got %q", tt.wantPlaylist, got.Prompt)
+			}
+		})
+	}
+}
+
+func TestHandler_ExperimentReport(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: []domain.Track{{ID: "t1", Title: "Kept", Artist: "A"}}}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	if err := store.SaveIntentRun(ctx, domain.IntentRun{
+		ID: "run-1", PlaylistID: "pl-1", Experiment: "thresholds", Variant: "strict",
+		Candidates: []domain.Track{{ID: "t1"}, {ID: "t2"}, {ID: "t3"}, {ID: "t4"}},
+		Added:      []string{"t1", "t2"}, CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("save run: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		query      string
+		wantStatus int
+	}{
+		{name: "recording disabled", wantStatus: http.StatusNotImplemented},
+		{name: "invalid limit", opts: []services.Option{services.WithIntentRuns(store)}, query: "?limit=0", wantStatus: http.StatusBadRequest},
+		{name: "reports variants", opts: []services.Option{services.WithIntentRuns(store)}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil, WithAdminToken("secret"))
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/experiments"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var report domain.ExperimentReport
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatalf("decode report: %v", err)
+			}
+			if report.Runs != 1 || len(report.Variants) != 1 {
+				t.Fatalf("unexpected report %+v", report)
+			}
+			v := report.Variants[0]
+			if v.Variant != "strict" || v.TracksAdded != 2 || v.TracksKept != 1 || v.AcceptanceRate != 0.5 || v.AddRate != 0.5 {
+				t.Fatalf("unexpected variant report %+v", v)
+			}
+		})
+	}
+}
+
+func TestHandler_IntentAnalytics(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	run := domain.IntentRun{
+		ID: "run-1", PlaylistID: "pl-1",
+		Candidates: []domain.Track{{ID: "t1"}, {ID: "t2"}},
+		Added:      []string{"t1"}, CreatedAt: time.Now(),
+	}
+	run.Intent.Entities.Artists = []string{"Bon Iver"}
+	run.Intent.Entities.Genres = []string{"folk"}
+	if err := store.SaveIntentRun(context.Background(), run); err != nil {
+		t.Fatalf("save run: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		query      string
+		wantStatus int
+	}{
+		{name: "recording disabled", wantStatus: http.StatusNotImplemented},
+		{name: "invalid limit", opts: []services.Option{services.WithIntentRuns(store)}, query: "?limit=10001", wantStatus: http.StatusBadRequest},
+		{name: "aggregates runs", opts: []services.Option{services.WithIntentRuns(store)}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil, WithAdminToken("secret"))
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/analytics/intents"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var analytics domain.IntentAnalytics
+			if err := json.NewDecoder(rec.Body).Decode(&analytics); err != nil {
+				t.Fatalf("decode analytics: %v", err)
+			}
+			if analytics.Runs != 1 || analytics.SuccessRate != 1 || analytics.AddRate != 0.5 {
+				t.Fatalf("unexpected analytics %+v", analytics)
+			}
+			if len(analytics.TopArtists) != 1 || analytics.TopArtists[0].Name != "Bon Iver" || len(analytics.TopGenres) != 1 {
+				t.Fatalf("unexpected top terms %+v %+v", analytics.TopArtists, analytics.TopGenres)
+			}
+		})
+	}
+}
+
+// stubLyrics serves lyrics for the tracks it knows.
+type stubLyrics map[string]string
+
+func (s stubLyrics) GetLyrics(ctx context.Context, track domain.Track) (domain.Lyrics, error) {
+	plain, ok := s[track.ID]
+	if !ok {
+		return domain.Lyrics{}, domain.ErrNotFound
+	}
+	return domain.Lyrics{Plain: plain, Source: "stub"}, nil
+}
+
+func TestHandler_GetTrackLyrics(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	tracks := []domain.Track{{ID: "t1", Title: "Sunny", Artist: "A"}, {ID: "t2", Title: "Hum", Artist: "B"}}
+	if err := store.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: tracks}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	withLyrics := []services.Option{services.WithLyrics(stubLyrics{"t1": "I love the sunshine"}, store, store)}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		trackID    string
+		wantStatus int
+	}{
+		{name: "lyrics disabled", trackID: "t1", wantStatus: http.StatusNotImplemented},
+		{name: "unknown track", opts: withLyrics, trackID: "missing", wantStatus: http.StatusNotFound},
+		{name: "no lyrics", opts: withLyrics, trackID: "t2", wantStatus: http.StatusNotFound},
+		{name: "found", opts: withLyrics, trackID: "t1", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/tracks/"+tt.trackID+"/lyrics", nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var lyrics domain.Lyrics
+			if err := json.NewDecoder(rec.Body).Decode(&lyrics); err != nil {
+				t.Fatalf("decode lyrics: %v", err)
+			}
+			if lyrics.TrackID != "t1" || lyrics.Plain != "I love the sunshine" || lyrics.Sentiment <= 0.5 {
+				t.Fatalf("unexpected lyrics %+v", lyrics)
+			}
+			if _, err := store.GetLyrics(ctx, "t1"); err != nil {
+				t.Fatalf("expected lyrics to be cached: %v", err)
+			}
+		})
+	}
+}
+
+// stubPodcasts returns the same episodes for every search.
+type stubPodcasts []domain.Episode
+
+func (s stubPodcasts) SearchEpisodes(ctx context.Context, query string, limit int) ([]domain.Episode, error) {
+	return s, nil
+}
+
+func TestHandler_Episodes(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	if err := store.Save(context.Background(), domain.Playlist{ID: "pl-1", Name: "Focus", Tracks: []domain.Track{}}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	podcasts := []services.Option{services.WithPodcasts(stubPodcasts{{ID: "ep-1", Title: "Briefing", Show: "The Daily"}})}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "search disabled", method: http.MethodGet, path: "/episodes?q=news", wantStatus: http.StatusNotImplemented},
+		{name: "search without query", opts: podcasts, method: http.MethodGet, path: "/episodes", wantStatus: http.StatusBadRequest},
+		{name: "search", opts: podcasts, method: http.MethodGet, path: "/episodes?q=news&limit=5", wantStatus: http.StatusOK},
+		{name: "add disabled", method: http.MethodPost, path: "/playlists/pl-1/episodes", body: `{"query":"news"}`, wantStatus: http.StatusNotImplemented},
+		{name: "add without query", opts: podcasts, method: http.MethodPost, path: "/playlists/pl-1/episodes", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "add to unknown playlist", opts: podcasts, method: http.MethodPost, path: "/playlists/missing/episodes", body: `{"query":"news"}`, wantStatus: http.StatusNotFound},
+		{name: "add", opts: podcasts, method: http.MethodPost, path: "/playlists/pl-1/episodes", body: `{"query":"news","latest":true}`, wantStatus: http.StatusCreated},
+		{name: "add again finds nothing new", opts: podcasts, method: http.MethodPost, path: "/playlists/pl-1/episodes", body: `{"query":"news"}`, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	pl, err := store.GetByID(context.Background(), "pl-1")
+	if err != nil {
+		t.Fatalf("get playlist: %v", err)
+	}
+	if len(pl.Tracks) != 1 || !pl.Tracks[0].IsEpisode() || pl.Tracks[0].Artist != "The Daily" {
+		t.Fatalf("expected the episode in the playlist, got %+v", pl.Tracks)
+	}
+}
+
+// stubAlbums serves one album, or no confident match for other titles.
+type stubAlbums domain.Album
+
+func (s stubAlbums) GetAlbum(ctx context.Context, title, artist string) (domain.Album, error) {
+	if title != s.Title {
+		return domain.Album{}, &ports.NoConfidentMatchError{Title: title, Artist: artist}
+	}
+	return domain.Album(s), nil
+}
+
+func TestHandler_AddAlbum(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: []domain.Track{{ID: "a2", Title: "Two", Artist: "A"}}}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	album := stubAlbums{ID: "al-1", Title: "Album", Artist: "A", Tracks: []domain.Track{
+		{ID: "a1", Title: "One", Artist: "A"}, {ID: "a2", Title: "Two", Artist: "A"}, {ID: "a3", Title: "Three", Artist: "A"},
+	}}
+	albums := []services.Option{services.WithAlbums(album)}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		playlistID string
+		body       string
+		wantStatus int
+	}{
+		{name: "disabled", playlistID: "pl-1", body: `{"title":"Album","artist":"A"}`, wantStatus: http.StatusNotImplemented},
+		{name: "missing artist", opts: albums, playlistID: "pl-1", body: `{"title":"Album"}`, wantStatus: http.StatusBadRequest},
+		{name: "no match", opts: albums, playlistID: "pl-1", body: `{"title":"Nope","artist":"A"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "unknown playlist", opts: albums, playlistID: "missing", body: `{"title":"Album","artist":"A"}`, wantStatus: http.StatusNotFound},
+		{name: "adds album", opts: albums, playlistID: "pl-1", body: `{"title":"Album","artist":"A"}`, wantStatus: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/playlists/"+tt.playlistID+"/albums", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			var result domain.AlbumAddition
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("decode result: %v", err)
+			}
+			if result.TracksAdded != 2 || result.TracksSkipped != 1 || result.Album.ID != "al-1" {
+				t.Fatalf("unexpected result %+v", result)
+			}
+		})
+	}
+
+	pl, err := store.GetByID(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("get playlist: %v", err)
+	}
+	var ids []string
+	for _, tr := range pl.Tracks {
+		ids = append(ids, tr.ID)
+	}
+	if want := []string{"a2", "a1", "a3"}; strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected tracks %v in order, got %v", want, ids)
+	}
+}
+
+func TestHandler_ImportPlaylist(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	importer := stubImporter{"37i9dQZF1DXcBWIGoYBM5M": {Name: "Today's Top Hits", Tracks: []domain.Track{
+		{ID: "t1", Title: "One", Artist: "A", ISRC: "USAAA0000001"},
+		{ID: "t2", Title: "Two", Artist: "B"},
+		{ID: "t1", Title: "One", Artist: "A", ISRC: "USAAA0000001"},
+	}}}
+	imports := []services.Option{services.WithPlaylistImport(importer)}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		body       string
+		wantStatus int
+	}{
+		{name: "disabled", body: `{"url":"37i9dQZF1DXcBWIGoYBM5M"}`, wantStatus: http.StatusNotImplemented},
+		{name: "missing url", opts: imports, body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "not a playlist", opts: imports, body: `{"url":"https://open.spotify.com/album/4aawyAB9vmqN3uQ7FjRGTy"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown playlist", opts: imports, body: `{"url":"spotify:playlist:0000000000000000000000"}`, wantStatus: http.StatusNotFound},
+		{name: "imports", opts: imports, body: `{"url":"https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M?si=abc","name":"Hits"}`, wantStatus: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/playlists/import", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			var result domain.PlaylistImport
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("decode result: %v", err)
+			}
+			if result.TracksImported != 2 || result.TracksSkipped != 1 || result.SourceID != "37i9dQZF1DXcBWIGoYBM5M" {
+				t.Fatalf("unexpected result %+v", result)
+			}
+			if got := rec.Header().Get("Location"); got != "/playlists/"+result.Playlist.ID {
+				t.Fatalf("unexpected Location %q", got)
+			}
+			pl, err := store.GetByID(context.Background(), result.Playlist.ID)
+			if err != nil {
+				t.Fatalf("get playlist: %v", err)
+			}
+			if pl.Name != "Hits" || len(pl.Tracks) != 2 {
+				t.Fatalf("unexpected playlist %+v", pl)
+			}
+		})
+	}
+}
+
+// stubImporter serves Spotify playlists by ID.
+type stubImporter map[string]domain.Playlist
+
+func (s stubImporter) GetSpotifyPlaylist(ctx context.Context, playlistID string) (domain.Playlist, error) {
+	pl, ok := s[playlistID]
+	if !ok {
+		return domain.Playlist{}, domain.ErrNotFound
+	}
+	pl.ID = playlistID
+	return pl, nil
+}
+
+// fakeService implements ports.PlaylistService so handler behavior can be
+// tested without the Orchestrator.
+func TestHandler_Playback(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: []domain.Track{{ID: "t1", Title: "One", Artist: "A"}}}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	playback := []services.Option{services.WithPlayback(store)}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		method     string
+		playlistID string
+		body       string
+		wantStatus int
+	}{
+		{name: "disabled", method: http.MethodGet, playlistID: "pl-1", wantStatus: http.StatusNotImplemented},
+		{name: "nothing playing yet", opts: playback, method: http.MethodGet, playlistID: "pl-1", wantStatus: http.StatusNotFound},
+		{name: "missing track", opts: playback, method: http.MethodPut, playlistID: "pl-1", body: `{"position_ms":0}`, wantStatus: http.StatusBadRequest},
+		{name: "negative position", opts: playback, method: http.MethodPut, playlistID: "pl-1", body: `{"track_id":"t1","position_ms":-5}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown playlist", opts: playback, method: http.MethodPut, playlistID: "missing", body: `{"track_id":"t1"}`, wantStatus: http.StatusNotFound},
+		{name: "track not in playlist", opts: playback, method: http.MethodPut, playlistID: "pl-1", body: `{"track_id":"t9"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "updates", opts: playback, method: http.MethodPut, playlistID: "pl-1", body: `{"track_id":"t1","position_ms":30000,"playing":true,"device":"web"}`, wantStatus: http.StatusOK},
+		{name: "reads update", opts: playback, method: http.MethodGet, playlistID: "pl-1", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(tt.method, "/playlists/"+tt.playlistID+"/playback", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var state domain.PlaybackState
+			if err := json.NewDecoder(rec.Body).Decode(&state); err != nil {
+				t.Fatalf("decode state: %v", err)
+			}
+			if state.PlaylistID != "pl-1" || state.TrackID != "t1" || state.PositionMs != 30000 || !state.Playing || state.Device != "web" || state.UpdatedAt.IsZero() {
+				t.Fatalf("unexpected state %+v", state)
+			}
+		})
+	}
+}
+
+func TestHandler_RunningTemplate(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	if err := store.Save(context.Background(), domain.Playlist{ID: "pl-1", Name: "Run", Tracks: []domain.Track{}}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	// Played double time, 64 BPM suits the warm-up towards 170 SPM.
+	spotify := &mockSpotify{track: domain.Track{ID: "t1", Title: "Stride", Artist: "A", DurationMs: 240000, Features: domain.AudioFeatures{Tempo: 64}}}
+
+	tests := []struct {
+		name       string
+		playlistID string
+		body       string
+		wantStatus int
+		wantAdded  int
+	}{
+		{name: "generates", playlistID: "pl-1", body: `{"artists":["A"],"target_spm":170,"duration_ms":300000}`, wantStatus: http.StatusOK, wantAdded: 1},
+		{name: "cadence out of range", playlistID: "pl-1", body: `{"artists":["A"],"target_spm":20,"duration_ms":300000}`, wantStatus: http.StatusBadRequest},
+		{name: "missing artists", playlistID: "pl-1", body: `{"target_spm":170,"duration_ms":300000}`, wantStatus: http.StatusBadRequest},
+		{name: "bad body", playlistID: "pl-1", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "unknown playlist", playlistID: "missing", body: `{"artists":["A"],"target_spm":170,"duration_ms":300000}`, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(spotify, store, nil)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/playlists/"+tt.playlistID+"/templates/running", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var result domain.RunningResult
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("decode result: %v", err)
+			}
+			if result.TracksAdded != tt.wantAdded || len(result.Tracks) != tt.wantAdded || result.Tracks[0].CadenceBPM != 128 {
+				t.Fatalf("unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestHandler_Queue(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	queue := []services.Option{services.WithQueue(store)}
+
+	// Cases run in order against the same store.
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantTitles string
+	}{
+		{name: "disabled", method: http.MethodGet, path: "/queue", wantStatus: http.StatusNotImplemented},
+		{name: "starts empty", opts: queue, method: http.MethodGet, path: "/queue", wantStatus: http.StatusOK, wantTitles: ""},
+		{name: "pop empty", opts: queue, method: http.MethodPost, path: "/queue/pop", wantStatus: http.StatusNotFound},
+		{name: "missing artist", opts: queue, method: http.MethodPost, path: "/queue", body: `{"title":"One"}`, wantStatus: http.StatusBadRequest},
+		{name: "appends", opts: queue, method: http.MethodPost, path: "/queue", body: `{"title":"One","artist":"A"}`, wantStatus: http.StatusOK, wantTitles: "One"},
+		{name: "appends again", opts: queue, method: http.MethodPost, path: "/queue", body: `{"title":"Two","artist":"B"}`, wantStatus: http.StatusOK, wantTitles: "One,Two"},
+		{name: "inserts next", opts: queue, method: http.MethodPost, path: "/queue", body: `{"title":"Zero","artist":"C","next":true}`, wantStatus: http.StatusOK, wantTitles: "Zero,One,Two"},
+		{name: "pops", opts: queue, method: http.MethodPost, path: "/queue/pop", wantStatus: http.StatusOK},
+		{name: "reads remaining", opts: queue, method: http.MethodGet, path: "/queue", wantStatus: http.StatusOK, wantTitles: "One,Two"},
+		{name: "clears", opts: queue, method: http.MethodDelete, path: "/queue", wantStatus: http.StatusNoContent},
+		{name: "reads cleared", opts: queue, method: http.MethodGet, path: "/queue", wantStatus: http.StatusOK, wantTitles: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if tt.path == "/queue/pop" {
+				var track domain.Track
+				if err := json.NewDecoder(rec.Body).Decode(&track); err != nil || track.Title != "Zero" {
+					t.Fatalf("expected to pop Zero, got %+v (%v)", track, err)
+				}
+				return
+			}
+			var q domain.Queue
+			if err := json.NewDecoder(rec.Body).Decode(&q); err != nil {
+				t.Fatalf("decode queue: %v", err)
+			}
+			titles := make([]string, len(q.Tracks))
+			for i, tr := range q.Tracks {
+				titles[i] = tr.Title
+			}
+			if got := strings.Join(titles, ","); got != tt.wantTitles {
+				t.Fatalf("expected queue %q, got %q", tt.wantTitles, got)
+			}
+		})
+	}
+}
+
+// fakeWeather reports rain everywhere.
+type fakeWeather struct{}
+
+func (fakeWeather) CurrentWeather(ctx context.Context, latitude, longitude float64) (domain.Weather, error) {
+	return domain.Weather{Condition: domain.WeatherRain}, nil
+}
+
+func TestHandler_WeatherSettings(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	weather := []services.Option{services.WithWeather(fakeWeather{}, store)}
+
+	// Cases run in order against the same store.
+	tests := []struct {
+		name        string
+		opts        []services.Option
+		method      string
+		body        string
+		wantStatus  int
+		wantEnabled bool
+	}{
+		{name: "disabled", method: http.MethodGet, wantStatus: http.StatusNotImplemented},
+		{name: "off by default", opts: weather, method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "enabled without location", opts: weather, method: http.MethodPut, body: `{"enabled":true}`, wantStatus: http.StatusBadRequest},
+		{name: "latitude out of range", opts: weather, method: http.MethodPut, body: `{"enabled":true,"latitude":95,"longitude":0}`, wantStatus: http.StatusBadRequest},
+		{name: "bad body", opts: weather, method: http.MethodPut, body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "enables", opts: weather, method: http.MethodPut, body: `{"enabled":true,"latitude":47.6,"longitude":-122.3}`, wantStatus: http.StatusOK, wantEnabled: true},
+		{name: "reads back", opts: weather, method: http.MethodGet, wantStatus: http.StatusOK, wantEnabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(tt.method, "/settings/weather", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var settings domain.WeatherSettings
+			if err := json.NewDecoder(rec.Body).Decode(&settings); err != nil {
+				t.Fatalf("decode settings: %v", err)
+			}
+			if settings.Enabled != tt.wantEnabled || (tt.wantEnabled && settings.Latitude != 47.6) {
+				t.Fatalf("unexpected settings %+v", settings)
+			}
+		})
+	}
+}
+
+func TestHandler_PlaylistSettings(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	if err := store.Save(context.Background(), domain.Playlist{ID: "pl-1", Name: "Family"}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	settings := []services.Option{services.WithPlaylistSettings(store)}
+
+	// Cases run in order against the same store.
+	tests := []struct {
+		name              string
+		opts              []services.Option
+		method            string
+		path              string
+		body              string
+		wantStatus        int
+		wantAllowExplicit bool
+	}{
+		{name: "defaults without a store", method: http.MethodGet, path: "/playlists/pl-1/settings", wantStatus: http.StatusOK, wantAllowExplicit: true},
+		{name: "patch without a store", method: http.MethodPatch, path: "/playlists/pl-1/settings", body: `{}`, wantStatus: http.StatusNotImplemented},
+		{name: "unknown playlist", opts: settings, method: http.MethodGet, path: "/playlists/nope/settings", wantStatus: http.StatusNotFound},
+		{name: "bad body", opts: settings, method: http.MethodPatch, path: "/playlists/pl-1/settings", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "disallows explicit", opts: settings, method: http.MethodPatch, path: "/playlists/pl-1/settings", body: `{"allow_explicit":false}`, wantStatus: http.StatusOK},
+		{name: "empty patch keeps it", opts: settings, method: http.MethodPatch, path: "/playlists/pl-1/settings", body: `{}`, wantStatus: http.StatusOK},
+		{name: "reads back", opts: settings, method: http.MethodGet, path: "/playlists/pl-1/settings", wantStatus: http.StatusOK},
+		{name: "rejects explicit tracks", opts: settings, method: http.MethodPost, path: "/playlists/pl-1/tracks", body: `{"title":"Song","artist":"Artist"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "rejects a bad market", opts: settings, method: http.MethodPatch, path: "/playlists/pl-1/settings", body: `{"default_market":"usa"}`, wantStatus: http.StatusBadRequest},
+		{name: "locks ordering", opts: settings, method: http.MethodPatch, path: "/playlists/pl-1/settings", body: `{"locked_ordering":true,"default_market":"gb"}`, wantStatus: http.StatusOK},
+		{name: "rejects reordering", opts: settings, method: http.MethodPut, path: "/playlists/pl-1/tracks/order", body: `{"track_ids":[]}`, wantStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spotify := &mockSpotify{track: domain.Track{ID: "t1", Title: "Song", Artist: "Artist", Explicit: true}}
+			svc := services.NewOrchestrator(spotify, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusUnprocessableEntity && !strings.Contains(rec.Body.String(), errCodeExplicitTrack) {
+				t.Fatalf("expected code %s, got %s", errCodeExplicitTrack, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got domain.PlaylistSettings
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode settings: %v", err)
+			}
+			if got.AllowExplicit != tt.wantAllowExplicit {
+				t.Fatalf("unexpected settings %+v", got)
+			}
+		})
+	}
+}
+
+func TestHandler_AlsoAdded(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	for id, ids := range map[string][]string{"p1": {"a", "b", "c"}, "p2": {"a", "b"}} {
+		var tracks []domain.Track
+		for _, tid := range ids {
+			tracks = append(tracks, domain.Track{ID: tid, Title: tid, Artist: "Artist"})
+		}
+		if err := store.Save(ctx, domain.Playlist{ID: id, Name: id}); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		if err := store.AddTracksToPlaylist(ctx, id, tracks); err != nil {
+			t.Fatalf("add tracks: %v", err)
+		}
+	}
+	memberships, err := store.PlaylistMemberships(ctx)
+	if err != nil {
+		t.Fatalf("memberships: %v", err)
+	}
+	if err := store.ReplaceCooccurrence(ctx, domain.BuildCooccurrence(memberships, 0)); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	cooccurrence := []services.Option{services.WithCooccurrence(store, store)}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		path       string
+		wantStatus int
+		wantIDs    []string
+	}{
+		{name: "not configured", path: "/tracks/a/also-added", wantStatus: http.StatusNotImplemented},
+		{name: "best first", opts: cooccurrence, path: "/tracks/a/also-added", wantStatus: http.StatusOK, wantIDs: []string{"b", "c"}},
+		{name: "limit", opts: cooccurrence, path: "/tracks/a/also-added?limit=1", wantStatus: http.StatusOK, wantIDs: []string{"b"}},
+		{name: "bad limit", opts: cooccurrence, path: "/tracks/a/also-added?limit=100", wantStatus: http.StatusBadRequest},
+		{name: "unknown track", opts: cooccurrence, path: "/tracks/zzz/also-added", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var recs []domain.Recommendation
+			if err := json.NewDecoder(rec.Body).Decode(&recs); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			var ids []string
+			for _, r := range recs {
+				ids = append(ids, r.Track.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Fatalf("expected %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+func TestHandler_TrackLibrary(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Save(ctx, domain.Playlist{ID: "p1", Name: "Mix", Tracks: []domain.Track{
+		{ID: "a", Title: "Calm", Artist: "Nils Frahm", Features: domain.AudioFeatures{Energy: 0.2, Tempo: 80}},
+		{ID: "b", Title: "Loud", Artist: "Daft Punk", Features: domain.AudioFeatures{Energy: 0.9, Tempo: 124}},
+		{ID: "c", Title: "Around", Artist: "Daft Punk", Features: domain.AudioFeatures{Energy: 0.8, Tempo: 121}},
+	}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	library := []services.Option{services.WithTrackLibrary(store)}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		path       string
+		wantStatus int
+		wantIDs    []string
+		wantTotal  int
+	}{
+		{name: "not configured", path: "/tracks", wantStatus: http.StatusNotImplemented},
+		{name: "every track by artist and title", opts: library, path: "/tracks", wantStatus: http.StatusOK, wantIDs: []string{"c", "b", "a"}, wantTotal: 3},
+		{name: "by artist", opts: library, path: "/tracks?artist=daft", wantStatus: http.StatusOK, wantIDs: []string{"c", "b"}, wantTotal: 2},
+		{name: "by feature range", opts: library, path: "/tracks?min_energy=0.5&max_tempo=122", wantStatus: http.StatusOK, wantIDs: []string{"c"}, wantTotal: 1},
+		{name: "paged", opts: library, path: "/tracks?limit=1&offset=1", wantStatus: http.StatusOK, wantIDs: []string{"b"}, wantTotal: 3},
+		{name: "bad offset", opts: library, path: "/tracks?offset=-1", wantStatus: http.StatusBadRequest},
+		{name: "bad bound", opts: library, path: "/tracks?min_energy=high", wantStatus: http.StatusBadRequest},
+		{name: "empty range", opts: library, path: "/tracks?min_energy=0.9&max_energy=0.1", wantStatus: http.StatusBadRequest},
+		{name: "one track", opts: library, path: "/tracks/b", wantStatus: http.StatusOK, wantIDs: []string{"b"}},
+		{name: "unknown track", opts: library, path: "/tracks/zzz", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var page domain.TrackPage
+			if strings.HasPrefix(tt.path, "/tracks/") {
+				var track domain.Track
+				if err := json.NewDecoder(rec.Body).Decode(&track); err != nil {
+					t.Fatalf("decode: %v", err)
+				}
+				page = domain.TrackPage{Tracks: []domain.Track{track}}
+			} else if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			var ids []string
+			for _, tr := range page.Tracks {
+				ids = append(ids, tr.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") || page.Total != tt.wantTotal {
+				t.Fatalf("expected %v of %d, got %v of %d", tt.wantIDs, tt.wantTotal, ids, page.Total)
+			}
+		})
+	}
+}
+
+func TestHandler_Mood(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	mood := []services.Option{services.WithMood(store)}
+
+	// Cases run in order against the same store.
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "disabled", method: http.MethodPost, path: "/me/mood", body: `{"emoji":"😀"}`, wantStatus: http.StatusNotImplemented},
+		{name: "empty history", opts: mood, method: http.MethodGet, path: "/me/mood", wantStatus: http.StatusOK, wantBody: `{"check_ins":[]}`},
+		{name: "unknown emoji", opts: mood, method: http.MethodPost, path: "/me/mood", body: `{"emoji":"🦖"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing energy", opts: mood, method: http.MethodPost, path: "/me/mood", body: `{"valence":0.5}`, wantStatus: http.StatusBadRequest},
+		{name: "values", opts: mood, method: http.MethodPost, path: "/me/mood", body: `{"valence":0.2,"energy":0.4}`, wantStatus: http.StatusCreated, wantBody: `"valence":0.2`},
+		{name: "emoji", opts: mood, method: http.MethodPost, path: "/me/mood", body: `{"emoji":"😴"}`, wantStatus: http.StatusCreated, wantBody: `"energy":0.1`},
+		{name: "history with trend", opts: mood, method: http.MethodGet, path: "/me/mood?days=1", wantStatus: http.StatusOK, wantBody: `"check_ins":2`},
+		{name: "bad days", opts: mood, method: http.MethodGet, path: "/me/mood?days=0", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_SimilarPlaylists(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	calm := domain.AudioFeatures{Energy: 0.2, Acousticness: 0.9}
+	loud := domain.AudioFeatures{Energy: 0.9, Danceability: 0.8}
+	for _, p := range []domain.Playlist{
+		{ID: "p", Name: "Evening", Tracks: []domain.Track{{ID: "t1", Title: "One", Artist: "A", Features: calm}}},
+		{ID: "twin", Name: "Night", Tracks: []domain.Track{{ID: "t2", Title: "Two", Artist: "A", Features: calm}}},
+		{ID: "loud", Name: "Gym", Tracks: []domain.Track{{ID: "t3", Title: "Three", Artist: "B", Features: loud}}},
+	} {
+		if err := store.Save(context.Background(), p); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantIDs    []string
+	}{
+		{name: "closest first", path: "/playlists/p/similar", wantStatus: http.StatusOK, wantIDs: []string{"twin", "loud"}},
+		{name: "limit", path: "/playlists/p/similar?limit=1", wantStatus: http.StatusOK, wantIDs: []string{"twin"}},
+		{name: "bad limit", path: "/playlists/p/similar?limit=51", wantStatus: http.StatusBadRequest},
+		{name: "unknown playlist", path: "/playlists/missing/similar", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(services.NewOrchestrator(&mockSpotify{}, store, nil), nil)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var similar []domain.SimilarPlaylist
+			if err := json.NewDecoder(rec.Body).Decode(&similar); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			var ids []string
+			for _, s := range similar {
+				ids = append(ids, s.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Fatalf("expected %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+func TestHandler_Discovery(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	if err := store.Save(context.Background(), domain.Playlist{
+		ID:     "pl-1",
+		Name:   "Road Trip",
+		Tracks: []domain.Track{{ID: "t1", Title: "One", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	discovery := []services.Option{services.WithDiscovery(store)}
+
+	// Cases run in order against the same store.
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "not configured", method: http.MethodGet, path: "/discover", wantStatus: http.StatusNotImplemented},
+		{name: "nothing public", opts: discovery, method: http.MethodGet, path: "/discover", wantStatus: http.StatusOK, wantBody: `{"sort":"recent","playlists":[]}`},
+		{name: "copy private", opts: discovery, method: http.MethodPost, path: "/playlists/pl-1/copy", body: `{}`, wantStatus: http.StatusNotFound},
+		{name: "publish unknown", opts: discovery, method: http.MethodPut, path: "/playlists/missing/public", wantStatus: http.StatusNotFound},
+		{name: "publish", opts: discovery, method: http.MethodPut, path: "/playlists/pl-1/public", wantStatus: http.StatusOK, wantBody: `"owner":"default"`},
+		{name: "copy", opts: discovery, method: http.MethodPost, path: "/playlists/pl-1/copy", body: `{"name":"Mine"}`, wantStatus: http.StatusCreated, wantBody: `"name":"Mine"`},
+		{name: "most copied", opts: discovery, method: http.MethodGet, path: "/discover?sort=copied", wantStatus: http.StatusOK, wantBody: `"copies":1`},
+		{name: "bad sort", opts: discovery, method: http.MethodGet, path: "/discover?sort=popular", wantStatus: http.StatusBadRequest},
+		{name: "bad limit", opts: discovery, method: http.MethodGet, path: "/discover?limit=0", wantStatus: http.StatusBadRequest},
+		{name: "unpublish", opts: discovery, method: http.MethodDelete, path: "/playlists/pl-1/public", wantStatus: http.StatusNoContent},
+		{name: "copy after unpublish", opts: discovery, method: http.MethodPost, path: "/playlists/pl-1/copy", body: `{}`, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_Revisions(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Save(ctx, domain.Playlist{
+		ID:     "pl-1",
+		Name:   "Road Trip",
+		Tracks: []domain.Track{{ID: "t1", Title: "One", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := store.AddTracksToPlaylist(ctx, "pl-1", []domain.Track{{ID: "t2", Title: "Two", Artist: "Artist"}}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	revisions := []services.Option{services.WithRevisions(store)}
+
+	// Cases run in order against the same store.
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "not configured", method: http.MethodGet, path: "/playlists/pl-1/revisions", wantStatus: http.StatusNotImplemented},
+		{name: "list", opts: revisions, method: http.MethodGet, path: "/playlists/pl-1/revisions", wantStatus: http.StatusOK, wantBody: `"rev":2,"reason":"add_tracks","track_ids":["t1","t2"]`},
+		{name: "list unknown", opts: revisions, method: http.MethodGet, path: "/playlists/missing/revisions", wantStatus: http.StatusNotFound},
+		{name: "revert", opts: revisions, method: http.MethodPost, path: "/playlists/pl-1/revert/1", wantStatus: http.StatusOK, wantBody: `"tracks":[{"id":"t1"`},
+		{name: "revert recorded", opts: revisions, method: http.MethodGet, path: "/playlists/pl-1/revisions?limit=1", wantStatus: http.StatusOK, wantBody: `{"revisions":[{"rev":3,"reason":"revert","reverted_to":1`},
+		{name: "unknown revision", opts: revisions, method: http.MethodPost, path: "/playlists/pl-1/revert/9", wantStatus: http.StatusNotFound},
+		{name: "bad revision", opts: revisions, method: http.MethodPost, path: "/playlists/pl-1/revert/zero", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ExportPlaylist(t *testing.T) {
+	playlist := domain.Playlist{
+		ID:   "pl-1",
+		Name: "Road Trip: 2024",
+		Tracks: []domain.Track{
+			{ID: "sp1", Title: "One, Two", Artist: "Artist", DurationMs: 181000, ISRC: "USRC17607839"},
+			{ID: "apple_music:9", Title: "Three", Artist: "Other", DurationMs: 200000, ISRC: "GBAYE0601498", Source: domain.SourceAppleMusic},
+		},
+	}
+	tests := []struct {
+		name            string
+		query           string
+		err             error
+		wantStatus      int
+		wantType        string
+		wantDisposition string
+		wantBody        string
+	}{
+		{
+			name: "json by default", wantStatus: http.StatusOK, wantType: "application/json",
+			wantDisposition: `attachment; filename="Road-Trip-2024.json"`,
+			wantBody:        `"spotify_id": "sp1"`,
+		},
+		{
+			name: "csv", query: "?format=csv", wantStatus: http.StatusOK, wantType: "text/csv",
+			wantDisposition: `attachment; filename="Road-Trip-2024.csv"`,
+			wantBody:        "isrc,spotify_id,title,artist,duration_ms\nUSRC17607839,sp1,\"One, Two\",Artist,181000\nGBAYE0601498,,Three,Other,200000\n",
+		},
+		{
+			name: "m3u", query: "?format=M3U", wantStatus: http.StatusOK, wantType: "audio/x-mpegurl",
+			wantDisposition: `attachment; filename="Road-Trip-2024.m3u"`,
+			wantBody:        "#EXTM3U\n#PLAYLIST:Road Trip: 2024\n#EXTINF:181,Artist - One, Two\nhttps://open.spotify.com/track/sp1\n#EXTINF:200,Other - Three\nisrc:GBAYE0601498\n",
+		},
+		{name: "unknown format", query: "?format=xspf", wantStatus: http.StatusBadRequest},
+		{name: "unknown playlist", err: domain.ErrNotFound, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&fakeService{playlist: playlist, err: tt.err}, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/playlists/pl-1/export"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type: got %q, want %q", got, tt.wantType)
+			}
+			if got := rec.Header().Get("Content-Disposition"); got != tt.wantDisposition {
+				t.Errorf("Content-Disposition: got %q, want %q", got, tt.wantDisposition)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("expected body to contain %q, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+// stubAuthorizer grants every code without talking to Spotify.
+type stubAuthorizer struct{}
+
+func (stubAuthorizer) AuthCodeURL(state string) (string, string) {
+	return "https://accounts.example.com/authorize?state=" + state, "verifier"
+}
+
+func (stubAuthorizer) Exchange(ctx context.Context, code, verifier string) (domain.OAuthToken, error) {
+	return domain.OAuthToken{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func (stubAuthorizer) Library(token domain.OAuthToken, onRefresh func(domain.OAuthToken)) ports.SpotifyLibrary {
+	return stubLibrary{}
+}
+
+// stubLibrary accepts every write to a playlist it names "sp-" + name.
+type stubLibrary struct{}
+
+func (stubLibrary) CreatePlaylist(ctx context.Context, name string) (string, error) {
+	return "sp-" + name, nil
+}
+
+func (stubLibrary) AddTracks(ctx context.Context, spotifyPlaylistID string, tracks []domain.Track) error {
+	return nil
+}
+
+func (stubLibrary) ReplaceTracks(ctx context.Context, spotifyPlaylistID string, tracks []domain.Track) (string, int, error) {
+	return "snap-1", len(tracks), nil
+}
+
+func TestHandler_SpotifyAuth(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	auth := []services.Option{services.WithSpotifyAuth(stubAuthorizer{}, store)}
+	serve := func(opts []services.Option, path string) *httptest.ResponseRecorder {
+		h := NewHandler(services.NewOrchestrator(&mockSpotify{}, store, nil, opts...), nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if rec := serve(nil, "/auth/spotify/login"); rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected 501 without spotify auth, got %d", rec.Code)
+	}
+	rec := serve(auth, "/auth/spotify/login")
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected a redirect, got %d: %s", rec.Code, rec.Body.String())
+	}
+	location := rec.Header().Get("Location")
+	state := strings.TrimPrefix(location, "https://accounts.example.com/authorize?state=")
+	if state == location || state == "" {
+		t.Fatalf("unexpected redirect to %q", location)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "declined", path: "/auth/spotify/callback?error=access_denied&state=" + state, wantStatus: http.StatusForbidden, wantBody: "SPOTIFY_AUTH_DENIED"},
+		{name: "missing code", path: "/auth/spotify/callback?state=" + state, wantStatus: http.StatusBadRequest},
+		{name: "unknown state", path: "/auth/spotify/callback?state=forged&code=c", wantStatus: http.StatusBadRequest, wantBody: "INVALID_AUTH_STATE"},
+		{name: "connected", path: "/auth/spotify/callback?state=" + state + "&code=c", wantStatus: http.StatusOK, wantBody: `{"connected":true}`},
+		{name: "replayed", path: "/auth/spotify/callback?state=" + state + "&code=c", wantStatus: http.StatusBadRequest, wantBody: "INVALID_AUTH_STATE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(auth, tt.path)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+	if token, err := store.GetSpotifyToken(context.Background(), domain.DefaultOwner); err != nil || token.RefreshToken != "refresh" {
+		t.Fatalf("expected the token to be stored, got %+v (%v)", token, err)
+	}
+}
+
+func TestHandler_SyncPlaylist(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: []domain.Track{
+		{ID: "t1", Title: "One", Artist: "A"}, {ID: "applemusic:2", Title: "Two", Artist: "B", Source: domain.SourceAppleMusic},
+	}}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	auth := []services.Option{services.WithSpotifyAuth(stubAuthorizer{}, store)}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		connect    bool
+		playlistID string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "disabled", playlistID: "pl-1", wantStatus: http.StatusNotImplemented},
+		{name: "not connected", opts: auth, playlistID: "pl-1", wantStatus: http.StatusConflict, wantBody: "SPOTIFY_NOT_CONNECTED"},
+		{name: "unknown playlist", opts: auth, connect: true, playlistID: "missing", wantStatus: http.StatusNotFound},
+		{name: "syncs", opts: auth, connect: true, playlistID: "pl-1", wantStatus: http.StatusOK, wantBody: `"snapshot_id":"snap-1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.connect {
+				if err := store.SaveSpotifyToken(ctx, domain.DefaultOwner, domain.OAuthToken{AccessToken: "access"}); err != nil {
+					t.Fatalf("save token: %v", err)
+				}
+			}
+			h := NewHandler(services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...), nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/playlists/"+tt.playlistID+"/sync", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %s", tt.wantBody, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var result domain.PlaylistSync
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("decode result: %v", err)
+			}
+			if !result.Created || result.SpotifyID != "sp-Mix" || result.Synced != 1 || result.Skipped != 1 {
+				t.Fatalf("unexpected result %+v", result)
+			}
+		})
+	}
+	if link, err := store.GetSpotifyPlaylistLink(ctx, "pl-1"); err != nil || link != "sp-Mix" {
+		t.Fatalf("expected the mirror to be linked, got %q (%v)", link, err)
+	}
+}
+
+// fakeCalendar returns events for any window.
+type fakeCalendar []domain.CalendarEvent
+
+func (f fakeCalendar) Events(ctx context.Context, from, to time.Time) ([]domain.CalendarEvent, error) {
+	return f, nil
+}
+
+func TestHandler_Focus(t *testing.T) {
+	now := time.Now()
+	focus := fakeCalendar{{Summary: "Deep work", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}}
+	meeting := fakeCalendar{{Summary: "Planning", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}}
+
+	tests := []struct {
+		name       string
+		calendar   fakeCalendar
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "status not configured", method: http.MethodGet, path: "/focus", wantStatus: http.StatusNotImplemented},
+		{name: "status active", calendar: focus, method: http.MethodGet, path: "/focus", wantStatus: http.StatusOK, wantBody: `"summary":"Deep work"`},
+		{name: "status idle", calendar: meeting, method: http.MethodGet, path: "/focus", wantStatus: http.StatusOK, wantBody: `{"active":false}`},
+		{name: "trigger during focus", calendar: focus, method: http.MethodPost, path: "/playlists/pl-1/focus", body: `{"message":"coding music"}`, wantStatus: http.StatusOK, wantBody: `"focus":true`},
+		{name: "trigger outside focus", calendar: meeting, method: http.MethodPost, path: "/playlists/pl-1/focus", body: `{"message":"coding music"}`, wantStatus: http.StatusConflict, wantBody: "NO_FOCUS_BLOCK"},
+		{name: "forced trigger", calendar: meeting, method: http.MethodPost, path: "/playlists/pl-1/focus", body: `{"message":"coding music","force":true}`, wantStatus: http.StatusOK, wantBody: `"instrumentalness"`},
+		{name: "trigger without message", calendar: focus, method: http.MethodPost, path: "/playlists/pl-1/focus", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "trigger not configured", method: http.MethodPost, path: "/playlists/pl-1/focus", body: `{"message":"coding music"}`, wantStatus: http.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intent := domain.IntentObject{}
+			intent.Entities.Artists = []string{"Nils Frahm"}
+			var opts []services.Option
+			if tt.calendar != nil {
+				opts = append(opts, services.WithFocus(tt.calendar, nil))
+			}
+			svc := services.NewOrchestrator(&mockSpotify{}, &mockRepo{}, &mockIntentCompiler{intent: intent}, opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+// fakePlayer is a playback controller that fails every command with err.
+type fakePlayer struct{ err error }
+
+func (f fakePlayer) Play(ctx context.Context, tracks []domain.Track, offset int) error { return f.err }
+
+func (f fakePlayer) Pause(ctx context.Context) error { return f.err }
+
+func (f fakePlayer) Resume(ctx context.Context) error { return f.err }
+
+func (f fakePlayer) Skip(ctx context.Context) error { return f.err }
+
+func (f fakePlayer) Queue(ctx context.Context, track domain.Track) error { return f.err }
+
+func TestHandler_Player(t *testing.T) {
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: []domain.Track{{ID: "t1", Title: "One", Artist: "A"}, {ID: "t2", Title: "Two", Artist: "B"}}}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	control := []services.Option{services.WithPlaybackController(fakePlayer{}), services.WithPlayback(store)}
+	noDevice := []services.Option{services.WithPlaybackController(fakePlayer{err: domain.ErrNoActiveDevice})}
+
+	tests := []struct {
+		name       string
+		opts       []services.Option
+		path       string
+		body       string
+		wantStatus int
+		wantTrack  string
+	}{
+		{name: "disabled", path: "/player/pause", wantStatus: http.StatusNotImplemented},
+		{name: "play disabled", path: "/playlists/pl-1/play", wantStatus: http.StatusNotImplemented},
+		{name: "plays from the top", opts: control, path: "/playlists/pl-1/play", wantStatus: http.StatusOK, wantTrack: "t1"},
+		{name: "plays from a track", opts: control, path: "/playlists/pl-1/play", body: `{"track_id":"t2"}`, wantStatus: http.StatusOK, wantTrack: "t2"},
+		{name: "bad body", opts: control, path: "/playlists/pl-1/play", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "unknown playlist", opts: control, path: "/playlists/missing/play", wantStatus: http.StatusNotFound},
+		{name: "start track not in playlist", opts: control, path: "/playlists/pl-1/play", body: `{"track_id":"t9"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "no active device", opts: noDevice, path: "/playlists/pl-1/play", wantStatus: http.StatusConflict},
+		{name: "queues", opts: control, path: "/playlists/pl-1/queue", body: `{"track_id":"t2"}`, wantStatus: http.StatusNoContent},
+		{name: "queue missing track", opts: control, path: "/playlists/pl-1/queue", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "queue track not in playlist", opts: control, path: "/playlists/pl-1/queue", body: `{"track_id":"t9"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "pauses", opts: control, path: "/player/pause", wantStatus: http.StatusNoContent},
+		{name: "resumes", opts: control, path: "/player/resume", wantStatus: http.StatusNoContent},
+		{name: "skips", opts: control, path: "/player/next", wantStatus: http.StatusNoContent},
+		{name: "skip without device", opts: noDevice, path: "/player/next", wantStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewOrchestrator(&mockSpotify{}, store, nil, tt.opts...)
+			h := NewHandler(svc, nil)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantTrack == "" {
+				return
+			}
+			var state domain.PlaybackState
+			if err := json.NewDecoder(rec.Body).Decode(&state); err != nil {
+				t.Fatalf("decode state: %v", err)
+			}
+			if state.PlaylistID != "pl-1" || state.TrackID != tt.wantTrack || !state.Playing {
+				t.Fatalf("unexpected state %+v", state)
+			}
+		})
+	}
+}
+
+type fakeService struct {
+	playlist domain.Playlist
+	err      error
+	calledID string
+	// recommendations, when set, enables recommendations.
+	recommendations []domain.PlaylistRecommendation
+}
+
+func (f *fakeService) CreatePlaylist(ctx context.Context, name string) (domain.Playlist, error) {
+	return f.playlist, f.err
+}
+
+func (f *fakeService) GetPlaylist(ctx context.Context, playlistID string) (domain.Playlist, error) {
+	f.calledID = playlistID
+	return f.playlist, f.err
+}
+
+func (f *fakeService) SimilarPlaylists(ctx context.Context, playlistID string, limit int) ([]domain.SimilarPlaylist, error) {
+	return nil, f.err
+}
+
+func (f *fakeService) GetPlaylistAnalysis(ctx context.Context, playlistID string) (domain.AudioFeatures, error) {
+	return domain.AudioFeatures{}, f.err
+}
+
+func (f *fakeService) PlaylistArc(ctx context.Context, playlistID string, window int) (domain.PlaylistArc, error) {
+	if f.err != nil {
+		return domain.PlaylistArc{}, f.err
+	}
+	return f.playlist.Arc(window), nil
+}
+
+func (f *fakeService) GetPlaylistAnalysisDetail(ctx context.Context, playlistID string) (domain.PlaylistAnalysis, error) {
+	if f.err != nil {
+		return domain.PlaylistAnalysis{}, f.err
+	}
+	return f.playlist.AnalyzeDetail(), nil
+}
+
+func (f *fakeService) AddTrackToPlaylist(ctx context.Context, playlistID, title, artist string) (string, string, string, error) {
+	return playlistID, "", "", f.err
+}
+
+func (f *fakeService) ReorderTracks(ctx context.Context, playlistID string, trackIDs []string) (domain.Playlist, error) {
+	return f.playlist, f.err
+}
+
+func (f *fakeService) HasPlaylistSettings() bool { return false }
+
+func (f *fakeService) GetPlaylistSettings(ctx context.Context, playlistID string) (domain.PlaylistSettings, error) {
+	return domain.DefaultPlaylistSettings(playlistID), f.err
+}
+
+func (f *fakeService) UpdatePlaylistSettings(ctx context.Context, settings domain.PlaylistSettings) (domain.PlaylistSettings, error) {
+	return settings, f.err
+}
+
+func (f *fakeService) HasRevisions() bool { return false }
+
+func (f *fakeService) ListRevisions(ctx context.Context, playlistID string, limit int) ([]domain.PlaylistRevision, error) {
+	return nil, f.err
+}
+
+func (f *fakeService) RevertPlaylist(ctx context.Context, playlistID string, rev int) (domain.Playlist, error) {
+	return f.playlist, f.err
+}
+
+func (f *fakeService) HasIntentCompiler() bool { return false }
+
+func (f *fakeService) ProcessIntent(ctx context.Context, playlistID, message string) (domain.IntentResult, error) {
+	return domain.IntentResult{}, f.err
+}
+
+func (f *fakeService) ProcessIntentWithDuration(ctx context.Context, playlistID, message string, target domain.DurationTarget) (domain.IntentResult, error) {
+	return domain.IntentResult{}, f.err
+}
+
+func (f *fakeService) ProcessIntentStream(ctx context.Context, playlistID, message string, opts domain.IntentOptions, onDelta func(domain.IntentDelta)) (domain.IntentResult, error) {
+	return domain.IntentResult{}, f.err
+}
+
+func (f *fakeService) GenerateRunningPlaylist(ctx context.Context, playlistID string, tmpl domain.RunningTemplate) (domain.RunningResult, error) {
+	return domain.RunningResult{}, f.err
+}
+
+func (f *fakeService) HasIntentRuns() bool { return false }
+
+func (f *fakeService) ReplayIntentRuns(ctx context.Context, limit int) (domain.ReplayReport, error) {
+	return domain.ReplayReport{}, f.err
+}
+
+func (f *fakeService) ExperimentReport(ctx context.Context, limit int) (domain.ExperimentReport, error) {
+	return domain.ExperimentReport{}, f.err
+}
+
+func (f *fakeService) IntentAnalytics(ctx context.Context, limit int) (domain.IntentAnalytics, error) {
+	return domain.IntentAnalytics{}, f.err
+}
+
+func (f *fakeService) HasLyrics() bool { return false }
+
+func (f *fakeService) GetTrackLyrics(ctx context.Context, trackID string) (domain.Lyrics, error) {
+	return domain.Lyrics{}, f.err
+}
+
+func (f *fakeService) HasPodcasts() bool { return false }
+
+func (f *fakeService) SearchEpisodes(ctx context.Context, query string, limit int) ([]domain.Episode, error) {
+	return nil, f.err
+}
+
+func (f *fakeService) AddEpisodeToPlaylist(ctx context.Context, playlistID, query string, latest bool) (domain.Episode, error) {
+	return domain.Episode{}, f.err
+}
+
+func (f *fakeService) HasAlbums() bool { return false }
+
+func (f *fakeService) HasRecommendations() bool { return f.recommendations != nil }
+
+func (f *fakeService) Recommendations(ctx context.Context, playlistID string, limit int) ([]domain.PlaylistRecommendation, error) {
+	f.calledID = playlistID
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.recommendations[:min(limit, len(f.recommendations))], nil
+}
+
+func (f *fakeService) AddAlbumToPlaylist(ctx context.Context, playlistID, title, artist string) (domain.AlbumAddition, error) {
+	return domain.AlbumAddition{}, f.err
+}
+
+func (f *fakeService) HasPlaylistImport() bool { return false }
+
+func (f *fakeService) ImportPlaylist(ctx context.Context, ref, name string) (domain.PlaylistImport, error) {
+	return domain.PlaylistImport{}, f.err
+}
+
+func (f *fakeService) HasPlayback() bool { return false }
+
+func (f *fakeService) GetPlayback(ctx context.Context, playlistID string) (domain.PlaybackState, error) {
+	return domain.PlaybackState{}, f.err
+}
+
+func (f *fakeService) UpdatePlayback(ctx context.Context, state domain.PlaybackState) (domain.PlaybackState, error) {
+	return domain.PlaybackState{}, f.err
+}
+
+func (f *fakeService) HasPlaybackControl() bool { return false }
+
+func (f *fakeService) PlayPlaylist(ctx context.Context, playlistID, startTrackID string) (domain.PlaybackState, error) {
+	return domain.PlaybackState{}, f.err
+}
+
+func (f *fakeService) PausePlayback(ctx context.Context) error { return f.err }
+
+func (f *fakeService) ResumePlayback(ctx context.Context) error { return f.err }
+
+func (f *fakeService) SkipTrack(ctx context.Context) error { return f.err }
+
+func (f *fakeService) QueueTrack(ctx context.Context, playlistID, trackID string) error { return f.err }
+
+func (f *fakeService) HasQueue() bool { return false }
+
+func (f *fakeService) GetQueue(ctx context.Context, owner string) (domain.Queue, error) {
+	return domain.Queue{}, f.err
+}
+
+func (f *fakeService) AddToQueue(ctx context.Context, owner, title, artist string, next bool) (domain.Queue, error) {
+	return domain.Queue{}, f.err
+}
+
+func (f *fakeService) PopQueue(ctx context.Context, owner string) (domain.Track, error) {
+	return domain.Track{}, f.err
+}
+
+func (f *fakeService) ClearQueue(ctx context.Context, owner string) error { return f.err }
+
+func (f *fakeService) HasWeather() bool { return false }
+
+func (f *fakeService) HasFocus() bool { return false }
+
+func (f *fakeService) HasMood() bool { return false }
+
+func (f *fakeService) HasCooccurrence() bool { return false }
+
+func (f *fakeService) HasTrackLibrary() bool { return false }
+
+func (f *fakeService) ListTracks(ctx context.Context, filter domain.TrackFilter) (domain.TrackPage, error) {
+	return domain.TrackPage{}, f.err
+}
+
+func (f *fakeService) GetTrack(ctx context.Context, trackID string) (domain.Track, error) {
+	return domain.Track{}, f.err
+}
+
+func (f *fakeService) HasDiscovery() bool { return false }
+
+func (f *fakeService) PublishPlaylist(ctx context.Context, playlistID, owner string) (domain.PublicPlaylist, error) {
+	return domain.PublicPlaylist{}, f.err
+}
+
+func (f *fakeService) UnpublishPlaylist(ctx context.Context, playlistID string) error {
+	return f.err
+}
+
+func (f *fakeService) Discover(ctx context.Context, sort domain.DiscoverSort, limit int) ([]domain.PublicPlaylist, error) {
+	return nil, f.err
+}
+
+func (f *fakeService) CopyPlaylist(ctx context.Context, playlistID, name string) (domain.Playlist, error) {
+	return domain.Playlist{}, f.err
+}
+
+func (f *fakeService) HasSpotifyAuth() bool { return false }
+
+func (f *fakeService) StartSpotifyLogin(ctx context.Context, owner string) (string, error) {
+	return "", f.err
+}
+
+func (f *fakeService) CompleteSpotifyLogin(ctx context.Context, state, code string) error {
+	return f.err
+}
+
+func (f *fakeService) SyncPlaylistToSpotify(ctx context.Context, owner, playlistID string) (domain.PlaylistSync, error) {
+	return domain.PlaylistSync{}, f.err
+}
+
+func (f *fakeService) AlsoAdded(ctx context.Context, trackID string, limit int) ([]domain.Recommendation, error) {
+	return nil, f.err
+}
+
+func (f *fakeService) RecordMood(ctx context.Context, owner string, report domain.MoodReport) (domain.MoodCheckIn, error) {
+	return domain.MoodCheckIn{}, f.err
+}
+
+func (f *fakeService) ListMoods(ctx context.Context, owner string, since time.Time) ([]domain.MoodCheckIn, error) {
+	return nil, f.err
+}
+
+func (f *fakeService) CurrentMood(ctx context.Context, owner string) (domain.MoodTrend, bool, error) {
+	return domain.MoodTrend{}, false, f.err
+}
+
+func (f *fakeService) CurrentFocusBlock(ctx context.Context) (domain.CalendarEvent, bool, error) {
+	return domain.CalendarEvent{}, false, f.err
+}
+
+func (f *fakeService) TriggerFocus(ctx context.Context, playlistID, message string, force bool) (domain.IntentResult, error) {
+	return domain.IntentResult{}, f.err
+}
+
+func (f *fakeService) GetWeatherSettings(ctx context.Context, owner string) (domain.WeatherSettings, error) {
+	return domain.WeatherSettings{}, f.err
+}
+
+func (f *fakeService) UpdateWeatherSettings(ctx context.Context, settings domain.WeatherSettings) (domain.WeatherSettings, error) {
+	return domain.WeatherSettings{}, f.err
+}
+
+func TestHandler_GetPlaylist_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		svc        *fakeService
+		wantStatus int
+	}{
+		{name: "found", svc: &fakeService{playlist: domain.Playlist{ID: "pl-1", Name: "Mine"}}, wantStatus: http.StatusOK},
+		{name: "not found", svc: &fakeService{err: fmt.Errorf("service: failed to get playlist: %w", domain.ErrNotFound)}, wantStatus: http.StatusNotFound},
+		{name: "unexpected error", svc: &fakeService{err: errors.New("db down")}, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.svc, nil)
+			req := httptest.NewRequest(http.MethodGet, "/playlists/pl-1", nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.svc.calledID != "pl-1" {
+				t.Fatalf("expected service call for pl-1, got %q", tt.svc.calledID)
+			}
+		})
+	}
+}
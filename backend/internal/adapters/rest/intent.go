@@ -3,15 +3,25 @@ package rest
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
-	"github.com/ewilliams-labs/overture/backend/internal/core/services"
 )
 
 type analyzeIntentRequest struct {
 	Message string `json:"message"`
+	// TargetDurationMs, when set, fills the playlist to this length
+	// (within ToleranceMs) rather than adding every matching track.
+	TargetDurationMs int `json:"target_duration_ms"`
+	ToleranceMs      int `json:"tolerance_ms"`
+	// ExpandSimilar adds up to this many similar artists per named artist
+	// to the candidate pool.
+	ExpandSimilar int `json:"expand_similar"`
+	// SessionID continues a conversation: the message refines the intents
+	// of the session's earlier messages.
+	SessionID string `json:"session_id"`
 }
 
 // sseStatus represents the status field in SSE events.
@@ -27,6 +37,50 @@ type sseComplete struct {
 	TracksEvaluated int                 `json:"tracks_evaluated"`
 	TracksAdded     int                 `json:"tracks_added"`
 	Summary         string              `json:"summary"`
+	// TargetDurationMs echoes the requested length; DurationMs is the
+	// playlist's length after the additions.
+	TargetDurationMs int `json:"target_duration_ms,omitempty"`
+	DurationMs       int `json:"duration_ms"`
+	// Daypart and Weather name the time-of-day preset and weather
+	// condition that filled in open vibe constraints, if any.
+	Daypart string `json:"daypart,omitempty"`
+	Weather string `json:"weather,omitempty"`
+	// Focus and Mood are set when a focus block and the recent mood trend
+	// filled in open vibe constraints.
+	Focus bool `json:"focus,omitempty"`
+	Mood  bool `json:"mood,omitempty"`
+	// SimilarArtists are the artists expand_similar added.
+	SimilarArtists []string `json:"similar_artists,omitempty"`
+	// Sequence is the pattern the added tracks were ordered by.
+	Sequence string `json:"sequence,omitempty"`
+	// SessionID echoes the request's; SessionHistory is how many earlier
+	// turns of the session the intent followed up on.
+	SessionID      string `json:"session_id,omitempty"`
+	SessionHistory int    `json:"session_history,omitempty"`
+	// Degraded is set when the intent was compiled by keyword rules
+	// because the intent compiler failed.
+	Degraded bool `json:"degraded,omitempty"`
+}
+
+func newSSEComplete(result domain.IntentResult) sseComplete {
+	return sseComplete{
+		Status:           "complete",
+		Data:             result.Intent,
+		TracksEvaluated:  result.TracksEvaluated,
+		TracksAdded:      result.TracksAdded,
+		Summary:          result.Summary,
+		TargetDurationMs: result.TargetDurationMs,
+		DurationMs:       result.DurationMs,
+		Daypart:          result.Daypart,
+		Weather:          result.Weather,
+		Focus:            result.Focus,
+		Mood:             result.Mood,
+		SimilarArtists:   result.SimilarArtists,
+		Sequence:         result.Sequence,
+		SessionID:        result.SessionID,
+		SessionHistory:   result.SessionHistory,
+		Degraded:         result.Degraded,
+	}
 }
 
 // sseError represents an error SSE event.
@@ -62,11 +116,32 @@ func (h *Handler) AnalyzeIntent(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "message is required")
 		return
 	}
+	if req.TargetDurationMs < 0 || req.ToleranceMs < 0 {
+		writeError(w, http.StatusBadRequest, "target_duration_ms and tolerance_ms cannot be negative")
+		return
+	}
+	if req.ExpandSimilar < 0 || req.ExpandSimilar > domain.MaxExpandSimilar {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("expand_similar must be between 0 and %d", domain.MaxExpandSimilar))
+		return
+	}
+	if len(req.SessionID) > domain.MaxSessionIDLength {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("session_id cannot be longer than %d bytes", domain.MaxSessionIDLength))
+		return
+	}
+	opts := domain.IntentOptions{
+		Duration:      domain.DurationTarget{DurationMs: req.TargetDurationMs, ToleranceMs: req.ToleranceMs},
+		ExpandSimilar: req.ExpandSimilar,
+		SessionID:     req.SessionID,
+	}
 
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	// Connection is a hop-by-hop header and is forbidden in HTTP/2 responses.
+	if r.ProtoMajor < 2 {
+		w.Header().Set("Connection", "keep-alive")
+	}
 
 	rc := http.NewResponseController(w)
 
@@ -80,11 +155,24 @@ func (h *Handler) AnalyzeIntent(w http.ResponseWriter, r *http.Request) {
 
 	// Channel to receive the result from ProcessIntent
 	type intentResultWrapper struct {
-		result services.IntentResult
+		result domain.IntentResult
 		err    error
 	}
 	resultCh := make(chan intentResultWrapper, 1)
 
+	// Streamed compiler output is relayed as "delta" events. Once the
+	// handler has returned, deltas are dropped rather than blocking the
+	// detached processing.
+	deltaCh := make(chan domain.IntentDelta, 64)
+	done := make(chan struct{})
+	defer close(done)
+	onDelta := func(d domain.IntentDelta) {
+		select {
+		case deltaCh <- d:
+		case <-done:
+		}
+	}
+
 	// Create a detached context for background processing.
 	// This ensures DB writes and provider operations complete even if the client disconnects.
 	// context.WithoutCancel preserves values from the parent context but ignores cancellation.
@@ -92,7 +180,14 @@ func (h *Handler) AnalyzeIntent(w http.ResponseWriter, r *http.Request) {
 
 	// Run ProcessIntent in a goroutine with the detached context
 	go func() {
-		result, err := h.svc.ProcessIntent(detachedCtx, playlistID, req.Message)
+		// A panic here would bypass ServeHTTP's recovery and crash the process.
+		defer func() {
+			if rec := recover(); rec != nil {
+				h.recovered(r, rec)
+				resultCh <- intentResultWrapper{err: fmt.Errorf("internal server error")}
+			}
+		}()
+		result, err := h.svc.ProcessIntentStream(detachedCtx, playlistID, req.Message, opts, onDelta)
 		resultCh <- intentResultWrapper{result: result, err: err}
 	}()
 
@@ -113,7 +208,18 @@ func (h *Handler) AnalyzeIntent(w http.ResponseWriter, r *http.Request) {
 			}); err != nil {
 				return // Client disconnected
 			}
+		case delta := <-deltaCh:
+			if err := writeSSEEvent(w, rc, "delta", delta); err != nil {
+				return // Client disconnected
+			}
 		case wrapper := <-resultCh:
+			// Deltas are all sent before the result; flush the ones
+			// still buffered so the client sees the whole output.
+			for len(deltaCh) > 0 {
+				if err := writeSSEEvent(w, rc, "delta", <-deltaCh); err != nil {
+					return // Client disconnected
+				}
+			}
 			if wrapper.err != nil {
 				// Send error event
 				_ = writeSSEEvent(w, rc, "error", sseError{
@@ -124,13 +230,7 @@ func (h *Handler) AnalyzeIntent(w http.ResponseWriter, r *http.Request) {
 			}
 
 			// Send final "complete" event with IntentObject and summary
-			_ = writeSSEEvent(w, rc, "complete", sseComplete{
-				Status:          "complete",
-				Data:            wrapper.result.Intent,
-				TracksEvaluated: wrapper.result.TracksEvaluated,
-				TracksAdded:     wrapper.result.TracksAdded,
-				Summary:         wrapper.result.Summary,
-			})
+			_ = writeSSEEvent(w, rc, "complete", newSSEComplete(wrapper.result))
 			return
 		}
 	}
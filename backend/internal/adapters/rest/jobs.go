@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// playlistJobsResponse summarizes the analysis of a playlist's tracks.
+// Pending counts queued and running jobs.
+type playlistJobsResponse struct {
+	PlaylistID string       `json:"playlist_id"`
+	Total      int          `json:"total"`
+	Pending    int          `json:"pending"`
+	Done       int          `json:"done"`
+	Failed     int          `json:"failed"`
+	Jobs       []domain.Job `json:"jobs"`
+}
+
+// GetJob handles GET /jobs/{id}.
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobs.GetJob(r.Context(), r.PathValue("id"))
+	if errors.Is(err, domain.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// GetPlaylistJobs handles GET /playlists/{id}/jobs, reporting the latest
+// analysis job of each track in the playlist.
+func (h *Handler) GetPlaylistJobs(w http.ResponseWriter, r *http.Request) {
+	playlistID := r.PathValue("id")
+	if _, err := h.svc.GetPlaylist(r.Context(), playlistID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	jobs, err := h.jobs.PlaylistJobs(r.Context(), playlistID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := playlistJobsResponse{PlaylistID: playlistID, Total: len(jobs), Jobs: jobs}
+	for _, job := range jobs {
+		switch job.State {
+		case domain.JobQueued, domain.JobRunning:
+			resp.Pending++
+		case domain.JobDone:
+			resp.Done++
+		case domain.JobFailed:
+			resp.Failed++
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// ListTracks handles GET /tracks, a page of every stored music track. It
+// takes ?artist, ?limit and ?offset, and ?min_<feature> and ?max_<feature>
+// for each audio feature, such as ?min_energy=0.7.
+func (h *Handler) ListTracks(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasTrackLibrary() {
+		writeError(w, http.StatusNotImplemented, "track library not configured")
+		return
+	}
+	limit, ok := parseLimit(w, r, domain.DefaultTrackPageSize, domain.MaxTrackPageSize)
+	if !ok {
+		return
+	}
+	filter := domain.TrackFilter{Artist: r.URL.Query().Get("artist"), Limit: limit}
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		filter.Offset = n
+	}
+	for _, feature := range domain.LibraryFeatures() {
+		fr := domain.FeatureRange{Feature: feature}
+		for _, bound := range []struct {
+			param string
+			value **float64
+		}{{"min_" + feature, &fr.Min}, {"max_" + feature, &fr.Max}} {
+			raw := r.URL.Query().Get(bound.param)
+			if raw == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("%s must be a number", bound.param))
+				return
+			}
+			*bound.value = &v
+		}
+		if fr.Min != nil || fr.Max != nil {
+			filter.Features = append(filter.Features, fr)
+		}
+	}
+
+	page, err := h.svc.ListTracks(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTrackFilter) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// GetTrack handles GET /tracks/{id}
+func (h *Handler) GetTrack(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasTrackLibrary() {
+		writeError(w, http.StatusNotImplemented, "track library not configured")
+		return
+	}
+	track, err := h.svc.GetTrack(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, track)
+}
@@ -0,0 +1,16 @@
+package rest
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var (
+	rateLimited = metrics.NewCounterVec(
+		"overture_http_rate_limited_total",
+		"Requests rejected with 429 by the per-client rate limit, by limit (default, intent).",
+		"limit",
+	)
+	rateLimitClients = metrics.NewGaugeVec(
+		"overture_http_rate_limit_clients",
+		"Clients with a rate limit bucket, by limit (default, intent).",
+		"limit",
+	)
+)
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+const (
+	defaultMoodDays = 7
+	maxMoodDays     = 90
+)
+
+type moodRequest struct {
+	Valence *float64 `json:"valence"`
+	Energy  *float64 `json:"energy"`
+	Emoji   string   `json:"emoji"`
+}
+
+type moodHistoryResponse struct {
+	// Trend is omitted when there are no recent check-ins.
+	Trend    *domain.MoodTrend    `json:"trend,omitempty"`
+	CheckIns []domain.MoodCheckIn `json:"check_ins"`
+}
+
+// RecordMood handles POST /me/mood, a self-reported valence and energy or
+// an emoji standing for both.
+func (h *Handler) RecordMood(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasMood() {
+		writeError(w, http.StatusNotImplemented, "mood check-ins not configured")
+		return
+	}
+	var req moodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	checkIn, err := h.svc.RecordMood(r.Context(), domain.DefaultOwner, domain.MoodReport{
+		Valence: req.Valence,
+		Energy:  req.Energy,
+		Emoji:   req.Emoji,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMood) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, checkIn)
+}
+
+// GetMoodHistory handles GET /me/mood, listing the check-ins of the last
+// ?days (default 7) with the current trend.
+func (h *Handler) GetMoodHistory(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasMood() {
+		writeError(w, http.StatusNotImplemented, "mood check-ins not configured")
+		return
+	}
+	days := defaultMoodDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxMoodDays {
+			writeError(w, http.StatusBadRequest, "days must be between 1 and 90")
+			return
+		}
+		days = n
+	}
+
+	checkIns, err := h.svc.ListMoods(r.Context(), domain.DefaultOwner, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	trend, ok, err := h.svc.CurrentMood(r.Context(), domain.DefaultOwner)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := moodHistoryResponse{CheckIns: checkIns}
+	if resp.CheckIns == nil {
+		resp.CheckIns = []domain.MoodCheckIn{}
+	}
+	if ok {
+		resp.Trend = &trend
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
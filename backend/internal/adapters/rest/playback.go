@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type updatePlaybackRequest struct {
+	TrackID    string `json:"track_id"`
+	PositionMs int    `json:"position_ms"`
+	Playing    bool   `json:"playing"`
+	Device     string `json:"device"`
+}
+
+// GetPlayback handles GET /playlists/{id}/playback.
+func (h *Handler) GetPlayback(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasPlayback() {
+		writeError(w, http.StatusNotImplemented, "playback not configured")
+		return
+	}
+	state, err := h.svc.GetPlayback(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "nothing is playing from this playlist")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// UpdatePlayback handles PUT /playlists/{id}/playback, replacing what is
+// playing from the playlist. Other clients learn of it through the
+// playback.updated event.
+func (h *Handler) UpdatePlayback(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasPlayback() {
+		writeError(w, http.StatusNotImplemented, "playback not configured")
+		return
+	}
+
+	var req updatePlaybackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.TrackID == "" {
+		writeError(w, http.StatusBadRequest, "track_id is required")
+		return
+	}
+	if req.PositionMs < 0 {
+		writeError(w, http.StatusBadRequest, "position_ms cannot be negative")
+		return
+	}
+
+	state, err := h.svc.UpdatePlayback(r.Context(), domain.PlaybackState{
+		PlaylistID: r.PathValue("id"),
+		TrackID:    req.TrackID,
+		PositionMs: req.PositionMs,
+		Playing:    req.Playing,
+		Device:     req.Device,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrNotInPlaylist):
+			writeErrorWithCode(w, http.StatusUnprocessableEntity, err.Error(), "TRACK_NOT_IN_PLAYLIST")
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type playRequest struct {
+	// TrackID starts playback at this track instead of the first one.
+	TrackID string `json:"track_id"`
+}
+
+type queueRequest struct {
+	TrackID string `json:"track_id"`
+}
+
+// PlayPlaylist handles POST /playlists/{id}/play, playing the playlist on
+// the user's active device. The body is optional.
+func (h *Handler) PlayPlaylist(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasPlaybackControl() {
+		writeError(w, http.StatusNotImplemented, "playback control not configured")
+		return
+	}
+	var req playRequest
+	if r.ContentLength != 0 {
+		if !isJSONContentType(r) {
+			writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	state, err := h.svc.PlayPlaylist(r.Context(), r.PathValue("id"), req.TrackID)
+	if err != nil {
+		writePlayerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// QueueTrack handles POST /playlists/{id}/queue, queuing one of the
+// playlist's tracks to play next.
+func (h *Handler) QueueTrack(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasPlaybackControl() {
+		writeError(w, http.StatusNotImplemented, "playback control not configured")
+		return
+	}
+	var req queueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.TrackID == "" {
+		writeError(w, http.StatusBadRequest, "track_id is required")
+		return
+	}
+
+	if err := h.svc.QueueTrack(r.Context(), r.PathValue("id"), req.TrackID); err != nil {
+		writePlayerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PausePlayback handles POST /player/pause.
+func (h *Handler) PausePlayback(w http.ResponseWriter, r *http.Request) {
+	h.playerCommand(w, r, h.svc.PausePlayback)
+}
+
+// ResumePlayback handles POST /player/resume.
+func (h *Handler) ResumePlayback(w http.ResponseWriter, r *http.Request) {
+	h.playerCommand(w, r, h.svc.ResumePlayback)
+}
+
+// SkipTrack handles POST /player/next.
+func (h *Handler) SkipTrack(w http.ResponseWriter, r *http.Request) {
+	h.playerCommand(w, r, h.svc.SkipTrack)
+}
+
+func (h *Handler) playerCommand(w http.ResponseWriter, r *http.Request, command func(ctx context.Context) error) {
+	if !h.svc.HasPlaybackControl() {
+		writeError(w, http.StatusNotImplemented, "playback control not configured")
+		return
+	}
+	if err := command(r.Context()); err != nil {
+		writePlayerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writePlayerError maps playback control failures to responses.
+func writePlayerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNoActiveDevice):
+		writeErrorWithCode(w, http.StatusConflict, err.Error(), "NO_ACTIVE_DEVICE")
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrNotInPlaylist):
+		writeErrorWithCode(w, http.StatusUnprocessableEntity, err.Error(), "TRACK_NOT_IN_PLAYLIST")
+	default:
+		writeError(w, http.StatusBadGateway, err.Error())
+	}
+}
@@ -3,15 +3,30 @@ package rest
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
 )
 
+const (
+	defaultSimilarLimit        = 10
+	maxSimilarLimit            = 50
+	defaultRecommendationLimit = 20
+	maxRecommendationLimit     = 50
+	// maxArcWindow bounds the smoothing window of GET /playlists/{id}/arc.
+	maxArcWindow = 25
+)
+
 type createPlaylistRequest struct {
 	Name string `json:"name"`
 }
 
+type reorderTracksRequest struct {
+	TrackIDs []string `json:"track_ids"`
+}
+
 // CreatePlaylist handles POST /playlists
 func (h *Handler) CreatePlaylist(w http.ResponseWriter, r *http.Request) {
 	if !isJSONContentType(r) {
@@ -64,7 +79,8 @@ func (h *Handler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, playlist)
 }
 
-// GetPlaylistAnalysis handles GET /playlists/{id}/analysis
+// GetPlaylistAnalysis handles GET /playlists/{id}/analysis, returning the
+// average audio features or, with ?detail=full, their distribution.
 func (h *Handler) GetPlaylistAnalysis(w http.ResponseWriter, r *http.Request) {
 	playlistID := r.PathValue("id")
 	if playlistID == "" {
@@ -72,7 +88,17 @@ func (h *Handler) GetPlaylistAnalysis(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	features, err := h.svc.GetPlaylistAnalysis(r.Context(), playlistID)
+	var features any
+	var err error
+	switch detail := r.URL.Query().Get("detail"); detail {
+	case "", "summary":
+		features, err = h.svc.GetPlaylistAnalysis(r.Context(), playlistID)
+	case "full":
+		features, err = h.svc.GetPlaylistAnalysisDetail(r.Context(), playlistID)
+	default:
+		writeError(w, http.StatusBadRequest, "detail must be summary or full")
+		return
+	}
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
@@ -84,3 +110,105 @@ func (h *Handler) GetPlaylistAnalysis(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, features)
 }
+
+// GetRecommendations handles GET /playlists/{id}/recommendations, listing
+// up to ?limit (default 20) tracks suggested for the playlist's vibe.
+func (h *Handler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasRecommendations() {
+		writeError(w, http.StatusNotImplemented, "recommendation provider not configured")
+		return
+	}
+	limit, ok := parseLimit(w, r, defaultRecommendationLimit, maxRecommendationLimit)
+	if !ok {
+		return
+	}
+
+	recs, err := h.svc.Recommendations(r.Context(), r.PathValue("id"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, recs)
+}
+
+// GetPlaylistArc handles GET /playlists/{id}/arc, returning the per-track
+// energy, valence and tempo series with a curve smoothed over ?window
+// tracks (default domain.DefaultArcWindow).
+func (h *Handler) GetPlaylistArc(w http.ResponseWriter, r *http.Request) {
+	window := domain.DefaultArcWindow
+	if raw := r.URL.Query().Get("window"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxArcWindow {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("window must be between 1 and %d", maxArcWindow))
+			return
+		}
+		window = n
+	}
+
+	arc, err := h.svc.PlaylistArc(r.Context(), r.PathValue("id"), window)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, arc)
+}
+
+// GetSimilarPlaylists handles GET /playlists/{id}/similar, listing the
+// ?limit (default 10) playlists closest to this one.
+func (h *Handler) GetSimilarPlaylists(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(w, r, defaultSimilarLimit, maxSimilarLimit)
+	if !ok {
+		return
+	}
+
+	similar, err := h.svc.SimilarPlaylists(r.Context(), r.PathValue("id"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "playlist not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, similar)
+}
+
+// ReorderTracks handles PUT /playlists/{id}/tracks/order, which takes every
+// track ID in the playlist in its new order and responds with the reordered
+// playlist.
+func (h *Handler) ReorderTracks(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+
+	var req reorderTracksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	playlist, err := h.svc.ReorderTracks(r.Context(), r.PathValue("id"), req.TrackIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+		case errors.Is(err, domain.ErrInvalidOrder):
+			writeErrorWithCode(w, http.StatusUnprocessableEntity, err.Error(), "INVALID_TRACK_ORDER")
+		case errors.Is(err, domain.ErrPlaylistLocked):
+			writeErrorWithCode(w, http.StatusConflict, "the playlist's ordering is locked", "PLAYLIST_LOCKED")
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, playlist)
+}
@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// exportedTrack is a track as written to a playlist export: what other
+// tools need to find the same recording.
+type exportedTrack struct {
+	ISRC       string `json:"isrc,omitempty"`
+	SpotifyID  string `json:"spotify_id,omitempty"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	DurationMs int    `json:"duration_ms"`
+}
+
+type exportedPlaylist struct {
+	Name   string          `json:"name"`
+	Tracks []exportedTrack `json:"tracks"`
+}
+
+// playlistExporters encode a playlist export in each supported format,
+// with its content type.
+var playlistExporters = map[string]struct {
+	contentType string
+	encode      func(exportedPlaylist) ([]byte, error)
+}{
+	"m3u":  {"audio/x-mpegurl", encodeM3U},
+	"json": {"application/json", encodeExportJSON},
+	"csv":  {"text/csv", encodeExportCSV},
+}
+
+// ExportPlaylist handles GET /playlists/{id}/export?format=m3u|json|csv,
+// downloading the playlist's tracks as a file other tools can import.
+// JSON is the default.
+func (h *Handler) ExportPlaylist(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "json"
+	}
+	exporter, ok := playlistExporters[format]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "format must be one of m3u, json, csv")
+		return
+	}
+
+	playlist, err := h.svc.GetPlaylist(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	export := exportedPlaylist{Name: playlist.Name, Tracks: make([]exportedTrack, 0, len(playlist.Tracks))}
+	for _, t := range playlist.Tracks {
+		e := exportedTrack{ISRC: t.ISRC, Title: t.Title, Artist: t.Artist, DurationMs: t.DurationMs}
+		if t.OnSpotify() {
+			e.SpotifyID = t.ID
+		}
+		export.Tracks = append(export.Tracks, e)
+	}
+	body, err := exporter.encode(export)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", exporter.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFilename(playlist)+"."+format))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// exportFilename is the playlist's name reduced to characters safe in a
+// filename, or its ID when none are left.
+func exportFilename(p domain.Playlist) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			return r
+		case unicode.IsSpace(r):
+			return '-'
+		}
+		return -1
+	}, p.Name)
+	if name == "" {
+		return p.ID
+	}
+	return name
+}
+
+func encodeExportJSON(p exportedPlaylist) ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+func encodeExportCSV(p exportedPlaylist) ([]byte, error) {
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	_ = cw.Write([]string{"isrc", "spotify_id", "title", "artist", "duration_ms"})
+	for _, t := range p.Tracks {
+		_ = cw.Write([]string{t.ISRC, t.SpotifyID, t.Title, t.Artist, strconv.Itoa(t.DurationMs)})
+	}
+	cw.Flush()
+	return buf.Bytes(), cw.Error()
+}
+
+// encodeM3U writes an extended M3U playlist. Each entry points at the
+// track on Spotify, or at isrc:<ISRC> for tracks from other catalogs.
+func encodeM3U(p exportedPlaylist) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&buf, "#PLAYLIST:%s\n", oneLine(p.Name))
+	for _, t := range p.Tracks {
+		location := "https://open.spotify.com/track/" + t.SpotifyID
+		if t.SpotifyID == "" {
+			location = "isrc:" + t.ISRC
+		}
+		fmt.Fprintf(&buf, "#EXTINF:%d,%s - %s\n%s\n", t.DurationMs/1000, oneLine(t.Artist), oneLine(t.Title), location)
+	}
+	return buf.Bytes(), nil
+}
+
+// oneLine keeps s from breaking an M3U line.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
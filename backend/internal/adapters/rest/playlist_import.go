@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/worker"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type importPlaylistRequest struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+// ImportPlaylist handles POST /playlists/import, creating a playlist from
+// a Spotify playlist URL, URI or ID. Audio features of the imported tracks
+// are resolved in the background.
+func (h *Handler) ImportPlaylist(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasPlaylistImport() {
+		writeError(w, http.StatusNotImplemented, "playlist import not configured")
+		return
+	}
+
+	var req importPlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.URL == "" {
+		writeError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+
+	result, err := h.svc.ImportPlaylist(r.Context(), req.URL, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidPlaylistRef):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	if h.pool != nil {
+		for _, t := range result.Playlist.Tracks {
+			h.pool.Submit(worker.Job{TrackID: t.ID, PreviewURL: t.PreviewURL, Trace: trace.SpanContextFromContext(r.Context())})
+		}
+	}
+
+	w.Header().Set("Location", "/playlists/"+result.Playlist.ID)
+	writeJSON(w, http.StatusCreated, result)
+}
@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+type addToQueueRequest struct {
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+	// Next plays the track before everything already queued.
+	Next bool `json:"next"`
+}
+
+// GetQueue handles GET /queue.
+func (h *Handler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasQueue() {
+		writeError(w, http.StatusNotImplemented, "queue not configured")
+		return
+	}
+	q, err := h.svc.GetQueue(r.Context(), domain.DefaultOwner)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, q)
+}
+
+// AddToQueue handles POST /queue, looking a track up by title and artist
+// and queuing it. It responds with the updated queue.
+func (h *Handler) AddToQueue(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasQueue() {
+		writeError(w, http.StatusNotImplemented, "queue not configured")
+		return
+	}
+	var req addToQueueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Title == "" || req.Artist == "" {
+		writeError(w, http.StatusBadRequest, "title and artist are required")
+		return
+	}
+
+	q, err := h.svc.AddToQueue(r.Context(), domain.DefaultOwner, req.Title, req.Artist, req.Next)
+	if err != nil {
+		var matchErr *ports.NoConfidentMatchError
+		if errors.As(err, &matchErr) {
+			writeErrorWithCode(w, http.StatusUnprocessableEntity, matchErr.Error(), errCodeNoConfidentMatch)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, q)
+}
+
+// PopQueue handles POST /queue/pop, removing and returning the next track.
+func (h *Handler) PopQueue(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasQueue() {
+		writeError(w, http.StatusNotImplemented, "queue not configured")
+		return
+	}
+	track, err := h.svc.PopQueue(r.Context(), domain.DefaultOwner)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "queue is empty")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, track)
+}
+
+// ClearQueue handles DELETE /queue.
+func (h *Handler) ClearQueue(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasQueue() {
+		writeError(w, http.StatusNotImplemented, "queue not configured")
+		return
+	}
+	if err := h.svc.ClearQueue(r.Context(), domain.DefaultOwner); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIKeyHeader identifies a client for rate limiting by one of the
+// configured RateLimit.APIKeys. Requests without a known key are limited by
+// IP address.
+const APIKeyHeader = "X-API-Key"
+
+// RateLimit configures per-client token buckets. Rate is the requests per
+// second a client may make, in bursts of up to Burst; IntentRate and
+// IntentBurst apply separately to POST /playlists/{id}/intent, which calls
+// the LLM and Spotify. A zero rate disables that limit.
+type RateLimit struct {
+	Rate        float64
+	Burst       int
+	IntentRate  float64
+	IntentBurst int
+	// TrustProxy identifies clients by the last X-Forwarded-For address,
+	// as set by the BFF, instead of the connection's address.
+	TrustProxy bool
+	// APIKeys are the keys that give a client its own bucket. Any other
+	// key is ignored, so sending a fresh key per request buys nothing.
+	APIKeys []string
+}
+
+// WithRateLimit limits each client's requests. /health is never limited.
+func WithRateLimit(cfg RateLimit) Option {
+	return func(h *Handler) {
+		h.rateLimit = cfg
+		h.apiKeys = make(map[string]bool, len(cfg.APIKeys))
+		for _, key := range cfg.APIKeys {
+			h.apiKeys[key] = true
+		}
+		if cfg.Rate > 0 {
+			h.limiter = newLimiter("default", cfg.Rate, cfg.Burst)
+		}
+		if cfg.IntentRate > 0 {
+			h.intentLimiter = newLimiter("intent", cfg.IntentRate, cfg.IntentBurst)
+		}
+	}
+}
+
+// limitIdle is how long a client's bucket is kept after its last request.
+// A bucket idle this long has refilled for any practical rate, so dropping
+// it changes nothing.
+const limitIdle = 10 * time.Minute
+
+// limiter holds one token bucket per client.
+type limiter struct {
+	name  string
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(name string, rate float64, burst int) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{name: name, rate: rate, burst: float64(burst), now: time.Now, buckets: map[string]*bucket{}}
+}
+
+// allow takes a token from client's bucket. When it is empty, it reports
+// how long until the next token.
+func (l *limiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+		rateLimitClients.Set(float64(len(l.buckets)), l.name)
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops idle buckets at most once per limitIdle.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limitIdle {
+		return
+	}
+	l.lastSweep = now
+	for client, b := range l.buckets {
+		if now.Sub(b.last) >= limitIdle {
+			delete(l.buckets, client)
+		}
+	}
+	rateLimitClients.Set(float64(len(l.buckets)), l.name)
+}
+
+// limit reports whether r's client is within l's limit. When it is not, it
+// answers 429 with a Retry-After in whole seconds.
+func (h *Handler) limit(l *limiter, w http.ResponseWriter, r *http.Request) bool {
+	if l == nil {
+		return true
+	}
+	ok, wait := l.allow(h.clientKey(r))
+	if ok {
+		return true
+	}
+	rateLimited.Inc(l.name)
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	writeErrorWithCode(w, http.StatusTooManyRequests, "rate limit exceeded; retry later", "RATE_LIMITED")
+	return false
+}
+
+// limitIntent applies the intent limit to next.
+func (h *Handler) limitIntent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.limit(h.intentLimiter, w, r) {
+			next(w, r)
+		}
+	}
+}
+
+// clientKey identifies the client of r by a configured API key or IP
+// address.
+func (h *Handler) clientKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" && h.apiKeys[key] {
+		return "key:" + key
+	}
+	if h.rateLimit.TrustProxy {
+		if fwd := r.Header.Values("X-Forwarded-For"); len(fwd) > 0 {
+			hops := strings.Split(fwd[len(fwd)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return "ip:" + ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+const defaultRevisionLimit = 20
+
+type revisionsResponse struct {
+	Revisions []domain.PlaylistRevision `json:"revisions"`
+}
+
+// ListRevisions handles GET /playlists/{id}/revisions, listing the
+// playlist's revisions newest first.
+func (h *Handler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasRevisions() {
+		writeError(w, http.StatusNotImplemented, "revisions not configured")
+		return
+	}
+	limit, ok := parseLimit(w, r, defaultRevisionLimit, domain.MaxRevisions)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.svc.ListRevisions(r.Context(), r.PathValue("id"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "playlist not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if revisions == nil {
+		revisions = []domain.PlaylistRevision{}
+	}
+	writeJSON(w, http.StatusOK, revisionsResponse{Revisions: revisions})
+}
+
+// RevertPlaylist handles POST /playlists/{id}/revert/{rev}, restoring the
+// playlist's tracks to that revision.
+func (h *Handler) RevertPlaylist(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasRevisions() {
+		writeError(w, http.StatusNotImplemented, "revisions not configured")
+		return
+	}
+	rev, err := strconv.Atoi(r.PathValue("rev"))
+	if err != nil || rev < 1 {
+		writeError(w, http.StatusBadRequest, "rev must be a positive integer")
+		return
+	}
+
+	playlist, err := h.svc.RevertPlaylist(r.Context(), r.PathValue("id"), rev)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "playlist or revision not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, playlist)
+}
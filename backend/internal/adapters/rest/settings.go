@@ -0,0 +1,144 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type weatherSettingsRequest struct {
+	Enabled   bool    `json:"enabled"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// GetWeatherSettings handles GET /settings/weather.
+func (h *Handler) GetWeatherSettings(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasWeather() {
+		writeError(w, http.StatusNotImplemented, "weather not configured")
+		return
+	}
+	settings, err := h.svc.GetWeatherSettings(r.Context(), domain.DefaultOwner)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdateWeatherSettings handles PUT /settings/weather, which turns
+// weather-aware generation on or off and sets the location it uses.
+func (h *Handler) UpdateWeatherSettings(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasWeather() {
+		writeError(w, http.StatusNotImplemented, "weather not configured")
+		return
+	}
+	var req weatherSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	settings, err := h.svc.UpdateWeatherSettings(r.Context(), domain.WeatherSettings{
+		Owner:     domain.DefaultOwner,
+		Enabled:   req.Enabled,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSettings) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// playlistSettingsPatch holds the playlist settings a PATCH changes; absent
+// fields keep their current values.
+type playlistSettingsPatch struct {
+	MaxTracks        *int    `json:"max_tracks"`
+	AllowExplicit    *bool   `json:"allow_explicit"`
+	TargetDurationMs *int    `json:"target_duration_ms"`
+	LockedOrdering   *bool   `json:"locked_ordering"`
+	DefaultMarket    *string `json:"default_market"`
+}
+
+// GetPlaylistSettings handles GET /playlists/{id}/settings.
+func (h *Handler) GetPlaylistSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.svc.GetPlaylistSettings(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "playlist not found")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidSettings) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdatePlaylistSettings handles PATCH /playlists/{id}/settings, changing
+// the settings the body names.
+func (h *Handler) UpdatePlaylistSettings(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	if !h.svc.HasPlaylistSettings() {
+		writeError(w, http.StatusNotImplemented, "playlist settings not configured")
+		return
+	}
+	var patch playlistSettingsPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	playlistID := r.PathValue("id")
+	settings, err := h.svc.GetPlaylistSettings(r.Context(), playlistID)
+	if err == nil {
+		if patch.MaxTracks != nil {
+			settings.MaxTracks = *patch.MaxTracks
+		}
+		if patch.AllowExplicit != nil {
+			settings.AllowExplicit = *patch.AllowExplicit
+		}
+		if patch.TargetDurationMs != nil {
+			settings.TargetDurationMs = *patch.TargetDurationMs
+		}
+		if patch.LockedOrdering != nil {
+			settings.LockedOrdering = *patch.LockedOrdering
+		}
+		if patch.DefaultMarket != nil {
+			settings.DefaultMarket = strings.ToUpper(strings.TrimSpace(*patch.DefaultMarket))
+		}
+		settings, err = h.svc.UpdatePlaylistSettings(r.Context(), settings)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "playlist not found")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidSettings) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
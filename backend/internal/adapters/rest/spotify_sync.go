@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// SyncPlaylist handles POST /playlists/{id}/sync, pushing the playlist to
+// its mirror in the connected Spotify account.
+func (h *Handler) SyncPlaylist(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasSpotifyAuth() {
+		writeError(w, http.StatusNotImplemented, "spotify auth not configured")
+		return
+	}
+	playlistID := r.PathValue("id")
+	if playlistID == "" {
+		writeError(w, http.StatusBadRequest, "playlist id is required")
+		return
+	}
+
+	result, err := h.svc.SyncPlaylistToSpotify(r.Context(), domain.DefaultOwner, playlistID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSpotifyNotConnected):
+			writeErrorWithCode(w, http.StatusConflict, "connect a Spotify account at /auth/spotify/login first", "SPOTIFY_NOT_CONNECTED")
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+		default:
+			writeError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
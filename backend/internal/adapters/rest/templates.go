@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type runningTemplateRequest struct {
+	Artists []string `json:"artists"`
+	// TargetSPM is the runner's steady cadence in steps per minute.
+	TargetSPM  int `json:"target_spm"`
+	DurationMs int `json:"duration_ms"`
+}
+
+// GenerateRunningPlaylist handles POST /playlists/{id}/templates/running,
+// filling the playlist with tracks paced to a warm-up, steady cadence and
+// cool-down.
+func (h *Handler) GenerateRunningPlaylist(w http.ResponseWriter, r *http.Request) {
+	if !isJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	var req runningTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.svc.GenerateRunningPlaylist(r.Context(), r.PathValue("id"), domain.RunningTemplate{
+		Artists:    req.Artists,
+		TargetSPM:  req.TargetSPM,
+		DurationMs: req.DurationMs,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidTemplate):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, "playlist not found")
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -3,13 +3,24 @@ package rest
 import (
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
 	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
 	"github.com/ewilliams-labs/overture/backend/internal/worker"
+	"go.opentelemetry.io/otel/trace"
 )
 
-const errCodeNoConfidentMatch = "NO_CONFIDENT_MATCH"
+const (
+	errCodeNoConfidentMatch = "NO_CONFIDENT_MATCH"
+	errCodeDuplicateTrack   = "DUPLICATE_TRACK"
+	errCodeExplicitTrack    = "EXPLICIT_TRACK"
+	errCodePlaylistFull     = "PLAYLIST_FULL"
+	errCodeVersionConflict  = "VERSION_CONFLICT"
+	errCodeUnavailable      = "PROVIDER_UNAVAILABLE"
+)
 
 // addTrackRequest defines what the client sends us
 type addTrackRequest struct {
@@ -19,6 +30,9 @@ type addTrackRequest struct {
 
 type addTrackResponse struct {
 	ID string `json:"id"`
+	// JobID identifies the track's analysis job when jobs are persisted,
+	// for polling GET /jobs/{id}.
+	JobID string `json:"job_id,omitempty"`
 }
 
 // AddTrack handles POST /playlists/{id}/tracks
@@ -56,15 +70,91 @@ func (h *Handler) AddTrack(w http.ResponseWriter, r *http.Request) {
 			writeErrorWithCode(w, http.StatusUnprocessableEntity, matchErr.Error(), errCodeNoConfidentMatch)
 			return
 		}
+		if errors.Is(err, domain.ErrDuplicateISRC) || errors.Is(err, domain.ErrDuplicateTrack) || errors.Is(err, domain.ErrDuplicateRecording) {
+			writeErrorWithCode(w, http.StatusConflict, "track is already in the playlist", errCodeDuplicateTrack)
+			return
+		}
+		if errors.Is(err, domain.ErrExplicitTrack) {
+			writeErrorWithCode(w, http.StatusUnprocessableEntity, "the playlist does not allow explicit tracks", errCodeExplicitTrack)
+			return
+		}
+		if errors.Is(err, domain.ErrPlaylistFull) {
+			writeErrorWithCode(w, http.StatusConflict, "the playlist has reached its maximum number of tracks", errCodePlaylistFull)
+			return
+		}
+		if errors.Is(err, domain.ErrVersionConflict) {
+			// Nothing was saved; the request can simply be sent again.
+			w.Header().Set("Retry-After", "0")
+			writeErrorWithCode(w, http.StatusConflict, "the playlist was changed by another request; retry the request", errCodeVersionConflict)
+			return
+		}
+		var unavailable ports.ProviderUnavailableError
+		if errors.As(err, &unavailable) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(unavailable.RetryAfter.Seconds()))))
+			writeErrorWithCode(w, http.StatusServiceUnavailable, unavailable.Error(), errCodeUnavailable)
+			return
+		}
 		// In a real app, you'd check the error type to decide between 400 vs 500
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	resp := addTrackResponse{ID: playlistIDResult}
 	if h.pool != nil {
-		h.pool.Submit(worker.Job{TrackID: trackID, PreviewURL: previewURL})
+		resp.JobID, _ = h.pool.SubmitTracked(worker.Job{TrackID: trackID, PreviewURL: previewURL, Trace: trace.SpanContextFromContext(r.Context())})
 	}
 
 	// 4. Return the Response
 	w.Header().Set("Location", "/playlists/"+playlistIDResult)
-	writeJSON(w, http.StatusCreated, addTrackResponse{ID: playlistIDResult})
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// GetTrackLyrics handles GET /tracks/{id}/lyrics
+func (h *Handler) GetTrackLyrics(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasLyrics() {
+		writeError(w, http.StatusNotImplemented, "lyrics provider not configured")
+		return
+	}
+	trackID := r.PathValue("id")
+	if trackID == "" {
+		writeError(w, http.StatusBadRequest, "track id is required")
+		return
+	}
+
+	lyrics, err := h.svc.GetTrackLyrics(r.Context(), trackID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, lyrics)
+}
+
+// GetAlsoAdded handles GET /tracks/{id}/also-added, listing the tracks
+// most often added to playlists together with the track.
+func (h *Handler) GetAlsoAdded(w http.ResponseWriter, r *http.Request) {
+	if !h.svc.HasCooccurrence() {
+		writeError(w, http.StatusNotImplemented, "co-occurrence not configured")
+		return
+	}
+	limit, ok := parseLimit(w, r, 10, 50)
+	if !ok {
+		return
+	}
+
+	recs, err := h.svc.AlsoAdded(r.Context(), r.PathValue("id"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if recs == nil {
+		recs = []domain.Recommendation{}
+	}
+	writeJSON(w, http.StatusOK, recs)
 }
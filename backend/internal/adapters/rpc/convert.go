@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	pb "github.com/ewilliams-labs/overture/backend/internal/adapters/rpc/overturev1"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func toPlaylist(p domain.Playlist) *pb.Playlist {
+	out := &pb.Playlist{Id: p.ID, Name: p.Name, Tracks: make([]*pb.Track, 0, len(p.Tracks))}
+	for _, t := range p.Tracks {
+		out.Tracks = append(out.Tracks, toTrack(t))
+	}
+	return out
+}
+
+func toTrack(t domain.Track) *pb.Track {
+	kind := string(t.Type)
+	if kind == "" {
+		kind = string(domain.ItemTrack)
+	}
+	return &pb.Track{
+		Id:         t.ID,
+		Type:       kind,
+		Title:      t.Title,
+		Artist:     t.Artist,
+		Album:      t.Album,
+		CoverUrl:   t.CoverURL,
+		PreviewUrl: t.PreviewURL,
+		DurationMs: int64(t.DurationMs),
+		Isrc:       t.ISRC,
+		Features:   toFeatures(t.Features),
+	}
+}
+
+func toFeatures(f domain.AudioFeatures) *pb.AudioFeatures {
+	return &pb.AudioFeatures{
+		Danceability:     f.Danceability,
+		Energy:           f.Energy,
+		Valence:          f.Valence,
+		Tempo:            f.Tempo,
+		Instrumentalness: f.Instrumentalness,
+		Acousticness:     f.Acousticness,
+	}
+}
+
+func toIntentResult(r domain.IntentResult) *pb.IntentResult {
+	return &pb.IntentResult{
+		IntentType:       r.Intent.IntentType,
+		Artists:          r.Intent.Entities.Artists,
+		Genres:           r.Intent.Entities.Genres,
+		Explanation:      r.Intent.Explanation,
+		TracksEvaluated:  int64(r.TracksEvaluated),
+		TracksAdded:      int64(r.TracksAdded),
+		Summary:          r.Summary,
+		TargetDurationMs: int64(r.TargetDurationMs),
+		DurationMs:       int64(r.DurationMs),
+		Daypart:          r.Daypart,
+		Weather:          r.Weather,
+		Focus:            r.Focus,
+		Mood:             r.Mood,
+	}
+}
+
+func statusEvent(s string) *pb.IntentEvent {
+	return &pb.IntentEvent{Event: &pb.IntentEvent_Status{Status: s}}
+}
+
+func deltaEvent(d domain.IntentDelta) *pb.IntentEvent {
+	return &pb.IntentEvent{Event: &pb.IntentEvent_Delta{Delta: &pb.IntentDelta{Thinking: d.Thinking, Content: d.Content}}}
+}
@@ -0,0 +1,1064 @@
+// The gRPC interface to Overture's core, for internal services that would
+// rather not go through JSON over HTTP. It mirrors the REST playlist routes.
+// Regenerate internal/adapters/rpc/overturev1 with `just proto` after editing.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: overture/v1/playlist.proto
+
+package overturev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AudioFeatures struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Danceability     float64                `protobuf:"fixed64,1,opt,name=danceability,proto3" json:"danceability,omitempty"`
+	Energy           float64                `protobuf:"fixed64,2,opt,name=energy,proto3" json:"energy,omitempty"`
+	Valence          float64                `protobuf:"fixed64,3,opt,name=valence,proto3" json:"valence,omitempty"`
+	Tempo            float64                `protobuf:"fixed64,4,opt,name=tempo,proto3" json:"tempo,omitempty"`
+	Instrumentalness float64                `protobuf:"fixed64,5,opt,name=instrumentalness,proto3" json:"instrumentalness,omitempty"`
+	Acousticness     float64                `protobuf:"fixed64,6,opt,name=acousticness,proto3" json:"acousticness,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AudioFeatures) Reset() {
+	*x = AudioFeatures{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AudioFeatures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioFeatures) ProtoMessage() {}
+
+func (x *AudioFeatures) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioFeatures.ProtoReflect.Descriptor instead.
+func (*AudioFeatures) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AudioFeatures) GetDanceability() float64 {
+	if x != nil {
+		return x.Danceability
+	}
+	return 0
+}
+
+func (x *AudioFeatures) GetEnergy() float64 {
+	if x != nil {
+		return x.Energy
+	}
+	return 0
+}
+
+func (x *AudioFeatures) GetValence() float64 {
+	if x != nil {
+		return x.Valence
+	}
+	return 0
+}
+
+func (x *AudioFeatures) GetTempo() float64 {
+	if x != nil {
+		return x.Tempo
+	}
+	return 0
+}
+
+func (x *AudioFeatures) GetInstrumentalness() float64 {
+	if x != nil {
+		return x.Instrumentalness
+	}
+	return 0
+}
+
+func (x *AudioFeatures) GetAcousticness() float64 {
+	if x != nil {
+		return x.Acousticness
+	}
+	return 0
+}
+
+type Track struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// type is "track" or "episode".
+	Type          string         `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Title         string         `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Artist        string         `protobuf:"bytes,4,opt,name=artist,proto3" json:"artist,omitempty"`
+	Album         string         `protobuf:"bytes,5,opt,name=album,proto3" json:"album,omitempty"`
+	CoverUrl      string         `protobuf:"bytes,6,opt,name=cover_url,json=coverUrl,proto3" json:"cover_url,omitempty"`
+	PreviewUrl    string         `protobuf:"bytes,7,opt,name=preview_url,json=previewUrl,proto3" json:"preview_url,omitempty"`
+	DurationMs    int64          `protobuf:"varint,8,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Isrc          string         `protobuf:"bytes,9,opt,name=isrc,proto3" json:"isrc,omitempty"`
+	Features      *AudioFeatures `protobuf:"bytes,10,opt,name=features,proto3" json:"features,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Track) Reset() {
+	*x = Track{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Track) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Track) ProtoMessage() {}
+
+func (x *Track) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Track.ProtoReflect.Descriptor instead.
+func (*Track) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Track) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Track) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Track) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Track) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
+}
+
+func (x *Track) GetAlbum() string {
+	if x != nil {
+		return x.Album
+	}
+	return ""
+}
+
+func (x *Track) GetCoverUrl() string {
+	if x != nil {
+		return x.CoverUrl
+	}
+	return ""
+}
+
+func (x *Track) GetPreviewUrl() string {
+	if x != nil {
+		return x.PreviewUrl
+	}
+	return ""
+}
+
+func (x *Track) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *Track) GetIsrc() string {
+	if x != nil {
+		return x.Isrc
+	}
+	return ""
+}
+
+func (x *Track) GetFeatures() *AudioFeatures {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+type Playlist struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Tracks        []*Track               `protobuf:"bytes,3,rep,name=tracks,proto3" json:"tracks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Playlist) Reset() {
+	*x = Playlist{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Playlist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Playlist) ProtoMessage() {}
+
+func (x *Playlist) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Playlist.ProtoReflect.Descriptor instead.
+func (*Playlist) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Playlist) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Playlist) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Playlist) GetTracks() []*Track {
+	if x != nil {
+		return x.Tracks
+	}
+	return nil
+}
+
+type CreatePlaylistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePlaylistRequest) Reset() {
+	*x = CreatePlaylistRequest{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePlaylistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePlaylistRequest) ProtoMessage() {}
+
+func (x *CreatePlaylistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePlaylistRequest.ProtoReflect.Descriptor instead.
+func (*CreatePlaylistRequest) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreatePlaylistRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetPlaylistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPlaylistRequest) Reset() {
+	*x = GetPlaylistRequest{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPlaylistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPlaylistRequest) ProtoMessage() {}
+
+func (x *GetPlaylistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPlaylistRequest.ProtoReflect.Descriptor instead.
+func (*GetPlaylistRequest) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPlaylistRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type AddTrackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlaylistId    string                 `protobuf:"bytes,1,opt,name=playlist_id,json=playlistId,proto3" json:"playlist_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Artist        string                 `protobuf:"bytes,3,opt,name=artist,proto3" json:"artist,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTrackRequest) Reset() {
+	*x = AddTrackRequest{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTrackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTrackRequest) ProtoMessage() {}
+
+func (x *AddTrackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTrackRequest.ProtoReflect.Descriptor instead.
+func (*AddTrackRequest) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AddTrackRequest) GetPlaylistId() string {
+	if x != nil {
+		return x.PlaylistId
+	}
+	return ""
+}
+
+func (x *AddTrackRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *AddTrackRequest) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
+}
+
+type AddTrackResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	PlaylistId string                 `protobuf:"bytes,1,opt,name=playlist_id,json=playlistId,proto3" json:"playlist_id,omitempty"`
+	TrackId    string                 `protobuf:"bytes,2,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	// job_id identifies the queued analysis job, when jobs are persisted.
+	JobId         string `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTrackResponse) Reset() {
+	*x = AddTrackResponse{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTrackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTrackResponse) ProtoMessage() {}
+
+func (x *AddTrackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTrackResponse.ProtoReflect.Descriptor instead.
+func (*AddTrackResponse) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AddTrackResponse) GetPlaylistId() string {
+	if x != nil {
+		return x.PlaylistId
+	}
+	return ""
+}
+
+func (x *AddTrackResponse) GetTrackId() string {
+	if x != nil {
+		return x.TrackId
+	}
+	return ""
+}
+
+func (x *AddTrackResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type AnalyzePlaylistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlaylistId    string                 `protobuf:"bytes,1,opt,name=playlist_id,json=playlistId,proto3" json:"playlist_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzePlaylistRequest) Reset() {
+	*x = AnalyzePlaylistRequest{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzePlaylistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzePlaylistRequest) ProtoMessage() {}
+
+func (x *AnalyzePlaylistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzePlaylistRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzePlaylistRequest) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AnalyzePlaylistRequest) GetPlaylistId() string {
+	if x != nil {
+		return x.PlaylistId
+	}
+	return ""
+}
+
+type ProcessIntentRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	PlaylistId string                 `protobuf:"bytes,1,opt,name=playlist_id,json=playlistId,proto3" json:"playlist_id,omitempty"`
+	Message    string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Optional: stop adding tracks once the playlist is this long.
+	TargetDurationMs int64 `protobuf:"varint,3,opt,name=target_duration_ms,json=targetDurationMs,proto3" json:"target_duration_ms,omitempty"`
+	ToleranceMs      int64 `protobuf:"varint,4,opt,name=tolerance_ms,json=toleranceMs,proto3" json:"tolerance_ms,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ProcessIntentRequest) Reset() {
+	*x = ProcessIntentRequest{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessIntentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessIntentRequest) ProtoMessage() {}
+
+func (x *ProcessIntentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessIntentRequest.ProtoReflect.Descriptor instead.
+func (*ProcessIntentRequest) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProcessIntentRequest) GetPlaylistId() string {
+	if x != nil {
+		return x.PlaylistId
+	}
+	return ""
+}
+
+func (x *ProcessIntentRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ProcessIntentRequest) GetTargetDurationMs() int64 {
+	if x != nil {
+		return x.TargetDurationMs
+	}
+	return 0
+}
+
+func (x *ProcessIntentRequest) GetToleranceMs() int64 {
+	if x != nil {
+		return x.ToleranceMs
+	}
+	return 0
+}
+
+type IntentEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*IntentEvent_Status
+	//	*IntentEvent_Delta
+	//	*IntentEvent_Result
+	Event         isIntentEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntentEvent) Reset() {
+	*x = IntentEvent{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentEvent) ProtoMessage() {}
+
+func (x *IntentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentEvent.ProtoReflect.Descriptor instead.
+func (*IntentEvent) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *IntentEvent) GetEvent() isIntentEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *IntentEvent) GetStatus() string {
+	if x != nil {
+		if x, ok := x.Event.(*IntentEvent_Status); ok {
+			return x.Status
+		}
+	}
+	return ""
+}
+
+func (x *IntentEvent) GetDelta() *IntentDelta {
+	if x != nil {
+		if x, ok := x.Event.(*IntentEvent_Delta); ok {
+			return x.Delta
+		}
+	}
+	return nil
+}
+
+func (x *IntentEvent) GetResult() *IntentResult {
+	if x != nil {
+		if x, ok := x.Event.(*IntentEvent_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isIntentEvent_Event interface {
+	isIntentEvent_Event()
+}
+
+type IntentEvent_Status struct {
+	// status is "thinking" at the start and "heartbeat" while waiting.
+	Status string `protobuf:"bytes,1,opt,name=status,proto3,oneof"`
+}
+
+type IntentEvent_Delta struct {
+	Delta *IntentDelta `protobuf:"bytes,2,opt,name=delta,proto3,oneof"`
+}
+
+type IntentEvent_Result struct {
+	Result *IntentResult `protobuf:"bytes,3,opt,name=result,proto3,oneof"`
+}
+
+func (*IntentEvent_Status) isIntentEvent_Event() {}
+
+func (*IntentEvent_Delta) isIntentEvent_Event() {}
+
+func (*IntentEvent_Result) isIntentEvent_Event() {}
+
+// IntentDelta is streamed compiler output, when the compiler can stream.
+type IntentDelta struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Thinking      string                 `protobuf:"bytes,1,opt,name=thinking,proto3" json:"thinking,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntentDelta) Reset() {
+	*x = IntentDelta{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntentDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentDelta) ProtoMessage() {}
+
+func (x *IntentDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentDelta.ProtoReflect.Descriptor instead.
+func (*IntentDelta) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *IntentDelta) GetThinking() string {
+	if x != nil {
+		return x.Thinking
+	}
+	return ""
+}
+
+func (x *IntentDelta) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type IntentResult struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	IntentType       string                 `protobuf:"bytes,1,opt,name=intent_type,json=intentType,proto3" json:"intent_type,omitempty"`
+	Artists          []string               `protobuf:"bytes,2,rep,name=artists,proto3" json:"artists,omitempty"`
+	Genres           []string               `protobuf:"bytes,3,rep,name=genres,proto3" json:"genres,omitempty"`
+	Explanation      string                 `protobuf:"bytes,4,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	TracksEvaluated  int64                  `protobuf:"varint,5,opt,name=tracks_evaluated,json=tracksEvaluated,proto3" json:"tracks_evaluated,omitempty"`
+	TracksAdded      int64                  `protobuf:"varint,6,opt,name=tracks_added,json=tracksAdded,proto3" json:"tracks_added,omitempty"`
+	Summary          string                 `protobuf:"bytes,7,opt,name=summary,proto3" json:"summary,omitempty"`
+	TargetDurationMs int64                  `protobuf:"varint,8,opt,name=target_duration_ms,json=targetDurationMs,proto3" json:"target_duration_ms,omitempty"`
+	DurationMs       int64                  `protobuf:"varint,9,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Daypart          string                 `protobuf:"bytes,10,opt,name=daypart,proto3" json:"daypart,omitempty"`
+	Weather          string                 `protobuf:"bytes,11,opt,name=weather,proto3" json:"weather,omitempty"`
+	Focus            bool                   `protobuf:"varint,12,opt,name=focus,proto3" json:"focus,omitempty"`
+	Mood             bool                   `protobuf:"varint,13,opt,name=mood,proto3" json:"mood,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *IntentResult) Reset() {
+	*x = IntentResult{}
+	mi := &file_overture_v1_playlist_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntentResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentResult) ProtoMessage() {}
+
+func (x *IntentResult) ProtoReflect() protoreflect.Message {
+	mi := &file_overture_v1_playlist_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentResult.ProtoReflect.Descriptor instead.
+func (*IntentResult) Descriptor() ([]byte, []int) {
+	return file_overture_v1_playlist_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *IntentResult) GetIntentType() string {
+	if x != nil {
+		return x.IntentType
+	}
+	return ""
+}
+
+func (x *IntentResult) GetArtists() []string {
+	if x != nil {
+		return x.Artists
+	}
+	return nil
+}
+
+func (x *IntentResult) GetGenres() []string {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+func (x *IntentResult) GetExplanation() string {
+	if x != nil {
+		return x.Explanation
+	}
+	return ""
+}
+
+func (x *IntentResult) GetTracksEvaluated() int64 {
+	if x != nil {
+		return x.TracksEvaluated
+	}
+	return 0
+}
+
+func (x *IntentResult) GetTracksAdded() int64 {
+	if x != nil {
+		return x.TracksAdded
+	}
+	return 0
+}
+
+func (x *IntentResult) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *IntentResult) GetTargetDurationMs() int64 {
+	if x != nil {
+		return x.TargetDurationMs
+	}
+	return 0
+}
+
+func (x *IntentResult) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *IntentResult) GetDaypart() string {
+	if x != nil {
+		return x.Daypart
+	}
+	return ""
+}
+
+func (x *IntentResult) GetWeather() string {
+	if x != nil {
+		return x.Weather
+	}
+	return ""
+}
+
+func (x *IntentResult) GetFocus() bool {
+	if x != nil {
+		return x.Focus
+	}
+	return false
+}
+
+func (x *IntentResult) GetMood() bool {
+	if x != nil {
+		return x.Mood
+	}
+	return false
+}
+
+var File_overture_v1_playlist_proto protoreflect.FileDescriptor
+
+const file_overture_v1_playlist_proto_rawDesc = "" +
+	"\n" +
+	"\x1aoverture/v1/playlist.proto\x12\voverture.v1\"\xcb\x01\n" +
+	"\rAudioFeatures\x12\"\n" +
+	"\fdanceability\x18\x01 \x01(\x01R\fdanceability\x12\x16\n" +
+	"\x06energy\x18\x02 \x01(\x01R\x06energy\x12\x18\n" +
+	"\avalence\x18\x03 \x01(\x01R\avalence\x12\x14\n" +
+	"\x05tempo\x18\x04 \x01(\x01R\x05tempo\x12*\n" +
+	"\x10instrumentalness\x18\x05 \x01(\x01R\x10instrumentalness\x12\"\n" +
+	"\facousticness\x18\x06 \x01(\x01R\facousticness\"\x9a\x02\n" +
+	"\x05Track\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x16\n" +
+	"\x06artist\x18\x04 \x01(\tR\x06artist\x12\x14\n" +
+	"\x05album\x18\x05 \x01(\tR\x05album\x12\x1b\n" +
+	"\tcover_url\x18\x06 \x01(\tR\bcoverUrl\x12\x1f\n" +
+	"\vpreview_url\x18\a \x01(\tR\n" +
+	"previewUrl\x12\x1f\n" +
+	"\vduration_ms\x18\b \x01(\x03R\n" +
+	"durationMs\x12\x12\n" +
+	"\x04isrc\x18\t \x01(\tR\x04isrc\x126\n" +
+	"\bfeatures\x18\n" +
+	" \x01(\v2\x1a.overture.v1.AudioFeaturesR\bfeatures\"Z\n" +
+	"\bPlaylist\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12*\n" +
+	"\x06tracks\x18\x03 \x03(\v2\x12.overture.v1.TrackR\x06tracks\"+\n" +
+	"\x15CreatePlaylistRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"$\n" +
+	"\x12GetPlaylistRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"`\n" +
+	"\x0fAddTrackRequest\x12\x1f\n" +
+	"\vplaylist_id\x18\x01 \x01(\tR\n" +
+	"playlistId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06artist\x18\x03 \x01(\tR\x06artist\"e\n" +
+	"\x10AddTrackResponse\x12\x1f\n" +
+	"\vplaylist_id\x18\x01 \x01(\tR\n" +
+	"playlistId\x12\x19\n" +
+	"\btrack_id\x18\x02 \x01(\tR\atrackId\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\"9\n" +
+	"\x16AnalyzePlaylistRequest\x12\x1f\n" +
+	"\vplaylist_id\x18\x01 \x01(\tR\n" +
+	"playlistId\"\xa2\x01\n" +
+	"\x14ProcessIntentRequest\x12\x1f\n" +
+	"\vplaylist_id\x18\x01 \x01(\tR\n" +
+	"playlistId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\x12target_duration_ms\x18\x03 \x01(\x03R\x10targetDurationMs\x12!\n" +
+	"\ftolerance_ms\x18\x04 \x01(\x03R\vtoleranceMs\"\x97\x01\n" +
+	"\vIntentEvent\x12\x18\n" +
+	"\x06status\x18\x01 \x01(\tH\x00R\x06status\x120\n" +
+	"\x05delta\x18\x02 \x01(\v2\x18.overture.v1.IntentDeltaH\x00R\x05delta\x123\n" +
+	"\x06result\x18\x03 \x01(\v2\x19.overture.v1.IntentResultH\x00R\x06resultB\a\n" +
+	"\x05event\"C\n" +
+	"\vIntentDelta\x12\x1a\n" +
+	"\bthinking\x18\x01 \x01(\tR\bthinking\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"\x98\x03\n" +
+	"\fIntentResult\x12\x1f\n" +
+	"\vintent_type\x18\x01 \x01(\tR\n" +
+	"intentType\x12\x18\n" +
+	"\aartists\x18\x02 \x03(\tR\aartists\x12\x16\n" +
+	"\x06genres\x18\x03 \x03(\tR\x06genres\x12 \n" +
+	"\vexplanation\x18\x04 \x01(\tR\vexplanation\x12)\n" +
+	"\x10tracks_evaluated\x18\x05 \x01(\x03R\x0ftracksEvaluated\x12!\n" +
+	"\ftracks_added\x18\x06 \x01(\x03R\vtracksAdded\x12\x18\n" +
+	"\asummary\x18\a \x01(\tR\asummary\x12,\n" +
+	"\x12target_duration_ms\x18\b \x01(\x03R\x10targetDurationMs\x12\x1f\n" +
+	"\vduration_ms\x18\t \x01(\x03R\n" +
+	"durationMs\x12\x18\n" +
+	"\adaypart\x18\n" +
+	" \x01(\tR\adaypart\x12\x18\n" +
+	"\aweather\x18\v \x01(\tR\aweather\x12\x14\n" +
+	"\x05focus\x18\f \x01(\bR\x05focus\x12\x12\n" +
+	"\x04mood\x18\r \x01(\bR\x04mood2\x92\x03\n" +
+	"\x0fPlaylistService\x12K\n" +
+	"\x0eCreatePlaylist\x12\".overture.v1.CreatePlaylistRequest\x1a\x15.overture.v1.Playlist\x12E\n" +
+	"\vGetPlaylist\x12\x1f.overture.v1.GetPlaylistRequest\x1a\x15.overture.v1.Playlist\x12G\n" +
+	"\bAddTrack\x12\x1c.overture.v1.AddTrackRequest\x1a\x1d.overture.v1.AddTrackResponse\x12R\n" +
+	"\x0fAnalyzePlaylist\x12#.overture.v1.AnalyzePlaylistRequest\x1a\x1a.overture.v1.AudioFeatures\x12N\n" +
+	"\rProcessIntent\x12!.overture.v1.ProcessIntentRequest\x1a\x18.overture.v1.IntentEvent0\x01BXZVgithub.com/ewilliams-labs/overture/backend/internal/adapters/rpc/overturev1;overturev1b\x06proto3"
+
+var (
+	file_overture_v1_playlist_proto_rawDescOnce sync.Once
+	file_overture_v1_playlist_proto_rawDescData []byte
+)
+
+func file_overture_v1_playlist_proto_rawDescGZIP() []byte {
+	file_overture_v1_playlist_proto_rawDescOnce.Do(func() {
+		file_overture_v1_playlist_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_overture_v1_playlist_proto_rawDesc), len(file_overture_v1_playlist_proto_rawDesc)))
+	})
+	return file_overture_v1_playlist_proto_rawDescData
+}
+
+var file_overture_v1_playlist_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_overture_v1_playlist_proto_goTypes = []any{
+	(*AudioFeatures)(nil),          // 0: overture.v1.AudioFeatures
+	(*Track)(nil),                  // 1: overture.v1.Track
+	(*Playlist)(nil),               // 2: overture.v1.Playlist
+	(*CreatePlaylistRequest)(nil),  // 3: overture.v1.CreatePlaylistRequest
+	(*GetPlaylistRequest)(nil),     // 4: overture.v1.GetPlaylistRequest
+	(*AddTrackRequest)(nil),        // 5: overture.v1.AddTrackRequest
+	(*AddTrackResponse)(nil),       // 6: overture.v1.AddTrackResponse
+	(*AnalyzePlaylistRequest)(nil), // 7: overture.v1.AnalyzePlaylistRequest
+	(*ProcessIntentRequest)(nil),   // 8: overture.v1.ProcessIntentRequest
+	(*IntentEvent)(nil),            // 9: overture.v1.IntentEvent
+	(*IntentDelta)(nil),            // 10: overture.v1.IntentDelta
+	(*IntentResult)(nil),           // 11: overture.v1.IntentResult
+}
+var file_overture_v1_playlist_proto_depIdxs = []int32{
+	0,  // 0: overture.v1.Track.features:type_name -> overture.v1.AudioFeatures
+	1,  // 1: overture.v1.Playlist.tracks:type_name -> overture.v1.Track
+	10, // 2: overture.v1.IntentEvent.delta:type_name -> overture.v1.IntentDelta
+	11, // 3: overture.v1.IntentEvent.result:type_name -> overture.v1.IntentResult
+	3,  // 4: overture.v1.PlaylistService.CreatePlaylist:input_type -> overture.v1.CreatePlaylistRequest
+	4,  // 5: overture.v1.PlaylistService.GetPlaylist:input_type -> overture.v1.GetPlaylistRequest
+	5,  // 6: overture.v1.PlaylistService.AddTrack:input_type -> overture.v1.AddTrackRequest
+	7,  // 7: overture.v1.PlaylistService.AnalyzePlaylist:input_type -> overture.v1.AnalyzePlaylistRequest
+	8,  // 8: overture.v1.PlaylistService.ProcessIntent:input_type -> overture.v1.ProcessIntentRequest
+	2,  // 9: overture.v1.PlaylistService.CreatePlaylist:output_type -> overture.v1.Playlist
+	2,  // 10: overture.v1.PlaylistService.GetPlaylist:output_type -> overture.v1.Playlist
+	6,  // 11: overture.v1.PlaylistService.AddTrack:output_type -> overture.v1.AddTrackResponse
+	0,  // 12: overture.v1.PlaylistService.AnalyzePlaylist:output_type -> overture.v1.AudioFeatures
+	9,  // 13: overture.v1.PlaylistService.ProcessIntent:output_type -> overture.v1.IntentEvent
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_overture_v1_playlist_proto_init() }
+func file_overture_v1_playlist_proto_init() {
+	if File_overture_v1_playlist_proto != nil {
+		return
+	}
+	file_overture_v1_playlist_proto_msgTypes[9].OneofWrappers = []any{
+		(*IntentEvent_Status)(nil),
+		(*IntentEvent_Delta)(nil),
+		(*IntentEvent_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_overture_v1_playlist_proto_rawDesc), len(file_overture_v1_playlist_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_overture_v1_playlist_proto_goTypes,
+		DependencyIndexes: file_overture_v1_playlist_proto_depIdxs,
+		MessageInfos:      file_overture_v1_playlist_proto_msgTypes,
+	}.Build()
+	File_overture_v1_playlist_proto = out.File
+	file_overture_v1_playlist_proto_goTypes = nil
+	file_overture_v1_playlist_proto_depIdxs = nil
+}
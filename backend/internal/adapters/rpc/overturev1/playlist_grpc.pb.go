@@ -0,0 +1,301 @@
+// The gRPC interface to Overture's core, for internal services that would
+// rather not go through JSON over HTTP. It mirrors the REST playlist routes.
+// Regenerate internal/adapters/rpc/overturev1 with `just proto` after editing.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: overture/v1/playlist.proto
+
+package overturev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PlaylistService_CreatePlaylist_FullMethodName  = "/overture.v1.PlaylistService/CreatePlaylist"
+	PlaylistService_GetPlaylist_FullMethodName     = "/overture.v1.PlaylistService/GetPlaylist"
+	PlaylistService_AddTrack_FullMethodName        = "/overture.v1.PlaylistService/AddTrack"
+	PlaylistService_AnalyzePlaylist_FullMethodName = "/overture.v1.PlaylistService/AnalyzePlaylist"
+	PlaylistService_ProcessIntent_FullMethodName   = "/overture.v1.PlaylistService/ProcessIntent"
+)
+
+// PlaylistServiceClient is the client API for PlaylistService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PlaylistService manages playlists and fills them from natural-language
+// intents. Errors use the standard status codes: NOT_FOUND for unknown
+// playlists, INVALID_ARGUMENT for bad input, UNIMPLEMENTED when the intent
+// compiler is not configured.
+type PlaylistServiceClient interface {
+	CreatePlaylist(ctx context.Context, in *CreatePlaylistRequest, opts ...grpc.CallOption) (*Playlist, error)
+	GetPlaylist(ctx context.Context, in *GetPlaylistRequest, opts ...grpc.CallOption) (*Playlist, error)
+	// AddTrack looks a track up on Spotify, adds it and queues its audio
+	// analysis.
+	AddTrack(ctx context.Context, in *AddTrackRequest, opts ...grpc.CallOption) (*AddTrackResponse, error)
+	// AnalyzePlaylist returns the average audio features of the playlist.
+	AnalyzePlaylist(ctx context.Context, in *AnalyzePlaylistRequest, opts ...grpc.CallOption) (*AudioFeatures, error)
+	// ProcessIntent streams progress while an intent is analyzed and applied,
+	// ending with its result.
+	ProcessIntent(ctx context.Context, in *ProcessIntentRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IntentEvent], error)
+}
+
+type playlistServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlaylistServiceClient(cc grpc.ClientConnInterface) PlaylistServiceClient {
+	return &playlistServiceClient{cc}
+}
+
+func (c *playlistServiceClient) CreatePlaylist(ctx context.Context, in *CreatePlaylistRequest, opts ...grpc.CallOption) (*Playlist, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Playlist)
+	err := c.cc.Invoke(ctx, PlaylistService_CreatePlaylist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playlistServiceClient) GetPlaylist(ctx context.Context, in *GetPlaylistRequest, opts ...grpc.CallOption) (*Playlist, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Playlist)
+	err := c.cc.Invoke(ctx, PlaylistService_GetPlaylist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playlistServiceClient) AddTrack(ctx context.Context, in *AddTrackRequest, opts ...grpc.CallOption) (*AddTrackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddTrackResponse)
+	err := c.cc.Invoke(ctx, PlaylistService_AddTrack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playlistServiceClient) AnalyzePlaylist(ctx context.Context, in *AnalyzePlaylistRequest, opts ...grpc.CallOption) (*AudioFeatures, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AudioFeatures)
+	err := c.cc.Invoke(ctx, PlaylistService_AnalyzePlaylist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playlistServiceClient) ProcessIntent(ctx context.Context, in *ProcessIntentRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IntentEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PlaylistService_ServiceDesc.Streams[0], PlaylistService_ProcessIntent_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ProcessIntentRequest, IntentEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PlaylistService_ProcessIntentClient = grpc.ServerStreamingClient[IntentEvent]
+
+// PlaylistServiceServer is the server API for PlaylistService service.
+// All implementations must embed UnimplementedPlaylistServiceServer
+// for forward compatibility.
+//
+// PlaylistService manages playlists and fills them from natural-language
+// intents. Errors use the standard status codes: NOT_FOUND for unknown
+// playlists, INVALID_ARGUMENT for bad input, UNIMPLEMENTED when the intent
+// compiler is not configured.
+type PlaylistServiceServer interface {
+	CreatePlaylist(context.Context, *CreatePlaylistRequest) (*Playlist, error)
+	GetPlaylist(context.Context, *GetPlaylistRequest) (*Playlist, error)
+	// AddTrack looks a track up on Spotify, adds it and queues its audio
+	// analysis.
+	AddTrack(context.Context, *AddTrackRequest) (*AddTrackResponse, error)
+	// AnalyzePlaylist returns the average audio features of the playlist.
+	AnalyzePlaylist(context.Context, *AnalyzePlaylistRequest) (*AudioFeatures, error)
+	// ProcessIntent streams progress while an intent is analyzed and applied,
+	// ending with its result.
+	ProcessIntent(*ProcessIntentRequest, grpc.ServerStreamingServer[IntentEvent]) error
+	mustEmbedUnimplementedPlaylistServiceServer()
+}
+
+// UnimplementedPlaylistServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPlaylistServiceServer struct{}
+
+func (UnimplementedPlaylistServiceServer) CreatePlaylist(context.Context, *CreatePlaylistRequest) (*Playlist, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePlaylist not implemented")
+}
+func (UnimplementedPlaylistServiceServer) GetPlaylist(context.Context, *GetPlaylistRequest) (*Playlist, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlaylist not implemented")
+}
+func (UnimplementedPlaylistServiceServer) AddTrack(context.Context, *AddTrackRequest) (*AddTrackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTrack not implemented")
+}
+func (UnimplementedPlaylistServiceServer) AnalyzePlaylist(context.Context, *AnalyzePlaylistRequest) (*AudioFeatures, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzePlaylist not implemented")
+}
+func (UnimplementedPlaylistServiceServer) ProcessIntent(*ProcessIntentRequest, grpc.ServerStreamingServer[IntentEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method ProcessIntent not implemented")
+}
+func (UnimplementedPlaylistServiceServer) mustEmbedUnimplementedPlaylistServiceServer() {}
+func (UnimplementedPlaylistServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafePlaylistServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlaylistServiceServer will
+// result in compilation errors.
+type UnsafePlaylistServiceServer interface {
+	mustEmbedUnimplementedPlaylistServiceServer()
+}
+
+func RegisterPlaylistServiceServer(s grpc.ServiceRegistrar, srv PlaylistServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPlaylistServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PlaylistService_ServiceDesc, srv)
+}
+
+func _PlaylistService_CreatePlaylist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePlaylistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlaylistServiceServer).CreatePlaylist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlaylistService_CreatePlaylist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlaylistServiceServer).CreatePlaylist(ctx, req.(*CreatePlaylistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlaylistService_GetPlaylist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlaylistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlaylistServiceServer).GetPlaylist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlaylistService_GetPlaylist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlaylistServiceServer).GetPlaylist(ctx, req.(*GetPlaylistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlaylistService_AddTrack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlaylistServiceServer).AddTrack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlaylistService_AddTrack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlaylistServiceServer).AddTrack(ctx, req.(*AddTrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlaylistService_AnalyzePlaylist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzePlaylistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlaylistServiceServer).AnalyzePlaylist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlaylistService_AnalyzePlaylist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlaylistServiceServer).AnalyzePlaylist(ctx, req.(*AnalyzePlaylistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlaylistService_ProcessIntent_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ProcessIntentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlaylistServiceServer).ProcessIntent(m, &grpc.GenericServerStream[ProcessIntentRequest, IntentEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PlaylistService_ProcessIntentServer = grpc.ServerStreamingServer[IntentEvent]
+
+// PlaylistService_ServiceDesc is the grpc.ServiceDesc for PlaylistService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PlaylistService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "overture.v1.PlaylistService",
+	HandlerType: (*PlaylistServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePlaylist",
+			Handler:    _PlaylistService_CreatePlaylist_Handler,
+		},
+		{
+			MethodName: "GetPlaylist",
+			Handler:    _PlaylistService_GetPlaylist_Handler,
+		},
+		{
+			MethodName: "AddTrack",
+			Handler:    _PlaylistService_AddTrack_Handler,
+		},
+		{
+			MethodName: "AnalyzePlaylist",
+			Handler:    _PlaylistService_AnalyzePlaylist_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ProcessIntent",
+			Handler:       _PlaylistService_ProcessIntent_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "overture/v1/playlist.proto",
+}
@@ -0,0 +1,267 @@
+// Package rpc exposes Overture's core over gRPC for internal services, next
+// to the REST adapter. The service is defined in proto/overture/v1.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	pb "github.com/ewilliams-labs/overture/backend/internal/adapters/rpc/overturev1"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/backend/internal/worker"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// heartbeatInterval matches the REST intent stream's heartbeats.
+const heartbeatInterval = 10 * time.Second
+
+// Server implements pb.PlaylistServiceServer on top of the core service.
+type Server struct {
+	pb.UnimplementedPlaylistServiceServer
+
+	svc      ports.PlaylistService
+	pool     *worker.Pool
+	reporter ports.ErrorReporter
+	logger   *slog.Logger
+}
+
+// Option configures optional Server features.
+type Option func(*Server)
+
+// WithErrorReporter reports recovered panics with the method name.
+func WithErrorReporter(reporter ports.ErrorReporter) Option {
+	return func(s *Server) {
+		s.reporter = reporter
+	}
+}
+
+// WithLogger logs through l instead of the default logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = l
+	}
+}
+
+// NewServer returns a Server backed by svc. Tracks added through it are
+// submitted to pool for analysis; pool may be nil.
+func NewServer(svc ports.PlaylistService, pool *worker.Pool, opts ...Option) *Server {
+	s := &Server{svc: svc, pool: pool}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.logger = logging.Component(s.logger, "rpc")
+	return s
+}
+
+// GRPCServer returns a *grpc.Server with the PlaylistService registered and
+// panics turned into Internal errors.
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(s.recoverUnary),
+		grpc.ChainStreamInterceptor(s.recoverStream),
+	)
+	srv := grpc.NewServer(opts...)
+	pb.RegisterPlaylistServiceServer(srv, s)
+	return srv
+}
+
+// CreatePlaylist creates an empty playlist.
+func (s *Server) CreatePlaylist(ctx context.Context, req *pb.CreatePlaylistRequest) (*pb.Playlist, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	playlist, err := s.svc.CreatePlaylist(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPlaylist(playlist), nil
+}
+
+// GetPlaylist returns a playlist with its tracks.
+func (s *Server) GetPlaylist(ctx context.Context, req *pb.GetPlaylistRequest) (*pb.Playlist, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	playlist, err := s.svc.GetPlaylist(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPlaylist(playlist), nil
+}
+
+// AddTrack adds a track found on Spotify and queues its analysis.
+func (s *Server) AddTrack(ctx context.Context, req *pb.AddTrackRequest) (*pb.AddTrackResponse, error) {
+	if req.GetPlaylistId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "playlist_id is required")
+	}
+	if req.GetTitle() == "" || req.GetArtist() == "" {
+		return nil, status.Error(codes.InvalidArgument, "title and artist are required")
+	}
+	playlistID, trackID, previewURL, err := s.svc.AddTrackToPlaylist(ctx, req.GetPlaylistId(), req.GetTitle(), req.GetArtist())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.AddTrackResponse{PlaylistId: playlistID, TrackId: trackID}
+	if s.pool != nil {
+		resp.JobId, _ = s.pool.SubmitTracked(worker.Job{TrackID: trackID, PreviewURL: previewURL, Trace: trace.SpanContextFromContext(ctx)})
+	}
+	return resp, nil
+}
+
+// AnalyzePlaylist returns the playlist's average audio features.
+func (s *Server) AnalyzePlaylist(ctx context.Context, req *pb.AnalyzePlaylistRequest) (*pb.AudioFeatures, error) {
+	if req.GetPlaylistId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "playlist_id is required")
+	}
+	features, err := s.svc.GetPlaylistAnalysis(ctx, req.GetPlaylistId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toFeatures(features), nil
+}
+
+// ProcessIntent applies an intent, streaming a "thinking" status, compiler
+// deltas and heartbeats before the result. Like the REST stream, processing
+// continues when the client goes away so its writes are not left half done.
+func (s *Server) ProcessIntent(req *pb.ProcessIntentRequest, stream grpc.ServerStreamingServer[pb.IntentEvent]) error {
+	if req.GetPlaylistId() == "" {
+		return status.Error(codes.InvalidArgument, "playlist_id is required")
+	}
+	if req.GetMessage() == "" {
+		return status.Error(codes.InvalidArgument, "message is required")
+	}
+	if req.GetTargetDurationMs() < 0 || req.GetToleranceMs() < 0 {
+		return status.Error(codes.InvalidArgument, "target_duration_ms and tolerance_ms cannot be negative")
+	}
+	if !s.svc.HasIntentCompiler() {
+		return status.Error(codes.Unimplemented, "intent compiler not configured")
+	}
+	target := domain.DurationTarget{DurationMs: int(req.GetTargetDurationMs()), ToleranceMs: int(req.GetToleranceMs())}
+
+	if err := stream.Send(statusEvent("thinking")); err != nil {
+		return err
+	}
+
+	type outcome struct {
+		result domain.IntentResult
+		err    error
+	}
+	resultCh := make(chan outcome, 1)
+	deltaCh := make(chan domain.IntentDelta, 64)
+	done := make(chan struct{})
+	defer close(done)
+	onDelta := func(d domain.IntentDelta) {
+		select {
+		case deltaCh <- d:
+		case <-done:
+		}
+	}
+
+	ctx := stream.Context()
+	detached := context.WithoutCancel(ctx)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				s.recovered(ctx, "ProcessIntent", rec)
+				resultCh <- outcome{err: status.Error(codes.Internal, "internal server error")}
+			}
+		}()
+		result, err := s.svc.ProcessIntentStream(detached, req.GetPlaylistId(), req.GetMessage(), domain.IntentOptions{Duration: target}, onDelta)
+		resultCh <- outcome{result: result, err: err}
+	}()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+			if err := stream.Send(statusEvent("heartbeat")); err != nil {
+				return err
+			}
+		case d := <-deltaCh:
+			if err := stream.Send(deltaEvent(d)); err != nil {
+				return err
+			}
+		case out := <-resultCh:
+			for len(deltaCh) > 0 {
+				if err := stream.Send(deltaEvent(<-deltaCh)); err != nil {
+					return err
+				}
+			}
+			if out.err != nil {
+				return toStatus(out.err)
+			}
+			return stream.Send(&pb.IntentEvent{Event: &pb.IntentEvent_Result{Result: toIntentResult(out.result)}})
+		}
+	}
+}
+
+// toStatus maps core errors to gRPC status codes.
+func toStatus(err error) error {
+	var matchErr *ports.NoConfidentMatchError
+	switch {
+	case status.Code(err) != codes.Unknown:
+		return err
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, domain.ErrNotFound.Error())
+	case errors.As(err, &matchErr):
+		return status.Error(codes.FailedPrecondition, matchErr.Error())
+	case errors.Is(err, domain.ErrDuplicateISRC), errors.Is(err, domain.ErrDuplicateTrack), errors.Is(err, domain.ErrDuplicateRecording):
+		return status.Error(codes.AlreadyExists, "track is already in the playlist")
+	case errors.Is(err, domain.ErrExplicitTrack):
+		return status.Error(codes.FailedPrecondition, "the playlist does not allow explicit tracks")
+	case errors.Is(err, domain.ErrPlaylistFull):
+		return status.Error(codes.FailedPrecondition, "the playlist has reached its maximum number of tracks")
+	case errors.Is(err, domain.ErrPlaylistLocked):
+		return status.Error(codes.FailedPrecondition, "the playlist's ordering is locked")
+	case errors.Is(err, domain.ErrVersionConflict):
+		// Nothing was saved; the call can simply be retried.
+		return status.Error(codes.Aborted, "the playlist was changed by another request; retry the request")
+	case errors.Is(err, ports.ErrProviderUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func (s *Server) recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			s.recovered(ctx, info.FullMethod, rec)
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func (s *Server) recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			s.recovered(ss.Context(), info.FullMethod, rec)
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// recovered logs and reports a panic raised while serving method.
+func (s *Server) recovered(ctx context.Context, method string, rec any) {
+	err := fmt.Errorf("panic: %v", rec)
+	s.logger.ErrorContext(ctx, "handler panicked", "method", method, "error", err, "stack", string(debug.Stack()))
+	if s.reporter != nil {
+		s.reporter.Report(ctx, err, map[string]string{"grpc.method": method})
+	}
+}
@@ -0,0 +1,196 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	pb "github.com/ewilliams-labs/overture/backend/internal/adapters/rpc/overturev1"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/sqlite"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeSpotify struct{}
+
+func (fakeSpotify) GetTrackByMetadata(ctx context.Context, title, artist string) (domain.Track, error) {
+	return domain.Track{ID: "t-" + title, Title: title, Artist: artist, ISRC: "ISRC-" + title,
+		Features: domain.AudioFeatures{Energy: 0.8, Tempo: 120}}, nil
+}
+
+func (f fakeSpotify) GetTrack(ctx context.Context, title, artist string) (domain.Track, error) {
+	return f.GetTrackByMetadata(ctx, title, artist)
+}
+
+func (fakeSpotify) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	return []domain.Track{{ID: "top1", Title: "Top", Artist: artistName, ISRC: "ISRC-top"}}, nil
+}
+
+type fakeCompiler struct {
+	err error
+}
+
+func (c fakeCompiler) AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error) {
+	if c.err != nil {
+		return domain.IntentObject{}, c.err
+	}
+	var intent domain.IntentObject
+	intent.Explanation = "because " + message
+	intent.Entities.Artists = []string{"Willie Nelson"}
+	return intent, nil
+}
+
+// newClient serves a Server over an in-memory connection.
+func newClient(t *testing.T, compiler fakeCompiler) pb.PlaylistServiceClient {
+	t.Helper()
+	store, err := sqlite.NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("NewAdapter: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	svc := services.NewOrchestrator(fakeSpotify{}, store, compiler)
+
+	ln := bufconn.Listen(1 << 20)
+	srv := NewServer(svc, nil).GRPCServer()
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewPlaylistServiceClient(conn)
+}
+
+func TestServer_Playlists(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t, fakeCompiler{})
+
+	created, err := client.CreatePlaylist(ctx, &pb.CreatePlaylistRequest{Name: "Road Trip"})
+	if err != nil {
+		t.Fatalf("CreatePlaylist: %v", err)
+	}
+	added, err := client.AddTrack(ctx, &pb.AddTrackRequest{PlaylistId: created.GetId(), Title: "Jolene", Artist: "Dolly Parton"})
+	if err != nil {
+		t.Fatalf("AddTrack: %v", err)
+	}
+	if added.GetTrackId() != "t-Jolene" {
+		t.Errorf("track id = %q, want t-Jolene", added.GetTrackId())
+	}
+
+	got, err := client.GetPlaylist(ctx, &pb.GetPlaylistRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("GetPlaylist: %v", err)
+	}
+	if got.GetName() != "Road Trip" || len(got.GetTracks()) != 1 || got.GetTracks()[0].GetType() != "track" {
+		t.Errorf("GetPlaylist = %v", got)
+	}
+	features, err := client.AnalyzePlaylist(ctx, &pb.AnalyzePlaylistRequest{PlaylistId: created.GetId()})
+	if err != nil {
+		t.Fatalf("AnalyzePlaylist: %v", err)
+	}
+	if features.GetEnergy() != 0.8 || features.GetTempo() != 120 {
+		t.Errorf("AnalyzePlaylist = %v", features)
+	}
+
+	_, err = client.AddTrack(ctx, &pb.AddTrackRequest{PlaylistId: created.GetId(), Title: "Jolene", Artist: "Dolly Parton"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("duplicate AddTrack: got %v, want AlreadyExists", err)
+	}
+	_, err = client.GetPlaylist(ctx, &pb.GetPlaylistRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetPlaylist(missing): got %v, want NotFound", err)
+	}
+	_, err = client.CreatePlaylist(ctx, &pb.CreatePlaylistRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreatePlaylist(\"\"): got %v, want InvalidArgument", err)
+	}
+}
+
+func TestServer_ProcessIntent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("streams status then result", func(t *testing.T) {
+		client := newClient(t, fakeCompiler{})
+		created, err := client.CreatePlaylist(ctx, &pb.CreatePlaylistRequest{Name: "Outlaw"})
+		if err != nil {
+			t.Fatalf("CreatePlaylist: %v", err)
+		}
+		stream, err := client.ProcessIntent(ctx, &pb.ProcessIntentRequest{PlaylistId: created.GetId(), Message: "outlaw country"})
+		if err != nil {
+			t.Fatalf("ProcessIntent: %v", err)
+		}
+		var events []*pb.IntentEvent
+		for {
+			ev, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Recv: %v", err)
+			}
+			events = append(events, ev)
+		}
+		if len(events) < 2 || events[0].GetStatus() != "thinking" {
+			t.Fatalf("events = %v, want thinking first", events)
+		}
+		result := events[len(events)-1].GetResult()
+		if result == nil {
+			t.Fatalf("last event = %v, want a result", events[len(events)-1])
+		}
+		if result.GetExplanation() != "because outlaw country" || result.GetTracksAdded() != 1 {
+			t.Errorf("result = %v", result)
+		}
+	})
+
+	t.Run("compiler failure ends the stream with an error", func(t *testing.T) {
+		client := newClient(t, fakeCompiler{err: errors.New("model offline")})
+		created, err := client.CreatePlaylist(ctx, &pb.CreatePlaylistRequest{Name: "Outlaw"})
+		if err != nil {
+			t.Fatalf("CreatePlaylist: %v", err)
+		}
+		stream, err := client.ProcessIntent(ctx, &pb.ProcessIntentRequest{PlaylistId: created.GetId(), Message: "anything"})
+		if err != nil {
+			t.Fatalf("ProcessIntent: %v", err)
+		}
+		for {
+			_, err = stream.Recv()
+			if err != nil {
+				break
+			}
+		}
+		if status.Code(err) != codes.Internal {
+			t.Errorf("Recv: got %v, want Internal", err)
+		}
+	})
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{err: domain.ErrNotFound, want: codes.NotFound},
+		{err: fmt.Errorf("save: %w", domain.ErrVersionConflict), want: codes.Aborted},
+		{err: domain.ErrExplicitTrack, want: codes.FailedPrecondition},
+		{err: domain.ErrPlaylistFull, want: codes.FailedPrecondition},
+		{err: domain.ErrPlaylistLocked, want: codes.FailedPrecondition},
+		{err: errors.New("disk on fire"), want: codes.Internal},
+	}
+	for _, tt := range tests {
+		if got := status.Code(toStatus(tt.err)); got != tt.want {
+			t.Errorf("toStatus(%v): got %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// ArtistCacheConfig controls the artist metadata cache.
+type ArtistCacheConfig struct {
+	Store ports.ArtistStore
+	// TTL is how long a cached artist is used before it is searched again.
+	// Stale entries are still served if the refresh fails.
+	TTL time.Duration
+}
+
+// EnableArtistCache makes artist searches consult cfg.Store first.
+func (c *Client) EnableArtistCache(cfg ArtistCacheConfig) {
+	c.artistCache = &cfg
+}
+
+// lookupArtist resolves an artist name, using the cache when it is enabled
+// and the entry is fresh.
+func (c *Client) lookupArtist(ctx context.Context, name string) (domain.Artist, error) {
+	if c.artistCache == nil {
+		return c.searchArtist(ctx, name)
+	}
+	cfg := c.artistCache
+
+	cached, err := cfg.Store.GetArtist(ctx, name)
+	switch {
+	case err == nil && time.Since(cached.FetchedAt) < cfg.TTL:
+		artistCacheLookups.Inc("hit")
+		return cached, nil
+	case err == nil:
+		artistCacheLookups.Inc("expired")
+	case errors.Is(err, domain.ErrNotFound):
+		artistCacheLookups.Inc("miss")
+	default:
+		artistCacheLookups.Inc("error")
+		c.logger.WarnContext(ctx, "artist cache read failed", "artist", name, "error", err)
+	}
+
+	artist, searchErr := c.searchArtist(ctx, name)
+	if searchErr != nil {
+		if err == nil {
+			// Serve the stale entry rather than failing the whole lookup.
+			artistCacheLookups.Inc("stale")
+			return cached, nil
+		}
+		return domain.Artist{}, searchErr
+	}
+
+	artist.FetchedAt = time.Now().UTC()
+	if err := cfg.Store.SaveArtist(ctx, name, artist); err != nil {
+		c.logger.WarnContext(ctx, "artist cache write failed", "artist", name, "error", err)
+	}
+	return artist, nil
+}
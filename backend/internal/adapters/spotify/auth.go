@@ -0,0 +1,137 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+)
+
+// userScopes let Overture manage the playlists it mirrors into the user's
+// library.
+var userScopes = []string{"playlist-modify-private", "playlist-modify-public"}
+
+// Authorizer implements ports.SpotifyAuthorizer with the authorization code
+// flow and PKCE.
+type Authorizer struct {
+	config    *oauth2.Config
+	baseURL   string
+	scheduler *Scheduler
+	// opts configure the library clients.
+	opts []Option
+	// tokenClient talks to the accounts service. Codes can only be
+	// exchanged once, so token requests are never retried.
+	tokenClient *http.Client
+}
+
+// NewAuthorizer returns an Authorizer for the Spotify application, which
+// must list redirectURL among its redirect URIs. The libraries it returns
+// are clients configured with opts.
+func NewAuthorizer(clientID, clientSecret, redirectURL string, opts ...Option) *Authorizer {
+	return &Authorizer{
+		config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       userScopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  "https://accounts.spotify.com/authorize",
+				TokenURL: "https://accounts.spotify.com/api/token", // #nosec G101 -- Public Spotify OAuth endpoint, not a secret
+			},
+		},
+		baseURL:     BaseURL,
+		opts:        opts,
+		tokenClient: httpx.NewClient("spotify_accounts", 10*time.Second, httpx.NoRetry()),
+	}
+}
+
+// EnableScheduler paces the requests of the libraries the Authorizer
+// returns with s, which should be the catalog client's.
+func (a *Authorizer) EnableScheduler(s *Scheduler) {
+	a.scheduler = s
+}
+
+// AuthCodeURL implements ports.SpotifyAuthorizer.
+func (a *Authorizer) AuthCodeURL(state string) (string, string) {
+	verifier := oauth2.GenerateVerifier()
+	return a.config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)), verifier
+}
+
+// Exchange implements ports.SpotifyAuthorizer.
+func (a *Authorizer) Exchange(ctx context.Context, code, verifier string) (domain.OAuthToken, error) {
+	token, err := a.config.Exchange(a.tokenContext(ctx), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return domain.OAuthToken{}, fmt.Errorf("spotify adapter: exchange authorization code: %w", err)
+	}
+	return fromOAuth2(token), nil
+}
+
+// Library implements ports.SpotifyAuthorizer. The returned Client refreshes
+// the access token as it expires.
+func (a *Authorizer) Library(token domain.OAuthToken, onRefresh func(domain.OAuthToken)) ports.SpotifyLibrary {
+	source := &notifyingSource{
+		base:      a.config.TokenSource(a.tokenContext(context.Background()), toOAuth2(token)),
+		last:      token.AccessToken,
+		onRefresh: onRefresh,
+	}
+	c := newClient(oauth2.NewClient(context.Background(), source), a.baseURL, a.opts)
+	c.scheduler = a.scheduler
+	return c
+}
+
+// tokenContext makes the oauth2 package send token requests through
+// tokenClient.
+func (a *Authorizer) tokenContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, a.tokenClient)
+}
+
+// notifyingSource passes each new access token its base hands out to
+// onRefresh.
+type notifyingSource struct {
+	base      oauth2.TokenSource
+	onRefresh func(domain.OAuthToken)
+
+	mu   sync.Mutex
+	last string
+}
+
+func (s *notifyingSource) Token() (*oauth2.Token, error) {
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	refreshed := token.AccessToken != s.last
+	s.last = token.AccessToken
+	s.mu.Unlock()
+	if refreshed && s.onRefresh != nil {
+		s.onRefresh(fromOAuth2(token))
+	}
+	return token, nil
+}
+
+func fromOAuth2(t *oauth2.Token) domain.OAuthToken {
+	scope, _ := t.Extra("scope").(string)
+	return domain.OAuthToken{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		Expiry:       t.Expiry.UTC(),
+		Scope:        scope,
+	}
+}
+
+func toOAuth2(t domain.OAuthToken) *oauth2.Token {
+	return &oauth2.Token{
+		AccessToken:  t.AccessToken,
+		TokenType:    "Bearer",
+		RefreshToken: t.RefreshToken,
+		Expiry:       t.Expiry,
+	}
+}
@@ -0,0 +1,149 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func newTestAuthorizer(srv *httptest.Server) *Authorizer {
+	a := NewAuthorizer("client-id", "client-secret", "http://localhost:8080/auth/spotify/callback")
+	a.config.Endpoint.AuthURL = srv.URL + "/authorize"
+	a.config.Endpoint.TokenURL = srv.URL + "/api/token"
+	a.baseURL = srv.URL + "/v1"
+	return a
+}
+
+func TestAuthorizer_AuthCodeURL(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	authURL, verifier := newTestAuthorizer(srv).AuthCodeURL("state-1")
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse %q: %v", authURL, err)
+	}
+	q := u.Query()
+	if verifier == "" || q.Get("code_challenge") == "" || q.Get("code_challenge") == verifier {
+		t.Fatalf("expected a PKCE challenge derived from the verifier, got %q", authURL)
+	}
+	for key, want := range map[string]string{
+		"state":                 "state-1",
+		"response_type":         "code",
+		"client_id":             "client-id",
+		"code_challenge_method": "S256",
+		"redirect_uri":          "http://localhost:8080/auth/spotify/callback",
+		"scope":                 "playlist-modify-private playlist-modify-public",
+	} {
+		if got := q.Get(key); got != want {
+			t.Errorf("%s: got %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestAuthorizer_ExchangeAndLibrary(t *testing.T) {
+	var refreshes int
+	var added [][]string
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/token":
+			_ = r.ParseForm()
+			w.Header().Set("Content-Type", "application/json")
+			switch r.Form.Get("grant_type") {
+			case "authorization_code":
+				if r.Form.Get("code") != "code-1" || r.Form.Get("code_verifier") != "verifier-1" {
+					http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `{"access_token":"access-1","token_type":"Bearer","refresh_token":"refresh-1","expires_in":3600,"scope":"playlist-modify-private"}`)
+			case "refresh_token":
+				refreshes++
+				fmt.Fprint(w, `{"access_token":"access-2","token_type":"Bearer","expires_in":3600}`)
+			}
+		case "/v1/me":
+			if r.Header.Get("Authorization") != "Bearer access-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"id":"user-1"}`)
+		case "/v1/users/user-1/playlists":
+			fmt.Fprint(w, `{"id":"sp-1"}`)
+		case "/v1/playlists/sp-1/tracks":
+			var body struct {
+				URIs []string `json:"uris"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			added = append(added, body.URIs)
+			methods = append(methods, r.Method)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"snapshot_id":"s"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	a := newTestAuthorizer(srv)
+	ctx := context.Background()
+
+	if _, err := a.Exchange(ctx, "code-1", "wrong"); err == nil {
+		t.Fatal("expected an error for a mismatched verifier")
+	}
+	token, err := a.Exchange(ctx, "code-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.Scope != "playlist-modify-private" || token.Expiry.IsZero() {
+		t.Fatalf("unexpected token %+v", token)
+	}
+
+	// An expired token is refreshed once and the new one handed back.
+	token.Expiry = time.Now().Add(-time.Minute)
+	var saved []domain.OAuthToken
+	library := a.Library(token, func(t domain.OAuthToken) { saved = append(saved, t) })
+
+	id, err := library.CreatePlaylist(ctx, "Mix")
+	if err != nil || id != "sp-1" {
+		t.Fatalf("create playlist: got %q (%v), want sp-1", id, err)
+	}
+	tracks := make([]domain.Track, 150)
+	for i := range tracks {
+		tracks[i] = domain.Track{ID: fmt.Sprintf("t%d", i)}
+	}
+	tracks[149] = domain.Track{ID: "ep1", Type: domain.ItemEpisode}
+	if err := library.AddTracks(ctx, "sp-1", tracks); err != nil {
+		t.Fatalf("add tracks: %v", err)
+	}
+
+	if refreshes != 1 || len(saved) != 1 || saved[0].AccessToken != "access-2" {
+		t.Fatalf("expected one refresh handed to onRefresh, got %d refreshes and %+v", refreshes, saved)
+	}
+	if len(added) != 2 || len(added[0]) != 100 || len(added[1]) != 50 {
+		t.Fatalf("expected tracks added in batches of 100, got %d batches", len(added))
+	}
+	if added[0][0] != "spotify:track:t0" || !strings.HasPrefix(added[1][49], "spotify:episode:") {
+		t.Fatalf("unexpected URIs %q ... %q", added[0][0], added[1][49])
+	}
+
+	// Replacing puts the first batch and appends the rest.
+	added, methods = nil, nil
+	snapshot, written, err := library.ReplaceTracks(ctx, "sp-1", tracks)
+	if err != nil || snapshot != "s" || written != 150 {
+		t.Fatalf("replace tracks: got %q, %d (%v)", snapshot, written, err)
+	}
+	if strings.Join(methods, ",") != "PUT,POST" || len(added[0]) != 100 || len(added[1]) != 50 {
+		t.Fatalf("expected a PUT of 100 then a POST of 50, got %v", methods)
+	}
+	if _, _, err := library.ReplaceTracks(ctx, "deleted", tracks); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a missing playlist, got %v", err)
+	}
+}
@@ -3,15 +3,23 @@ package spotify
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/backend/internal/match"
 )
 
 const (
 	// BaseURL is the production Spotify API endpoint
 	BaseURL = "https://api.spotify.com/v1"
+	// DefaultMarket is the market tracks are looked up in unless WithMarket
+	// or the request names another.
+	DefaultMarket = "US"
 )
 
 // Client adapts the Spotify API to our Domain interface
@@ -20,36 +28,107 @@ type Client struct {
 	baseURL     string
 	maxRetries  int
 	baseBackoff time.Duration
+	artistCache *ArtistCacheConfig
+	searchCache *SearchCacheConfig
+	scheduler   *Scheduler
+	// featureFallback supplies features Spotify has none for.
+	featureFallback ports.FeatureProvider
+	logger          *slog.Logger
+	// matcher scores search results.
+	matcher match.Matcher
+	// market is the country availability and results are for, unless the
+	// request's context names another.
+	market string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithLogger logs through l instead of the default logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithRetries retries failed requests up to maxRetries times, waiting
+// baseBackoff before the first retry and doubling it for each one after.
+// Zero values keep the defaults of 3 retries and 500ms.
+func WithRetries(maxRetries int, baseBackoff time.Duration) Option {
+	return func(c *Client) {
+		if maxRetries > 0 {
+			c.maxRetries = maxRetries
+		}
+		if baseBackoff > 0 {
+			c.baseBackoff = baseBackoff
+		}
+	}
+}
+
+// WithMinConfidence accepts search matches scoring at least threshold,
+// between 0 and 1, instead of the default 0.5.
+func WithMinConfidence(threshold float64) Option {
+	return func(c *Client) {
+		c.matcher.MinScore = threshold
+	}
+}
+
+// WithMarket looks tracks up in market, a two-letter country code,
+// instead of DefaultMarket.
+func WithMarket(market string) Option {
+	return func(c *Client) {
+		if market != "" {
+			c.market = market
+		}
+	}
+}
+
+// marketFor returns the market of requests made with ctx: the one
+// ports.WithMarket set, or the client's.
+func (c *Client) marketFor(ctx context.Context) string {
+	if market := ports.MarketFrom(ctx); market != "" {
+		return market
+	}
+	return c.market
+}
+
+// newClient applies opts to a client sending requests with httpClient.
+func newClient(httpClient *http.Client, baseURL string, opts []Option) *Client {
+	c := &Client{
+		httpClient:  httpClient,
+		baseURL:     baseURL,
+		maxRetries:  defaultMaxRetries,
+		baseBackoff: defaultBackoff,
+		matcher:     match.Default,
+		market:      DefaultMarket,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.logger = logging.Component(c.logger, "spotify")
+	return c
+}
+
+// EnableScheduler paces the client's requests with s. Clients sharing a
+// Spotify application should share its scheduler, as they share its rate
+// limit.
+func (c *Client) EnableScheduler(s *Scheduler) {
+	c.scheduler = s
 }
 
 // NewClient creates a standard Spotify client.
-func NewClient(clientID, clientSecret string) *Client {
+func NewClient(clientID, clientSecret string, opts ...Option) *Client {
 	config := &clientcredentials.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 		TokenURL:     "https://accounts.spotify.com/api/token", // #nosec G101 -- Public Spotify OAuth endpoint, not a secret
 	}
 
-	httpClient := config.Client(context.Background())
-	maxRetries, baseBackoff := getRetryConfig()
-
-	return &Client{
-		httpClient:  httpClient,
-		baseURL:     BaseURL,
-		maxRetries:  maxRetries,
-		baseBackoff: baseBackoff,
-	}
+	return newClient(config.Client(context.Background()), BaseURL, opts)
 }
 
 // NewClientWithBaseURL creates a client with a custom base URL.
 // This is strictly for TESTS (injecting the mock server URL).
-func NewClientWithBaseURL(httpClient *http.Client, baseURL string) *Client {
-	maxRetries, baseBackoff := getRetryConfig()
-
-	return &Client{
-		httpClient:  httpClient,
-		baseURL:     baseURL,
-		maxRetries:  maxRetries,
-		baseBackoff: baseBackoff,
-	}
+func NewClientWithBaseURL(httpClient *http.Client, baseURL string, opts ...Option) *Client {
+	return newClient(httpClient, baseURL, opts)
 }
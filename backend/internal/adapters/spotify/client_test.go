@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"hash/fnv"
+	"io"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/cache"
 	"github.com/ewilliams-labs/overture/backend/internal/adapters/spotify"
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
 	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
@@ -109,6 +113,19 @@ func deterministicFeatures(trackID string) domain.AudioFeatures {
 	}
 }
 
+// stubFeatures is a ports.FeatureProvider knowing features by ISRC.
+type stubFeatures struct {
+	features map[string]domain.AudioFeatures
+}
+
+func (s *stubFeatures) GetFeatures(ctx context.Context, isrc string) (domain.AudioFeatures, error) {
+	f, ok := s.features[isrc]
+	if !ok {
+		return domain.AudioFeatures{}, domain.ErrNotFound
+	}
+	return f, nil
+}
+
 // --- Tests ---
 
 func TestGetTrackByMetadata(t *testing.T) {
@@ -121,7 +138,7 @@ func TestGetTrackByMetadata(t *testing.T) {
 		expectedTrack domain.Track
 		expectErr     bool
 		expectErrIs   error
-		minConfidence string
+		minConfidence float64
 	}{
 		{
 			name:       "successful track retrieval",
@@ -135,9 +152,14 @@ func TestGetTrackByMetadata(t *testing.T) {
 							"id": "1",
 							"name": "Test Track",
 							"duration_ms": 200000,
+							"preview_url": "http://p.com/1.mp3",
+							"popularity": 73,
+							"explicit": true,
+							"external_ids": { "isrc": "USAAA1000001" },
 							"artists": [ { "name": "Test Artist" } ],
 							"album": {
 								"name": "Test Album",
+								"release_date": "1997-05",
 								"images": [ { "url": "http://img.com/1.jpg" } ]
 							}
 						}
@@ -145,14 +167,18 @@ func TestGetTrackByMetadata(t *testing.T) {
 				}
 			}`,
 			expectedTrack: domain.Track{
-				ID:         "1",
-				Title:      "Test Track",
-				Artist:     "Test Artist",
-				Album:      "Test Album",
-				CoverURL:   "http://img.com/1.jpg",
-				DurationMs: 200000,
-				ISRC:       "",
-				Features:   domain.AudioFeatures{},
+				ID:          "1",
+				Title:       "Test Track",
+				Artist:      "Test Artist",
+				Album:       "Test Album",
+				CoverURL:    "http://img.com/1.jpg",
+				PreviewURL:  "http://p.com/1.mp3",
+				DurationMs:  200000,
+				ISRC:        "USAAA1000001",
+				Popularity:  73,
+				ReleaseYear: 1997,
+				Explicit:    true,
+				Features:    domain.AudioFeatures{},
 			},
 			expectErr: false,
 		},
@@ -231,7 +257,7 @@ func TestGetTrackByMetadata(t *testing.T) {
 			}`,
 			expectErr:     true,
 			expectErrIs:   ports.ErrNoConfidentMatch,
-			minConfidence: "0.8",
+			minConfidence: 0.8,
 		},
 	}
 
@@ -257,10 +283,11 @@ func TestGetTrackByMetadata(t *testing.T) {
 			}))
 			defer ts.Close()
 
-			client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL)
-			if tt.minConfidence != "" {
-				t.Setenv("SPOTIFY_MIN_CONFIDENCE", tt.minConfidence)
+			var opts []spotify.Option
+			if tt.minConfidence != 0 {
+				opts = append(opts, spotify.WithMinConfidence(tt.minConfidence))
 			}
+			client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL, opts...)
 
 			track, err := client.GetTrackByMetadata(context.Background(), tt.title, tt.artist)
 			if (err != nil) != tt.expectErr {
@@ -357,6 +384,7 @@ func TestGetTrack(t *testing.T) {
 		featuresStatus int
 		featuresBody   string
 		expectErr      bool
+		fallback       *stubFeatures
 		want           domain.Track
 		wantFeatures   domain.AudioFeatures
 	}{
@@ -521,6 +549,72 @@ func TestGetTrack(t *testing.T) {
 			},
 			wantFeatures: deterministicFeatures("track-4"),
 		},
+		{
+			name:         "features restricted uses the fallback provider by ISRC",
+			title:        "Measured Track",
+			artist:       "Test Artist",
+			searchStatus: http.StatusOK,
+			searchBody: `{
+				"tracks": {
+					"items": [
+						{
+							"id": "track-5",
+							"name": "Measured Track",
+							"duration_ms": 200000,
+							"artists": [ { "name": "Test Artist" } ],
+							"album": { "name": "Test Album", "images": [] },
+							"external_ids": { "isrc": "USABC1234567" }
+						}
+					]
+				}
+			}`,
+			featuresStatus: http.StatusForbidden,
+			featuresBody:   `{ "error": "restricted" }`,
+			fallback: &stubFeatures{features: map[string]domain.AudioFeatures{
+				"USABC1234567": {Danceability: 0.6, Energy: 0.8, Valence: 0.5, Tempo: 124, Instrumentalness: 0.1, Acousticness: 0.2},
+			}},
+			want: domain.Track{
+				ID:         "track-5",
+				Title:      "Measured Track",
+				Artist:     "Test Artist",
+				Album:      "Test Album",
+				DurationMs: 200000,
+				ISRC:       "USABC1234567",
+			},
+			wantFeatures: domain.AudioFeatures{Danceability: 0.6, Energy: 0.8, Valence: 0.5, Tempo: 124, Instrumentalness: 0.1, Acousticness: 0.2},
+		},
+		{
+			name:         "fallback provider miss falls back to deterministic",
+			title:        "Unmeasured Track",
+			artist:       "Test Artist",
+			searchStatus: http.StatusOK,
+			searchBody: `{
+				"tracks": {
+					"items": [
+						{
+							"id": "track-6",
+							"name": "Unmeasured Track",
+							"duration_ms": 200000,
+							"artists": [ { "name": "Test Artist" } ],
+							"album": { "name": "Test Album", "images": [] },
+							"external_ids": { "isrc": "USXYZ7654321" }
+						}
+					]
+				}
+			}`,
+			featuresStatus: http.StatusNotFound,
+			featuresBody:   `{}`,
+			fallback:       &stubFeatures{},
+			want: domain.Track{
+				ID:         "track-6",
+				Title:      "Unmeasured Track",
+				Artist:     "Test Artist",
+				Album:      "Test Album",
+				DurationMs: 200000,
+				ISRC:       "USXYZ7654321",
+			},
+			wantFeatures: deterministicFeatures("track-6"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -558,6 +652,10 @@ func TestGetTrack(t *testing.T) {
 					featuresCalled = true
 					w.WriteHeader(tt.featuresStatus)
 					w.Write([]byte(tt.featuresBody))
+				case r.URL.Path == "/audio-features/track-5", r.URL.Path == "/audio-features/track-6":
+					featuresCalled = true
+					w.WriteHeader(tt.featuresStatus)
+					w.Write([]byte(tt.featuresBody))
 				default:
 					t.Fatalf("unexpected path: %s", r.URL.Path)
 				}
@@ -565,6 +663,9 @@ func TestGetTrack(t *testing.T) {
 			defer ts.Close()
 
 			client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL)
+			if tt.fallback != nil {
+				client.EnableFeatureFallback(tt.fallback)
+			}
 
 			track, err := client.GetTrack(context.Background(), tt.title, tt.artist)
 			if (err != nil) != tt.expectErr {
@@ -587,3 +688,538 @@ func TestGetTrack(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchEpisodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		searchStatus int
+		searchBody   string
+		wantErr      bool
+		want         []domain.Episode
+	}{
+		{
+			name:         "no results",
+			searchStatus: http.StatusOK,
+			searchBody:   `{ "episodes": { "items": [] } }`,
+			want:         []domain.Episode{},
+		},
+		{
+			name:         "search failure",
+			searchStatus: http.StatusBadRequest,
+			searchBody:   `{ "error": "bad query" }`,
+			wantErr:      true,
+		},
+		{
+			name:         "fetches shows and skips unavailable episodes",
+			searchStatus: http.StatusOK,
+			searchBody:   `{ "episodes": { "items": [ { "id": "ep-2" }, null, { "id": "ep-1" } ] } }`,
+			want: []domain.Episode{
+				{
+					ID: "ep-2", Title: "Tuesday Briefing", Show: "The Daily", Description: "News",
+					DurationMs: 600000, PublishedAt: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+					CoverURL: "http://img.com/daily.jpg", PreviewURL: "http://audio.com/ep-2.mp3", Explicit: true,
+				},
+				{ID: "ep-1", Title: "Pilot", Show: "Old Show", PublishedAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/search":
+					if r.URL.Query().Get("type") != "episode" || r.URL.Query().Get("q") != "news briefing" {
+						t.Errorf("unexpected search query %s", r.URL.RawQuery)
+					}
+					w.WriteHeader(tt.searchStatus)
+					w.Write([]byte(tt.searchBody))
+				case "/episodes":
+					if got := r.URL.Query().Get("ids"); got != "ep-2,ep-1" {
+						t.Errorf("ids param: got %q, want %q", got, "ep-2,ep-1")
+					}
+					w.Write([]byte(`{ "episodes": [
+						{
+							"id": "ep-2", "name": "Tuesday Briefing", "description": "News", "duration_ms": 600000,
+							"release_date": "2024-03-05", "release_date_precision": "day",
+							"audio_preview_url": "http://audio.com/ep-2.mp3", "explicit": true,
+							"images": [ { "url": "http://img.com/daily.jpg" } ],
+							"show": { "name": "The Daily" }
+						},
+						{ "id": "ep-1", "name": "Pilot", "release_date": "2019", "release_date_precision": "year", "show": { "name": "Old Show" } }
+					] }`))
+				default:
+					t.Fatalf("unexpected path: %s", r.URL.Path)
+				}
+			}))
+			defer ts.Close()
+
+			client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL)
+			got, err := client.SearchEpisodes(context.Background(), "news briefing", 5)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAlbum(t *testing.T) {
+	tests := []struct {
+		name       string
+		searchBody string
+		wantErr    error
+		wantTracks []string
+	}{
+		{
+			name:       "no match",
+			searchBody: `{ "albums": { "items": [ { "id": "al-x", "name": "Something Else", "artists": [ { "name": "Nobody" } ] } ] } }`,
+			wantErr:    ports.ErrNoConfidentMatch,
+		},
+		{
+			name: "follows track pages in order",
+			searchBody: `{ "albums": { "items": [
+				{ "id": "al-1", "name": "Test Album", "release_date": "2020-01-31",
+				  "artists": [ { "name": "Test Artist" } ], "images": [ { "url": "http://img.com/al-1.jpg" } ] }
+			] } }`,
+			wantTracks: []string{"track-1", "track-2", "track-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts *httptest.Server
+			ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/search":
+					if r.URL.Query().Get("type") != "album" {
+						t.Errorf("type param: got %q, want %q", r.URL.Query().Get("type"), "album")
+					}
+					w.Write([]byte(tt.searchBody))
+				case r.URL.Path == "/albums/al-1/tracks" && r.URL.Query().Get("offset") == "":
+					w.Write([]byte(`{ "items": [
+						{ "id": "track-1", "name": "One", "duration_ms": 1000, "artists": [ { "name": "Test Artist" } ] },
+						{ "id": "track-2", "name": "Two", "duration_ms": 2000, "artists": [ { "name": "Test Artist" } ] }
+					], "next": "` + ts.URL + `/albums/al-1/tracks?offset=2&limit=50" }`))
+				case r.URL.Path == "/albums/al-1/tracks":
+					w.Write([]byte(`{ "items": [
+						{ "id": "track-3", "name": "Three", "duration_ms": 3000, "artists": [ { "name": "Test Artist" } ] }
+					], "next": null }`))
+				case r.URL.Path == "/tracks":
+					if r.URL.Query().Get("ids") != "track-1,track-2,track-3" {
+						t.Errorf("ids param: got %q", r.URL.Query().Get("ids"))
+					}
+					w.Write([]byte(`{ "tracks": [
+						{ "id": "track-1", "popularity": 61, "preview_url": "http://p.com/1.mp3", "external_ids": { "isrc": "USAAA2000001" } },
+						null,
+						{ "id": "track-3", "popularity": 40, "external_ids": { "isrc": "USAAA2000003" } }
+					] }`))
+				case r.URL.Path == "/audio-features":
+					w.Write([]byte(`{ "audio_features": [ { "id": "track-2", "energy": 0.9 }, null ] }`))
+				default:
+					t.Fatalf("unexpected path: %s", r.URL.Path)
+				}
+			}))
+			defer ts.Close()
+
+			client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL)
+			album, err := client.GetAlbum(context.Background(), "Test Album", "Test Artist")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if album.ID != "al-1" || album.Artist != "Test Artist" || album.ReleaseDate != "2020-01-31" || album.CoverURL != "http://img.com/al-1.jpg" {
+				t.Errorf("unexpected album %+v", album)
+			}
+			var ids []string
+			for _, tr := range album.Tracks {
+				ids = append(ids, tr.ID)
+				if tr.Album != "Test Album" || tr.CoverURL != "http://img.com/al-1.jpg" || tr.ReleaseYear != 2020 {
+					t.Errorf("track %s: expected album metadata, got %+v", tr.ID, tr)
+				}
+			}
+			if !reflect.DeepEqual(ids, tt.wantTracks) {
+				t.Errorf("tracks: got %v, want %v", ids, tt.wantTracks)
+			}
+			if album.Tracks[1].Features.Energy != 0.9 {
+				t.Errorf("expected features for track-2, got %+v", album.Tracks[1].Features)
+			}
+			if first := album.Tracks[0]; first.ISRC != "USAAA2000001" || first.Popularity != 61 || first.PreviewURL != "http://p.com/1.mp3" {
+				t.Errorf("expected full track details for track-1, got %+v", first)
+			}
+			if second := album.Tracks[1]; second.ISRC != "" || second.Popularity != 0 {
+				t.Errorf("expected no full track details for track-2, got %+v", second)
+			}
+		})
+	}
+}
+
+func TestGetRecommendations(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/recommendations":
+			q := r.URL.Query()
+			if q.Get("seed_tracks") != "s1,s2,s3,s4,s5" || q.Get("limit") != "20" {
+				t.Errorf("unexpected seeds %q and limit %q", q.Get("seed_tracks"), q.Get("limit"))
+			}
+			if q.Get("target_energy") != "0.700" || q.Get("target_tempo") != "128.000" {
+				t.Errorf("unexpected targets %v", q)
+			}
+			w.Write([]byte(`{ "tracks": [
+				{ "id": "rec-1", "name": "One", "artists": [ { "name": "A" } ], "external_ids": { "isrc": "USAAA0000001" } },
+				{ "id": "rec-2", "name": "Two", "artists": [ { "name": "B" } ] }
+			] }`))
+		case "/audio-features":
+			w.Write([]byte(`{ "audio_features": [ { "id": "rec-1", "energy": 0.8 }, null ] }`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL)
+	tracks, err := client.GetRecommendations(context.Background(), domain.RecommendationRequest{
+		SeedTrackIDs: []string{"s1", "s2", "s3", "s4", "s5", "s6"},
+		Target:       domain.AudioFeatures{Energy: 0.7, Tempo: 128},
+		Limit:        20,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tracks) != 2 || tracks[0].ID != "rec-1" || tracks[0].ISRC != "USAAA0000001" || tracks[0].Features.Energy != 0.8 {
+		t.Fatalf("unexpected tracks %+v", tracks)
+	}
+
+	if _, err := client.GetRecommendations(context.Background(), domain.RecommendationRequest{}); err == nil {
+		t.Fatal("expected an error without seed tracks")
+	}
+}
+
+func TestGetSpotifyPlaylist(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/playlists/pl-1":
+			w.Write([]byte(`{ "id": "pl-1", "name": "Road Trip" }`))
+		case r.URL.Path == "/playlists/pl-1/tracks" && r.URL.Query().Get("offset") == "":
+			w.Write([]byte(`{ "items": [
+				{ "track": { "id": "track-1", "name": "One", "duration_ms": 1000, "artists": [ { "name": "A" } ], "external_ids": { "isrc": "USRC1" } } },
+				{ "is_local": true, "track": { "id": "", "name": "Home Recording" } },
+				{ "track": null }
+			], "next": "` + ts.URL + `/playlists/pl-1/tracks?offset=3&limit=100" }`))
+		case r.URL.Path == "/playlists/pl-1/tracks":
+			w.Write([]byte(`{ "items": [
+				{ "track": { "id": "track-2", "name": "Two", "duration_ms": 2000, "artists": [ { "name": "B" } ] } }
+			], "next": null }`))
+		case r.URL.Path == "/playlists/private":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL)
+
+	playlist, err := client.GetSpotifyPlaylist(context.Background(), "pl-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, tr := range playlist.Tracks {
+		ids = append(ids, tr.ID)
+	}
+	if playlist.Name != "Road Trip" || !reflect.DeepEqual(ids, []string{"track-1", "track-2"}) {
+		t.Fatalf("expected both pages without the local file and missing item, got %q %v", playlist.Name, ids)
+	}
+	if playlist.Tracks[0].ISRC != "USRC1" || playlist.Tracks[0].Artist != "A" {
+		t.Errorf("unexpected track %+v", playlist.Tracks[0])
+	}
+
+	if _, err := client.GetSpotifyPlaylist(context.Background(), "private"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a private playlist, got %v", err)
+	}
+}
+
+// memArtistStore is an in-memory ports.ArtistStore.
+type memArtistStore map[string]domain.Artist
+
+func (m memArtistStore) GetArtist(_ context.Context, name string) (domain.Artist, error) {
+	a, ok := m[strings.ToLower(name)]
+	if !ok {
+		return domain.Artist{}, domain.ErrNotFound
+	}
+	return a, nil
+}
+
+func (m memArtistStore) SaveArtist(_ context.Context, name string, a domain.Artist) error {
+	m[strings.ToLower(name)] = a
+	return nil
+}
+
+func TestArtistCache(t *testing.T) {
+	fresh := domain.Artist{ID: "cached-id", Name: "Daft Punk", FetchedAt: time.Now().UTC()}
+	stale := domain.Artist{ID: "stale-id", Name: "Daft Punk", FetchedAt: time.Now().Add(-48 * time.Hour).UTC()}
+
+	tests := []struct {
+		name         string
+		cached       *domain.Artist
+		searchStatus int
+		wantSearches int
+		wantArtistID string
+		wantGenres   []string
+	}{
+		{name: "miss searches and stores", searchStatus: http.StatusOK, wantSearches: 1, wantArtistID: "live-id", wantGenres: []string{"french house"}},
+		{name: "fresh entry skips search", cached: &fresh, searchStatus: http.StatusOK, wantArtistID: "cached-id"},
+		{name: "expired entry is refreshed", cached: &stale, searchStatus: http.StatusOK, wantSearches: 1, wantArtistID: "live-id", wantGenres: []string{"french house"}},
+		{name: "stale entry served when refresh fails", cached: &stale, searchStatus: http.StatusBadRequest, wantSearches: 1, wantArtistID: "stale-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searches := 0
+			var topTracksFor string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/search":
+					searches++
+					w.WriteHeader(tt.searchStatus)
+					w.Write([]byte(`{ "artists": { "items": [ {
+						"id": "live-id", "name": "Daft Punk", "genres": ["french house"], "popularity": 80,
+						"images": [ { "url": "http://img.com/big.jpg" }, { "url": "http://img.com/small.jpg" } ]
+					} ] } }`))
+				case strings.HasSuffix(r.URL.Path, "/top-tracks"):
+					topTracksFor = strings.Split(r.URL.Path, "/")[2]
+					w.Write([]byte(`{ "tracks": [] }`))
+				default:
+					t.Fatalf("unexpected path: %s", r.URL.Path)
+				}
+			}))
+			defer ts.Close()
+
+			store := memArtistStore{}
+			if tt.cached != nil {
+				store["daft punk"] = *tt.cached
+			}
+			client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL)
+			client.EnableArtistCache(spotify.ArtistCacheConfig{Store: store, TTL: 24 * time.Hour})
+
+			if _, err := client.GetArtistTopTracks(context.Background(), "Daft Punk"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if searches != tt.wantSearches {
+				t.Errorf("searches: got %d, want %d", searches, tt.wantSearches)
+			}
+			if topTracksFor != tt.wantArtistID {
+				t.Errorf("top tracks fetched for %q, want %q", topTracksFor, tt.wantArtistID)
+			}
+			got := store["daft punk"]
+			if got.ID != tt.wantArtistID {
+				t.Errorf("cached ID: got %q, want %q", got.ID, tt.wantArtistID)
+			}
+			if tt.wantGenres != nil {
+				if !reflect.DeepEqual(got.Genres, tt.wantGenres) || got.ImageURL != "http://img.com/big.jpg" || got.Popularity != 80 {
+					t.Errorf("cached metadata not stored: %+v", got)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchCache(t *testing.T) {
+	searches, features := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			searches++
+			w.Write([]byte(`{ "tracks": { "items": [ {
+				"id": "t1", "name": "Song", "artists": [ { "name": "Artist" } ]
+			} ] } }`))
+		case "/audio-features/t1":
+			features++
+			w.Write([]byte(`{ "danceability": 0.5, "energy": 0.8, "valence": 0.4, "tempo": 120 }`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL)
+	client.EnableSearchCache(spotify.SearchCacheConfig{Cache: cache.NewLRU(10), SearchTTL: time.Hour, FeaturesTTL: time.Hour})
+
+	ctx := context.Background()
+	first, err := client.GetTrack(ctx, "Song", "Artist")
+	if err != nil {
+		t.Fatalf("get track: %v", err)
+	}
+	// Another spelling of the same search is served from the cache.
+	second, err := client.GetTrack(ctx, "Song (Remastered)", "artist")
+	if err != nil {
+		t.Fatalf("get cached track: %v", err)
+	}
+	if searches != 1 || features != 1 {
+		t.Fatalf("expected one search and one features request, got %d and %d", searches, features)
+	}
+	compareTracks(t, second, first)
+
+	if _, err := client.GetTrack(ctx, "Other Song", "Artist"); err != nil {
+		t.Fatalf("get other track: %v", err)
+	}
+	if searches != 2 || features != 1 {
+		t.Fatalf("expected a new search reusing cached features, got %d searches and %d features requests", searches, features)
+	}
+
+	// Results differ by market, so another market searches again.
+	if _, err := client.GetTrack(ports.WithMarket(ctx, "GB"), "Song", "Artist"); err != nil {
+		t.Fatalf("get track in another market: %v", err)
+	}
+	if searches != 3 {
+		t.Fatalf("expected another market to search again, got %d searches", searches)
+	}
+}
+
+func TestMarket(t *testing.T) {
+	var markets []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		markets = append(markets, r.URL.Query().Get("market"))
+		switch {
+		case r.URL.Path == "/search":
+			w.Write([]byte(`{ "artists": { "items": [ { "id": "a1", "name": "Artist" } ] } }`))
+		case strings.HasSuffix(r.URL.Path, "/top-tracks"):
+			w.Write([]byte(`{ "tracks": [] }`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name string
+		opts []spotify.Option
+		ctx  context.Context
+		want string
+	}{
+		{name: "default", ctx: context.Background(), want: spotify.DefaultMarket},
+		{name: "configured", opts: []spotify.Option{spotify.WithMarket("GB")}, ctx: context.Background(), want: "GB"},
+		{name: "request overrides", opts: []spotify.Option{spotify.WithMarket("GB")}, ctx: ports.WithMarket(context.Background(), "JP"), want: "JP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markets = nil
+			client := spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL, tt.opts...)
+			if _, err := client.GetArtistTopTracks(tt.ctx, "Artist"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(markets, []string{tt.want, tt.want}) {
+				t.Fatalf("expected the search and top tracks in %s, got %q", tt.want, markets)
+			}
+		})
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	tracks := []domain.Track{{ID: "t1"}, {ID: "ep1", Type: domain.ItemEpisode}}
+
+	tests := []struct {
+		name       string
+		call       func(c *spotify.Client) error
+		status     int
+		respBody   string
+		wantMethod string
+		wantPath   string
+		wantQuery  string
+		wantBody   string
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "play starts tracks at offset",
+			call:       func(c *spotify.Client) error { return c.Play(context.Background(), tracks, 1) },
+			wantMethod: http.MethodPut, wantPath: "/me/player/play",
+			wantBody: `{"uris":["spotify:track:t1","spotify:episode:ep1"],"offset":{"position":1}}`,
+		},
+		{
+			name:       "pause",
+			call:       func(c *spotify.Client) error { return c.Pause(context.Background()) },
+			wantMethod: http.MethodPut, wantPath: "/me/player/pause",
+		},
+		{
+			name:       "resume sends no body",
+			call:       func(c *spotify.Client) error { return c.Resume(context.Background()) },
+			wantMethod: http.MethodPut, wantPath: "/me/player/play",
+		},
+		{
+			name:       "skip",
+			call:       func(c *spotify.Client) error { return c.Skip(context.Background()) },
+			wantMethod: http.MethodPost, wantPath: "/me/player/next",
+		},
+		{
+			name:       "queue uses the item URI",
+			call:       func(c *spotify.Client) error { return c.Queue(context.Background(), tracks[1]) },
+			wantMethod: http.MethodPost, wantPath: "/me/player/queue", wantQuery: "spotify:episode:ep1",
+		},
+		{
+			name:       "no active device",
+			call:       func(c *spotify.Client) error { return c.Pause(context.Background()) },
+			status:     http.StatusNotFound,
+			respBody:   `{"error":{"status":404,"message":"Player command failed: No active device found","reason":"NO_ACTIVE_DEVICE"}}`,
+			wantMethod: http.MethodPut, wantPath: "/me/player/pause",
+			wantErr: domain.ErrNoActiveDevice,
+		},
+		{
+			name:       "premium required",
+			call:       func(c *spotify.Client) error { return c.Skip(context.Background()) },
+			status:     http.StatusForbidden,
+			respBody:   `{"error":{"status":403,"message":"Player command failed: Premium required","reason":"PREMIUM_REQUIRED"}}`,
+			wantMethod: http.MethodPost, wantPath: "/me/player/next",
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.wantMethod || r.URL.Path != tt.wantPath {
+					t.Errorf("got %s %s, want %s %s", r.Method, r.URL.Path, tt.wantMethod, tt.wantPath)
+				}
+				if got := r.URL.Query().Get("uri"); got != tt.wantQuery {
+					t.Errorf("uri param: got %q, want %q", got, tt.wantQuery)
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != tt.wantBody {
+					t.Errorf("body: got %s, want %s", body, tt.wantBody)
+				}
+				status := tt.status
+				if status == 0 {
+					status = http.StatusNoContent
+				}
+				w.WriteHeader(status)
+				w.Write([]byte(tt.respBody))
+			}))
+			defer ts.Close()
+
+			err := tt.call(spotify.NewClientWithBaseURL(http.DefaultClient, ts.URL))
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+			case tt.wantAnyErr:
+				if err == nil || errors.Is(err, domain.ErrNoActiveDevice) {
+					t.Fatalf("expected a non-device error, got %v", err)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -1,12 +1,38 @@
 package spotify
 
 import (
+	"context"
 	"hash/fnv"
 	"math/rand"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
 )
 
+// EnableFeatureFallback looks features Spotify has none for up in p by
+// ISRC before generating deterministic ones.
+func (c *Client) EnableFeatureFallback(p ports.FeatureProvider) {
+	c.featureFallback = p
+}
+
+// fallbackFeatures returns features for track when Spotify has none, for
+// the reason given: measured ones from the fallback provider when it knows
+// the recording, otherwise deterministic ones generated from the ID.
+func (c *Client) fallbackFeatures(ctx context.Context, track domain.Track, reason string) domain.AudioFeatures {
+	if c.featureFallback != nil && track.ISRC != "" {
+		features, err := c.featureFallback.GetFeatures(ctx, track.ISRC)
+		if err == nil {
+			featureFallbacks.Inc("provider")
+			c.logger.InfoContext(ctx, reason+", using fallback provider features", "track_id", track.ID, "isrc", track.ISRC)
+			return features
+		}
+		c.logger.DebugContext(ctx, "fallback features unavailable", "track_id", track.ID, "isrc", track.ISRC, "error", err)
+	}
+	featureFallbacks.Inc("generated")
+	c.logger.WarnContext(ctx, reason+", generating deterministic features", "track_id", track.ID)
+	return generateDeterministicFeatures(track.ID)
+}
+
 func generateDeterministicFeatures(trackID string) domain.AudioFeatures {
 	hasher := fnv.New32a()
 	_, _ = hasher.Write([]byte(trackID))
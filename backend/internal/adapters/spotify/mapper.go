@@ -1,6 +1,7 @@
 package spotify
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
@@ -30,7 +31,11 @@ func mapTrackToDomain(st spotifyTrack, features *spotifyAudioFeatures) domain.Tr
 		CoverURL:   coverURL,
 		PreviewURL: st.PreviewURL,
 		DurationMs: st.DurationMs,
+		ISRC:       st.ExternalIDs.ISRC,
+		Popularity: st.Popularity,
+		Explicit:   st.Explicit,
 	}
+	dt.ReleaseYear = releaseYear(st.Album.ReleaseDate)
 
 	// 4. Map Features (if provided)
 	if features != nil {
@@ -40,6 +45,19 @@ func mapTrackToDomain(st spotifyTrack, features *spotifyAudioFeatures) domain.Tr
 	return dt
 }
 
+// releaseYear reads the year of a Spotify release date, which starts with
+// it at every precision; it is 0 when there is none.
+func releaseYear(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
 func mapFeaturesToDomain(features spotifyAudioFeatures) domain.AudioFeatures {
 	return domain.AudioFeatures{
 		Danceability:     features.Danceability,
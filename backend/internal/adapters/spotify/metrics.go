@@ -0,0 +1,95 @@
+package spotify
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+var (
+	requestDuration = metrics.NewHistogramVec(
+		"overture_spotify_request_duration_seconds",
+		"Latency of individual Spotify API attempts.",
+		metrics.DefaultBuckets,
+		"endpoint", "code",
+	)
+	requestFailures = metrics.NewCounterVec(
+		"overture_spotify_failures_total",
+		"Spotify API requests that failed once retries were exhausted, by cause (network, rate_limited, server_error).",
+		"endpoint", "cause",
+	)
+	requestRetries = metrics.NewCounterVec(
+		"overture_spotify_retries_total",
+		"Spotify API attempts that were retried, by cause (rate_limited, server_error, network).",
+		"endpoint", "cause",
+	)
+	retryAfterSeconds = metrics.NewGaugeVec(
+		"overture_spotify_retry_after_seconds",
+		"Most recent Retry-After delay requested by the Spotify API.",
+		"endpoint",
+	)
+	schedulerWait = metrics.NewHistogramVec(
+		"overture_spotify_scheduler_wait_seconds",
+		"Time Spotify requests waited for the rate limit, by priority.",
+		metrics.DefaultBuckets,
+		"priority",
+	)
+	schedulerQueued = metrics.NewGaugeVec(
+		"overture_spotify_scheduler_queued",
+		"Spotify requests waiting for the rate limit, by priority.",
+		"priority",
+	)
+	schedulerRejected = metrics.NewCounterVec(
+		"overture_spotify_scheduler_rejected_total",
+		"Spotify requests failed because the rate limit could not admit them before their deadline, by priority.",
+		"priority",
+	)
+	featureFallbacks = metrics.NewCounterVec(
+		"overture_spotify_feature_fallbacks_total",
+		"Tracks Spotify had no audio features for, by where their features came from (provider, generated).",
+		"source",
+	)
+	artistCacheLookups = metrics.NewCounterVec(
+		"overture_spotify_artist_cache_total",
+		"Artist cache lookups, by result (hit, miss, expired, stale, error).",
+		"result",
+	)
+	cacheLookups = metrics.NewCounterVec(
+		"overture_spotify_cache_total",
+		"Search and audio features cache lookups, by kind (search, features) and result (hit, miss, error).",
+		"kind", "result",
+	)
+)
+
+// idCollections are path segments that are followed by a Spotify ID.
+var idCollections = map[string]bool{
+	"albums":         true,
+	"artists":        true,
+	"audio-features": true,
+	"playlists":      true,
+	"tracks":         true,
+	"users":          true,
+}
+
+// endpointLabel reduces a request path to a low-cardinality label by
+// replacing IDs, e.g. /v1/artists/{id}/top-tracks.
+func endpointLabel(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		if idCollections[segments[i-1]] {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// statusCode labels an attempt's outcome by HTTP status, or "error" when no
+// response was received.
+func statusCode(resp *http.Response) string {
+	if resp == nil {
+		return "error"
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
@@ -6,15 +6,21 @@ type spotifyTrack struct {
 	Name       string `json:"name"` // API uses "name", Domain uses "Title"
 	DurationMs int    `json:"duration_ms"`
 	PreviewURL string `json:"preview_url"`
+	Popularity int    `json:"popularity"`
+	Explicit   bool   `json:"explicit"`
 	Artists    []struct {
 		Name string `json:"name"`
 	} `json:"artists"` // API is a list, Domain is a string
 	Album struct {
-		Name   string `json:"name"`
-		Images []struct {
+		Name        string `json:"name"`
+		ReleaseDate string `json:"release_date"`
+		Images      []struct {
 			URL string `json:"url"`
 		} `json:"images"`
 	} `json:"album"` // API is an object, Domain is a string
+	ExternalIDs struct {
+		ISRC string `json:"isrc"`
+	} `json:"external_ids"`
 }
 
 // spotifyAudioFeatures represents the separate API call for "Vibes"
@@ -2,164 +2,88 @@ package spotify
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
-	"os"
-	"strconv"
 	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
 )
 
 const (
 	defaultMaxRetries = 3
-	defaultBackoffMs  = 500
+	defaultBackoff    = 500 * time.Millisecond
 )
 
-func getRetryConfig() (int, time.Duration) {
-	maxRetries := defaultMaxRetries
-	if raw := os.Getenv("SPOTIFY_MAX_RETRIES"); raw != "" {
-		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
-			maxRetries = parsed
-		}
-	}
+// clientDo sends an attempt through the client's own http.Client, which
+// carries the OAuth token source.
+type clientDo func(*http.Request) (*http.Response, error)
 
-	backoffMs := defaultBackoffMs
-	if raw := os.Getenv("SPOTIFY_RETRY_BACKOFF_MS"); raw != "" {
-		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
-			backoffMs = parsed
-		}
-	}
-
-	return maxRetries, time.Duration(backoffMs) * time.Millisecond
-}
+func (f clientDo) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
 
-func (c *Client) doRequestWithRetry(req *http.Request) (*http.Response, error) {
-	maxRetries := c.maxRetries
-	if maxRetries <= 0 {
-		maxRetries = defaultMaxRetries
+// transport returns the retrying transport for a request to endpoint. It
+// paces attempts with the client's scheduler and records the per-endpoint
+// Spotify metrics.
+func (c *Client) transport(endpoint string) *httpx.Transport {
+	policy := httpx.Policy{MaxAttempts: c.maxRetries, BaseBackoff: c.baseBackoff}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaultMaxRetries
+	}
+	if policy.BaseBackoff <= 0 {
+		policy.BaseBackoff = defaultBackoff
 	}
 
-	baseBackoff := c.baseBackoff
-	if baseBackoff <= 0 {
-		baseBackoff = time.Duration(defaultBackoffMs) * time.Millisecond
+	t := &httpx.Transport{
+		Base:         clientDo(c.httpClient.Do),
+		Name:         "spotify",
+		PathTemplate: endpointLabel,
+		Policy:       policy,
+		Hooks: httpx.Hooks{
+			Attempt: func(_ *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+				requestDuration.Observe(elapsed.Seconds(), endpoint, statusCode(resp))
+				if !httpx.Retryable(resp, err) {
+					return
+				}
+				requestRetries.Inc(endpoint, httpx.RetryCause(resp, err))
+				if after := httpx.RetryAfter(resp); after > 0 {
+					retryAfterSeconds.Set(after.Seconds(), endpoint)
+				}
+			},
+		},
+	}
+	if c.scheduler != nil {
+		t.Hooks.Wait = c.scheduler.Wait
 	}
+	return t
+}
 
+// doRequestWithRetry sends req through the retrying transport. A request
+// still failing with 429 or 5xx once retries are exhausted is returned as
+// an error, so callers only handle responses Spotify meant to send.
+func (c *Client) doRequestWithRetry(req *http.Request) (*http.Response, error) {
 	if req.Body != nil && req.GetBody == nil {
 		bodyBytes, err := io.ReadAll(req.Body)
 		if err != nil {
 			return nil, fmt.Errorf("spotify adapter: read request body: %w", err)
 		}
 		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 		req.GetBody = func() (io.ReadCloser, error) {
 			return io.NopCloser(bytes.NewReader(bodyBytes)), nil
 		}
 	}
 
-	ctx := req.Context()
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		if err := ctx.Err(); err != nil {
-			return nil, fmt.Errorf("spotify adapter: request canceled: %w", err)
-		}
-
-		if req.GetBody != nil {
-			body, err := req.GetBody()
-			if err != nil {
-				return nil, fmt.Errorf("spotify adapter: reset request body: %w", err)
-			}
-			req.Body = body
-		}
-
-		resp, err := c.httpClient.Do(req) // #nosec G107,G704
-		retryAfter, retry := shouldRetry(resp, err)
-		if !retry {
-			return resp, err
-		}
-
-		attemptNum := attempt + 1
-		if err != nil {
-			log.Printf("WARN spotify adapter: retry attempt %d/%d after error: %v", attemptNum, maxRetries, err) // #nosec G706 -- error value is from trusted internal HTTP operation
-		} else if resp != nil {
-			log.Printf("WARN spotify adapter: retry attempt %d/%d after status %d", attemptNum, maxRetries, resp.StatusCode) // #nosec G706 -- status code is numeric from trusted HTTP response
-			_ = resp.Body.Close()
-		}
-
-		if attempt == maxRetries-1 {
-			if err != nil {
-				return nil, fmt.Errorf("spotify adapter: request failed after %d attempts: %w", maxRetries, err)
-			}
-			if resp != nil {
-				_ = resp.Body.Close()
-				return nil, fmt.Errorf("spotify adapter: request failed after %d attempts: status %d", maxRetries, resp.StatusCode)
-			}
-			return nil, fmt.Errorf("spotify adapter: request failed after %d attempts", maxRetries)
-		}
-
-		backoff := baseBackoff * time.Duration(1<<attempt)
-		if retryAfter > 0 {
-			backoff = retryAfter
-		}
-
-		if err := sleepWithContext(ctx, backoff); err != nil {
-			return nil, err
-		}
-	}
-
-	return nil, fmt.Errorf("spotify adapter: request failed after %d attempts", maxRetries)
-}
-
-func shouldRetry(resp *http.Response, err error) (time.Duration, bool) {
+	endpoint := endpointLabel(req.URL.Path)
+	t := c.transport(endpoint)
+	resp, err := t.RoundTrip(req) // #nosec G107,G704
 	if err != nil {
-		return 0, true
-	}
-	if resp == nil {
-		return 0, false
+		requestFailures.Inc(endpoint, "network")
+		return nil, fmt.Errorf("spotify adapter: request failed: %w", err)
 	}
-
-	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
-		return parseRetryAfter(resp), true
-	}
-
-	return 0, false
-}
-
-func parseRetryAfter(resp *http.Response) time.Duration {
-	if resp == nil {
-		return 0
-	}
-
-	retryAfter := resp.Header.Get("Retry-After")
-	if retryAfter == "" {
-		return 0
-	}
-
-	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
-		return time.Duration(seconds) * time.Second
-	}
-
-	if when, err := http.ParseTime(retryAfter); err == nil {
-		until := time.Until(when)
-		if until > 0 {
-			return until
-		}
-	}
-
-	return 0
-}
-
-func sleepWithContext(ctx context.Context, delay time.Duration) error {
-	if delay <= 0 {
-		return nil
-	}
-
-	timer := time.NewTimer(delay)
-	defer timer.Stop()
-
-	select {
-	case <-ctx.Done():
-		return fmt.Errorf("spotify adapter: request canceled: %w", ctx.Err())
-	case <-timer.C:
-		return nil
+	if httpx.Retryable(resp, nil) {
+		requestFailures.Inc(endpoint, httpx.RetryCause(resp, nil))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("spotify adapter: request failed after %d attempts: status %d", t.Policy.MaxAttempts, resp.StatusCode)
 	}
+	return resp, nil
 }
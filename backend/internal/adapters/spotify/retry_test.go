@@ -15,6 +15,8 @@ func TestClientDoRequestWithRetry(t *testing.T) {
 		expectedStatus   int
 		expectedAttempts int
 		expectErr        bool
+		retryCause       string
+		expectedRetries  float64
 	}{
 		{
 			name:             "retries on 503 then succeeds",
@@ -23,6 +25,8 @@ func TestClientDoRequestWithRetry(t *testing.T) {
 			expectedStatus:   http.StatusOK,
 			expectedAttempts: 3,
 			expectErr:        false,
+			retryCause:       "server_error",
+			expectedRetries:  2,
 		},
 		{
 			name:             "exhausts retries on 429",
@@ -31,6 +35,8 @@ func TestClientDoRequestWithRetry(t *testing.T) {
 			expectedStatus:   0,
 			expectedAttempts: 2,
 			expectErr:        true,
+			retryCause:       "rate_limited",
+			expectedRetries:  2,
 		},
 	}
 
@@ -54,7 +60,11 @@ func TestClientDoRequestWithRetry(t *testing.T) {
 				baseBackoff: time.Millisecond,
 			}
 
-			req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+			retriesBefore := requestRetries.Value("/v1/tracks/{id}", tt.retryCause)
+			latencyBefore := requestDuration.Count("/v1/tracks/{id}", "200")
+			failuresBefore := requestFailures.Value("/v1/tracks/{id}", tt.retryCause)
+
+			req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/tracks/4uLU6hMCjMI75M1A2tKUQC", nil)
 			if err != nil {
 				t.Fatalf("create request: %v", err)
 			}
@@ -72,6 +82,44 @@ func TestClientDoRequestWithRetry(t *testing.T) {
 			if attempts != tt.expectedAttempts {
 				t.Fatalf("attempts: got %d, want %d", attempts, tt.expectedAttempts)
 			}
+			if got := requestRetries.Value("/v1/tracks/{id}", tt.retryCause) - retriesBefore; got != tt.expectedRetries {
+				t.Fatalf("retries metric: got %v, want %v", got, tt.expectedRetries)
+			}
+			wantOK := uint64(0)
+			if tt.expectedStatus == http.StatusOK {
+				wantOK = 1
+			}
+			if got := requestDuration.Count("/v1/tracks/{id}", "200") - latencyBefore; got != wantOK {
+				t.Fatalf("latency observations for 200: got %d, want %d", got, wantOK)
+			}
+			wantFailures := 0.0
+			if tt.expectErr {
+				wantFailures = 1
+			}
+			if got := requestFailures.Value("/v1/tracks/{id}", tt.retryCause) - failuresBefore; got != wantFailures {
+				t.Fatalf("failures metric: got %v, want %v", got, wantFailures)
+			}
+		})
+	}
+}
+
+func TestEndpointLabel(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/v1/search", want: "/v1/search"},
+		{path: "/v1/tracks/4uLU6hMCjMI75M1A2tKUQC", want: "/v1/tracks/{id}"},
+		{path: "/v1/audio-features/4uLU6hMCjMI75M1A2tKUQC", want: "/v1/audio-features/{id}"},
+		{path: "/v1/artists/0TnOYISbd1XYRBk9myaseg/top-tracks", want: "/v1/artists/{id}/top-tracks"},
+		{path: "/v1/playlists/37i9dQZF1DXcBWIGoYBM5M/tracks", want: "/v1/playlists/{id}/tracks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := endpointLabel(tt.path); got != tt.want {
+				t.Fatalf("endpointLabel(%q) = %q, want %q", tt.path, got, tt.want)
+			}
 		})
 	}
 }
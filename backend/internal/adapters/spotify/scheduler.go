@@ -0,0 +1,208 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// backgroundShare is how many queued interactive requests go ahead of a
+// queued background request, so background jobs keep making progress while
+// users are active.
+const backgroundShare = 3
+
+// Scheduler paces every request of the clients sharing it with a token
+// bucket: tokens refill at Rate per second up to Burst, and each attempt,
+// retries included, takes one. Waiting requests are served by priority
+// (see ports.PriorityFrom), interactive first but background at least one
+// in every backgroundShare+1.
+type Scheduler struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	queues [2][]*waiter
+	// skipped counts interactive grants while background requests waited.
+	skipped int
+	timer   *time.Timer
+}
+
+type waiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// NewScheduler creates a scheduler allowing rate (> 0) requests per second
+// with bursts of up to burst (at least 1). The bucket starts full.
+func NewScheduler(rate float64, burst int) *Scheduler {
+	if burst < 1 {
+		burst = 1
+	}
+	s := &Scheduler{rate: rate, burst: float64(burst), now: time.Now}
+	s.tokens = s.burst
+	s.last = s.now()
+	return s
+}
+
+// Wait blocks until the request may be sent or ctx is done. It fails at
+// once with context.DeadlineExceeded when the requests queued ahead leave
+// no token before ctx's deadline.
+func (s *Scheduler) Wait(ctx context.Context) error {
+	p := ports.PriorityFrom(ctx)
+	if p != ports.PriorityBackground {
+		p = ports.PriorityInteractive
+	}
+	start := time.Now()
+
+	s.mu.Lock()
+	s.refill()
+	if s.queued() == 0 && s.tokens >= 1 {
+		s.tokens--
+		s.mu.Unlock()
+		schedulerWait.Observe(0, p.String())
+		return nil
+	}
+	// A request that cannot be granted before its deadline gives up now
+	// rather than holding a place in the queue until then.
+	if deadline, ok := ctx.Deadline(); ok && s.now().Add(s.eta(p)).After(deadline) {
+		s.mu.Unlock()
+		schedulerRejected.Inc(p.String())
+		return fmt.Errorf("spotify adapter: no request budget before the deadline: %w", context.DeadlineExceeded)
+	}
+	w := &waiter{ready: make(chan struct{})}
+	s.queues[p] = append(s.queues[p], w)
+	schedulerQueued.Set(float64(len(s.queues[p])), p.String())
+	s.arm()
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		schedulerWait.Observe(time.Since(start).Seconds(), p.String())
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if w.granted {
+			// Granted as ctx ended; hand the token back.
+			s.tokens = math.Min(s.tokens+1, s.burst)
+			s.dispatch()
+		} else {
+			s.remove(p, w)
+		}
+		return fmt.Errorf("spotify adapter: request canceled: %w", ctx.Err())
+	}
+}
+
+// refill adds the tokens accrued since the last refill. s.mu must be held.
+func (s *Scheduler) refill() {
+	now := s.now()
+	if elapsed := now.Sub(s.last).Seconds(); elapsed > 0 {
+		s.tokens = math.Min(s.tokens+elapsed*s.rate, s.burst)
+	}
+	s.last = now
+}
+
+// eta estimates how long a request of priority p joining the queue now
+// waits for its token: one token for each waiter served before it, then its
+// own. s.mu must be held.
+func (s *Scheduler) eta(p ports.Priority) time.Duration {
+	missing := float64(s.ahead(p)+1) - s.tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing / s.rate * float64(time.Second))
+}
+
+// ahead counts the waiters served before a request of priority p joining
+// the queue now: interactive ones wait only for the interactive queue and
+// background's share of turns, background ones for nearly everyone.
+// s.mu must be held.
+func (s *Scheduler) ahead(p ports.Priority) int {
+	queued := [2]int{len(s.queues[ports.PriorityInteractive]), len(s.queues[ports.PriorityBackground])}
+	queued[p]++
+	skipped := s.skipped
+	for n := 0; ; n++ {
+		var q ports.Priority
+		q, skipped = pick(queued, skipped)
+		queued[q]--
+		if q == p && queued[p] == 0 {
+			return n
+		}
+	}
+}
+
+func (s *Scheduler) queued() int {
+	return len(s.queues[ports.PriorityInteractive]) + len(s.queues[ports.PriorityBackground])
+}
+
+// dispatch grants tokens to waiters and arms the timer for the rest.
+// s.mu must be held.
+func (s *Scheduler) dispatch() {
+	s.refill()
+	for s.tokens >= 1 && s.queued() > 0 {
+		p := s.next()
+		w := s.queues[p][0]
+		s.queues[p] = s.queues[p][1:]
+		schedulerQueued.Set(float64(len(s.queues[p])), p.String())
+		s.tokens--
+		w.granted = true
+		close(w.ready)
+	}
+	s.arm()
+}
+
+// next picks the queue to serve. s.mu must be held.
+func (s *Scheduler) next() ports.Priority {
+	var p ports.Priority
+	p, s.skipped = pick([2]int{len(s.queues[ports.PriorityInteractive]), len(s.queues[ports.PriorityBackground])}, s.skipped)
+	return p
+}
+
+// pick chooses between queues holding queued waiters by priority, given
+// the interactive grants skipped background since its last turn, and
+// returns the choice with the updated count.
+func pick(queued [2]int, skipped int) (ports.Priority, int) {
+	interactive := queued[ports.PriorityInteractive] > 0
+	background := queued[ports.PriorityBackground] > 0
+	if interactive && (!background || skipped < backgroundShare) {
+		if background {
+			skipped++
+		}
+		return ports.PriorityInteractive, skipped
+	}
+	return ports.PriorityBackground, 0
+}
+
+// arm schedules a dispatch for when the next token is due, if anyone is
+// waiting and none is scheduled. s.mu must be held.
+func (s *Scheduler) arm() {
+	if s.queued() == 0 || s.timer != nil {
+		return
+	}
+	delay := time.Duration((1 - s.tokens) / s.rate * float64(time.Second))
+	s.timer = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.timer = nil
+		s.dispatch()
+	})
+}
+
+// remove drops a waiter whose context ended. s.mu must be held.
+func (s *Scheduler) remove(p ports.Priority, w *waiter) {
+	q := s.queues[p]
+	for i, other := range q {
+		if other == w {
+			s.queues[p] = append(q[:i:i], q[i+1:]...)
+			break
+		}
+	}
+	schedulerQueued.Set(float64(len(s.queues[p])), p.String())
+}
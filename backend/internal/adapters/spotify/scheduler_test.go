@@ -0,0 +1,215 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+func TestScheduler_Next(t *testing.T) {
+	s := NewScheduler(1, 1)
+	for i := 0; i < 4; i++ {
+		s.queues[ports.PriorityInteractive] = append(s.queues[ports.PriorityInteractive], &waiter{})
+		s.queues[ports.PriorityBackground] = append(s.queues[ports.PriorityBackground], &waiter{})
+	}
+
+	var order []string
+	for s.queued() > 0 {
+		p := s.next()
+		s.queues[p] = s.queues[p][1:]
+		order = append(order, p.String()[:1])
+	}
+	// Background gets one turn in every backgroundShare+1 while both wait.
+	if got := strings.Join(order, ""); got != "iiibibbb" {
+		t.Fatalf("expected grant order iiibibbb, got %s", got)
+	}
+}
+
+func TestScheduler_ETA(t *testing.T) {
+	tests := []struct {
+		name                    string
+		interactive, background int
+		skipped                 int
+		p                       ports.Priority
+		want                    time.Duration
+	}{
+		{name: "empty queue", p: ports.PriorityInteractive, want: 100 * time.Millisecond},
+		{name: "interactive ahead of queued background", background: 5, p: ports.PriorityInteractive, want: 100 * time.Millisecond},
+		{name: "interactive behind interactive", interactive: 2, background: 5, p: ports.PriorityInteractive, want: 300 * time.Millisecond},
+		{name: "interactive behind background's turn", interactive: 1, background: 5, skipped: backgroundShare, p: ports.PriorityInteractive, want: 300 * time.Millisecond},
+		{name: "background behind everyone", interactive: 2, background: 5, p: ports.PriorityBackground, want: 800 * time.Millisecond},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewScheduler(10, 1)
+			s.tokens = 0
+			s.skipped = tc.skipped
+			for i := 0; i < tc.interactive; i++ {
+				s.queues[ports.PriorityInteractive] = append(s.queues[ports.PriorityInteractive], &waiter{})
+			}
+			for i := 0; i < tc.background; i++ {
+				s.queues[ports.PriorityBackground] = append(s.queues[ports.PriorityBackground], &waiter{})
+			}
+			if got := s.eta(tc.p); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestScheduler_Wait(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     float64
+		burst    int
+		requests int
+		minTotal time.Duration
+	}{
+		{name: "burst passes at once", rate: 1, burst: 3, requests: 3},
+		{name: "paced after the burst", rate: 50, burst: 1, requests: 4, minTotal: 50 * time.Millisecond},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewScheduler(tc.rate, tc.burst)
+			start := time.Now()
+			for i := 0; i < tc.requests; i++ {
+				if err := s.Wait(context.Background()); err != nil {
+					t.Fatalf("wait: %v", err)
+				}
+			}
+			elapsed := time.Since(start)
+			if elapsed < tc.minTotal {
+				t.Fatalf("expected at least %v, took %v", tc.minTotal, elapsed)
+			}
+			if tc.minTotal == 0 && elapsed > 50*time.Millisecond {
+				t.Fatalf("expected the burst without waiting, took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestScheduler_WaitCanceled(t *testing.T) {
+	s := NewScheduler(0.1, 1)
+	if err := s.Wait(context.Background()); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ports.WithPriority(context.Background(), ports.PriorityBackground), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline error, got %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queued() != 0 {
+		t.Fatalf("expected the canceled request dequeued, %d still queued", s.queued())
+	}
+}
+
+func TestScheduler_WaitPastDeadline(t *testing.T) {
+	s := NewScheduler(10, 1)
+	if err := s.Wait(context.Background()); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	// The next token is 100ms away; a 1s deadline waits for it.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Wait(ctx); err != nil {
+		t.Fatalf("expected a token before the deadline, got %v", err)
+	}
+
+	// A 20ms deadline cannot be met, so the request fails without waiting.
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := s.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Fatalf("expected an immediate failure, took %v", elapsed)
+	}
+}
+
+func TestScheduler_WaitPastDeadlineBehindBackground(t *testing.T) {
+	s := NewScheduler(10, 1)
+	if err := s.Wait(context.Background()); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	// Queued background work would take 600ms, but an interactive request
+	// goes ahead of it and needs only the next token, 100ms away.
+	s.mu.Lock()
+	for i := 0; i < 5; i++ {
+		s.queues[ports.PriorityBackground] = append(s.queues[ports.PriorityBackground], &waiter{ready: make(chan struct{})})
+	}
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := s.Wait(ctx); err != nil {
+		t.Fatalf("expected a token before the deadline, got %v", err)
+	}
+
+	// A background request queues behind all of it and fails at once.
+	ctx, cancel = context.WithTimeout(ports.WithPriority(context.Background(), ports.PriorityBackground), 300*time.Millisecond)
+	defer cancel()
+	if err := s.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline error, got %v", err)
+	}
+}
+
+func TestScheduler_WaitCanceledWithoutDeadline(t *testing.T) {
+	s := NewScheduler(0.1, 1)
+	if err := s.Wait(context.Background()); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := s.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancellation error, got %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queued() != 0 {
+		t.Fatalf("expected the canceled request dequeued, %d still queued", s.queued())
+	}
+}
+
+func TestClient_Scheduler(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := NewClientWithBaseURL(http.DefaultClient, ts.URL)
+	client.baseBackoff = time.Millisecond
+	client.EnableScheduler(NewScheduler(20, 1))
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/tracks/abc", nil)
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	start := time.Now()
+	resp, err := client.doRequestWithRetry(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	// The retry needs a second token, 50ms after the first.
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("expected the retry to wait for a token, took %v", elapsed)
+	}
+}
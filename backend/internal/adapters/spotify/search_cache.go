@@ -0,0 +1,98 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/match"
+)
+
+// SearchCacheConfig controls the cache of track searches and audio
+// features. A zero TTL leaves that kind of response uncached.
+type SearchCacheConfig struct {
+	Cache ports.Cache
+	// SearchTTL is how long the track a title and artist resolved to is
+	// reused.
+	SearchTTL time.Duration
+	// FeaturesTTL is how long a track's audio features are reused.
+	FeaturesTTL time.Duration
+}
+
+// Kinds of cached responses, as labeled in metrics.
+const (
+	cacheSearch   = "search"
+	cacheFeatures = "features"
+)
+
+// EnableSearchCache makes track searches and audio feature lookups consult
+// cfg.Cache first.
+func (c *Client) EnableSearchCache(cfg SearchCacheConfig) {
+	c.searchCache = &cfg
+}
+
+// searchCacheKey keys a search in market by its normalized title and
+// artist, so "Song (Remastered)" and "song" share an entry.
+func searchCacheKey(market, title, artist string) string {
+	return "spotify:search:" + market + ":" + fallbackIfEmpty(match.Normalize(title), strings.ToLower(title)) +
+		"\x1f" + fallbackIfEmpty(match.Normalize(artist), strings.ToLower(artist))
+}
+
+// featuresCacheKey keys the audio features of a track by its ID.
+func featuresCacheKey(trackID string) string {
+	return "spotify:features:" + trackID
+}
+
+// cacheTTL returns how long responses of kind are cached, zero when they
+// are not.
+func (c *Client) cacheTTL(kind string) time.Duration {
+	if c.searchCache == nil {
+		return 0
+	}
+	if kind == cacheSearch {
+		return c.searchCache.SearchTTL
+	}
+	return c.searchCache.FeaturesTTL
+}
+
+// cacheGet decodes the cached response of kind under key into dst and
+// reports whether there was one. Read failures count as misses.
+func (c *Client) cacheGet(ctx context.Context, kind, key string, dst any) bool {
+	if c.cacheTTL(kind) <= 0 {
+		return false
+	}
+	raw, err := c.searchCache.Cache.Get(ctx, key)
+	if err == nil {
+		err = json.Unmarshal(raw, dst)
+	}
+	switch {
+	case err == nil:
+		cacheLookups.Inc(kind, "hit")
+		return true
+	case errors.Is(err, domain.ErrNotFound):
+		cacheLookups.Inc(kind, "miss")
+	default:
+		cacheLookups.Inc(kind, "error")
+		c.logger.WarnContext(ctx, "spotify cache read failed", "kind", kind, "error", err)
+	}
+	return false
+}
+
+// cachePut stores value as the response of kind under key.
+func (c *Client) cachePut(ctx context.Context, kind, key string, value any) {
+	ttl := c.cacheTTL(kind)
+	if ttl <= 0 {
+		return
+	}
+	raw, err := json.Marshal(value)
+	if err == nil {
+		err = c.searchCache.Cache.Set(ctx, key, raw, ttl)
+	}
+	if err != nil {
+		c.logger.WarnContext(ctx, "spotify cache write failed", "kind", kind, "error", err)
+	}
+}
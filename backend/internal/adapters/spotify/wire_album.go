@@ -0,0 +1,193 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/match"
+)
+
+// maxFeatureIDs is the most IDs the audio features endpoint accepts per
+// request.
+const maxFeatureIDs = 100
+
+// maxTrackIDs is the most IDs the several-tracks endpoint accepts per
+// request.
+const maxTrackIDs = 50
+
+type spotifyAlbum struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ReleaseDate string `json:"release_date"`
+	Artists     []struct {
+		Name string `json:"name"`
+	} `json:"artists"`
+	Images []struct {
+		URL string `json:"url"`
+	} `json:"images"`
+}
+
+// albumTrackPage is a page of an album's simplified tracks.
+type albumTrackPage struct {
+	Items []spotifyTrack `json:"items"`
+	Next  string         `json:"next"`
+}
+
+// GetAlbum implements ports.AlbumProvider. Tracks come back in disc and
+// track order with their audio features when Spotify provides them.
+func (c *Client) GetAlbum(ctx context.Context, title, artist string) (domain.Album, error) {
+	album, err := c.searchAlbum(ctx, title, artist)
+	if err != nil {
+		return domain.Album{}, err
+	}
+
+	tracks, err := c.getAlbumTracks(ctx, album.ID)
+	if err != nil {
+		return domain.Album{}, fmt.Errorf("spotify adapter: failed to get tracks for album %q: %w", album.Name, err)
+	}
+
+	ids := make([]string, len(tracks))
+	for i, t := range tracks {
+		ids[i] = t.ID
+	}
+	if err := c.fillFullTracks(ctx, tracks, ids); err != nil {
+		// Log but don't fail - the simplified tracks are still usable
+		c.logger.WarnContext(ctx, "failed to get full album tracks", "error", err)
+	}
+	features := make(map[string]spotifyAudioFeatures, len(ids))
+	for start := 0; start < len(ids); start += maxFeatureIDs {
+		batch, err := c.getAudioFeaturesBatch(ctx, ids[start:min(start+maxFeatureIDs, len(ids))])
+		if err != nil {
+			// Log but don't fail - features are optional for filtering
+			c.logger.WarnContext(ctx, "failed to get audio features", "error", err)
+			break
+		}
+		for id, f := range batch {
+			features[id] = f
+		}
+	}
+
+	return mapAlbumToDomain(album, tracks, features), nil
+}
+
+// searchAlbum returns the search result that best matches title and artist.
+func (c *Client) searchAlbum(ctx context.Context, title, artist string) (spotifyAlbum, error) {
+	q := url.Values{}
+	q.Set("q", fmt.Sprintf("album:%s artist:%s", title, artist))
+	q.Set("type", "album")
+	q.Set("limit", "5")
+	q.Set("market", c.marketFor(ctx))
+
+	var body struct {
+		Albums struct {
+			Items []spotifyAlbum `json:"items"`
+		} `json:"albums"`
+	}
+	if err := c.getJSON(ctx, c.baseURL+"/search?"+q.Encode(), &body); err != nil {
+		return spotifyAlbum{}, fmt.Errorf("spotify adapter: album search failed: %w", err)
+	}
+
+	bestScore, bestIndex := 0.0, -1
+	for i, candidate := range body.Albums.Items {
+		score := match.Score(artist, title, joinAlbumArtists(candidate), candidate.Name)
+		c.logger.DebugContext(ctx, "album candidate scored", "artist", joinAlbumArtists(candidate), "title", candidate.Name, "score", score)
+		if score >= c.matcher.MinScore && score > bestScore {
+			bestScore, bestIndex = score, i
+		}
+	}
+	if bestIndex == -1 {
+		return spotifyAlbum{}, fmt.Errorf("spotify adapter: %w", &ports.NoConfidentMatchError{Title: title, Artist: artist})
+	}
+	return body.Albums.Items[bestIndex], nil
+}
+
+// getAlbumTracks follows the album's track pages to the end.
+func (c *Client) getAlbumTracks(ctx context.Context, albumID string) ([]spotifyTrack, error) {
+	var tracks []spotifyTrack
+	next := fmt.Sprintf("%s/albums/%s/tracks?limit=50&market=%s", c.baseURL, url.PathEscape(albumID), c.marketFor(ctx))
+	for next != "" {
+		var page albumTrackPage
+		if err := c.getJSON(ctx, next, &page); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, page.Items...)
+		next = page.Next
+		// Only follow pages on the API we were configured with.
+		if next != "" && !strings.HasPrefix(next, c.baseURL+"/") {
+			return nil, fmt.Errorf("unexpected next page url %q", next)
+		}
+	}
+	return tracks, nil
+}
+
+// fillFullTracks copies the ISRC, popularity and preview URL of the full
+// track objects into tracks, whose IDs are ids. Album track pages hold
+// simplified tracks that leave the first two out.
+func (c *Client) fillFullTracks(ctx context.Context, tracks []spotifyTrack, ids []string) error {
+	full := make(map[string]spotifyTrack, len(ids))
+	for start := 0; start < len(ids); start += maxTrackIDs {
+		q := url.Values{}
+		q.Set("ids", strings.Join(ids[start:min(start+maxTrackIDs, len(ids))], ","))
+		q.Set("market", c.marketFor(ctx))
+		var body struct {
+			Tracks []*spotifyTrack `json:"tracks"`
+		}
+		if err := c.getJSON(ctx, c.baseURL+"/tracks?"+q.Encode(), &body); err != nil {
+			return err
+		}
+		for _, t := range body.Tracks {
+			if t != nil {
+				full[t.ID] = *t
+			}
+		}
+	}
+	for i := range tracks {
+		f, ok := full[tracks[i].ID]
+		if !ok {
+			continue
+		}
+		tracks[i].ExternalIDs = f.ExternalIDs
+		tracks[i].Popularity = f.Popularity
+		if f.PreviewURL != "" {
+			tracks[i].PreviewURL = f.PreviewURL
+		}
+	}
+	return nil
+}
+
+func joinAlbumArtists(a spotifyAlbum) string {
+	names := make([]string, 0, len(a.Artists))
+	for _, artist := range a.Artists {
+		names = append(names, artist.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func mapAlbumToDomain(sa spotifyAlbum, tracks []spotifyTrack, features map[string]spotifyAudioFeatures) domain.Album {
+	album := domain.Album{
+		ID:          sa.ID,
+		Title:       sa.Name,
+		Artist:      joinAlbumArtists(sa),
+		ReleaseDate: sa.ReleaseDate,
+		Tracks:      make([]domain.Track, 0, len(tracks)),
+	}
+	if len(sa.Images) > 0 {
+		album.CoverURL = sa.Images[0].URL
+	}
+	for _, st := range tracks {
+		// Album track objects omit the album itself.
+		st.Album.Name = sa.Name
+		st.Album.Images = sa.Images
+		st.Album.ReleaseDate = sa.ReleaseDate
+		var f *spotifyAudioFeatures
+		if feat, ok := features[st.ID]; ok {
+			f = &feat
+		}
+		album.Tracks = append(album.Tracks, mapTrackToDomain(st, f))
+	}
+	return album
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 
@@ -13,21 +12,26 @@ import (
 
 // spotifyArtist represents an artist from the Spotify API.
 type spotifyArtist struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Genres     []string `json:"genres"`
+	Popularity int      `json:"popularity"`
+	Images     []struct {
+		URL string `json:"url"`
+	} `json:"images"`
 }
 
 // GetArtistTopTracks searches for an artist by name and returns their top tracks.
 // Returns up to 10 tracks (Spotify's maximum for top tracks endpoint).
 func (c *Client) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
 	// 1. Search for the artist to get their ID
-	artistID, err := c.searchArtist(ctx, artistName)
+	artist, err := c.lookupArtist(ctx, artistName)
 	if err != nil {
 		return nil, fmt.Errorf("spotify adapter: failed to find artist %q: %w", artistName, err)
 	}
 
 	// 2. Fetch the artist's top tracks
-	tracks, err := c.getTopTracks(ctx, artistID)
+	tracks, err := c.getTopTracks(ctx, artist.ID)
 	if err != nil {
 		return nil, fmt.Errorf("spotify adapter: failed to get top tracks for artist %q: %w", artistName, err)
 	}
@@ -41,7 +45,7 @@ func (c *Client) GetArtistTopTracks(ctx context.Context, artistName string) ([]d
 	features, err := c.getAudioFeaturesBatch(ctx, trackIDs)
 	if err != nil {
 		// Log but don't fail - features are optional for filtering
-		log.Printf("WARN spotify adapter: failed to get audio features: %v", err)
+		c.logger.WarnContext(ctx, "failed to get audio features", "error", err)
 		features = make(map[string]spotifyAudioFeatures)
 	}
 
@@ -58,35 +62,33 @@ func (c *Client) GetArtistTopTracks(ctx context.Context, artistName string) ([]d
 	return domainTracks, nil
 }
 
-// searchArtist searches for an artist by name and returns their Spotify ID.
-func (c *Client) searchArtist(ctx context.Context, artistName string) (string, error) {
+// searchArtist searches for an artist by name and returns the best match.
+func (c *Client) searchArtist(ctx context.Context, artistName string) (domain.Artist, error) {
 	searchURL, err := url.Parse(fmt.Sprintf("%s/search", c.baseURL))
 	if err != nil {
-		return "", fmt.Errorf("invalid search url: %w", err)
+		return domain.Artist{}, fmt.Errorf("invalid search url: %w", err)
 	}
 
 	query := searchURL.Query()
 	query.Set("q", artistName)
 	query.Set("type", "artist")
 	query.Set("limit", "1")
-	query.Set("market", "US")
+	query.Set("market", c.marketFor(ctx))
 	searchURL.RawQuery = query.Encode()
 
-	log.Printf("DEBUG spotify adapter: artist search URL: %s", searchURL.String()) // #nosec G706 -- URL is internally constructed from trusted baseURL constant
-
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL.String(), nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create search request: %w", err)
+		return domain.Artist{}, fmt.Errorf("failed to create search request: %w", err)
 	}
 
 	resp, err := c.doRequestWithRetry(req)
 	if err != nil {
-		return "", fmt.Errorf("search request failed: %w", err)
+		return domain.Artist{}, fmt.Errorf("search request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("search status %d", resp.StatusCode)
+		return domain.Artist{}, fmt.Errorf("search status %d", resp.StatusCode)
 	}
 
 	var searchBody struct {
@@ -96,19 +98,34 @@ func (c *Client) searchArtist(ctx context.Context, artistName string) (string, e
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&searchBody); err != nil {
-		return "", fmt.Errorf("search decode error: %w", err)
+		return domain.Artist{}, fmt.Errorf("search decode error: %w", err)
 	}
 
 	if len(searchBody.Artists.Items) == 0 {
-		return "", fmt.Errorf("no artist found with name %q", artistName)
+		return domain.Artist{}, fmt.Errorf("no artist found with name %q", artistName)
 	}
 
-	return searchBody.Artists.Items[0].ID, nil
+	return mapArtistToDomain(searchBody.Artists.Items[0]), nil
+}
+
+// mapArtistToDomain converts a Spotify artist, keeping the largest image
+// (Spotify lists them widest first).
+func mapArtistToDomain(sa spotifyArtist) domain.Artist {
+	artist := domain.Artist{
+		ID:         sa.ID,
+		Name:       sa.Name,
+		Genres:     sa.Genres,
+		Popularity: sa.Popularity,
+	}
+	if len(sa.Images) > 0 {
+		artist.ImageURL = sa.Images[0].URL
+	}
+	return artist
 }
 
 // getTopTracks fetches an artist's top tracks from Spotify.
 func (c *Client) getTopTracks(ctx context.Context, artistID string) ([]spotifyTrack, error) {
-	topTracksURL := fmt.Sprintf("%s/artists/%s/top-tracks?market=US", c.baseURL, artistID)
+	topTracksURL := fmt.Sprintf("%s/artists/%s/top-tracks?market=%s", c.baseURL, artistID, c.marketFor(ctx))
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, topTracksURL, nil)
 	if err != nil {
@@ -136,11 +153,23 @@ func (c *Client) getTopTracks(ctx context.Context, artistID string) ([]spotifyTr
 	return body.Tracks, nil
 }
 
-// getAudioFeaturesBatch fetches audio features for multiple tracks in a single request.
+// getAudioFeaturesBatch fetches audio features for multiple tracks in a
+// single request, taking those it can from the cache when it is enabled.
 func (c *Client) getAudioFeaturesBatch(ctx context.Context, trackIDs []string) (map[string]spotifyAudioFeatures, error) {
-	if len(trackIDs) == 0 {
-		return make(map[string]spotifyAudioFeatures), nil
+	result := make(map[string]spotifyAudioFeatures, len(trackIDs))
+	missing := make([]string, 0, len(trackIDs))
+	for _, id := range trackIDs {
+		var f spotifyAudioFeatures
+		if c.cacheGet(ctx, cacheFeatures, featuresCacheKey(id), &f) {
+			result[id] = f
+			continue
+		}
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return result, nil
 	}
+	trackIDs = missing
 
 	featuresURL, err := url.Parse(fmt.Sprintf("%s/audio-features", c.baseURL))
 	if err != nil {
@@ -189,7 +218,6 @@ func (c *Client) getAudioFeaturesBatch(ctx context.Context, trackIDs []string) (
 		return nil, fmt.Errorf("features decode error: %w", err)
 	}
 
-	result := make(map[string]spotifyAudioFeatures, len(body.AudioFeatures))
 	for _, f := range body.AudioFeatures {
 		if f.ID != "" { // Spotify returns null for some tracks
 			result[f.ID] = spotifyAudioFeatures{
@@ -200,6 +228,7 @@ func (c *Client) getAudioFeaturesBatch(ctx context.Context, trackIDs []string) (
 				Instrumentalness: f.Instrumentalness,
 				Acousticness:     f.Acousticness,
 			}
+			c.cachePut(ctx, cacheFeatures, featuresCacheKey(f.ID), result[f.ID])
 		}
 	}
 
@@ -0,0 +1,165 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// maxEpisodeIDs is the most IDs the episodes endpoint accepts per request.
+const maxEpisodeIDs = 50
+
+// spotifyEpisode is a full episode object. Search returns simplified
+// episodes without the show, so SearchEpisodes fetches the full objects.
+type spotifyEpisode struct {
+	ID                   string `json:"id"`
+	Name                 string `json:"name"`
+	Description          string `json:"description"`
+	DurationMs           int    `json:"duration_ms"`
+	ReleaseDate          string `json:"release_date"`
+	ReleaseDatePrecision string `json:"release_date_precision"`
+	AudioPreviewURL      string `json:"audio_preview_url"`
+	Explicit             bool   `json:"explicit"`
+	Images               []struct {
+		URL string `json:"url"`
+	} `json:"images"`
+	Show struct {
+		Name string `json:"name"`
+	} `json:"show"`
+}
+
+// SearchEpisodes implements ports.PodcastProvider.
+func (c *Client) SearchEpisodes(ctx context.Context, query string, limit int) ([]domain.Episode, error) {
+	if limit < 1 || limit > maxEpisodeIDs {
+		limit = maxEpisodeIDs
+	}
+	ids, err := c.searchEpisodeIDs(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("spotify adapter: episode search failed: %w", err)
+	}
+	if len(ids) == 0 {
+		return []domain.Episode{}, nil
+	}
+	episodes, err := c.getEpisodes(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("spotify adapter: failed to get episodes: %w", err)
+	}
+	return episodes, nil
+}
+
+func (c *Client) searchEpisodeIDs(ctx context.Context, query string, limit int) ([]string, error) {
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("type", "episode")
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("market", c.marketFor(ctx))
+
+	var body struct {
+		Episodes struct {
+			// Unavailable episodes come back as null items.
+			Items []*struct {
+				ID string `json:"id"`
+			} `json:"items"`
+		} `json:"episodes"`
+	}
+	if err := c.getJSON(ctx, c.baseURL+"/search?"+q.Encode(), &body); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(body.Episodes.Items))
+	for _, item := range body.Episodes.Items {
+		if item != nil && item.ID != "" {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids, nil
+}
+
+// getEpisodes fetches full episode objects, keeping the order of ids.
+func (c *Client) getEpisodes(ctx context.Context, ids []string) ([]domain.Episode, error) {
+	q := url.Values{}
+	q.Set("ids", strings.Join(ids, ","))
+	q.Set("market", c.marketFor(ctx))
+
+	var body struct {
+		Episodes []*spotifyEpisode `json:"episodes"`
+	}
+	if err := c.getJSON(ctx, c.baseURL+"/episodes?"+q.Encode(), &body); err != nil {
+		return nil, err
+	}
+	episodes := make([]domain.Episode, 0, len(body.Episodes))
+	for _, se := range body.Episodes {
+		if se != nil {
+			episodes = append(episodes, mapEpisodeToDomain(*se))
+		}
+	}
+	return episodes, nil
+}
+
+// errStatusNotFound is returned by getJSON when Spotify answers 404.
+var errStatusNotFound = errors.New("status 404")
+
+// getJSON sends a GET with retries and decodes a 200 response into out.
+func (c *Client) getJSON(ctx context.Context, rawURL string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.doRequestWithRetry(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errStatusNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode error: %w", err)
+	}
+	return nil
+}
+
+func mapEpisodeToDomain(se spotifyEpisode) domain.Episode {
+	coverURL := ""
+	if len(se.Images) > 0 {
+		coverURL = se.Images[0].URL
+	}
+	return domain.Episode{
+		ID:          se.ID,
+		Title:       se.Name,
+		Show:        se.Show.Name,
+		Description: se.Description,
+		DurationMs:  se.DurationMs,
+		PublishedAt: parseReleaseDate(se.ReleaseDate, se.ReleaseDatePrecision),
+		CoverURL:    coverURL,
+		PreviewURL:  se.AudioPreviewURL,
+		Explicit:    se.Explicit,
+	}
+}
+
+// parseReleaseDate parses a Spotify release date at its precision ("year",
+// "month" or "day"), returning the zero time when it cannot.
+func parseReleaseDate(date, precision string) time.Time {
+	layout := "2006-01-02"
+	switch precision {
+	case "year":
+		layout = "2006"
+	case "month":
+		layout = "2006-01"
+	}
+	t, err := time.Parse(layout, date)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,126 @@
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// maxTracksPerAdd is the most items Spotify adds to a playlist per request.
+const maxTracksPerAdd = 100
+
+// CreatePlaylist implements ports.SpotifyLibrary, creating a private
+// playlist owned by the user the client acts for.
+func (c *Client) CreatePlaylist(ctx context.Context, name string) (string, error) {
+	var me struct {
+		ID string `json:"id"`
+	}
+	if err := c.libraryRequest(ctx, http.MethodGet, "/me", nil, &me); err != nil {
+		return "", err
+	}
+	body := map[string]any{
+		"name":        name,
+		"public":      false,
+		"description": "Mirrored from Overture",
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := c.libraryRequest(ctx, http.MethodPost, "/users/"+url.PathEscape(me.ID)+"/playlists", body, &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// AddTracks implements ports.SpotifyLibrary.
+func (c *Client) AddTracks(ctx context.Context, spotifyPlaylistID string, tracks []domain.Track) error {
+	for start := 0; start < len(tracks); start += maxTracksPerAdd {
+		end := min(start+maxTracksPerAdd, len(tracks))
+		path := "/playlists/" + url.PathEscape(spotifyPlaylistID) + "/tracks"
+		if err := c.libraryRequest(ctx, http.MethodPost, path, map[string]any{"uris": itemURIs(tracks[start:end])}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplaceTracks implements ports.SpotifyLibrary. The first batch replaces
+// the playlist's items and later ones are appended, so a failure part way
+// leaves the playlist holding the first written tracks.
+func (c *Client) ReplaceTracks(ctx context.Context, spotifyPlaylistID string, tracks []domain.Track) (string, int, error) {
+	path := "/playlists/" + url.PathEscape(spotifyPlaylistID) + "/tracks"
+	var snapshot struct {
+		SnapshotID string `json:"snapshot_id"`
+	}
+	end := min(maxTracksPerAdd, len(tracks))
+	if err := c.libraryRequest(ctx, http.MethodPut, path, map[string]any{"uris": itemURIs(tracks[:end])}, &snapshot); err != nil {
+		return "", 0, err
+	}
+	for start := end; start < len(tracks); start += maxTracksPerAdd {
+		end := min(start+maxTracksPerAdd, len(tracks))
+		if err := c.libraryRequest(ctx, http.MethodPost, path, map[string]any{"uris": itemURIs(tracks[start:end])}, &snapshot); err != nil {
+			return snapshot.SnapshotID, start, err
+		}
+	}
+	return snapshot.SnapshotID, len(tracks), nil
+}
+
+// itemURIs returns the Spotify URIs of tracks.
+func itemURIs(tracks []domain.Track) []string {
+	uris := make([]string, 0, len(tracks))
+	for _, t := range tracks {
+		uris = append(uris, itemURI(t))
+	}
+	return uris
+}
+
+// libraryRequest sends body as JSON and decodes the response into out when
+// it is not nil.
+func (c *Client) libraryRequest(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("spotify adapter: encode library request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("spotify adapter: failed to create library request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.doRequestWithRetry(req)
+	if err != nil {
+		return fmt.Errorf("spotify adapter: library request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errBody struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errBody)
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("spotify adapter: library status %d: %s: %w", resp.StatusCode, errBody.Error.Message, domain.ErrNotFound)
+		}
+		return fmt.Errorf("spotify adapter: library status %d: %s", resp.StatusCode, errBody.Error.Message)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("spotify adapter: decode library response: %w", err)
+		}
+	}
+	return nil
+}
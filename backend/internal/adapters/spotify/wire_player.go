@@ -0,0 +1,115 @@
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// NewUserClient creates a client acting on behalf of the Spotify user who
+// granted refreshToken (with the user-modify-playback-state scope), as
+// playback control requires. Access tokens are refreshed as they expire.
+func NewUserClient(clientID, clientSecret, refreshToken string, opts ...Option) *Client {
+	config := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  "https://accounts.spotify.com/authorize",
+			TokenURL: "https://accounts.spotify.com/api/token", // #nosec G101 -- Public Spotify OAuth endpoint, not a secret
+		},
+	}
+	return newClient(config.Client(context.Background(), &oauth2.Token{RefreshToken: refreshToken}), BaseURL, opts)
+}
+
+// itemURI returns the Spotify URI of a track or episode.
+func itemURI(t domain.Track) string {
+	if t.IsEpisode() {
+		return "spotify:episode:" + t.ID
+	}
+	return "spotify:track:" + t.ID
+}
+
+// Play implements ports.PlaybackController.
+func (c *Client) Play(ctx context.Context, tracks []domain.Track, offset int) error {
+	body := struct {
+		URIs   []string `json:"uris"`
+		Offset struct {
+			Position int `json:"position"`
+		} `json:"offset"`
+	}{URIs: make([]string, len(tracks))}
+	for i, t := range tracks {
+		body.URIs[i] = itemURI(t)
+	}
+	body.Offset.Position = offset
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("spotify adapter: encode play request: %w", err)
+	}
+	return c.player(ctx, http.MethodPut, "/me/player/play", payload)
+}
+
+// Pause implements ports.PlaybackController.
+func (c *Client) Pause(ctx context.Context) error {
+	return c.player(ctx, http.MethodPut, "/me/player/pause", nil)
+}
+
+// Resume implements ports.PlaybackController. Without a body, Spotify
+// continues the current context where it was paused.
+func (c *Client) Resume(ctx context.Context) error {
+	return c.player(ctx, http.MethodPut, "/me/player/play", nil)
+}
+
+// Skip implements ports.PlaybackController.
+func (c *Client) Skip(ctx context.Context) error {
+	return c.player(ctx, http.MethodPost, "/me/player/next", nil)
+}
+
+// Queue implements ports.PlaybackController.
+func (c *Client) Queue(ctx context.Context, track domain.Track) error {
+	return c.player(ctx, http.MethodPost, "/me/player/queue?uri="+url.QueryEscape(itemURI(track)), nil)
+}
+
+// player sends a playback command. Spotify answers 204 (or 200/202) on
+// success and 404 with reason NO_ACTIVE_DEVICE when nothing can play.
+func (c *Client) player(ctx context.Context, method, path string, body []byte) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("spotify adapter: failed to create player request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.doRequestWithRetry(req)
+	if err != nil {
+		return fmt.Errorf("spotify adapter: player request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	var errBody struct {
+		Error struct {
+			Message string `json:"message"`
+			Reason  string `json:"reason"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&errBody)
+	if resp.StatusCode == http.StatusNotFound || errBody.Error.Reason == "NO_ACTIVE_DEVICE" {
+		return fmt.Errorf("spotify adapter: %w", domain.ErrNoActiveDevice)
+	}
+	return fmt.Errorf("spotify adapter: player status %d: %s", resp.StatusCode, errBody.Error.Message)
+}
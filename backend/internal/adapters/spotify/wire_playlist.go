@@ -4,12 +4,75 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
 )
 
+var _ ports.PlaylistImporter = (*Client)(nil)
+
+// playlistItemPage is a page of a playlist's items. Track is null for
+// items Spotify no longer serves.
+type playlistItemPage struct {
+	Items []struct {
+		IsLocal bool          `json:"is_local"`
+		Track   *spotifyTrack `json:"track"`
+	} `json:"items"`
+	Next string `json:"next"`
+}
+
+// GetSpotifyPlaylist implements ports.PlaylistImporter, following the
+// playlist's item pages to the end.
+func (c *Client) GetSpotifyPlaylist(ctx context.Context, playlistID string) (domain.Playlist, error) {
+	base := c.baseURL + "/playlists/" + url.PathEscape(playlistID)
+	var meta struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := c.getPlaylistJSON(ctx, base+"?fields=id,name", &meta); err != nil {
+		return domain.Playlist{}, err
+	}
+
+	playlist := domain.Playlist{ID: meta.ID, Name: meta.Name, Tracks: []domain.Track{}}
+	next := base + "/tracks?limit=100&market=" + c.marketFor(ctx) + "&additional_types=track"
+	for next != "" {
+		var page playlistItemPage
+		if err := c.getPlaylistJSON(ctx, next, &page); err != nil {
+			return domain.Playlist{}, err
+		}
+		for _, item := range page.Items {
+			if item.IsLocal || item.Track == nil || item.Track.ID == "" {
+				continue
+			}
+			playlist.Tracks = append(playlist.Tracks, mapTrackToDomain(*item.Track, nil))
+		}
+		next = page.Next
+		// Only follow pages on the API we were configured with.
+		if next != "" && !strings.HasPrefix(next, c.baseURL+"/") {
+			return domain.Playlist{}, fmt.Errorf("spotify adapter: unexpected next page url %q", next)
+		}
+	}
+	return playlist, nil
+}
+
+// getPlaylistJSON fetches rawURL into out. Spotify answers 404 for unknown
+// playlists and, with client credentials, for private ones.
+func (c *Client) getPlaylistJSON(ctx context.Context, rawURL string, out any) error {
+	err := c.getJSON(ctx, rawURL, out)
+	if errors.Is(err, errStatusNotFound) {
+		return fmt.Errorf("spotify adapter: playlist: %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("spotify adapter: failed to get playlist: %w", err)
+	}
+	return nil
+}
+
 // AddTrackToPlaylist adds a track to a playlist and returns the updated playlist.
 // Note: This is a simplified implementation. Real Spotify API returns a Snapshot ID,
 // so we usually have to fetch the playlist again to return the full domain object.
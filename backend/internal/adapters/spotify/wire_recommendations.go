@@ -0,0 +1,73 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// maxRecommendations is the most tracks the recommendations endpoint
+// returns per request.
+const maxRecommendations = 100
+
+// GetRecommendations implements ports.RecommendationProvider through
+// Spotify's recommendations endpoint, seeded with req's tracks and aiming
+// for its target audio features. Tracks come back with their audio
+// features when Spotify provides them.
+func (c *Client) GetRecommendations(ctx context.Context, req domain.RecommendationRequest) ([]domain.Track, error) {
+	seeds := req.SeedTrackIDs[:min(len(req.SeedTrackIDs), domain.MaxRecommendationSeeds)]
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("spotify adapter: recommendations need at least one seed track")
+	}
+	limit := req.Limit
+	if limit < 1 || limit > maxRecommendations {
+		limit = maxRecommendations
+	}
+
+	q := url.Values{}
+	q.Set("seed_tracks", strings.Join(seeds, ","))
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("market", c.marketFor(ctx))
+	for name, value := range map[string]float64{
+		"danceability":     req.Target.Danceability,
+		"energy":           req.Target.Energy,
+		"valence":          req.Target.Valence,
+		"tempo":            req.Target.Tempo,
+		"instrumentalness": req.Target.Instrumentalness,
+		"acousticness":     req.Target.Acousticness,
+	} {
+		q.Set("target_"+name, strconv.FormatFloat(value, 'f', 3, 64))
+	}
+
+	var body struct {
+		Tracks []spotifyTrack `json:"tracks"`
+	}
+	if err := c.getJSON(ctx, c.baseURL+"/recommendations?"+q.Encode(), &body); err != nil {
+		return nil, fmt.Errorf("spotify adapter: recommendations failed: %w", err)
+	}
+
+	ids := make([]string, len(body.Tracks))
+	for i, t := range body.Tracks {
+		ids[i] = t.ID
+	}
+	features, err := c.getAudioFeaturesBatch(ctx, ids)
+	if err != nil {
+		// Log but don't fail - features only refine the scores
+		c.logger.WarnContext(ctx, "failed to get audio features", "error", err)
+		features = make(map[string]spotifyAudioFeatures)
+	}
+
+	tracks := make([]domain.Track, len(body.Tracks))
+	for i, st := range body.Tracks {
+		var f *spotifyAudioFeatures
+		if feat, ok := features[st.ID]; ok {
+			f = &feat
+		}
+		tracks[i] = mapTrackToDomain(st, f)
+	}
+	return tracks, nil
+}
@@ -4,19 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
-	"os"
-	"strconv"
-	"strings"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
 	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/match"
 )
 
-const defaultSearchMatchThreshold = 0.5
-
 // GetTrackByMetadata searches for a track using title and artist metadata.
 func (c *Client) GetTrackByMetadata(ctx context.Context, title string, artist string) (domain.Track, error) {
 	track, err := c.searchTrack(ctx, title, artist)
@@ -27,7 +22,23 @@ func (c *Client) GetTrackByMetadata(ctx context.Context, title string, artist st
 	return mapTrackToDomain(track, nil), nil
 }
 
+// searchTrack returns the best search result for title and artist, from
+// the cache when it is enabled.
 func (c *Client) searchTrack(ctx context.Context, title string, artist string) (spotifyTrack, error) {
+	key := searchCacheKey(c.marketFor(ctx), title, artist)
+	var track spotifyTrack
+	if c.cacheGet(ctx, cacheSearch, key, &track) {
+		return track, nil
+	}
+	track, err := c.fetchSearchTrack(ctx, title, artist)
+	if err != nil {
+		return spotifyTrack{}, err
+	}
+	c.cachePut(ctx, cacheSearch, key, track)
+	return track, nil
+}
+
+func (c *Client) fetchSearchTrack(ctx context.Context, title string, artist string) (spotifyTrack, error) {
 	searchURL, err := url.Parse(fmt.Sprintf("%s/search", c.baseURL))
 	if err != nil {
 		return spotifyTrack{}, fmt.Errorf("spotify adapter: invalid search url: %w", err)
@@ -41,11 +52,9 @@ func (c *Client) searchTrack(ctx context.Context, title string, artist string) (
 	query.Set("q", fmt.Sprintf("track:%s artist:%s", queryTitle, queryArtist))
 	query.Set("type", "track")
 	query.Set("limit", "5")
-	query.Set("market", "US")
+	query.Set("market", c.marketFor(ctx))
 	searchURL.RawQuery = query.Encode()
 
-	log.Printf("DEBUG spotify adapter: search request URL: %s", searchURL.String()) // #nosec G706 -- URL is internally constructed from trusted baseURL constant
-
 	searchReq, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL.String(), nil)
 	if err != nil {
 		return spotifyTrack{}, fmt.Errorf("spotify adapter: failed to create search request: %w", err)
@@ -79,32 +88,15 @@ func (c *Client) searchTrack(ctx context.Context, title string, artist string) (
 	if maxItems > 5 {
 		maxItems = 5
 	}
-	minConfidence := getMinConfidence()
-	bestScore := 0.0
 	bestIndex := -1
-	bestExactArtist := false
-	bestTitleMatch := false
+	var best match.Result
 	for i := 0; i < maxItems; i++ {
 		candidate := searchBody.Tracks.Items[i]
-		candidateArtist := joinArtistNames(candidate)
-		score := ScoreResult(artist, title, candidateArtist, candidate.Name)
-		exactArtist := artistExactMatch(candidate, artist)
-		if exactArtist {
-			score += 0.4
-		}
-		titleMatch := titleSubstringMatch(candidate.Name, title)
-		if titleMatch {
-			score += 0.3
-		}
-		if score > 1.0 {
-			score = 1.0
-		}
-		log.Printf("DEBUG spotify adapter: Spotify Match: %s - %s (Score: %.2f)", candidateArtist, candidate.Name, score)
-		if score >= minConfidence && (score > bestScore || (score == bestScore && (exactArtist && !bestExactArtist || (exactArtist == bestExactArtist && titleMatch && !bestTitleMatch)))) {
-			bestScore = score
-			bestIndex = i
-			bestExactArtist = exactArtist
-			bestTitleMatch = titleMatch
+		artists := trackArtists(candidate)
+		res := c.matcher.Score(title, artist, match.Candidate{Title: candidate.Name, Artists: artists})
+		c.logger.DebugContext(ctx, "search candidate scored", "artist", match.JoinArtists(artists), "title", candidate.Name, "score", res.Score)
+		if c.matcher.Accepts(res) && (bestIndex == -1 || res.Beats(best)) {
+			bestIndex, best = i, res
 		}
 	}
 
@@ -115,43 +107,11 @@ func (c *Client) searchTrack(ctx context.Context, title string, artist string) (
 	return searchBody.Tracks.Items[bestIndex], nil
 }
 
-func getMinConfidence() float64 {
-	value := strings.TrimSpace(os.Getenv("SPOTIFY_MIN_CONFIDENCE"))
-	if value == "" {
-		return defaultSearchMatchThreshold
-	}
-	parsed, err := strconv.ParseFloat(value, 64)
-	if err != nil {
-		log.Printf("WARN spotify adapter: invalid SPOTIFY_MIN_CONFIDENCE %q", value) // #nosec G706
-		return defaultSearchMatchThreshold
-	}
-	if parsed < 0 {
-		return 0
-	}
-	if parsed > 1 {
-		return 1
-	}
-	return parsed
-}
-
-func artistExactMatch(candidate spotifyTrack, target string) bool {
-	target = strings.TrimSpace(target)
-	if target == "" {
-		return false
-	}
-	for _, artist := range candidate.Artists {
-		if strings.EqualFold(strings.TrimSpace(artist.Name), target) {
-			return true
-		}
-	}
-	return false
-}
-
-func titleSubstringMatch(candidateTitle string, targetTitle string) bool {
-	ct := strings.ToLower(strings.TrimSpace(candidateTitle))
-	tt := strings.ToLower(strings.TrimSpace(targetTitle))
-	if ct == "" || tt == "" {
-		return false
+// trackArtists returns the names of the track's artists.
+func trackArtists(t spotifyTrack) []string {
+	names := make([]string, 0, len(t.Artists))
+	for _, a := range t.Artists {
+		names = append(names, a.Name)
 	}
-	return strings.Contains(ct, tt)
+	return names
 }
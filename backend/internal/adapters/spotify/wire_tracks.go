@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
@@ -19,42 +18,57 @@ func (c *Client) GetTrack(ctx context.Context, title string, artist string) (dom
 
 	mapped := mapTrackToDomain(track, nil)
 
-	featuresURL := fmt.Sprintf("%s/audio-features/%s", c.baseURL, track.ID)
+	features, ok, err := c.getAudioFeatures(ctx, track.ID)
+	if err != nil {
+		return domain.Track{}, err
+	}
+	if !ok {
+		mapped.Features = c.fallbackFeatures(ctx, mapped, "audio features unavailable")
+		return mapped, nil
+	}
+	if features.Energy <= 0.001 {
+		mapped.Features = c.fallbackFeatures(ctx, mapped, "audio features empty")
+		return mapped, nil
+	}
+
+	if allFeaturesZero(features) {
+		mapped.Features = c.fallbackFeatures(ctx, mapped, "audio features unavailable")
+		return mapped, nil
+	}
+
+	return mapTrackToDomain(track, &features), nil
+}
+
+// getAudioFeatures returns the audio features of the track, from the cache
+// when it is enabled. ok is false when Spotify has none for it.
+func (c *Client) getAudioFeatures(ctx context.Context, trackID string) (features spotifyAudioFeatures, ok bool, err error) {
+	key := featuresCacheKey(trackID)
+	if c.cacheGet(ctx, cacheFeatures, key, &features) {
+		return features, true, nil
+	}
+
+	featuresURL := fmt.Sprintf("%s/audio-features/%s", c.baseURL, trackID)
 	featuresReq, err := http.NewRequestWithContext(ctx, http.MethodGet, featuresURL, nil)
 	if err != nil {
-		return domain.Track{}, fmt.Errorf("spotify adapter: failed to create features request: %w", err)
+		return features, false, fmt.Errorf("spotify adapter: failed to create features request: %w", err)
 	}
 
 	featuresResp, err := c.doRequestWithRetry(featuresReq)
 	if err != nil {
-		return domain.Track{}, fmt.Errorf("spotify adapter: features request failed: %w", err)
+		return features, false, fmt.Errorf("spotify adapter: features request failed: %w", err)
 	}
 	defer featuresResp.Body.Close()
 
 	if featuresResp.StatusCode != http.StatusOK {
 		if featuresResp.StatusCode == http.StatusForbidden || featuresResp.StatusCode == http.StatusNotFound {
-			log.Printf("WARN spotify adapter: falling back to deterministic vibe generation for track %s", track.ID)
-			mapped.Features = generateDeterministicFeatures(track.ID)
-			return mapped, nil
+			return features, false, nil
 		}
-		return domain.Track{}, fmt.Errorf("spotify adapter: features status %d", featuresResp.StatusCode)
+		return features, false, fmt.Errorf("spotify adapter: features status %d", featuresResp.StatusCode)
 	}
 
-	var features spotifyAudioFeatures
 	if err := json.NewDecoder(featuresResp.Body).Decode(&features); err != nil {
-		return domain.Track{}, fmt.Errorf("spotify adapter: features decode error: %w", err)
-	}
-	if features.Energy <= 0.001 {
-		log.Printf("WARN spotify adapter: Spotify returned empty features. Triggering deterministic fallback.")
-		mapped.Features = generateDeterministicFeatures(track.ID)
-		return mapped, nil
+		return features, false, fmt.Errorf("spotify adapter: features decode error: %w", err)
 	}
-
-	if allFeaturesZero(features) {
-		log.Printf("WARN spotify adapter: falling back to deterministic vibe generation for track %s", track.ID)
-		mapped.Features = generateDeterministicFeatures(track.ID)
-		return mapped, nil
-	}
-
-	return mapTrackToDomain(track, &features), nil
+	c.cachePut(ctx, cacheFeatures, key, features)
+	return features, true, nil
 }
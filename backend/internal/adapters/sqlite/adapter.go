@@ -6,29 +6,53 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
 	_ "github.com/mattn/go-sqlite3" // Import the driver anonymously
 )
 
+// querier is the subset of *sql.DB and *sql.Tx the repository methods use,
+// so the same code runs standalone or inside a unit of work.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 // Adapter implements the repository port for SQLite
 type Adapter struct {
 	db *sql.DB
+	q  querier
+	// tx is set when the adapter is bound to a unit of work.
+	tx *sql.Tx
 }
 
-// NewAdapter creates a connection and runs the schema migration
+// NewAdapter creates a connection with DefaultConfig and runs the schema
+// migration
 func NewAdapter(storagePath string) (*Adapter, error) {
-	db, err := sql.Open("sqlite3", storagePath)
+	return Open(storagePath, DefaultConfig())
+}
+
+// Open creates a connection tuned by cfg and runs the schema migration
+func Open(storagePath string, cfg Config) (*Adapter, error) {
+	db, err := sql.Open("sqlite3", cfg.DSN(storagePath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open sqlite db: %w", err)
 	}
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
 
 	// Verify connection
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping sqlite db: %w", err)
 	}
 
-	adapter := &Adapter{db: db}
+	adapter := &Adapter{db: db, q: db}
 
 	// "Principal" Move: Auto-migrate on startup for local dev
 	if err := adapter.migrate(); err != nil {
@@ -44,9 +68,9 @@ func (a *Adapter) Close() error {
 }
 
 func (a *Adapter) GetByID(ictx context.Context, id string) (domain.Playlist, error) {
-	row := a.db.QueryRowContext(ictx, "SELECT id, name FROM playlists WHERE id = ?", id)
+	row := a.q.QueryRowContext(ictx, "SELECT id, name, version FROM playlists WHERE id = ?", id)
 	var playlist domain.Playlist
-	if err := row.Scan(&playlist.ID, &playlist.Name); err != nil {
+	if err := row.Scan(&playlist.ID, &playlist.Name, &playlist.Version); err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Playlist{}, domain.ErrNotFound
 		}
@@ -54,14 +78,12 @@ func (a *Adapter) GetByID(ictx context.Context, id string) (domain.Playlist, err
 	}
 	playlist.Tracks = []domain.Track{}
 
-	trackRows, err := a.db.QueryContext(ictx, `
-		SELECT t.id, t.title, t.artist, t.album, t.duration_ms, t.isrc, t.cover_url, t.preview_url,
-			IFNULL(t.danceability, 0), IFNULL(t.energy, 0), IFNULL(t.valence, 0),
-			IFNULL(t.tempo, 0), IFNULL(t.instrumentalness, 0), IFNULL(t.acousticness, 0)
+	trackRows, err := a.q.QueryContext(ictx, `
+		SELECT `+trackSelect+`
 		FROM tracks t
 		JOIN playlist_tracks pt ON pt.track_id = t.id
 		WHERE pt.playlist_id = ?
-		ORDER BY pt.added_at ASC
+		ORDER BY pt.position ASC, pt.added_at ASC, pt.rowid ASC
 	`, playlist.ID)
 	if err != nil {
 		return domain.Playlist{}, fmt.Errorf("failed to load playlist tracks: %w", err)
@@ -69,45 +91,10 @@ func (a *Adapter) GetByID(ictx context.Context, id string) (domain.Playlist, err
 	defer trackRows.Close()
 
 	for trackRows.Next() {
-		var track domain.Track
-		var album sql.NullString
-		var isrc sql.NullString
-		var coverURL sql.NullString
-		var previewURL sql.NullString
-		var duration sql.NullInt64
-		if err := trackRows.Scan(
-			&track.ID,
-			&track.Title,
-			&track.Artist,
-			&album,
-			&duration,
-			&isrc,
-			&coverURL,
-			&previewURL,
-			&track.Features.Danceability,
-			&track.Features.Energy,
-			&track.Features.Valence,
-			&track.Features.Tempo,
-			&track.Features.Instrumentalness,
-			&track.Features.Acousticness,
-		); err != nil {
+		track, err := scanTrack(trackRows)
+		if err != nil {
 			return domain.Playlist{}, fmt.Errorf("failed to scan playlist track: %w", err)
 		}
-		if album.Valid {
-			track.Album = album.String
-		}
-		if duration.Valid {
-			track.DurationMs = int(duration.Int64)
-		}
-		if isrc.Valid {
-			track.ISRC = isrc.String
-		}
-		if coverURL.Valid {
-			track.CoverURL = coverURL.String
-		}
-		if previewURL.Valid {
-			track.PreviewURL = previewURL.String
-		}
 		playlist.Tracks = append(playlist.Tracks, track)
 	}
 	if err := trackRows.Err(); err != nil {
@@ -117,8 +104,92 @@ func (a *Adapter) GetByID(ictx context.Context, id string) (domain.Playlist, err
 	return playlist, nil
 }
 
+// trackSelect selects, from tracks aliased as t, what scanTrack reads.
+const trackSelect = `t.id, t.title, t.artist, t.album, t.duration_ms, t.isrc, t.cover_url, t.preview_url,
+			IFNULL(t.danceability, 0), IFNULL(t.energy, 0), IFNULL(t.valence, 0),
+			IFNULL(t.tempo, 0), IFNULL(t.instrumentalness, 0), IFNULL(t.acousticness, 0),
+			t.item_type, t.description, t.published_at, t.source, t.popularity, t.release_year, t.explicit`
+
+func scanTrack(row interface{ Scan(...any) error }) (domain.Track, error) {
+	var track domain.Track
+	var album sql.NullString
+	var isrc sql.NullString
+	var coverURL sql.NullString
+	var previewURL sql.NullString
+	var duration sql.NullInt64
+	var itemType string
+	var publishedAt sql.NullInt64
+	if err := row.Scan(
+		&track.ID,
+		&track.Title,
+		&track.Artist,
+		&album,
+		&duration,
+		&isrc,
+		&coverURL,
+		&previewURL,
+		&track.Features.Danceability,
+		&track.Features.Energy,
+		&track.Features.Valence,
+		&track.Features.Tempo,
+		&track.Features.Instrumentalness,
+		&track.Features.Acousticness,
+		&itemType,
+		&track.Description,
+		&publishedAt,
+		&track.Source,
+		&track.Popularity,
+		&track.ReleaseYear,
+		&track.Explicit,
+	); err != nil {
+		return domain.Track{}, err
+	}
+	track.Type = domain.ItemType(itemType)
+	if publishedAt.Valid {
+		t := time.Unix(0, publishedAt.Int64).UTC()
+		track.PublishedAt = &t
+	}
+	track.Album = album.String
+	track.DurationMs = int(duration.Int64)
+	track.ISRC = isrc.String
+	track.CoverURL = coverURL.String
+	track.PreviewURL = previewURL.String
+	return track, nil
+}
+
+// ListPlaylists returns every playlist with its tracks, oldest first.
+func (a *Adapter) ListPlaylists(ctx context.Context) ([]domain.Playlist, error) {
+	rows, err := a.q.QueryContext(ctx, "SELECT id FROM playlists ORDER BY created_at ASC, id ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan playlist id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate playlists: %w", err)
+	}
+
+	playlists := make([]domain.Playlist, 0, len(ids))
+	for _, id := range ids {
+		p, err := a.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, p)
+	}
+	return playlists, nil
+}
+
 func (a *Adapter) GetPlaylistAudioFeatures(ctx context.Context, playlistID string) (domain.AudioFeatures, error) {
-	row := a.db.QueryRowContext(ctx, "SELECT id FROM playlists WHERE id = ?", playlistID)
+	row := a.q.QueryRowContext(ctx, "SELECT id FROM playlists WHERE id = ?", playlistID)
 	var id string
 	if err := row.Scan(&id); err != nil {
 		if err == sql.ErrNoRows {
@@ -137,11 +208,11 @@ func (a *Adapter) GetPlaylistAudioFeatures(ctx context.Context, playlistID strin
 			COALESCE(AVG(t.acousticness), 0)
 		FROM tracks t
 		JOIN playlist_tracks pt ON pt.track_id = t.id
-		WHERE pt.playlist_id = ?
+		WHERE pt.playlist_id = ? AND t.item_type = 'track'
 	`
 
 	var features domain.AudioFeatures
-	if err := a.db.QueryRowContext(ctx, query, playlistID).Scan(
+	if err := a.q.QueryRowContext(ctx, query, playlistID).Scan(
 		&features.Danceability,
 		&features.Energy,
 		&features.Valence,
@@ -167,7 +238,7 @@ func (a *Adapter) UpdateTrackFeatures(ctx context.Context, trackID string, featu
 			acousticness = ?
 		WHERE id = ?
 	`
-	if _, err := a.db.ExecContext(
+	if _, err := a.q.ExecContext(
 		ctx,
 		query,
 		features.Danceability,
@@ -185,95 +256,70 @@ func (a *Adapter) UpdateTrackFeatures(ctx context.Context, trackID string, featu
 }
 
 func (a *Adapter) Save(ctx context.Context, p domain.Playlist) error {
-	// 1. Start Transaction
-	tx, err := a.db.BeginTx(ctx, nil)
+	// 1. Start Transaction (or join the enclosing unit of work)
+	scope, err := a.begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return err
 	}
-	defer tx.Rollback() // Safety net: auto-rollback if we error/panic before commit
+	defer scope.rollback() // Safety net: auto-rollback if we error/panic before commit
+	tx := scope.tx
 
-	// 2. Upsert Playlist (Create if new, Update name if exists)
-	queryPlaylist := `
-		INSERT INTO playlists (id, name) VALUES (?, ?)
-		ON CONFLICT(id) DO UPDATE SET name=excluded.name;
-	`
-	if _, err := tx.ExecContext(ctx, queryPlaylist, p.ID, p.Name); err != nil {
-		return fmt.Errorf("failed to save playlist metadata: %w", err)
+	// 2. Upsert Playlist (Create if new, Update name if exists). A playlist
+	// loaded at a Version only saves over that Version; writeRevision
+	// moves it on below.
+	if p.Version > 0 {
+		res, err := tx.ExecContext(ctx, "UPDATE playlists SET name = ? WHERE id = ? AND version = ?", p.Name, p.ID, p.Version)
+		if err != nil {
+			return fmt.Errorf("failed to save playlist metadata: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to save playlist metadata: %w", err)
+		} else if n == 0 {
+			return domain.ErrVersionConflict
+		}
+	} else {
+		queryPlaylist := `
+			INSERT INTO playlists (id, name, version) VALUES (?, ?, 0)
+			ON CONFLICT(id) DO UPDATE SET name=excluded.name;
+		`
+		if _, err := tx.ExecContext(ctx, queryPlaylist, p.ID, p.Name); err != nil {
+			return fmt.Errorf("failed to save playlist metadata: %w", err)
+		}
 	}
 
 	// 3. Reset Links: Remove old track associations for this playlist
 	// (We don't delete the tracks themselves, just the connection to this playlist)
+	// Stamp the tracks first so the cleanup job can measure how long a track
+	// has been without a playlist; tracks that are re-linked below are
+	// simply not orphans.
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE tracks SET unlinked_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT track_id FROM playlist_tracks WHERE playlist_id = ?)
+	`, p.ID); err != nil {
+		return fmt.Errorf("failed to mark unlinked tracks: %w", err)
+	}
 	if _, err := tx.ExecContext(ctx, "DELETE FROM playlist_tracks WHERE playlist_id = ?", p.ID); err != nil {
 		return fmt.Errorf("failed to clear old tracks: %w", err)
 	}
 
-	// 4. Upsert Tracks & Re-link
-	// Prepare statements once for performance
-	stmtTrack, err := tx.PrepareContext(ctx, `
-		INSERT INTO tracks (
-			id, title, artist, album, duration_ms, isrc, cover_url, preview_url,
-			danceability, energy, valence, tempo, instrumentalness, acousticness
-		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			title=excluded.title,
-			artist=excluded.artist,
-			album=excluded.album,
-			duration_ms=excluded.duration_ms,
-			isrc=excluded.isrc,
-			cover_url=excluded.cover_url,
-			preview_url=excluded.preview_url,
-			danceability=excluded.danceability,
-			energy=excluded.energy,
-			valence=excluded.valence,
-			tempo=excluded.tempo,
-			instrumentalness=excluded.instrumentalness,
-			acousticness=excluded.acousticness;
-	`)
-	if err != nil {
+	// 4. Upsert Tracks & Re-link in the playlist's order
+	if err := writeTracks(ctx, tx, p.ID, p.Tracks, 0); err != nil {
 		return err
 	}
-	defer stmtTrack.Close()
 
-	stmtLink, err := tx.PrepareContext(ctx, `
-		INSERT INTO playlist_tracks (playlist_id, track_id)
-		VALUES (?, ?)
-		ON CONFLICT(playlist_id, track_id) DO NOTHING
-	`)
-	if err != nil {
+	// 5. Record the event and the revision alongside the change
+	if err := enqueueEvent(ctx, tx, domain.EventPlaylistSaved, p.ID, domain.PlaylistSavedPayload{
+		Name:       p.Name,
+		TrackCount: len(p.Tracks),
+	}); err != nil {
 		return err
 	}
-	defer stmtLink.Close()
-
-	for _, t := range p.Tracks {
-		// Ensure track exists in the global 'tracks' table
-		if _, err := stmtTrack.ExecContext(
-			ctx,
-			t.ID,
-			t.Title,
-			t.Artist,
-			t.Album,
-			t.DurationMs,
-			t.ISRC,
-			t.CoverURL,
-			t.PreviewURL,
-			t.Features.Danceability,
-			t.Features.Energy,
-			t.Features.Valence,
-			t.Features.Tempo,
-			t.Features.Instrumentalness,
-			t.Features.Acousticness,
-		); err != nil {
-			return fmt.Errorf("failed to save track %s: %w", t.ID, err)
-		}
-		// Create the link in 'playlist_tracks'
-		if _, err := stmtLink.ExecContext(ctx, p.ID, t.ID); err != nil {
-			return fmt.Errorf("failed to link track %s: %w", t.ID, err)
-		}
+	if _, err := writeRevision(ctx, tx, p.ID, domain.RevisionSave, 0); err != nil {
+		return err
 	}
 
-	// 5. Commit Transaction
-	if err := tx.Commit(); err != nil {
+	// 6. Commit Transaction
+	if err := scope.commit(); err != nil {
 		return fmt.Errorf("transaction commit failed: %w", err)
 	}
 
@@ -288,7 +334,7 @@ func (a *Adapter) AddTracksToPlaylist(ctx context.Context, playlistID string, tr
 	}
 
 	// 1. Verify playlist exists
-	row := a.db.QueryRowContext(ctx, "SELECT id FROM playlists WHERE id = ?", playlistID)
+	row := a.q.QueryRowContext(ctx, "SELECT id FROM playlists WHERE id = ?", playlistID)
 	var id string
 	if err := row.Scan(&id); err != nil {
 		if err == sql.ErrNoRows {
@@ -297,84 +343,122 @@ func (a *Adapter) AddTracksToPlaylist(ctx context.Context, playlistID string, tr
 		return fmt.Errorf("failed to verify playlist: %w", err)
 	}
 
-	// 2. Start Transaction
-	tx, err := a.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// 3. Prepare statements
-	stmtTrack, err := tx.PrepareContext(ctx, `
-		INSERT INTO tracks (
-			id, title, artist, album, duration_ms, isrc, cover_url, preview_url,
-			danceability, energy, valence, tempo, instrumentalness, acousticness
-		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			title=excluded.title,
-			artist=excluded.artist,
-			album=excluded.album,
-			duration_ms=excluded.duration_ms,
-			isrc=excluded.isrc,
-			cover_url=excluded.cover_url,
-			preview_url=excluded.preview_url,
-			danceability=excluded.danceability,
-			energy=excluded.energy,
-			valence=excluded.valence,
-			tempo=excluded.tempo,
-			instrumentalness=excluded.instrumentalness,
-			acousticness=excluded.acousticness;
-	`)
+	// 2. Start Transaction (or join the enclosing unit of work)
+	scope, err := a.begin(ctx)
 	if err != nil {
 		return err
 	}
-	defer stmtTrack.Close()
+	defer scope.rollback()
+	tx := scope.tx
 
-	stmtLink, err := tx.PrepareContext(ctx, `
-		INSERT INTO playlist_tracks (playlist_id, track_id)
-		VALUES (?, ?)
-		ON CONFLICT(playlist_id, track_id) DO NOTHING
-	`)
-	if err != nil {
+	// 3. Insert tracks and links in batches after the existing ones
+	var next int
+	if err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(position) + 1, 0) FROM playlist_tracks WHERE playlist_id = ?", playlistID).Scan(&next); err != nil {
+		return fmt.Errorf("failed to find end of playlist: %w", err)
+	}
+	if err := writeTracks(ctx, tx, playlistID, tracks, next); err != nil {
 		return err
 	}
-	defer stmtLink.Close()
 
-	// 4. Insert each track
+	// 4. Record the event and the revision alongside the change
+	trackIDs := make([]string, 0, len(tracks))
 	for _, t := range tracks {
-		if _, err := stmtTrack.ExecContext(
-			ctx,
-			t.ID,
-			t.Title,
-			t.Artist,
-			t.Album,
-			t.DurationMs,
-			t.ISRC,
-			t.CoverURL,
-			t.PreviewURL,
-			t.Features.Danceability,
-			t.Features.Energy,
-			t.Features.Valence,
-			t.Features.Tempo,
-			t.Features.Instrumentalness,
-			t.Features.Acousticness,
-		); err != nil {
-			return fmt.Errorf("failed to save track %s: %w", t.ID, err)
-		}
-		if _, err := stmtLink.ExecContext(ctx, playlistID, t.ID); err != nil {
-			return fmt.Errorf("failed to link track %s: %w", t.ID, err)
-		}
+		trackIDs = append(trackIDs, t.ID)
+	}
+	if err := enqueueEvent(ctx, tx, domain.EventTracksAdded, playlistID, domain.TracksAddedPayload{
+		TrackIDs: trackIDs,
+	}); err != nil {
+		return err
+	}
+	if _, err := writeRevision(ctx, tx, playlistID, domain.RevisionAddTracks, 0); err != nil {
+		return err
 	}
 
 	// 5. Commit Transaction
-	if err := tx.Commit(); err != nil {
+	if err := scope.commit(); err != nil {
 		return fmt.Errorf("transaction commit failed: %w", err)
 	}
 
 	return nil
 }
 
+// ReorderTracks implements ports.PlaylistRepository.
+func (a *Adapter) ReorderTracks(ctx context.Context, playlistID string, trackIDs []string) error {
+	scope, err := a.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer scope.rollback()
+	tx := scope.tx
+
+	var id string
+	if err := tx.QueryRowContext(ctx, "SELECT id FROM playlists WHERE id = ?", playlistID).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to verify playlist: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "UPDATE playlist_tracks SET position = ? WHERE playlist_id = ? AND track_id = ?")
+	if err != nil {
+		return fmt.Errorf("failed to prepare reorder: %w", err)
+	}
+	defer stmt.Close()
+	for position, trackID := range trackIDs {
+		if _, err := stmt.ExecContext(ctx, position, playlistID, trackID); err != nil {
+			return fmt.Errorf("failed to move track %s: %w", trackID, err)
+		}
+	}
+
+	if err := enqueueEvent(ctx, tx, domain.EventTracksReordered, playlistID, domain.TracksReorderedPayload{
+		TrackIDs: trackIDs,
+	}); err != nil {
+		return err
+	}
+	if _, err := writeRevision(ctx, tx, playlistID, domain.RevisionReorder, 0); err != nil {
+		return err
+	}
+
+	if err := scope.commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return nil
+}
+
+// txScope is a transaction used by a single repository write. When the
+// adapter is bound to a unit of work the scope borrows the enclosing
+// transaction and leaves committing or rolling back to the unit of work.
+type txScope struct {
+	tx    *sql.Tx
+	owned bool
+}
+
+func (s txScope) commit() error {
+	if !s.owned {
+		return nil
+	}
+	return s.tx.Commit()
+}
+
+func (s txScope) rollback() {
+	if s.owned {
+		_ = s.tx.Rollback()
+	}
+}
+
+// begin starts a transaction for a multi-statement write, or joins the
+// enclosing unit of work.
+func (a *Adapter) begin(ctx context.Context) (txScope, error) {
+	if a.tx != nil {
+		return txScope{tx: a.tx}, nil
+	}
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return txScope{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return txScope{tx: tx, owned: true}, nil
+}
+
 func (a *Adapter) migrate() error {
 	query := `
 	CREATE TABLE IF NOT EXISTS tracks (
@@ -405,10 +489,217 @@ func (a *Adapter) migrate() error {
 		playlist_id TEXT,
 		track_id TEXT,
 		added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		position INTEGER,
 		PRIMARY KEY (playlist_id, track_id),
 		FOREIGN KEY(playlist_id) REFERENCES playlists(id) ON DELETE CASCADE,
 		FOREIGN KEY(track_id) REFERENCES tracks(id) ON DELETE CASCADE
 	);
+
+	CREATE TABLE IF NOT EXISTS locks (
+		key TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		expires_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS outbox (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT NOT NULL UNIQUE,
+		type TEXT NOT NULL,
+		playlist_id TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		occurred_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS intent_captures (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT NOT NULL UNIQUE,
+		model TEXT NOT NULL,
+		prompt TEXT NOT NULL,
+		response TEXT NOT NULL,
+		error TEXT NOT NULL,
+		duration_ms INTEGER NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS intent_runs (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT NOT NULL UNIQUE,
+		playlist_id TEXT NOT NULL,
+		message TEXT NOT NULL,
+		intent TEXT NOT NULL,
+		candidates TEXT NOT NULL,
+		existing TEXT NOT NULL,
+		added TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS track_fingerprints (
+		track_id TEXT PRIMARY KEY,
+		recording_id TEXT NOT NULL,
+		fingerprint BLOB NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_track_fingerprints_recording ON track_fingerprints(recording_id);
+
+	CREATE TABLE IF NOT EXISTS track_lyrics (
+		track_id TEXT PRIMARY KEY,
+		plain TEXT NOT NULL,
+		synced TEXT NOT NULL,
+		instrumental INTEGER NOT NULL,
+		source TEXT NOT NULL,
+		sentiment REAL NOT NULL,
+		fetched_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS artists (
+		name_key TEXT PRIMARY KEY,
+		id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		genres TEXT NOT NULL,
+		image_url TEXT NOT NULL,
+		popularity INTEGER NOT NULL,
+		fetched_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_artists_id ON artists(id);
+
+	CREATE TABLE IF NOT EXISTS playback_state (
+		playlist_id TEXT PRIMARY KEY,
+		track_id TEXT NOT NULL,
+		position_ms INTEGER NOT NULL,
+		playing INTEGER NOT NULL,
+		device TEXT NOT NULL,
+		updated_at INTEGER NOT NULL,
+		FOREIGN KEY(playlist_id) REFERENCES playlists(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS queue_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		owner TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		track TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_queue_items_owner ON queue_items(owner, position);
+
+	CREATE TABLE IF NOT EXISTS playlist_settings (
+		playlist_id TEXT PRIMARY KEY,
+		allow_explicit INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL,
+		FOREIGN KEY(playlist_id) REFERENCES playlists(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS weather_settings (
+		owner TEXT PRIMARY KEY,
+		enabled INTEGER NOT NULL,
+		latitude REAL NOT NULL,
+		longitude REAL NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS mood_checkins (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		owner TEXT NOT NULL,
+		valence REAL NOT NULL,
+		energy REAL NOT NULL,
+		emoji TEXT NOT NULL DEFAULT '',
+		recorded_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_mood_checkins_owner ON mood_checkins(owner, recorded_at);
+
+	CREATE TABLE IF NOT EXISTS track_cooccurrence (
+		track_id TEXT NOT NULL,
+		other_id TEXT NOT NULL,
+		together INTEGER NOT NULL,
+		score REAL NOT NULL,
+		PRIMARY KEY (track_id, other_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS public_playlists (
+		playlist_id TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		published_at INTEGER NOT NULL,
+		FOREIGN KEY(playlist_id) REFERENCES playlists(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS playlist_copies (
+		copy_id TEXT PRIMARY KEY,
+		source_id TEXT NOT NULL,
+		copied_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_playlist_copies_source ON playlist_copies(source_id);
+
+	CREATE TABLE IF NOT EXISTS playlist_plays (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		playlist_id TEXT NOT NULL,
+		track_id TEXT NOT NULL,
+		played_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_playlist_plays_playlist ON playlist_plays(playlist_id, played_at);
+
+	CREATE TABLE IF NOT EXISTS spotify_auth_states (
+		state TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		verifier TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS spotify_tokens (
+		owner TEXT PRIMARY KEY,
+		access_token TEXT NOT NULL,
+		refresh_token TEXT NOT NULL,
+		expiry INTEGER NOT NULL,
+		scope TEXT NOT NULL DEFAULT ''
+	);
+
+	CREATE TABLE IF NOT EXISTS spotify_playlist_links (
+		playlist_id TEXT PRIMARY KEY,
+		spotify_id TEXT NOT NULL,
+		snapshot_id TEXT NOT NULL DEFAULT '',
+		synced_at INTEGER,
+		FOREIGN KEY(playlist_id) REFERENCES playlists(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS jobs (
+		id TEXT PRIMARY KEY,
+		track_id TEXT NOT NULL,
+		preview_url TEXT NOT NULL DEFAULT '',
+		state TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		run_at INTEGER NOT NULL,
+		claimed_by TEXT NOT NULL DEFAULT '',
+		lease_until INTEGER NOT NULL DEFAULT 0,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_jobs_state_run_at ON jobs(state, run_at);
+
+	CREATE TABLE IF NOT EXISTS intent_sessions (
+		id TEXT PRIMARY KEY,
+		playlist_id TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		FOREIGN KEY(playlist_id) REFERENCES playlists(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS intent_session_turns (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		message TEXT NOT NULL,
+		intent TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		FOREIGN KEY(session_id) REFERENCES intent_sessions(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS idx_intent_session_turns_session ON intent_session_turns(session_id, id);
+
+	CREATE TABLE IF NOT EXISTS playlist_revisions (
+		playlist_id TEXT NOT NULL,
+		rev INTEGER NOT NULL,
+		reason TEXT NOT NULL,
+		reverted_to INTEGER NOT NULL DEFAULT 0,
+		track_ids TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		PRIMARY KEY (playlist_id, rev),
+		FOREIGN KEY(playlist_id) REFERENCES playlists(id) ON DELETE CASCADE
+	);
 	`
 	if _, err := a.db.Exec(query); err != nil {
 		return err
@@ -454,6 +745,106 @@ func (a *Adapter) migrate() error {
 			return err
 		}
 	}
+	if _, err := a.db.Exec("ALTER TABLE tracks ADD COLUMN unlinked_at DATETIME"); err != nil {
+		if !isDuplicateColumnError(err) {
+			return err
+		}
+	}
+	// Podcast episodes share the tracks table, told apart by item_type;
+	// backfill_attempted_at orders the analysis backfill; source records
+	// the catalog provider, popularity its score for the track,
+	// release_year when its album came out and explicit its content rating.
+	for _, column := range []string{
+		"item_type TEXT NOT NULL DEFAULT 'track'",
+		"description TEXT NOT NULL DEFAULT ''",
+		"published_at INTEGER",
+		"backfill_attempted_at INTEGER",
+		"source TEXT NOT NULL DEFAULT ''",
+		"popularity INTEGER NOT NULL DEFAULT 0",
+		"release_year INTEGER NOT NULL DEFAULT 0",
+		"explicit INTEGER NOT NULL DEFAULT 0",
+	} {
+		if _, err := a.db.Exec("ALTER TABLE tracks ADD COLUMN " + column); err != nil {
+			if !isDuplicateColumnError(err) {
+				return err
+			}
+		}
+	}
+	// Tracks linked before playlists could be reordered keep the order
+	// they were added in.
+	if _, err := a.db.Exec("ALTER TABLE playlist_tracks ADD COLUMN position INTEGER"); err != nil {
+		if !isDuplicateColumnError(err) {
+			return err
+		}
+	}
+	if _, err := a.db.Exec(`
+		UPDATE playlist_tracks SET position = (
+			SELECT r.n FROM (
+				SELECT rowid AS rid, ROW_NUMBER() OVER (PARTITION BY playlist_id ORDER BY added_at, rowid) - 1 AS n
+				FROM playlist_tracks
+			) r WHERE r.rid = playlist_tracks.rowid
+		) WHERE position IS NULL`); err != nil {
+		return err
+	}
+	// version counts the changes to a playlist for optimistic saves.
+	if _, err := a.db.Exec("ALTER TABLE playlists ADD COLUMN version INTEGER NOT NULL DEFAULT 1"); err != nil {
+		if !isDuplicateColumnError(err) {
+			return err
+		}
+	}
+	for _, column := range []string{
+		"experiment TEXT NOT NULL DEFAULT ''",
+		"variant TEXT NOT NULL DEFAULT ''",
+		"scoring TEXT NOT NULL DEFAULT '{}'",
+	} {
+		if _, err := a.db.Exec("ALTER TABLE intent_runs ADD COLUMN " + column); err != nil {
+			if !isDuplicateColumnError(err) {
+				return err
+			}
+		}
+	}
+	if _, err := a.db.Exec("ALTER TABLE jobs ADD COLUMN trace_parent TEXT NOT NULL DEFAULT ''"); err != nil {
+		if !isDuplicateColumnError(err) {
+			return err
+		}
+	}
+	for _, column := range []string{
+		"snapshot_id TEXT NOT NULL DEFAULT ''",
+		"synced_at INTEGER",
+	} {
+		if _, err := a.db.Exec("ALTER TABLE spotify_playlist_links ADD COLUMN " + column); err != nil {
+			if !isDuplicateColumnError(err) {
+				return err
+			}
+		}
+	}
+	// Events used to be kept after delivery, stamped with published_at;
+	// now every event in the outbox is pending.
+	if _, err := a.db.Exec("DELETE FROM outbox WHERE published_at IS NOT NULL"); err != nil {
+		if !isMissingColumnError(err) {
+			return err
+		}
+	}
+	if _, err := a.db.Exec("DROP INDEX IF EXISTS idx_outbox_pending"); err != nil {
+		return err
+	}
+	if _, err := a.db.Exec("ALTER TABLE outbox DROP COLUMN published_at"); err != nil {
+		if !isMissingColumnError(err) {
+			return err
+		}
+	}
+	for _, column := range []string{
+		"max_tracks INTEGER NOT NULL DEFAULT 0",
+		"target_duration_ms INTEGER NOT NULL DEFAULT 0",
+		"locked_ordering INTEGER NOT NULL DEFAULT 0",
+		"default_market TEXT NOT NULL DEFAULT ''",
+	} {
+		if _, err := a.db.Exec("ALTER TABLE playlist_settings ADD COLUMN " + column); err != nil {
+			if !isDuplicateColumnError(err) {
+				return err
+			}
+		}
+	}
 
 	return nil
 }
@@ -461,3 +852,7 @@ func (a *Adapter) migrate() error {
 func isDuplicateColumnError(err error) bool {
 	return err != nil && (strings.Contains(err.Error(), "duplicate column") || strings.Contains(err.Error(), "already exists"))
 }
+
+func isMissingColumnError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "no such column")
+}
@@ -3,7 +3,9 @@ package sqlite
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
 )
@@ -211,3 +213,171 @@ func floatEquals(a, b, tol float64) bool {
 	}
 	return b-a <= tol
 }
+
+func TestAdapter_ListPlaylists(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+
+	ctx := context.Background()
+	for _, p := range []domain.Playlist{
+		{ID: "pl-a", Name: "First", Tracks: makeTracks(2)},
+		{ID: "pl-b", Name: "Second"},
+	} {
+		if err := a.Save(ctx, p); err != nil {
+			t.Fatalf("save %s: %v", p.ID, err)
+		}
+	}
+
+	got, err := a.ListPlaylists(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 playlists, got %d", len(got))
+	}
+	if got[0].ID != "pl-a" || len(got[0].Tracks) != 2 || got[1].ID != "pl-b" || len(got[1].Tracks) != 0 {
+		t.Fatalf("unexpected playlists: %+v", got)
+	}
+}
+
+func TestAdapter_ReorderTracks(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+
+	ctx := context.Background()
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: makeTracks(3)}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := a.ReorderTracks(ctx, "pl-1", []string{"t0002", "t0000", "t0001"}); err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	// Added tracks go after the reordered ones.
+	if err := a.AddTracksToPlaylist(ctx, "pl-1", []domain.Track{{ID: "t9", Title: "New", Artist: "B"}}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	pl, err := a.GetByID(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	var got []string
+	for _, tr := range pl.Tracks {
+		got = append(got, tr.ID)
+	}
+	if want := []string{"t0002", "t0000", "t0001", "t9"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected tracks %v, got %v", want, got)
+	}
+
+	if err := a.ReorderTracks(ctx, "missing", []string{"t0000"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAdapter_Save_Version(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+
+	ctx := context.Background()
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: makeTracks(1)}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	first, err := a.GetByID(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if first.Version != 1 {
+		t.Fatalf("expected version 1, got %d", first.Version)
+	}
+	second := first
+
+	// Two writers load the same version; the second one to save loses.
+	first.Tracks = append(first.Tracks, domain.Track{ID: "a", Title: "A", Artist: "X"})
+	if err := a.Save(ctx, first); err != nil {
+		t.Fatalf("save first: %v", err)
+	}
+	second.Tracks = append(second.Tracks, domain.Track{ID: "b", Title: "B", Artist: "X"})
+	if err := a.Save(ctx, second); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+
+	got, err := a.GetByID(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Version != 2 || len(got.Tracks) != 2 || got.Tracks[1].ID != "a" {
+		t.Fatalf("expected version 2 with the first writer's track, got %d %+v", got.Version, got.Tracks)
+	}
+
+	// Other changes move the version on too.
+	if err := a.AddTracksToPlaylist(ctx, "pl-1", []domain.Track{{ID: "c", Title: "C", Artist: "X"}}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := a.Save(ctx, got); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict after add, got %v", err)
+	}
+	got.Version = 0
+	if err := a.Save(ctx, got); err != nil {
+		t.Fatalf("unconditional save: %v", err)
+	}
+}
+
+func TestAdapter_Episodes(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	published := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	episode := domain.Episode{
+		ID: "ep-1", Title: "Tuesday Briefing", Show: "The Daily", Description: "News",
+		DurationMs: 600000, PublishedAt: published, PreviewURL: "http://audio.com/ep-1.mp3",
+	}
+	track := domain.Track{ID: "t1", Title: "Focus", Artist: "A", Features: domain.AudioFeatures{Energy: 0.4, Tempo: 90}}
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Focus", Tracks: []domain.Track{track}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := a.AddTracksToPlaylist(ctx, "pl-1", []domain.Track{episode.Item()}); err != nil {
+		t.Fatalf("add episode: %v", err)
+	}
+
+	pl, err := a.GetByID(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(pl.Tracks) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(pl.Tracks))
+	}
+	items := map[string]domain.Track{}
+	for _, item := range pl.Tracks {
+		items[item.ID] = item
+	}
+	if got := items["t1"]; got.Type != domain.ItemTrack || got.PublishedAt != nil {
+		t.Fatalf("expected a music track, got %+v", got)
+	}
+	got := items["ep-1"]
+	if got.Type != domain.ItemEpisode || got.Artist != "The Daily" || got.Description != "News" ||
+		got.PublishedAt == nil || !got.PublishedAt.Equal(published) {
+		t.Fatalf("unexpected episode %+v", got)
+	}
+
+	// Episodes have no audio features and must not dilute the averages.
+	features, err := a.GetPlaylistAudioFeatures(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("features: %v", err)
+	}
+	if features.Energy != 0.4 || features.Tempo != 90 {
+		t.Fatalf("expected the track's features only, got %+v", features)
+	}
+}
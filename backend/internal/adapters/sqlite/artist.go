@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// artistKey normalizes a searched-for name so "Daft Punk" and " daft punk"
+// share a cache entry.
+func artistKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// SaveArtist implements ports.ArtistStore.
+func (a *Adapter) SaveArtist(ctx context.Context, name string, artist domain.Artist) error {
+	genres := artist.Genres
+	if genres == nil {
+		genres = []string{}
+	}
+	b, err := json.Marshal(genres)
+	if err != nil {
+		return fmt.Errorf("failed to encode artist genres: %w", err)
+	}
+	_, err = a.q.ExecContext(ctx, `
+		INSERT INTO artists (name_key, id, name, genres, image_url, popularity, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name_key) DO UPDATE SET
+			id = excluded.id,
+			name = excluded.name,
+			genres = excluded.genres,
+			image_url = excluded.image_url,
+			popularity = excluded.popularity,
+			fetched_at = excluded.fetched_at`,
+		artistKey(name), artist.ID, artist.Name, string(b), artist.ImageURL, artist.Popularity, artist.FetchedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save artist: %w", err)
+	}
+	return nil
+}
+
+// EvictArtists implements ports.ArtistEvictor.
+func (a *Adapter) EvictArtists(ctx context.Context, cutoff time.Time) (int, error) {
+	res, err := a.q.ExecContext(ctx, "DELETE FROM artists WHERE fetched_at < ?", cutoff.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("failed to evict artists: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to evict artists: %w", err)
+	}
+	return int(n), nil
+}
+
+// GetArtist implements ports.ArtistStore.
+func (a *Adapter) GetArtist(ctx context.Context, name string) (domain.Artist, error) {
+	row := a.q.QueryRowContext(ctx, `
+		SELECT id, name, genres, image_url, popularity, fetched_at
+		FROM artists WHERE name_key = ?`, artistKey(name))
+	var artist domain.Artist
+	var genres string
+	var fetchedAt int64
+	err := row.Scan(&artist.ID, &artist.Name, &genres, &artist.ImageURL, &artist.Popularity, &fetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Artist{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Artist{}, fmt.Errorf("failed to load artist: %w", err)
+	}
+	if err := json.Unmarshal([]byte(genres), &artist.Genres); err != nil {
+		return domain.Artist{}, fmt.Errorf("failed to decode artist genres: %w", err)
+	}
+	artist.FetchedAt = time.Unix(0, fetchedAt).UTC()
+	return artist, nil
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Artists(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	if _, err := a.GetArtist(ctx, "Daft Punk"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before saving, got %v", err)
+	}
+
+	want := domain.Artist{
+		ID:         "4tZwfgrHOc3mvqYlEYSvVi",
+		Name:       "Daft Punk",
+		Genres:     []string{"electro", "french house"},
+		ImageURL:   "https://i.scdn.co/image/daft",
+		Popularity: 80,
+		FetchedAt:  time.Unix(1700000000, 0).UTC(),
+	}
+	if err := a.SaveArtist(ctx, "Daft Punk", want); err != nil {
+		t.Fatalf("save artist: %v", err)
+	}
+	got, err := a.GetArtist(ctx, "  daft punk ")
+	if err != nil {
+		t.Fatalf("get artist: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	// Saving again under the same name refreshes the entry.
+	want.Popularity = 81
+	want.Genres = nil
+	want.FetchedAt = time.Unix(1800000000, 0).UTC()
+	if err := a.SaveArtist(ctx, "DAFT PUNK", want); err != nil {
+		t.Fatalf("refresh artist: %v", err)
+	}
+	got, err = a.GetArtist(ctx, "Daft Punk")
+	if err != nil {
+		t.Fatalf("get refreshed artist: %v", err)
+	}
+	if got.Popularity != 81 || len(got.Genres) != 0 || !got.FetchedAt.Equal(want.FetchedAt) {
+		t.Fatalf("expected refreshed entry, got %+v", got)
+	}
+}
+
+func TestAdapter_EvictArtists(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	cutoff := time.Unix(1700000000, 0)
+	for name, fetched := range map[string]time.Time{
+		"Daft Punk": cutoff.Add(-time.Hour),
+		"Justice":   cutoff.Add(time.Hour),
+	} {
+		if err := a.SaveArtist(ctx, name, domain.Artist{Name: name, FetchedAt: fetched}); err != nil {
+			t.Fatalf("save %s: %v", name, err)
+		}
+	}
+
+	n, err := a.EvictArtists(ctx, cutoff)
+	if err != nil {
+		t.Fatalf("evict artists: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 artist evicted, got %d", n)
+	}
+	if _, err := a.GetArtist(ctx, "Daft Punk"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected the stale artist evicted, got %v", err)
+	}
+	if _, err := a.GetArtist(ctx, "Justice"); err != nil {
+		t.Fatalf("expected the fresh artist kept, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// missingAnalysisPredicate matches music tracks without a preview or with
+// every feature unset.
+const missingAnalysisPredicate = ` t.item_type = 'track' AND (
+	IFNULL(t.preview_url, '') = '' OR (
+		IFNULL(t.danceability, 0) = 0 AND IFNULL(t.energy, 0) = 0 AND IFNULL(t.valence, 0) = 0 AND
+		IFNULL(t.tempo, 0) = 0 AND IFNULL(t.instrumentalness, 0) = 0 AND IFNULL(t.acousticness, 0) = 0))`
+
+// TracksMissingAnalysis implements ports.AnalysisBacklog.
+func (a *Adapter) TracksMissingAnalysis(ctx context.Context, limit int) ([]domain.Track, error) {
+	rows, err := a.q.QueryContext(ctx, "SELECT "+trackSelect+" FROM tracks t WHERE"+missingAnalysisPredicate+`
+		ORDER BY IFNULL(t.backfill_attempted_at, 0) ASC, t.id ASC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tracks missing analysis: %w", err)
+	}
+	defer rows.Close()
+
+	tracks := []domain.Track{}
+	for rows.Next() {
+		track, err := scanTrack(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan track: %w", err)
+		}
+		tracks = append(tracks, track)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tracks missing analysis: %w", err)
+	}
+	return tracks, nil
+}
+
+// MarkBackfillAttempted implements ports.AnalysisBacklog.
+func (a *Adapter) MarkBackfillAttempted(ctx context.Context, trackIDs []string, at time.Time) error {
+	if len(trackIDs) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(trackIDs)+1)
+	args = append(args, at.UnixNano())
+	for _, id := range trackIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(trackIDs)), ", ")
+	if _, err := a.q.ExecContext(ctx, "UPDATE tracks SET backfill_attempted_at = ? WHERE id IN ("+placeholders+")", args...); err != nil {
+		return fmt.Errorf("failed to mark backfill attempts: %w", err)
+	}
+	return nil
+}
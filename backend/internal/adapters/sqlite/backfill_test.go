@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_TracksMissingAnalysis(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	tracks := makeTracks(4)
+	tracks[0].PreviewURL = "https://p.scdn.co/0.mp3" // analyzed
+	tracks[1].PreviewURL = ""                        // no preview
+	tracks[2].PreviewURL = "https://p.scdn.co/2.mp3"
+	tracks[2].Features = domain.AudioFeatures{} // never analyzed
+	tracks[3].PreviewURL = ""
+	episode := domain.Track{ID: "ep-1", Title: "Episode", Type: domain.ItemEpisode}
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: append(tracks, episode)}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	ids := func(limit int) []string {
+		t.Helper()
+		got, err := a.TracksMissingAnalysis(ctx, limit)
+		if err != nil {
+			t.Fatalf("tracks missing analysis: %v", err)
+		}
+		out := []string{}
+		for _, tr := range got {
+			out = append(out, tr.ID)
+		}
+		return out
+	}
+
+	if got := ids(10); len(got) != 3 || got[0] != "t0001" || got[1] != "t0002" || got[2] != "t0003" {
+		t.Fatalf("expected t0001, t0002, t0003, got %v", got)
+	}
+	if got := ids(2); len(got) != 2 {
+		t.Fatalf("expected limit to apply, got %v", got)
+	}
+
+	// Attempted tracks move behind the ones not yet tried.
+	if err := a.MarkBackfillAttempted(ctx, []string{"t0001", "t0002"}, time.Now()); err != nil {
+		t.Fatalf("mark attempted: %v", err)
+	}
+	if got := ids(1); len(got) != 1 || got[0] != "t0003" {
+		t.Fatalf("expected t0003 first, got %v", got)
+	}
+	if err := a.MarkBackfillAttempted(ctx, nil, time.Now()); err != nil {
+		t.Fatalf("mark nothing: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Snapshot implements ports.Snapshotter using SQLite's online backup API,
+// which copies pages incrementally and restarts if a writer modifies the
+// source mid-copy, so the result is always consistent.
+func (a *Adapter) Snapshot(ctx context.Context, path string) error {
+	dest, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot %s: %w", path, err)
+	}
+	defer dest.Close()
+
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot %s: %w", path, err)
+	}
+	defer destConn.Close()
+
+	srcConn, err := a.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer srcConn.Close()
+
+	return destConn.Raw(func(destDriver any) error {
+		return srcConn.Raw(func(srcDriver any) error {
+			destSQLite, ok := destDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected snapshot driver connection %T", destDriver)
+			}
+			srcSQLite, ok := srcDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected source driver connection %T", srcDriver)
+			}
+
+			backup, err := destSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return fmt.Errorf("failed to start backup: %w", err)
+			}
+			if _, err := backup.Step(-1); err != nil {
+				_ = backup.Finish()
+				return fmt.Errorf("failed to copy database: %w", err)
+			}
+			if err := backup.Finish(); err != nil {
+				return fmt.Errorf("failed to finish backup: %w", err)
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Snapshot(t *testing.T) {
+	dir := t.TempDir()
+	a, err := NewAdapter(filepath.Join(dir, "source.db"))
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+
+	ctx := context.Background()
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Backed Up", Tracks: makeTracks(3)}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	snapshot := filepath.Join(dir, "snapshot.db")
+	if err := a.Snapshot(ctx, snapshot); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+
+	restored, err := NewAdapter(snapshot)
+	if err != nil {
+		t.Fatalf("open snapshot: %v", err)
+	}
+	defer restored.Close()
+
+	got, err := restored.GetByID(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("get from snapshot: %v", err)
+	}
+	if got.Name != "Backed Up" || len(got.Tracks) != 3 {
+		t.Fatalf("unexpected snapshot contents: name=%q tracks=%d", got.Name, len(got.Tracks))
+	}
+}
@@ -0,0 +1,129 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// bulkBatchSize is the number of rows written per multi-row INSERT. At 21
+// columns per track, 100 rows stays well below SQLite's bound-parameter limit.
+var bulkBatchSize = 100
+
+const trackColumns = 21
+
+// upsertTracksSQL returns a multi-row track upsert for n rows. An empty
+// preview URL keeps the stored one, which may have come from a fallback
+// source, an empty source keeps the recorded provider, and a missing ISRC,
+// popularity or release year keeps what a fuller catalog response recorded.
+func upsertTracksSQL(n int) string {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", trackColumns), ", ") + ")"
+	return `
+		INSERT INTO tracks (
+			id, title, artist, album, duration_ms, isrc, cover_url, preview_url,
+			danceability, energy, valence, tempo, instrumentalness, acousticness,
+			item_type, description, published_at, source, popularity, release_year, explicit
+		)
+		VALUES ` + strings.TrimSuffix(strings.Repeat(row+", ", n), ", ") + `
+		ON CONFLICT(id) DO UPDATE SET
+			title=excluded.title,
+			artist=excluded.artist,
+			album=excluded.album,
+			duration_ms=excluded.duration_ms,
+			isrc=COALESCE(NULLIF(excluded.isrc, ''), tracks.isrc),
+			cover_url=excluded.cover_url,
+			preview_url=COALESCE(NULLIF(excluded.preview_url, ''), tracks.preview_url),
+			danceability=excluded.danceability,
+			energy=excluded.energy,
+			valence=excluded.valence,
+			tempo=excluded.tempo,
+			instrumentalness=excluded.instrumentalness,
+			acousticness=excluded.acousticness,
+			item_type=excluded.item_type,
+			description=excluded.description,
+			published_at=excluded.published_at,
+			source=COALESCE(NULLIF(excluded.source, ''), tracks.source),
+			popularity=COALESCE(NULLIF(excluded.popularity, 0), tracks.popularity),
+			release_year=COALESCE(NULLIF(excluded.release_year, 0), tracks.release_year),
+			explicit=excluded.explicit;
+	`
+}
+
+// linkTracksSQL returns a multi-row playlist_tracks insert for n rows.
+func linkTracksSQL(n int) string {
+	return `
+		INSERT INTO playlist_tracks (playlist_id, track_id, position)
+		VALUES ` + strings.TrimSuffix(strings.Repeat("(?, ?, ?), ", n), ", ") + `
+		ON CONFLICT(playlist_id, track_id) DO NOTHING
+	`
+}
+
+// writeTracks upserts tracks and links them to playlistID within tx,
+// numbering their positions from first. Tracks are written in multi-row batches
+// first and links afterwards, so a large import costs a handful of
+// statements instead of two per track.
+func writeTracks(ctx context.Context, tx *sql.Tx, playlistID string, tracks []domain.Track, first int) error {
+	for start := 0; start < len(tracks); start += bulkBatchSize {
+		batch := tracks[start:min(start+bulkBatchSize, len(tracks))]
+		args := make([]any, 0, len(batch)*trackColumns)
+		for _, t := range batch {
+			args = append(args,
+				t.ID,
+				t.Title,
+				t.Artist,
+				t.Album,
+				t.DurationMs,
+				t.ISRC,
+				t.CoverURL,
+				t.PreviewURL,
+				t.Features.Danceability,
+				t.Features.Energy,
+				t.Features.Valence,
+				t.Features.Tempo,
+				t.Features.Instrumentalness,
+				t.Features.Acousticness,
+				itemType(t),
+				t.Description,
+				publishedAt(t),
+				t.Source,
+				t.Popularity,
+				t.ReleaseYear,
+				t.Explicit,
+			)
+		}
+		if _, err := tx.ExecContext(ctx, upsertTracksSQL(len(batch)), args...); err != nil {
+			return fmt.Errorf("failed to save tracks %s..%s: %w", batch[0].ID, batch[len(batch)-1].ID, err)
+		}
+	}
+
+	for start := 0; start < len(tracks); start += bulkBatchSize {
+		batch := tracks[start:min(start+bulkBatchSize, len(tracks))]
+		args := make([]any, 0, len(batch)*3)
+		for i, t := range batch {
+			args = append(args, playlistID, t.ID, first+start+i)
+		}
+		if _, err := tx.ExecContext(ctx, linkTracksSQL(len(batch)), args...); err != nil {
+			return fmt.Errorf("failed to link tracks %s..%s: %w", batch[0].ID, batch[len(batch)-1].ID, err)
+		}
+	}
+
+	return nil
+}
+
+// itemType returns the stored item_type of t; tracks without one are music.
+func itemType(t domain.Track) string {
+	if t.Type == "" {
+		return string(domain.ItemTrack)
+	}
+	return string(t.Type)
+}
+
+func publishedAt(t domain.Track) any {
+	if t.PublishedAt == nil {
+		return nil
+	}
+	return t.PublishedAt.UnixNano()
+}
@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func makeTracks(n int) []domain.Track {
+	tracks := make([]domain.Track, n)
+	for i := range tracks {
+		tracks[i] = domain.Track{
+			ID:         fmt.Sprintf("t%04d", i),
+			Title:      fmt.Sprintf("Song %d", i),
+			Artist:     "Artist",
+			DurationMs: 180000,
+			Features:   domain.AudioFeatures{Energy: 0.5, Tempo: 120},
+		}
+	}
+	return tracks
+}
+
+func TestAdapter_SaveBatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		tracks int
+	}{
+		{name: "empty playlist", tracks: 0},
+		{name: "single partial batch", tracks: 7},
+		{name: "exact batch", tracks: 100},
+		{name: "several batches with remainder", tracks: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAdapter(":memory:")
+			if err != nil {
+				t.Fatalf("new adapter: %v", err)
+			}
+			defer a.Close()
+
+			ctx := context.Background()
+			tracks := makeTracks(tt.tracks)
+			if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Import", Tracks: tracks}); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+
+			got, err := a.GetByID(ctx, "pl-1")
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if len(got.Tracks) != tt.tracks {
+				t.Fatalf("expected %d tracks, got %d", tt.tracks, len(got.Tracks))
+			}
+		})
+	}
+}
+
+// BenchmarkSave compares importing a 500-track playlist one row per
+// statement against multi-row batches.
+func BenchmarkSave(b *testing.B) {
+	tracks := makeTracks(500)
+
+	for _, batchSize := range []int{1, 100} {
+		b.Run(fmt.Sprintf("batch=%d", batchSize), func(b *testing.B) {
+			prev := bulkBatchSize
+			bulkBatchSize = batchSize
+			defer func() { bulkBatchSize = prev }()
+
+			a, err := NewAdapter(filepath.Join(b.TempDir(), "bench.db"))
+			if err != nil {
+				b.Fatalf("new adapter: %v", err)
+			}
+			defer a.Close()
+
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p := domain.Playlist{ID: fmt.Sprintf("pl-%d", i), Name: "Import", Tracks: tracks}
+				if err := a.Save(ctx, p); err != nil {
+					b.Fatalf("save: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestAdapter_SaveKeepsCatalogIDs(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	ctx := context.Background()
+
+	tracks := makeTracks(1)
+	tracks[0].ISRC = "USAAA2400001"
+	tracks[0].Popularity = 64
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := a.GetTrack(ctx, tracks[0].ID)
+	if err != nil {
+		t.Fatalf("get track: %v", err)
+	}
+	if got.ISRC != "USAAA2400001" || got.Popularity != 64 {
+		t.Fatalf("expected ISRC and popularity to be stored, got %+v", got)
+	}
+
+	// A simplified catalog object without them keeps what was stored.
+	tracks[0].ISRC, tracks[0].Popularity = "", 0
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-2", Name: "Q", Tracks: tracks}); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+	got, err = a.GetTrack(ctx, tracks[0].ID)
+	if err != nil {
+		t.Fatalf("get track: %v", err)
+	}
+	if got.ISRC != "USAAA2400001" || got.Popularity != 64 {
+		t.Fatalf("expected ISRC and popularity to survive, got %+v", got)
+	}
+}
@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+const captureColumns = "id, model, prompt, response, error, duration_ms, created_at"
+
+// SaveCapture implements ports.CaptureStore.
+func (a *Adapter) SaveCapture(ctx context.Context, c domain.IntentCapture) error {
+	_, err := a.q.ExecContext(ctx,
+		"INSERT INTO intent_captures ("+captureColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)",
+		c.ID, c.Model, c.Prompt, c.Response, c.Error, c.DurationMS, c.CreatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save intent capture: %w", err)
+	}
+	return nil
+}
+
+// ListCaptures implements ports.CaptureStore.
+func (a *Adapter) ListCaptures(ctx context.Context, limit int) ([]domain.IntentCapture, error) {
+	rows, err := a.q.QueryContext(ctx,
+		"SELECT "+captureColumns+" FROM intent_captures ORDER BY seq DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intent captures: %w", err)
+	}
+	defer rows.Close()
+
+	captures := []domain.IntentCapture{}
+	for rows.Next() {
+		c, err := scanCapture(rows)
+		if err != nil {
+			return nil, err
+		}
+		captures = append(captures, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate intent captures: %w", err)
+	}
+	return captures, nil
+}
+
+// GetCapture implements ports.CaptureStore.
+func (a *Adapter) GetCapture(ctx context.Context, id string) (domain.IntentCapture, error) {
+	row := a.q.QueryRowContext(ctx, "SELECT "+captureColumns+" FROM intent_captures WHERE id = ?", id)
+	c, err := scanCapture(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.IntentCapture{}, domain.ErrNotFound
+	}
+	return c, err
+}
+
+// PruneCaptures implements ports.CaptureStore.
+func (a *Adapter) PruneCaptures(ctx context.Context, cutoff time.Time, keep int) (int64, error) {
+	var cutoffNanos int64
+	if !cutoff.IsZero() {
+		cutoffNanos = cutoff.UnixNano()
+	}
+	if keep <= 0 {
+		keep = -1 // LIMIT -1 selects every row
+	}
+	res, err := a.q.ExecContext(ctx, `
+		DELETE FROM intent_captures
+		WHERE created_at < ?
+		   OR seq NOT IN (SELECT seq FROM intent_captures ORDER BY seq DESC LIMIT ?)`,
+		cutoffNanos, keep)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune intent captures: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune intent captures: %w", err)
+	}
+	return n, nil
+}
+
+func scanCapture(s interface{ Scan(...any) error }) (domain.IntentCapture, error) {
+	var c domain.IntentCapture
+	var createdAt int64
+	if err := s.Scan(&c.ID, &c.Model, &c.Prompt, &c.Response, &c.Error, &c.DurationMS, &createdAt); err != nil {
+		return c, fmt.Errorf("failed to scan intent capture: %w", err)
+	}
+	c.CreatedAt = time.Unix(0, createdAt).UTC()
+	return c, nil
+}
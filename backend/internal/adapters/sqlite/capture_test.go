@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Captures(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for i := 0; i < 5; i++ {
+		c := domain.IntentCapture{
+			ID:        fmt.Sprintf("c%d", i),
+			Model:     "test",
+			Prompt:    "prompt",
+			Response:  "{}",
+			CreatedAt: now.Add(time.Duration(i-4) * time.Hour),
+		}
+		if err := a.SaveCapture(ctx, c); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	got, err := a.ListCaptures(ctx, 2)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "c4" || got[1].ID != "c3" {
+		t.Fatalf("expected newest two captures, got %+v", got)
+	}
+	if _, err := a.GetCapture(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cutoff  time.Time
+		keep    int
+		wantIDs []string
+	}{
+		{name: "no limits", keep: 0, wantIDs: []string{"c4", "c3", "c2", "c1", "c0"}},
+		{name: "by age", cutoff: now.Add(-150 * time.Minute), wantIDs: []string{"c4", "c3", "c2"}},
+		{name: "by count", keep: 1, wantIDs: []string{"c4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.PruneCaptures(ctx, tt.cutoff, tt.keep); err != nil {
+				t.Fatalf("prune: %v", err)
+			}
+			remaining, err := a.ListCaptures(ctx, 10)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			ids := make([]string, len(remaining))
+			for i, c := range remaining {
+				ids[i] = c.ID
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Fatalf("remaining = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
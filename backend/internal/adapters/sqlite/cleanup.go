@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// orphanPredicate matches tracks with no playlist link that have been
+// unlinked (or, if never linked, created) before the cutoff bound as ?.
+// Timestamps are compared in SQLite's CURRENT_TIMESTAMP text format.
+const orphanPredicate = `
+	NOT EXISTS (SELECT 1 FROM playlist_tracks pt WHERE pt.track_id = tracks.id)
+	AND COALESCE(tracks.unlinked_at, tracks.created_at) < ?
+`
+
+func sqliteTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// OrphanedTracks implements ports.OrphanCleaner.
+func (a *Adapter) OrphanedTracks(ctx context.Context, cutoff time.Time) ([]string, error) {
+	rows, err := a.q.QueryContext(ctx, "SELECT id FROM tracks WHERE"+orphanPredicate+"ORDER BY id", sqliteTimestamp(cutoff))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphaned tracks: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan orphaned track: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate orphaned tracks: %w", err)
+	}
+	return ids, nil
+}
+
+// DeleteOrphanedTracks implements ports.OrphanCleaner. The orphan check and
+// the delete are one statement, so a track re-linked concurrently survives.
+// Fingerprints of deleted tracks are dropped with them, as are lyrics cached
+// for tracks that are not stored, including intent candidates never added.
+func (a *Adapter) DeleteOrphanedTracks(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := a.q.ExecContext(ctx, "DELETE FROM tracks WHERE"+orphanPredicate, sqliteTimestamp(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete orphaned tracks: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete orphaned tracks: %w", err)
+	}
+	if n > 0 {
+		if _, err := a.q.ExecContext(ctx, "DELETE FROM track_fingerprints WHERE track_id NOT IN (SELECT id FROM tracks)"); err != nil {
+			return n, fmt.Errorf("failed to delete orphaned fingerprints: %w", err)
+		}
+	}
+	if _, err := a.q.ExecContext(ctx, "DELETE FROM track_lyrics WHERE track_id NOT IN (SELECT id FROM tracks)"); err != nil {
+		return n, fmt.Errorf("failed to delete orphaned lyrics: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_OrphanedTracks(t *testing.T) {
+	tests := []struct {
+		name   string
+		cutoff time.Duration // relative to now
+		want   []string
+	}{
+		{name: "within grace period", cutoff: -time.Hour, want: []string{}},
+		{name: "past grace period", cutoff: time.Hour, want: []string{"t0001"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAdapter(":memory:")
+			if err != nil {
+				t.Fatalf("new adapter: %v", err)
+			}
+			defer a.Close()
+			a.db.SetMaxOpenConns(1)
+
+			// Save two tracks, then replace the playlist with only the first,
+			// orphaning the second.
+			ctx := context.Background()
+			tracks := makeTracks(2)
+			if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks}); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks[:1]}); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+
+			cutoff := time.Now().Add(tt.cutoff)
+			got, err := a.OrphanedTracks(ctx, cutoff)
+			if err != nil {
+				t.Fatalf("orphaned tracks: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("OrphanedTracks: got %v, want %v", got, tt.want)
+			}
+
+			n, err := a.DeleteOrphanedTracks(ctx, cutoff)
+			if err != nil {
+				t.Fatalf("delete orphaned tracks: %v", err)
+			}
+			if n != int64(len(tt.want)) {
+				t.Fatalf("DeleteOrphanedTracks: removed %d, want %d", n, len(tt.want))
+			}
+
+			p, err := a.GetByID(ctx, "pl-1")
+			if err != nil || len(p.Tracks) != 1 {
+				t.Fatalf("linked track must survive cleanup: tracks=%d err=%v", len(p.Tracks), err)
+			}
+		})
+	}
+}
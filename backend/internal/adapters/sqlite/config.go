@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config tunes the pragmas every connection opens with and the size of the
+// connection pool.
+type Config struct {
+	// JournalMode is the journal_mode pragma; "WAL" lets readers run
+	// alongside the writer instead of waiting for it. Empty keeps the
+	// database's mode.
+	JournalMode string
+	// BusyTimeout is how long a connection waits for another's lock before
+	// failing with "database is locked".
+	BusyTimeout time.Duration
+	// ForeignKeys enforces the schema's foreign keys, and with them the
+	// cascades that delete a playlist's rows along with it.
+	ForeignKeys bool
+	// MaxOpenConns and MaxIdleConns bound the pool; zero leaves
+	// database/sql's defaults.
+	MaxOpenConns int
+	MaxIdleConns int
+}
+
+// DefaultConfig is the tuning NewAdapter uses, meant for a server whose
+// workers and requests share the database.
+func DefaultConfig() Config {
+	return Config{
+		JournalMode:  "WAL",
+		BusyTimeout:  5 * time.Second,
+		ForeignKeys:  true,
+		MaxOpenConns: 8,
+		MaxIdleConns: 8,
+	}
+}
+
+// DSN returns storagePath with the connection parameters c asks for,
+// leaving any that storagePath sets itself.
+//
+// Transactions always take the write lock as they begin
+// (_txlock=immediate). A unit of work then reads what no other one is
+// about to change: with SQLite's default deferred transactions, two
+// concurrent intents both read the playlist, and the second to write fails
+// with "database is locked" instead of waiting its turn.
+func (c Config) DSN(storagePath string) string {
+	dsn := withParam(storagePath, "_txlock", "immediate")
+	if c.JournalMode != "" {
+		dsn = withParam(dsn, "_journal_mode", strings.ToUpper(c.JournalMode))
+	}
+	if c.BusyTimeout > 0 {
+		dsn = withParam(dsn, "_busy_timeout", strconv.FormatInt(c.BusyTimeout.Milliseconds(), 10))
+	}
+	if c.ForeignKeys {
+		dsn = withParam(dsn, "_foreign_keys", "1")
+	}
+	return dsn
+}
+
+// withParam adds key=value to the query of dsn unless it sets key already.
+func withParam(dsn, key, value string) string {
+	if strings.Contains(dsn, key+"=") {
+		return dsn
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + key + "=" + value
+}
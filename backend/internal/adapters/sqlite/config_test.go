@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfig_DSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		path string
+		want string
+	}{
+		{name: "zero config", path: "overture.db", want: "overture.db?_txlock=immediate"},
+		{
+			name: "tuned",
+			cfg:  Config{JournalMode: "wal", BusyTimeout: 5 * time.Second, ForeignKeys: true},
+			path: "overture.db",
+			want: "overture.db?_txlock=immediate&_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=1",
+		},
+		{
+			name: "keeps the path's own parameters",
+			cfg:  Config{JournalMode: "WAL", BusyTimeout: time.Second},
+			path: "file:overture.db?_txlock=deferred&_busy_timeout=100",
+			want: "file:overture.db?_txlock=deferred&_busy_timeout=100&_journal_mode=WAL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.DSN(tt.path); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOpen_Pragmas(t *testing.T) {
+	a, err := Open(filepath.Join(t.TempDir(), "overture.db"), DefaultConfig())
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer a.Close()
+
+	var mode string
+	var timeout, fk int
+	if err := a.db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("journal_mode: %v", err)
+	}
+	if err := a.db.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil {
+		t.Fatalf("busy_timeout: %v", err)
+	}
+	if err := a.db.QueryRow("PRAGMA foreign_keys").Scan(&fk); err != nil {
+		t.Fatalf("foreign_keys: %v", err)
+	}
+	if mode != "wal" || timeout != 5000 || fk != 1 {
+		t.Fatalf("expected wal, 5000 and 1, got %s, %d and %d", mode, timeout, fk)
+	}
+	if got := a.db.Stats().MaxOpenConnections; got != DefaultConfig().MaxOpenConns {
+		t.Fatalf("expected %d open connections at most, got %d", DefaultConfig().MaxOpenConns, got)
+	}
+}
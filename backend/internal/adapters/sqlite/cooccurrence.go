@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// PlaylistMemberships implements ports.CooccurrenceStore.
+func (a *Adapter) PlaylistMemberships(ctx context.Context) ([][]string, error) {
+	rows, err := a.q.QueryContext(ctx, `
+		SELECT pt.playlist_id, pt.track_id
+		FROM playlist_tracks pt
+		JOIN tracks t ON t.id = pt.track_id
+		WHERE t.item_type = 'track'
+		ORDER BY pt.playlist_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlist memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships [][]string
+	current := ""
+	for rows.Next() {
+		var playlistID, trackID string
+		if err := rows.Scan(&playlistID, &trackID); err != nil {
+			return nil, fmt.Errorf("failed to scan playlist membership: %w", err)
+		}
+		if len(memberships) == 0 || playlistID != current {
+			memberships = append(memberships, nil)
+			current = playlistID
+		}
+		memberships[len(memberships)-1] = append(memberships[len(memberships)-1], trackID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list playlist memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+// ReplaceCooccurrence implements ports.CooccurrenceStore.
+func (a *Adapter) ReplaceCooccurrence(ctx context.Context, pairs []domain.TrackPair) error {
+	scope, err := a.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer scope.rollback()
+	tx := scope.tx
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM track_cooccurrence"); err != nil {
+		return fmt.Errorf("failed to clear co-occurrence: %w", err)
+	}
+	for start := 0; start < len(pairs); start += bulkBatchSize {
+		batch := pairs[start:min(start+bulkBatchSize, len(pairs))]
+		args := make([]any, 0, len(batch)*4)
+		for _, p := range batch {
+			args = append(args, p.TrackID, p.OtherID, p.Together, p.Score)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO track_cooccurrence (track_id, other_id, together, score)
+			VALUES `+strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?), ", len(batch)), ", "), args...); err != nil {
+			return fmt.Errorf("failed to write co-occurrence: %w", err)
+		}
+	}
+
+	if err := scope.commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return nil
+}
+
+// AlsoAdded implements ports.CooccurrenceStore. Up to bulkBatchSize
+// trackIDs are used.
+func (a *Adapter) AlsoAdded(ctx context.Context, trackIDs []string, limit int) ([]domain.Recommendation, error) {
+	if len(trackIDs) == 0 {
+		return nil, nil
+	}
+	trackIDs = trackIDs[:min(len(trackIDs), bulkBatchSize)]
+	args := make([]any, 0, 2*len(trackIDs)+1)
+	for _, id := range trackIDs {
+		args = append(args, id)
+	}
+	args = append(args, args...)
+	args = append(args, limit)
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(trackIDs)), ", ")
+
+	rows, err := a.q.QueryContext(ctx, `
+		SELECT `+trackSelect+`, c.together, c.score
+		FROM (
+			SELECT other_id, SUM(together) AS together, SUM(score) AS score
+			FROM track_cooccurrence
+			WHERE track_id IN (`+placeholders+`) AND other_id NOT IN (`+placeholders+`)
+			GROUP BY other_id
+		) c
+		JOIN tracks t ON t.id = c.other_id
+		ORDER BY c.score DESC, c.together DESC, t.id
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load co-occurring tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var recs []domain.Recommendation
+	for rows.Next() {
+		var rec domain.Recommendation
+		track, err := scanTrack(trailingScanner{rows: rows, extra: []any{&rec.Together, &rec.Score}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan co-occurring track: %w", err)
+		}
+		rec.Track = track
+		recs = append(recs, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load co-occurring tracks: %w", err)
+	}
+	return recs, nil
+}
+
+// trailingScanner lets scanTrack read rows that select extra columns after
+// trackSelect, scanning those into extra.
+type trailingScanner struct {
+	rows  *sql.Rows
+	extra []any
+}
+
+func (s trailingScanner) Scan(dest ...any) error {
+	return s.rows.Scan(append(dest, s.extra...)...)
+}
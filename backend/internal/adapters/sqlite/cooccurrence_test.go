@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Cooccurrence(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	track := func(id string) domain.Track { return domain.Track{ID: id, Title: id, Artist: "Artist"} }
+	episode := domain.Track{ID: "ep", Title: "Episode", Artist: "Show", Type: domain.ItemEpisode}
+	for id, tracks := range map[string][]domain.Track{
+		"p1": {track("a"), track("b"), track("c")},
+		"p2": {track("a"), track("b"), episode},
+	} {
+		if err := a.Save(ctx, domain.Playlist{ID: id, Name: id}); err != nil {
+			t.Fatalf("save %s: %v", id, err)
+		}
+		if err := a.AddTracksToPlaylist(ctx, id, tracks); err != nil {
+			t.Fatalf("add tracks to %s: %v", id, err)
+		}
+	}
+
+	memberships, err := a.PlaylistMemberships(ctx)
+	if err != nil {
+		t.Fatalf("memberships: %v", err)
+	}
+	if len(memberships) != 2 || len(memberships[0]) != 3 || len(memberships[1]) != 2 {
+		t.Fatalf("expected music tracks per playlist, got %v", memberships)
+	}
+
+	if err := a.ReplaceCooccurrence(ctx, domain.BuildCooccurrence(memberships, 0)); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	recs, err := a.AlsoAdded(ctx, []string{"a"}, 10)
+	if err != nil {
+		t.Fatalf("also added: %v", err)
+	}
+	if len(recs) != 2 || recs[0].Track.ID != "b" || recs[0].Together != 2 || recs[1].Track.ID != "c" || recs[1].Track.Title != "c" {
+		t.Fatalf("expected b then c, got %+v", recs)
+	}
+
+	// Seeds are left out and scores add up across them.
+	recs, err = a.AlsoAdded(ctx, []string{"a", "b"}, 1)
+	if err != nil || len(recs) != 1 || recs[0].Track.ID != "c" || recs[0].Together != 2 {
+		t.Fatalf("expected c from both seeds, got %+v (%v)", recs, err)
+	}
+
+	// Replacing drops the old model.
+	if err := a.ReplaceCooccurrence(ctx, nil); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if recs, err := a.AlsoAdded(ctx, []string{"a"}, 10); err != nil || len(recs) != 0 {
+		t.Fatalf("expected an empty model, got %+v (%v)", recs, err)
+	}
+}
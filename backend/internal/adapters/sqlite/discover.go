@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// publicSelect loads public playlists with the numbers they are ranked by;
+// the single parameter is the time plays are counted from.
+const publicSelect = `
+	SELECT p.id, p.name, pp.owner, pp.published_at,
+		(SELECT COUNT(*) FROM playlist_tracks t WHERE t.playlist_id = p.id) AS track_count,
+		(SELECT COUNT(*) FROM playlist_copies c WHERE c.source_id = p.id) AS copies,
+		(SELECT COUNT(*) FROM playlist_plays l WHERE l.playlist_id = p.id AND l.played_at >= ?) AS plays
+	FROM public_playlists pp JOIN playlists p ON p.id = pp.playlist_id`
+
+// discoverOrder maps each sort to its ORDER BY clause. Ties go to the
+// most recently published.
+var discoverOrder = map[domain.DiscoverSort]string{
+	domain.DiscoverRecent:   "pp.published_at DESC, p.id",
+	domain.DiscoverCopied:   "copies DESC, pp.published_at DESC, p.id",
+	domain.DiscoverTrending: "plays DESC, pp.published_at DESC, p.id",
+}
+
+// Publish implements ports.DiscoveryStore.
+func (a *Adapter) Publish(ctx context.Context, playlistID, owner string, at time.Time) error {
+	var id string
+	if err := a.q.QueryRowContext(ctx, "SELECT id FROM playlists WHERE id = ?", playlistID).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to verify playlist: %w", err)
+	}
+	if _, err := a.q.ExecContext(ctx, `
+		INSERT INTO public_playlists (playlist_id, owner, published_at) VALUES (?, ?, ?)
+		ON CONFLICT(playlist_id) DO NOTHING`,
+		playlistID, owner, at.UnixNano()); err != nil {
+		return fmt.Errorf("failed to publish playlist: %w", err)
+	}
+	return nil
+}
+
+// Unpublish implements ports.DiscoveryStore.
+func (a *Adapter) Unpublish(ctx context.Context, playlistID string) error {
+	if _, err := a.q.ExecContext(ctx, "DELETE FROM public_playlists WHERE playlist_id = ?", playlistID); err != nil {
+		return fmt.Errorf("failed to unpublish playlist: %w", err)
+	}
+	return nil
+}
+
+// GetPublic implements ports.DiscoveryStore.
+func (a *Adapter) GetPublic(ctx context.Context, playlistID string, playsSince time.Time) (domain.PublicPlaylist, error) {
+	row := a.q.QueryRowContext(ctx, publicSelect+" WHERE p.id = ?", playsSince.UnixNano(), playlistID)
+	p, err := scanPublic(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PublicPlaylist{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.PublicPlaylist{}, fmt.Errorf("failed to load public playlist: %w", err)
+	}
+	return p, nil
+}
+
+// ListPublic implements ports.DiscoveryStore.
+func (a *Adapter) ListPublic(ctx context.Context, sort domain.DiscoverSort, playsSince time.Time, limit int) ([]domain.PublicPlaylist, error) {
+	order, ok := discoverOrder[sort]
+	if !ok {
+		return nil, domain.ErrInvalidSort
+	}
+	rows, err := a.q.QueryContext(ctx, publicSelect+" ORDER BY "+order+" LIMIT ?", playsSince.UnixNano(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public playlists: %w", err)
+	}
+	defer rows.Close()
+
+	var playlists []domain.PublicPlaylist
+	for rows.Next() {
+		p, err := scanPublic(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan public playlist: %w", err)
+		}
+		playlists = append(playlists, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list public playlists: %w", err)
+	}
+	return playlists, nil
+}
+
+// RecordCopy implements ports.DiscoveryStore.
+func (a *Adapter) RecordCopy(ctx context.Context, sourceID, copyID string, at time.Time) error {
+	if _, err := a.q.ExecContext(ctx, `
+		INSERT INTO playlist_copies (copy_id, source_id, copied_at) VALUES (?, ?, ?)`,
+		copyID, sourceID, at.UnixNano()); err != nil {
+		return fmt.Errorf("failed to record copy: %w", err)
+	}
+	return nil
+}
+
+func scanPublic(s interface{ Scan(...any) error }) (domain.PublicPlaylist, error) {
+	var p domain.PublicPlaylist
+	var publishedAt int64
+	if err := s.Scan(&p.ID, &p.Name, &p.Owner, &publishedAt, &p.TrackCount, &p.Copies, &p.Plays); err != nil {
+		return domain.PublicPlaylist{}, err
+	}
+	p.PublishedAt = time.Unix(0, publishedAt).UTC()
+	return p, nil
+}
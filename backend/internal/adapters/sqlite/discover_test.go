@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Discovery(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	ctx := context.Background()
+	base := time.Unix(1700000000, 0).UTC()
+
+	for _, p := range []domain.Playlist{
+		{ID: "old", Name: "Old", Tracks: []domain.Track{{ID: "t1", Title: "One", Artist: "A"}, {ID: "t2", Title: "Two", Artist: "A"}}},
+		{ID: "new", Name: "New", Tracks: []domain.Track{{ID: "t3", Title: "Three", Artist: "B"}}},
+		{ID: "private", Name: "Private"},
+	} {
+		if err := a.Save(ctx, p); err != nil {
+			t.Fatalf("save %s: %v", p.ID, err)
+		}
+	}
+	if err := a.Publish(ctx, "missing", "alice", base); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound publishing an unknown playlist, got %v", err)
+	}
+	if err := a.Publish(ctx, "old", "alice", base); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if err := a.Publish(ctx, "new", "bob", base.Add(time.Hour)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	// Publishing again keeps the original time.
+	if err := a.Publish(ctx, "old", "alice", base.Add(2*time.Hour)); err != nil {
+		t.Fatalf("republish: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := a.RecordCopy(ctx, "old", "copy-"+string(rune('a'+i)), base); err != nil {
+			t.Fatalf("record copy: %v", err)
+		}
+	}
+	// Two tracks start on "new"; the seek and the stale play don't count
+	// towards trending.
+	for _, s := range []domain.PlaybackState{
+		{PlaylistID: "old", TrackID: "t1", Playing: true, UpdatedAt: base.Add(-30 * 24 * time.Hour)},
+		{PlaylistID: "new", TrackID: "t3", Playing: true, UpdatedAt: base},
+		{PlaylistID: "new", TrackID: "t3", PositionMs: 9000, Playing: true, UpdatedAt: base},
+		{PlaylistID: "new", TrackID: "t4", Playing: true, UpdatedAt: base},
+	} {
+		if err := a.SavePlayback(ctx, s); err != nil {
+			t.Fatalf("save playback: %v", err)
+		}
+	}
+
+	since := base.Add(-domain.TrendingWindow)
+	tests := []struct {
+		sort domain.DiscoverSort
+		want []string
+	}{
+		{domain.DiscoverRecent, []string{"new", "old"}},
+		{domain.DiscoverCopied, []string{"old", "new"}},
+		{domain.DiscoverTrending, []string{"new", "old"}},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.sort), func(t *testing.T) {
+			got, err := a.ListPublic(ctx, tc.sort, since, 10)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected %v, got %+v", tc.want, got)
+			}
+			for i, id := range tc.want {
+				if got[i].ID != id {
+					t.Fatalf("expected %v, got %+v", tc.want, got)
+				}
+			}
+		})
+	}
+	if _, err := a.ListPublic(ctx, "popular", since, 10); !errors.Is(err, domain.ErrInvalidSort) {
+		t.Fatalf("expected ErrInvalidSort, got %v", err)
+	}
+	if got, err := a.ListPublic(ctx, domain.DiscoverRecent, since, 1); err != nil || len(got) != 1 {
+		t.Fatalf("expected the limit to apply, got %+v (%v)", got, err)
+	}
+
+	old, err := a.GetPublic(ctx, "old", since)
+	if err != nil {
+		t.Fatalf("get public: %v", err)
+	}
+	want := domain.PublicPlaylist{ID: "old", Name: "Old", Owner: "alice", TrackCount: 2, PublishedAt: base, Copies: 2}
+	if old != want {
+		t.Fatalf("expected %+v, got %+v", want, old)
+	}
+	if p, _ := a.GetPublic(ctx, "new", since); p.Plays != 2 {
+		t.Fatalf("expected 2 plays, got %+v", p)
+	}
+	if _, err := a.GetPublic(ctx, "private", since); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a private playlist, got %v", err)
+	}
+
+	if err := a.Unpublish(ctx, "old"); err != nil {
+		t.Fatalf("unpublish: %v", err)
+	}
+	if _, err := a.GetPublic(ctx, "old", since); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after unpublishing, got %v", err)
+	}
+	if err := a.Unpublish(ctx, "old"); err != nil {
+		t.Fatalf("unpublishing twice: %v", err)
+	}
+}
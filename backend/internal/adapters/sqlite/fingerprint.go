@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/fingerprint"
+)
+
+// SaveFingerprint implements ports.FingerprintStore.
+func (a *Adapter) SaveFingerprint(ctx context.Context, fp domain.TrackFingerprint) error {
+	_, err := a.q.ExecContext(ctx, `
+		INSERT INTO track_fingerprints (track_id, recording_id, fingerprint, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(track_id) DO UPDATE SET
+			recording_id = excluded.recording_id,
+			fingerprint = excluded.fingerprint,
+			created_at = excluded.created_at`,
+		fp.TrackID, fp.RecordingID, fingerprint.Encode(fp.Fingerprint), time.Now().UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save fingerprint: %w", err)
+	}
+	return nil
+}
+
+// ListFingerprints implements ports.FingerprintStore.
+func (a *Adapter) ListFingerprints(ctx context.Context, afterTrackID string, limit int) ([]domain.TrackFingerprint, error) {
+	rows, err := a.q.QueryContext(ctx, `
+		SELECT track_id, recording_id, fingerprint FROM track_fingerprints
+		WHERE track_id > ? ORDER BY track_id LIMIT ?`, afterTrackID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	fps := []domain.TrackFingerprint{}
+	for rows.Next() {
+		var fp domain.TrackFingerprint
+		var data []byte
+		if err := rows.Scan(&fp.TrackID, &fp.RecordingID, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan fingerprint: %w", err)
+		}
+		if fp.Fingerprint, err = fingerprint.Decode(data); err != nil {
+			return nil, fmt.Errorf("failed to decode fingerprint for %s: %w", fp.TrackID, err)
+		}
+		fps = append(fps, fp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate fingerprints: %w", err)
+	}
+	return fps, nil
+}
+
+// RecordingIDs implements ports.RecordingIndex. IDs are looked up in
+// batches of bulkBatchSize to stay below SQLite's bound-parameter limit.
+func (a *Adapter) RecordingIDs(ctx context.Context, trackIDs []string) (map[string]string, error) {
+	out := make(map[string]string, len(trackIDs))
+	for start := 0; start < len(trackIDs); start += bulkBatchSize {
+		batch := trackIDs[start:min(start+bulkBatchSize, len(trackIDs))]
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", ")
+		if err := a.scanRecordings(ctx, out,
+			"SELECT track_id, recording_id FROM track_fingerprints WHERE track_id IN ("+placeholders+")", args...); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (a *Adapter) scanRecordings(ctx context.Context, out map[string]string, query string, args ...any) error {
+	rows, err := a.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to look up recordings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var trackID, recordingID string
+		if err := rows.Scan(&trackID, &recordingID); err != nil {
+			return fmt.Errorf("failed to scan recording: %w", err)
+		}
+		out[trackID] = recordingID
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate recordings: %w", err)
+	}
+	return nil
+}
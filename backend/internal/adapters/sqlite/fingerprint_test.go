@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Fingerprints(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	tracks := makeTracks(3)
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	fps := []domain.TrackFingerprint{
+		{TrackID: tracks[0].ID, RecordingID: tracks[0].ID, Fingerprint: []uint32{1, 2, 3}},
+		{TrackID: tracks[1].ID, RecordingID: tracks[0].ID, Fingerprint: []uint32{1, 2, 4}},
+		{TrackID: tracks[2].ID, RecordingID: tracks[2].ID, Fingerprint: []uint32{9}},
+	}
+	for _, fp := range fps {
+		if err := a.SaveFingerprint(ctx, fp); err != nil {
+			t.Fatalf("save fingerprint: %v", err)
+		}
+	}
+	// Re-fingerprinting replaces the stored row.
+	fps[2].Fingerprint = []uint32{7, 8}
+	if err := a.SaveFingerprint(ctx, fps[2]); err != nil {
+		t.Fatalf("save fingerprint: %v", err)
+	}
+
+	first, err := a.ListFingerprints(ctx, "", 2)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	rest, err := a.ListFingerprints(ctx, first[len(first)-1].TrackID, 2)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if got := append(first, rest...); !reflect.DeepEqual(got, fps) {
+		t.Fatalf("expected %+v, got %+v", fps, got)
+	}
+
+	recordings, err := a.RecordingIDs(ctx, []string{tracks[0].ID, tracks[1].ID, "unknown"})
+	if err != nil {
+		t.Fatalf("recording ids: %v", err)
+	}
+	want := map[string]string{tracks[0].ID: tracks[0].ID, tracks[1].ID: tracks[0].ID}
+	if !reflect.DeepEqual(recordings, want) {
+		t.Fatalf("expected %v, got %v", want, recordings)
+	}
+
+	// Orphan the last track; cleaning it up drops its fingerprint too.
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks[:2]}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := a.DeleteOrphanedTracks(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("delete orphaned tracks: %v", err)
+	}
+	remaining, err := a.ListFingerprints(ctx, "", 10)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("expected orphaned fingerprint to be removed, got %+v", remaining)
+	}
+}
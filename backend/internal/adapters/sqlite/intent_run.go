@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// SaveIntentRun implements ports.IntentRunStore. Structured fields are stored
+// as JSON since runs are only ever read back whole.
+func (a *Adapter) SaveIntentRun(ctx context.Context, run domain.IntentRun) error {
+	fields := []any{run.Intent, run.Candidates, run.Existing, run.Added, run.Scoring}
+	encoded := make([]any, len(fields))
+	for i, f := range fields {
+		b, err := json.Marshal(f)
+		if err != nil {
+			return fmt.Errorf("failed to encode intent run: %w", err)
+		}
+		encoded[i] = string(b)
+	}
+	_, err := a.q.ExecContext(ctx, `
+		INSERT INTO intent_runs (id, playlist_id, message, intent, candidates, existing, added, experiment, variant, scoring, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ID, run.PlaylistID, run.Message, encoded[0], encoded[1], encoded[2], encoded[3],
+		run.Experiment, run.Variant, encoded[4], run.CreatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save intent run: %w", err)
+	}
+	return nil
+}
+
+// ListIntentRuns implements ports.IntentRunStore.
+func (a *Adapter) ListIntentRuns(ctx context.Context, limit int) ([]domain.IntentRun, error) {
+	rows, err := a.q.QueryContext(ctx, `
+		SELECT id, playlist_id, message, intent, candidates, existing, added, experiment, variant, scoring, created_at
+		FROM intent_runs ORDER BY seq DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intent runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []domain.IntentRun{}
+	for rows.Next() {
+		var run domain.IntentRun
+		var intent, candidates, existing, added, scoring string
+		var createdAt int64
+		if err := rows.Scan(&run.ID, &run.PlaylistID, &run.Message, &intent, &candidates, &existing, &added, &run.Experiment, &run.Variant, &scoring, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan intent run: %w", err)
+		}
+		for _, f := range []struct {
+			raw string
+			dst any
+		}{{intent, &run.Intent}, {candidates, &run.Candidates}, {existing, &run.Existing}, {added, &run.Added}, {scoring, &run.Scoring}} {
+			if err := json.Unmarshal([]byte(f.raw), f.dst); err != nil {
+				return nil, fmt.Errorf("failed to decode intent run %s: %w", run.ID, err)
+			}
+		}
+		run.CreatedAt = time.Unix(0, createdAt).UTC()
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate intent runs: %w", err)
+	}
+	return runs, nil
+}
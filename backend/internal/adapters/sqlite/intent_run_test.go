@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_IntentRuns(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	ctx := context.Background()
+
+	intent := domain.IntentObject{IntentType: "CREATE"}
+	intent.VibeConstraints.Energy = &domain.VibeConstraint{Min: 0.1, Max: 0.4}
+	for _, id := range []string{"r1", "r2"} {
+		run := domain.IntentRun{
+			ID:         id,
+			PlaylistID: "pl-1",
+			Message:    "calm please",
+			Intent:     intent,
+			Candidates: []domain.Track{{ID: "t1", Features: domain.AudioFeatures{Energy: 0.3}}},
+			Existing:   []string{},
+			Added:      []string{"t1"},
+			Experiment: "thresholds",
+			Variant:    "strict",
+			Scoring:    domain.ScoringConfig{RankByTarget: true, Threshold: 0.2},
+			CreatedAt:  time.Now(),
+		}
+		if err := a.SaveIntentRun(ctx, run); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	runs, err := a.ListIntentRuns(ctx, 1)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(runs) != 1 || runs[0].ID != "r2" {
+		t.Fatalf("expected newest run, got %+v", runs)
+	}
+	got := runs[0]
+	if got.Intent.VibeConstraints.Energy == nil || got.Intent.VibeConstraints.Energy.Max != 0.4 {
+		t.Fatalf("intent not round-tripped: %+v", got.Intent)
+	}
+	if len(got.Candidates) != 1 || got.Candidates[0].Features.Energy != 0.3 || len(got.Added) != 1 {
+		t.Fatalf("run not round-tripped: %+v", got)
+	}
+	if got.Experiment != "thresholds" || got.Variant != "strict" || !got.Scoring.RankByTarget || got.Scoring.Threshold != 0.2 {
+		t.Fatalf("experiment assignment not round-tripped: %+v", got)
+	}
+}
@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+const jobColumns = "id, track_id, preview_url, state, attempts, last_error, run_at, created_at, updated_at, trace_parent"
+
+func scanJob(row interface{ Scan(...any) error }) (domain.Job, error) {
+	var job domain.Job
+	var state string
+	var runAt, createdAt, updatedAt int64
+	if err := row.Scan(&job.ID, &job.TrackID, &job.PreviewURL, &state, &job.Attempts, &job.LastError, &runAt, &createdAt, &updatedAt, &job.TraceParent); err != nil {
+		return domain.Job{}, err
+	}
+	job.State = domain.JobState(state)
+	job.RunAt = time.UnixMilli(runAt).UTC()
+	job.CreatedAt = time.UnixMilli(createdAt).UTC()
+	job.UpdatedAt = time.UnixMilli(updatedAt).UTC()
+	return job, nil
+}
+
+// EnqueueJob implements ports.JobQueue.
+func (a *Adapter) EnqueueJob(ctx context.Context, queued domain.Job) (domain.Job, error) {
+	scope, err := a.begin(ctx)
+	if err != nil {
+		return domain.Job{}, err
+	}
+	defer scope.rollback()
+	tx := scope.tx
+
+	job, err := scanJob(tx.QueryRowContext(ctx, `
+		SELECT `+jobColumns+` FROM jobs
+		WHERE track_id = ? AND state IN ('queued', 'running')
+		ORDER BY created_at DESC LIMIT 1`, queued.TrackID))
+	if err == nil {
+		return job, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return domain.Job{}, fmt.Errorf("failed to look up job for %s: %w", queued.TrackID, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	job = domain.Job{
+		ID:          uuid.New().String(),
+		TrackID:     queued.TrackID,
+		PreviewURL:  queued.PreviewURL,
+		State:       domain.JobQueued,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		TraceParent: queued.TraceParent,
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO jobs (id, track_id, preview_url, state, run_at, created_at, updated_at, trace_parent)
+		VALUES (?, ?, ?, 'queued', ?, ?, ?, ?)`,
+		job.ID, job.TrackID, job.PreviewURL, now.UnixMilli(), now.UnixMilli(), now.UnixMilli(), job.TraceParent); err != nil {
+		return domain.Job{}, fmt.Errorf("failed to enqueue job for %s: %w", job.TrackID, err)
+	}
+	if err := scope.commit(); err != nil {
+		return domain.Job{}, fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return job, nil
+}
+
+// ClaimJob implements ports.JobQueue. The job is selected and marked running
+// in a single statement, so two instances never claim the same job.
+func (a *Adapter) ClaimJob(ctx context.Context, owner string, lease time.Duration) (domain.Job, bool, error) {
+	now := time.Now()
+	job, err := scanJob(a.q.QueryRowContext(ctx, `
+		UPDATE jobs SET state = 'running', attempts = attempts + 1, claimed_by = ?, lease_until = ?, updated_at = ?
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (state = 'queued' AND run_at <= ?) OR (state = 'running' AND lease_until <= ?)
+			ORDER BY run_at, created_at
+			LIMIT 1
+		)
+		RETURNING `+jobColumns,
+		owner, now.Add(lease).UnixMilli(), now.UnixMilli(), now.UnixMilli(), now.UnixMilli(),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Job{}, false, nil
+	}
+	if err != nil {
+		return domain.Job{}, false, fmt.Errorf("failed to claim job: %w", err)
+	}
+	return job, true, nil
+}
+
+// CompleteJob implements ports.JobQueue.
+func (a *Adapter) CompleteJob(ctx context.Context, id, owner string) error {
+	res, err := a.q.ExecContext(ctx, `
+		UPDATE jobs SET state = 'done', claimed_by = '', lease_until = 0, updated_at = ?
+		WHERE id = ? AND state = 'running' AND claimed_by = ?`, time.Now().UnixMilli(), id, owner)
+	if err != nil {
+		return fmt.Errorf("failed to complete job %s: %w", id, err)
+	}
+	return leaseHeld(res, id)
+}
+
+// FailJob implements ports.JobQueue.
+func (a *Adapter) FailJob(ctx context.Context, id, owner, cause string, retryAt time.Time) error {
+	state, runAt := "failed", time.Now()
+	if !retryAt.IsZero() {
+		state, runAt = "queued", retryAt
+	}
+	res, err := a.q.ExecContext(ctx, `
+		UPDATE jobs SET state = ?, last_error = ?, run_at = ?, claimed_by = '', lease_until = 0, updated_at = ?
+		WHERE id = ? AND state = 'running' AND claimed_by = ?`, state, cause, runAt.UnixMilli(), time.Now().UnixMilli(), id, owner)
+	if err != nil {
+		return fmt.Errorf("failed to record failure of job %s: %w", id, err)
+	}
+	return leaseHeld(res, id)
+}
+
+// leaseHeld turns an update of job id that matched no row, because another
+// owner claimed the job since, into domain.ErrJobLeaseLost.
+func leaseHeld(res sql.Result, id string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to settle job %s: %w", id, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("job %s: %w", id, domain.ErrJobLeaseLost)
+	}
+	return nil
+}
+
+// GetJob implements ports.JobQueue.
+func (a *Adapter) GetJob(ctx context.Context, id string) (domain.Job, error) {
+	job, err := scanJob(a.q.QueryRowContext(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Job{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Job{}, fmt.Errorf("failed to load job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+// PlaylistJobs implements ports.JobQueue.
+func (a *Adapter) PlaylistJobs(ctx context.Context, playlistID string) ([]domain.Job, error) {
+	rows, err := a.q.QueryContext(ctx, `
+		SELECT j.id, j.track_id, j.preview_url, j.state, j.attempts, j.last_error, j.run_at, j.created_at, j.updated_at, j.trace_parent
+		FROM playlist_tracks pt
+		JOIN jobs j ON j.id = (
+			SELECT id FROM jobs WHERE track_id = pt.track_id ORDER BY created_at DESC, rowid DESC LIMIT 1
+		)
+		WHERE pt.playlist_id = ?
+		ORDER BY pt.position`, playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load playlist jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []domain.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load playlist jobs: %w", err)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,176 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_JobQueue(t *testing.T) {
+	ctx := context.Background()
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+
+	claim := func(owner string, lease time.Duration) (domain.Job, bool) {
+		t.Helper()
+		job, ok, err := a.ClaimJob(ctx, owner, lease)
+		if err != nil {
+			t.Fatalf("claim: %v", err)
+		}
+		return job, ok
+	}
+
+	traceParent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	queued, err := a.EnqueueJob(ctx, domain.Job{TrackID: "t1", PreviewURL: "https://p.scdn.co/t1.mp3", TraceParent: traceParent})
+	if err != nil || queued.State != domain.JobQueued {
+		t.Fatalf("enqueue: %+v (%v)", queued, err)
+	}
+	if dup, err := a.EnqueueJob(ctx, domain.Job{TrackID: "t1", PreviewURL: "https://p.scdn.co/t1.mp3"}); err != nil || dup.ID != queued.ID {
+		t.Fatalf("expected the queued job back for a duplicate submission, got %+v (%v)", dup, err)
+	}
+	job, ok := claim("instance-a", time.Minute)
+	if !ok || job.ID != queued.ID || job.TrackID != "t1" || job.PreviewURL != "https://p.scdn.co/t1.mp3" || job.State != domain.JobRunning || job.Attempts != 1 || job.TraceParent != traceParent {
+		t.Fatalf("expected running first attempt for t1, got ok=%v %+v", ok, job)
+	}
+	if _, ok := claim("instance-b", time.Minute); ok {
+		t.Fatal("expected duplicate submission and running job not to be claimable")
+	}
+
+	// A retry is claimable once it comes due.
+	if err := a.FailJob(ctx, job.ID, "instance-a", "analysis failed: timeout", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	if _, ok := claim("instance-a", time.Minute); ok {
+		t.Fatal("expected retry not to be claimable before it is due")
+	}
+	// Bring the retry forward rather than wait an hour for it.
+	if _, err := a.q.ExecContext(ctx, "UPDATE jobs SET run_at = ? WHERE id = ?", time.Now().Add(-time.Second).UnixMilli(), job.ID); err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	retry, ok := claim("instance-a", -time.Second)
+	if !ok || retry.ID != job.ID || retry.Attempts != 2 || retry.LastError != "analysis failed: timeout" {
+		t.Fatalf("expected second attempt with last error, got ok=%v %+v", ok, retry)
+	}
+
+	// The lease above has already lapsed, as if instance-a died.
+	taken, ok := claim("instance-b", time.Minute)
+	if !ok || taken.ID != job.ID || taken.Attempts != 3 {
+		t.Fatalf("expected instance-b to take over the lapsed job, got ok=%v %+v", ok, taken)
+	}
+	if err := a.FailJob(ctx, job.ID, "instance-b", "analysis failed: bad mp3", time.Time{}); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	if _, ok := claim("instance-b", time.Minute); ok {
+		t.Fatal("expected failed job not to be claimable")
+	}
+
+	// A track whose job failed for good can be submitted again.
+	if _, err := a.EnqueueJob(ctx, domain.Job{TrackID: "t1"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	again, ok := claim("instance-a", time.Minute)
+	if !ok || again.ID == job.ID || again.Attempts != 1 {
+		t.Fatalf("expected a new job for t1, got ok=%v %+v", ok, again)
+	}
+	if err := a.CompleteJob(ctx, again.ID, "instance-a"); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if _, ok := claim("instance-a", -time.Second); ok {
+		t.Fatal("expected done job not to be claimable")
+	}
+	if got, err := a.GetJob(ctx, again.ID); err != nil || got.State != domain.JobDone {
+		t.Fatalf("expected done job, got %+v (%v)", got, err)
+	}
+	if _, err := a.GetJob(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAdapter_JobQueue_LostLease(t *testing.T) {
+	ctx := context.Background()
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+
+	if _, err := a.EnqueueJob(ctx, domain.Job{TrackID: "t1"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	// instance-a stalls past its lease and instance-b takes the job over.
+	job, ok, err := a.ClaimJob(ctx, "instance-a", -time.Second)
+	if err != nil || !ok {
+		t.Fatalf("claim: %v, %v", ok, err)
+	}
+	if _, ok, err := a.ClaimJob(ctx, "instance-b", time.Minute); err != nil || !ok {
+		t.Fatalf("expected instance-b to take over, got %v, %v", ok, err)
+	}
+
+	if err := a.CompleteJob(ctx, job.ID, "instance-a"); !errors.Is(err, domain.ErrJobLeaseLost) {
+		t.Fatalf("expected ErrJobLeaseLost completing, got %v", err)
+	}
+	if err := a.FailJob(ctx, job.ID, "instance-a", "analysis failed: timeout", time.Time{}); !errors.Is(err, domain.ErrJobLeaseLost) {
+		t.Fatalf("expected ErrJobLeaseLost failing, got %v", err)
+	}
+	if got, err := a.GetJob(ctx, job.ID); err != nil || got.State != domain.JobRunning || got.LastError != "" {
+		t.Fatalf("expected instance-b's run untouched, got %+v (%v)", got, err)
+	}
+
+	if err := a.CompleteJob(ctx, job.ID, "instance-b"); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	// Settling twice finds the lease already released.
+	if err := a.CompleteJob(ctx, job.ID, "instance-b"); !errors.Is(err, domain.ErrJobLeaseLost) {
+		t.Fatalf("expected ErrJobLeaseLost for a settled job, got %v", err)
+	}
+}
+
+func TestAdapter_PlaylistJobs(t *testing.T) {
+	ctx := context.Background()
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Jobs", Tracks: []domain.Track{
+		{ID: "t1", Title: "One", Artist: "A"},
+		{ID: "t2", Title: "Two", Artist: "B"},
+		{ID: "t3", Title: "Three", Artist: "C"},
+	}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	first, err := a.EnqueueJob(ctx, domain.Job{TrackID: "t2"})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if _, _, err := a.ClaimJob(ctx, "instance-a", time.Minute); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if err := a.FailJob(ctx, first.ID, "instance-a", "no preview", time.Time{}); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	// Only the newest job of a track is reported.
+	retry, err := a.EnqueueJob(ctx, domain.Job{TrackID: "t2"})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	other, err := a.EnqueueJob(ctx, domain.Job{TrackID: "t1"})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	jobs, err := a.PlaylistJobs(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("playlist jobs: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != other.ID || jobs[1].ID != retry.ID || jobs[1].State != domain.JobQueued {
+		t.Fatalf("expected t1's job then t2's retry, got %+v", jobs)
+	}
+}
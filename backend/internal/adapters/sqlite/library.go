@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// featureColumns maps the features a domain.FeatureRange may bound to
+// their tracks columns.
+var featureColumns = map[string]string{
+	"danceability":     "t.danceability",
+	"energy":           "t.energy",
+	"valence":          "t.valence",
+	"tempo":            "t.tempo",
+	"instrumentalness": "t.instrumentalness",
+	"acousticness":     "t.acousticness",
+}
+
+// ListTracks implements ports.TrackLibrary. Tracks not yet analyzed have
+// no features, so any feature range leaves them out.
+func (a *Adapter) ListTracks(ctx context.Context, filter domain.TrackFilter) ([]domain.Track, int, error) {
+	where := []string{"t.item_type = 'track'"}
+	var args []any
+	if filter.Artist != "" {
+		where = append(where, `t.artist LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(filter.Artist)+"%")
+	}
+	for _, r := range filter.Features {
+		column, ok := featureColumns[r.Feature]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: unknown feature %q", domain.ErrInvalidTrackFilter, r.Feature)
+		}
+		where = append(where, column+" IS NOT NULL")
+		if r.Min != nil {
+			where = append(where, column+" >= ?")
+			args = append(args, *r.Min)
+		}
+		if r.Max != nil {
+			where = append(where, column+" <= ?")
+			args = append(args, *r.Max)
+		}
+	}
+	clause := " WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	if err := a.q.QueryRowContext(ctx, "SELECT COUNT(*) FROM tracks t"+clause, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count tracks: %w", err)
+	}
+
+	rows, err := a.q.QueryContext(ctx, "SELECT "+trackSelect+" FROM tracks t"+clause+`
+		ORDER BY t.artist COLLATE NOCASE, t.title COLLATE NOCASE, t.id
+		LIMIT ? OFFSET ?`, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tracks: %w", err)
+	}
+	defer rows.Close()
+
+	tracks := []domain.Track{}
+	for rows.Next() {
+		track, err := scanTrack(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan track: %w", err)
+		}
+		tracks = append(tracks, track)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate tracks: %w", err)
+	}
+	return tracks, total, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s for an ESCAPE '\' pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
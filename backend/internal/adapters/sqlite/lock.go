@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TryLock implements ports.Locker using an upsert that only takes over a
+// lease when it belongs to the same owner or has already expired.
+func (a *Adapter) TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	res, err := a.db.ExecContext(ctx, `
+		INSERT INTO locks (key, owner, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			owner=excluded.owner,
+			expires_at=excluded.expires_at
+		WHERE locks.owner = excluded.owner OR locks.expires_at <= ?
+	`, key, owner, now.Add(ttl).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	return n == 1, nil
+}
+
+// Unlock implements ports.Locker.
+func (a *Adapter) Unlock(ctx context.Context, key, owner string) error {
+	if _, err := a.db.ExecContext(ctx, "DELETE FROM locks WHERE key = ? AND owner = ?", key, owner); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAdapter_TryLock(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, a *Adapter)
+		owner  string
+		wantOK bool
+	}{
+		{
+			name:   "acquires free lock",
+			setup:  func(t *testing.T, a *Adapter) {},
+			owner:  "instance-a",
+			wantOK: true,
+		},
+		{
+			name: "renews own lock",
+			setup: func(t *testing.T, a *Adapter) {
+				mustLock(t, a, "instance-a", time.Minute)
+			},
+			owner:  "instance-a",
+			wantOK: true,
+		},
+		{
+			name: "rejects lock held by another owner",
+			setup: func(t *testing.T, a *Adapter) {
+				mustLock(t, a, "instance-a", time.Minute)
+			},
+			owner:  "instance-b",
+			wantOK: false,
+		},
+		{
+			name: "takes over expired lock",
+			setup: func(t *testing.T, a *Adapter) {
+				mustLock(t, a, "instance-a", -time.Second)
+			},
+			owner:  "instance-b",
+			wantOK: true,
+		},
+		{
+			name: "acquires after release",
+			setup: func(t *testing.T, a *Adapter) {
+				mustLock(t, a, "instance-a", time.Minute)
+				if err := a.Unlock(context.Background(), "job:1", "instance-a"); err != nil {
+					t.Fatalf("unlock: %v", err)
+				}
+			},
+			owner:  "instance-b",
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAdapter(":memory:")
+			if err != nil {
+				t.Fatalf("new adapter: %v", err)
+			}
+			defer a.Close()
+
+			tt.setup(t, a)
+			ok, err := a.TryLock(context.Background(), "job:1", tt.owner, time.Minute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("TryLock: got %v, want %v", ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func mustLock(t *testing.T, a *Adapter, owner string, ttl time.Duration) {
+	t.Helper()
+	ok, err := a.TryLock(context.Background(), "job:1", owner, ttl)
+	if err != nil || !ok {
+		t.Fatalf("seed lock: ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// GetTrack implements ports.TrackLookup.
+func (a *Adapter) GetTrack(ctx context.Context, trackID string) (domain.Track, error) {
+	row := a.q.QueryRowContext(ctx, "SELECT "+trackSelect+" FROM tracks t WHERE t.id = ?", trackID)
+	track, err := scanTrack(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Track{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Track{}, fmt.Errorf("failed to load track: %w", err)
+	}
+	return track, nil
+}
+
+// SaveLyrics implements ports.LyricsStore.
+func (a *Adapter) SaveLyrics(ctx context.Context, l domain.Lyrics) error {
+	_, err := a.q.ExecContext(ctx, `
+		INSERT INTO track_lyrics (track_id, plain, synced, instrumental, source, sentiment, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(track_id) DO UPDATE SET
+			plain = excluded.plain,
+			synced = excluded.synced,
+			instrumental = excluded.instrumental,
+			source = excluded.source,
+			sentiment = excluded.sentiment,
+			fetched_at = excluded.fetched_at`,
+		l.TrackID, l.Plain, l.Synced, l.Instrumental, l.Source, l.Sentiment, l.FetchedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save lyrics: %w", err)
+	}
+	return nil
+}
+
+// GetLyrics implements ports.LyricsStore.
+func (a *Adapter) GetLyrics(ctx context.Context, trackID string) (domain.Lyrics, error) {
+	row := a.q.QueryRowContext(ctx, `
+		SELECT track_id, plain, synced, instrumental, source, sentiment, fetched_at
+		FROM track_lyrics WHERE track_id = ?`, trackID)
+	var l domain.Lyrics
+	var fetchedAt int64
+	err := row.Scan(&l.TrackID, &l.Plain, &l.Synced, &l.Instrumental, &l.Source, &l.Sentiment, &fetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Lyrics{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Lyrics{}, fmt.Errorf("failed to load lyrics: %w", err)
+	}
+	l.FetchedAt = time.Unix(0, fetchedAt).UTC()
+	return l, nil
+}
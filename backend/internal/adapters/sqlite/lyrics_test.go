@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Lyrics(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	tracks := makeTracks(1)
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	track, err := a.GetTrack(ctx, tracks[0].ID)
+	if err != nil {
+		t.Fatalf("get track: %v", err)
+	}
+	if track.ID != tracks[0].ID || track.Title != tracks[0].Title || track.Artist != tracks[0].Artist {
+		t.Fatalf("expected %+v, got %+v", tracks[0], track)
+	}
+	if _, err := a.GetTrack(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown track, got %v", err)
+	}
+
+	if _, err := a.GetLyrics(ctx, tracks[0].ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before saving, got %v", err)
+	}
+	want := domain.Lyrics{
+		TrackID:   tracks[0].ID,
+		Plain:     "la la",
+		Synced:    "[00:01.00] la la",
+		Source:    "lrclib",
+		Sentiment: 0.8,
+		FetchedAt: time.Unix(1700000000, 0).UTC(),
+	}
+	if err := a.SaveLyrics(ctx, want); err != nil {
+		t.Fatalf("save lyrics: %v", err)
+	}
+	// Re-fetching replaces the stored row.
+	want.Plain = "la la la"
+	if err := a.SaveLyrics(ctx, want); err != nil {
+		t.Fatalf("save lyrics: %v", err)
+	}
+	got, err := a.GetLyrics(ctx, tracks[0].ID)
+	if err != nil {
+		t.Fatalf("get lyrics: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// RecordMood implements ports.MoodStore.
+func (a *Adapter) RecordMood(ctx context.Context, checkIn domain.MoodCheckIn) error {
+	_, err := a.q.ExecContext(ctx, `
+		INSERT INTO mood_checkins (owner, valence, energy, emoji, recorded_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		checkIn.Owner, checkIn.Valence, checkIn.Energy, checkIn.Emoji, checkIn.RecordedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to record mood: %w", err)
+	}
+	return nil
+}
+
+// ListMoods implements ports.MoodStore.
+func (a *Adapter) ListMoods(ctx context.Context, owner string, since time.Time) ([]domain.MoodCheckIn, error) {
+	rows, err := a.q.QueryContext(ctx, `
+		SELECT valence, energy, emoji, recorded_at
+		FROM mood_checkins WHERE owner = ? AND recorded_at >= ?
+		ORDER BY recorded_at DESC, id DESC`, owner, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list moods: %w", err)
+	}
+	defer rows.Close()
+
+	var checkIns []domain.MoodCheckIn
+	for rows.Next() {
+		c := domain.MoodCheckIn{Owner: owner}
+		var recordedAt int64
+		if err := rows.Scan(&c.Valence, &c.Energy, &c.Emoji, &recordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan mood: %w", err)
+		}
+		c.RecordedAt = time.Unix(0, recordedAt).UTC()
+		checkIns = append(checkIns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list moods: %w", err)
+	}
+	return checkIns, nil
+}
@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Moods(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	base := time.Unix(1700000000, 0).UTC()
+	for i, c := range []domain.MoodCheckIn{
+		{Owner: "alice", Valence: 0.2, Energy: 0.3, RecordedAt: base},
+		{Owner: "alice", Valence: 0.8, Energy: 0.6, Emoji: "😀", RecordedAt: base.Add(time.Hour)},
+		{Owner: "bob", Valence: 0.5, Energy: 0.5, RecordedAt: base.Add(time.Hour)},
+		{Owner: "alice", Valence: 0.4, Energy: 0.1, Emoji: "😴", RecordedAt: base.Add(2 * time.Hour)},
+	} {
+		if err := a.RecordMood(ctx, c); err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+	}
+
+	got, err := a.ListMoods(ctx, "alice", base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 2 || got[0].Emoji != "😴" || got[1].Emoji != "😀" || got[1].Owner != "alice" || !got[1].RecordedAt.Equal(base.Add(time.Hour)) {
+		t.Fatalf("expected alice's last two check-ins newest first, got %+v", got)
+	}
+	if got, err := a.ListMoods(ctx, "carol", base); err != nil || len(got) != 0 {
+		t.Fatalf("expected no check-ins for carol, got %+v (%v)", got, err)
+	}
+}
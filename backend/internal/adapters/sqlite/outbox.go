@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// enqueueEvent records an event in the outbox as part of tx, so the event
+// exists if and only if the mutation that caused it is committed.
+func enqueueEvent(ctx context.Context, tx *sql.Tx, eventType, playlistID string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO outbox (id, type, playlist_id, payload, occurred_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, uuid.New().String(), eventType, playlistID, string(body), time.Now().UnixMilli()); err != nil {
+		return fmt.Errorf("failed to enqueue %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// PendingEvents implements ports.Outbox.
+func (a *Adapter) PendingEvents(ctx context.Context, limit int) ([]domain.Event, error) {
+	rows, err := a.db.QueryContext(ctx, `
+		SELECT id, type, playlist_id, payload, occurred_at
+		FROM outbox
+		ORDER BY seq ASC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var event domain.Event
+		var payload string
+		var occurredAt int64
+		if err := rows.Scan(&event.ID, &event.Type, &event.PlaylistID, &payload, &occurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pending event: %w", err)
+		}
+		event.Payload = json.RawMessage(payload)
+		event.OccurredAt = time.UnixMilli(occurredAt).UTC()
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pending events: %w", err)
+	}
+	return events, nil
+}
+
+// MarkPublished implements ports.Outbox. Delivered events are deleted, as
+// nothing reads them again and the outbox would otherwise grow with every
+// mutation.
+func (a *Adapter) MarkPublished(ctx context.Context, eventID string) error {
+	if _, err := a.db.ExecContext(ctx, "DELETE FROM outbox WHERE id = ?", eventID); err != nil {
+		return fmt.Errorf("failed to mark event %s published: %w", eventID, err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Outbox(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutate    func(t *testing.T, a *Adapter)
+		wantTypes []string
+	}{
+		{
+			name: "save records playlist saved",
+			mutate: func(t *testing.T, a *Adapter) {
+				mustSave(t, a)
+			},
+			wantTypes: []string{domain.EventPlaylistSaved},
+		},
+		{
+			name: "add tracks records tracks added",
+			mutate: func(t *testing.T, a *Adapter) {
+				mustSave(t, a)
+				if err := a.AddTracksToPlaylist(context.Background(), "pl-1", []domain.Track{
+					{ID: "t2", Title: "Song Two", Artist: "Artist B"},
+				}); err != nil {
+					t.Fatalf("add tracks: %v", err)
+				}
+			},
+			wantTypes: []string{domain.EventPlaylistSaved, domain.EventTracksAdded},
+		},
+		{
+			name: "playback update records playback updated",
+			mutate: func(t *testing.T, a *Adapter) {
+				mustSave(t, a)
+				if err := a.SavePlayback(context.Background(), domain.PlaybackState{PlaylistID: "pl-1", TrackID: "t1"}); err != nil {
+					t.Fatalf("save playback: %v", err)
+				}
+			},
+			wantTypes: []string{domain.EventPlaylistSaved, domain.EventPlaybackUpdated},
+		},
+		{
+			name: "failed mutation records nothing",
+			mutate: func(t *testing.T, a *Adapter) {
+				if err := a.AddTracksToPlaylist(context.Background(), "missing", []domain.Track{
+					{ID: "t2", Title: "Song Two", Artist: "Artist B"},
+				}); err == nil {
+					t.Fatalf("expected error for missing playlist")
+				}
+			},
+			wantTypes: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAdapter(":memory:")
+			if err != nil {
+				t.Fatalf("new adapter: %v", err)
+			}
+			defer a.Close()
+
+			tt.mutate(t, a)
+
+			ctx := context.Background()
+			events, err := a.PendingEvents(ctx, 10)
+			if err != nil {
+				t.Fatalf("pending events: %v", err)
+			}
+			if len(events) != len(tt.wantTypes) {
+				t.Fatalf("expected %d events, got %d", len(tt.wantTypes), len(events))
+			}
+			for i, event := range events {
+				if event.Type != tt.wantTypes[i] {
+					t.Errorf("event %d: got type %q, want %q", i, event.Type, tt.wantTypes[i])
+				}
+				if event.PlaylistID != "pl-1" {
+					t.Errorf("event %d: got playlist %q, want pl-1", i, event.PlaylistID)
+				}
+				if err := a.MarkPublished(ctx, event.ID); err != nil {
+					t.Fatalf("mark published: %v", err)
+				}
+			}
+
+			remaining, err := a.PendingEvents(ctx, 10)
+			if err != nil {
+				t.Fatalf("pending events: %v", err)
+			}
+			if len(remaining) != 0 {
+				t.Fatalf("expected no pending events after publish, got %d", len(remaining))
+			}
+			var rows int
+			if err := a.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM outbox").Scan(&rows); err != nil {
+				t.Fatalf("count outbox: %v", err)
+			}
+			if rows != 0 {
+				t.Fatalf("expected published events to be deleted, got %d rows", rows)
+			}
+		})
+	}
+}
+
+func TestAdapter_OutboxDropsPublishedAt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overture.db")
+	old, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// The outbox as it was when delivered events were stamped and kept.
+	if _, err := old.Exec(`
+		CREATE TABLE outbox (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			id TEXT NOT NULL UNIQUE,
+			type TEXT NOT NULL,
+			playlist_id TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			occurred_at INTEGER NOT NULL,
+			published_at INTEGER
+		);
+		CREATE INDEX idx_outbox_pending ON outbox (seq) WHERE published_at IS NULL;
+		INSERT INTO outbox (id, type, playlist_id, payload, occurred_at, published_at) VALUES
+			('delivered', 'playlist.saved', 'pl-1', '{}', 1, 2),
+			('pending', 'tracks.added', 'pl-1', '{}', 3, NULL);
+	`); err != nil {
+		t.Fatalf("create old outbox: %v", err)
+	}
+	_ = old.Close()
+
+	a, err := NewAdapter(path)
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	events, err := a.PendingEvents(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("pending events: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "pending" {
+		t.Fatalf("expected only the pending event kept, got %+v", events)
+	}
+	var columns int
+	if err := a.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('outbox') WHERE name = 'published_at'").Scan(&columns); err != nil {
+		t.Fatalf("table info: %v", err)
+	}
+	if columns != 0 {
+		t.Fatal("expected published_at dropped")
+	}
+}
+
+func mustSave(t *testing.T, a *Adapter) {
+	t.Helper()
+	if err := a.Save(context.Background(), domain.Playlist{
+		ID:     "pl-1",
+		Name:   "Test Playlist",
+		Tracks: []domain.Track{{ID: "t1", Title: "Song One", Artist: "Artist A"}},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+}
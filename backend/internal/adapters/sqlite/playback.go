@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// SavePlayback implements ports.PlaybackStore.
+func (a *Adapter) SavePlayback(ctx context.Context, state domain.PlaybackState) error {
+	scope, err := a.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer scope.rollback()
+	tx := scope.tx
+
+	// A play is counted when playback moves to another track; seeks and
+	// pause/resume of the same track are not.
+	var prevTrack string
+	err = tx.QueryRowContext(ctx, "SELECT track_id FROM playback_state WHERE playlist_id = ?", state.PlaylistID).
+		Scan(&prevTrack)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to load playback state: %w", err)
+	}
+	if state.Playing && prevTrack != state.TrackID {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO playlist_plays (playlist_id, track_id, played_at) VALUES (?, ?, ?)`,
+			state.PlaylistID, state.TrackID, state.UpdatedAt.UnixNano()); err != nil {
+			return fmt.Errorf("failed to record play: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO playback_state (playlist_id, track_id, position_ms, playing, device, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(playlist_id) DO UPDATE SET
+			track_id = excluded.track_id,
+			position_ms = excluded.position_ms,
+			playing = excluded.playing,
+			device = excluded.device,
+			updated_at = excluded.updated_at`,
+		state.PlaylistID, state.TrackID, state.PositionMs, state.Playing, state.Device, state.UpdatedAt.UnixNano()); err != nil {
+		return fmt.Errorf("failed to save playback state: %w", err)
+	}
+	if err := enqueueEvent(ctx, tx, domain.EventPlaybackUpdated, state.PlaylistID, state); err != nil {
+		return err
+	}
+
+	if err := scope.commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return nil
+}
+
+// GetPlayback implements ports.PlaybackStore.
+func (a *Adapter) GetPlayback(ctx context.Context, playlistID string) (domain.PlaybackState, error) {
+	row := a.q.QueryRowContext(ctx, `
+		SELECT playlist_id, track_id, position_ms, playing, device, updated_at
+		FROM playback_state WHERE playlist_id = ?`, playlistID)
+	var state domain.PlaybackState
+	var updatedAt int64
+	err := row.Scan(&state.PlaylistID, &state.TrackID, &state.PositionMs, &state.Playing, &state.Device, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PlaybackState{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.PlaybackState{}, fmt.Errorf("failed to load playback state: %w", err)
+	}
+	state.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	return state, nil
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Playback(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	ctx := context.Background()
+	mustSave(t, a)
+
+	if _, err := a.GetPlayback(ctx, "pl-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before playing, got %v", err)
+	}
+
+	want := domain.PlaybackState{
+		PlaylistID: "pl-1",
+		TrackID:    "t1",
+		PositionMs: 42000,
+		Playing:    true,
+		Device:     "web",
+		UpdatedAt:  time.Unix(1700000000, 0).UTC(),
+	}
+	if err := a.SavePlayback(ctx, want); err != nil {
+		t.Fatalf("save playback: %v", err)
+	}
+	got, err := a.GetPlayback(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("get playback: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	// The latest update replaces the state.
+	want.PositionMs, want.Playing, want.Device = 50000, false, "ios"
+	if err := a.SavePlayback(ctx, want); err != nil {
+		t.Fatalf("update playback: %v", err)
+	}
+	if got, err = a.GetPlayback(ctx, "pl-1"); err != nil || got != want {
+		t.Fatalf("expected %+v, got %+v (%v)", want, got, err)
+	}
+}
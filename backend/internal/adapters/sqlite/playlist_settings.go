@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// SavePlaylistSettings implements ports.PlaylistSettingsStore.
+func (a *Adapter) SavePlaylistSettings(ctx context.Context, settings domain.PlaylistSettings) error {
+	_, err := a.q.ExecContext(ctx, `
+		INSERT INTO playlist_settings (playlist_id, max_tracks, allow_explicit, target_duration_ms,
+			locked_ordering, default_market, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(playlist_id) DO UPDATE SET
+			max_tracks = excluded.max_tracks,
+			allow_explicit = excluded.allow_explicit,
+			target_duration_ms = excluded.target_duration_ms,
+			locked_ordering = excluded.locked_ordering,
+			default_market = excluded.default_market,
+			updated_at = excluded.updated_at`,
+		settings.PlaylistID, settings.MaxTracks, settings.AllowExplicit, settings.TargetDurationMs,
+		settings.LockedOrdering, settings.DefaultMarket, settings.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save playlist settings: %w", err)
+	}
+	return nil
+}
+
+// GetPlaylistSettings implements ports.PlaylistSettingsStore.
+func (a *Adapter) GetPlaylistSettings(ctx context.Context, playlistID string) (domain.PlaylistSettings, error) {
+	row := a.q.QueryRowContext(ctx, `
+		SELECT playlist_id, max_tracks, allow_explicit, target_duration_ms, locked_ordering,
+			default_market, updated_at
+		FROM playlist_settings WHERE playlist_id = ?`, playlistID)
+	var s domain.PlaylistSettings
+	var updatedAt int64
+	err := row.Scan(&s.PlaylistID, &s.MaxTracks, &s.AllowExplicit, &s.TargetDurationMs,
+		&s.LockedOrdering, &s.DefaultMarket, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PlaylistSettings{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.PlaylistSettings{}, fmt.Errorf("failed to load playlist settings: %w", err)
+	}
+	s.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	return s, nil
+}
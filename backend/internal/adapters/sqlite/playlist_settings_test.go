@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_PlaylistSettings(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Family"}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	if _, err := a.GetPlaylistSettings(ctx, "pl-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before saving, got %v", err)
+	}
+
+	want := domain.PlaylistSettings{PlaylistID: "pl-1", AllowExplicit: false, UpdatedAt: time.Unix(1700000000, 0).UTC()}
+	if err := a.SavePlaylistSettings(ctx, want); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := a.GetPlaylistSettings(ctx, "pl-1")
+	if err != nil || got != want {
+		t.Fatalf("expected %+v, got %+v (%v)", want, got, err)
+	}
+
+	want.AllowExplicit = true
+	want.MaxTracks, want.TargetDurationMs, want.LockedOrdering, want.DefaultMarket = 25, 3_600_000, true, "GB"
+	if err := a.SavePlaylistSettings(ctx, want); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got, err = a.GetPlaylistSettings(ctx, "pl-1"); err != nil || got != want {
+		t.Fatalf("expected %+v, got %+v (%v)", want, got, err)
+	}
+}
+
+func TestAdapter_SaveExplicitTracks(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	ctx := context.Background()
+
+	tracks := makeTracks(2)
+	tracks[1].Explicit = true
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := a.GetByID(ctx, "pl-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Tracks[0].Explicit || !got.Tracks[1].Explicit {
+		t.Fatalf("expected only the second track explicit, got %+v", got.Tracks)
+	}
+}
@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// SetPreviewURL implements ports.PreviewStore.
+func (a *Adapter) SetPreviewURL(ctx context.Context, trackID, previewURL string) error {
+	res, err := a.q.ExecContext(ctx, "UPDATE tracks SET preview_url = ? WHERE id = ?", previewURL, trackID)
+	if err != nil {
+		return fmt.Errorf("failed to set preview url: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to set preview url: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_SetPreviewURL(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	tracks := makeTracks(1)
+	tracks[0].PreviewURL = ""
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	if err := a.SetPreviewURL(ctx, "missing", "https://cdn.example.com/x.mp3"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown track, got %v", err)
+	}
+	if err := a.SetPreviewURL(ctx, tracks[0].ID, "https://cdn.example.com/a.mp3"); err != nil {
+		t.Fatalf("set preview: %v", err)
+	}
+
+	// Saving the track again without a preview keeps the resolved one.
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks}); err != nil {
+		t.Fatalf("resave: %v", err)
+	}
+	got, err := a.GetTrack(ctx, tracks[0].ID)
+	if err != nil {
+		t.Fatalf("get track: %v", err)
+	}
+	if got.PreviewURL != "https://cdn.example.com/a.mp3" {
+		t.Fatalf("expected resolved preview to survive, got %q", got.PreviewURL)
+	}
+
+	// A preview from the provider replaces it.
+	tracks[0].PreviewURL = "https://p.scdn.co/new.mp3"
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks}); err != nil {
+		t.Fatalf("resave with preview: %v", err)
+	}
+	got, err = a.GetTrack(ctx, tracks[0].ID)
+	if err != nil {
+		t.Fatalf("get track: %v", err)
+	}
+	if got.PreviewURL != tracks[0].PreviewURL {
+		t.Fatalf("expected provider preview, got %q", got.PreviewURL)
+	}
+}
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// Queue items are ordered by position: appends take one past the largest,
+// inserts one before the smallest, so neither renumbers existing rows.
+
+// GetQueue implements ports.QueueStore.
+func (a *Adapter) GetQueue(ctx context.Context, owner string) (domain.Queue, error) {
+	rows, err := a.q.QueryContext(ctx, `
+		SELECT track FROM queue_items WHERE owner = ? ORDER BY position`, owner)
+	if err != nil {
+		return domain.Queue{}, fmt.Errorf("failed to load queue: %w", err)
+	}
+	defer rows.Close()
+
+	q := domain.Queue{Owner: owner, Tracks: []domain.Track{}}
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return domain.Queue{}, fmt.Errorf("failed to scan queue item: %w", err)
+		}
+		var t domain.Track
+		if err := json.Unmarshal([]byte(raw), &t); err != nil {
+			return domain.Queue{}, fmt.Errorf("failed to decode queue item: %w", err)
+		}
+		q.Tracks = append(q.Tracks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Queue{}, fmt.Errorf("failed to load queue: %w", err)
+	}
+	return q, nil
+}
+
+// AppendToQueue implements ports.QueueStore.
+func (a *Adapter) AppendToQueue(ctx context.Context, owner string, tracks []domain.Track) error {
+	scope, err := a.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer scope.rollback()
+	tx := scope.tx
+
+	for _, t := range tracks {
+		b, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Errorf("failed to encode queue item: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO queue_items (owner, position, track)
+			SELECT ?, COALESCE(MAX(position), 0) + 1, ? FROM queue_items WHERE owner = ?`,
+			owner, string(b), owner); err != nil {
+			return fmt.Errorf("failed to append to queue: %w", err)
+		}
+	}
+
+	if err := scope.commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return nil
+}
+
+// InsertNextInQueue implements ports.QueueStore.
+func (a *Adapter) InsertNextInQueue(ctx context.Context, owner string, track domain.Track) error {
+	b, err := json.Marshal(track)
+	if err != nil {
+		return fmt.Errorf("failed to encode queue item: %w", err)
+	}
+	if _, err := a.q.ExecContext(ctx, `
+		INSERT INTO queue_items (owner, position, track)
+		SELECT ?, COALESCE(MIN(position), 0) - 1, ? FROM queue_items WHERE owner = ?`,
+		owner, string(b), owner); err != nil {
+		return fmt.Errorf("failed to insert into queue: %w", err)
+	}
+	return nil
+}
+
+// PopQueue implements ports.QueueStore.
+func (a *Adapter) PopQueue(ctx context.Context, owner string) (domain.Track, error) {
+	var raw string
+	err := a.q.QueryRowContext(ctx, `
+		DELETE FROM queue_items WHERE id = (
+			SELECT id FROM queue_items WHERE owner = ? ORDER BY position LIMIT 1
+		) RETURNING track`, owner).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Track{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Track{}, fmt.Errorf("failed to pop queue: %w", err)
+	}
+	var t domain.Track
+	if err := json.Unmarshal([]byte(raw), &t); err != nil {
+		return domain.Track{}, fmt.Errorf("failed to decode queue item: %w", err)
+	}
+	return t, nil
+}
+
+// ClearQueue implements ports.QueueStore.
+func (a *Adapter) ClearQueue(ctx context.Context, owner string) error {
+	if _, err := a.q.ExecContext(ctx, `DELETE FROM queue_items WHERE owner = ?`, owner); err != nil {
+		return fmt.Errorf("failed to clear queue: %w", err)
+	}
+	return nil
+}
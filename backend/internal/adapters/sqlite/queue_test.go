@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Queue(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	q, err := a.GetQueue(ctx, "alice")
+	if err != nil || len(q.Tracks) != 0 {
+		t.Fatalf("expected empty queue, got %+v (%v)", q, err)
+	}
+	if _, err := a.PopQueue(ctx, "alice"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound popping an empty queue, got %v", err)
+	}
+
+	if err := a.AppendToQueue(ctx, "alice", []domain.Track{{ID: "t1", Title: "One"}, {ID: "t2"}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := a.InsertNextInQueue(ctx, "alice", domain.Track{ID: "t0"}); err != nil {
+		t.Fatalf("insert next: %v", err)
+	}
+	if err := a.AppendToQueue(ctx, "bob", []domain.Track{{ID: "b1"}}); err != nil {
+		t.Fatalf("append other owner: %v", err)
+	}
+
+	q, err = a.GetQueue(ctx, "alice")
+	if err != nil {
+		t.Fatalf("get queue: %v", err)
+	}
+	if ids := queueIDs(q); ids != "t0,t1,t2" {
+		t.Fatalf("expected t0,t1,t2, got %s", ids)
+	}
+	if q.Tracks[1].Title != "One" {
+		t.Fatalf("expected track metadata to round-trip, got %+v", q.Tracks[1])
+	}
+
+	next, err := a.PopQueue(ctx, "alice")
+	if err != nil || next.ID != "t0" {
+		t.Fatalf("expected to pop t0, got %+v (%v)", next, err)
+	}
+
+	if err := a.ClearQueue(ctx, "alice"); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if q, _ = a.GetQueue(ctx, "alice"); len(q.Tracks) != 0 {
+		t.Fatalf("expected cleared queue, got %+v", q.Tracks)
+	}
+	if q, _ = a.GetQueue(ctx, "bob"); queueIDs(q) != "b1" {
+		t.Fatalf("expected other owner's queue untouched, got %+v", q.Tracks)
+	}
+}
+
+func queueIDs(q domain.Queue) string {
+	ids := ""
+	for i, t := range q.Tracks {
+		if i > 0 {
+			ids += ","
+		}
+		ids += t.ID
+	}
+	return ids
+}
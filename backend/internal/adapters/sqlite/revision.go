@@ -0,0 +1,186 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// writeRevision snapshots the playlist's current tracks as its next
+// revision, drops revisions beyond domain.MaxRevisions and moves the
+// playlist on to its next version.
+func writeRevision(ctx context.Context, tx *sql.Tx, playlistID, reason string, revertedTo int) (domain.PlaylistRevision, error) {
+	trackIDs, err := linkedTrackIDs(ctx, tx, playlistID)
+	if err != nil {
+		return domain.PlaylistRevision{}, err
+	}
+	body, err := json.Marshal(trackIDs)
+	if err != nil {
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to encode revision: %w", err)
+	}
+
+	rev := domain.PlaylistRevision{
+		Reason:     reason,
+		RevertedTo: revertedTo,
+		TrackIDs:   trackIDs,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO playlist_revisions (playlist_id, rev, reason, reverted_to, track_ids, created_at)
+		SELECT ?, COALESCE(MAX(rev), 0) + 1, ?, ?, ?, ? FROM playlist_revisions WHERE playlist_id = ?
+		RETURNING rev`,
+		playlistID, reason, revertedTo, string(body), rev.CreatedAt.UnixNano(), playlistID).Scan(&rev.Rev); err != nil {
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to record revision: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM playlist_revisions WHERE playlist_id = ? AND rev <= ?",
+		playlistID, rev.Rev-domain.MaxRevisions); err != nil {
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to prune revisions: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE playlists SET version = version + 1 WHERE id = ?", playlistID); err != nil {
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to bump playlist version: %w", err)
+	}
+	return rev, nil
+}
+
+// linkedTrackIDs returns the IDs of the playlist's tracks in order.
+func linkedTrackIDs(ctx context.Context, tx *sql.Tx, playlistID string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT track_id FROM playlist_tracks WHERE playlist_id = ? ORDER BY position, added_at, rowid", playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlist tracks: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan playlist track: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list playlist tracks: %w", err)
+	}
+	return ids, nil
+}
+
+// ListRevisions implements ports.RevisionStore.
+func (a *Adapter) ListRevisions(ctx context.Context, playlistID string, limit int) ([]domain.PlaylistRevision, error) {
+	rows, err := a.q.QueryContext(ctx, `
+		SELECT rev, reason, reverted_to, track_ids, created_at
+		FROM playlist_revisions WHERE playlist_id = ?
+		ORDER BY rev DESC LIMIT ?`, playlistID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []domain.PlaylistRevision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// RevertPlaylist implements ports.RevisionStore.
+func (a *Adapter) RevertPlaylist(ctx context.Context, playlistID string, rev int) (domain.PlaylistRevision, error) {
+	scope, err := a.begin(ctx)
+	if err != nil {
+		return domain.PlaylistRevision{}, err
+	}
+	defer scope.rollback()
+	tx := scope.tx
+
+	target, err := scanRevision(tx.QueryRowContext(ctx, `
+		SELECT rev, reason, reverted_to, track_ids, created_at
+		FROM playlist_revisions WHERE playlist_id = ? AND rev = ?`, playlistID, rev))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PlaylistRevision{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.PlaylistRevision{}, err
+	}
+	var name string
+	if err := tx.QueryRowContext(ctx, "SELECT name FROM playlists WHERE id = ?", playlistID).Scan(&name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PlaylistRevision{}, domain.ErrNotFound
+		}
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to verify playlist: %w", err)
+	}
+
+	// Unlink the current tracks as Save does, then relink the revision's
+	// in order, skipping any the cleanup job has deleted since.
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE tracks SET unlinked_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT track_id FROM playlist_tracks WHERE playlist_id = ?)
+	`, playlistID); err != nil {
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to mark unlinked tracks: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM playlist_tracks WHERE playlist_id = ?", playlistID); err != nil {
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to clear old tracks: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT OR IGNORE INTO playlist_tracks (playlist_id, track_id, position)
+		SELECT ?, id, ? FROM tracks WHERE id = ?`)
+	if err != nil {
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to prepare revert: %w", err)
+	}
+	defer stmt.Close()
+	position := 0
+	for _, trackID := range target.TrackIDs {
+		res, err := stmt.ExecContext(ctx, playlistID, position, trackID)
+		if err != nil {
+			return domain.PlaylistRevision{}, fmt.Errorf("failed to restore track %s: %w", trackID, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			position++
+		}
+	}
+
+	if err := enqueueEvent(ctx, tx, domain.EventPlaylistSaved, playlistID, domain.PlaylistSavedPayload{
+		Name:       name,
+		TrackCount: position,
+	}); err != nil {
+		return domain.PlaylistRevision{}, err
+	}
+	reverted, err := writeRevision(ctx, tx, playlistID, domain.RevisionRevert, rev)
+	if err != nil {
+		return domain.PlaylistRevision{}, err
+	}
+
+	if err := scope.commit(); err != nil {
+		return domain.PlaylistRevision{}, fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return reverted, nil
+}
+
+// scanRevision scans a playlist_revisions row. sql.ErrNoRows is returned
+// as is.
+func scanRevision(row interface{ Scan(...any) error }) (domain.PlaylistRevision, error) {
+	var rev domain.PlaylistRevision
+	var trackIDs string
+	var createdAt int64
+	if err := row.Scan(&rev.Rev, &rev.Reason, &rev.RevertedTo, &trackIDs, &createdAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PlaylistRevision{}, err
+		}
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to scan revision: %w", err)
+	}
+	if err := json.Unmarshal([]byte(trackIDs), &rev.TrackIDs); err != nil {
+		return domain.PlaylistRevision{}, fmt.Errorf("failed to decode revision: %w", err)
+	}
+	rev.CreatedAt = time.Unix(0, createdAt).UTC()
+	return rev, nil
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_Revisions(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	tracks := makeTracks(4)
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P", Tracks: tracks[:2]}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := a.AddTracksToPlaylist(ctx, "pl-1", tracks[2:]); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	revisions, err := a.ListRevisions(ctx, "pl-1", 10)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Rev != 2 || revisions[0].Reason != domain.RevisionAddTracks || revisions[1].Reason != domain.RevisionSave {
+		t.Fatalf("expected the add then the save, newest first, got %+v", revisions)
+	}
+	if want := []string{"t0000", "t0001", "t0002", "t0003"}; !reflect.DeepEqual(revisions[0].TrackIDs, want) {
+		t.Fatalf("expected the tracks after the add, got %v", revisions[0].TrackIDs)
+	}
+
+	reverted, err := a.RevertPlaylist(ctx, "pl-1", 1)
+	if err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	if reverted.Rev != 3 || reverted.Reason != domain.RevisionRevert || reverted.RevertedTo != 1 || !reflect.DeepEqual(reverted.TrackIDs, []string{"t0000", "t0001"}) {
+		t.Fatalf("unexpected revert revision %+v", reverted)
+	}
+	p, err := a.GetByID(ctx, "pl-1")
+	if err != nil || len(p.Tracks) != 2 || p.Tracks[0].ID != "t0000" || p.Tracks[1].ID != "t0001" {
+		t.Fatalf("expected the first two tracks back, got %+v (%v)", p.Tracks, err)
+	}
+
+	// Tracks the cleanup job deletes meanwhile cannot come back.
+	if _, err := a.DeleteOrphanedTracks(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	reverted, err = a.RevertPlaylist(ctx, "pl-1", 2)
+	if err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	if !reflect.DeepEqual(reverted.TrackIDs, []string{"t0000", "t0001"}) {
+		t.Fatalf("expected deleted tracks to be skipped, got %v", reverted.TrackIDs)
+	}
+
+	if _, err := a.RevertPlaylist(ctx, "pl-1", 99); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown revision, got %v", err)
+	}
+	if _, err := a.RevertPlaylist(ctx, "missing", 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown playlist, got %v", err)
+	}
+}
+
+func TestAdapter_RevisionsPruned(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	for i := 0; i < domain.MaxRevisions+5; i++ {
+		if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "P"}); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+	revisions, err := a.ListRevisions(ctx, "pl-1", 2*domain.MaxRevisions)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(revisions) != domain.MaxRevisions || revisions[0].Rev != domain.MaxRevisions+5 {
+		t.Fatalf("expected the last %d revisions, got %d from rev %d", domain.MaxRevisions, len(revisions), revisions[0].Rev)
+	}
+}
@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// GetIntentSession implements ports.IntentSessionStore.
+func (a *Adapter) GetIntentSession(ctx context.Context, sessionID string, limit int) (domain.IntentSession, error) {
+	session := domain.IntentSession{ID: sessionID}
+	err := a.q.QueryRowContext(ctx, "SELECT playlist_id FROM intent_sessions WHERE id = ?", sessionID).Scan(&session.PlaylistID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.IntentSession{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.IntentSession{}, fmt.Errorf("failed to load intent session: %w", err)
+	}
+
+	rows, err := a.q.QueryContext(ctx, `
+		SELECT message, intent, created_at FROM (
+			SELECT id, message, intent, created_at
+			FROM intent_session_turns WHERE session_id = ?
+			ORDER BY id DESC LIMIT ?
+		) ORDER BY id`, sessionID, limit)
+	if err != nil {
+		return domain.IntentSession{}, fmt.Errorf("failed to list intent turns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var turn domain.IntentTurn
+		var intent string
+		var createdAt int64
+		if err := rows.Scan(&turn.Message, &intent, &createdAt); err != nil {
+			return domain.IntentSession{}, fmt.Errorf("failed to scan intent turn: %w", err)
+		}
+		if err := json.Unmarshal([]byte(intent), &turn.Intent); err != nil {
+			return domain.IntentSession{}, fmt.Errorf("failed to decode intent turn: %w", err)
+		}
+		turn.CreatedAt = time.Unix(0, createdAt).UTC()
+		session.Turns = append(session.Turns, turn)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.IntentSession{}, fmt.Errorf("failed to list intent turns: %w", err)
+	}
+	return session, nil
+}
+
+// AppendIntentTurn implements ports.IntentSessionStore. It returns
+// domain.ErrSessionPlaylist if the session was started on another playlist.
+func (a *Adapter) AppendIntentTurn(ctx context.Context, sessionID, playlistID string, turn domain.IntentTurn) error {
+	intent, err := json.Marshal(turn.Intent)
+	if err != nil {
+		return fmt.Errorf("failed to encode intent turn: %w", err)
+	}
+
+	scope, err := a.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer scope.rollback()
+	tx := scope.tx
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO intent_sessions (id, playlist_id, created_at) VALUES (?, ?, ?)
+		ON CONFLICT(id) DO NOTHING`, sessionID, playlistID, turn.CreatedAt.UnixNano()); err != nil {
+		return fmt.Errorf("failed to start intent session: %w", err)
+	}
+	var owner string
+	if err := tx.QueryRowContext(ctx, "SELECT playlist_id FROM intent_sessions WHERE id = ?", sessionID).Scan(&owner); err != nil {
+		return fmt.Errorf("failed to load intent session: %w", err)
+	}
+	if owner != playlistID {
+		return domain.ErrSessionPlaylist
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO intent_session_turns (session_id, message, intent, created_at)
+		VALUES (?, ?, ?, ?)`, sessionID, turn.Message, string(intent), turn.CreatedAt.UnixNano()); err != nil {
+		return fmt.Errorf("failed to record intent turn: %w", err)
+	}
+
+	if err := scope.commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return nil
+}
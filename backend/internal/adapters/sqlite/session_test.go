@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_IntentSessions(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	if err := a.Save(ctx, domain.Playlist{ID: "p1", Name: "Mix"}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+
+	if _, err := a.GetIntentSession(ctx, "s1", 10); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown session, got %v", err)
+	}
+
+	base := time.Unix(1700000000, 0).UTC()
+	for i, msg := range []string{"chill jazz", "more upbeat", "add some vocals"} {
+		turn := domain.IntentTurn{Message: msg, CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		turn.Intent.IntentType = "turn"
+		turn.Intent.Entities.Genres = []string{msg}
+		if err := a.AppendIntentTurn(ctx, "s1", "p1", turn); err != nil {
+			t.Fatalf("append %d: %v", i, err)
+		}
+	}
+
+	got, err := a.GetIntentSession(ctx, "s1", 2)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.ID != "s1" || got.PlaylistID != "p1" || len(got.Turns) != 2 {
+		t.Fatalf("expected the last two turns of s1 on p1, got %+v", got)
+	}
+	if got.Turns[0].Message != "more upbeat" || got.Turns[1].Message != "add some vocals" {
+		t.Fatalf("expected turns oldest first, got %+v", got.Turns)
+	}
+	if g := got.Turns[1].Intent.Entities.Genres; len(g) != 1 || g[0] != "add some vocals" || !got.Turns[1].CreatedAt.Equal(base.Add(2*time.Minute)) {
+		t.Fatalf("expected the turn's intent and time to round-trip, got %+v", got.Turns[1])
+	}
+
+	err = a.AppendIntentTurn(ctx, "s1", "p2", domain.IntentTurn{Message: "other", CreatedAt: base})
+	if !errors.Is(err, domain.ErrSessionPlaylist) {
+		t.Fatalf("expected ErrSessionPlaylist on another playlist, got %v", err)
+	}
+	if got, _ := a.GetIntentSession(ctx, "s1", 10); len(got.Turns) != 3 {
+		t.Fatalf("expected the rejected turn not to be recorded, got %d turns", len(got.Turns))
+	}
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// SavePendingAuth implements ports.SpotifyAuthStore.
+func (a *Adapter) SavePendingAuth(ctx context.Context, pending domain.PendingAuth) error {
+	cutoff := pending.CreatedAt.Add(-domain.AuthStateTTL).UnixNano()
+	if _, err := a.q.ExecContext(ctx, "DELETE FROM spotify_auth_states WHERE created_at < ?", cutoff); err != nil {
+		return fmt.Errorf("failed to prune pending authorizations: %w", err)
+	}
+	if _, err := a.q.ExecContext(ctx, `
+		INSERT INTO spotify_auth_states (state, owner, verifier, created_at) VALUES (?, ?, ?, ?)`,
+		pending.State, pending.Owner, pending.Verifier, pending.CreatedAt.UnixNano()); err != nil {
+		return fmt.Errorf("failed to save pending authorization: %w", err)
+	}
+	return nil
+}
+
+// TakePendingAuth implements ports.SpotifyAuthStore.
+func (a *Adapter) TakePendingAuth(ctx context.Context, state string) (domain.PendingAuth, error) {
+	pending := domain.PendingAuth{State: state}
+	var createdAt int64
+	err := a.q.QueryRowContext(ctx, `
+		DELETE FROM spotify_auth_states WHERE state = ?
+		RETURNING owner, verifier, created_at`, state).Scan(&pending.Owner, &pending.Verifier, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PendingAuth{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.PendingAuth{}, fmt.Errorf("failed to take pending authorization: %w", err)
+	}
+	pending.CreatedAt = time.Unix(0, createdAt).UTC()
+	return pending, nil
+}
+
+// SaveSpotifyToken implements ports.SpotifyAuthStore.
+func (a *Adapter) SaveSpotifyToken(ctx context.Context, owner string, token domain.OAuthToken) error {
+	if _, err := a.q.ExecContext(ctx, `
+		INSERT INTO spotify_tokens (owner, access_token, refresh_token, expiry, scope) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(owner) DO UPDATE SET
+			access_token = excluded.access_token,
+			refresh_token = excluded.refresh_token,
+			expiry = excluded.expiry,
+			scope = excluded.scope`,
+		owner, token.AccessToken, token.RefreshToken, token.Expiry.UnixNano(), token.Scope); err != nil {
+		return fmt.Errorf("failed to save spotify token: %w", err)
+	}
+	return nil
+}
+
+// GetSpotifyToken implements ports.SpotifyAuthStore.
+func (a *Adapter) GetSpotifyToken(ctx context.Context, owner string) (domain.OAuthToken, error) {
+	var token domain.OAuthToken
+	var expiry int64
+	err := a.q.QueryRowContext(ctx, `
+		SELECT access_token, refresh_token, expiry, scope FROM spotify_tokens WHERE owner = ?`,
+		owner).Scan(&token.AccessToken, &token.RefreshToken, &expiry, &token.Scope)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.OAuthToken{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.OAuthToken{}, fmt.Errorf("failed to load spotify token: %w", err)
+	}
+	token.Expiry = time.Unix(0, expiry).UTC()
+	return token, nil
+}
+
+// LinkSpotifyPlaylist implements ports.SpotifyAuthStore.
+func (a *Adapter) LinkSpotifyPlaylist(ctx context.Context, playlistID, spotifyID string) error {
+	if _, err := a.q.ExecContext(ctx, `
+		INSERT INTO spotify_playlist_links (playlist_id, spotify_id) VALUES (?, ?)
+		ON CONFLICT(playlist_id) DO UPDATE SET spotify_id = excluded.spotify_id`,
+		playlistID, spotifyID); err != nil {
+		return fmt.Errorf("failed to link spotify playlist: %w", err)
+	}
+	return nil
+}
+
+// GetSpotifyPlaylistLink implements ports.SpotifyAuthStore.
+func (a *Adapter) GetSpotifyPlaylistLink(ctx context.Context, playlistID string) (string, error) {
+	var spotifyID string
+	err := a.q.QueryRowContext(ctx, "SELECT spotify_id FROM spotify_playlist_links WHERE playlist_id = ?", playlistID).Scan(&spotifyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load spotify playlist link: %w", err)
+	}
+	return spotifyID, nil
+}
+
+// RecordSpotifySync implements ports.SpotifyAuthStore.
+func (a *Adapter) RecordSpotifySync(ctx context.Context, playlistID, snapshotID string, syncedAt time.Time) error {
+	res, err := a.q.ExecContext(ctx, "UPDATE spotify_playlist_links SET snapshot_id = ?, synced_at = ? WHERE playlist_id = ?",
+		snapshotID, syncedAt.UnixNano(), playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to record spotify sync: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
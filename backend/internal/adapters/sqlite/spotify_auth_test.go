@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_SpotifyAuth(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	ctx := context.Background()
+	base := time.Unix(1700000000, 0).UTC()
+
+	stale := domain.PendingAuth{State: "stale", Owner: "alice", Verifier: "v0", CreatedAt: base.Add(-time.Hour)}
+	fresh := domain.PendingAuth{State: "fresh", Owner: "alice", Verifier: "v1", CreatedAt: base}
+	for _, p := range []domain.PendingAuth{stale, fresh} {
+		if err := a.SavePendingAuth(ctx, p); err != nil {
+			t.Fatalf("save pending %s: %v", p.State, err)
+		}
+	}
+	if _, err := a.TakePendingAuth(ctx, "stale"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected the stale state to be pruned, got %v", err)
+	}
+	got, err := a.TakePendingAuth(ctx, "fresh")
+	if err != nil {
+		t.Fatalf("take pending: %v", err)
+	}
+	if got != fresh {
+		t.Fatalf("pending: got %+v, want %+v", got, fresh)
+	}
+	if _, err := a.TakePendingAuth(ctx, "fresh"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a state to be taken once, got %v", err)
+	}
+
+	if _, err := a.GetSpotifyToken(ctx, "alice"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before connecting, got %v", err)
+	}
+	token := domain.OAuthToken{AccessToken: "a1", RefreshToken: "r1", Expiry: base.Add(time.Hour), Scope: "playlist-modify-private"}
+	if err := a.SaveSpotifyToken(ctx, "alice", token); err != nil {
+		t.Fatalf("save token: %v", err)
+	}
+	token.AccessToken = "a2"
+	if err := a.SaveSpotifyToken(ctx, "alice", token); err != nil {
+		t.Fatalf("replace token: %v", err)
+	}
+	if got, err := a.GetSpotifyToken(ctx, "alice"); err != nil || got != token {
+		t.Fatalf("token: got %+v (%v), want %+v", got, err, token)
+	}
+
+	if err := a.Save(ctx, domain.Playlist{ID: "p1", Name: "Mix"}); err != nil {
+		t.Fatalf("save playlist: %v", err)
+	}
+	if _, err := a.GetSpotifyPlaylistLink(ctx, "p1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before linking, got %v", err)
+	}
+	if err := a.LinkSpotifyPlaylist(ctx, "p1", "sp1"); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if got, err := a.GetSpotifyPlaylistLink(ctx, "p1"); err != nil || got != "sp1" {
+		t.Fatalf("link: got %q (%v), want sp1", got, err)
+	}
+	if err := a.RecordSpotifySync(ctx, "p1", "snap-1", base); err != nil {
+		t.Fatalf("record sync: %v", err)
+	}
+	if err := a.RecordSpotifySync(ctx, "p2", "snap-1", base); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound recording an unlinked playlist, got %v", err)
+	}
+	var snapshot string
+	if err := a.db.QueryRowContext(ctx, "SELECT snapshot_id FROM spotify_playlist_links WHERE playlist_id = ?", "p1").Scan(&snapshot); err != nil || snapshot != "snap-1" {
+		t.Fatalf("snapshot: got %q (%v), want snap-1", snapshot, err)
+	}
+}
@@ -0,0 +1,33 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// Do implements ports.UnitOfWork. fn receives an adapter bound to a single
+// transaction that is committed only if fn returns nil. Nested calls join the
+// outer transaction. The transaction holds the write lock from the start, so
+// concurrent calls run one after another and each sees the writes of those
+// before it.
+func (a *Adapter) Do(ctx context.Context, fn func(ctx context.Context, repo ports.PlaylistRepository) error) error {
+	if a.tx != nil {
+		return fn(ctx, a)
+	}
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(ctx, &Adapter{db: a.db, q: tx, tx: tx}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return nil
+}
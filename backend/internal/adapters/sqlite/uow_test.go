@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+func TestAdapter_Do(t *testing.T) {
+	errAbort := errors.New("abort")
+
+	tests := []struct {
+		name       string
+		fnErr      error
+		wantTracks int
+	}{
+		{name: "commits all writes", fnErr: nil, wantTracks: 2},
+		{name: "rolls back all writes on error", fnErr: errAbort, wantTracks: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAdapter(":memory:")
+			if err != nil {
+				t.Fatalf("new adapter: %v", err)
+			}
+			defer a.Close()
+			// A single connection keeps the in-memory database shared
+			// between the transaction and the assertions below.
+			a.db.SetMaxOpenConns(1)
+
+			ctx := context.Background()
+			if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Test Playlist"}); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+
+			err = a.Do(ctx, func(ctx context.Context, repo ports.PlaylistRepository) error {
+				for _, id := range []string{"t1", "t2"} {
+					if err := repo.AddTracksToPlaylist(ctx, "pl-1", []domain.Track{
+						{ID: id, Title: "Song " + id, Artist: "Artist"},
+					}); err != nil {
+						return err
+					}
+				}
+				return tt.fnErr
+			})
+			if !errors.Is(err, tt.fnErr) {
+				t.Fatalf("Do: got error %v, want %v", err, tt.fnErr)
+			}
+
+			got, err := a.GetByID(ctx, "pl-1")
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if len(got.Tracks) != tt.wantTracks {
+				t.Fatalf("expected %d tracks, got %d", tt.wantTracks, len(got.Tracks))
+			}
+		})
+	}
+}
+
+func TestAdapter_Do_Serializes(t *testing.T) {
+	a, err := NewAdapter(filepath.Join(t.TempDir(), "overture.db"))
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	ctx := context.Background()
+	if err := a.Save(ctx, domain.Playlist{ID: "pl-1", Name: "Test Playlist"}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// Each unit of work adds the track only if it does not find it, as
+	// ProcessIntent does; run together, exactly one of them may add it.
+	const workers = 4
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- a.Do(ctx, func(ctx context.Context, repo ports.PlaylistRepository) error {
+				playlist, err := repo.GetByID(ctx, "pl-1")
+				if err != nil {
+					return err
+				}
+				if len(playlist.Tracks) > 0 {
+					return nil
+				}
+				time.Sleep(20 * time.Millisecond)
+				return repo.AddTracksToPlaylist(ctx, "pl-1", []domain.Track{{ID: "t1", Title: "Song", Artist: "Artist"}})
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+	}
+	var links int
+	if err := a.db.QueryRow("SELECT COUNT(*) FROM playlist_tracks WHERE playlist_id = 'pl-1'").Scan(&links); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if links != 1 {
+		t.Fatalf("expected the track added once, got %d links", links)
+	}
+}
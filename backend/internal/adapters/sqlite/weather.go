@@ -0,0 +1,46 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// SaveWeatherSettings implements ports.WeatherSettingsStore.
+func (a *Adapter) SaveWeatherSettings(ctx context.Context, settings domain.WeatherSettings) error {
+	_, err := a.q.ExecContext(ctx, `
+		INSERT INTO weather_settings (owner, enabled, latitude, longitude, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(owner) DO UPDATE SET
+			enabled = excluded.enabled,
+			latitude = excluded.latitude,
+			longitude = excluded.longitude,
+			updated_at = excluded.updated_at`,
+		settings.Owner, settings.Enabled, settings.Latitude, settings.Longitude, settings.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save weather settings: %w", err)
+	}
+	return nil
+}
+
+// GetWeatherSettings implements ports.WeatherSettingsStore.
+func (a *Adapter) GetWeatherSettings(ctx context.Context, owner string) (domain.WeatherSettings, error) {
+	row := a.q.QueryRowContext(ctx, `
+		SELECT owner, enabled, latitude, longitude, updated_at
+		FROM weather_settings WHERE owner = ?`, owner)
+	var s domain.WeatherSettings
+	var updatedAt int64
+	err := row.Scan(&s.Owner, &s.Enabled, &s.Latitude, &s.Longitude, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.WeatherSettings{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.WeatherSettings{}, fmt.Errorf("failed to load weather settings: %w", err)
+	}
+	s.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	return s, nil
+}
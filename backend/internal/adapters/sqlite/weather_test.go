@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestAdapter_WeatherSettings(t *testing.T) {
+	a, err := NewAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer a.Close()
+	a.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	if _, err := a.GetWeatherSettings(ctx, "alice"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before saving, got %v", err)
+	}
+
+	want := domain.WeatherSettings{Owner: "alice", Enabled: true, Latitude: 47.6062, Longitude: -122.3321, UpdatedAt: time.Unix(1700000000, 0).UTC()}
+	if err := a.SaveWeatherSettings(ctx, want); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := a.GetWeatherSettings(ctx, "alice")
+	if err != nil || got != want {
+		t.Fatalf("expected %+v, got %+v (%v)", want, got, err)
+	}
+
+	want.Enabled = false
+	if err := a.SaveWeatherSettings(ctx, want); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got, err = a.GetWeatherSettings(ctx, "alice"); err != nil || got != want {
+		t.Fatalf("expected %+v, got %+v (%v)", want, got, err)
+	}
+	if _, err := a.GetWeatherSettings(ctx, "bob"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected other owners unaffected, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+// Package synthetic provides a fake track provider for load testing. It
+// generates deterministic tracks with configurable latency and failure rate
+// so throughput can be measured without calling Spotify.
+package synthetic
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// Config tunes the simulated provider.
+type Config struct {
+	// Latency is the mean delay added to every call; Jitter spreads it
+	// uniformly over [Latency-Jitter, Latency+Jitter].
+	Latency time.Duration
+	Jitter  time.Duration
+	// ErrorRate is the probability (0-1) that a call fails.
+	ErrorRate float64
+	// TracksPerArtist is how many top tracks GetArtistTopTracks returns.
+	TracksPerArtist int
+}
+
+// Provider implements ports.SpotifyProvider with generated data. The same
+// title/artist always yields the same track, so repeated requests exercise
+// duplicate handling like real traffic would.
+type Provider struct {
+	cfg Config
+}
+
+// NewProvider returns a Provider. TracksPerArtist defaults to 10.
+func NewProvider(cfg Config) *Provider {
+	if cfg.TracksPerArtist <= 0 {
+		cfg.TracksPerArtist = 10
+	}
+	return &Provider{cfg: cfg}
+}
+
+// GetTrackByMetadata implements ports.SpotifyProvider.
+func (p *Provider) GetTrackByMetadata(ctx context.Context, title, artist string) (domain.Track, error) {
+	if err := p.simulate(ctx); err != nil {
+		return domain.Track{}, err
+	}
+	return generateTrack(title, artist), nil
+}
+
+// GetTrack implements ports.SpotifyProvider.
+func (p *Provider) GetTrack(ctx context.Context, title, artist string) (domain.Track, error) {
+	return p.GetTrackByMetadata(ctx, title, artist)
+}
+
+// GetArtistTopTracks implements ports.SpotifyProvider.
+func (p *Provider) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	if err := p.simulate(ctx); err != nil {
+		return nil, err
+	}
+	tracks := make([]domain.Track, p.cfg.TracksPerArtist)
+	for i := range tracks {
+		tracks[i] = generateTrack(fmt.Sprintf("Track %d", i+1), artistName)
+	}
+	return tracks, nil
+}
+
+// AnalyzePreview stands in for worker.AnalyzePreviewFunc: it takes as long
+// as a provider call and returns stable features for the preview URL.
+func (p *Provider) AnalyzePreview(previewURL string) (domain.AudioFeatures, error) {
+	if err := p.simulate(context.Background()); err != nil {
+		return domain.AudioFeatures{}, err
+	}
+	h := hash(previewURL)
+	return domain.AudioFeatures{
+		Danceability: unit(h, 1),
+		Energy:       unit(h, 0),
+		Valence:      unit(h, 3),
+		Tempo:        60 + 120*unit(h, 4),
+		Acousticness: unit(h, 6),
+	}, nil
+}
+
+// simulate sleeps for the configured latency and then fails with the
+// configured probability.
+func (p *Provider) simulate(ctx context.Context) error {
+	delay := p.cfg.Latency
+	if p.cfg.Jitter > 0 {
+		delay += time.Duration(rand.Int64N(int64(2*p.cfg.Jitter)+1)) - p.cfg.Jitter // #nosec G404 -- load simulation, not security sensitive
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if p.cfg.ErrorRate > 0 && rand.Float64() < p.cfg.ErrorRate { // #nosec G404 -- load simulation, not security sensitive
+		return fmt.Errorf("synthetic: injected provider failure")
+	}
+	return nil
+}
+
+func generateTrack(title, artist string) domain.Track {
+	h := hash(artist + "\x00" + title)
+	id := fmt.Sprintf("syn%016x", h)
+	return domain.Track{
+		ID:         id,
+		Title:      title,
+		Artist:     artist,
+		Album:      artist + " (Synthetic)",
+		PreviewURL: "synthetic://preview/" + id,
+		DurationMs: 120000 + int(h%180000),
+		Features: domain.AudioFeatures{
+			Danceability:     unit(h, 1),
+			Energy:           unit(h, 2),
+			Valence:          unit(h, 3),
+			Tempo:            60 + 120*unit(h, 4),
+			Instrumentalness: unit(h, 5),
+			Acousticness:     unit(h, 6),
+		},
+	}
+}
+
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// unit derives a value in [0, 1) from h; salt selects independent values.
+func unit(h uint64, salt uint64) float64 {
+	x := h ^ (salt * 0x9e3779b97f4a7c15)
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	return float64(x>>11) / float64(1<<53)
+}
@@ -0,0 +1,74 @@
+package synthetic
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "succeeds without faults", cfg: Config{}, wantErr: false},
+		{name: "always fails at error rate 1", cfg: Config{ErrorRate: 1}, wantErr: true},
+		{name: "applies latency", cfg: Config{Latency: 20 * time.Millisecond}, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(tt.cfg)
+			start := time.Now()
+			tracks, err := p.GetArtistTopTracks(context.Background(), "Synth Band")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetArtistTopTracks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed < tt.cfg.Latency {
+				t.Fatalf("returned after %v, want at least %v", elapsed, tt.cfg.Latency)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(tracks) != 10 {
+				t.Fatalf("expected 10 tracks, got %d", len(tracks))
+			}
+			seen := map[string]bool{}
+			for _, tr := range tracks {
+				if seen[tr.ID] {
+					t.Fatalf("duplicate track ID %s", tr.ID)
+				}
+				seen[tr.ID] = true
+				if f := tr.Features; f.Energy < 0 || f.Energy >= 1 || f.Tempo < 60 || f.Tempo > 180 {
+					t.Fatalf("features out of range: %+v", f)
+				}
+			}
+		})
+	}
+}
+
+func TestProvider_Deterministic(t *testing.T) {
+	p := NewProvider(Config{})
+	a, err := p.GetTrack(context.Background(), "Song", "Artist")
+	if err != nil {
+		t.Fatalf("GetTrack() error = %v", err)
+	}
+	b, _ := p.GetTrack(context.Background(), "Song", "Artist")
+	c, _ := p.GetTrack(context.Background(), "Other Song", "Artist")
+	if a.ID != b.ID || a.Features != b.Features {
+		t.Fatalf("same metadata produced different tracks: %+v vs %+v", a, b)
+	}
+	if a.ID == c.ID {
+		t.Fatalf("different metadata produced the same ID %s", a.ID)
+	}
+}
+
+func TestProvider_ContextCanceled(t *testing.T) {
+	p := NewProvider(Config{Latency: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.GetTrack(ctx, "Song", "Artist"); err == nil {
+		t.Fatal("expected context error")
+	}
+}
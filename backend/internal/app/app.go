@@ -0,0 +1,878 @@
+// Package app assembles Overture's adapters, core service, workers and HTTP
+// handler from a Config. The server binary and tests share it; functional
+// options replace individual components, e.g. a fake provider in tests.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/anthropic"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/applemusic"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/blob"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/breaker"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/cache"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/chaos"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/events"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/ical"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/keywords"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/lastfm"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/lrclib"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/musicbrainz"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/ollama"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/openweather"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/previews"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/reporting"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/rest"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/rpc"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/spotify"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/sqlite"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/synthetic"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/core/services"
+	"github.com/ewilliams-labs/overture/backend/internal/flags"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/backend/internal/prompt"
+	"github.com/ewilliams-labs/overture/backend/internal/tracing"
+	"github.com/ewilliams-labs/overture/backend/internal/worker"
+	"github.com/ewilliams-labs/overture/metrics"
+	"google.golang.org/grpc"
+)
+
+// Store is everything the application persists. *sqlite.Adapter implements
+// it.
+type Store interface {
+	ports.PlaylistRepository
+	ports.UnitOfWork
+	ports.Locker
+	ports.Outbox
+	ports.JobQueue
+	ports.Snapshotter
+	ports.OrphanCleaner
+	ports.CaptureStore
+	ports.IntentRunStore
+	ports.FingerprintStore
+	ports.LyricsStore
+	ports.TrackLibrary
+	ports.ArtistStore
+	ports.ArtistEvictor
+	ports.PreviewStore
+	ports.AnalysisBacklog
+	ports.PlaybackStore
+	ports.QueueStore
+	ports.WeatherSettingsStore
+	ports.MoodStore
+	ports.IntentSessionStore
+	ports.RevisionStore
+	ports.PlaylistSettingsStore
+	ports.CooccurrenceStore
+	ports.DiscoveryStore
+	ports.SpotifyAuthStore
+}
+
+// Option replaces a component that New would otherwise build from Config.
+type Option func(*App)
+
+// WithStore uses store instead of opening Config.StorageDriver. The caller
+// keeps ownership: Close does not close it.
+func WithStore(store Store) Option {
+	return func(a *App) { a.store = store }
+}
+
+// WithSpotify uses provider instead of the Spotify (or synthetic) client.
+// Chaos decoration still applies.
+func WithSpotify(provider ports.SpotifyProvider) Option {
+	return func(a *App) { a.spotify = provider }
+}
+
+// WithPodcastProvider uses provider for podcast episodes. Without it,
+// podcasts are served by the Spotify provider when it supports them.
+func WithPodcastProvider(provider ports.PodcastProvider) Option {
+	return func(a *App) { a.podcasts = provider }
+}
+
+// WithAlbumProvider uses provider for whole-album lookups. Without it,
+// albums are served by the Spotify provider when it supports them.
+func WithAlbumProvider(provider ports.AlbumProvider) Option {
+	return func(a *App) { a.albums = provider }
+}
+
+// WithRecommendationProvider uses provider for playlist recommendations.
+// Without it, they are served by the Spotify provider when it supports
+// them.
+func WithRecommendationProvider(provider ports.RecommendationProvider) Option {
+	return func(a *App) { a.recommender = provider }
+}
+
+// WithPlaylistImporter uses importer for Spotify playlist imports. Without
+// it, imports are served by the Spotify provider when it supports them.
+func WithPlaylistImporter(importer ports.PlaylistImporter) Option {
+	return func(a *App) { a.importer = importer }
+}
+
+// WithIntentCompiler uses compiler instead of the Ollama client. Chaos
+// decoration still applies; prompt capture does not.
+func WithIntentCompiler(compiler ports.IntentCompiler) Option {
+	return func(a *App) { a.compiler = compiler }
+}
+
+// WithLyricsProvider uses provider instead of the LRCLib client when
+// Config.Lyrics is enabled.
+func WithLyricsProvider(provider ports.LyricsProvider) Option {
+	return func(a *App) { a.lyrics = provider }
+}
+
+// WithPreviewResolver uses resolver instead of the iTunes/Deezer resolver
+// when Config.PreviewFallback is enabled.
+func WithPreviewResolver(resolver ports.PreviewResolver) Option {
+	return func(a *App) { a.previews = resolver }
+}
+
+// WithPlaybackController uses controller instead of the Spotify Connect
+// client built from Config.SpotifyRefreshToken.
+func WithPlaybackController(controller ports.PlaybackController) Option {
+	return func(a *App) { a.player = controller }
+}
+
+// WithSpotifyAuthorizer uses authorizer instead of the Spotify one built
+// from Config.SpotifyRedirectURL.
+func WithSpotifyAuthorizer(authorizer ports.SpotifyAuthorizer) Option {
+	return func(a *App) { a.authorizer = authorizer }
+}
+
+// WithWeatherProvider uses provider instead of the OpenWeather client built
+// from Config.Weather.
+func WithWeatherProvider(provider ports.WeatherProvider) Option {
+	return func(a *App) { a.weather = provider }
+}
+
+// WithSimilarArtistProvider uses provider instead of the Last.fm client
+// built from Config.LastFM.
+func WithSimilarArtistProvider(provider ports.SimilarArtistProvider) Option {
+	return func(a *App) { a.similar = provider }
+}
+
+// WithCalendarProvider uses provider instead of the iCalendar feed from
+// Config.Focus.
+func WithCalendarProvider(provider ports.CalendarProvider) Option {
+	return func(a *App) { a.calendar = provider }
+}
+
+// WithErrorReporter uses reporter instead of the one from Config.Sentry.
+func WithErrorReporter(reporter ports.ErrorReporter) Option {
+	return func(a *App) { a.reporter = reporter }
+}
+
+// WithBlobStore uses store instead of the one from Config.Blob.
+func WithBlobStore(store ports.BlobStore) Option {
+	return func(a *App) { a.blobs = store }
+}
+
+// WithEventSink relays outbox events to sink instead of the process log.
+func WithEventSink(sink ports.EventSink) Option {
+	return func(a *App) { a.sink = sink }
+}
+
+// WithEventBus uses bus instead of the one from Config.EventBus. The
+// caller keeps ownership: Close does not close it.
+func WithEventBus(bus ports.EventBus) Option {
+	return func(a *App) { a.bus = bus }
+}
+
+// WithLogger makes the adapters, the core service and the worker pool log
+// through l instead of the default logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(a *App) { a.logger = l }
+}
+
+// WithDebugConfig serves cfg, which must already be redacted, at
+// GET /debug/config to admins.
+func WithDebugConfig(cfg any) Option {
+	return func(a *App) { a.debugConfig = cfg }
+}
+
+// WithMiddleware wraps the HTTP handler. The first middleware is outermost.
+func WithMiddleware(mw ...func(http.Handler) http.Handler) Option {
+	return func(a *App) { a.middleware = append(a.middleware, mw...) }
+}
+
+// App is an assembled Overture instance.
+type App struct {
+	Service *services.Orchestrator
+	Pool    *worker.Pool
+	Flags   *flags.Set
+
+	cfg         Config
+	store       Store
+	spotify     ports.SpotifyProvider
+	compiler    ports.IntentCompiler
+	lyrics      ports.LyricsProvider
+	previews    ports.PreviewResolver
+	features    ports.FeatureProvider
+	analyzer    func(previewURL string) (domain.AudioFeatures, error)
+	podcasts    ports.PodcastProvider
+	albums      ports.AlbumProvider
+	recommender ports.RecommendationProvider
+	importer    ports.PlaylistImporter
+	player      ports.PlaybackController
+	authorizer  ports.SpotifyAuthorizer
+	weather     ports.WeatherProvider
+	similar     ports.SimilarArtistProvider
+	calendar    ports.CalendarProvider
+	reporter    ports.ErrorReporter
+	blobs       ports.BlobStore
+	sink        ports.EventSink
+	bus         ports.EventBus
+	middleware  []func(http.Handler) http.Handler
+	logger      *slog.Logger
+
+	debugConfig any
+	prompts     *prompt.Set
+	promptName  string
+
+	handler   http.Handler
+	backups   *worker.Backups
+	cleaner   *worker.Cleaner
+	backfill  *worker.Backfiller
+	cooccur   *worker.CooccurrenceBuilder
+	closeOnce sync.Once
+	started   bool
+	closers   []func() error
+}
+
+// New builds an App from cfg. Components supplied through options are used
+// as-is; everything else is constructed from cfg.
+func New(cfg Config, opts ...Option) (*App, error) {
+	a := &App{cfg: cfg}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.logger == nil {
+		a.logger = slog.Default()
+	}
+	a.identifyOutbound()
+
+	// Set up first so the provider is flushed after everything else closes.
+	shutdown, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
+	a.closers = append(a.closers, func() error { return shutdown(context.Background()) })
+
+	if err := a.buildStore(); err != nil {
+		a.Close()
+		return nil, err
+	}
+	if err := a.buildProviders(); err != nil {
+		a.Close()
+		return nil, err
+	}
+	if a.reporter == nil {
+		reporter, err := newErrorReporter(cfg.Sentry)
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+		a.reporter = reporter
+	}
+	if a.blobs == nil {
+		store, err := newBlobStore(cfg.Blob)
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+		a.blobs = store
+	}
+	if a.sink == nil {
+		a.sink = events.LogSink{Logger: a.logger}
+	}
+	if a.bus == nil {
+		bus, err := newEventBus(cfg.EventBus)
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+		a.bus = bus
+		if c, ok := bus.(interface{ Close() error }); ok {
+			a.closers = append(a.closers, c.Close)
+		}
+	}
+	set, err := flags.New(cfg.Flags)
+	if err != nil {
+		a.Close()
+		return nil, fmt.Errorf("app: %w", err)
+	}
+	a.Flags = set
+
+	svcOpts := []services.Option{
+		services.WithUnitOfWork(a.store),
+		services.WithErrorReporter(a.reporter),
+		services.WithFeatureFlags(a.Flags),
+		services.WithPlayback(a.store),
+		services.WithQueue(a.store),
+		services.WithMood(a.store),
+		services.WithIntentSessions(a.store),
+		services.WithRevisions(a.store),
+		services.WithPlaylistSettings(a.store),
+		services.WithCooccurrence(a.store, a.store),
+		services.WithTrackLibrary(a.store),
+		services.WithDiscovery(a.store),
+		services.WithEvents(a.bus),
+		services.WithLogger(a.logger),
+	}
+	svcOpts = append(svcOpts, services.WithIntentValidation(cfg.IntentRepairs))
+	if cfg.IntentFallback {
+		svcOpts = append(svcOpts, services.WithIntentFallback(keywords.NewCompiler()))
+	}
+	// Recorded runs feed POST /admin/replay and GET /admin/experiments.
+	if cfg.RecordIntentRuns || cfg.Experiment != nil {
+		svcOpts = append(svcOpts, services.WithIntentRuns(a.store))
+	}
+	// Synthetic previews cannot be fetched, so load tests skip fingerprints.
+	fingerprints := cfg.FingerprintPreviews && !cfg.LoadTest
+	if fingerprints {
+		svcOpts = append(svcOpts, services.WithRecordings(a.store))
+	}
+	if a.podcasts != nil {
+		svcOpts = append(svcOpts, services.WithPodcasts(a.podcasts))
+	}
+	if a.albums != nil {
+		svcOpts = append(svcOpts, services.WithAlbums(a.albums))
+	}
+	if a.recommender != nil {
+		svcOpts = append(svcOpts, services.WithRecommendations(a.recommender))
+	}
+	if a.importer != nil {
+		svcOpts = append(svcOpts, services.WithPlaylistImport(a.importer))
+	}
+	if a.player != nil {
+		svcOpts = append(svcOpts, services.WithPlaybackController(a.player))
+	}
+	if a.authorizer != nil {
+		svcOpts = append(svcOpts, services.WithSpotifyAuth(a.authorizer, a.store))
+	}
+	if a.weather != nil {
+		svcOpts = append(svcOpts, services.WithWeather(a.weather, a.store))
+	}
+	if a.similar != nil {
+		svcOpts = append(svcOpts, services.WithSimilarArtists(a.similar))
+	}
+	if a.calendar != nil {
+		svcOpts = append(svcOpts, services.WithFocus(a.calendar, cfg.Focus.Keywords))
+	}
+	// Synthetic tracks have no lyrics worth fetching either.
+	lyrics := cfg.Lyrics.Enabled && !cfg.LoadTest
+	if lyrics {
+		if a.lyrics == nil {
+			a.lyrics = lrclib.NewClient(cfg.Lyrics.URL)
+		}
+		svcOpts = append(svcOpts, services.WithLyrics(a.lyrics, a.store, a.store))
+		if cfg.Lyrics.Valence {
+			svcOpts = append(svcOpts, services.WithLyricValence())
+		}
+	}
+	// Synthetic tracks always carry a preview.
+	previewFallback := cfg.PreviewFallback.Enabled && !cfg.LoadTest
+	if previewFallback {
+		if a.previews == nil {
+			a.previews = previews.NewResolver(previews.Config{
+				ITunesURL: cfg.PreviewFallback.ITunesURL,
+				DeezerURL: cfg.PreviewFallback.DeezerURL,
+			})
+		}
+		svcOpts = append(svcOpts, services.WithPreviewFallback(a.previews, a.store, a.store))
+	}
+	// The backfill looks up features for tracks the catalog had none for.
+	if a.features != nil {
+		svcOpts = append(svcOpts, services.WithFeatureLookup(a.features, a.store))
+	}
+	if cfg.Dayparts.Enabled {
+		presets := cfg.Dayparts.Presets
+		if len(presets) == 0 {
+			presets = domain.DefaultDayparts()
+		}
+		if err := presets.Validate(); err != nil {
+			a.Close()
+			return nil, fmt.Errorf("app: invalid dayparts: %w", err)
+		}
+		loc, err := time.LoadLocation(cfg.Dayparts.Timezone)
+		if err != nil {
+			a.Close()
+			return nil, fmt.Errorf("app: invalid daypart timezone: %w", err)
+		}
+		svcOpts = append(svcOpts, services.WithDayparts(presets, loc))
+	}
+	if cfg.Experiment != nil {
+		if err := cfg.Experiment.Validate(); err != nil {
+			a.Close()
+			return nil, fmt.Errorf("app: invalid experiment: %w", err)
+		}
+		svcOpts = append(svcOpts, services.WithExperiment(*cfg.Experiment))
+	}
+	a.Service = services.NewOrchestrator(a.spotify, a.store, a.compiler, svcOpts...)
+
+	workers, queue := cfg.Workers, cfg.QueueSize
+	if workers < 1 {
+		workers = 1
+	}
+	a.Pool = worker.NewPool(a.store, workers, queue)
+	a.Pool.SetLocker(a.store, a.instanceID())
+	a.Pool.SetJobQueue(a.store, a.instanceID())
+	a.Pool.SetErrorReporter(a.reporter)
+	a.Pool.SetLogger(a.logger)
+	a.Pool.SetEventSink(a.bus)
+	if fingerprints {
+		a.Pool.SetFingerprints(a.store)
+	}
+	if lyrics && cfg.Lyrics.Valence {
+		a.Pool.SetValenceEstimator(a.Service.LyricValence)
+	}
+	if previewFallback {
+		a.Pool.SetPreviewFallback(a.Service.FallbackPreview)
+	}
+	if a.analyzer != nil {
+		a.Pool.SetAnalyzer(a.analyzer)
+	}
+
+	a.handler = a.buildHandler()
+	return a, nil
+}
+
+func (a *App) buildStore() error {
+	if a.store != nil {
+		return nil
+	}
+	switch a.cfg.StorageDriver {
+	case "", "sqlite":
+		path := a.cfg.DatabasePath
+		if path == "" {
+			path = "overture.db"
+		}
+		adapter, err := sqlite.Open(path, a.cfg.SQLite)
+		if err != nil {
+			return fmt.Errorf("app: failed to initialize database: %w", err)
+		}
+		a.store = adapter
+		a.closers = append(a.closers, adapter.Close)
+		return nil
+	case "postgres":
+		return fmt.Errorf("app: postgres driver not yet implemented")
+	default:
+		return fmt.Errorf("app: unknown storage driver: %s", a.cfg.StorageDriver)
+	}
+}
+
+func (a *App) buildProviders() error {
+	cfg := a.cfg
+	// The catalog and user clients share the application's rate limit.
+	var scheduler *spotify.Scheduler
+	if cfg.SpotifyRateLimit > 0 {
+		scheduler = spotify.NewScheduler(cfg.SpotifyRateLimit, cfg.SpotifyRateBurst)
+	}
+	spotifyOpts := []spotify.Option{
+		spotify.WithLogger(a.logger),
+		spotify.WithRetries(cfg.SpotifyMaxRetries, cfg.SpotifyRetryBackoff),
+		spotify.WithMarket(cfg.SpotifyMarket),
+	}
+	if cfg.SpotifyMinConfidence > 0 {
+		spotifyOpts = append(spotifyOpts, spotify.WithMinConfidence(cfg.SpotifyMinConfidence))
+	}
+	if a.spotify == nil {
+		// LoadTest swaps Spotify and preview analysis for generated data so
+		// throughput can be measured without calling the real API.
+		if cfg.LoadTest {
+			synth := synthetic.NewProvider(cfg.Synthetic)
+			a.spotify = synth
+			a.analyzer = synth.AnalyzePreview
+			a.logger.Warn("LOAD_TEST enabled: using the synthetic track provider")
+		} else {
+			if cfg.SpotifyClientID == "" || cfg.SpotifyClientSecret == "" {
+				return fmt.Errorf("app: spotify client ID and secret are required")
+			}
+			client := spotify.NewClient(cfg.SpotifyClientID, cfg.SpotifyClientSecret, spotifyOpts...)
+			if cfg.ArtistCacheTTL > 0 {
+				client.EnableArtistCache(spotify.ArtistCacheConfig{Store: a.store, TTL: cfg.ArtistCacheTTL})
+			}
+			searchCache, err := newSpotifyCache(cfg.SpotifyCache)
+			if err != nil {
+				return err
+			}
+			if searchCache != nil {
+				if c, ok := searchCache.(interface{ Close() error }); ok {
+					a.closers = append(a.closers, c.Close)
+				}
+				client.EnableSearchCache(spotify.SearchCacheConfig{
+					Cache:       searchCache,
+					SearchTTL:   cfg.SpotifyCache.SearchTTL,
+					FeaturesTTL: cfg.SpotifyCache.FeaturesTTL,
+				})
+			}
+			if scheduler != nil {
+				client.EnableScheduler(scheduler)
+			}
+			if cfg.FeatureFallback.Enabled {
+				if a.features == nil {
+					a.features = musicbrainz.NewClient(musicbrainz.Config{
+						MusicBrainzURL:    cfg.FeatureFallback.MusicBrainzURL,
+						AcousticBrainzURL: cfg.FeatureFallback.AcousticBrainzURL,
+					})
+				}
+				client.EnableFeatureFallback(a.features)
+			}
+			a.spotify = client
+		}
+	}
+	// Taken before chaos decoration, which only wraps the track methods.
+	if p, ok := a.spotify.(ports.PodcastProvider); ok && a.podcasts == nil {
+		a.podcasts = p
+	}
+	if p, ok := a.spotify.(ports.AlbumProvider); ok && a.albums == nil {
+		a.albums = p
+	}
+	if p, ok := a.spotify.(ports.PlaylistImporter); ok && a.importer == nil {
+		a.importer = p
+	}
+	if p, ok := a.spotify.(ports.RecommendationProvider); ok && a.recommender == nil {
+		a.recommender = p
+	}
+	// Other catalogs fill in and stand in for Spotify's track lookups.
+	if cfg.AppleMusic.Token != "" && !cfg.LoadTest {
+		registry := services.NewProviderRegistry(domain.SourceSpotify, a.spotify, a.logger)
+		registry.Register(domain.SourceAppleMusic, applemusic.NewClient(applemusic.Config{
+			Token:      cfg.AppleMusic.Token,
+			Storefront: cfg.AppleMusic.Storefront,
+			URL:        cfg.AppleMusic.URL,
+		}))
+		a.spotify = registry
+	}
+	// Playback control acts as the user, so it needs their token rather
+	// than the client credentials used for catalog lookups.
+	if a.player == nil && cfg.SpotifyRefreshToken != "" && !cfg.LoadTest {
+		client := spotify.NewUserClient(cfg.SpotifyClientID, cfg.SpotifyClientSecret, cfg.SpotifyRefreshToken, spotifyOpts...)
+		if scheduler != nil {
+			client.EnableScheduler(scheduler)
+		}
+		a.player = client
+	}
+	// Mirroring playlists writes to each user's library with the token
+	// they grant through /auth/spotify/login.
+	if a.authorizer == nil && cfg.SpotifyRedirectURL != "" && !cfg.LoadTest {
+		authorizer := spotify.NewAuthorizer(cfg.SpotifyClientID, cfg.SpotifyClientSecret, cfg.SpotifyRedirectURL, spotifyOpts...)
+		if scheduler != nil {
+			authorizer.EnableScheduler(scheduler)
+		}
+		a.authorizer = authorizer
+	}
+	if a.weather == nil && cfg.Weather.APIKey != "" && !cfg.LoadTest {
+		a.weather = openweather.NewClient(cfg.Weather.APIKey, cfg.Weather.URL)
+	}
+	if a.similar == nil && cfg.LastFM.APIKey != "" && !cfg.LoadTest {
+		a.similar = lastfm.NewClient(cfg.LastFM.APIKey, cfg.LastFM.URL)
+	}
+	if a.calendar == nil && cfg.Focus.CalendarURL != "" && !cfg.LoadTest {
+		a.calendar = ical.NewClient(cfg.Focus.CalendarURL)
+	}
+	if a.compiler == nil && cfg.Anthropic.APIKey != "" {
+		a.compiler = anthropic.NewClient(cfg.Anthropic.APIKey, cfg.Anthropic.Model, cfg.Anthropic.URL)
+	}
+	if a.compiler == nil {
+		client := ollama.NewClient(cfg.OllamaHost, ollama.WithLogger(a.logger), ollama.WithModel(cfg.OllamaModel))
+		if cfg.Capture.Enabled {
+			client.EnableCapture(ollama.CaptureConfig{
+				Store:      a.store,
+				MaxAge:     cfg.Capture.MaxAge,
+				MaxEntries: cfg.Capture.MaxEntries,
+			})
+		}
+		a.compiler = client
+	}
+	if err := a.loadPrompts(); err != nil {
+		return err
+	}
+
+	for _, target := range cfg.ChaosTargets {
+		switch target {
+		case "spotify":
+			a.spotify = chaos.WrapSpotify(a.spotify, cfg.Chaos)
+		case "intent":
+			a.compiler = chaos.WrapIntentCompiler(a.compiler, cfg.Chaos)
+		default:
+			return fmt.Errorf("app: unknown chaos target %q", target)
+		}
+	}
+	if len(cfg.ChaosTargets) > 0 {
+		a.logger.Warn("CHAOS enabled", "targets", cfg.ChaosTargets, "latency", cfg.Chaos.Latency, "latency_rate", cfg.Chaos.LatencyRate,
+			"error_rate", cfg.Chaos.ErrorRate, "malformed_rate", cfg.Chaos.MalformedRate)
+	}
+	// Breakers wrap the chaos faults so they can be seen tripping.
+	if cfg.Breaker.Cooldown > 0 {
+		a.spotify = breaker.WrapSpotify(a.spotify, "spotify", cfg.Breaker)
+		a.compiler = breaker.WrapIntentCompiler(a.compiler, "intent compiler", cfg.Breaker)
+	}
+	return nil
+}
+
+// loadPrompts renders the intent compiler's prompt from cfg.PromptDir when
+// the compiler is one of ours, telling it which context Overture weighs in.
+func (a *App) loadPrompts() error {
+	cfg := a.cfg
+	capabilities := []string{"recent moods"}
+	if cfg.Dayparts.Enabled {
+		capabilities = append(capabilities, "the time of day")
+	}
+	if a.weather != nil {
+		capabilities = append(capabilities, "the weather")
+	}
+	if a.calendar != nil {
+		capabilities = append(capabilities, "calendar focus blocks")
+	}
+	if a.similar != nil {
+		capabilities = append(capabilities, "similar artists")
+	}
+	prompts, err := prompt.Load(cfg.PromptDir, prompt.Vars{Genres: cfg.PromptGenres, Capabilities: capabilities})
+	if err != nil {
+		return fmt.Errorf("app: load prompts: %w", err)
+	}
+	switch compiler := a.compiler.(type) {
+	case *anthropic.Client:
+		compiler.EnablePrompts(prompts)
+		a.prompts, a.promptName = prompts, "anthropic"
+	case *ollama.Client:
+		compiler.EnablePrompts(prompts)
+		a.prompts, a.promptName = prompts, "ollama"
+	}
+	return nil
+}
+
+func (a *App) buildHandler() http.Handler {
+	cfg := a.cfg
+	handlerOpts := []rest.Option{
+		rest.WithAdminToken(cfg.AdminToken),
+		rest.WithErrorReporter(a.reporter),
+		rest.WithFlags(a.Flags),
+		rest.WithJobs(a.store),
+		rest.WithEvents(a.bus),
+		rest.WithLogger(a.logger),
+		rest.WithRateLimit(cfg.RateLimit),
+	}
+	if a.debugConfig != nil {
+		handlerOpts = append(handlerOpts, rest.WithDebugConfig(a.debugConfig))
+	}
+	if a.prompts != nil {
+		handlerOpts = append(handlerOpts, rest.WithPrompts(a.prompts, a.promptName))
+	}
+	if cfg.Backups.Enabled {
+		retain := cfg.Backups.Retain
+		if retain < 1 {
+			retain = 7
+		}
+		a.backups = worker.NewBackups(a.store, a.blobs, retain)
+		handlerOpts = append(handlerOpts, rest.WithBackups(a.backups))
+	}
+	// Orphaned tracks are removed once unreferenced for Cleanup.Grace.
+	a.cleaner = worker.NewCleaner(a.store, a.backups, cfg.Cleanup.Grace, cfg.Cleanup.SnapshotMaxAge)
+	handlerOpts = append(handlerOpts, rest.WithCleaner(a.cleaner))
+	a.backfill = worker.NewBackfiller(a.store, a.Pool, cfg.Backfill.Limit)
+	// Reports no preview unless PreviewFallback is enabled.
+	a.backfill.SetPreviewFallback(a.Service.FallbackPreview)
+	// Reports no features unless FeatureFallback is enabled.
+	a.backfill.SetFeatureLookup(a.Service.LookupFeatures)
+	handlerOpts = append(handlerOpts, rest.WithBackfill(a.backfill))
+	a.cooccur = worker.NewCooccurrenceBuilder(a.store, cfg.Cooccurrence.MaxNeighbors)
+	if cfg.Capture.Enabled {
+		handlerOpts = append(handlerOpts, rest.WithCaptures(a.store))
+	}
+	exports := worker.NewExports(a.Service.ExportUserData, a.blobs, a.Pool)
+	handlerOpts = append(handlerOpts, rest.WithExports(exports))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.Handle("/", rest.NewHandler(a.Service, a.Pool, handlerOpts...))
+
+	var h http.Handler = logging.Middleware(tracing.Handler(metrics.InstrumentHandler(mux)))
+	for i := len(a.middleware) - 1; i >= 0; i-- {
+		h = a.middleware[i](h)
+	}
+	return h
+}
+
+// Handler serves the REST API and /metrics.
+func (a *App) Handler() http.Handler {
+	return a.handler
+}
+
+// GRPCServer returns a gRPC server exposing the same core service as
+// Handler, for internal clients. Each call builds a new server.
+func (a *App) GRPCServer() *grpc.Server {
+	return rpc.NewServer(a.Service, a.Pool, rpc.WithErrorReporter(a.reporter), rpc.WithLogger(a.logger)).GRPCServer()
+}
+
+// Start launches the worker pool and the background jobs: leader election,
+// the outbox relay and the scheduler running backups, cleanups, backfills,
+// co-occurrence rebuilds and artist cache eviction. They stop when ctx is
+// canceled; call Close afterwards to drain the pool.
+func (a *App) Start(ctx context.Context) {
+	cfg := a.cfg
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	a.Pool.Start(workers)
+	a.started = true
+
+	// Only the elected leader runs scheduled (singleton) work.
+	elector := worker.NewElector(a.store, "scheduler", a.instanceID(), 30*time.Second)
+	go elector.Run(ctx)
+
+	// Playlist mutations record events in the outbox; the leader relays them.
+	// Relayed events also go to the event bus, which feeds the
+	// /playlists/{id}/events streams of every replica sharing it.
+	relay := worker.NewOutboxRelay(a.store, events.Multi{a.sink, a.bus}, time.Second, elector.IsLeader)
+	go relay.Run(ctx)
+
+	// A zero interval disables a task.
+	sched := worker.NewScheduler(elector.IsLeader)
+	if a.backups != nil {
+		sched.Add(a.backups.Task(cfg.Backups.Interval))
+	}
+	sched.Add(a.cleaner.Task(cfg.Cleanup.Interval, cfg.Cleanup.DryRun))
+	sched.Add(a.backfill.Task(cfg.Backfill.Interval))
+	sched.Add(a.cooccur.Task(cfg.Cooccurrence.Interval))
+	// Artists unused for several TTLs are only a stale fallback; with the
+	// cache disabled, every entry goes.
+	sched.Add(worker.ArtistCacheEviction(a.store, cfg.ArtistCacheEvictInterval, artistCacheMaxAgeTTLs*cfg.ArtistCacheTTL))
+	a.logger.Info("scheduled tasks", "tasks", sched.Tasks())
+	go sched.Run(ctx)
+}
+
+// artistCacheMaxAgeTTLs is how many cache TTLs an artist may go unrefreshed
+// before eviction drops it.
+const artistCacheMaxAgeTTLs = 4
+
+// Close drains the worker pool and closes the resources New opened.
+func (a *App) Close() error {
+	var err error
+	a.closeOnce.Do(func() {
+		if a.started {
+			a.Pool.Stop()
+		}
+		for i := len(a.closers) - 1; i >= 0; i-- {
+			if cerr := a.closers[i](); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	})
+	return err
+}
+
+// identifyOutbound sets how outbound requests identify this instance.
+func (a *App) identifyOutbound() {
+	headers := http.Header{}
+	for name, value := range a.cfg.Outbound.Headers {
+		headers.Set(name, value)
+	}
+	httpx.SetIdentity(httpx.Identity{
+		Service:  "overture",
+		Version:  a.cfg.Outbound.Version,
+		Instance: a.instanceID(),
+		Headers:  headers,
+	})
+}
+
+// instanceID identifies this process when claiming shared work.
+func (a *App) instanceID() string {
+	if a.cfg.InstanceID != "" {
+		return a.cfg.InstanceID
+	}
+	return "overture"
+}
+
+// newEventBus connects to the broker cfg names, if any.
+func newEventBus(cfg EventBusConfig) (ports.EventBus, error) {
+	channel := cfg.Channel
+	if channel == "" {
+		channel = "overture.events"
+	}
+	switch cfg.Driver {
+	case "", "memory":
+		return events.NewBus(), nil
+	case "redis":
+		bus, err := events.NewRedisBus(cfg.URL, channel)
+		if err != nil {
+			return nil, fmt.Errorf("app: failed to initialize event bus: %w", err)
+		}
+		return bus, nil
+	case "nats":
+		bus, err := events.NewNATSBus(cfg.URL, channel)
+		if err != nil {
+			return nil, fmt.Errorf("app: failed to initialize event bus: %w", err)
+		}
+		return bus, nil
+	default:
+		return nil, fmt.Errorf("app: unknown event bus driver: %s", cfg.Driver)
+	}
+}
+
+// newSpotifyCache returns the Spotify search cache cfg names, or nil when
+// caching is off.
+func newSpotifyCache(cfg SpotifyCacheConfig) (ports.Cache, error) {
+	switch cfg.Driver {
+	case "none":
+		return nil, nil
+	case "", "memory":
+		return cache.NewLRU(cfg.Size), nil
+	case "redis":
+		c, err := cache.NewRedis(cfg.URL, "overture:spotify:")
+		if err != nil {
+			return nil, fmt.Errorf("app: failed to initialize spotify cache: %w", err)
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("app: unknown spotify cache driver: %s", cfg.Driver)
+	}
+}
+
+// newErrorReporter sends errors to a Sentry-compatible service when a DSN is
+// configured and discards them otherwise.
+func newErrorReporter(cfg SentryConfig) (ports.ErrorReporter, error) {
+	if cfg.DSN == "" {
+		return reporting.Nop{}, nil
+	}
+	reporter, err := reporting.NewSentry(cfg.DSN, cfg.Environment, cfg.Release)
+	if err != nil {
+		return nil, fmt.Errorf("app: failed to initialize error reporting: %w", err)
+	}
+	return reporter, nil
+}
+
+// newBlobStore selects where artifacts such as snapshots are kept: "local"
+// (default, below Dir) or "s3" (any S3-compatible service, including GCS
+// through its interoperability endpoint).
+func newBlobStore(cfg BlobConfig) (ports.BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		dir := cfg.Dir
+		if dir == "" {
+			dir = "data"
+		}
+		store, err := blob.NewLocalStore(dir)
+		if err != nil {
+			return nil, fmt.Errorf("app: failed to initialize blob store: %w", err)
+		}
+		return store, nil
+	case "s3":
+		store, err := blob.NewS3Store(cfg.S3)
+		if err != nil {
+			return nil, fmt.Errorf("app: failed to initialize blob store: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("app: unknown blob driver: %s", cfg.Driver)
+	}
+}
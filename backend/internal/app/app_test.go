@@ -0,0 +1,181 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/synthetic"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func testConfig(t *testing.T) Config {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.DatabasePath = filepath.Join(t.TempDir(), "app.db")
+	cfg.Blob.Dir = t.TempDir()
+	cfg.Workers = 1
+	cfg.InstanceID = "test"
+	cfg.Cleanup.Interval = 0
+	return cfg
+}
+
+func TestNew_ConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{
+			name:    "Missing Spotify credentials",
+			mutate:  func(c *Config) {},
+			wantErr: "spotify client ID and secret are required",
+		},
+		{
+			name:    "Unknown storage driver",
+			mutate:  func(c *Config) { c.StorageDriver = "mysql" },
+			wantErr: "unknown storage driver",
+		},
+		{
+			name:    "Postgres not implemented",
+			mutate:  func(c *Config) { c.StorageDriver = "postgres" },
+			wantErr: "postgres driver not yet implemented",
+		},
+		{
+			name: "Unknown chaos target",
+			mutate: func(c *Config) {
+				c.LoadTest = true
+				c.ChaosTargets = []string{"database"}
+			},
+			wantErr: "unknown chaos target",
+		},
+		{
+			name: "Unknown feature flag",
+			mutate: func(c *Config) {
+				c.LoadTest = true
+				c.Flags = map[string]bool{"warp_drive": true}
+			},
+			wantErr: "unknown flag",
+		},
+		{
+			name: "Invalid experiment",
+			mutate: func(c *Config) {
+				c.LoadTest = true
+				c.Experiment = &domain.Experiment{Name: "thresholds"}
+			},
+			wantErr: "invalid experiment",
+		},
+		{
+			name: "Invalid dayparts",
+			mutate: func(c *Config) {
+				c.LoadTest = true
+				c.Dayparts = DaypartConfig{Enabled: true, Presets: domain.DaypartPresets{{Name: "late", StartHour: 25}}}
+			},
+			wantErr: "invalid dayparts",
+		},
+		{
+			name: "Unknown daypart timezone",
+			mutate: func(c *Config) {
+				c.LoadTest = true
+				c.Dayparts = DaypartConfig{Enabled: true, Timezone: "Mars/Olympus_Mons"}
+			},
+			wantErr: "invalid daypart timezone",
+		},
+		{
+			name: "Unknown blob driver",
+			mutate: func(c *Config) {
+				c.LoadTest = true
+				c.Blob.Driver = "ftp"
+			},
+			wantErr: "unknown blob driver",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			tt.mutate(&cfg)
+			a, err := New(cfg)
+			if err == nil {
+				a.Close()
+				t.Fatalf("expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApp_Handler(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.AdminToken = "secret"
+	var wrapped bool
+	a, err := New(cfg,
+		WithSpotify(synthetic.NewProvider(synthetic.Config{TracksPerArtist: 3})),
+		WithMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				wrapped = true
+				next.ServeHTTP(w, r)
+			})
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.Start(ctx)
+	defer func() {
+		cancel()
+		if err := a.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}()
+
+	srv := httptest.NewServer(a.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 from /health, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("X-Request-ID") == "" {
+		t.Fatal("expected a request ID on the response")
+	}
+	if !wrapped {
+		t.Fatal("expected middleware to wrap the handler")
+	}
+
+	resp, err = http.Post(srv.URL+"/playlists", "application/json", strings.NewReader(`{"name":"Wired"}`))
+	if err != nil {
+		t.Fatalf("POST /playlists: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 from POST /playlists, got %d", resp.StatusCode)
+	}
+	var created domain.Playlist
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode playlist: %v", err)
+	}
+	if _, err := a.Service.GetPlaylist(context.Background(), created.ID); err != nil {
+		t.Fatalf("expected playlist in the configured store: %v", err)
+	}
+
+	metricsResp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	metricsResp.Body.Close()
+	if metricsResp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 from /metrics, got %d", metricsResp.StatusCode)
+	}
+}
@@ -0,0 +1,340 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/blob"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/breaker"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/chaos"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/ollama"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/rest"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/spotify"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/sqlite"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/synthetic"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/prompt"
+	"github.com/ewilliams-labs/overture/backend/internal/tracing"
+)
+
+// Config describes how to assemble the application. The zero value plus
+// Spotify credentials is a working development setup; DefaultConfig fills
+// in the defaults the server uses.
+type Config struct {
+	// StorageDriver is "sqlite" (default). DatabasePath defaults to
+	// "overture.db".
+	StorageDriver string
+	DatabasePath  string
+	// SQLite tunes the SQLite connections: journal mode, busy timeout,
+	// foreign keys and pool size.
+	SQLite sqlite.Config
+
+	SpotifyClientID     string
+	SpotifyClientSecret string
+	// SpotifyRefreshToken, granted by the user with the
+	// user-modify-playback-state scope, enables playback control on their
+	// Spotify Connect devices.
+	SpotifyRefreshToken string
+	// SpotifyRedirectURL, registered with the Spotify application, lets
+	// users connect their account at /auth/spotify/login so the tracks
+	// added to their playlists are mirrored to their Spotify library.
+	SpotifyRedirectURL string
+	OllamaHost         string
+	// Anthropic replaces Ollama as the intent compiler when its APIKey is
+	// set.
+	Anthropic AnthropicConfig
+	// ArtistCacheTTL is how long Spotify artist lookups are cached in the
+	// store; zero disables the cache.
+	ArtistCacheTTL time.Duration
+	// ArtistCacheEvictInterval is how often artists left unrefreshed for
+	// four TTLs are dropped from the cache; zero disables eviction.
+	ArtistCacheEvictInterval time.Duration
+	// SpotifyCache caches the tracks searches resolve to and their audio
+	// features.
+	SpotifyCache SpotifyCacheConfig
+	// SpotifyRateLimit paces all Spotify requests to this many per second,
+	// with bursts of up to SpotifyRateBurst; zero disables pacing.
+	SpotifyRateLimit float64
+	SpotifyRateBurst int
+	// SpotifyMaxRetries and SpotifyRetryBackoff bound the retries of
+	// Spotify requests that fail with 429 or 5xx.
+	SpotifyMaxRetries   int
+	SpotifyRetryBackoff time.Duration
+	// SpotifyMinConfidence is the lowest match score (0 to 1) a search
+	// result needs to be used.
+	SpotifyMinConfidence float64
+	// SpotifyMarket is the two-letter country code tracks are looked up in
+	// unless the request or the playlist's settings name another.
+	SpotifyMarket string
+	// IntentRepairs is how many times an invalid intent is sent back to
+	// the intent compiler for correction before it is rejected.
+	IntentRepairs int
+	// IntentFallback compiles intents by keyword rules when the intent
+	// compiler fails.
+	IntentFallback bool
+	// OllamaModel is the model the Ollama intent compiler asks for.
+	OllamaModel string
+	// PromptDir holds templates replacing the embedded intent compiler
+	// prompts of the same name; empty uses the embedded ones.
+	PromptDir string
+	// PromptGenres are the genres the prompts tell the compiler it may use.
+	PromptGenres []string
+
+	// LoadTest replaces Spotify and preview analysis with Synthetic.
+	LoadTest  bool
+	Synthetic synthetic.Config
+
+	// Breaker guards Spotify and the intent compiler with circuit breakers;
+	// a zero Cooldown disables them.
+	Breaker breaker.Config
+
+	// ChaosTargets ("spotify", "intent") are wrapped with Chaos faults.
+	ChaosTargets []string
+	Chaos        chaos.Config
+
+	// Flags enables experimental behavior by name; see package flags.
+	Flags map[string]bool
+
+	// FingerprintPreviews fingerprints analyzed previews so the same
+	// recording is deduplicated under different IDs and ISRCs.
+	FingerprintPreviews bool
+
+	Lyrics LyricsConfig
+
+	PreviewFallback PreviewFallbackConfig
+
+	FeatureFallback FeatureFallbackConfig
+
+	Dayparts DaypartConfig
+
+	Weather WeatherConfig
+
+	AppleMusic AppleMusicConfig
+
+	LastFM LastFMConfig
+
+	Focus FocusConfig
+
+	Capture          CaptureConfig
+	RecordIntentRuns bool
+	// Experiment splits intent runs between scoring variants. It implies
+	// RecordIntentRuns, since runs carry the assignment and outcome.
+	Experiment *domain.Experiment
+
+	Workers    int
+	QueueSize  int
+	InstanceID string
+	AdminToken string
+	// RateLimit limits each client's REST requests; zero rates disable it.
+	RateLimit rest.RateLimit
+
+	Blob     BlobConfig
+	Backups  BackupConfig
+	Cleanup  CleanupConfig
+	Backfill BackfillConfig
+	Sentry   SentryConfig
+	Outbound OutboundConfig
+	EventBus EventBusConfig
+	// Tracing exports OpenTelemetry spans when Tracing.Endpoint is set.
+	Tracing tracing.Config
+
+	Cooccurrence CooccurrenceConfig
+}
+
+// LyricsConfig enables lyrics from LRCLib at URL (default the public API).
+// Valence estimates track valence from lyric sentiment, both for analyzed
+// tracks and for intent candidates.
+type LyricsConfig struct {
+	Enabled bool
+	URL     string
+	Valence bool
+}
+
+// PreviewFallbackConfig enables looking up previews on iTunes and Deezer
+// for tracks Spotify has none for, so they can still be analyzed. Empty
+// URLs use the public APIs.
+type PreviewFallbackConfig struct {
+	Enabled   bool
+	ITunesURL string
+	DeezerURL string
+}
+
+// FeatureFallbackConfig enables looking up audio features Spotify has none
+// for on AcousticBrainz, through the recording's ISRC on MusicBrainz,
+// before generating deterministic ones. Empty URLs use the public APIs.
+type FeatureFallbackConfig struct {
+	Enabled           bool
+	MusicBrainzURL    string
+	AcousticBrainzURL string
+}
+
+// DaypartConfig fills the energy and valence an intent leaves open from the
+// time of day in Timezone (an IANA name; empty means the server's). Empty
+// Presets use domain.DefaultDayparts.
+type DaypartConfig struct {
+	Enabled  bool
+	Timezone string
+	Presets  domain.DaypartPresets
+}
+
+// WeatherConfig enables weather-aware generation through OpenWeather at URL
+// (default the public API). Users still opt in with a location through
+// PUT /settings/weather.
+type WeatherConfig struct {
+	APIKey string
+	URL    string
+}
+
+// AppleMusicConfig adds the Apple Music catalog at URL (default the public
+// API) to track lookups when Token, a MusicKit developer token, is set.
+// Storefront is the catalog country (default "us").
+type AppleMusicConfig struct {
+	Token      string
+	Storefront string
+	URL        string
+}
+
+// LastFMConfig enables expanding intents with similar artists from Last.fm
+// at URL (default the public API) when APIKey is set.
+type LastFMConfig struct {
+	APIKey string
+	URL    string
+}
+
+// FocusConfig enables focus mode: intents processed while an event of the
+// iCalendar feed at CalendarURL is in progress and matches one of Keywords
+// lean instrumental and low energy. Empty Keywords use
+// domain.DefaultFocusKeywords.
+type FocusConfig struct {
+	CalendarURL string
+	Keywords    []string
+}
+
+// AnthropicConfig compiles intents with Model (default claude-sonnet-4-5)
+// through the Anthropic Messages API at URL (default the public API).
+type AnthropicConfig struct {
+	APIKey string
+	Model  string
+	URL    string
+}
+
+// CaptureConfig controls recording of intent compiler exchanges.
+type CaptureConfig struct {
+	Enabled    bool
+	MaxAge     time.Duration
+	MaxEntries int
+}
+
+// BlobConfig selects the artifact store: Driver "local" (default, under
+// Dir) or "s3".
+type BlobConfig struct {
+	Driver string
+	Dir    string
+	S3     blob.S3Config
+}
+
+// BackupConfig enables database snapshots into the blob store.
+type BackupConfig struct {
+	Enabled bool
+	Retain  int
+	// Interval schedules snapshots on the leader; zero means on demand only.
+	Interval time.Duration
+}
+
+// CleanupConfig tunes the orphan and snapshot cleanup job.
+type CleanupConfig struct {
+	Grace          time.Duration
+	SnapshotMaxAge time.Duration
+	// Interval schedules cleanups on the leader; zero disables scheduling.
+	Interval time.Duration
+	DryRun   bool
+}
+
+// BackfillConfig tunes the job that resolves missing previews and queues
+// unanalyzed tracks for analysis.
+type BackfillConfig struct {
+	// Interval schedules backfills on the leader; zero disables scheduling.
+	Interval time.Duration
+	// Limit caps the tracks handled per run (default 100).
+	Limit int
+}
+
+// CooccurrenceConfig tunes the job that rebuilds the model of tracks added
+// to playlists together.
+type CooccurrenceConfig struct {
+	// Interval schedules rebuilds on the leader; zero disables scheduling.
+	Interval time.Duration
+	// MaxNeighbors caps the co-occurring tracks kept per track (default 50).
+	MaxNeighbors int
+}
+
+// OutboundConfig identifies Overture on requests to Spotify, Ollama and the
+// other providers: a User-Agent naming Version and the instance, plus any
+// Headers a provider asks partners to send.
+type OutboundConfig struct {
+	Version string
+	Headers map[string]string
+}
+
+// EventBusConfig selects how domain events reach subscribers such as the
+// /playlists/{id}/events feeds. Driver is "memory" (default: this process
+// only), "redis" or "nats", which share events between replicas through
+// Channel at URL.
+type EventBusConfig struct {
+	Driver  string
+	URL     string
+	Channel string
+}
+
+// SpotifyCacheConfig configures the Spotify search and audio features
+// cache. Driver is "memory" (an LRU of Size entries per process), "redis"
+// (shared by every replica, at URL) or "none".
+type SpotifyCacheConfig struct {
+	Driver      string
+	URL         string
+	Size        int
+	SearchTTL   time.Duration
+	FeaturesTTL time.Duration
+}
+
+// SentryConfig enables error reporting when DSN is set.
+type SentryConfig struct {
+	DSN         string
+	Environment string
+	Release     string
+}
+
+// DefaultConfig returns the server defaults.
+func DefaultConfig() Config {
+	return Config{
+		StorageDriver:            "sqlite",
+		DatabasePath:             "overture.db",
+		SQLite:                   sqlite.DefaultConfig(),
+		ArtistCacheTTL:           7 * 24 * time.Hour,
+		ArtistCacheEvictInterval: 24 * time.Hour,
+		SpotifyCache:             SpotifyCacheConfig{Driver: "memory", Size: 10000, SearchTTL: 24 * time.Hour, FeaturesTTL: 7 * 24 * time.Hour},
+		SpotifyRateLimit:         10,
+		SpotifyRateBurst:         20,
+		SpotifyMaxRetries:        3,
+		SpotifyRetryBackoff:      500 * time.Millisecond,
+		SpotifyMinConfidence:     0.5,
+		SpotifyMarket:            spotify.DefaultMarket,
+		OllamaModel:              ollama.DefaultModel,
+		PromptGenres:             prompt.DefaultGenres,
+		IntentRepairs:            2,
+		IntentFallback:           true,
+		Synthetic:                synthetic.Config{Latency: 50 * time.Millisecond, TracksPerArtist: 10},
+		Breaker:                  breaker.Config{Failures: 5, Cooldown: 30 * time.Second},
+		Chaos:                    chaos.Config{LatencyRate: 1},
+		Capture:                  CaptureConfig{MaxAge: 7 * 24 * time.Hour, MaxEntries: 500},
+		Workers:                  2,
+		QueueSize:                100,
+		RateLimit:                rest.RateLimit{Burst: 20, IntentBurst: 3},
+		Blob:                     BlobConfig{Driver: "local", Dir: "data"},
+		Backups:                  BackupConfig{Retain: 7},
+		Cleanup:                  CleanupConfig{Grace: 7 * 24 * time.Hour, Interval: 24 * time.Hour},
+		Backfill:                 BackfillConfig{Interval: 24 * time.Hour, Limit: 100},
+		Cooccurrence:             CooccurrenceConfig{Interval: time.Hour, MaxNeighbors: 50},
+		EventBus:                 EventBusConfig{Driver: "memory", Channel: "overture.events"},
+		Tracing:                  tracing.Config{ServiceName: "overture-backend"},
+	}
+}
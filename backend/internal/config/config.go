@@ -0,0 +1,163 @@
+// Package config loads the server's configuration into one typed Config.
+// Values come from defaults, an optional YAML file, the environment and
+// command-line flags, in increasing order of precedence. Validate rejects a
+// configuration the server cannot run with before anything is started, and
+// Redacted renders it without secrets for /debug/config.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/app"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+)
+
+// Config is everything the api server is configured with.
+type Config struct {
+	App     app.Config
+	Server  ServerConfig
+	Logging logging.Config
+	// Env names the deployment, e.g. "production". Chaos faults are refused
+	// in production.
+	Env string
+}
+
+// ServerConfig describes how the server listens and shuts down.
+type ServerConfig struct {
+	// ListenAddr is a TCP address, or unix:///path/to.sock for a unix
+	// socket.
+	ListenAddr string
+	// GRPCAddr also serves the gRPC API when set, e.g. ":9090".
+	GRPCAddr string
+	// DrainTimeout bounds how long the old process keeps serving in-flight
+	// requests after a listener handoff.
+	DrainTimeout time.Duration
+	TLS          TLSConfig
+}
+
+// TLSConfig describes how the server terminates TLS, if at all.
+// Deployments behind an ingress leave everything empty and serve plain HTTP.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// AutocertDomains obtains certificates from Let's Encrypt for these
+	// hosts, cached in AutocertCacheDir.
+	AutocertDomains  []string
+	AutocertCacheDir string
+	AutocertEmail    string
+	// H2C serves HTTP/2 without TLS.
+	H2C bool
+}
+
+// Enabled reports whether the server terminates TLS.
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" || len(t.AutocertDomains) > 0
+}
+
+// Scheme is the URL scheme clients reach the server with.
+func (t TLSConfig) Scheme() string {
+	if t.Enabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// Default returns the configuration used for everything left unset.
+func Default() Config {
+	return Config{
+		App: app.DefaultConfig(),
+		Server: ServerConfig{
+			ListenAddr:   ":8080",
+			DrainTimeout: 150 * time.Second,
+			TLS:          TLSConfig{AutocertCacheDir: "autocert-cache"},
+		},
+	}
+}
+
+// Validate reports every setting the server cannot run with.
+func (c Config) Validate() error {
+	var errs []error
+	a := c.App
+	if !a.LoadTest && (a.SpotifyClientID == "" || a.SpotifyClientSecret == "") {
+		errs = append(errs, errors.New("SPOTIFY_CLIENT_ID and SPOTIFY_CLIENT_SECRET are required"))
+	}
+	switch a.StorageDriver {
+	case "sqlite", "postgres":
+	default:
+		errs = append(errs, fmt.Errorf("unknown STORAGE_DRIVER %q (want sqlite or postgres)", a.StorageDriver))
+	}
+	switch a.SQLite.JournalMode {
+	case "", "DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF":
+	default:
+		errs = append(errs, fmt.Errorf("unknown SQLITE_JOURNAL_MODE %q (want WAL, DELETE, TRUNCATE, PERSIST, MEMORY or OFF)", a.SQLite.JournalMode))
+	}
+	switch a.Blob.Driver {
+	case "local":
+	case "s3":
+		if a.Blob.S3.Bucket == "" {
+			errs = append(errs, errors.New("S3_BUCKET is required with BLOB_DRIVER=s3"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown BLOB_DRIVER %q (want local or s3)", a.Blob.Driver))
+	}
+	switch a.EventBus.Driver {
+	case "memory":
+	case "redis", "nats":
+		if a.EventBus.URL == "" {
+			errs = append(errs, fmt.Errorf("EVENT_BUS_URL is required with EVENT_BUS=%s", a.EventBus.Driver))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown EVENT_BUS %q (want memory, redis or nats)", a.EventBus.Driver))
+	}
+	switch a.SpotifyCache.Driver {
+	case "memory", "none":
+	case "redis":
+		if a.SpotifyCache.URL == "" {
+			errs = append(errs, errors.New("SPOTIFY_CACHE_URL is required with SPOTIFY_CACHE=redis"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown SPOTIFY_CACHE %q (want memory, redis or none)", a.SpotifyCache.Driver))
+	}
+	if a.SpotifyMinConfidence < 0 || a.SpotifyMinConfidence > 1 {
+		errs = append(errs, fmt.Errorf("SPOTIFY_MIN_CONFIDENCE must be between 0 and 1, got %v", a.SpotifyMinConfidence))
+	}
+	if !domain.ValidMarket(a.SpotifyMarket) {
+		errs = append(errs, fmt.Errorf("SPOTIFY_MARKET must be a two-letter country code such as US, got %q", a.SpotifyMarket))
+	}
+	if len(a.ChaosTargets) > 0 && c.Env == "production" {
+		errs = append(errs, errors.New("CHAOS_ENABLED must not be set when APP_ENV=production"))
+	}
+	if a.Dayparts.Enabled {
+		if len(a.Dayparts.Presets) > 0 {
+			if err := a.Dayparts.Presets.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("invalid DAYPART_CONFIG: %w", err))
+			}
+		}
+		if _, err := time.LoadLocation(a.Dayparts.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("invalid DAYPART_TIMEZONE %q", a.Dayparts.Timezone))
+		}
+	}
+	if a.Experiment != nil {
+		if err := a.Experiment.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid EXPERIMENT_CONFIG: %w", err))
+		}
+	}
+	if _, err := logging.New(io.Discard, c.Logging); err != nil {
+		errs = append(errs, err)
+	}
+	tls := c.Server.TLS
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	if tls.CertFile != "" && len(tls.AutocertDomains) > 0 {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_AUTOCERT_DOMAINS are mutually exclusive"))
+	}
+	if c.Server.DrainTimeout <= 0 {
+		errs = append(errs, errors.New("HANDOFF_DRAIN_TIMEOUT must be positive"))
+	}
+	return errors.Join(errs...)
+}
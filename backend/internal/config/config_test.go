@@ -0,0 +1,173 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "overture.yaml", `
+spotify:
+  client_id: from-file
+  client_secret: file-secret
+  max_retries: 5
+listen_addr: ":9000"
+focus_keywords: [deep work, focus]
+log_level: debug
+`)
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("SPOTIFY_CLIENT_ID", "from-env")
+	t.Setenv("LISTEN_ADDR", ":9100")
+
+	cfg, err := Load([]string{"-config", path, "-set", "spotify_max_retries=7", "-listen", ":9200"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.App.SpotifyClientID != "from-env" {
+		t.Errorf("expected the environment to override the file, got %q", cfg.App.SpotifyClientID)
+	}
+	if cfg.App.SpotifyClientSecret != "file-secret" {
+		t.Errorf("expected the file value for an unset variable, got %q", cfg.App.SpotifyClientSecret)
+	}
+	if cfg.App.SpotifyMaxRetries != 7 || cfg.Server.ListenAddr != ":9200" {
+		t.Errorf("expected flags to override everything, got retries %d and listen %q", cfg.App.SpotifyMaxRetries, cfg.Server.ListenAddr)
+	}
+	if strings.Join(cfg.App.Focus.Keywords, "|") != "deep work|focus" {
+		t.Errorf("expected the file list, got %v", cfg.App.Focus.Keywords)
+	}
+	if cfg.Logging.Level != "debug" {
+		t.Errorf("expected the file log level, got %q", cfg.Logging.Level)
+	}
+	if cfg.App.SpotifyRetryBackoff != 500*time.Millisecond || cfg.Server.DrainTimeout != 150*time.Second {
+		t.Errorf("expected defaults for unset values, got %v and %v", cfg.App.SpotifyRetryBackoff, cfg.Server.DrainTimeout)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validate: %v", err)
+	}
+}
+
+func TestLoad_MalformedValues(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("SPOTIFY_MIN_CONFIDENCE", "high")
+	t.Setenv("BACKFILL_LIMIT", "0")
+	t.Setenv("LYRICS_ENABLED", "sure")
+
+	_, err := Load(nil)
+	if err == nil {
+		t.Fatal("expected malformed values to be rejected")
+	}
+	for _, key := range []string{"SPOTIFY_MIN_CONFIDENCE", "BACKFILL_LIMIT", "LYRICS_ENABLED"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("expected the error to name %s, got %v", key, err)
+		}
+	}
+
+	if _, err := Load([]string{"-set", "novalue"}); err == nil {
+		t.Error("expected a -set without a value to be rejected")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() Config {
+		cfg := Default()
+		cfg.App.SpotifyClientID = "id"
+		cfg.App.SpotifyClientSecret = "secret"
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{name: "valid", mutate: func(*Config) {}},
+		{name: "load test needs no credentials", mutate: func(c *Config) { c.App.SpotifyClientID, c.App.LoadTest = "", true }},
+		{name: "missing credentials", mutate: func(c *Config) { c.App.SpotifyClientSecret = "" }, wantErr: "SPOTIFY_CLIENT_ID"},
+		{name: "unknown storage driver", mutate: func(c *Config) { c.App.StorageDriver = "mysql" }, wantErr: "STORAGE_DRIVER"},
+		{name: "s3 without bucket", mutate: func(c *Config) { c.App.Blob.Driver = "s3" }, wantErr: "S3_BUCKET"},
+		{name: "redis without url", mutate: func(c *Config) { c.App.EventBus.Driver = "redis" }, wantErr: "EVENT_BUS_URL"},
+		{name: "redis cache without url", mutate: func(c *Config) { c.App.SpotifyCache.Driver = "redis" }, wantErr: "SPOTIFY_CACHE_URL"},
+		{name: "confidence out of range", mutate: func(c *Config) { c.App.SpotifyMinConfidence = 1.5 }, wantErr: "SPOTIFY_MIN_CONFIDENCE"},
+		{name: "unknown journal mode", mutate: func(c *Config) { c.App.SQLite.JournalMode = "FAST" }, wantErr: "SQLITE_JOURNAL_MODE"},
+		{name: "unknown market", mutate: func(c *Config) { c.App.SpotifyMarket = "USA" }, wantErr: "SPOTIFY_MARKET"},
+		{name: "chaos in production", mutate: func(c *Config) { c.Env, c.App.ChaosTargets = "production", []string{"spotify"} }, wantErr: "CHAOS_ENABLED"},
+		{name: "unknown timezone", mutate: func(c *Config) { c.App.Dayparts.Enabled, c.App.Dayparts.Timezone = true, "Mars/Olympus" }, wantErr: "DAYPART_TIMEZONE"},
+		{name: "invalid log level", mutate: func(c *Config) { c.Logging.Level = "loud" }, wantErr: "invalid level"},
+		{name: "cert without key", mutate: func(c *Config) { c.Server.TLS.CertFile = "cert.pem" }, wantErr: "TLS_KEY_FILE"},
+		{name: "cert and autocert", mutate: func(c *Config) {
+			c.Server.TLS = TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem", AutocertDomains: []string{"example.com"}}
+		}, wantErr: "mutually exclusive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.mutate(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected valid, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an error mentioning %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := Default()
+	cfg.App.SpotifyClientID = "client-id"
+	cfg.App.SpotifyClientSecret = "s3cret"
+	cfg.App.AdminToken = "admin-token"
+	cfg.App.Anthropic.APIKey = "sk-ant"
+	cfg.App.Blob.S3.AccessKey = "AKIA"
+	cfg.App.Sentry.DSN = "https://key@sentry.example/1"
+	cfg.App.EventBus.URL = "redis://:hunter2@redis:6379/0"
+	cfg.App.Outbound.Headers = map[string]string{"X-Partner-Id": "partner"}
+
+	out := cfg.Redacted()
+	app := out["App"].(map[string]any)
+	for _, key := range []string{"SpotifyClientSecret", "AdminToken"} {
+		if app[key] != Redacted {
+			t.Errorf("expected %s redacted, got %v", key, app[key])
+		}
+	}
+	if app["SpotifyClientID"] != "client-id" {
+		t.Errorf("expected the client ID kept, got %v", app["SpotifyClientID"])
+	}
+	if app["Anthropic"].(map[string]any)["APIKey"] != Redacted {
+		t.Errorf("expected the Anthropic key redacted")
+	}
+	if app["Sentry"].(map[string]any)["DSN"] != Redacted {
+		t.Errorf("expected the Sentry DSN redacted")
+	}
+	if got := app["EventBus"].(map[string]any)["URL"]; strings.Contains(got.(string), "hunter2") {
+		t.Errorf("expected the URL password redacted, got %v", got)
+	}
+	if got := app["Outbound"].(map[string]any)["Headers"].(map[string]any)["X-Partner-Id"]; got != Redacted {
+		t.Errorf("expected header values redacted, got %v", got)
+	}
+	if got := out["Server"].(map[string]any)["DrainTimeout"]; got != "2m30s" {
+		t.Errorf("expected durations rendered as strings, got %v", got)
+	}
+	if got := app["Blob"].(map[string]any)["S3"].(map[string]any)["AccessKey"]; got != Redacted {
+		t.Errorf("expected the S3 access key redacted, got %v", got)
+	}
+	if got := app["Weather"].(map[string]any)["APIKey"]; got != "" {
+		t.Errorf("expected unset secrets left empty, got %v", got)
+	}
+}
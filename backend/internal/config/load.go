@@ -0,0 +1,582 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/blob"
+	"github.com/ewilliams-labs/overture/backend/internal/app"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/flags"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads the configuration from the defaults, the YAML file named by
+// -config or CONFIG_FILE, the environment, and the -set KEY=VALUE and
+// -listen flags in args, each overriding the one before. The file uses the
+// environment variable names as nested lowercase keys, so
+//
+//	spotify:
+//	  client_id: abc
+//
+// sets SPOTIFY_CLIENT_ID. It is an error for a set value to be malformed;
+// Validate checks that the values fit together.
+func Load(args []string) (Config, error) {
+	sets := map[string]string{}
+	fs := flag.NewFlagSet("overture", flag.ContinueOnError)
+	file := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration `file`")
+	listen := fs.String("listen", "", "listen `address`, overriding LISTEN_ADDR")
+	fs.Func("set", "set a configuration `KEY=VALUE`, overriding the environment", func(s string) error {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("want KEY=VALUE, got %q", s)
+		}
+		sets[strings.ToUpper(key)] = value
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if *listen != "" {
+		sets["LISTEN_ADDR"] = *listen
+	}
+
+	l := &loader{sets: sets, file: map[string]string{}}
+	if *file != "" {
+		raw, err := os.ReadFile(*file) // #nosec G304 -- operator-supplied config path
+		if err != nil {
+			return Config{}, fmt.Errorf("config: read %s: %w", *file, err)
+		}
+		var doc map[string]any
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return Config{}, fmt.Errorf("config: parse %s: %w", *file, err)
+		}
+		flatten("", doc, l.file)
+	}
+
+	cfg := Default()
+	l.load(&cfg)
+	if err := errors.Join(l.errs...); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// flatten stores the scalars of doc in out under their upper-cased key
+// paths joined with "_". Lists become comma-separated values.
+func flatten(prefix string, doc map[string]any, out map[string]string) {
+	for k, v := range doc {
+		key := strings.ToUpper(k)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch v := v.(type) {
+		case map[string]any:
+			flatten(key, v, out)
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			out[key] = strings.Join(items, ",")
+		case nil:
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// loader reads settings by environment variable name, collecting every
+// malformed value instead of stopping at the first.
+type loader struct {
+	sets map[string]string
+	file map[string]string
+	errs []error
+}
+
+// get returns the value of key from the flags, the environment or the
+// file, in that order. Empty values count as unset.
+func (l *loader) get(key string) string {
+	if v := l.sets[key]; v != "" {
+		return v
+	}
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return l.file[key]
+}
+
+func (l *loader) fail(key, raw string) {
+	l.errs = append(l.errs, fmt.Errorf("invalid %s %q", key, raw))
+}
+
+// string returns key, or def when it is unset.
+func (l *loader) string(key, def string) string {
+	if v := strings.TrimSpace(l.get(key)); v != "" {
+		return v
+	}
+	return def
+}
+
+func (l *loader) bool(key string) bool {
+	raw := l.get(key)
+	if raw == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.fail(key, raw)
+	}
+	return b
+}
+
+// duration reads a non-negative duration such as "6h" from key, or returns
+// def when it is unset.
+func (l *loader) duration(key string, def time.Duration) time.Duration {
+	raw := l.get(key)
+	if raw == "" {
+		return def
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		l.fail(key, raw)
+		return def
+	}
+	return d
+}
+
+// millis reads a positive number of milliseconds from key, or returns def.
+func (l *loader) millis(key string, def time.Duration) time.Duration {
+	raw := l.get(key)
+	if raw == "" {
+		return def
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		l.fail(key, raw)
+		return def
+	}
+	return time.Duration(n) * time.Millisecond
+}
+
+// rate reads a probability between 0 and 1 from key, or returns def.
+func (l *loader) rate(key string, def float64) float64 {
+	raw := l.get(key)
+	if raw == "" {
+		return def
+	}
+	rate, err := strconv.ParseFloat(raw, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		l.fail(key, raw)
+		return def
+	}
+	return rate
+}
+
+// nonNegative reads a number of at least 0 from key, or returns def.
+func (l *loader) nonNegative(key string, def float64) float64 {
+	raw := l.get(key)
+	if raw == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || f < 0 {
+		l.fail(key, raw)
+		return def
+	}
+	return f
+}
+
+// count reads a non-negative integer from key, or returns def.
+func (l *loader) count(key string, def int) int {
+	raw := l.get(key)
+	if raw == "" {
+		return def
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		l.fail(key, raw)
+		return def
+	}
+	return n
+}
+
+// positive reads an integer of at least 1 from key, or returns def.
+func (l *loader) positive(key string, def int) int {
+	raw := l.get(key)
+	if raw == "" {
+		return def
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		l.fail(key, raw)
+		return def
+	}
+	return n
+}
+
+// list reads a comma-separated list from key, dropping empty entries.
+func (l *loader) list(key string) []string {
+	var out []string
+	for _, s := range strings.Split(l.get(key), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// jsonFile decodes the JSON file named by key into v, reporting whether
+// key was set.
+func (l *loader) jsonFile(key string, v any) bool {
+	path := l.get(key)
+	if path == "" {
+		return false
+	}
+	raw, err := os.ReadFile(path) // #nosec G304 -- operator-supplied config path
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("read %s: %w", key, err))
+		return false
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s %q: %w", key, path, err))
+		return false
+	}
+	return true
+}
+
+func (l *loader) load(cfg *Config) {
+	cfg.Env = l.get("APP_ENV")
+	cfg.Logging = l.loadLogging()
+	l.loadServer(&cfg.Server)
+
+	a := &cfg.App
+	a.SpotifyClientID = l.get("SPOTIFY_CLIENT_ID")
+	a.SpotifyClientSecret = l.get("SPOTIFY_CLIENT_SECRET")
+	a.SpotifyRefreshToken = l.get("SPOTIFY_REFRESH_TOKEN")
+	a.SpotifyRedirectURL = l.get("SPOTIFY_REDIRECT_URL")
+	a.LoadTest = l.bool("LOAD_TEST")
+	if a.LoadTest {
+		l.loadTest(a)
+	}
+
+	a.StorageDriver = l.string("STORAGE_DRIVER", a.StorageDriver)
+	l.loadSQLite(a)
+	a.OllamaHost = l.get("OLLAMA_HOST")
+	a.OllamaModel = l.string("OLLAMA_MODEL", a.OllamaModel)
+	a.PromptDir = l.string("PROMPT_DIR", a.PromptDir)
+	if genres := l.list("PROMPT_GENRES"); len(genres) > 0 {
+		a.PromptGenres = genres
+	}
+	a.IntentRepairs = l.count("INTENT_REPAIR_ATTEMPTS", a.IntentRepairs)
+	if l.get("INTENT_FALLBACK") != "" {
+		a.IntentFallback = l.bool("INTENT_FALLBACK")
+	}
+	a.Anthropic = app.AnthropicConfig{
+		APIKey: l.get("ANTHROPIC_API_KEY"),
+		Model:  l.get("ANTHROPIC_MODEL"),
+		URL:    l.get("ANTHROPIC_URL"),
+	}
+	a.ArtistCacheTTL = l.duration("ARTIST_CACHE_TTL", a.ArtistCacheTTL)
+	a.ArtistCacheEvictInterval = l.duration("ARTIST_CACHE_EVICT_INTERVAL", a.ArtistCacheEvictInterval)
+	a.SpotifyCache = app.SpotifyCacheConfig{
+		Driver:      l.string("SPOTIFY_CACHE", a.SpotifyCache.Driver),
+		URL:         l.get("SPOTIFY_CACHE_URL"),
+		Size:        l.positive("SPOTIFY_CACHE_SIZE", a.SpotifyCache.Size),
+		SearchTTL:   l.duration("SPOTIFY_SEARCH_CACHE_TTL", a.SpotifyCache.SearchTTL),
+		FeaturesTTL: l.duration("SPOTIFY_FEATURES_CACHE_TTL", a.SpotifyCache.FeaturesTTL),
+	}
+	l.loadSpotify(a)
+	l.loadOutbound(a)
+	l.loadChaos(a)
+	a.Breaker.Failures = l.positive("BREAKER_FAILURES", a.Breaker.Failures)
+	a.Breaker.Cooldown = l.duration("BREAKER_COOLDOWN", a.Breaker.Cooldown)
+	l.loadFlags(a)
+	l.loadCapture(a)
+	// Recorded runs feed POST /admin/replay.
+	a.RecordIntentRuns = l.bool("RECORD_INTENT_RUNS")
+	var exp domain.Experiment
+	if l.jsonFile("EXPERIMENT_CONFIG", &exp) {
+		a.Experiment = &exp
+	}
+	a.FingerprintPreviews = l.bool("FINGERPRINT_PREVIEWS")
+	a.Lyrics = app.LyricsConfig{
+		Enabled: l.bool("LYRICS_ENABLED"),
+		URL:     l.get("LRCLIB_URL"),
+		Valence: l.bool("LYRICS_VALENCE"),
+	}
+	l.loadDayparts(a)
+	// FOCUS_KEYWORDS lists the words that mark a calendar event as a focus
+	// block.
+	a.Focus = app.FocusConfig{
+		CalendarURL: l.get("FOCUS_CALENDAR_URL"),
+		Keywords:    l.list("FOCUS_KEYWORDS"),
+	}
+	a.Weather = app.WeatherConfig{
+		APIKey: l.get("OPENWEATHER_API_KEY"),
+		URL:    l.get("OPENWEATHER_URL"),
+	}
+	a.LastFM = app.LastFMConfig{
+		APIKey: l.get("LASTFM_API_KEY"),
+		URL:    l.get("LASTFM_URL"),
+	}
+	a.FeatureFallback = app.FeatureFallbackConfig{
+		Enabled:           l.bool("FEATURE_FALLBACK"),
+		MusicBrainzURL:    l.get("MUSICBRAINZ_URL"),
+		AcousticBrainzURL: l.get("ACOUSTICBRAINZ_URL"),
+	}
+	a.AppleMusic = app.AppleMusicConfig{
+		Token:      l.get("APPLE_MUSIC_TOKEN"),
+		Storefront: l.get("APPLE_MUSIC_STOREFRONT"),
+		URL:        l.get("APPLE_MUSIC_URL"),
+	}
+	a.PreviewFallback = app.PreviewFallbackConfig{
+		Enabled:   l.bool("PREVIEW_FALLBACK"),
+		ITunesURL: l.get("ITUNES_URL"),
+		DeezerURL: l.get("DEEZER_URL"),
+	}
+
+	a.InstanceID = l.string("INSTANCE_ID", defaultInstanceID())
+	a.AdminToken = l.get("ADMIN_TOKEN")
+	l.loadRateLimit(a)
+	a.Sentry = app.SentryConfig{
+		DSN:         l.get("SENTRY_DSN"),
+		Environment: l.get("SENTRY_ENVIRONMENT"),
+		Release:     l.get("SENTRY_RELEASE"),
+	}
+
+	l.loadBlob(a)
+	a.EventBus = app.EventBusConfig{
+		Driver:  l.string("EVENT_BUS", a.EventBus.Driver),
+		URL:     l.get("EVENT_BUS_URL"),
+		Channel: l.string("EVENT_BUS_CHANNEL", a.EventBus.Channel),
+	}
+	a.Tracing.Endpoint = l.get("OTEL_EXPORTER_OTLP_ENDPOINT")
+	a.Tracing.ServiceName = l.string("OTEL_SERVICE_NAME", a.Tracing.ServiceName)
+	l.loadBackups(a)
+	a.Cleanup = app.CleanupConfig{
+		Grace:          l.duration("CLEANUP_GRACE", a.Cleanup.Grace),
+		SnapshotMaxAge: l.duration("SNAPSHOT_MAX_AGE", 0),
+		Interval:       l.duration("CLEANUP_INTERVAL", a.Cleanup.Interval),
+		DryRun:         l.bool("CLEANUP_DRY_RUN"),
+	}
+	// BACKFILL_INTERVAL and COOCCURRENCE_INTERVAL of zero disable
+	// scheduling.
+	a.Backfill.Interval = l.duration("BACKFILL_INTERVAL", a.Backfill.Interval)
+	a.Backfill.Limit = l.positive("BACKFILL_LIMIT", a.Backfill.Limit)
+	a.Cooccurrence.Interval = l.duration("COOCCURRENCE_INTERVAL", a.Cooccurrence.Interval)
+	a.Cooccurrence.MaxNeighbors = l.positive("COOCCURRENCE_NEIGHBORS", a.Cooccurrence.MaxNeighbors)
+}
+
+// loadLogging reads LOG_LEVEL (debug, info, warn or error; default info)
+// and LOG_FORMAT (text or json; default text).
+func (l *loader) loadLogging() logging.Config {
+	return logging.Config{Level: l.get("LOG_LEVEL"), Format: l.get("LOG_FORMAT")}
+}
+
+// loadServer reads LISTEN_ADDR, GRPC_LISTEN_ADDR, HANDOFF_DRAIN_TIMEOUT and
+// the TLS settings: TLS_CERT_FILE and TLS_KEY_FILE, or TLS_AUTOCERT_DOMAINS
+// with TLS_AUTOCERT_CACHE_DIR and TLS_AUTOCERT_EMAIL. HTTP2_CLEARTEXT
+// enables h2c.
+func (l *loader) loadServer(s *ServerConfig) {
+	s.ListenAddr = l.string("LISTEN_ADDR", s.ListenAddr)
+	s.GRPCAddr = l.string("GRPC_LISTEN_ADDR", s.GRPCAddr)
+	s.DrainTimeout = l.duration("HANDOFF_DRAIN_TIMEOUT", s.DrainTimeout)
+	s.TLS = TLSConfig{
+		CertFile:         l.get("TLS_CERT_FILE"),
+		KeyFile:          l.get("TLS_KEY_FILE"),
+		AutocertDomains:  l.list("TLS_AUTOCERT_DOMAINS"),
+		AutocertCacheDir: l.string("TLS_AUTOCERT_CACHE_DIR", s.TLS.AutocertCacheDir),
+		AutocertEmail:    l.get("TLS_AUTOCERT_EMAIL"),
+		H2C:              l.bool("HTTP2_CLEARTEXT"),
+	}
+}
+
+// defaultInstanceID identifies this process when claiming shared work by
+// hostname and PID, which is unique per replica in container deployments.
+func defaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// loadFlags reads FEATURE_FLAGS, a comma-separated list of experimental
+// behaviors to enable such as "target_scoring=false".
+func (l *loader) loadFlags(a *app.Config) {
+	raw := l.get("FEATURE_FLAGS")
+	if raw == "" {
+		return
+	}
+	enabled, err := flags.Parse(raw)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid FEATURE_FLAGS %q: %w", raw, err))
+		return
+	}
+	a.Flags = enabled
+}
+
+// loadDayparts enables daypart vibe presets with DAYPARTS_ENABLED. The
+// presets come from the JSON file at DAYPART_CONFIG when set, and the time
+// of day from DAYPART_TIMEZONE.
+func (l *loader) loadDayparts(a *app.Config) {
+	a.Dayparts.Enabled = l.bool("DAYPARTS_ENABLED")
+	a.Dayparts.Timezone = l.get("DAYPART_TIMEZONE")
+	l.jsonFile("DAYPART_CONFIG", &a.Dayparts.Presets)
+}
+
+// loadBlob selects where artifacts such as snapshots are kept via
+// BLOB_DRIVER: "local" (default, below BLOB_DIR) or "s3".
+func (l *loader) loadBlob(a *app.Config) {
+	a.Blob.Driver = l.string("BLOB_DRIVER", a.Blob.Driver)
+	a.Blob.Dir = l.string("BLOB_DIR", a.Blob.Dir)
+	a.Blob.S3 = blob.S3Config{
+		Endpoint:  l.get("S3_ENDPOINT"),
+		Region:    l.get("S3_REGION"),
+		Bucket:    l.get("S3_BUCKET"),
+		Prefix:    l.get("S3_PREFIX"),
+		AccessKey: l.get("S3_ACCESS_KEY_ID"),
+		SecretKey: l.get("S3_SECRET_ACCESS_KEY"),
+	}
+}
+
+// loadSQLite reads the SQLite connection tuning.
+func (l *loader) loadSQLite(a *app.Config) {
+	a.SQLite.JournalMode = strings.ToUpper(l.string("SQLITE_JOURNAL_MODE", a.SQLite.JournalMode))
+	a.SQLite.BusyTimeout = l.duration("SQLITE_BUSY_TIMEOUT", a.SQLite.BusyTimeout)
+	if l.get("SQLITE_FOREIGN_KEYS") != "" {
+		a.SQLite.ForeignKeys = l.bool("SQLITE_FOREIGN_KEYS")
+	}
+	a.SQLite.MaxOpenConns = l.count("SQLITE_MAX_OPEN_CONNS", a.SQLite.MaxOpenConns)
+	a.SQLite.MaxIdleConns = l.count("SQLITE_MAX_IDLE_CONNS", a.SQLite.MaxIdleConns)
+}
+
+// loadBackups enables database snapshots when BACKUPS_ENABLED is set,
+// keeping BACKUP_RETAIN (default 7) of them. BACKUP_INTERVAL of zero keeps
+// on-demand snapshots via the admin API only.
+func (l *loader) loadBackups(a *app.Config) {
+	a.Backups.Enabled = l.bool("BACKUPS_ENABLED")
+	if !a.Backups.Enabled {
+		return
+	}
+	a.Backups.Retain = l.positive("BACKUP_RETAIN", a.Backups.Retain)
+	a.Backups.Interval = l.duration("BACKUP_INTERVAL", 0)
+}
+
+// loadSpotify reads SPOTIFY_RATE_LIMIT, the Spotify requests allowed per
+// second (default 10, 0 disables pacing), SPOTIFY_RATE_BURST (default 20),
+// the retry settings SPOTIFY_MAX_RETRIES (default 3) and
+// SPOTIFY_RETRY_BACKOFF_MS (default 500), and SPOTIFY_MIN_CONFIDENCE, the
+// lowest search match score accepted (default 0.5), and SPOTIFY_MARKET, the
+// country tracks are looked up in (default US).
+func (l *loader) loadSpotify(a *app.Config) {
+	a.SpotifyRateLimit = l.nonNegative("SPOTIFY_RATE_LIMIT", a.SpotifyRateLimit)
+	a.SpotifyRateBurst = l.positive("SPOTIFY_RATE_BURST", a.SpotifyRateBurst)
+	a.SpotifyMaxRetries = l.positive("SPOTIFY_MAX_RETRIES", a.SpotifyMaxRetries)
+	a.SpotifyRetryBackoff = l.millis("SPOTIFY_RETRY_BACKOFF_MS", a.SpotifyRetryBackoff)
+	if raw := l.get("SPOTIFY_MIN_CONFIDENCE"); raw != "" {
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			l.fail("SPOTIFY_MIN_CONFIDENCE", raw)
+		} else {
+			a.SpotifyMinConfidence = threshold
+		}
+	}
+	a.SpotifyMarket = strings.ToUpper(l.string("SPOTIFY_MARKET", a.SpotifyMarket))
+}
+
+// loadRateLimit reads RATE_LIMIT, the REST requests per second allowed per
+// client (0 disables), in bursts of RATE_LIMIT_BURST (default 20), and the
+// separate INTENT_RATE_LIMIT and INTENT_RATE_LIMIT_BURST (default 3) for
+// intents. RATE_LIMIT_TRUST_PROXY identifies clients by the address the BFF
+// forwards, and RATE_LIMIT_API_KEYS lists the X-API-Key values that get
+// their own bucket.
+func (l *loader) loadRateLimit(a *app.Config) {
+	a.RateLimit.Rate = l.nonNegative("RATE_LIMIT", a.RateLimit.Rate)
+	a.RateLimit.Burst = l.positive("RATE_LIMIT_BURST", a.RateLimit.Burst)
+	a.RateLimit.IntentRate = l.nonNegative("INTENT_RATE_LIMIT", a.RateLimit.IntentRate)
+	a.RateLimit.IntentBurst = l.positive("INTENT_RATE_LIMIT_BURST", a.RateLimit.IntentBurst)
+	a.RateLimit.TrustProxy = l.bool("RATE_LIMIT_TRUST_PROXY")
+	a.RateLimit.APIKeys = l.list("RATE_LIMIT_API_KEYS")
+}
+
+// loadOutbound reads OUTBOUND_HEADERS, a comma-separated list of headers
+// such as "X-Partner-Id=abc123,X-Team=music" sent to every provider.
+func (l *loader) loadOutbound(a *app.Config) {
+	raw := l.get("OUTBOUND_HEADERS")
+	if raw == "" {
+		return
+	}
+	a.Outbound.Headers = map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t:") || strings.EqualFold(name, "User-Agent") {
+			l.errs = append(l.errs, fmt.Errorf("invalid OUTBOUND_HEADERS entry %q", pair))
+			continue
+		}
+		a.Outbound.Headers[name] = strings.TrimSpace(value)
+	}
+}
+
+// loadCapture enables recording of intent compiler prompts and responses
+// with CAPTURE_INTENTS. Captures older than CAPTURE_MAX_AGE (default 168h)
+// or beyond the newest CAPTURE_MAX_ENTRIES (default 500) are pruned.
+func (l *loader) loadCapture(a *app.Config) {
+	a.Capture.Enabled = l.bool("CAPTURE_INTENTS")
+	if !a.Capture.Enabled {
+		return
+	}
+	a.Capture.MaxEntries = l.positive("CAPTURE_MAX_ENTRIES", a.Capture.MaxEntries)
+	a.Capture.MaxAge = l.duration("CAPTURE_MAX_AGE", a.Capture.MaxAge)
+}
+
+// loadTest reads the synthetic provider settings: LOAD_TEST_LATENCY
+// (default 50ms), LOAD_TEST_JITTER, LOAD_TEST_ERROR_RATE (0-1) and
+// LOAD_TEST_TRACKS per artist (default 10).
+func (l *loader) loadTest(a *app.Config) {
+	a.Synthetic.Latency = l.duration("LOAD_TEST_LATENCY", a.Synthetic.Latency)
+	a.Synthetic.Jitter = l.duration("LOAD_TEST_JITTER", 0)
+	a.Synthetic.ErrorRate = l.rate("LOAD_TEST_ERROR_RATE", 0)
+	a.Synthetic.TracksPerArtist = l.positive("LOAD_TEST_TRACKS", a.Synthetic.TracksPerArtist)
+}
+
+// loadChaos reads fault injection settings when CHAOS_ENABLED is set.
+// CHAOS_TARGETS selects the wrapped providers (default "spotify,intent").
+func (l *loader) loadChaos(a *app.Config) {
+	if !l.bool("CHAOS_ENABLED") {
+		return
+	}
+	targets := l.list("CHAOS_TARGETS")
+	if len(targets) == 0 {
+		targets = []string{"spotify", "intent"}
+	}
+	for _, target := range targets {
+		switch target {
+		case "spotify", "intent":
+			a.ChaosTargets = append(a.ChaosTargets, target)
+		default:
+			l.errs = append(l.errs, fmt.Errorf("unknown CHAOS_TARGETS entry %q", target))
+		}
+	}
+
+	a.Chaos.Latency = l.duration("CHAOS_LATENCY", 0)
+	a.Chaos.LatencyRate = l.rate("CHAOS_LATENCY_RATE", 1)
+	a.Chaos.ErrorRate = l.rate("CHAOS_ERROR_RATE", 0)
+	a.Chaos.MalformedRate = l.rate("CHAOS_MALFORMED_RATE", 0)
+	if raw := l.get("CHAOS_SEED"); raw != "" {
+		seed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			l.fail("CHAOS_SEED", raw)
+		}
+		a.Chaos.Seed = seed
+	}
+}
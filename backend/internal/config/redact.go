@@ -0,0 +1,92 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Redacted replaces the value of a secret setting.
+const Redacted = "[REDACTED]"
+
+// secretFields are the name fragments of settings holding credentials.
+var secretFields = []string{"secret", "token", "password", "apikey", "accesskey", "dsn"}
+
+// Redacted returns c as nested maps keyed by field name, with credentials,
+// the passwords of URLs and outbound header values replaced by Redacted.
+func (c Config) Redacted() map[string]any {
+	return redactValue("", reflect.ValueOf(c)).(map[string]any)
+}
+
+func redactValue(name string, v reflect.Value) any {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(name, v.Elem())
+	case reflect.Struct:
+		out := map[string]any{}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			out[f.Name] = redactValue(f.Name, v.Field(i))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = redactValue(name, v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := map[string]any{}
+		for _, k := range v.MapKeys() {
+			value := v.MapIndex(k)
+			// Map values such as partner headers are often credentials.
+			if value.Kind() == reflect.String && value.String() != "" {
+				out[k.String()] = Redacted
+				continue
+			}
+			out[k.String()] = redactValue(name, value)
+		}
+		return out
+	case reflect.String:
+		s := v.String()
+		if s == "" {
+			return s
+		}
+		if secret(name) {
+			return Redacted
+		}
+		if u, err := url.Parse(s); err == nil && u.User != nil {
+			return u.Redacted()
+		}
+		return s
+	default:
+		return v.Interface()
+	}
+}
+
+// secret reports whether the field called name holds a credential.
+func secret(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range secretFields {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package domain
+
+// Album represents a released album and its tracks in disc and track order.
+type Album struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Artist      string  `json:"artist"`
+	ReleaseDate string  `json:"release_date"`
+	CoverURL    string  `json:"cover_url"`
+	Tracks      []Track `json:"tracks"`
+}
+
+// AlbumAddition reports which of an album's tracks were added to a
+// playlist. Songs already in the playlist, by Track.SameSong or recording,
+// are skipped.
+type AlbumAddition struct {
+	Album         Album   `json:"album"`
+	Added         []Track `json:"-"`
+	TracksAdded   int     `json:"tracks_added"`
+	TracksSkipped int     `json:"tracks_skipped"`
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// TempoBucketBPM is the width of a PlaylistAnalysis tempo histogram bucket.
+const TempoBucketBPM = 20
+
+// OutlierZScore is how many standard deviations from the playlist's mean a
+// feature must be for its track to be reported as an outlier.
+const OutlierZScore = 2.0
+
+// analysisFeatures are the features PlaylistAnalysis describes, by their
+// AudioFeatures JSON names.
+var analysisFeatures = []struct {
+	name  string
+	value func(AudioFeatures) float64
+}{
+	{"danceability", func(f AudioFeatures) float64 { return f.Danceability }},
+	{"energy", func(f AudioFeatures) float64 { return f.Energy }},
+	{"valence", func(f AudioFeatures) float64 { return f.Valence }},
+	{"tempo", func(f AudioFeatures) float64 { return f.Tempo }},
+	{"instrumentalness", func(f AudioFeatures) float64 { return f.Instrumentalness }},
+	{"acousticness", func(f AudioFeatures) float64 { return f.Acousticness }},
+}
+
+// FeatureStats describe how one audio feature is distributed over a
+// playlist's tracks. StdDev is the population standard deviation.
+type FeatureStats struct {
+	Mean   float64 `json:"mean"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	StdDev float64 `json:"stddev"`
+}
+
+// TempoBucket counts the tracks with a tempo from MinBPM up to, but not
+// including, MaxBPM.
+type TempoBucket struct {
+	MinBPM int `json:"min_bpm"`
+	MaxBPM int `json:"max_bpm"`
+	Count  int `json:"count"`
+}
+
+// Outlier is a track with a feature far from the rest of the playlist.
+type Outlier struct {
+	TrackID string  `json:"track_id"`
+	Title   string  `json:"title"`
+	Artist  string  `json:"artist"`
+	Feature string  `json:"feature"`
+	Value   float64 `json:"value"`
+	// ZScore is how many standard deviations Value is from the mean,
+	// negative below it.
+	ZScore float64 `json:"z_score"`
+}
+
+// PlaylistAnalysis describes the audio features of a playlist's music
+// beyond their averages.
+type PlaylistAnalysis struct {
+	TrackCount int           `json:"track_count"`
+	Averages   AudioFeatures `json:"averages"`
+	// Features are keyed by the AudioFeatures JSON names.
+	Features map[string]FeatureStats `json:"features"`
+	// TempoHistogram covers the playlist's tempo range in TempoBucketBPM
+	// steps, empty buckets included.
+	TempoHistogram []TempoBucket `json:"tempo_histogram"`
+	// Outliers are ordered by how far they stray, furthest first.
+	Outliers []Outlier `json:"outliers"`
+}
+
+// AnalyzeDetail returns the distribution of the playlist's audio features.
+// Like Analyze, it leaves episodes out.
+func (p Playlist) AnalyzeDetail() PlaylistAnalysis {
+	var tracks []Track
+	for _, t := range p.Tracks {
+		if !t.IsEpisode() {
+			tracks = append(tracks, t)
+		}
+	}
+	a := PlaylistAnalysis{
+		TrackCount:     len(tracks),
+		Averages:       p.Analyze(),
+		Features:       make(map[string]FeatureStats, len(analysisFeatures)),
+		TempoHistogram: tempoHistogram(tracks),
+		Outliers:       []Outlier{},
+	}
+	for _, feature := range analysisFeatures {
+		stats := featureStats(tracks, feature.value)
+		a.Features[feature.name] = stats
+		if stats.StdDev == 0 {
+			continue
+		}
+		for _, t := range tracks {
+			value := feature.value(t.Features)
+			z := (value - stats.Mean) / stats.StdDev
+			if math.Abs(z) >= OutlierZScore {
+				a.Outliers = append(a.Outliers, Outlier{
+					TrackID: t.ID, Title: t.Title, Artist: t.Artist,
+					Feature: feature.name, Value: value, ZScore: z,
+				})
+			}
+		}
+	}
+	sort.SliceStable(a.Outliers, func(i, j int) bool {
+		return math.Abs(a.Outliers[i].ZScore) > math.Abs(a.Outliers[j].ZScore)
+	})
+	return a
+}
+
+func featureStats(tracks []Track, value func(AudioFeatures) float64) FeatureStats {
+	if len(tracks) == 0 {
+		return FeatureStats{}
+	}
+	s := FeatureStats{Min: math.Inf(1), Max: math.Inf(-1)}
+	for _, t := range tracks {
+		v := value(t.Features)
+		s.Mean += v
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+	}
+	s.Mean /= float64(len(tracks))
+	var squares float64
+	for _, t := range tracks {
+		d := value(t.Features) - s.Mean
+		squares += d * d
+	}
+	s.StdDev = math.Sqrt(squares / float64(len(tracks)))
+	return s
+}
+
+func tempoHistogram(tracks []Track) []TempoBucket {
+	if len(tracks) == 0 {
+		return []TempoBucket{}
+	}
+	bucket := func(tempo float64) int { return int(math.Max(tempo, 0)) / TempoBucketBPM }
+	first, last := bucket(tracks[0].Features.Tempo), bucket(tracks[0].Features.Tempo)
+	for _, t := range tracks {
+		first = min(first, bucket(t.Features.Tempo))
+		last = max(last, bucket(t.Features.Tempo))
+	}
+	buckets := make([]TempoBucket, last-first+1)
+	for i := range buckets {
+		buckets[i].MinBPM = (first + i) * TempoBucketBPM
+		buckets[i].MaxBPM = buckets[i].MinBPM + TempoBucketBPM
+	}
+	for _, t := range tracks {
+		buckets[bucket(t.Features.Tempo)-first].Count++
+	}
+	return buckets
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPlaylist_AnalyzeDetail(t *testing.T) {
+	steady := AudioFeatures{Energy: 0.5, Tempo: 120}
+	p := Playlist{Tracks: []Track{
+		{ID: "1", Features: steady},
+		{ID: "2", Features: steady},
+		{ID: "3", Features: steady},
+		{ID: "4", Features: steady},
+		{ID: "5", Features: steady},
+		{ID: "6", Title: "Sprint", Artist: "A", Features: AudioFeatures{Energy: 1, Tempo: 175}},
+		{ID: "ep", Type: ItemEpisode, Features: AudioFeatures{Energy: 0, Tempo: 60}},
+	}}
+
+	got := p.AnalyzeDetail()
+
+	if got.TrackCount != 6 || got.Averages != p.Analyze() {
+		t.Fatalf("expected 6 tracks averaging %+v, got %d averaging %+v", p.Analyze(), got.TrackCount, got.Averages)
+	}
+	energy := got.Features["energy"]
+	wantStdDev := math.Sqrt((5*math.Pow(0.5-3.5/6, 2) + math.Pow(1-3.5/6, 2)) / 6)
+	if energy.Min != 0.5 || energy.Max != 1 || math.Abs(energy.Mean-3.5/6) > 1e-9 || math.Abs(energy.StdDev-wantStdDev) > 1e-9 {
+		t.Fatalf("unexpected energy stats %+v", energy)
+	}
+	if valence := got.Features["valence"]; valence != (FeatureStats{}) {
+		t.Fatalf("expected zero valence stats, got %+v", valence)
+	}
+
+	wantHistogram := []TempoBucket{{120, 140, 5}, {140, 160, 0}, {160, 180, 1}}
+	if !reflect.DeepEqual(got.TempoHistogram, wantHistogram) {
+		t.Fatalf("expected histogram %+v, got %+v", wantHistogram, got.TempoHistogram)
+	}
+
+	if len(got.Outliers) != 2 {
+		t.Fatalf("expected the sprint to stand out in energy and tempo, got %+v", got.Outliers)
+	}
+	features := map[string]bool{}
+	for _, o := range got.Outliers {
+		if o.TrackID != "6" || o.Title != "Sprint" || o.ZScore < OutlierZScore {
+			t.Fatalf("unexpected outlier %+v", o)
+		}
+		features[o.Feature] = true
+	}
+	if !features["energy"] || !features["tempo"] {
+		t.Fatalf("expected energy and tempo outliers, got %+v", got.Outliers)
+	}
+}
+
+func TestPlaylist_AnalyzeDetail_Empty(t *testing.T) {
+	got := Playlist{}.AnalyzeDetail()
+	if got.TrackCount != 0 || len(got.TempoHistogram) != 0 || len(got.Outliers) != 0 || got.TempoHistogram == nil || got.Outliers == nil {
+		t.Fatalf("expected an empty analysis with empty lists, got %+v", got)
+	}
+	if len(got.Features) != len(analysisFeatures) {
+		t.Fatalf("expected stats for every feature, got %+v", got.Features)
+	}
+}
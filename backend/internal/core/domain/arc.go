@@ -0,0 +1,71 @@
+package domain
+
+// DefaultArcWindow is how many tracks PlaylistArc smooths over unless told
+// otherwise.
+const DefaultArcWindow = 5
+
+// ArcPoint is one track's place in a playlist's arc.
+type ArcPoint struct {
+	Position int     `json:"position"`
+	TrackID  string  `json:"track_id"`
+	Title    string  `json:"title"`
+	Artist   string  `json:"artist"`
+	Energy   float64 `json:"energy"`
+	Valence  float64 `json:"valence"`
+	Tempo    float64 `json:"tempo"`
+}
+
+// ArcValue is a point of a playlist's smoothed arc.
+type ArcValue struct {
+	Energy  float64 `json:"energy"`
+	Valence float64 `json:"valence"`
+	Tempo   float64 `json:"tempo"`
+}
+
+// PlaylistArc is the series of a playlist's energy, valence and tempo in
+// play order, for charting its emotional arc.
+type PlaylistArc struct {
+	Tracks []ArcPoint `json:"tracks"`
+	// Smoothed holds, for each of Tracks, the average over the Window
+	// tracks centered on it, fewer at the ends.
+	Smoothed []ArcValue `json:"smoothed"`
+	Window   int        `json:"window"`
+}
+
+// Arc returns the playlist's arc smoothed over window tracks; a window
+// below 1 uses DefaultArcWindow. Episodes are left out, and Position counts
+// music tracks only.
+func (p Playlist) Arc(window int) PlaylistArc {
+	if window < 1 {
+		window = DefaultArcWindow
+	}
+	arc := PlaylistArc{Tracks: []ArcPoint{}, Smoothed: []ArcValue{}, Window: window}
+	for _, t := range p.Tracks {
+		if t.IsEpisode() {
+			continue
+		}
+		arc.Tracks = append(arc.Tracks, ArcPoint{
+			Position: len(arc.Tracks),
+			TrackID:  t.ID,
+			Title:    t.Title,
+			Artist:   t.Artist,
+			Energy:   t.Features.Energy,
+			Valence:  t.Features.Valence,
+			Tempo:    t.Features.Tempo,
+		})
+	}
+	// An even window leans one track later.
+	before, after := (window-1)/2, window/2
+	for i := range arc.Tracks {
+		lo, hi := max(i-before, 0), min(i+after, len(arc.Tracks)-1)
+		var v ArcValue
+		for _, pt := range arc.Tracks[lo : hi+1] {
+			v.Energy += pt.Energy
+			v.Valence += pt.Valence
+			v.Tempo += pt.Tempo
+		}
+		n := float64(hi - lo + 1)
+		arc.Smoothed = append(arc.Smoothed, ArcValue{Energy: v.Energy / n, Valence: v.Valence / n, Tempo: v.Tempo / n})
+	}
+	return arc
+}
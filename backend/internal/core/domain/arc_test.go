@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPlaylist_Arc(t *testing.T) {
+	p := Playlist{Tracks: []Track{
+		{ID: "1", Features: AudioFeatures{Energy: 0.2, Valence: 0.1, Tempo: 90}},
+		{ID: "ep", Type: ItemEpisode, Features: AudioFeatures{Energy: 1}},
+		{ID: "2", Features: AudioFeatures{Energy: 0.5, Valence: 0.4, Tempo: 120}},
+		{ID: "3", Features: AudioFeatures{Energy: 0.8, Valence: 0.7, Tempo: 150}},
+		{ID: "4", Features: AudioFeatures{Energy: 0.2, Valence: 0.1, Tempo: 90}},
+	}}
+
+	tests := []struct {
+		name       string
+		window     int
+		wantWindow int
+		wantEnergy []float64
+	}{
+		{name: "no smoothing", window: 1, wantWindow: 1, wantEnergy: []float64{0.2, 0.5, 0.8, 0.2}},
+		{name: "three tracks", window: 3, wantWindow: 3, wantEnergy: []float64{0.35, 0.5, 0.5, 0.5}},
+		{name: "default", window: 0, wantWindow: DefaultArcWindow, wantEnergy: []float64{0.5, 0.425, 0.425, 0.5}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			arc := p.Arc(tc.window)
+			if arc.Window != tc.wantWindow || len(arc.Tracks) != 4 || len(arc.Smoothed) != 4 {
+				t.Fatalf("expected 4 points smoothed over %d, got %+v", tc.wantWindow, arc)
+			}
+			for i, pt := range arc.Tracks {
+				if pt.Position != i {
+					t.Fatalf("expected position %d, got %+v", i, pt)
+				}
+				if math.Abs(arc.Smoothed[i].Energy-tc.wantEnergy[i]) > 1e-9 {
+					t.Fatalf("smoothed energy %d: expected %.3f, got %.3f", i, tc.wantEnergy[i], arc.Smoothed[i].Energy)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaylist_Arc_Empty(t *testing.T) {
+	arc := Playlist{}.Arc(3)
+	if arc.Tracks == nil || arc.Smoothed == nil || len(arc.Tracks) != 0 {
+		t.Fatalf("expected empty series, got %+v", arc)
+	}
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Artist is provider metadata about a performer, cached so repeated lookups
+// of the same name don't hit the provider again.
+type Artist struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Genres     []string  `json:"genres"`
+	ImageURL   string    `json:"image_url,omitempty"`
+	Popularity int       `json:"popularity"`
+	FetchedAt  time.Time `json:"fetched_at"`
+}
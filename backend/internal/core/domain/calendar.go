@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrNoFocusBlock is returned when a focus automation is triggered outside
+// a focus block.
+var ErrNoFocusBlock = errors.New("no focus block in progress")
+
+// DefaultFocusKeywords mark calendar events as focus blocks unless
+// configured otherwise.
+var DefaultFocusKeywords = []string{"focus", "deep work", "heads down", "no meetings"}
+
+// CalendarEvent is a single occurrence of a calendar event. Recurring
+// events appear once per occurrence.
+type CalendarEvent struct {
+	Summary string    `json:"summary"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// Covers reports whether the event is in progress at t.
+func (e CalendarEvent) Covers(t time.Time) bool {
+	return !t.Before(e.Start) && t.Before(e.End)
+}
+
+// IsFocus reports whether the event's summary contains one of keywords,
+// ignoring case.
+func (e CalendarEvent) IsFocus(keywords []string) bool {
+	summary := strings.ToLower(e.Summary)
+	for _, k := range keywords {
+		if k != "" && strings.Contains(summary, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// FocusBlock returns the focus block in progress at t, if any. When blocks
+// overlap, the one ending last wins.
+func FocusBlock(events []CalendarEvent, keywords []string, t time.Time) (CalendarEvent, bool) {
+	var block CalendarEvent
+	found := false
+	for _, e := range events {
+		if !e.Covers(t) || !e.IsFocus(keywords) {
+			continue
+		}
+		if !found || e.End.After(block.End) {
+			block = e
+			found = true
+		}
+	}
+	return block, found
+}
+
+// ApplyFocus fills the instrumentalness and energy an intent leaves open
+// with focus-friendly ranges: mostly instrumental and low energy. It
+// reports whether anything was filled in.
+func ApplyFocus(intent *IntentObject) bool {
+	vc := &intent.VibeConstraints
+	applied := false
+	if vc.Instrument == nil {
+		vc.Instrument = &VibeConstraint{Min: 0.5, Max: 1, Weight: "high"}
+		applied = true
+	}
+	if vc.Energy == nil {
+		vc.Energy = &VibeConstraint{Min: 0, Max: 0.5, Weight: "medium"}
+		applied = true
+	}
+	return applied
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFocusBlock(t *testing.T) {
+	at := func(hour, min int) time.Time { return time.Date(2026, 3, 2, hour, min, 0, 0, time.UTC) }
+	events := []CalendarEvent{
+		{Summary: "Standup", Start: at(9, 0), End: at(9, 15)},
+		{Summary: "Deep Work: parser rewrite", Start: at(9, 30), End: at(11, 0)},
+		{Summary: "FOCUS", Start: at(10, 30), End: at(12, 0)},
+		{Summary: "Lunch", Start: at(12, 0), End: at(13, 0)},
+	}
+
+	tests := []struct {
+		name        string
+		keywords    []string
+		at          time.Time
+		wantFound   bool
+		wantSummary string
+	}{
+		{name: "meeting", keywords: DefaultFocusKeywords, at: at(9, 5)},
+		{name: "focus block", keywords: DefaultFocusKeywords, at: at(10, 0), wantFound: true, wantSummary: "Deep Work: parser rewrite"},
+		{name: "overlap takes the later end", keywords: DefaultFocusKeywords, at: at(10, 45), wantFound: true, wantSummary: "FOCUS"},
+		{name: "end is exclusive", keywords: DefaultFocusKeywords, at: at(12, 0)},
+		{name: "custom keywords", keywords: []string{"lunch"}, at: at(12, 30), wantFound: true, wantSummary: "Lunch"},
+		{name: "no keywords", at: at(10, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, found := FocusBlock(events, tt.keywords, tt.at)
+			if found != tt.wantFound {
+				t.Fatalf("expected found=%v, got %v (%+v)", tt.wantFound, found, block)
+			}
+			if block.Summary != tt.wantSummary {
+				t.Fatalf("expected %q, got %q", tt.wantSummary, block.Summary)
+			}
+		})
+	}
+}
+
+func TestApplyFocus(t *testing.T) {
+	var intent IntentObject
+	if !ApplyFocus(&intent) {
+		t.Fatal("expected open constraints to be filled")
+	}
+	vc := intent.VibeConstraints
+	if vc.Instrument == nil || vc.Instrument.Min != 0.5 {
+		t.Fatalf("expected instrumental bias, got %+v", vc.Instrument)
+	}
+	if vc.Energy == nil || vc.Energy.Max != 0.5 {
+		t.Fatalf("expected low energy, got %+v", vc.Energy)
+	}
+
+	// Explicit constraints win.
+	intent = IntentObject{}
+	intent.VibeConstraints.Instrument = &VibeConstraint{Min: 0, Max: 0.2}
+	intent.VibeConstraints.Energy = &VibeConstraint{Min: 0.8, Max: 1}
+	if ApplyFocus(&intent) {
+		t.Fatal("expected explicit constraints to be kept")
+	}
+	if intent.VibeConstraints.Energy.Min != 0.8 {
+		t.Fatalf("explicit energy overwritten: %+v", intent.VibeConstraints.Energy)
+	}
+}
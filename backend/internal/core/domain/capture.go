@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// IntentCapture records one exchange with the intent compiler for debugging:
+// the exact prompt sent and the raw response received, after redaction.
+type IntentCapture struct {
+	ID         string    `json:"id"`
+	Model      string    `json:"model"`
+	Prompt     string    `json:"prompt"`
+	Response   string    `json:"response"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// MaxCooccurrencePlaylist is the largest playlist counted by the
+// co-occurrence model. Huge playlists pair everything with everything and
+// say little about which tracks belong together.
+const MaxCooccurrencePlaylist = 500
+
+// TrackPair records that OtherID was added to Together of the playlists
+// TrackID is in. Score normalizes Together by how common both tracks are
+// (cosine similarity), so ubiquitous tracks don't dominate.
+type TrackPair struct {
+	TrackID  string
+	OtherID  string
+	Together int
+	Score    float64
+}
+
+// Recommendation is a track suggested for its co-occurrence with others.
+type Recommendation struct {
+	Track    Track   `json:"track"`
+	Together int     `json:"together"`
+	Score    float64 `json:"score"`
+}
+
+// BuildCooccurrence computes the item-to-item co-occurrence model over the
+// track IDs of each playlist, keeping the maxNeighbors best-scored pairs
+// per track (all when maxNeighbors <= 0). Pairs are ordered by track, then
+// best first.
+func BuildCooccurrence(playlists [][]string, maxNeighbors int) []TrackPair {
+	freq := make(map[string]int)
+	together := make(map[[2]string]int)
+	for _, tracks := range playlists {
+		ids := dedupe(tracks)
+		if len(ids) < 2 || len(ids) > MaxCooccurrencePlaylist {
+			continue
+		}
+		for i, a := range ids {
+			freq[a]++
+			for _, b := range ids[i+1:] {
+				together[[2]string{a, b}]++
+				together[[2]string{b, a}]++
+			}
+		}
+	}
+
+	byTrack := make(map[string][]TrackPair)
+	for k, n := range together {
+		byTrack[k[0]] = append(byTrack[k[0]], TrackPair{
+			TrackID:  k[0],
+			OtherID:  k[1],
+			Together: n,
+			Score:    float64(n) / math.Sqrt(float64(freq[k[0]]*freq[k[1]])),
+		})
+	}
+
+	ids := make([]string, 0, len(byTrack))
+	for id := range byTrack {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var pairs []TrackPair
+	for _, id := range ids {
+		neighbors := byTrack[id]
+		sort.Slice(neighbors, func(i, j int) bool {
+			a, b := neighbors[i], neighbors[j]
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+			if a.Together != b.Together {
+				return a.Together > b.Together
+			}
+			return a.OtherID < b.OtherID
+		})
+		if maxNeighbors > 0 && len(neighbors) > maxNeighbors {
+			neighbors = neighbors[:maxNeighbors]
+		}
+		pairs = append(pairs, neighbors...)
+	}
+	return pairs
+}
+
+// dedupe returns ids without repeats, sorted.
+func dedupe(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBuildCooccurrence(t *testing.T) {
+	playlists := [][]string{
+		{"a", "b", "c"},
+		{"a", "b"},
+		{"a", "d", "d"}, // repeats count once
+		{"e"},           // nothing to pair with
+	}
+	pairs := BuildCooccurrence(playlists, 0)
+
+	got := make(map[[2]string]TrackPair)
+	for _, p := range pairs {
+		got[[2]string{p.TrackID, p.OtherID}] = p
+	}
+	if len(got) != 8 {
+		t.Fatalf("expected 8 directed pairs, got %d: %+v", len(got), pairs)
+	}
+	ab := got[[2]string{"a", "b"}]
+	// a is in 3 playlists, b in 2, together in 2.
+	if ab.Together != 2 || math.Abs(ab.Score-2/math.Sqrt(6)) > 1e-9 {
+		t.Fatalf("unexpected a-b pair %+v", ab)
+	}
+	if ba := got[[2]string{"b", "a"}]; ba.Together != 2 || ba.Score != ab.Score {
+		t.Fatalf("expected a symmetric pair, got %+v", ba)
+	}
+	if pairs[0].TrackID != "a" || pairs[0].OtherID != "b" {
+		t.Fatalf("expected a's best neighbor first, got %+v", pairs[0])
+	}
+
+	limited := BuildCooccurrence(playlists, 1)
+	perTrack := make(map[string]int)
+	for _, p := range limited {
+		perTrack[p.TrackID]++
+	}
+	for id, n := range perTrack {
+		if n != 1 {
+			t.Fatalf("expected one neighbor for %s, got %d", id, n)
+		}
+	}
+}
+
+func TestBuildCooccurrence_SkipsHugePlaylists(t *testing.T) {
+	huge := make([]string, MaxCooccurrencePlaylist+1)
+	for i := range huge {
+		huge[i] = string(rune('A'+i%26)) + string(rune('a'+i/26%26)) + string(rune('0'+i/676))
+	}
+	if pairs := BuildCooccurrence([][]string{huge}, 0); len(pairs) != 0 {
+		t.Fatalf("expected no pairs from a huge playlist, got %d", len(pairs))
+	}
+}
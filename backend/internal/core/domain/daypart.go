@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Daypart holds the vibe defaults for part of the day, from StartHour until
+// the next daypart starts. Intents that leave energy or valence open take
+// them from the daypart, so "play something" differs at 7am and 11pm.
+type Daypart struct {
+	Name      string          `json:"name"`
+	StartHour int             `json:"start_hour"`
+	Energy    *VibeConstraint `json:"energy,omitempty"`
+	Valence   *VibeConstraint `json:"valence,omitempty"`
+}
+
+// DaypartPresets maps the hours of the day to dayparts.
+type DaypartPresets []Daypart
+
+// DefaultDayparts returns the presets used unless configured otherwise.
+func DefaultDayparts() DaypartPresets {
+	return DaypartPresets{
+		{Name: "morning", StartHour: 5, Energy: &VibeConstraint{Min: 0.3, Max: 0.7, Weight: "medium"}, Valence: &VibeConstraint{Min: 0.5, Max: 1, Weight: "medium"}},
+		{Name: "daytime", StartHour: 10, Energy: &VibeConstraint{Min: 0.5, Max: 0.9, Weight: "medium"}},
+		{Name: "evening", StartHour: 18, Energy: &VibeConstraint{Min: 0.3, Max: 0.7, Weight: "medium"}},
+		{Name: "night", StartHour: 22, Energy: &VibeConstraint{Min: 0, Max: 0.4, Weight: "medium"}, Valence: &VibeConstraint{Min: 0, Max: 0.6, Weight: "low"}},
+	}
+}
+
+// Validate reports whether the presets cover the day unambiguously.
+func (p DaypartPresets) Validate() error {
+	if len(p) == 0 {
+		return errors.New("at least one daypart is required")
+	}
+	seen := make(map[int]bool, len(p))
+	for _, d := range p {
+		if d.Name == "" {
+			return errors.New("daypart name is required")
+		}
+		if d.StartHour < 0 || d.StartHour > 23 {
+			return fmt.Errorf("daypart %q: start_hour must be between 0 and 23", d.Name)
+		}
+		if seen[d.StartHour] {
+			return fmt.Errorf("daypart %q: another daypart starts at %d", d.Name, d.StartHour)
+		}
+		seen[d.StartHour] = true
+	}
+	return nil
+}
+
+// At returns the daypart covering hour (0-23): the one that started last.
+// Hours before the earliest start belong to the latest daypart, which runs
+// past midnight. The presets must be valid.
+func (p DaypartPresets) At(hour int) Daypart {
+	sorted := append(DaypartPresets{}, p...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartHour < sorted[j].StartHour })
+	current := sorted[len(sorted)-1]
+	for _, d := range sorted {
+		if d.StartHour <= hour {
+			current = d
+		}
+	}
+	return current
+}
+
+// Apply fills the energy and valence constraints intent leaves open with
+// the daypart's. It reports whether anything was filled in.
+func (d Daypart) Apply(intent *IntentObject) bool {
+	applied := false
+	if intent.VibeConstraints.Energy == nil && d.Energy != nil {
+		c := *d.Energy
+		intent.VibeConstraints.Energy = &c
+		applied = true
+	}
+	if intent.VibeConstraints.Valence == nil && d.Valence != nil {
+		c := *d.Valence
+		intent.VibeConstraints.Valence = &c
+		applied = true
+	}
+	return applied
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "testing"
+
+func TestDaypartPresets_At(t *testing.T) {
+	presets := DefaultDayparts()
+	if err := presets.Validate(); err != nil {
+		t.Fatalf("default presets invalid: %v", err)
+	}
+	tests := []struct {
+		hour int
+		want string
+	}{
+		{hour: 0, want: "night"},
+		{hour: 4, want: "night"},
+		{hour: 5, want: "morning"},
+		{hour: 7, want: "morning"},
+		{hour: 12, want: "daytime"},
+		{hour: 18, want: "evening"},
+		{hour: 23, want: "night"},
+	}
+	for _, tt := range tests {
+		if got := presets.At(tt.hour); got.Name != tt.want {
+			t.Errorf("hour %d: expected %s, got %s", tt.hour, tt.want, got.Name)
+		}
+	}
+}
+
+func TestDaypartPresets_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		presets DaypartPresets
+		wantErr bool
+	}{
+		{name: "single", presets: DaypartPresets{{Name: "all day", StartHour: 0}}},
+		{name: "empty", presets: DaypartPresets{}, wantErr: true},
+		{name: "unnamed", presets: DaypartPresets{{StartHour: 3}}, wantErr: true},
+		{name: "hour out of range", presets: DaypartPresets{{Name: "late", StartHour: 24}}, wantErr: true},
+		{name: "same start", presets: DaypartPresets{{Name: "a", StartHour: 6}, {Name: "b", StartHour: 6}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.presets.Validate(); tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDaypart_Apply(t *testing.T) {
+	night := DefaultDayparts().At(23)
+
+	var open IntentObject
+	if !night.Apply(&open) {
+		t.Fatal("expected open intent to take the daypart's constraints")
+	}
+	if open.VibeConstraints.Energy == nil || open.VibeConstraints.Energy.Max != 0.4 || open.VibeConstraints.Valence == nil {
+		t.Fatalf("unexpected constraints %+v", open.VibeConstraints)
+	}
+	open.VibeConstraints.Energy.Max = 1
+	if night.Energy.Max != 0.4 {
+		t.Fatal("expected Apply to copy the preset's constraints")
+	}
+
+	var explicit IntentObject
+	explicit.VibeConstraints.Energy = &VibeConstraint{Min: 0.8, Max: 1}
+	explicit.VibeConstraints.Valence = &VibeConstraint{Min: 0.1, Max: 0.2}
+	if night.Apply(&explicit) {
+		t.Fatal("expected explicit constraints to be kept")
+	}
+	if explicit.VibeConstraints.Energy.Min != 0.8 {
+		t.Fatalf("explicit energy overwritten: %+v", explicit.VibeConstraints.Energy)
+	}
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidSort is returned for discovery orderings other than the
+// DiscoverSort values.
+var ErrInvalidSort = errors.New("domain: invalid sort")
+
+// TrendingWindow is how far back plays count towards a public playlist
+// trending.
+const TrendingWindow = 7 * 24 * time.Hour
+
+// DiscoverSort orders the public playlists listed for discovery.
+type DiscoverSort string
+
+const (
+	// DiscoverRecent lists the most recently published playlists first.
+	DiscoverRecent DiscoverSort = "recent"
+	// DiscoverCopied lists the playlists copied most often first.
+	DiscoverCopied DiscoverSort = "copied"
+	// DiscoverTrending lists the playlists played most within the
+	// TrendingWindow first.
+	DiscoverTrending DiscoverSort = "trending"
+)
+
+// ParseDiscoverSort resolves a sort name, defaulting to DiscoverRecent.
+func ParseDiscoverSort(s string) (DiscoverSort, error) {
+	switch DiscoverSort(s) {
+	case "", DiscoverRecent:
+		return DiscoverRecent, nil
+	case DiscoverCopied, DiscoverTrending:
+		return DiscoverSort(s), nil
+	}
+	return "", ErrInvalidSort
+}
+
+// PublicPlaylist is a playlist its owner opted to share with everyone on
+// the instance, with the numbers it is ranked by.
+type PublicPlaylist struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Owner       string    `json:"owner"`
+	TrackCount  int       `json:"track_count"`
+	PublishedAt time.Time `json:"published_at"`
+	// Copies counts how often the playlist was copied into a library.
+	Copies int `json:"copies"`
+	// Plays counts the tracks played from the playlist within the
+	// TrendingWindow.
+	Plays int `json:"plays"`
+}
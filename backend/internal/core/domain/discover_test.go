@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDiscoverSort(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    DiscoverSort
+		wantErr error
+	}{
+		{in: "", want: DiscoverRecent},
+		{in: "recent", want: DiscoverRecent},
+		{in: "copied", want: DiscoverCopied},
+		{in: "trending", want: DiscoverTrending},
+		{in: "popular", wantErr: ErrInvalidSort},
+		{in: "Recent", wantErr: ErrInvalidSort},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseDiscoverSort(tc.in)
+			if !errors.Is(err, tc.wantErr) || got != tc.want {
+				t.Fatalf("ParseDiscoverSort(%q) = %q, %v; want %q, %v", tc.in, got, err, tc.want, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/match"
+)
+
+// ErrDuplicateTrack is returned when a playlist already has the same song
+// under another ID, such as a remastered release of it.
+var ErrDuplicateTrack = errors.New("domain: duplicate track")
+
+// SameSong reports whether t and o are releases of the same song: the same
+// ID or ISRC, or the same title once version suffixes such as
+// " - Remastered 2011" are trimmed by the same artist, so
+// "Song (Remastered)" matches "Song" even though remasters get ISRCs of
+// their own. Episodes are only the same by ID.
+func (t Track) SameSong(o Track) bool {
+	if t.ID != "" && t.ID == o.ID {
+		return true
+	}
+	if t.IsEpisode() || o.IsEpisode() {
+		return false
+	}
+	if t.ISRC != "" && strings.EqualFold(t.ISRC, o.ISRC) {
+		return true
+	}
+	return match.Default.Same(t.Title, t.Artist, o.Title, o.Artist)
+}
+
+// NewSongs returns tracks, in order, that are not the same song as one in
+// existing or as an earlier one in tracks, so a bulk add keeps the first
+// release of each song it is offered.
+func NewSongs(tracks, existing []Track) []Track {
+	kept := make([]Track, 0, len(tracks))
+	for _, t := range tracks {
+		if sameSongIn(t, existing) || sameSongIn(t, kept) {
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+func sameSongIn(t Track, tracks []Track) bool {
+	for _, o := range tracks {
+		if t.SameSong(o) {
+			return true
+		}
+	}
+	return false
+}
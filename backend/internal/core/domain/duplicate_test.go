@@ -0,0 +1,48 @@
+package domain
+
+import "testing"
+
+func TestTrack_SameSong(t *testing.T) {
+	song := Track{ID: "t1", Title: "Song", Artist: "Artist A", ISRC: "USAAA0000001"}
+	tests := []struct {
+		name  string
+		other Track
+		want  bool
+	}{
+		{name: "same ID", other: Track{ID: "t1"}, want: true},
+		{name: "same ISRC in another case", other: Track{ID: "t2", ISRC: "usaaa0000001"}, want: true},
+		{name: "remaster with its own ISRC", other: Track{ID: "t2", Title: "Song (Remastered 2011)", Artist: "Artist A", ISRC: "USAAA1100001"}, want: true},
+		{name: "dash suffix without ISRC", other: Track{ID: "t2", Title: "Song - Radio Edit", Artist: "artist a"}, want: true},
+		{name: "another song", other: Track{ID: "t2", Title: "Song Two", Artist: "Artist A"}, want: false},
+		{name: "different song with a noise word", other: Track{ID: "t2", Title: "Live Song", Artist: "Artist A"}, want: false},
+		{name: "cover by another artist", other: Track{ID: "t2", Title: "Song", Artist: "Artist B"}, want: false},
+		{name: "nothing to compare", other: Track{ID: "t2"}, want: false},
+		{name: "episode of the same name", other: Track{ID: "e1", Title: "Song", Artist: "Artist A", Type: ItemEpisode}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := song.SameSong(tt.other); got != tt.want {
+				t.Fatalf("SameSong(%+v) = %v, want %v", tt.other, got, tt.want)
+			}
+			if got := tt.other.SameSong(song); got != tt.want {
+				t.Fatalf("SameSong is not symmetric for %+v", tt.other)
+			}
+		})
+	}
+}
+
+func TestNewSongs(t *testing.T) {
+	existing := []Track{{ID: "t1", Title: "Song", Artist: "Artist A"}}
+	tracks := []Track{
+		{ID: "t2", Title: "Song (Remastered 2011)", Artist: "Artist A"},
+		{ID: "t3", Title: "Other", Artist: "Artist A", ISRC: "USAAA0000003"},
+		{ID: "t4", Title: "Other - Radio Edit", Artist: "Artist A"},
+		{ID: "t5", Title: "Another", Artist: "Artist A", ISRC: "usaaa0000003"},
+		{ID: "t6", Title: "Song Two", Artist: "Artist A"},
+		{ID: "t6"},
+	}
+	got := NewSongs(tracks, existing)
+	if len(got) != 2 || got[0].ID != "t3" || got[1].ID != "t6" {
+		t.Fatalf("expected t3 and t6, got %+v", got)
+	}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// ItemType discriminates the kinds of content a playlist holds.
+type ItemType string
+
+const (
+	ItemTrack   ItemType = "track"
+	ItemEpisode ItemType = "episode"
+)
+
+// Episode represents a single podcast episode.
+type Episode struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Show        string    `json:"show"`
+	Description string    `json:"description"`
+	DurationMs  int       `json:"duration_ms"`
+	PublishedAt time.Time `json:"published_at"`
+	CoverURL    string    `json:"cover_url"`
+	PreviewURL  string    `json:"preview_url"`
+	Explicit    bool      `json:"explicit"`
+}
+
+// Item returns the episode as a playlist item. The show takes the place of
+// the artist, and an episode has no audio features.
+func (e Episode) Item() Track {
+	published := e.PublishedAt
+	return Track{
+		ID:          e.ID,
+		Type:        ItemEpisode,
+		Title:       e.Title,
+		Artist:      e.Show,
+		CoverURL:    e.CoverURL,
+		PreviewURL:  e.PreviewURL,
+		DurationMs:  e.DurationMs,
+		Description: e.Description,
+		PublishedAt: &published,
+		Explicit:    e.Explicit,
+	}
+}
+
+// IsEpisode reports whether the playlist item is a podcast episode.
+func (t Track) IsEpisode() bool {
+	return t.Type == ItemEpisode
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types emitted when playlists or their playback change.
+const (
+	// EventPlaylistSaved is emitted when a playlist is created or replaced.
+	EventPlaylistSaved = "playlist.saved"
+	// EventTracksAdded is emitted when tracks are appended to a playlist.
+	EventTracksAdded = "playlist.tracks_added"
+	// EventTracksReordered is emitted when a playlist's tracks are
+	// rearranged.
+	EventTracksReordered = "playlist.tracks_reordered"
+	// EventPlaybackUpdated is emitted when a playlist's playback state
+	// changes; its Payload is the new PlaybackState.
+	EventPlaybackUpdated = "playback.updated"
+	// EventFeaturesUpdated is emitted when a track's audio analysis
+	// finishes. It concerns a track rather than a playlist, so PlaylistID
+	// is empty; its Payload is a FeaturesUpdatedPayload.
+	EventFeaturesUpdated = "track.features_updated"
+	// EventAnalysisFailed is emitted when a track's audio analysis fails
+	// for good; its Payload is an AnalysisFailedPayload.
+	EventAnalysisFailed = "track.analysis_failed"
+	// EventIntentProcessed is emitted when an intent has been applied to a
+	// playlist; its Payload is an IntentProcessedPayload.
+	EventIntentProcessed = "intent.processed"
+)
+
+// Event describes a change to the domain that other subsystems may react to.
+type Event struct {
+	// ID uniquely identifies the event so consumers can deduplicate redeliveries.
+	ID string `json:"id"`
+	// Type is one of the Event* constants.
+	Type string `json:"type"`
+	// PlaylistID is the playlist the event concerns, if any.
+	PlaylistID string `json:"playlist_id,omitempty"`
+	// Payload carries type-specific details as JSON.
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// OccurredAt is when the change was committed.
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// TracksAddedPayload is the Payload of an EventTracksAdded event.
+type TracksAddedPayload struct {
+	TrackIDs []string `json:"track_ids"`
+}
+
+// TracksReorderedPayload is the Payload of an EventTracksReordered event,
+// listing the playlist's tracks in their new order.
+type TracksReorderedPayload struct {
+	TrackIDs []string `json:"track_ids"`
+}
+
+// PlaylistSavedPayload is the Payload of an EventPlaylistSaved event.
+type PlaylistSavedPayload struct {
+	Name       string `json:"name"`
+	TrackCount int    `json:"track_count"`
+}
+
+// FeaturesUpdatedPayload is the Payload of an EventFeaturesUpdated event.
+type FeaturesUpdatedPayload struct {
+	TrackID  string        `json:"track_id"`
+	Features AudioFeatures `json:"features"`
+}
+
+// AnalysisFailedPayload is the Payload of an EventAnalysisFailed event.
+type AnalysisFailedPayload struct {
+	TrackID  string `json:"track_id"`
+	Error    string `json:"error"`
+	Attempts int    `json:"attempts"`
+}
+
+// IntentProcessedPayload is the Payload of an EventIntentProcessed event.
+type IntentProcessedPayload struct {
+	IntentType      string `json:"intent_type"`
+	TracksEvaluated int    `json:"tracks_evaluated"`
+	TracksAdded     int    `json:"tracks_added"`
+	Summary         string `json:"summary"`
+}
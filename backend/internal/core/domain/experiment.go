@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+)
+
+// ScoringConfig tunes how intent candidates that pass the vibe check are
+// ranked and accepted.
+type ScoringConfig struct {
+	// RankByTarget orders selections by weighted distance to the
+	// constraint targets, closest first.
+	RankByTarget bool `json:"rank_by_target,omitempty"`
+	// Threshold drops candidates whose weighted target distance exceeds it.
+	// Zero disables the cut.
+	Threshold float64        `json:"threshold,omitempty"`
+	Weights   FeatureWeights `json:"weights,omitempty"`
+}
+
+// FeatureWeights scales each feature's contribution to the target distance.
+// A zero weight counts as 1.
+type FeatureWeights struct {
+	Energy           float64 `json:"energy,omitempty"`
+	Valence          float64 `json:"valence,omitempty"`
+	Acousticness     float64 `json:"acousticness,omitempty"`
+	Instrumentalness float64 `json:"instrumentalness,omitempty"`
+}
+
+// Variant is one arm of an experiment. Weight is its relative share of
+// assignments.
+type Variant struct {
+	Name    string        `json:"name"`
+	Weight  int           `json:"weight"`
+	Scoring ScoringConfig `json:"scoring"`
+}
+
+// Experiment splits intent runs between scoring variants.
+type Experiment struct {
+	Name     string    `json:"name"`
+	Variants []Variant `json:"variants"`
+}
+
+// Validate reports whether the experiment can assign runs.
+func (e Experiment) Validate() error {
+	if e.Name == "" {
+		return errors.New("experiment name is required")
+	}
+	if len(e.Variants) == 0 {
+		return errors.New("experiment needs at least one variant")
+	}
+	seen := make(map[string]bool, len(e.Variants))
+	total := 0
+	for _, v := range e.Variants {
+		if v.Name == "" {
+			return errors.New("variant name is required")
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("duplicate variant %q", v.Name)
+		}
+		seen[v.Name] = true
+		if v.Weight < 0 || v.Scoring.Threshold < 0 {
+			return fmt.Errorf("variant %q: weight and threshold must not be negative", v.Name)
+		}
+		total += v.Weight
+	}
+	if total == 0 {
+		return errors.New("experiment variants need a positive total weight")
+	}
+	return nil
+}
+
+// Assign deterministically picks the variant for key, in proportion to the
+// variant weights. The experiment must be valid.
+func (e Experiment) Assign(key string) Variant {
+	total := 0
+	for _, v := range e.Variants {
+		total += v.Weight
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(e.Name + "/" + key))
+	n := int(h.Sum64() % uint64(total))
+	for _, v := range e.Variants {
+		if n < v.Weight {
+			return v
+		}
+		n -= v.Weight
+	}
+	return e.Variants[len(e.Variants)-1]
+}
+
+// VariantReport aggregates the outcome of the runs assigned to one variant.
+// A track added by a run counts as kept while it is still in the playlist.
+type VariantReport struct {
+	Experiment      string `json:"experiment"`
+	Variant         string `json:"variant"`
+	Runs            int    `json:"runs"`
+	TracksEvaluated int    `json:"tracks_evaluated"`
+	TracksAdded     int    `json:"tracks_added"`
+	TracksKept      int    `json:"tracks_kept"`
+	// AddRate is TracksAdded / TracksEvaluated.
+	AddRate float64 `json:"add_rate"`
+	// AcceptanceRate is TracksKept / TracksAdded.
+	AcceptanceRate float64 `json:"acceptance_rate"`
+}
+
+// ExperimentReport compares variants over recorded intent runs.
+type ExperimentReport struct {
+	Runs     int             `json:"runs"`
+	Variants []VariantReport `json:"variants"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExperiment_Validate(t *testing.T) {
+	control := Variant{Name: "control", Weight: 1}
+	tests := []struct {
+		name    string
+		exp     Experiment
+		wantErr bool
+	}{
+		{name: "valid", exp: Experiment{Name: "thresholds", Variants: []Variant{control, {Name: "strict", Weight: 1, Scoring: ScoringConfig{Threshold: 0.2}}}}},
+		{name: "missing name", exp: Experiment{Variants: []Variant{control}}, wantErr: true},
+		{name: "no variants", exp: Experiment{Name: "x"}, wantErr: true},
+		{name: "duplicate variant", exp: Experiment{Name: "x", Variants: []Variant{control, control}}, wantErr: true},
+		{name: "negative threshold", exp: Experiment{Name: "x", Variants: []Variant{{Name: "a", Weight: 1, Scoring: ScoringConfig{Threshold: -1}}}}, wantErr: true},
+		{name: "zero total weight", exp: Experiment{Name: "x", Variants: []Variant{{Name: "a"}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.exp.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("expected err=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestExperiment_Assign(t *testing.T) {
+	exp := Experiment{Name: "thresholds", Variants: []Variant{
+		{Name: "control", Weight: 3},
+		{Name: "strict", Weight: 1},
+		{Name: "off", Weight: 0},
+	}}
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		key := fmt.Sprintf("pl-%d", i)
+		v := exp.Assign(key)
+		if again := exp.Assign(key); again.Name != v.Name {
+			t.Fatalf("assignment for %q not stable: %s then %s", key, v.Name, again.Name)
+		}
+		counts[v.Name]++
+	}
+	if counts["off"] != 0 {
+		t.Fatalf("zero-weight variant was assigned %d times", counts["off"])
+	}
+	if counts["control"] < 2700 || counts["control"] > 3300 {
+		t.Fatalf("expected roughly 3:1 split, got %v", counts)
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ExportFormat identifies Overture's portable export document.
+const ExportFormat = "overture.export"
+
+// ExportVersion is bumped whenever the export document changes incompatibly.
+const ExportVersion = 1
+
+// Export is a portable snapshot of everything the deployment stores for its
+// user, suitable for data-portability requests and re-import elsewhere.
+type Export struct {
+	Format     string     `json:"format"`
+	Version    int        `json:"version"`
+	ExportedAt time.Time  `json:"exported_at"`
+	Playlists  []Playlist `json:"playlists"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "errors"
+
+// ErrDuplicateRecording is returned when a track is the same recording as
+// one already in the playlist, even under a different ID or ISRC.
+var ErrDuplicateRecording = errors.New("domain: duplicate recording")
+
+// TrackFingerprint is the acoustic fingerprint of a track's preview and the
+// recording it was resolved to. RecordingID is the ID of the first track
+// fingerprinted as that recording.
+type TrackFingerprint struct {
+	TrackID     string
+	RecordingID string
+	Fingerprint []uint32
+}
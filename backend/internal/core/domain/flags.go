@@ -0,0 +1,8 @@
+package domain
+
+// Feature flags gating experimental behavior. Every flag defaults to off.
+const (
+	// FlagTargetScoring ranks intent candidates by distance to the vibe
+	// constraint targets instead of keeping provider order.
+	FlagTargetScoring = "target_scoring"
+)
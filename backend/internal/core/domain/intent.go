@@ -1,5 +1,12 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type VibeConstraint struct {
 	Target float64 `json:"target,omitempty"`
 	Min    float64 `json:"min,omitempty"`
@@ -19,9 +26,319 @@ type IntentObject struct {
 		Acoustic   *VibeConstraint `json:"acousticness,omitempty"`
 		Instrument *VibeConstraint `json:"instrumentalness,omitempty"`
 	} `json:"vibe_constraints"`
+	// Era and Popularity limit tracks by their catalog metadata rather than
+	// how they sound, as in "90s only" or "deep cuts only".
+	Era        *EraConstraint        `json:"era,omitempty"`
+	Popularity *PopularityConstraint `json:"popularity,omitempty"`
+	// Duration asks for the playlist to run about this long, as in "a
+	// 45-minute running mix".
+	Duration *DurationConstraint `json:"duration,omitempty"`
 	Sequence struct {
 		Pattern     string `json:"pattern"`
 		Description string `json:"description"`
 	} `json:"sequence"`
 	Explanation string `json:"explanation"`
 }
+
+// EraConstraint limits tracks to those released from MinYear to MaxYear; a
+// zero bound is open. Decade, such as "90s" or "1980s", stands for its ten
+// years when neither bound is set.
+type EraConstraint struct {
+	MinYear int    `json:"min_year,omitempty"`
+	MaxYear int    `json:"max_year,omitempty"`
+	Decade  string `json:"decade,omitempty"`
+}
+
+// Years returns the release years the constraint allows, resolving Decade.
+// ok is false when Decade cannot be read.
+func (e EraConstraint) Years() (minYear, maxYear int, ok bool) {
+	if e.MinYear != 0 || e.MaxYear != 0 || e.Decade == "" {
+		return e.MinYear, e.MaxYear, true
+	}
+	start, ok := decadeStart(e.Decade)
+	if !ok {
+		return 0, 0, false
+	}
+	return start, start + 9, true
+}
+
+// Matches reports whether a track released in year is within the era. An
+// era without bounds allows every track; otherwise tracks without a known
+// release year are left out.
+func (e EraConstraint) Matches(year int) bool {
+	minYear, maxYear, _ := e.Years()
+	if minYear == 0 && maxYear == 0 {
+		return true
+	}
+	if year == 0 {
+		return false
+	}
+	return year >= minYear && (maxYear == 0 || year <= maxYear)
+}
+
+// decadeStart reads decades such as "1990s", "90s", "'90s" or "1990". Two
+// digit decades from 30 up are taken to be the 1900s, the rest the 2000s.
+func decadeStart(decade string) (int, bool) {
+	d := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(decade), "'"), "s")
+	n, err := strconv.Atoi(d)
+	if err != nil || n < 0 || n%10 != 0 {
+		return 0, false
+	}
+	switch len(d) {
+	case 2:
+		if n >= 30 {
+			return 1900 + n, true
+		}
+		return 2000 + n, true
+	case 4:
+		return n, true
+	}
+	return 0, false
+}
+
+// MaxPopularity is the top of the catalog's popularity scale.
+const MaxPopularity = 100
+
+// PopularityConstraint bounds tracks' catalog popularity from 0 to
+// MaxPopularity, such as a Max of 30 for "deep cuts only" or a Min of 70
+// for "hits only". A zero Max is unset.
+type PopularityConstraint struct {
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+}
+
+// Matches reports whether a track with popularity is within the bounds.
+func (p PopularityConstraint) Matches(popularity int) bool {
+	return popularity >= p.Min && (p.Max == 0 || popularity <= p.Max)
+}
+
+// IntentDelta is a piece of a streaming intent compiler's output as it is
+// generated: Thinking is the model's reasoning, Content part of the intent
+// JSON.
+type IntentDelta struct {
+	Thinking string `json:"thinking,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// DefaultDurationTolerance is how far a duration-targeted playlist may end
+// up from its target when DurationTarget.ToleranceMs is unset.
+const DefaultDurationTolerance = 2 * 60 * 1000
+
+// DurationTarget asks intent processing to fill a playlist to a total
+// length ("fill 45 minutes") instead of adding every matching track.
+type DurationTarget struct {
+	DurationMs  int
+	ToleranceMs int
+}
+
+// Tolerance returns ToleranceMs, or DefaultDurationTolerance when unset.
+func (d DurationTarget) Tolerance() int {
+	if d.ToleranceMs > 0 {
+		return d.ToleranceMs
+	}
+	return DefaultDurationTolerance
+}
+
+// MaxDurationMinutes caps DurationConstraint.Minutes at a day.
+const MaxDurationMinutes = 24 * 60
+
+// DurationConstraint is the length an intent asks the playlist to be, in
+// minutes since that is how requests put it.
+type DurationConstraint struct {
+	Minutes int `json:"minutes"`
+	// ToleranceMinutes is how far off the playlist may end up; zero uses
+	// DefaultDurationTolerance.
+	ToleranceMinutes int `json:"tolerance_minutes,omitempty"`
+}
+
+// Target returns the constraint as a DurationTarget.
+func (d DurationConstraint) Target() DurationTarget {
+	return DurationTarget{DurationMs: d.Minutes * 60 * 1000, ToleranceMs: d.ToleranceMinutes * 60 * 1000}
+}
+
+// MaxExpandSimilar caps IntentOptions.ExpandSimilar.
+const MaxExpandSimilar = 10
+
+// IntentOptions tune how an intent is processed beyond what its message
+// says.
+type IntentOptions struct {
+	Duration DurationTarget
+	// ExpandSimilar adds up to this many artists similar to each one the
+	// intent names to the candidate pool; zero uses only the named ones.
+	ExpandSimilar int
+	// SessionID continues the conversational session with this ID, so the
+	// message refines the session's earlier intents; empty starts afresh
+	// without recording a session.
+	SessionID string
+}
+
+// IntentResult contains the result of processing an intent, including the parsed
+// intent object and a summary of the playlist population.
+type IntentResult struct {
+	Intent          IntentObject
+	TracksEvaluated int
+	TracksAdded     int
+	Summary         string
+	// TargetDurationMs is the requested playlist length, if any, and
+	// DurationMs the playlist's length once the tracks were added.
+	TargetDurationMs int
+	DurationMs       int
+	// Daypart and Weather name the daypart preset and weather condition
+	// that filled in open vibe constraints, if any.
+	Daypart string
+	Weather string
+	// Focus and Mood report whether a focus block and the recent mood
+	// trend filled in open vibe constraints.
+	Focus bool
+	Mood  bool
+	// SimilarArtists are the artists added to the candidate pool by
+	// IntentOptions.ExpandSimilar.
+	SimilarArtists []string
+	// Sequence is the pattern the added tracks were ordered by, if the
+	// intent asked for a known one.
+	Sequence string
+	// SessionID echoes IntentOptions.SessionID; SessionHistory is how many
+	// of the session's earlier turns the intent followed up on.
+	SessionID      string
+	SessionHistory int
+	// Degraded is set when the intent compiler failed and the intent was
+	// compiled by the fallback's keyword rules instead.
+	Degraded bool
+}
+
+// Intent types a compiler may return.
+const (
+	// IntentCreate asks for tracks to be added to the playlist.
+	IntentCreate = "CREATE"
+	// IntentModify asks for the playlist to be changed.
+	IntentModify = "MODIFY"
+)
+
+// Weights a vibe constraint may have, in any case; empty counts as
+// WeightMedium.
+const (
+	WeightHigh   = "high"
+	WeightMedium = "medium"
+	WeightLow    = "low"
+)
+
+// ErrInvalidIntent is matched by InvalidIntentError.
+var ErrInvalidIntent = errors.New("domain: invalid intent")
+
+// InvalidIntentError lists what is wrong with an intent, one problem per
+// entry, so they can be shown to the compiler that produced it.
+type InvalidIntentError struct {
+	Problems []string
+}
+
+func (e InvalidIntentError) Error() string {
+	return ErrInvalidIntent.Error() + ": " + strings.Join(e.Problems, "; ")
+}
+
+func (e InvalidIntentError) Is(target error) bool {
+	return target == ErrInvalidIntent
+}
+
+// Validate reports an InvalidIntentError when the intent has an unknown
+// type or sequence pattern, a vibe constraint with an unknown weight, a
+// bound outside 0 to 1, or a minimum above its maximum, an era it cannot
+// read, a popularity bound outside 0 to MaxPopularity, or a duration
+// outside 1 to MaxDurationMinutes.
+func (i IntentObject) Validate() error {
+	var problems []string
+	switch i.IntentType {
+	case IntentCreate, IntentModify:
+	default:
+		problems = append(problems, fmt.Sprintf("intent_type must be %s or %s, got %q", IntentCreate, IntentModify, i.IntentType))
+	}
+	constraints := []struct {
+		name string
+		c    *VibeConstraint
+	}{
+		{"energy", i.VibeConstraints.Energy},
+		{"valence", i.VibeConstraints.Valence},
+		{"acousticness", i.VibeConstraints.Acoustic},
+		{"instrumentalness", i.VibeConstraints.Instrument},
+	}
+	for _, vc := range constraints {
+		if vc.c != nil {
+			problems = append(problems, vc.c.problems("vibe_constraints."+vc.name)...)
+		}
+	}
+	if i.Era != nil {
+		problems = append(problems, i.Era.problems()...)
+	}
+	if i.Popularity != nil {
+		problems = append(problems, i.Popularity.problems()...)
+	}
+	if i.Duration != nil {
+		problems = append(problems, i.Duration.problems()...)
+	}
+	if i.Sequence.Pattern != "" && SequencePattern(i.Sequence.Pattern) == "" {
+		problems = append(problems, fmt.Sprintf("sequence.pattern must be %s, %s, %s or %s, got %q",
+			SequenceLinear, SequencePeak, SequenceWave, SequenceShuffle, i.Sequence.Pattern))
+	}
+	if len(problems) > 0 {
+		return InvalidIntentError{Problems: problems}
+	}
+	return nil
+}
+
+// problems lists what is wrong with the constraint called name.
+func (c VibeConstraint) problems(name string) []string {
+	var problems []string
+	for _, bound := range []struct {
+		field string
+		value float64
+	}{{"target", c.Target}, {"min", c.Min}, {"max", c.Max}} {
+		if bound.value < 0 || bound.value > 1 {
+			problems = append(problems, fmt.Sprintf("%s.%s must be between 0 and 1, got %v", name, bound.field, bound.value))
+		}
+	}
+	// A zero max is unset.
+	if c.Max > 0 && c.Min > c.Max {
+		problems = append(problems, fmt.Sprintf("%s.min (%v) must not be above max (%v)", name, c.Min, c.Max))
+	}
+	switch strings.ToLower(c.Weight) {
+	case "", WeightHigh, WeightMedium, WeightLow:
+	default:
+		problems = append(problems, fmt.Sprintf("%s.weight must be %s, %s or %s, got %q", name, WeightHigh, WeightMedium, WeightLow, c.Weight))
+	}
+	return problems
+}
+
+func (e EraConstraint) problems() []string {
+	var problems []string
+	if e.MinYear < 0 || e.MaxYear < 0 {
+		problems = append(problems, fmt.Sprintf("era years must not be negative, got %d to %d", e.MinYear, e.MaxYear))
+	}
+	if e.MaxYear > 0 && e.MinYear > e.MaxYear {
+		problems = append(problems, fmt.Sprintf("era.min_year (%d) must not be above max_year (%d)", e.MinYear, e.MaxYear))
+	}
+	if _, _, ok := e.Years(); !ok {
+		problems = append(problems, fmt.Sprintf("era.decade must be a decade such as 90s or 1990s, got %q", e.Decade))
+	}
+	return problems
+}
+
+func (p PopularityConstraint) problems() []string {
+	var problems []string
+	if p.Min < 0 || p.Min > MaxPopularity || p.Max < 0 || p.Max > MaxPopularity {
+		problems = append(problems, fmt.Sprintf("popularity bounds must be between 0 and %d, got %d to %d", MaxPopularity, p.Min, p.Max))
+	}
+	if p.Max > 0 && p.Min > p.Max {
+		problems = append(problems, fmt.Sprintf("popularity.min (%d) must not be above max (%d)", p.Min, p.Max))
+	}
+	return problems
+}
+
+func (d DurationConstraint) problems() []string {
+	var problems []string
+	if d.Minutes < 1 || d.Minutes > MaxDurationMinutes {
+		problems = append(problems, fmt.Sprintf("duration.minutes must be between 1 and %d, got %d", MaxDurationMinutes, d.Minutes))
+	}
+	if d.ToleranceMinutes < 0 {
+		problems = append(problems, fmt.Sprintf("duration.tolerance_minutes must not be negative, got %d", d.ToleranceMinutes))
+	}
+	return problems
+}
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// RunOutcome classifies what an intent run achieved. Every outcome other
+// than OutcomeAdded is a cause for adding nothing.
+type RunOutcome string
+
+const (
+	// OutcomeAdded means at least one track was added.
+	OutcomeAdded RunOutcome = "added"
+	// OutcomeNoArtists means the intent named no artists to draw
+	// candidates from.
+	OutcomeNoArtists RunOutcome = "no_artists"
+	// OutcomeNoCandidates means the named artists yielded no tracks.
+	OutcomeNoCandidates RunOutcome = "no_candidates"
+	// OutcomeAlreadyInPlaylist means every candidate was in the playlist.
+	OutcomeAlreadyInPlaylist RunOutcome = "already_in_playlist"
+	// OutcomeFilteredByVibe means the vibe constraints rejected every new
+	// candidate.
+	OutcomeFilteredByVibe RunOutcome = "filtered_by_vibe"
+)
+
+// Outcome classifies the run from its recorded inputs and result.
+func (r IntentRun) Outcome() RunOutcome {
+	switch {
+	case len(r.Added) > 0:
+		return OutcomeAdded
+	case len(r.Candidates) == 0 && len(r.Intent.Entities.Artists) == 0:
+		return OutcomeNoArtists
+	case len(r.Candidates) == 0:
+		return OutcomeNoCandidates
+	}
+	existing := make(map[string]bool, len(r.Existing))
+	for _, id := range r.Existing {
+		existing[id] = true
+	}
+	for _, c := range r.Candidates {
+		if !existing[c.ID] {
+			return OutcomeFilteredByVibe
+		}
+	}
+	return OutcomeAlreadyInPlaylist
+}
+
+// TermCount is how many runs asked for an artist or genre.
+type TermCount struct {
+	Name string `json:"name"`
+	Runs int    `json:"runs"`
+}
+
+// ConstraintStats averages the bounds of one vibe constraint over the runs
+// that set it.
+type ConstraintStats struct {
+	Runs   int     `json:"runs"`
+	AvgMin float64 `json:"avg_min"`
+	AvgMax float64 `json:"avg_max"`
+}
+
+// IntentAnalytics aggregates recorded intent runs for product insight.
+type IntentAnalytics struct {
+	Runs int `json:"runs"`
+	// Successful counts runs that added at least one track; SuccessRate
+	// is Successful / Runs.
+	Successful      int     `json:"successful"`
+	SuccessRate     float64 `json:"success_rate"`
+	TracksEvaluated int     `json:"tracks_evaluated"`
+	TracksAdded     int     `json:"tracks_added"`
+	// AddRate is TracksAdded / TracksEvaluated.
+	AddRate    float64     `json:"add_rate"`
+	TopArtists []TermCount `json:"top_artists"`
+	TopGenres  []TermCount `json:"top_genres"`
+	// Constraints is keyed by the vibe constraint's JSON name, e.g.
+	// "energy", and holds the constraints as applied, context included.
+	Constraints map[string]ConstraintStats `json:"constraints"`
+	// Failures counts the runs that added nothing by cause.
+	Failures map[RunOutcome]int `json:"failures"`
+	// From and To span the runs' creation times; both are omitted without
+	// runs.
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// AnalyzeIntentRuns aggregates runs, keeping the top most requested
+// artists and genres. Names are counted once per run, case-insensitively,
+// under the spelling seen first.
+func AnalyzeIntentRuns(runs []IntentRun, top int) IntentAnalytics {
+	a := IntentAnalytics{
+		Constraints: map[string]ConstraintStats{},
+		Failures:    map[RunOutcome]int{},
+	}
+	artists, genres := newTermCounter(), newTermCounter()
+	for _, run := range runs {
+		a.Runs++
+		a.TracksEvaluated += len(run.Candidates)
+		a.TracksAdded += len(run.Added)
+		if outcome := run.Outcome(); outcome == OutcomeAdded {
+			a.Successful++
+		} else {
+			a.Failures[outcome]++
+		}
+		artists.add(run.Intent.Entities.Artists)
+		genres.add(run.Intent.Entities.Genres)
+
+		vc := run.Intent.VibeConstraints
+		for name, c := range map[string]*VibeConstraint{
+			"energy":           vc.Energy,
+			"valence":          vc.Valence,
+			"acousticness":     vc.Acoustic,
+			"instrumentalness": vc.Instrument,
+		} {
+			if c == nil {
+				continue
+			}
+			s := a.Constraints[name]
+			s.Runs++
+			s.AvgMin += c.Min
+			s.AvgMax += c.Max
+			a.Constraints[name] = s
+		}
+
+		created := run.CreatedAt
+		if a.From == nil || created.Before(*a.From) {
+			a.From = &created
+		}
+		if a.To == nil || created.After(*a.To) {
+			a.To = &created
+		}
+	}
+
+	for name, s := range a.Constraints {
+		s.AvgMin /= float64(s.Runs)
+		s.AvgMax /= float64(s.Runs)
+		a.Constraints[name] = s
+	}
+	if a.Runs > 0 {
+		a.SuccessRate = float64(a.Successful) / float64(a.Runs)
+	}
+	if a.TracksEvaluated > 0 {
+		a.AddRate = float64(a.TracksAdded) / float64(a.TracksEvaluated)
+	}
+	a.TopArtists = artists.top(top)
+	a.TopGenres = genres.top(top)
+	return a
+}
+
+// termCounter counts case-insensitive names once per run.
+type termCounter struct {
+	counts map[string]*TermCount
+}
+
+func newTermCounter() *termCounter {
+	return &termCounter{counts: map[string]*TermCount{}}
+}
+
+func (c *termCounter) add(names []string) {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if tc, ok := c.counts[key]; ok {
+			tc.Runs++
+		} else {
+			c.counts[key] = &TermCount{Name: strings.TrimSpace(name), Runs: 1}
+		}
+	}
+}
+
+// top returns the n most counted names, most first (all when n <= 0).
+func (c *termCounter) top(n int) []TermCount {
+	out := make([]TermCount, 0, len(c.counts))
+	for _, tc := range c.counts {
+		out = append(out, *tc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Runs != out[j].Runs {
+			return out[i].Runs > out[j].Runs
+		}
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestIntentRun_Outcome(t *testing.T) {
+	artists := IntentObject{}
+	artists.Entities.Artists = []string{"Artist"}
+
+	tests := []struct {
+		name string
+		run  IntentRun
+		want RunOutcome
+	}{
+		{name: "added", run: IntentRun{Intent: artists, Candidates: []Track{{ID: "a"}}, Added: []string{"a"}}, want: OutcomeAdded},
+		{name: "no artists", run: IntentRun{}, want: OutcomeNoArtists},
+		{name: "no candidates", run: IntentRun{Intent: artists}, want: OutcomeNoCandidates},
+		{name: "all in playlist", run: IntentRun{Intent: artists, Candidates: []Track{{ID: "a"}}, Existing: []string{"a"}}, want: OutcomeAlreadyInPlaylist},
+		{name: "filtered", run: IntentRun{Intent: artists, Candidates: []Track{{ID: "a"}, {ID: "b"}}, Existing: []string{"a"}}, want: OutcomeFilteredByVibe},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.run.Outcome(); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAnalyzeIntentRuns(t *testing.T) {
+	base := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	run := func(artists, genres []string, energy *VibeConstraint, candidates int, added []string, at time.Duration) IntentRun {
+		r := IntentRun{Added: added, CreatedAt: base.Add(at)}
+		r.Intent.Entities.Artists = artists
+		r.Intent.Entities.Genres = genres
+		r.Intent.VibeConstraints.Energy = energy
+		for i := 0; i < candidates; i++ {
+			r.Candidates = append(r.Candidates, Track{ID: string(rune('a' + i))})
+		}
+		return r
+	}
+	runs := []IntentRun{
+		run([]string{"Bon Iver", "bon iver"}, []string{"folk"}, &VibeConstraint{Min: 0, Max: 0.4}, 4, []string{"a", "b"}, time.Hour),
+		run([]string{"Bon Iver"}, []string{"Folk", "indie"}, &VibeConstraint{Min: 0.2, Max: 0.6}, 4, nil, 0),
+		run([]string{"Daft Punk"}, nil, nil, 0, nil, 2*time.Hour),
+		run(nil, []string{"indie"}, nil, 0, nil, 30*time.Minute),
+	}
+
+	got := AnalyzeIntentRuns(runs, 1)
+	if got.Runs != 4 || got.Successful != 1 || got.SuccessRate != 0.25 {
+		t.Fatalf("unexpected success counts %+v", got)
+	}
+	if got.TracksEvaluated != 8 || got.TracksAdded != 2 || got.AddRate != 0.25 {
+		t.Fatalf("unexpected track counts %+v", got)
+	}
+	if len(got.TopArtists) != 1 || got.TopArtists[0] != (TermCount{Name: "Bon Iver", Runs: 2}) {
+		t.Fatalf("unexpected top artists %+v", got.TopArtists)
+	}
+	// folk and indie tie at 2; the name breaks it.
+	if len(got.TopGenres) != 1 || got.TopGenres[0] != (TermCount{Name: "folk", Runs: 2}) {
+		t.Fatalf("unexpected top genres %+v", got.TopGenres)
+	}
+	energy := got.Constraints["energy"]
+	if energy.Runs != 2 || math.Abs(energy.AvgMin-0.1) > 1e-9 || math.Abs(energy.AvgMax-0.5) > 1e-9 {
+		t.Fatalf("unexpected energy stats %+v", energy)
+	}
+	if _, ok := got.Constraints["valence"]; ok {
+		t.Fatalf("expected no valence stats, got %+v", got.Constraints)
+	}
+	wantFailures := map[RunOutcome]int{OutcomeFilteredByVibe: 1, OutcomeNoCandidates: 1, OutcomeNoArtists: 1}
+	if len(got.Failures) != len(wantFailures) {
+		t.Fatalf("expected failures %v, got %v", wantFailures, got.Failures)
+	}
+	for k, v := range wantFailures {
+		if got.Failures[k] != v {
+			t.Fatalf("expected failures %v, got %v", wantFailures, got.Failures)
+		}
+	}
+	if !got.From.Equal(base) || !got.To.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("unexpected span %v to %v", got.From, got.To)
+	}
+
+	empty := AnalyzeIntentRuns(nil, 10)
+	if empty.Runs != 0 || empty.From != nil || empty.TopArtists == nil || empty.Failures == nil {
+		t.Fatalf("unexpected empty analytics %+v", empty)
+	}
+}
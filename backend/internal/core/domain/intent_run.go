@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// IntentRun records the inputs and outcome of one ProcessIntent call so it
+// can be replayed against newer matching logic.
+type IntentRun struct {
+	ID         string       `json:"id"`
+	PlaylistID string       `json:"playlist_id"`
+	Message    string       `json:"message"`
+	Intent     IntentObject `json:"intent"`
+	// Candidates are the provider tracks that were considered.
+	Candidates []Track `json:"candidates"`
+	// Existing holds IDs of tracks already in the playlist at the time.
+	Existing []string `json:"existing"`
+	// Added holds IDs of the tracks that were added, in order.
+	Added []string `json:"added"`
+	// Experiment and Variant name the experiment arm the run was assigned
+	// to; both are empty outside an experiment. Scoring is the config used.
+	Experiment string        `json:"experiment,omitempty"`
+	Variant    string        `json:"variant,omitempty"`
+	Scoring    ScoringConfig `json:"scoring"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// ReplayResult compares a recorded run with what the current matching logic
+// selects from the same inputs.
+type ReplayResult struct {
+	RunID      string   `json:"run_id"`
+	PlaylistID string   `json:"playlist_id"`
+	Message    string   `json:"message"`
+	Recorded   int      `json:"recorded"`
+	Replayed   int      `json:"replayed"`
+	NewlyAdded []string `json:"newly_added"`
+	NoLonger   []string `json:"no_longer_added"`
+}
+
+// Changed reports whether the replay selected a different set of tracks.
+func (r ReplayResult) Changed() bool {
+	return len(r.NewlyAdded) > 0 || len(r.NoLonger) > 0
+}
+
+// ReplayReport summarizes a replay over several recorded runs.
+type ReplayReport struct {
+	Runs    int            `json:"runs"`
+	Changed int            `json:"changed"`
+	Results []ReplayResult `json:"results"`
+}
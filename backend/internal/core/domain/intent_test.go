@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIntentObject_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*IntentObject)
+		want   []string
+	}{
+		{name: "valid", mutate: func(*IntentObject) {}},
+		{name: "alias pattern", mutate: func(i *IntentObject) { i.Sequence.Pattern = "build" }},
+		{name: "upper case weight", mutate: func(i *IntentObject) { i.VibeConstraints.Energy.Weight = "HIGH" }},
+		{name: "unset max", mutate: func(i *IntentObject) { i.VibeConstraints.Energy = &VibeConstraint{Min: 0.7} }},
+		{name: "unknown type", mutate: func(i *IntentObject) { i.IntentType = "PLAY" }, want: []string{"intent_type"}},
+		{
+			name: "out of range and inverted",
+			mutate: func(i *IntentObject) {
+				i.VibeConstraints.Valence = &VibeConstraint{Min: -5, Max: 42}
+				i.VibeConstraints.Energy = &VibeConstraint{Min: 0.9, Max: 0.1}
+			},
+			want: []string{"energy.min (0.9) must not be above max (0.1)", "valence.min must be between 0 and 1", "valence.max must be between 0 and 1"},
+		},
+		{name: "unknown weight", mutate: func(i *IntentObject) { i.VibeConstraints.Acoustic = &VibeConstraint{Min: 0.5, Weight: "extreme"} }, want: []string{"acousticness.weight"}},
+		{name: "unknown pattern", mutate: func(i *IntentObject) { i.Sequence.Pattern = "ZIGZAG" }, want: []string{"sequence.pattern"}},
+		{name: "decade", mutate: func(i *IntentObject) { i.Era = &EraConstraint{Decade: "1980s"} }},
+		{name: "unknown decade", mutate: func(i *IntentObject) { i.Era = &EraConstraint{Decade: "roaring twenties"} }, want: []string{"era.decade"}},
+		{name: "inverted era", mutate: func(i *IntentObject) { i.Era = &EraConstraint{MinYear: 2001, MaxYear: 1999} }, want: []string{"era.min_year (2001)"}},
+		{
+			name:   "popularity out of range and inverted",
+			mutate: func(i *IntentObject) { i.Popularity = &PopularityConstraint{Min: 90, Max: 120} },
+			want:   []string{"popularity bounds must be between 0 and 100"},
+		},
+		{name: "inverted popularity", mutate: func(i *IntentObject) { i.Popularity = &PopularityConstraint{Min: 60, Max: 30} }, want: []string{"popularity.min (60)"}},
+		{name: "duration", mutate: func(i *IntentObject) { i.Duration = &DurationConstraint{Minutes: 45} }},
+		{name: "empty duration", mutate: func(i *IntentObject) { i.Duration = &DurationConstraint{ToleranceMinutes: -1} }, want: []string{"duration.minutes", "duration.tolerance_minutes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intent := IntentObject{IntentType: IntentCreate}
+			intent.VibeConstraints.Energy = &VibeConstraint{Min: 0.2, Max: 0.6, Weight: WeightHigh}
+			tt.mutate(&intent)
+
+			err := intent.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var invalid InvalidIntentError
+			if !errors.As(err, &invalid) || !errors.Is(err, ErrInvalidIntent) {
+				t.Fatalf("expected an InvalidIntentError, got %v", err)
+			}
+			if len(invalid.Problems) != len(tt.want) {
+				t.Fatalf("expected %d problems, got %q", len(tt.want), invalid.Problems)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q among the problems, got %q", want, invalid.Problems)
+				}
+			}
+		})
+	}
+}
+
+func TestEraConstraint_Years(t *testing.T) {
+	tests := []struct {
+		era      EraConstraint
+		min, max int
+		ok       bool
+	}{
+		{EraConstraint{Decade: "90s"}, 1990, 1999, true},
+		{EraConstraint{Decade: "'00s"}, 2000, 2009, true},
+		{EraConstraint{Decade: "1960s"}, 1960, 1969, true},
+		{EraConstraint{Decade: "2010"}, 2010, 2019, true},
+		{EraConstraint{MinYear: 1975, Decade: "90s"}, 1975, 0, true},
+		{EraConstraint{Decade: "95s"}, 0, 0, false},
+		{EraConstraint{Decade: "nineties"}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		minYear, maxYear, ok := tt.era.Years()
+		if minYear != tt.min || maxYear != tt.max || ok != tt.ok {
+			t.Errorf("%+v: got %d to %d (%v), want %d to %d (%v)", tt.era, minYear, maxYear, ok, tt.min, tt.max, tt.ok)
+		}
+	}
+}
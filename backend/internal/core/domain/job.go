@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrJobLeaseLost is returned when settling a job whose lease has passed to
+// another owner, which runs it again and settles it instead.
+var ErrJobLeaseLost = errors.New("domain: job lease lost to another owner")
+
+// JobState is where a persisted background job is in its lifecycle.
+type JobState string
+
+const (
+	JobQueued  JobState = "queued"
+	JobRunning JobState = "running"
+	JobFailed  JobState = "failed"
+	JobDone    JobState = "done"
+)
+
+// Job is a persisted request to analyze a track's preview. A job that fails
+// is queued again to run at RunAt until it runs out of attempts, and then
+// stays failed.
+type Job struct {
+	ID         string   `json:"id"`
+	TrackID    string   `json:"track_id"`
+	PreviewURL string   `json:"preview_url,omitempty"`
+	State      JobState `json:"state"`
+	// Attempts counts the times the job has been claimed, including the
+	// current one while it is running.
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	RunAt     time.Time `json:"run_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// TraceParent is the W3C traceparent of the request that queued the
+	// job, so its analysis shows up in the same trace.
+	TraceParent string `json:"-"`
+}
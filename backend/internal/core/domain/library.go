@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidTrackFilter is returned for library filters naming an unknown
+// feature or an empty range.
+var ErrInvalidTrackFilter = errors.New("domain: invalid track filter")
+
+// Bounds of a TrackFilter page.
+const (
+	DefaultTrackPageSize = 50
+	MaxTrackPageSize     = 200
+)
+
+// FeatureRange bounds an audio feature, by its AudioFeatures JSON name
+// such as "energy", to Min through Max; a nil bound is open.
+type FeatureRange struct {
+	Feature string
+	Min     *float64
+	Max     *float64
+}
+
+// TrackFilter selects a page of the library, every stored music track
+// whichever playlists hold it.
+type TrackFilter struct {
+	// Artist keeps the tracks whose artist contains it, ignoring case.
+	Artist   string
+	Features []FeatureRange
+	Limit    int
+	Offset   int
+}
+
+// TrackPage is a page of the library, ordered by artist and title. Total
+// counts every track the filter matches.
+type TrackPage struct {
+	Tracks []Track `json:"tracks"`
+	Total  int     `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// LibraryFeatures lists the feature names a FeatureRange may bound.
+func LibraryFeatures() []string {
+	names := make([]string, 0, len(analysisFeatures))
+	for _, f := range analysisFeatures {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// Validate returns ErrInvalidTrackFilter for unknown features, ranges whose
+// Min is above their Max and pages out of bounds.
+func (f TrackFilter) Validate() error {
+	if f.Limit < 1 || f.Limit > MaxTrackPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidTrackFilter, MaxTrackPageSize)
+	}
+	if f.Offset < 0 {
+		return fmt.Errorf("%w: offset must not be negative", ErrInvalidTrackFilter)
+	}
+	known := LibraryFeatures()
+	for _, r := range f.Features {
+		if !slices.Contains(known, r.Feature) {
+			return fmt.Errorf("%w: unknown feature %q", ErrInvalidTrackFilter, r.Feature)
+		}
+		if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			return fmt.Errorf("%w: %s minimum must not be above its maximum", ErrInvalidTrackFilter, r.Feature)
+		}
+	}
+	return nil
+}
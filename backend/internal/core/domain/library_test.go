@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTrackFilter_Validate(t *testing.T) {
+	low, high := 0.2, 0.8
+	tests := []struct {
+		name    string
+		filter  TrackFilter
+		wantErr bool
+	}{
+		{name: "default page", filter: TrackFilter{Limit: DefaultTrackPageSize}},
+		{name: "open range", filter: TrackFilter{Limit: 10, Features: []FeatureRange{{Feature: "energy", Min: &low}}}},
+		{name: "closed range", filter: TrackFilter{Limit: 10, Features: []FeatureRange{{Feature: "tempo", Min: &low, Max: &high}}}},
+		{name: "empty range", filter: TrackFilter{Limit: 10, Features: []FeatureRange{{Feature: "valence", Min: &high, Max: &low}}}, wantErr: true},
+		{name: "unknown feature", filter: TrackFilter{Limit: 10, Features: []FeatureRange{{Feature: "loudness", Min: &low}}}, wantErr: true},
+		{name: "no limit", filter: TrackFilter{}, wantErr: true},
+		{name: "page too large", filter: TrackFilter{Limit: MaxTrackPageSize + 1}, wantErr: true},
+		{name: "negative offset", filter: TrackFilter{Limit: 10, Offset: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr != errors.Is(err, ErrInvalidTrackFilter) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Lyrics holds the words of a track as published by a lyrics provider.
+type Lyrics struct {
+	TrackID string `json:"track_id"`
+	// Plain is the unsynchronized text; Synced is the LRC-formatted text
+	// with line timestamps, when the provider has it.
+	Plain        string `json:"plain"`
+	Synced       string `json:"synced,omitempty"`
+	Instrumental bool   `json:"instrumental"`
+	Source       string `json:"source"`
+	// Sentiment scores the plain text from 0 (negative) to 1 (positive);
+	// 0.5 is neutral or unknown.
+	Sentiment float64   `json:"sentiment"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
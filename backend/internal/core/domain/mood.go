@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInvalidMood is returned for mood reports that cannot be recorded.
+var ErrInvalidMood = errors.New("invalid mood")
+
+const (
+	// MoodWindow is how far back check-ins count towards the mood trend.
+	MoodWindow = 48 * time.Hour
+	// MoodHalfLife is how quickly a check-in's weight in the trend fades.
+	MoodHalfLife = 6 * time.Hour
+	// moodSpread is how far either side of the trend the filled-in
+	// constraints reach.
+	moodSpread = 0.2
+)
+
+// moodEmoji maps the emoji accepted as check-ins to valence and energy.
+var moodEmoji = map[string][2]float64{
+	"😀": {0.8, 0.6},
+	"😊": {0.8, 0.4},
+	"🥳": {0.9, 0.9},
+	"😌": {0.7, 0.2},
+	"😐": {0.5, 0.5},
+	"😴": {0.4, 0.1},
+	"😢": {0.2, 0.3},
+	"😰": {0.3, 0.7},
+	"😡": {0.2, 0.9},
+}
+
+// MoodReport is a self-reported mood: valence and energy, an emoji
+// standing for both, or an emoji with either value overridden.
+type MoodReport struct {
+	Valence *float64
+	Energy  *float64
+	Emoji   string
+}
+
+// MoodCheckIn is a mood report as recorded.
+type MoodCheckIn struct {
+	Owner      string    `json:"-"`
+	Valence    float64   `json:"valence"`
+	Energy     float64   `json:"energy"`
+	Emoji      string    `json:"emoji,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// CheckIn resolves the report into a check-in for owner at t.
+func (r MoodReport) CheckIn(owner string, t time.Time) (MoodCheckIn, error) {
+	c := MoodCheckIn{Owner: owner, Emoji: r.Emoji, RecordedAt: t}
+	if r.Emoji != "" {
+		v, ok := moodEmoji[r.Emoji]
+		if !ok {
+			return MoodCheckIn{}, fmt.Errorf("%w: unsupported emoji %q", ErrInvalidMood, r.Emoji)
+		}
+		c.Valence, c.Energy = v[0], v[1]
+	} else if r.Valence == nil || r.Energy == nil {
+		return MoodCheckIn{}, fmt.Errorf("%w: valence and energy, or an emoji, are required", ErrInvalidMood)
+	}
+	if r.Valence != nil {
+		c.Valence = *r.Valence
+	}
+	if r.Energy != nil {
+		c.Energy = *r.Energy
+	}
+	if c.Valence < 0 || c.Valence > 1 || c.Energy < 0 || c.Energy > 1 {
+		return MoodCheckIn{}, fmt.Errorf("%w: valence and energy must be between 0 and 1", ErrInvalidMood)
+	}
+	return c, nil
+}
+
+// MoodTrend is the recent mood, weighted towards the latest check-ins.
+type MoodTrend struct {
+	Valence  float64 `json:"valence"`
+	Energy   float64 `json:"energy"`
+	CheckIns int     `json:"check_ins"`
+}
+
+// Trend averages the check-ins of the MoodWindow before now, each weighted
+// by its age so that the weight halves every MoodHalfLife. It reports false
+// when none are recent enough.
+func Trend(checkIns []MoodCheckIn, now time.Time) (MoodTrend, bool) {
+	var trend MoodTrend
+	var total float64
+	for _, c := range checkIns {
+		age := now.Sub(c.RecordedAt)
+		if age < 0 || age > MoodWindow {
+			continue
+		}
+		w := math.Exp2(-float64(age) / float64(MoodHalfLife))
+		trend.Valence += w * c.Valence
+		trend.Energy += w * c.Energy
+		trend.CheckIns++
+		total += w
+	}
+	if trend.CheckIns == 0 {
+		return MoodTrend{}, false
+	}
+	trend.Valence /= total
+	trend.Energy /= total
+	return trend, true
+}
+
+// Apply fills the valence and energy an intent leaves open with ranges
+// around the trend. It reports whether anything was filled in.
+func (t MoodTrend) Apply(intent *IntentObject) bool {
+	vc := &intent.VibeConstraints
+	applied := false
+	around := func(v float64) *VibeConstraint {
+		return &VibeConstraint{Min: math.Max(0, v-moodSpread), Max: math.Min(1, v+moodSpread), Weight: "low"}
+	}
+	if vc.Valence == nil {
+		vc.Valence = around(t.Valence)
+		applied = true
+	}
+	if vc.Energy == nil {
+		vc.Energy = around(t.Energy)
+		applied = true
+	}
+	return applied
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMoodReport_CheckIn(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name        string
+		report      MoodReport
+		wantErr     bool
+		wantValence float64
+		wantEnergy  float64
+	}{
+		{name: "values", report: MoodReport{Valence: f(0.3), Energy: f(0.7)}, wantValence: 0.3, wantEnergy: 0.7},
+		{name: "emoji", report: MoodReport{Emoji: "😴"}, wantValence: 0.4, wantEnergy: 0.1},
+		{name: "emoji with override", report: MoodReport{Emoji: "😴", Energy: f(0.5)}, wantValence: 0.4, wantEnergy: 0.5},
+		{name: "zero is a value", report: MoodReport{Valence: f(0), Energy: f(0)}},
+		{name: "unknown emoji", report: MoodReport{Emoji: "🦖"}, wantErr: true},
+		{name: "missing energy", report: MoodReport{Valence: f(0.5)}, wantErr: true},
+		{name: "out of range", report: MoodReport{Valence: f(1.5), Energy: f(0.5)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.report.CheckIn("alice", time.Unix(0, 0))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMood) {
+					t.Fatalf("expected ErrInvalidMood, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.Owner != "alice" || c.Valence != tt.wantValence || c.Energy != tt.wantEnergy {
+				t.Fatalf("unexpected check-in %+v", c)
+			}
+		})
+	}
+}
+
+func TestTrend(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	checkIn := func(ago time.Duration, valence, energy float64) MoodCheckIn {
+		return MoodCheckIn{Valence: valence, Energy: energy, RecordedAt: now.Add(-ago)}
+	}
+
+	if _, ok := Trend([]MoodCheckIn{checkIn(72*time.Hour, 1, 1)}, now); ok {
+		t.Fatal("expected no trend from stale check-ins")
+	}
+
+	// One half-life apart, the newer check-in counts twice as much.
+	trend, ok := Trend([]MoodCheckIn{
+		checkIn(0, 0.9, 0.3),
+		checkIn(MoodHalfLife, 0.3, 0.9),
+		checkIn(-time.Hour, 0, 0), // from the future: ignored
+	}, now)
+	if !ok || trend.CheckIns != 2 {
+		t.Fatalf("expected a trend from 2 check-ins, got %+v", trend)
+	}
+	if math.Abs(trend.Valence-0.7) > 1e-9 || math.Abs(trend.Energy-0.5) > 1e-9 {
+		t.Fatalf("expected valence 0.7 and energy 0.5, got %+v", trend)
+	}
+}
+
+func TestMoodTrend_Apply(t *testing.T) {
+	var intent IntentObject
+	intent.VibeConstraints.Energy = &VibeConstraint{Min: 0.8, Max: 1}
+	if !(MoodTrend{Valence: 0.1, Energy: 0.2}).Apply(&intent) {
+		t.Fatal("expected open valence to be filled")
+	}
+	if v := intent.VibeConstraints.Valence; v == nil || v.Min != 0 || math.Abs(v.Max-0.3) > 1e-9 {
+		t.Fatalf("expected valence 0-0.3, got %+v", v)
+	}
+	if intent.VibeConstraints.Energy.Min != 0.8 {
+		t.Fatalf("explicit energy overwritten: %+v", intent.VibeConstraints.Energy)
+	}
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotInPlaylist is returned when a track is referenced in the context of
+// a playlist that does not contain it.
+var ErrNotInPlaylist = errors.New("domain: track not in playlist")
+
+// ErrNoActiveDevice is returned by playback control when the user has no
+// device to play on, e.g. no Spotify app open.
+var ErrNoActiveDevice = errors.New("domain: no active playback device")
+
+// PlaybackState is what is currently playing from a playlist, shared so the
+// web and mobile clients agree on it. The latest update wins.
+type PlaybackState struct {
+	PlaylistID string `json:"playlist_id"`
+	TrackID    string `json:"track_id"`
+	PositionMs int    `json:"position_ms"`
+	Playing    bool   `json:"playing"`
+	// Device names the client that made the update, e.g. "web" or "ios",
+	// so others can tell their own echoes apart.
+	Device    string    `json:"device,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -8,11 +8,19 @@ var ErrDuplicateISRC = errors.New("domain: duplicate ISRC")
 // ErrNotFound is returned when a requested entity does not exist.
 var ErrNotFound = errors.New("domain: not found")
 
+// ErrVersionConflict is returned when a playlist is saved at a Version
+// another change has since moved past.
+var ErrVersionConflict = errors.New("domain: playlist was changed concurrently")
+
 // Playlist represents a collection of tracks.
 type Playlist struct {
 	ID     string  `json:"id"`
 	Name   string  `json:"name"`
 	Tracks []Track `json:"tracks"`
+	// Version counts the changes to the playlist. Saving a playlist loaded
+	// at a Version fails with ErrVersionConflict if it has changed since;
+	// zero saves unconditionally, as for new playlists.
+	Version int `json:"version,omitempty"`
 }
 
 // NewPlaylist creates a new Playlist instance with the given ID and name.
@@ -28,9 +36,11 @@ func NewPlaylist(id, name string) (*Playlist, error) {
 	}, nil
 }
 
-// AddTrack appends a track to the playlist while preventing duplicate ISRCs.
+// AddTrack appends a track to the playlist while preventing duplicates.
 // If the incoming track has a non-empty ISRC and that ISRC already exists in
-// the playlist, AddTrack returns ErrDuplicateISRC.
+// the playlist, AddTrack returns ErrDuplicateISRC. Other releases of a song
+// already in the playlist, recognized by Track.SameSong, return
+// ErrDuplicateTrack.
 func (p *Playlist) AddTrack(t Track) error {
 	if t.ISRC != "" {
 		for _, ex := range p.Tracks {
@@ -39,19 +49,26 @@ func (p *Playlist) AddTrack(t Track) error {
 			}
 		}
 	}
+	for _, ex := range p.Tracks {
+		if ex.SameSong(t) {
+			return ErrDuplicateTrack
+		}
+	}
 	p.Tracks = append(p.Tracks, t)
 	return nil
 }
 
 // Analyze returns the average audio features across all tracks in the playlist.
-// If there are no tracks, it returns zero values.
+// Episodes are not music and are left out. If there are no tracks, it returns
+// zero values.
 func (p Playlist) Analyze() AudioFeatures {
-	if len(p.Tracks) == 0 {
-		return AudioFeatures{}
-	}
-
 	var sum AudioFeatures
+	var count float64
 	for _, tr := range p.Tracks {
+		if tr.IsEpisode() {
+			continue
+		}
+		count++
 		feat := tr.Features
 		sum.Danceability += feat.Danceability
 		sum.Energy += feat.Energy
@@ -61,7 +78,9 @@ func (p Playlist) Analyze() AudioFeatures {
 		sum.Acousticness += feat.Acousticness
 	}
 
-	count := float64(len(p.Tracks))
+	if count == 0 {
+		return AudioFeatures{}
+	}
 	return AudioFeatures{
 		Danceability:     sum.Danceability / count,
 		Energy:           sum.Energy / count,
@@ -71,3 +90,17 @@ func (p Playlist) Analyze() AudioFeatures {
 		Acousticness:     sum.Acousticness / count,
 	}
 }
+
+// PlaylistSummary describes a playlist to the intent compiler so relative
+// requests such as "more danceable" can be read against what it holds.
+type PlaylistSummary struct {
+	Name       string
+	TrackCount int
+	// Features are the averages returned by Playlist.Analyze.
+	Features AudioFeatures
+}
+
+// Summary returns the playlist's summary.
+func (p Playlist) Summary() PlaylistSummary {
+	return PlaylistSummary{Name: p.Name, TrackCount: len(p.Tracks), Features: p.Analyze()}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidPlaylistRef is returned for playlist references that are not a
+// Spotify playlist URL, URI or ID.
+var ErrInvalidPlaylistRef = errors.New("domain: not a Spotify playlist URL or ID")
+
+// PlaylistImport reports a playlist created from a Spotify playlist.
+// Songs repeated in the source, by Track.SameSong, and local files are
+// skipped.
+type PlaylistImport struct {
+	Playlist       Playlist `json:"playlist"`
+	SourceID       string   `json:"source_id"`
+	TracksImported int      `json:"tracks_imported"`
+	TracksSkipped  int      `json:"tracks_skipped"`
+}
+
+// ParseSpotifyPlaylistID returns the playlist ID in ref, which may be an
+// open.spotify.com URL, a spotify:playlist: URI or the bare ID.
+func ParseSpotifyPlaylistID(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	id := ref
+	switch {
+	case strings.HasPrefix(ref, "spotify:playlist:"):
+		id = strings.TrimPrefix(ref, "spotify:playlist:")
+	case strings.Contains(ref, "/"):
+		u, err := url.Parse(ref)
+		if err != nil || u.Host != "open.spotify.com" {
+			return "", ErrInvalidPlaylistRef
+		}
+		// Localized links look like /intl-de/playlist/{id}.
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) < 2 || parts[len(parts)-2] != "playlist" {
+			return "", ErrInvalidPlaylistRef
+		}
+		id = parts[len(parts)-1]
+	}
+	if id == "" {
+		return "", ErrInvalidPlaylistRef
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return "", ErrInvalidPlaylistRef
+		}
+	}
+	return id, nil
+}
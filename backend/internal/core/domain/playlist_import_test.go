@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSpotifyPlaylistID(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "37i9dQZF1DXcBWIGoYBM5M", want: "37i9dQZF1DXcBWIGoYBM5M"},
+		{ref: "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M", want: "37i9dQZF1DXcBWIGoYBM5M"},
+		{ref: "https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M?si=abc123", want: "37i9dQZF1DXcBWIGoYBM5M"},
+		{ref: "  https://open.spotify.com/intl-de/playlist/37i9dQZF1DXcBWIGoYBM5M  ", want: "37i9dQZF1DXcBWIGoYBM5M"},
+		{ref: "https://open.spotify.com/album/4yP0hdKOZPNshxUOjY0cZj", wantErr: true},
+		{ref: "https://example.com/playlist/37i9dQZF1DXcBWIGoYBM5M", wantErr: true},
+		{ref: "spotify:playlist:", wantErr: true},
+		{ref: "not an id", wantErr: true},
+		{ref: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := ParseSpotifyPlaylistID(tt.ref)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPlaylistRef) {
+					t.Fatalf("expected ErrInvalidPlaylistRef, got %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("got %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ErrExplicitTrack is returned when an explicit track is added to a
+// playlist whose settings do not allow explicit content.
+var ErrExplicitTrack = errors.New("domain: explicit track not allowed")
+
+// ErrPlaylistFull is returned when a track is added to a playlist that
+// already has its settings' MaxTracks.
+var ErrPlaylistFull = errors.New("domain: playlist is full")
+
+// ErrPlaylistLocked is returned when a playlist whose settings lock its
+// ordering is reordered.
+var ErrPlaylistLocked = errors.New("domain: playlist order is locked")
+
+// Bounds of PlaylistSettings.
+const (
+	MaxPlaylistTracks     = 10000
+	MaxPlaylistDurationMs = 24 * 60 * 60 * 1000
+)
+
+// marketCode matches ISO 3166-1 alpha-2 country codes such as "US".
+var marketCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// ValidMarket reports whether market is a two-letter country code in
+// upper case, as catalogs take it.
+func ValidMarket(market string) bool {
+	return marketCode.MatchString(market)
+}
+
+// PlaylistSettings tune what may be added to a playlist.
+type PlaylistSettings struct {
+	PlaylistID string `json:"-"`
+	// MaxTracks caps the playlist's length; zero leaves it unbounded.
+	MaxTracks int `json:"max_tracks"`
+	// AllowExplicit lets tracks marked explicit into the playlist;
+	// family-friendly playlists turn it off.
+	AllowExplicit bool `json:"allow_explicit"`
+	// TargetDurationMs is the length intents fill the playlist to when they
+	// ask for none; zero adds every match.
+	TargetDurationMs int `json:"target_duration_ms"`
+	// LockedOrdering keeps the tracks in the order they were added.
+	LockedOrdering bool `json:"locked_ordering"`
+	// DefaultMarket is the country code, such as "GB", the playlist's
+	// tracks are looked up in; empty uses the configured market.
+	DefaultMarket string    `json:"default_market"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultPlaylistSettings are the settings of a playlist that never saved
+// any.
+func DefaultPlaylistSettings(playlistID string) PlaylistSettings {
+	return PlaylistSettings{PlaylistID: playlistID, AllowExplicit: true}
+}
+
+// Validate returns ErrInvalidSettings for out-of-range limits and markets
+// that are not two-letter country codes.
+func (s PlaylistSettings) Validate() error {
+	if s.MaxTracks < 0 || s.MaxTracks > MaxPlaylistTracks {
+		return fmt.Errorf("%w: max_tracks must be between 0 and %d", ErrInvalidSettings, MaxPlaylistTracks)
+	}
+	if s.TargetDurationMs < 0 || s.TargetDurationMs > MaxPlaylistDurationMs {
+		return fmt.Errorf("%w: target_duration_ms must be between 0 and %d", ErrInvalidSettings, MaxPlaylistDurationMs)
+	}
+	if s.DefaultMarket != "" && !ValidMarket(s.DefaultMarket) {
+		return fmt.Errorf("%w: default_market must be a two-letter country code such as US", ErrInvalidSettings)
+	}
+	return nil
+}
+
+// Allows reports whether t may be added to the playlist.
+func (s PlaylistSettings) Allows(t Track) bool {
+	return s.AllowExplicit || !t.Explicit
+}
+
+// Filter returns the tracks Allows, in order.
+func (s PlaylistSettings) Filter(tracks []Track) []Track {
+	if s.AllowExplicit {
+		return tracks
+	}
+	allowed := make([]Track, 0, len(tracks))
+	for _, t := range tracks {
+		if s.Allows(t) {
+			allowed = append(allowed, t)
+		}
+	}
+	return allowed
+}
+
+// Room returns the first of tracks that fit into a playlist already
+// holding count tracks.
+func (s PlaylistSettings) Room(tracks []Track, count int) []Track {
+	if s.MaxTracks == 0 {
+		return tracks
+	}
+	free := max(s.MaxTracks-count, 0)
+	return tracks[:min(free, len(tracks))]
+}
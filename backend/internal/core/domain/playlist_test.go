@@ -31,6 +31,15 @@ func TestPlaylist_AddTrack(t *testing.T) {
 			wantErr: ErrDuplicateISRC,
 			wantLen: 1,
 		},
+		{
+			name: "fails when adding a remaster of a track without ISRCs",
+			initialTracks: []Track{
+				{ID: "t_existing", Title: "Song", Artist: "Artist A"},
+			},
+			toAdd:   Track{ID: "t2", Title: "Song (Remastered)", Artist: "Artist A"},
+			wantErr: ErrDuplicateTrack,
+			wantLen: 1,
+		},
 	}
 
 	for _, tc := range tests {
@@ -117,6 +126,19 @@ func TestPlaylist_Analyze(t *testing.T) {
 			},
 			wantZero: false,
 		},
+		{
+			name: "skips episodes",
+			tracks: []Track{
+				{ID: "t1", Features: AudioFeatures{Energy: 0.6, Tempo: 100}},
+				Episode{ID: "e1", Title: "Morning Briefing", Show: "The Daily"}.Item(),
+			},
+			expected: AudioFeatures{Energy: 0.6, Tempo: 100},
+		},
+		{
+			name:     "returns zero values for episodes only",
+			tracks:   []Track{Episode{ID: "e1"}.Item()},
+			wantZero: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,39 @@
+package domain
+
+// DefaultOwner owns per-user data such as the up-next queue and settings.
+// Requests are not yet tied to user accounts, so a deployment has one user.
+const DefaultOwner = "default"
+
+// Queue is a user's up-next list: tracks to play after the current one. It
+// is kept apart from playlists so radio and suggestions can add to it
+// without editing saved playlists.
+type Queue struct {
+	Owner  string  `json:"owner"`
+	Tracks []Track `json:"tracks"`
+}
+
+// Append adds tracks to the end of the queue.
+func (q *Queue) Append(tracks ...Track) {
+	q.Tracks = append(q.Tracks, tracks...)
+}
+
+// InsertNext puts t at the front of the queue, ahead of everything queued.
+func (q *Queue) InsertNext(t Track) {
+	q.Tracks = append([]Track{t}, q.Tracks...)
+}
+
+// Clear empties the queue.
+func (q *Queue) Clear() {
+	q.Tracks = []Track{}
+}
+
+// Pop removes and returns the next track. It reports false when the queue
+// is empty.
+func (q *Queue) Pop() (Track, bool) {
+	if len(q.Tracks) == 0 {
+		return Track{}, false
+	}
+	t := q.Tracks[0]
+	q.Tracks = q.Tracks[1:]
+	return t, true
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "testing"
+
+func TestQueue(t *testing.T) {
+	q := Queue{Owner: "default"}
+	if _, ok := q.Pop(); ok {
+		t.Fatal("expected empty queue to pop nothing")
+	}
+
+	q.Append(Track{ID: "t1"}, Track{ID: "t2"})
+	q.InsertNext(Track{ID: "t0"})
+	q.Append(Track{ID: "t3"})
+
+	var got []string
+	for {
+		tr, ok := q.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, tr.ID)
+	}
+	want := []string{"t0", "t1", "t2", "t3"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	q.Append(Track{ID: "t4"})
+	q.Clear()
+	if len(q.Tracks) != 0 {
+		t.Fatalf("expected cleared queue, got %+v", q.Tracks)
+	}
+}
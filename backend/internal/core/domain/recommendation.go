@@ -0,0 +1,66 @@
+package domain
+
+import "sort"
+
+// MaxRecommendationSeeds is the most seed tracks a recommendation request
+// carries, Spotify's limit.
+const MaxRecommendationSeeds = 5
+
+// RecommendationRequest asks a catalog for up to Limit tracks like the
+// SeedTrackIDs with audio features near Target.
+type RecommendationRequest struct {
+	SeedTrackIDs []string
+	Target       AudioFeatures
+	Limit        int
+}
+
+// PlaylistRecommendation is a track suggested for a playlist's vibe.
+type PlaylistRecommendation struct {
+	Track Track `json:"track"`
+	// Score is how close the track's audio features are to the playlist's
+	// averages, 0 to 1.
+	Score float64 `json:"score"`
+}
+
+// RecommendationSeeds returns the IDs of the playlist's last Spotify tracks,
+// up to MaxRecommendationSeeds, which best describe where it is heading.
+func (p Playlist) RecommendationSeeds() []string {
+	var seeds []string
+	for i := len(p.Tracks) - 1; i >= 0 && len(seeds) < MaxRecommendationSeeds; i-- {
+		t := p.Tracks[i]
+		if t.IsEpisode() || t.ID == "" || (t.Source != "" && t.Source != SourceSpotify) {
+			continue
+		}
+		seeds = append(seeds, t.ID)
+	}
+	return seeds
+}
+
+// Recommend scores candidates against the playlist's average audio
+// features and returns up to limit of them, best first. Candidates already
+// in the playlist by ID, ISRC or as another release of the same song are
+// left out, as are repeats.
+func (p Playlist) Recommend(candidates []Track, limit int) []PlaylistRecommendation {
+	target := p.Analyze()
+	seen := make([]Track, 0, len(p.Tracks)+len(candidates))
+	seen = append(seen, p.Tracks...)
+	out := []PlaylistRecommendation{}
+candidates:
+	for _, c := range candidates {
+		if c.IsEpisode() {
+			continue
+		}
+		for _, t := range seen {
+			if t.SameSong(c) {
+				continue candidates
+			}
+		}
+		seen = append(seen, c)
+		out = append(out, PlaylistRecommendation{Track: c, Score: featureSimilarity(target, c.Features)})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlaylist_RecommendationSeeds(t *testing.T) {
+	p := Playlist{Tracks: []Track{
+		{ID: "1"}, {ID: "2"}, {ID: "3", Source: SourceSpotify}, {ID: "am", Source: SourceAppleMusic},
+		{ID: "ep", Type: ItemEpisode}, {ID: "4"}, {ID: "5"}, {ID: "6"},
+	}}
+	want := []string{"6", "5", "4", "3", "2"}
+	if got := p.RecommendationSeeds(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected seeds %v, got %v", want, got)
+	}
+}
+
+func TestPlaylist_Recommend(t *testing.T) {
+	calm := AudioFeatures{Energy: 0.2, Acousticness: 0.9, Tempo: 80}
+	p := Playlist{Tracks: []Track{
+		{ID: "in", Title: "Pink Moon", Artist: "Nick Drake", ISRC: "GBAAA7200001", Features: calm},
+	}}
+	candidates := []Track{
+		{ID: "loud", Title: "Around the World", Artist: "Daft Punk", Features: AudioFeatures{Energy: 0.9, Danceability: 0.8, Tempo: 121}},
+		{ID: "in", Title: "Pink Moon", Artist: "Nick Drake"},
+		{ID: "remaster", Title: "Pink Moon - Remastered", Artist: "Nick Drake"},
+		{ID: "same-isrc", Title: "Other", Artist: "Other", ISRC: "GBAAA7200001"},
+		{ID: "close", Title: "Between the Bars", Artist: "Elliott Smith", Features: AudioFeatures{Energy: 0.25, Acousticness: 0.85, Tempo: 84}},
+		{ID: "close", Title: "Between the Bars", Artist: "Elliott Smith", Features: AudioFeatures{Energy: 0.25, Acousticness: 0.85, Tempo: 84}},
+	}
+
+	got := p.Recommend(candidates, 10)
+	if len(got) != 2 || got[0].Track.ID != "close" || got[1].Track.ID != "loud" {
+		t.Fatalf("expected close then loud, got %+v", got)
+	}
+	if got[0].Score <= got[1].Score || got[0].Score > 1 || got[1].Score < 0 {
+		t.Fatalf("unexpected scores %.3f and %.3f", got[0].Score, got[1].Score)
+	}
+	if limited := p.Recommend(candidates, 1); len(limited) != 1 || limited[0].Track.ID != "close" {
+		t.Fatalf("expected the best candidate only, got %+v", limited)
+	}
+}
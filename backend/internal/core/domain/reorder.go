@@ -0,0 +1,30 @@
+package domain
+
+import "errors"
+
+// ErrInvalidOrder is returned when a new track order is not exactly the
+// playlist's tracks: one is missing, repeated or not in the playlist.
+var ErrInvalidOrder = errors.New("domain: order must list each playlist track exactly once")
+
+// Reorder arranges the playlist's tracks in the order of trackIDs, which
+// must list each of its tracks exactly once.
+func (p *Playlist) Reorder(trackIDs []string) error {
+	if len(trackIDs) != len(p.Tracks) {
+		return ErrInvalidOrder
+	}
+	byID := make(map[string]Track, len(p.Tracks))
+	for _, t := range p.Tracks {
+		byID[t.ID] = t
+	}
+	ordered := make([]Track, 0, len(trackIDs))
+	for _, id := range trackIDs {
+		t, ok := byID[id]
+		if !ok {
+			return ErrInvalidOrder
+		}
+		delete(byID, id)
+		ordered = append(ordered, t)
+	}
+	p.Tracks = ordered
+	return nil
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlaylist_Reorder(t *testing.T) {
+	tests := []struct {
+		name     string
+		trackIDs []string
+		want     []string
+		wantErr  error
+	}{
+		{name: "reversed", trackIDs: []string{"c", "b", "a"}, want: []string{"c", "b", "a"}},
+		{name: "unchanged", trackIDs: []string{"a", "b", "c"}, want: []string{"a", "b", "c"}},
+		{name: "missing track", trackIDs: []string{"a", "b"}, wantErr: ErrInvalidOrder},
+		{name: "repeated track", trackIDs: []string{"a", "a", "b"}, wantErr: ErrInvalidOrder},
+		{name: "unknown track", trackIDs: []string{"a", "b", "x"}, wantErr: ErrInvalidOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Playlist{ID: "p", Name: "P", Tracks: []Track{{ID: "a"}, {ID: "b"}, {ID: "c"}}}
+			err := p.Reorder(tt.trackIDs)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error: got %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if p.Tracks[0].ID != "a" || p.Tracks[2].ID != "c" {
+					t.Fatalf("expected the order to be kept on error, got %v", p.Tracks)
+				}
+				return
+			}
+			for i, id := range tt.want {
+				if p.Tracks[i].ID != id {
+					t.Fatalf("position %d: got %s, want %s", i, p.Tracks[i].ID, id)
+				}
+			}
+		})
+	}
+}
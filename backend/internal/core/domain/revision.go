@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Revision reasons: the change that produced a playlist revision.
+const (
+	RevisionSave      = "save"
+	RevisionAddTracks = "add_tracks"
+	RevisionReorder   = "reorder"
+	RevisionRevert    = "revert"
+)
+
+// MaxRevisions is how many revisions are kept per playlist; older ones are
+// dropped as new ones are written.
+const MaxRevisions = 50
+
+// PlaylistRevision is a snapshot of a playlist's tracks, in order, taken
+// after each change to them. Revisions are numbered from 1 per playlist.
+type PlaylistRevision struct {
+	Rev    int    `json:"rev"`
+	Reason string `json:"reason"`
+	// RevertedTo is the revision a RevisionRevert restored.
+	RevertedTo int       `json:"reverted_to,omitempty"`
+	TrackIDs   []string  `json:"track_ids"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidTemplate is returned when generation template parameters are
+// out of range.
+var ErrInvalidTemplate = errors.New("domain: invalid template")
+
+// Running template phases.
+const (
+	PhaseWarmUp   = "warm_up"
+	PhaseSteady   = "steady"
+	PhaseCoolDown = "cool_down"
+)
+
+// Cadence limits for RunningTemplate.TargetSPM, covering a brisk walk to a
+// sprint.
+const (
+	MinRunningSPM = 100
+	MaxRunningSPM = 220
+)
+
+// CadenceTolerance is how far, as a fraction of the target, a track's BPM
+// may be from the cadence wanted at its place in the workout.
+const CadenceTolerance = 0.06
+
+// RunningTemplate generates a workout playlist whose tempo follows the
+// runner's cadence: a warm-up ramping up to TargetSPM, a steady stretch at
+// it and a cool-down ramping back down.
+type RunningTemplate struct {
+	// Artists seed the candidate tracks.
+	Artists []string `json:"artists"`
+	// TargetSPM is the steady cadence in steps per minute.
+	TargetSPM  int `json:"target_spm"`
+	DurationMs int `json:"duration_ms"`
+}
+
+// Validate reports whether the template can generate a playlist.
+func (t RunningTemplate) Validate() error {
+	if len(t.Artists) == 0 {
+		return fmt.Errorf("%w: at least one artist is required", ErrInvalidTemplate)
+	}
+	if t.TargetSPM < MinRunningSPM || t.TargetSPM > MaxRunningSPM {
+		return fmt.Errorf("%w: target_spm must be between %d and %d", ErrInvalidTemplate, MinRunningSPM, MaxRunningSPM)
+	}
+	if t.DurationMs <= 0 {
+		return fmt.Errorf("%w: duration must be positive", ErrInvalidTemplate)
+	}
+	return nil
+}
+
+// Phase returns the workout phase at offsetMs. Warm-up and cool-down each
+// take a fifth of the workout, capped at ten minutes.
+func (t RunningTemplate) Phase(offsetMs int) string {
+	ramp := t.rampMs()
+	switch {
+	case offsetMs < ramp:
+		return PhaseWarmUp
+	case offsetMs >= t.DurationMs-ramp:
+		return PhaseCoolDown
+	default:
+		return PhaseSteady
+	}
+}
+
+// TargetBPM returns the cadence wanted at offsetMs. The ramps run between
+// three quarters of TargetSPM and TargetSPM.
+func (t RunningTemplate) TargetBPM(offsetMs int) float64 {
+	steady := float64(t.TargetSPM)
+	easy := 0.75 * steady
+	ramp := float64(t.rampMs())
+	switch t.Phase(offsetMs) {
+	case PhaseWarmUp:
+		return easy + (steady-easy)*float64(offsetMs)/ramp
+	case PhaseCoolDown:
+		left := float64(t.DurationMs - offsetMs)
+		return easy + (steady-easy)*math.Max(left, 0)/ramp
+	default:
+		return steady
+	}
+}
+
+func (t RunningTemplate) rampMs() int {
+	ramp := t.DurationMs / 5
+	if limit := 10 * 60 * 1000; ramp > limit {
+		ramp = limit
+	}
+	return ramp
+}
+
+// CadenceBPM returns the BPM a runner would step to for a track of the given
+// tempo: the tempo itself, or its half or double when that is closer to
+// target. A 90 BPM track runs at 180 steps per minute.
+func CadenceBPM(tempo, target float64) float64 {
+	best := tempo
+	for _, bpm := range []float64{tempo / 2, tempo * 2} {
+		if math.Abs(bpm-target) < math.Abs(best-target) {
+			best = bpm
+		}
+	}
+	return best
+}
+
+// CadenceTrack is a track placed in a running playlist.
+type CadenceTrack struct {
+	TrackID string `json:"track_id"`
+	Title   string `json:"title"`
+	Artist  string `json:"artist"`
+	Phase   string `json:"phase"`
+	// TargetBPM is the cadence wanted where the track starts; CadenceBPM
+	// the track's tempo after half/double-time matching.
+	TargetBPM  float64 `json:"target_bpm"`
+	CadenceBPM float64 `json:"cadence_bpm"`
+}
+
+// RunningResult summarizes a generated running playlist.
+type RunningResult struct {
+	TracksEvaluated int            `json:"tracks_evaluated"`
+	TracksAdded     int            `json:"tracks_added"`
+	DurationMs      int            `json:"duration_ms"`
+	Tracks          []CadenceTrack `json:"tracks"`
+}
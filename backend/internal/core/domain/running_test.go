@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRunningTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    RunningTemplate
+		wantErr bool
+	}{
+		{name: "valid", tmpl: RunningTemplate{Artists: []string{"A"}, TargetSPM: 170, DurationMs: 1800000}},
+		{name: "no artists", tmpl: RunningTemplate{TargetSPM: 170, DurationMs: 1800000}, wantErr: true},
+		{name: "cadence too low", tmpl: RunningTemplate{Artists: []string{"A"}, TargetSPM: 60, DurationMs: 1800000}, wantErr: true},
+		{name: "cadence too high", tmpl: RunningTemplate{Artists: []string{"A"}, TargetSPM: 300, DurationMs: 1800000}, wantErr: true},
+		{name: "no duration", tmpl: RunningTemplate{Artists: []string{"A"}, TargetSPM: 170}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tmpl.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTemplate) {
+				t.Fatalf("expected ErrInvalidTemplate, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRunningTemplate_TargetBPM(t *testing.T) {
+	// 30 minutes: six-minute warm-up and cool-down.
+	tmpl := RunningTemplate{TargetSPM: 160, DurationMs: 30 * 60000}
+	tests := []struct {
+		offsetMs  int
+		wantPhase string
+		wantBPM   float64
+	}{
+		{offsetMs: 0, wantPhase: PhaseWarmUp, wantBPM: 120},
+		{offsetMs: 3 * 60000, wantPhase: PhaseWarmUp, wantBPM: 140},
+		{offsetMs: 6 * 60000, wantPhase: PhaseSteady, wantBPM: 160},
+		{offsetMs: 20 * 60000, wantPhase: PhaseSteady, wantBPM: 160},
+		{offsetMs: 27 * 60000, wantPhase: PhaseCoolDown, wantBPM: 140},
+		{offsetMs: 30 * 60000, wantPhase: PhaseCoolDown, wantBPM: 120},
+	}
+	for _, tt := range tests {
+		if phase := tmpl.Phase(tt.offsetMs); phase != tt.wantPhase {
+			t.Errorf("offset %d: expected phase %s, got %s", tt.offsetMs, tt.wantPhase, phase)
+		}
+		if bpm := tmpl.TargetBPM(tt.offsetMs); math.Abs(bpm-tt.wantBPM) > 0.001 {
+			t.Errorf("offset %d: expected %.1f BPM, got %.1f", tt.offsetMs, tt.wantBPM, bpm)
+		}
+	}
+}
+
+func TestCadenceBPM(t *testing.T) {
+	tests := []struct {
+		tempo, target, want float64
+	}{
+		{tempo: 170, target: 170, want: 170},
+		{tempo: 88, target: 176, want: 176},
+		{tempo: 330, target: 165, want: 165},
+		{tempo: 120, target: 170, want: 120},
+	}
+	for _, tt := range tests {
+		if got := CadenceBPM(tt.tempo, tt.target); got != tt.want {
+			t.Errorf("CadenceBPM(%v, %v) = %v, want %v", tt.tempo, tt.target, got, tt.want)
+		}
+	}
+}
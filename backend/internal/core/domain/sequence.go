@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Sequence patterns an intent can ask for in IntentObject.Sequence.Pattern.
+const (
+	// SequenceLinear ramps energy up steadily from the first track to the
+	// last.
+	SequenceLinear = "LINEAR"
+	// SequencePeak builds to the most energetic track midway and winds
+	// back down.
+	SequencePeak = "PEAK"
+	// SequenceWave rises and falls repeatedly, about every six tracks.
+	SequenceWave = "WAVE"
+	// SequenceShuffle ignores energy and mixes the tracks up.
+	SequenceShuffle = "SHUFFLE"
+)
+
+// sequenceAliases maps other names compilers use to a pattern.
+var sequenceAliases = map[string]string{
+	"BUILD":  SequenceLinear,
+	"RAMP":   SequenceLinear,
+	"ARC":    SequencePeak,
+	"RANDOM": SequenceShuffle,
+}
+
+// waveLength is the number of tracks one rise and fall of a WAVE spans.
+const waveLength = 6
+
+// SequencePattern returns the pattern name is or is an alias of, or ""
+// when it names none.
+func SequencePattern(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if alias, ok := sequenceAliases[name]; ok {
+		return alias
+	}
+	switch name {
+	case SequenceLinear, SequencePeak, SequenceWave, SequenceShuffle:
+		return name
+	}
+	return ""
+}
+
+// SequenceTracks returns tracks ordered by pattern. Energy patterns place
+// the tracks, ranked by energy, on the pattern's curve; tracks of equal
+// energy keep their order. SHUFFLE is reproducible for the same seed.
+// Unknown patterns return tracks as they are.
+func SequenceTracks(tracks []Track, pattern string, seed int64) []Track {
+	pattern = SequencePattern(pattern)
+	if pattern == "" || len(tracks) < 2 {
+		return tracks
+	}
+	out := append([]Track(nil), tracks...)
+	if pattern == SequenceShuffle {
+		// #nosec G404 -- ordering only, reproducible by design
+		rand.New(rand.NewSource(seed)).Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+		return out
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Features.Energy < out[j].Features.Energy })
+
+	// Rank the positions by the curve's height; the k-th lowest position
+	// gets the k-th least energetic track.
+	n := len(out)
+	curve := make([]float64, n)
+	for i := range curve {
+		x := float64(i) / float64(n-1)
+		switch pattern {
+		case SequenceLinear:
+			curve[i] = x
+		case SequencePeak:
+			curve[i] = -math.Abs(x - 0.5)
+		case SequenceWave:
+			cycles := math.Max(1, math.Round(float64(n)/waveLength))
+			curve[i] = -math.Cos(2 * math.Pi * cycles * x)
+		}
+	}
+	positions := make([]int, n)
+	for i := range positions {
+		positions[i] = i
+	}
+	sort.SliceStable(positions, func(a, b int) bool { return curve[positions[a]] < curve[positions[b]] })
+
+	sequenced := make([]Track, n)
+	for rank, pos := range positions {
+		sequenced[pos] = out[rank]
+	}
+	return sequenced
+}
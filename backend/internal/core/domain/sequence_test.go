@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func energies(tracks []Track) []float64 {
+	out := make([]float64, len(tracks))
+	for i, t := range tracks {
+		out[i] = t.Features.Energy
+	}
+	return out
+}
+
+func TestSequenceTracks(t *testing.T) {
+	var tracks []Track
+	for _, e := range []float64{0.5, 0.1, 0.9, 0.3, 0.7} {
+		tracks = append(tracks, Track{ID: string(rune('a' + len(tracks))), Features: AudioFeatures{Energy: e}})
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []float64
+	}{
+		{name: "linear ramps up", pattern: "LINEAR", want: []float64{0.1, 0.3, 0.5, 0.7, 0.9}},
+		{name: "aliases and case are accepted", pattern: "build", want: []float64{0.1, 0.3, 0.5, 0.7, 0.9}},
+		{name: "peak builds and winds down", pattern: SequencePeak, want: []float64{0.1, 0.5, 0.9, 0.7, 0.3}},
+		{name: "wave rises and falls", pattern: SequenceWave, want: []float64{0.1, 0.5, 0.9, 0.7, 0.3}},
+		{name: "unknown pattern keeps the order", pattern: "ZIGZAG", want: []float64{0.5, 0.1, 0.9, 0.3, 0.7}},
+		{name: "empty pattern keeps the order", pattern: "", want: []float64{0.5, 0.1, 0.9, 0.3, 0.7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := energies(SequenceTracks(tracks, tt.pattern, 1))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := energies(tracks); !reflect.DeepEqual(got, []float64{0.5, 0.1, 0.9, 0.3, 0.7}) {
+		t.Errorf("expected the input left untouched, got %v", got)
+	}
+}
+
+func TestSequenceTracks_Wave(t *testing.T) {
+	var tracks []Track
+	for i := 0; i < 12; i++ {
+		tracks = append(tracks, Track{ID: string(rune('a' + i)), Features: AudioFeatures{Energy: float64(i) / 12}})
+	}
+	got := energies(SequenceTracks(tracks, SequenceWave, 1))
+	// Two waves: low at both ends and in the middle, high in between.
+	peaks := 0
+	for i := 1; i < len(got)-1; i++ {
+		if got[i] > got[i-1] && got[i] > got[i+1] {
+			peaks++
+		}
+	}
+	if peaks != 2 {
+		t.Errorf("expected two peaks, got %d in %v", peaks, got)
+	}
+}
+
+func TestSequenceTracks_Shuffle(t *testing.T) {
+	var tracks []Track
+	for i := 0; i < 20; i++ {
+		tracks = append(tracks, Track{ID: string(rune('a' + i))})
+	}
+	first := SequenceTracks(tracks, SequenceShuffle, 42)
+	if !reflect.DeepEqual(first, SequenceTracks(tracks, SequenceShuffle, 42)) {
+		t.Error("expected the same seed to shuffle the same way")
+	}
+	if reflect.DeepEqual(first, tracks) {
+		t.Error("expected the tracks to be shuffled")
+	}
+	if len(first) != len(tracks) {
+		t.Errorf("expected %d tracks, got %d", len(tracks), len(first))
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSessionPlaylist is returned when an intent session is continued on a
+// playlist other than the one it started on.
+var ErrSessionPlaylist = errors.New("domain: session belongs to another playlist")
+
+// MaxSessionIDLength caps IntentOptions.SessionID.
+const MaxSessionIDLength = 128
+
+// MaxSessionTurns is how many of a session's most recent turns are given
+// to the intent compiler as history.
+const MaxSessionTurns = 10
+
+// IntentTurn is one message of a conversational intent session and the
+// intent it was compiled into, before any context such as the weather or
+// the time of day filled it in.
+type IntentTurn struct {
+	Message   string       `json:"message"`
+	Intent    IntentObject `json:"intent"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// IntentSession is a conversation refining the intents for one playlist,
+// such as "chill jazz" followed by "make it more upbeat". Turns are oldest
+// first.
+type IntentSession struct {
+	ID         string
+	PlaylistID string
+	Turns      []IntentTurn
+}
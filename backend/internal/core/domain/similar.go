@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Weights of the two signals in SimilarPlaylist.Score.
+const (
+	similarFeatureWeight = 0.6
+	similarArtistWeight  = 0.4
+)
+
+// similarTempoScale maps tempo onto roughly the 0-1 range of the other
+// audio features so it doesn't dominate the distance.
+const similarTempoScale = 200.0
+
+// SimilarPlaylist is a playlist scored for how closely it resembles
+// another.
+type SimilarPlaylist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Score blends FeatureSimilarity and ArtistOverlap, 0 to 1.
+	Score float64 `json:"score"`
+	// FeatureSimilarity is 1 minus the normalized distance between the
+	// playlists' average audio features.
+	FeatureSimilarity float64 `json:"feature_similarity"`
+	// ArtistOverlap is the Jaccard index of the playlists' artists.
+	ArtistOverlap float64  `json:"artist_overlap"`
+	SharedArtists []string `json:"shared_artists"`
+}
+
+// ComparePlaylists scores how closely other resembles p. Episodes are left
+// out, and playlists without music compare as dissimilar.
+func ComparePlaylists(p, other Playlist) SimilarPlaylist {
+	s := SimilarPlaylist{ID: other.ID, Name: other.Name, SharedArtists: []string{}}
+	if !hasMusic(p) || !hasMusic(other) {
+		return s
+	}
+
+	s.FeatureSimilarity = featureSimilarity(p.Analyze(), other.Analyze())
+
+	mine, theirs := artistSet(p), artistSet(other)
+	union := len(theirs)
+	for key, name := range mine {
+		if _, ok := theirs[key]; ok {
+			s.SharedArtists = append(s.SharedArtists, name)
+		} else {
+			union++
+		}
+	}
+	sort.Strings(s.SharedArtists)
+	if union > 0 {
+		s.ArtistOverlap = float64(len(s.SharedArtists)) / float64(union)
+	}
+
+	s.Score = similarFeatureWeight*s.FeatureSimilarity + similarArtistWeight*s.ArtistOverlap
+	return s
+}
+
+// SimilarPlaylists returns up to limit of candidates most like p, best
+// first, leaving out p itself and playlists without music.
+func SimilarPlaylists(p Playlist, candidates []Playlist, limit int) []SimilarPlaylist {
+	out := []SimilarPlaylist{}
+	for _, c := range candidates {
+		if c.ID == p.ID || !hasMusic(c) {
+			continue
+		}
+		out = append(out, ComparePlaylists(p, c))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].ID < out[j].ID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func hasMusic(p Playlist) bool {
+	for _, t := range p.Tracks {
+		if !t.IsEpisode() {
+			return true
+		}
+	}
+	return false
+}
+
+// featureSimilarity is 1 minus the normalized distance between a and b:
+// 1 when they are equal, 0 when they are as far apart as can be.
+func featureSimilarity(a, b AudioFeatures) float64 {
+	va, vb := featureVector(a), featureVector(b)
+	var sum float64
+	for i := range va {
+		sum += (va[i] - vb[i]) * (va[i] - vb[i])
+	}
+	return 1 - math.Sqrt(sum)/math.Sqrt(float64(len(va)))
+}
+
+func featureVector(f AudioFeatures) [6]float64 {
+	return [6]float64{
+		f.Danceability,
+		f.Energy,
+		f.Valence,
+		math.Min(f.Tempo/similarTempoScale, 1),
+		f.Instrumentalness,
+		f.Acousticness,
+	}
+}
+
+// artistSet maps the playlist's artists, case-folded, to their name as
+// first seen.
+func artistSet(p Playlist) map[string]string {
+	set := make(map[string]string)
+	for _, t := range p.Tracks {
+		if t.IsEpisode() || t.Artist == "" {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(t.Artist))
+		if _, ok := set[key]; !ok {
+			set[key] = t.Artist
+		}
+	}
+	return set
+}
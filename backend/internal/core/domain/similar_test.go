@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComparePlaylists(t *testing.T) {
+	calm := AudioFeatures{Energy: 0.2, Acousticness: 0.9, Tempo: 80}
+	loud := AudioFeatures{Energy: 0.9, Danceability: 0.8, Tempo: 160}
+	p := Playlist{ID: "p", Tracks: []Track{
+		{ID: "1", Artist: "Nick Drake", Features: calm},
+		{ID: "2", Artist: "Elliott Smith", Features: calm},
+	}}
+
+	tests := []struct {
+		name        string
+		other       Playlist
+		wantFeature float64
+		wantArtists float64
+		wantShared  int
+	}{
+		{
+			name:        "same vibe, same artist in other case",
+			other:       Playlist{ID: "o", Tracks: []Track{{Artist: "nick drake", Features: calm}}},
+			wantFeature: 1,
+			wantArtists: 0.5,
+			wantShared:  1,
+		},
+		{
+			name:        "different vibe, no artists in common",
+			other:       Playlist{ID: "o", Tracks: []Track{{Artist: "Daft Punk", Features: loud}}},
+			wantFeature: 1 - math.Sqrt(0.8*0.8+0.7*0.7+0.4*0.4+0.9*0.9)/math.Sqrt(6),
+		},
+		{
+			name:  "episodes only",
+			other: Playlist{ID: "o", Tracks: []Track{{Type: ItemEpisode, Artist: "Nick Drake", Features: calm}}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ComparePlaylists(p, tc.other)
+			if math.Abs(got.FeatureSimilarity-tc.wantFeature) > 1e-9 || math.Abs(got.ArtistOverlap-tc.wantArtists) > 1e-9 {
+				t.Fatalf("expected features %.3f and artists %.3f, got %+v", tc.wantFeature, tc.wantArtists, got)
+			}
+			if len(got.SharedArtists) != tc.wantShared {
+				t.Fatalf("expected %d shared artists, got %v", tc.wantShared, got.SharedArtists)
+			}
+			want := similarFeatureWeight*tc.wantFeature + similarArtistWeight*tc.wantArtists
+			if math.Abs(got.Score-want) > 1e-9 {
+				t.Fatalf("expected score %.3f, got %.3f", want, got.Score)
+			}
+		})
+	}
+}
+
+func TestSimilarPlaylists(t *testing.T) {
+	calm := AudioFeatures{Energy: 0.2, Acousticness: 0.9}
+	loud := AudioFeatures{Energy: 0.9, Danceability: 0.8}
+	p := Playlist{ID: "p", Tracks: []Track{{Artist: "A", Features: calm}}}
+	candidates := []Playlist{
+		p,
+		{ID: "loud", Tracks: []Track{{Artist: "B", Features: loud}}},
+		{ID: "twin", Tracks: []Track{{Artist: "A", Features: calm}}},
+		{ID: "empty"},
+		{ID: "close", Tracks: []Track{{Artist: "C", Features: calm}}},
+	}
+
+	got := SimilarPlaylists(p, candidates, 0)
+	var ids []string
+	for _, s := range got {
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 3 || ids[0] != "twin" || ids[1] != "close" || ids[2] != "loud" {
+		t.Fatalf("expected [twin close loud], got %v", ids)
+	}
+	if limited := SimilarPlaylists(p, candidates, 1); len(limited) != 1 || limited[0].ID != "twin" {
+		t.Fatalf("expected the limit to keep the best, got %+v", limited)
+	}
+	if none := SimilarPlaylists(p, nil, 5); none == nil || len(none) != 0 {
+		t.Fatalf("expected an empty, non-nil result, got %#v", none)
+	}
+}
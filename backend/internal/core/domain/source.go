@@ -0,0 +1,14 @@
+package domain
+
+// Catalog providers a track can come from.
+const (
+	SourceSpotify    = "spotify"
+	SourceAppleMusic = "apple_music"
+)
+
+// OnSpotify reports whether the track's ID is a Spotify track ID, so it can
+// be played and mirrored through Spotify. Tracks saved before sources were
+// recorded all came from Spotify.
+func (t Track) OnSpotify() bool {
+	return t.Source == "" || t.Source == SourceSpotify
+}
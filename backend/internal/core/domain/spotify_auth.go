@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidAuthState is returned when an authorization callback carries a
+// state that was never issued, was already used or has expired.
+var ErrInvalidAuthState = errors.New("domain: invalid or expired authorization state")
+
+// AuthStateTTL is how long a user has to grant access between being sent to
+// the provider and returning through the callback.
+const AuthStateTTL = 10 * time.Minute
+
+// OAuthToken is the access a user granted Overture to their account with a
+// provider. The access token is refreshed with RefreshToken once Expiry
+// has passed.
+type OAuthToken struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time
+	Scope        string
+}
+
+// PendingAuth is an authorization Owner started and has not yet completed.
+// Verifier is the PKCE code verifier the provider's code is exchanged with.
+type PendingAuth struct {
+	State     string
+	Owner     string
+	Verifier  string
+	CreatedAt time.Time
+}
+
+// Expired reports whether the authorization can no longer be completed.
+func (p PendingAuth) Expired(now time.Time) bool {
+	return now.Sub(p.CreatedAt) > AuthStateTTL
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSpotifyNotConnected is returned when an operation needs a Spotify
+// account the owner has not connected.
+var ErrSpotifyNotConnected = errors.New("domain: spotify account not connected")
+
+// Outcomes of a track in a PlaylistSync.
+const (
+	// SyncSynced means the track is in the Spotify playlist.
+	SyncSynced = "synced"
+	// SyncSkipped means the track has no Spotify ID, such as tracks found
+	// in other catalogs.
+	SyncSkipped = "skipped"
+	// SyncFailed means Spotify rejected the batch the track was sent in.
+	SyncFailed = "failed"
+)
+
+// TrackSync is the outcome of syncing one track. Error is set for failed
+// tracks.
+type TrackSync struct {
+	TrackID string `json:"track_id"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PlaylistSync reports pushing a playlist to its mirror in the user's
+// Spotify library. Created is set when the mirror was created by this
+// sync; SnapshotID is the mirror's Spotify snapshot afterwards. Tracks are
+// in playlist order.
+type PlaylistSync struct {
+	PlaylistID string      `json:"playlist_id"`
+	SpotifyID  string      `json:"spotify_id"`
+	SnapshotID string      `json:"snapshot_id"`
+	Created    bool        `json:"created"`
+	Synced     int         `json:"synced"`
+	Skipped    int         `json:"skipped"`
+	Failed     int         `json:"failed"`
+	Tracks     []TrackSync `json:"tracks"`
+	SyncedAt   time.Time   `json:"synced_at"`
+}
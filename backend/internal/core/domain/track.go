@@ -1,6 +1,8 @@
 // Package domain contains the core business entities and logic for the Overture music application.
 package domain
 
+import "time"
+
 // AudioFeatures represents the audio characteristics of a track.
 type AudioFeatures struct {
 	// Danceability describes how suitable a track is for dancing based on a combination of musical elements including tempo, rhythm stability, beat strength, and overall regularity. A value of 0.0 is least danceable and 1.0 is most danceable.
@@ -17,10 +19,14 @@ type AudioFeatures struct {
 	Acousticness float64 `json:"acousticness"`
 }
 
-// Track represents a single music track.
+// Track represents a single music track. It is also the playlist item type:
+// a podcast episode is stored as a Track with Type ItemEpisode (see
+// Episode.Item).
 type Track struct {
 	// ID is the unique identifier for the track.
 	ID string `json:"id"`
+	// Type discriminates playlist items; empty means ItemTrack.
+	Type ItemType `json:"type,omitempty"`
 	// Title is the name of the track.
 	Title string `json:"title"`
 	// Artist is the name of the track's primary artist.
@@ -35,6 +41,18 @@ type Track struct {
 	DurationMs int `json:"duration_ms"`
 	// ISRC (International Standard Recording Code) for the track.
 	ISRC string `json:"isrc"`
+	// Popularity is the catalog's 0-100 popularity score when it has one.
+	Popularity int `json:"popularity,omitempty"`
+	// ReleaseYear is the year the track's album came out, when known.
+	ReleaseYear int `json:"release_year,omitempty"`
+	// Explicit marks tracks the catalog rates as explicit content.
+	Explicit bool `json:"explicit,omitempty"`
+	// Source is the catalog provider the track was found in, such as
+	// SourceSpotify; empty for tracks saved before providers were recorded.
+	Source string `json:"source,omitempty"`
 	// Features contains detailed audio characteristics of the track.
 	Features AudioFeatures `json:"features"`
+	// Description and PublishedAt are set for episodes only.
+	Description string     `json:"description,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
 }
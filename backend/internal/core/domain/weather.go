@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidSettings is returned when settings fail validation.
+var ErrInvalidSettings = errors.New("domain: invalid settings")
+
+// Weather conditions, coarsened from the provider's.
+const (
+	WeatherClear  = "clear"
+	WeatherClouds = "clouds"
+	WeatherRain   = "rain"
+	WeatherSnow   = "snow"
+	WeatherStorm  = "storm"
+	WeatherFog    = "fog"
+)
+
+// Weather is the current conditions at the user's location.
+type Weather struct {
+	Condition    string    `json:"condition"`
+	Description  string    `json:"description,omitempty"`
+	TemperatureC float64   `json:"temperature_c"`
+	Daylight     bool      `json:"daylight"`
+	ObservedAt   time.Time `json:"observed_at"`
+}
+
+// Apply biases the vibe constraints intent leaves open towards the weather:
+// gray and wet weather towards mellow, acoustic tracks, clear days towards
+// brighter ones. It reports whether anything was filled in.
+func (w Weather) Apply(intent *IntentObject) bool {
+	vc := &intent.VibeConstraints
+	applied := false
+	fill := func(c **VibeConstraint, v VibeConstraint) {
+		if *c == nil {
+			*c = &v
+			applied = true
+		}
+	}
+	switch w.Condition {
+	case WeatherRain, WeatherStorm, WeatherSnow, WeatherFog:
+		fill(&vc.Acoustic, VibeConstraint{Min: 0.4, Max: 1, Weight: "low"})
+		fill(&vc.Valence, VibeConstraint{Min: 0, Max: 0.6, Weight: "low"})
+	case WeatherClouds:
+		fill(&vc.Valence, VibeConstraint{Min: 0.2, Max: 0.8, Weight: "low"})
+	case WeatherClear:
+		if w.Daylight {
+			fill(&vc.Valence, VibeConstraint{Min: 0.5, Max: 1, Weight: "low"})
+		}
+	}
+	return applied
+}
+
+// WeatherSettings opts a user into weather-aware generation for a location.
+type WeatherSettings struct {
+	Owner     string    `json:"-"`
+	Enabled   bool      `json:"enabled"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate reports whether the settings name a usable location.
+func (s WeatherSettings) Validate() error {
+	if s.Latitude < -90 || s.Latitude > 90 {
+		return fmt.Errorf("%w: latitude must be between -90 and 90", ErrInvalidSettings)
+	}
+	if s.Longitude < -180 || s.Longitude > 180 {
+		return fmt.Errorf("%w: longitude must be between -180 and 180", ErrInvalidSettings)
+	}
+	if s.Enabled && s.Latitude == 0 && s.Longitude == 0 {
+		return fmt.Errorf("%w: a location is required", ErrInvalidSettings)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWeather_Apply(t *testing.T) {
+	tests := []struct {
+		name         string
+		weather      Weather
+		wantApplied  bool
+		wantAcoustic bool
+		wantValence  *VibeConstraint
+	}{
+		{name: "rain", weather: Weather{Condition: WeatherRain}, wantApplied: true, wantAcoustic: true, wantValence: &VibeConstraint{Min: 0, Max: 0.6}},
+		{name: "fog", weather: Weather{Condition: WeatherFog}, wantApplied: true, wantAcoustic: true, wantValence: &VibeConstraint{Min: 0, Max: 0.6}},
+		{name: "clouds", weather: Weather{Condition: WeatherClouds}, wantApplied: true, wantValence: &VibeConstraint{Min: 0.2, Max: 0.8}},
+		{name: "sunny day", weather: Weather{Condition: WeatherClear, Daylight: true}, wantApplied: true, wantValence: &VibeConstraint{Min: 0.5, Max: 1}},
+		{name: "clear night", weather: Weather{Condition: WeatherClear}},
+		{name: "unknown", weather: Weather{Condition: "tornado"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var intent IntentObject
+			if applied := tt.weather.Apply(&intent); applied != tt.wantApplied {
+				t.Fatalf("expected applied=%v, got %v", tt.wantApplied, applied)
+			}
+			vc := intent.VibeConstraints
+			if (vc.Acoustic != nil) != tt.wantAcoustic {
+				t.Fatalf("expected acousticness set=%v, got %+v", tt.wantAcoustic, vc.Acoustic)
+			}
+			if tt.wantValence == nil {
+				if vc.Valence != nil {
+					t.Fatalf("expected valence open, got %+v", vc.Valence)
+				}
+				return
+			}
+			if vc.Valence == nil || vc.Valence.Min != tt.wantValence.Min || vc.Valence.Max != tt.wantValence.Max {
+				t.Fatalf("expected valence %+v, got %+v", tt.wantValence, vc.Valence)
+			}
+		})
+	}
+
+	// Explicit constraints win.
+	var intent IntentObject
+	intent.VibeConstraints.Valence = &VibeConstraint{Min: 0.9, Max: 1}
+	intent.VibeConstraints.Acoustic = &VibeConstraint{Min: 0, Max: 0.1}
+	if (Weather{Condition: WeatherRain}).Apply(&intent) {
+		t.Fatal("expected explicit constraints to be kept")
+	}
+	if intent.VibeConstraints.Valence.Min != 0.9 {
+		t.Fatalf("explicit valence overwritten: %+v", intent.VibeConstraints.Valence)
+	}
+}
+
+func TestWeatherSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings WeatherSettings
+		wantErr  bool
+	}{
+		{name: "enabled with location", settings: WeatherSettings{Enabled: true, Latitude: 47.6, Longitude: -122.3}},
+		{name: "disabled without location", settings: WeatherSettings{}},
+		{name: "enabled without location", settings: WeatherSettings{Enabled: true}, wantErr: true},
+		{name: "latitude out of range", settings: WeatherSettings{Enabled: true, Latitude: 91, Longitude: 0}, wantErr: true},
+		{name: "longitude out of range", settings: WeatherSettings{Enabled: true, Latitude: 0, Longitude: -181}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSettings) {
+				t.Fatalf("expected ErrInvalidSettings, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// AlbumProvider looks up albums with their full track lists.
+type AlbumProvider interface {
+	// GetAlbum returns the best match for title and artist, or a
+	// *NoConfidentMatchError when none is close enough.
+	GetAlbum(ctx context.Context, title, artist string) (domain.Album, error)
+}
@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// ArtistStore caches artist metadata under the name it was searched for.
+// Implementations match names case-insensitively.
+type ArtistStore interface {
+	// GetArtist returns domain.ErrNotFound when name has no entry.
+	GetArtist(ctx context.Context, name string) (domain.Artist, error)
+	SaveArtist(ctx context.Context, name string, artist domain.Artist) error
+}
+
+// ArtistEvictor drops artists that have sat in the cache unused.
+type ArtistEvictor interface {
+	// EvictArtists removes the artists fetched before cutoff and returns
+	// how many it removed.
+	EvictArtists(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// SimilarArtistProvider suggests artists similar to a named one.
+type SimilarArtistProvider interface {
+	// GetSimilarArtists returns up to limit artist names, most similar
+	// first, or domain.ErrNotFound when the artist is unknown.
+	GetSimilarArtists(ctx context.Context, artist string, limit int) ([]string, error)
+}
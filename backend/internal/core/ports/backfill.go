@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// AnalysisBacklog finds stored tracks that preview analysis has not covered.
+type AnalysisBacklog interface {
+	// TracksMissingAnalysis returns up to limit music tracks that have no
+	// preview URL or only zero features, least recently attempted first.
+	TracksMissingAnalysis(ctx context.Context, limit int) ([]domain.Track, error)
+	// MarkBackfillAttempted moves trackIDs behind tracks not attempted since
+	// at, so tracks that cannot be fixed don't starve the rest.
+	MarkBackfillAttempted(ctx context.Context, trackIDs []string, at time.Time) error
+}
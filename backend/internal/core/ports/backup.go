@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// Snapshotter produces a consistent point-in-time copy of the database while
+// it keeps serving reads and writes.
+type Snapshotter interface {
+	// Snapshot writes the copy to a new file at path.
+	Snapshot(ctx context.Context, path string) error
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BlobInfo describes a stored object.
+type BlobInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// BlobStore persists opaque binary artifacts such as database snapshots
+// under slash-separated keys. Missing keys report domain.ErrNotFound.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// List returns the objects whose keys start with prefix, in key order.
+	List(ctx context.Context, prefix string) ([]BlobInfo, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Cache holds short-lived copies of provider responses under string keys.
+// Missing and expired keys report domain.ErrNotFound. Entries may be
+// evicted before their TTL.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
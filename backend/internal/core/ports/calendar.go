@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// CalendarProvider lists the user's calendar events.
+type CalendarProvider interface {
+	// Events returns the occurrences overlapping [from, to), with
+	// recurring events expanded.
+	Events(ctx context.Context, from, to time.Time) ([]domain.CalendarEvent, error)
+}
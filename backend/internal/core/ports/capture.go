@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// CaptureStore persists intent compiler exchanges for later inspection.
+type CaptureStore interface {
+	SaveCapture(ctx context.Context, c domain.IntentCapture) error
+	// ListCaptures returns up to limit captures, newest first.
+	ListCaptures(ctx context.Context, limit int) ([]domain.IntentCapture, error)
+	// GetCapture returns domain.ErrNotFound when id is unknown.
+	GetCapture(ctx context.Context, id string) (domain.IntentCapture, error)
+	// PruneCaptures deletes captures created before cutoff and all but the
+	// newest keep captures, reporting how many were removed. A zero cutoff or
+	// non-positive keep disables that limit.
+	PruneCaptures(ctx context.Context, cutoff time.Time, keep int) (int64, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// CatalogProvider is a music catalog tracks are looked up in, such as
+// Apple Music. Every SpotifyProvider is one.
+type CatalogProvider interface {
+	GetTrack(ctx context.Context, title, artist string) (domain.Track, error)
+	GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// OrphanCleaner finds and removes tracks that no playlist references.
+type OrphanCleaner interface {
+	// OrphanedTracks lists tracks without any playlist link whose last link
+	// was removed (or which were created, if never linked) before cutoff.
+	OrphanedTracks(ctx context.Context, cutoff time.Time) ([]string, error)
+	// DeleteOrphanedTracks deletes the tracks OrphanedTracks would return and
+	// reports how many were removed.
+	DeleteOrphanedTracks(ctx context.Context, cutoff time.Time) (int64, error)
+}
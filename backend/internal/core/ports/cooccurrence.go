@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// CooccurrenceStore holds the model of which tracks are added to playlists
+// together.
+type CooccurrenceStore interface {
+	// PlaylistMemberships returns the music track IDs of every playlist.
+	PlaylistMemberships(ctx context.Context) ([][]string, error)
+	// ReplaceCooccurrence swaps the stored model for pairs.
+	ReplaceCooccurrence(ctx context.Context, pairs []domain.TrackPair) error
+	// AlsoAdded returns up to limit tracks most often added alongside
+	// trackIDs, best first, leaving out trackIDs themselves. Scores are
+	// summed across the given tracks.
+	AlsoAdded(ctx context.Context, trackIDs []string, limit int) ([]domain.Recommendation, error)
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// DiscoveryStore persists which playlists are public and how often they
+// are copied. Plays are counted by the PlaybackStore.
+type DiscoveryStore interface {
+	// Publish makes the playlist public under owner, keeping the original
+	// publish time when it already is. It returns domain.ErrNotFound for
+	// unknown playlists.
+	Publish(ctx context.Context, playlistID, owner string, at time.Time) error
+	// Unpublish makes the playlist private again; private playlists are a
+	// no-op.
+	Unpublish(ctx context.Context, playlistID string) error
+	// GetPublic returns domain.ErrNotFound unless the playlist is public.
+	// Plays are counted from playsSince on.
+	GetPublic(ctx context.Context, playlistID string, playsSince time.Time) (domain.PublicPlaylist, error)
+	// ListPublic returns up to limit public playlists in the given order,
+	// counting plays from playsSince on.
+	ListPublic(ctx context.Context, sort domain.DiscoverSort, playsSince time.Time, limit int) ([]domain.PublicPlaylist, error)
+	// RecordCopy records that copyID was copied from sourceID.
+	RecordCopy(ctx context.Context, sourceID, copyID string, at time.Time) error
+}
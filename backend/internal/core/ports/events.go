@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// EventSink receives domain events for delivery to other subsystems.
+type EventSink interface {
+	Publish(ctx context.Context, event domain.Event) error
+}
+
+// EventSubscriber delivers published events to in-process consumers.
+type EventSubscriber interface {
+	// Subscribe returns a channel receiving every event published from now
+	// on. The channel is closed once ctx is done. Events are dropped
+	// rather than block the publisher when the subscriber falls behind.
+	Subscribe(ctx context.Context) <-chan domain.Event
+}
+
+// EventBus delivers published events to every subscriber: in this process
+// only, or on every replica when backed by a shared broker.
+type EventBus interface {
+	EventSink
+	EventSubscriber
+}
+
+// Outbox exposes events that were recorded in the same transaction as the
+// playlist mutation that caused them and have not been delivered yet.
+type Outbox interface {
+	// PendingEvents returns up to limit undelivered events, oldest first.
+	PendingEvents(ctx context.Context, limit int) ([]domain.Event, error)
+	// MarkPublished records that the event was delivered.
+	MarkPublished(ctx context.Context, eventID string) error
+}
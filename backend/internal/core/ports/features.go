@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// FeatureProvider looks up measured audio features for a recording by its
+// ISRC, for tracks the catalog has none for. It returns domain.ErrNotFound
+// when it knows no features for the recording.
+type FeatureProvider interface {
+	GetFeatures(ctx context.Context, isrc string) (domain.AudioFeatures, error)
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// RecordingIndex resolves tracks to the recording they were fingerprinted
+// as.
+type RecordingIndex interface {
+	// RecordingIDs maps each fingerprinted track in trackIDs to its
+	// recording ID. Tracks without a fingerprint are omitted.
+	RecordingIDs(ctx context.Context, trackIDs []string) (map[string]string, error)
+}
+
+// FingerprintStore persists track fingerprints.
+type FingerprintStore interface {
+	RecordingIndex
+	// SaveFingerprint stores or replaces the fingerprint for fp.TrackID.
+	SaveFingerprint(ctx context.Context, fp domain.TrackFingerprint) error
+	// ListFingerprints pages through stored fingerprints ordered by track
+	// ID, starting after afterTrackID ("" for the first page).
+	ListFingerprints(ctx context.Context, afterTrackID string, limit int) ([]domain.TrackFingerprint, error)
+}
@@ -0,0 +1,7 @@
+package ports
+
+// FeatureFlags reports whether an experimental behavior, named by a
+// domain.Flag* constant, is enabled for this deployment.
+type FeatureFlags interface {
+	Enabled(name string) bool
+}
@@ -9,3 +9,40 @@ import (
 type IntentCompiler interface {
 	AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error)
 }
+
+// IntentStreamer is an IntentCompiler that can report its output while it
+// is being generated.
+type IntentStreamer interface {
+	IntentCompiler
+	// AnalyzeIntentStream calls onDelta with each piece of output, in
+	// order and before returning, then returns the complete intent.
+	AnalyzeIntentStream(ctx context.Context, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error)
+}
+
+// ConversationalCompiler is an IntentCompiler that can compile a message
+// as a follow-up to earlier turns of a session, so "make it more upbeat"
+// modifies the last intent rather than starting afresh.
+type ConversationalCompiler interface {
+	IntentCompiler
+	// AnalyzeIntentTurn compiles message given history, oldest turn first.
+	// Compilers that stream call a non-nil onDelta as AnalyzeIntentStream
+	// does; others ignore it.
+	AnalyzeIntentTurn(ctx context.Context, history []domain.IntentTurn, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error)
+}
+
+type playlistSummaryKey struct{}
+
+// WithPlaylistSummary tells intent compilers called with ctx which playlist
+// the message is about.
+func WithPlaylistSummary(ctx context.Context, summary domain.PlaylistSummary) context.Context {
+	return context.WithValue(ctx, playlistSummaryKey{}, summary)
+}
+
+// PlaylistSummaryFrom returns the summary ctx carries, or nil when the
+// message is not about a known playlist.
+func PlaylistSummaryFrom(ctx context.Context) *domain.PlaylistSummary {
+	if s, ok := ctx.Value(playlistSummaryKey{}).(domain.PlaylistSummary); ok {
+		return &s
+	}
+	return nil
+}
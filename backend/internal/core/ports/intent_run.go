@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// IntentRunStore keeps recorded intent runs for replay.
+type IntentRunStore interface {
+	SaveIntentRun(ctx context.Context, run domain.IntentRun) error
+	// ListIntentRuns returns up to limit runs, newest first.
+	ListIntentRuns(ctx context.Context, limit int) ([]domain.IntentRun, error)
+}
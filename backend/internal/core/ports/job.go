@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// JobQueue persists analysis jobs so they survive restarts and are shared by
+// every backend instance pointed at the same database.
+type JobQueue interface {
+	// EnqueueJob stores a queued job to analyze job.TrackID with
+	// job.PreviewURL and job.TraceParent and returns it, or returns the
+	// track's queued or running job if it already has one.
+	EnqueueJob(ctx context.Context, job domain.Job) (domain.Job, error)
+	// ClaimJob marks the oldest runnable job as running for owner until
+	// lease elapses and returns it, or false when none is runnable. Running
+	// jobs whose lease has elapsed are runnable again, so jobs held by an
+	// instance that died are picked up by another.
+	ClaimJob(ctx context.Context, owner string, lease time.Duration) (domain.Job, bool, error)
+	// CompleteJob marks a job owner claimed done. It returns
+	// domain.ErrJobLeaseLost, changing nothing, when another owner has
+	// claimed the job since.
+	CompleteJob(ctx context.Context, id, owner string) error
+	// FailJob records why a job owner claimed failed and queues it to run
+	// again at retryAt, or marks it failed for good when retryAt is zero.
+	// Like CompleteJob it returns domain.ErrJobLeaseLost when another owner
+	// has claimed the job since.
+	FailJob(ctx context.Context, id, owner, cause string, retryAt time.Time) error
+	// GetJob returns a job by ID, or domain.ErrNotFound.
+	GetJob(ctx context.Context, id string) (domain.Job, error)
+	// PlaylistJobs returns the latest job of each track in a playlist, in
+	// playlist order. Tracks that were never queued are left out.
+	PlaylistJobs(ctx context.Context, playlistID string) ([]domain.Job, error)
+}
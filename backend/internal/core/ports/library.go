@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// TrackLibrary browses every stored track, whichever playlists hold it.
+type TrackLibrary interface {
+	TrackLookup
+	// ListTracks returns the music tracks filter selects, ordered by artist
+	// and title, and how many it matches in all.
+	ListTracks(ctx context.Context, filter domain.TrackFilter) ([]domain.Track, int, error)
+}
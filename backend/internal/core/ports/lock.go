@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Locker grants short-lived named leases shared by every backend instance
+// that points at the same database, so a unit of work is only claimed once.
+type Locker interface {
+	// TryLock acquires the lease on key for owner, or renews it if owner
+	// already holds it. It returns false when another owner holds an
+	// unexpired lease.
+	TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	// Unlock releases the lease on key if owner still holds it.
+	Unlock(ctx context.Context, key, owner string) error
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// LyricsProvider looks up lyrics for a track. It returns domain.ErrNotFound
+// when the provider has none.
+type LyricsProvider interface {
+	GetLyrics(ctx context.Context, track domain.Track) (domain.Lyrics, error)
+}
+
+// LyricsStore persists fetched lyrics.
+type LyricsStore interface {
+	SaveLyrics(ctx context.Context, lyrics domain.Lyrics) error
+	// GetLyrics returns domain.ErrNotFound when nothing is stored.
+	GetLyrics(ctx context.Context, trackID string) (domain.Lyrics, error)
+}
+
+// TrackLookup loads a single stored track.
+type TrackLookup interface {
+	// GetTrack returns domain.ErrNotFound for unknown IDs.
+	GetTrack(ctx context.Context, trackID string) (domain.Track, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import "context"
+
+type marketKey struct{}
+
+// WithMarket asks catalog adapters called with ctx to look tracks up in
+// market, a two-letter country code such as "GB", instead of their
+// configured one. Availability, top tracks and search results differ
+// between markets.
+func WithMarket(ctx context.Context, market string) context.Context {
+	return context.WithValue(ctx, marketKey{}, market)
+}
+
+// MarketFrom returns the market ctx carries, or "" when it names none.
+func MarketFrom(ctx context.Context) string {
+	market, _ := ctx.Value(marketKey{}).(string)
+	return market
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// MoodStore persists each user's mood check-ins.
+type MoodStore interface {
+	RecordMood(ctx context.Context, checkIn domain.MoodCheckIn) error
+	// ListMoods returns the owner's check-ins recorded at or after since,
+	// newest first.
+	ListMoods(ctx context.Context, owner string, since time.Time) ([]domain.MoodCheckIn, error)
+}
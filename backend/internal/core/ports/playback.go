@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// PlaybackStore persists the playback state of each playlist.
+type PlaybackStore interface {
+	// SavePlayback replaces the playlist's state and records an
+	// EventPlaybackUpdated event with it. Each track that starts playing
+	// counts as a play of the playlist.
+	SavePlayback(ctx context.Context, state domain.PlaybackState) error
+	// GetPlayback returns domain.ErrNotFound when nothing has played yet.
+	GetPlayback(ctx context.Context, playlistID string) (domain.PlaybackState, error)
+}
+
+// PlaybackController drives playback on the user's active device of a
+// streaming provider. Methods return domain.ErrNoActiveDevice when the user
+// has no device to play on.
+type PlaybackController interface {
+	// Play replaces what is playing with tracks, starting at offset.
+	Play(ctx context.Context, tracks []domain.Track, offset int) error
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
+	// Skip advances to the next item in the device's queue.
+	Skip(ctx context.Context) error
+	// Queue adds track after the one playing.
+	Queue(ctx context.Context, track domain.Track) error
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// PlaylistImporter reads playlists from Spotify.
+type PlaylistImporter interface {
+	// GetSpotifyPlaylist returns the playlist's name and every track on it,
+	// in order and without audio features. Local files and items that are
+	// not tracks are left out. It returns domain.ErrNotFound for unknown
+	// or private playlists.
+	GetSpotifyPlaylist(ctx context.Context, playlistID string) (domain.Playlist, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// PlaylistSettingsStore persists each playlist's settings.
+type PlaylistSettingsStore interface {
+	// GetPlaylistSettings returns domain.ErrNotFound when the playlist has
+	// none.
+	GetPlaylistSettings(ctx context.Context, playlistID string) (domain.PlaylistSettings, error)
+	SavePlaylistSettings(ctx context.Context, settings domain.PlaylistSettings) error
+}
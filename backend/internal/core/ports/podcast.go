@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// PodcastProvider searches podcast episodes.
+type PodcastProvider interface {
+	// SearchEpisodes returns up to limit episodes matching query, most
+	// relevant first.
+	SearchEpisodes(ctx context.Context, query string, limit int) ([]domain.Episode, error)
+}
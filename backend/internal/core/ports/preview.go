@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// PreviewResolver finds a preview clip for a track its own provider has
+// none for. It returns domain.ErrNotFound when no source has one.
+type PreviewResolver interface {
+	ResolvePreview(ctx context.Context, track domain.Track) (string, error)
+}
+
+// PreviewStore records a resolved preview URL on a stored track.
+type PreviewStore interface {
+	// SetPreviewURL returns domain.ErrNotFound for unknown tracks.
+	SetPreviewURL(ctx context.Context, trackID, previewURL string) error
+}
@@ -0,0 +1,40 @@
+package ports
+
+import "context"
+
+// Priority orders outbound provider calls that compete for a shared rate
+// limit. Adapters that pace their calls read it from the context.
+type Priority int
+
+const (
+	// PriorityInteractive is for calls a user is waiting on. It is the
+	// default.
+	PriorityInteractive Priority = iota
+	// PriorityBackground is for scheduled jobs such as backfills, which
+	// yield to interactive calls but are not starved by them.
+	PriorityBackground
+)
+
+// String names the priority for logs and metric labels.
+func (p Priority) String() string {
+	if p == PriorityBackground {
+		return "background"
+	}
+	return "interactive"
+}
+
+type priorityKey struct{}
+
+// WithPriority marks calls made with ctx as having priority p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the priority ctx was marked with, or
+// PriorityInteractive.
+func PriorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityInteractive
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrProviderUnavailable is matched by ProviderUnavailableError.
+var ErrProviderUnavailable = errors.New("provider unavailable")
+
+// ProviderUnavailableError is returned without calling a provider that has
+// been failing, until RetryAfter has passed.
+type ProviderUnavailableError struct {
+	Provider   string
+	RetryAfter time.Duration
+}
+
+func (e ProviderUnavailableError) Error() string {
+	return fmt.Sprintf("%s is unavailable; retry in %s", e.Provider, e.RetryAfter.Round(time.Second))
+}
+
+func (e ProviderUnavailableError) Is(target error) bool {
+	return target == ErrProviderUnavailable
+}
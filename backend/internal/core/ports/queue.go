@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// QueueStore persists each user's up-next queue. Every method is a single
+// atomic change, so concurrent pushes and pops do not lose tracks.
+type QueueStore interface {
+	// GetQueue returns an empty queue when the owner has none.
+	GetQueue(ctx context.Context, owner string) (domain.Queue, error)
+	AppendToQueue(ctx context.Context, owner string, tracks []domain.Track) error
+	InsertNextInQueue(ctx context.Context, owner string, track domain.Track) error
+	// PopQueue returns domain.ErrNotFound when the queue is empty.
+	PopQueue(ctx context.Context, owner string) (domain.Track, error)
+	ClearQueue(ctx context.Context, owner string) error
+}
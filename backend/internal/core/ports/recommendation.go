@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// RecommendationProvider suggests catalog tracks from seed tracks and
+// target audio features.
+type RecommendationProvider interface {
+	// GetRecommendations returns up to req.Limit tracks with their audio
+	// features when the catalog provides them.
+	GetRecommendations(ctx context.Context, req domain.RecommendationRequest) ([]domain.Track, error)
+}
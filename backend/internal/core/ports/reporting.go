@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// ErrorReporter forwards unexpected errors to an external tracker. Fields
+// carry context such as the request path or playlist ID. Implementations must
+// not block the caller.
+type ErrorReporter interface {
+	Report(ctx context.Context, err error, fields map[string]string)
+}
@@ -9,8 +9,12 @@ import (
 
 type PlaylistRepository interface {
 	GetByID(ctx context.Context, id string) (domain.Playlist, error)
+	ListPlaylists(ctx context.Context) ([]domain.Playlist, error)
 	GetPlaylistAudioFeatures(ctx context.Context, playlistID string) (domain.AudioFeatures, error)
 	UpdateTrackFeatures(ctx context.Context, trackID string, features domain.AudioFeatures) error
 	Save(ctx context.Context, p domain.Playlist) error
 	AddTracksToPlaylist(ctx context.Context, playlistID string, tracks []domain.Track) error
+	// ReorderTracks stores trackIDs, each of the playlist's tracks, as its
+	// new order. It returns domain.ErrNotFound for unknown playlists.
+	ReorderTracks(ctx context.Context, playlistID string, trackIDs []string) error
 }
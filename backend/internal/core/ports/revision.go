@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// RevisionStore reads the revisions a PlaylistRepository records with each
+// change to a playlist's tracks and restores them.
+type RevisionStore interface {
+	// ListRevisions returns up to limit of the playlist's revisions,
+	// newest first.
+	ListRevisions(ctx context.Context, playlistID string, limit int) ([]domain.PlaylistRevision, error)
+	// RevertPlaylist replaces the playlist's tracks with those of revision
+	// rev and records that as a new revision, which it returns. Tracks
+	// deleted since the revision are skipped. It returns domain.ErrNotFound
+	// for unknown playlists and revisions.
+	RevertPlaylist(ctx context.Context, playlistID string, rev int) (domain.PlaylistRevision, error)
+}
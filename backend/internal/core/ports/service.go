@@ -0,0 +1,157 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// PlaylistService is the application API that driving adapters such as the
+// REST handler call into. services.Orchestrator implements it.
+type PlaylistService interface {
+	CreatePlaylist(ctx context.Context, name string) (domain.Playlist, error)
+	GetPlaylist(ctx context.Context, playlistID string) (domain.Playlist, error)
+	GetPlaylistAnalysis(ctx context.Context, playlistID string) (domain.AudioFeatures, error)
+	// GetPlaylistAnalysisDetail returns the distribution of the playlist's
+	// audio features, or domain.ErrNotFound for unknown playlists.
+	GetPlaylistAnalysisDetail(ctx context.Context, playlistID string) (domain.PlaylistAnalysis, error)
+	// PlaylistArc returns domain.ErrNotFound for unknown playlists.
+	PlaylistArc(ctx context.Context, playlistID string, window int) (domain.PlaylistArc, error)
+	// SimilarPlaylists returns domain.ErrNotFound for unknown playlists.
+	SimilarPlaylists(ctx context.Context, playlistID string, limit int) ([]domain.SimilarPlaylist, error)
+	// AddTrackToPlaylist returns the playlist ID, the added track's ID and
+	// its preview URL.
+	AddTrackToPlaylist(ctx context.Context, playlistID, title, artist string) (string, string, string, error)
+	// ReorderTracks returns domain.ErrNotFound for unknown playlists and
+	// domain.ErrInvalidOrder unless trackIDs lists each track exactly once.
+	ReorderTracks(ctx context.Context, playlistID string, trackIDs []string) (domain.Playlist, error)
+
+	HasPlaylistSettings() bool
+	// GetPlaylistSettings returns domain.ErrNotFound for unknown playlists.
+	GetPlaylistSettings(ctx context.Context, playlistID string) (domain.PlaylistSettings, error)
+	// UpdatePlaylistSettings returns domain.ErrNotFound for unknown
+	// playlists.
+	UpdatePlaylistSettings(ctx context.Context, settings domain.PlaylistSettings) (domain.PlaylistSettings, error)
+
+	HasRevisions() bool
+	// ListRevisions returns domain.ErrNotFound for unknown playlists.
+	ListRevisions(ctx context.Context, playlistID string, limit int) ([]domain.PlaylistRevision, error)
+	// RevertPlaylist returns domain.ErrNotFound for unknown playlists and
+	// revisions.
+	RevertPlaylist(ctx context.Context, playlistID string, rev int) (domain.Playlist, error)
+
+	HasIntentCompiler() bool
+	ProcessIntent(ctx context.Context, playlistID, message string) (domain.IntentResult, error)
+	// ProcessIntentWithDuration stops adding tracks once the playlist
+	// reaches the target length.
+	ProcessIntentWithDuration(ctx context.Context, playlistID, message string, target domain.DurationTarget) (domain.IntentResult, error)
+	// ProcessIntentStream is ProcessIntentWithDuration with further opts,
+	// passing the intent compiler's output to onDelta as it is generated
+	// when the compiler can stream.
+	ProcessIntentStream(ctx context.Context, playlistID, message string, opts domain.IntentOptions, onDelta func(domain.IntentDelta)) (domain.IntentResult, error)
+	// GenerateRunningPlaylist returns domain.ErrInvalidTemplate for bad
+	// parameters.
+	GenerateRunningPlaylist(ctx context.Context, playlistID string, tmpl domain.RunningTemplate) (domain.RunningResult, error)
+
+	HasIntentRuns() bool
+	ReplayIntentRuns(ctx context.Context, limit int) (domain.ReplayReport, error)
+	ExperimentReport(ctx context.Context, limit int) (domain.ExperimentReport, error)
+	IntentAnalytics(ctx context.Context, limit int) (domain.IntentAnalytics, error)
+
+	HasLyrics() bool
+	// GetTrackLyrics returns domain.ErrNotFound for unknown tracks and for
+	// tracks without lyrics.
+	GetTrackLyrics(ctx context.Context, trackID string) (domain.Lyrics, error)
+
+	HasPodcasts() bool
+	SearchEpisodes(ctx context.Context, query string, limit int) ([]domain.Episode, error)
+	// AddEpisodeToPlaylist returns domain.ErrNotFound when the playlist
+	// does not exist or no new episode matches query.
+	AddEpisodeToPlaylist(ctx context.Context, playlistID, query string, latest bool) (domain.Episode, error)
+
+	HasAlbums() bool
+	AddAlbumToPlaylist(ctx context.Context, playlistID, title, artist string) (domain.AlbumAddition, error)
+
+	HasRecommendations() bool
+	// Recommendations returns domain.ErrNotFound for unknown playlists.
+	Recommendations(ctx context.Context, playlistID string, limit int) ([]domain.PlaylistRecommendation, error)
+
+	HasPlaylistImport() bool
+	// ImportPlaylist returns domain.ErrInvalidPlaylistRef for references
+	// that are not Spotify playlists and domain.ErrNotFound for playlists
+	// Spotify does not serve.
+	ImportPlaylist(ctx context.Context, ref, name string) (domain.PlaylistImport, error)
+
+	HasPlayback() bool
+	// GetPlayback returns domain.ErrNotFound when nothing has played from
+	// the playlist yet.
+	GetPlayback(ctx context.Context, playlistID string) (domain.PlaybackState, error)
+	// UpdatePlayback returns domain.ErrNotFound for unknown playlists and
+	// domain.ErrNotInPlaylist for tracks the playlist does not contain.
+	UpdatePlayback(ctx context.Context, state domain.PlaybackState) (domain.PlaybackState, error)
+
+	HasPlaybackControl() bool
+	// Playback control returns domain.ErrNoActiveDevice when the user has
+	// no device to play on.
+	PlayPlaylist(ctx context.Context, playlistID, startTrackID string) (domain.PlaybackState, error)
+	PausePlayback(ctx context.Context) error
+	ResumePlayback(ctx context.Context) error
+	SkipTrack(ctx context.Context) error
+	QueueTrack(ctx context.Context, playlistID, trackID string) error
+
+	HasQueue() bool
+	GetQueue(ctx context.Context, owner string) (domain.Queue, error)
+	AddToQueue(ctx context.Context, owner, title, artist string, next bool) (domain.Queue, error)
+	// PopQueue returns domain.ErrNotFound when the queue is empty.
+	PopQueue(ctx context.Context, owner string) (domain.Track, error)
+	ClearQueue(ctx context.Context, owner string) error
+
+	HasWeather() bool
+	GetWeatherSettings(ctx context.Context, owner string) (domain.WeatherSettings, error)
+	// UpdateWeatherSettings returns domain.ErrInvalidSettings for
+	// out-of-range locations.
+	UpdateWeatherSettings(ctx context.Context, settings domain.WeatherSettings) (domain.WeatherSettings, error)
+
+	HasTrackLibrary() bool
+	// ListTracks returns domain.ErrInvalidTrackFilter for unknown features,
+	// empty ranges and pages out of bounds.
+	ListTracks(ctx context.Context, filter domain.TrackFilter) (domain.TrackPage, error)
+	// GetTrack returns domain.ErrNotFound for unknown tracks.
+	GetTrack(ctx context.Context, trackID string) (domain.Track, error)
+
+	HasCooccurrence() bool
+	// AlsoAdded returns domain.ErrNotFound for unknown tracks.
+	AlsoAdded(ctx context.Context, trackID string, limit int) ([]domain.Recommendation, error)
+
+	HasMood() bool
+	// RecordMood returns domain.ErrInvalidMood for unresolvable reports.
+	RecordMood(ctx context.Context, owner string, report domain.MoodReport) (domain.MoodCheckIn, error)
+	ListMoods(ctx context.Context, owner string, since time.Time) ([]domain.MoodCheckIn, error)
+	CurrentMood(ctx context.Context, owner string) (domain.MoodTrend, bool, error)
+
+	HasFocus() bool
+	CurrentFocusBlock(ctx context.Context) (domain.CalendarEvent, bool, error)
+	// TriggerFocus returns domain.ErrNoFocusBlock outside a focus block
+	// unless force is set.
+	TriggerFocus(ctx context.Context, playlistID, message string, force bool) (domain.IntentResult, error)
+
+	HasDiscovery() bool
+	// PublishPlaylist returns domain.ErrNotFound for unknown playlists.
+	PublishPlaylist(ctx context.Context, playlistID, owner string) (domain.PublicPlaylist, error)
+	UnpublishPlaylist(ctx context.Context, playlistID string) error
+	Discover(ctx context.Context, sort domain.DiscoverSort, limit int) ([]domain.PublicPlaylist, error)
+	// CopyPlaylist returns domain.ErrNotFound unless the playlist is
+	// public.
+	CopyPlaylist(ctx context.Context, playlistID, name string) (domain.Playlist, error)
+
+	HasSpotifyAuth() bool
+	// StartSpotifyLogin returns the Spotify URL where owner grants access.
+	StartSpotifyLogin(ctx context.Context, owner string) (string, error)
+	// CompleteSpotifyLogin returns domain.ErrInvalidAuthState for unknown,
+	// used or expired states.
+	CompleteSpotifyLogin(ctx context.Context, state, code string) error
+	// SyncPlaylistToSpotify returns domain.ErrSpotifyNotConnected when
+	// owner has not connected a Spotify account.
+	SyncPlaylistToSpotify(ctx context.Context, owner, playlistID string) (domain.PlaylistSync, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// IntentSessionStore persists conversational intent sessions.
+type IntentSessionStore interface {
+	// GetIntentSession returns the session with up to its last limit
+	// turns, oldest first. Unknown sessions return domain.ErrNotFound.
+	GetIntentSession(ctx context.Context, sessionID string, limit int) (domain.IntentSession, error)
+	// AppendIntentTurn adds turn to the session, starting the session on
+	// playlistID if it does not exist yet.
+	AppendIntentTurn(ctx context.Context, sessionID, playlistID string, turn domain.IntentTurn) error
+}
@@ -0,0 +1,60 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// SpotifyAuthStore persists the Spotify accounts users connected and the
+// Spotify playlists their playlists are mirrored to.
+type SpotifyAuthStore interface {
+	// SavePendingAuth records an authorization awaiting its callback and
+	// drops the ones older than domain.AuthStateTTL.
+	SavePendingAuth(ctx context.Context, pending domain.PendingAuth) error
+	// TakePendingAuth returns and forgets the authorization for state, so
+	// it completes at most once. It returns domain.ErrNotFound for unknown
+	// states.
+	TakePendingAuth(ctx context.Context, state string) (domain.PendingAuth, error)
+	// SaveSpotifyToken replaces the owner's token.
+	SaveSpotifyToken(ctx context.Context, owner string, token domain.OAuthToken) error
+	// GetSpotifyToken returns domain.ErrNotFound when the owner has not
+	// connected a Spotify account.
+	GetSpotifyToken(ctx context.Context, owner string) (domain.OAuthToken, error)
+	// LinkSpotifyPlaylist records the Spotify playlist a playlist is
+	// mirrored to.
+	LinkSpotifyPlaylist(ctx context.Context, playlistID, spotifyID string) error
+	// GetSpotifyPlaylistLink returns domain.ErrNotFound when the playlist
+	// is not mirrored yet.
+	GetSpotifyPlaylistLink(ctx context.Context, playlistID string) (string, error)
+	// RecordSpotifySync records the snapshot the mirrored playlist had
+	// after its last sync.
+	RecordSpotifySync(ctx context.Context, playlistID, snapshotID string, syncedAt time.Time) error
+}
+
+// SpotifyAuthorizer connects users' Spotify accounts with the authorization
+// code flow and PKCE.
+type SpotifyAuthorizer interface {
+	// AuthCodeURL returns where to send the user to grant access, and the
+	// code verifier to complete the authorization with.
+	AuthCodeURL(state string) (authURL, verifier string)
+	// Exchange trades the code Spotify returned for a token.
+	Exchange(ctx context.Context, code, verifier string) (domain.OAuthToken, error)
+	// Library acts on the account that granted token. Tokens refreshed
+	// along the way are passed to onRefresh to be persisted.
+	Library(token domain.OAuthToken, onRefresh func(domain.OAuthToken)) SpotifyLibrary
+}
+
+// SpotifyLibrary writes to the playlists of a Spotify user.
+type SpotifyLibrary interface {
+	// CreatePlaylist creates a private playlist and returns its Spotify ID.
+	CreatePlaylist(ctx context.Context, name string) (string, error)
+	// AddTracks appends tracks to the Spotify playlist.
+	AddTracks(ctx context.Context, spotifyPlaylistID string, tracks []domain.Track) error
+	// ReplaceTracks makes tracks the Spotify playlist's items, in order.
+	// It returns the playlist's snapshot ID afterwards and how many of
+	// tracks were written, which is less than all of them on error. It
+	// returns domain.ErrNotFound when the playlist no longer exists.
+	ReplaceTracks(ctx context.Context, spotifyPlaylistID string, tracks []domain.Track) (snapshotID string, written int, err error)
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// UnitOfWork runs a sequence of repository operations atomically. The
+// repository passed to fn shares a single transaction: if fn returns an
+// error, none of its writes are committed.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context, repo PlaylistRepository) error) error
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// WeatherProvider reports current conditions.
+type WeatherProvider interface {
+	CurrentWeather(ctx context.Context, latitude, longitude float64) (domain.Weather, error)
+}
+
+// WeatherSettingsStore persists each user's weather settings.
+type WeatherSettingsStore interface {
+	// GetWeatherSettings returns domain.ErrNotFound when the owner has none.
+	GetWeatherSettings(ctx context.Context, owner string) (domain.WeatherSettings, error)
+	SaveWeatherSettings(ctx context.Context, settings domain.WeatherSettings) error
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithAlbums enables adding whole albums to playlists.
+func WithAlbums(provider ports.AlbumProvider) Option {
+	return func(o *Orchestrator) {
+		o.albums = provider
+	}
+}
+
+// HasAlbums returns true if an album provider is configured.
+func (o *Orchestrator) HasAlbums() bool {
+	return o.albums != nil
+}
+
+// AddAlbumToPlaylist appends every track of the album best matching title
+// and artist to the playlist, in album order. Songs already in the
+// playlist, by Track.SameSong or fingerprinted recording, are skipped, as
+// are repeats within the album and explicit tracks the playlist's settings rule out. A playlist
+// near its MaxTracks takes the first tracks that fit; one with no room left
+// fails with domain.ErrPlaylistFull.
+func (o *Orchestrator) AddAlbumToPlaylist(ctx context.Context, playlistID, title, artist string) (domain.AlbumAddition, error) {
+	if o.albums == nil {
+		return domain.AlbumAddition{}, fmt.Errorf("service: album provider not configured")
+	}
+	// The playlist's settings pick the market the album is looked up in
+	// and rule some of its tracks out.
+	settings, err := o.settingsFor(ctx, playlistID)
+	if err != nil {
+		return domain.AlbumAddition{}, err
+	}
+	// Fetch before the transaction so a slow provider never holds a write
+	// lock.
+	album, err := o.albums.GetAlbum(inMarket(ctx, settings), title, artist)
+	if err != nil {
+		return domain.AlbumAddition{}, fmt.Errorf("service: failed to fetch album: %w", err)
+	}
+
+	result := domain.AlbumAddition{Album: album}
+	err = o.atomically(ctx, func(ctx context.Context, repo ports.PlaylistRepository) error {
+		playlist, err := repo.GetByID(ctx, playlistID)
+		if err != nil {
+			return fmt.Errorf("service: failed to load playlist: %w", err)
+		}
+
+		existing := make([]string, 0, len(playlist.Tracks))
+		for _, t := range playlist.Tracks {
+			existing = append(existing, t.ID)
+		}
+		fresh := domain.NewSongs(settings.Filter(album.Tracks), playlist.Tracks)
+		fresh, err = o.dropKnownRecordings(ctx, fresh, existing)
+		if err != nil {
+			return err
+		}
+		if room := settings.Room(fresh, len(playlist.Tracks)); len(room) < len(fresh) {
+			if len(room) == 0 {
+				return fmt.Errorf("service: domain rule violation: %w", domain.ErrPlaylistFull)
+			}
+			fresh = room
+		}
+
+		if len(fresh) > 0 {
+			if err := repo.AddTracksToPlaylist(ctx, playlistID, fresh); err != nil {
+				return fmt.Errorf("service: failed to add album to playlist: %w", err)
+			}
+		}
+		result.Added = fresh
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			o.report(ctx, err, map[string]string{"operation": "add_album", "playlist_id": playlistID, "album_id": album.ID})
+		}
+		return domain.AlbumAddition{Album: album}, err
+	}
+	result.TracksAdded = len(result.Added)
+	result.TracksSkipped = len(album.Tracks) - len(result.Added)
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+type stubAlbums struct {
+	album domain.Album
+	err   error
+}
+
+func (s stubAlbums) GetAlbum(ctx context.Context, title, artist string) (domain.Album, error) {
+	return s.album, s.err
+}
+
+// recordingRepo is a mockRepo that remembers the tracks added.
+type recordingRepo struct {
+	mockRepo
+	added []domain.Track
+}
+
+func (r *recordingRepo) AddTracksToPlaylist(ctx context.Context, playlistID string, tracks []domain.Track) error {
+	r.added = append(r.added, tracks...)
+	return nil
+}
+
+func TestOrchestrator_AddAlbumToPlaylist(t *testing.T) {
+	album := domain.Album{ID: "al-1", Title: "Album", Tracks: []domain.Track{
+		{ID: "a1"}, {ID: "a2", ISRC: "ISRC-2"}, {ID: "a3"}, {ID: "a4"}, {ID: "a1"},
+	}}
+	existing := domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: []domain.Track{{ID: "a1"}, {ID: "other", ISRC: "ISRC-2"}, {ID: "orig"}}}
+
+	tests := []struct {
+		name         string
+		opts         []Option
+		playlist     domain.Playlist
+		getErr       error
+		wantAdded    []string
+		wantSkipped  int
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{name: "not configured", wantErr: true},
+		{name: "empty playlist keeps album order", opts: []Option{WithAlbums(stubAlbums{album: album})}, wantAdded: []string{"a1", "a2", "a3", "a4"}, wantSkipped: 1},
+		{
+			name:        "dedups by ID, ISRC and recording",
+			opts:        []Option{WithAlbums(stubAlbums{album: album}), WithRecordings(staticRecordings{ids: map[string]string{"orig": "rec", "a4": "rec"}})},
+			playlist:    existing,
+			wantAdded:   []string{"a3"},
+			wantSkipped: 4,
+		},
+		{
+			name: "dedups other releases of a song",
+			opts: []Option{WithAlbums(stubAlbums{album: domain.Album{ID: "al-2", Title: "Deluxe", Tracks: []domain.Track{
+				{ID: "d1", Title: "Song (Remastered 2011)", Artist: "Artist"},
+				{ID: "d2", Title: "Other", Artist: "Artist"},
+				{ID: "d3", Title: "Other - Radio Edit", Artist: "Artist"},
+			}}})},
+			playlist:    domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "s1", Title: "Song", Artist: "Artist"}}},
+			wantAdded:   []string{"d2"},
+			wantSkipped: 2,
+		},
+		{name: "no match", opts: []Option{WithAlbums(stubAlbums{err: &ports.NoConfidentMatchError{Title: "Album"}})}, wantErr: true},
+		{name: "unknown playlist", opts: []Option{WithAlbums(stubAlbums{album: album})}, getErr: domain.ErrNotFound, wantErr: true, wantNotFound: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &recordingRepo{mockRepo: mockRepo{playlist: tc.playlist, getErr: tc.getErr}}
+			o := NewOrchestrator(&mockSpotify{}, repo, nil, tc.opts...)
+			got, err := o.AddAlbumToPlaylist(context.Background(), "pl-1", "Album", "Artist")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected err=%v, got %v", tc.wantErr, err)
+			}
+			if errors.Is(err, domain.ErrNotFound) != tc.wantNotFound {
+				t.Fatalf("expected not found=%v, got %v", tc.wantNotFound, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			var ids []string
+			for _, tr := range repo.added {
+				ids = append(ids, tr.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.wantAdded) {
+				t.Fatalf("expected %v added, got %v", tc.wantAdded, ids)
+			}
+			if got.TracksAdded != len(tc.wantAdded) || got.TracksSkipped != tc.wantSkipped {
+				t.Fatalf("expected %d added and %d skipped, got %+v", len(tc.wantAdded), tc.wantSkipped, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// analyticsTopTerms is how many artists and genres IntentAnalytics ranks.
+const analyticsTopTerms = 10
+
+// IntentAnalytics aggregates up to limit recorded intent runs, newest
+// first. Only runs that got as far as selecting tracks are recorded, so
+// failures are the causes of adding nothing, not errors.
+func (o *Orchestrator) IntentAnalytics(ctx context.Context, limit int) (domain.IntentAnalytics, error) {
+	if o.runs == nil {
+		return domain.IntentAnalytics{}, fmt.Errorf("service: intent run recording not configured")
+	}
+	runs, err := o.runs.ListIntentRuns(ctx, limit)
+	if err != nil {
+		return domain.IntentAnalytics{}, fmt.Errorf("service: failed to load intent runs: %w", err)
+	}
+	return domain.AnalyzeIntentRuns(runs, analyticsTopTerms), nil
+}
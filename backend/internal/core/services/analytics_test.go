@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestOrchestrator_IntentAnalytics(t *testing.T) {
+	ctx := context.Background()
+	runs := &memoryRuns{}
+	for _, added := range [][]string{{"a"}, nil, {"b", "c"}} {
+		run := domain.IntentRun{Added: added, Candidates: []domain.Track{{ID: "a"}, {ID: "b"}, {ID: "c"}}}
+		run.Intent.Entities.Artists = []string{"Artist"}
+		if err := runs.SaveIntentRun(ctx, run); err != nil {
+			t.Fatalf("save run: %v", err)
+		}
+	}
+	o := NewOrchestrator(nil, &mockRepo{}, nil, WithIntentRuns(runs))
+
+	got, err := o.IntentAnalytics(ctx, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The newest two runs: one added two tracks, one nothing.
+	if got.Runs != 2 || got.Successful != 1 || got.TracksAdded != 2 || got.Failures[domain.OutcomeFilteredByVibe] != 1 {
+		t.Fatalf("unexpected analytics %+v", got)
+	}
+	if len(got.TopArtists) != 1 || got.TopArtists[0].Runs != 2 {
+		t.Fatalf("unexpected top artists %+v", got.TopArtists)
+	}
+
+	if _, err := NewOrchestrator(nil, &mockRepo{}, nil).IntentAnalytics(ctx, 10); err == nil {
+		t.Fatal("expected error without intent run recording")
+	}
+}
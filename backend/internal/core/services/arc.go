@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// PlaylistArc returns the playlist's energy, valence and tempo in play
+// order, smoothed over window tracks. It returns domain.ErrNotFound for
+// unknown playlists.
+func (o *Orchestrator) PlaylistArc(ctx context.Context, playlistID string, window int) (domain.PlaylistArc, error) {
+	playlist, err := o.repo.GetByID(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PlaylistArc{}, err
+		}
+		return domain.PlaylistArc{}, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	return playlist.Arc(window), nil
+}
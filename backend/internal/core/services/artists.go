@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithSimilarArtists lets intents expand their artist pool with artists
+// provider finds similar to the ones they name; see
+// domain.IntentOptions.ExpandSimilar.
+func WithSimilarArtists(provider ports.SimilarArtistProvider) Option {
+	return func(o *Orchestrator) {
+		o.similar = provider
+	}
+}
+
+// HasSimilarArtists returns true if a similar-artist provider is configured.
+func (o *Orchestrator) HasSimilarArtists() bool {
+	return o.similar != nil
+}
+
+// similarArtists returns up to n artists similar to each of artists,
+// leaving out the named ones and repeats. Lookups that fail are skipped,
+// as expansion only widens the pool.
+func (o *Orchestrator) similarArtists(ctx context.Context, artists []string, n int) []string {
+	if o.similar == nil || n <= 0 {
+		return nil
+	}
+	n = min(n, domain.MaxExpandSimilar)
+	seen := make(map[string]bool, len(artists))
+	for _, a := range artists {
+		seen[strings.ToLower(a)] = true
+	}
+	var similar []string
+	for _, artist := range artists {
+		names, err := o.similar.GetSimilarArtists(ctx, artist, n)
+		if err != nil {
+			o.logger.WarnContext(ctx, "similar artist lookup failed", "artist", artist, "error", err)
+			continue
+		}
+		for _, name := range names {
+			key := strings.ToLower(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			similar = append(similar, name)
+		}
+	}
+	return similar
+}
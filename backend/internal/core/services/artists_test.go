@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// stubSimilar suggests the artists listed for each name and records the
+// limits asked for.
+type stubSimilar struct {
+	similar map[string][]string
+	limits  []int
+}
+
+func (s *stubSimilar) GetSimilarArtists(ctx context.Context, artist string, limit int) ([]string, error) {
+	s.limits = append(s.limits, limit)
+	if artist == "Broken" {
+		return nil, errors.New("unavailable")
+	}
+	names, ok := s.similar[artist]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return names[:min(limit, len(names))], nil
+}
+
+func TestOrchestrator_SimilarArtists(t *testing.T) {
+	provider := &stubSimilar{similar: map[string][]string{
+		"Willie Nelson":   {"Waylon Jennings", "Merle Haggard", "Johnny Cash"},
+		"Waylon Jennings": {"willie nelson", "Johnny Cash", "Jessi Colter"},
+	}}
+
+	tests := []struct {
+		name    string
+		artists []string
+		n       int
+		want    []string
+	}{
+		{name: "Disabled", artists: []string{"Willie Nelson"}, n: 0, want: nil},
+		{name: "Up to n per artist", artists: []string{"Willie Nelson"}, n: 2, want: []string{"Waylon Jennings", "Merle Haggard"}},
+		{
+			name:    "Skips named artists and repeats",
+			artists: []string{"Willie Nelson", "Waylon Jennings"},
+			n:       3,
+			want:    []string{"Merle Haggard", "Johnny Cash", "Jessi Colter"},
+		},
+		{name: "Skips failed lookups", artists: []string{"Broken", "Unknown", "Willie Nelson"}, n: 1, want: []string{"Waylon Jennings"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, WithSimilarArtists(provider))
+			got := o.similarArtists(context.Background(), tt.artists, tt.n)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	provider.limits = nil
+	o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, WithSimilarArtists(provider))
+	o.similarArtists(context.Background(), []string{"Willie Nelson"}, 50)
+	if len(provider.limits) != 1 || provider.limits[0] != domain.MaxExpandSimilar {
+		t.Errorf("expected the limit capped at %d, got %v", domain.MaxExpandSimilar, provider.limits)
+	}
+}
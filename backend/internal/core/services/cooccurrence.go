@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// cooccurrenceCandidates is how many co-occurring tracks ProcessIntent
+// adds to the candidates from the intent's artists.
+const cooccurrenceCandidates = 20
+
+// WithCooccurrence serves "also added" recommendations from the
+// co-occurrence model and uses them as intent candidates alongside the
+// intent's artists, seeded with the playlist's tracks and the artists'.
+func WithCooccurrence(store ports.CooccurrenceStore, tracks ports.TrackLookup) Option {
+	return func(o *Orchestrator) {
+		o.cooccurrence = store
+		o.tracks = tracks
+	}
+}
+
+// HasCooccurrence returns true if co-occurrence recommendations are
+// available.
+func (o *Orchestrator) HasCooccurrence() bool {
+	return o.cooccurrence != nil
+}
+
+// AlsoAdded returns up to limit tracks most often added to playlists
+// together with trackID, best first. It returns domain.ErrNotFound for
+// unknown tracks.
+func (o *Orchestrator) AlsoAdded(ctx context.Context, trackID string, limit int) ([]domain.Recommendation, error) {
+	if !o.HasCooccurrence() {
+		return nil, fmt.Errorf("service: co-occurrence not configured")
+	}
+	if _, err := o.tracks.GetTrack(ctx, trackID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service: failed to load track: %w", err)
+	}
+	recs, err := o.cooccurrence.AlsoAdded(ctx, []string{trackID}, limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to load co-occurring tracks: %w", err)
+	}
+	return recs, nil
+}
+
+// cooccurringTracks returns tracks often added together with the
+// playlist's tracks and candidates, leaving out the candidates. Lookup
+// failures are reported and yield none.
+func (o *Orchestrator) cooccurringTracks(ctx context.Context, playlistID string, candidates []domain.Track) []domain.Track {
+	if !o.HasCooccurrence() {
+		return nil
+	}
+	// The playlist is read again inside the transaction; a stale copy
+	// only affects which seeds are used.
+	var seeds []string
+	if playlist, err := o.repo.GetByID(ctx, playlistID); err == nil {
+		for _, t := range playlist.Tracks {
+			seeds = append(seeds, t.ID)
+		}
+	}
+	for _, t := range candidates {
+		seeds = append(seeds, t.ID)
+	}
+	recs, err := o.cooccurrence.AlsoAdded(ctx, seeds, cooccurrenceCandidates)
+	if err != nil {
+		o.report(ctx, fmt.Errorf("service: failed to load co-occurring tracks: %w", err),
+			map[string]string{"operation": "cooccurring_tracks", "playlist_id": playlistID})
+		return nil
+	}
+	tracks := make([]domain.Track, 0, len(recs))
+	for _, r := range recs {
+		tracks = append(tracks, r.Track)
+	}
+	return tracks
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// memCooccurrence serves fixed recommendations per seed track.
+type memCooccurrence struct {
+	recs  map[string][]domain.Recommendation
+	err   error
+	seeds []string
+}
+
+func (m *memCooccurrence) PlaylistMemberships(ctx context.Context) ([][]string, error) {
+	return nil, nil
+}
+
+func (m *memCooccurrence) ReplaceCooccurrence(ctx context.Context, pairs []domain.TrackPair) error {
+	return nil
+}
+
+func (m *memCooccurrence) AlsoAdded(ctx context.Context, trackIDs []string, limit int) ([]domain.Recommendation, error) {
+	m.seeds = trackIDs
+	var out []domain.Recommendation
+	for _, id := range trackIDs {
+		out = append(out, m.recs[id]...)
+	}
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, m.err
+}
+
+func TestOrchestrator_ProcessIntent_Cooccurrence(t *testing.T) {
+	calm := domain.Track{ID: "calm", Features: domain.AudioFeatures{Energy: 0.2}}
+	loud := domain.Track{ID: "loud", Features: domain.AudioFeatures{Energy: 0.9}}
+
+	tests := []struct {
+		name        string
+		store       *memCooccurrence
+		wantAdded   int
+		wantSeeds   string
+		wantReports int
+	}{
+		{
+			name:      "co-occurring tracks are filtered like the rest",
+			store:     &memCooccurrence{recs: map[string][]domain.Recommendation{"old": {{Track: calm}, {Track: loud}}}},
+			wantAdded: 2, // artist track and calm
+			wantSeeds: "old,artist-track",
+		},
+		{name: "empty model", store: &memCooccurrence{}, wantAdded: 1, wantSeeds: "old,artist-track"},
+		{name: "store down", store: &memCooccurrence{err: errors.New("locked")}, wantAdded: 1, wantSeeds: "old,artist-track", wantReports: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			intent := domain.IntentObject{}
+			intent.Entities.Artists = []string{"Artist"}
+			intent.VibeConstraints.Energy = &domain.VibeConstraint{Min: 0, Max: 0.5}
+			repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "old"}}}}
+			spotify := &catalogSpotify{tracks: []domain.Track{{ID: "artist-track", Features: domain.AudioFeatures{Energy: 0.3}}}}
+			reporter := &fakeReporter{}
+			o := NewOrchestrator(spotify, repo, &mockIntentCompiler{intent: intent},
+				WithCooccurrence(tc.store, newMemLyrics()), WithErrorReporter(reporter))
+
+			result, err := o.ProcessIntent(context.Background(), "pl-1", "more like this")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TracksAdded != tc.wantAdded || len(reporter.errs) != tc.wantReports {
+				t.Fatalf("expected %d added with %d reports, got %d with %d", tc.wantAdded, tc.wantReports, result.TracksAdded, len(reporter.errs))
+			}
+			if got := strings.Join(tc.store.seeds, ","); got != tc.wantSeeds {
+				t.Fatalf("expected seeds %q, got %q", tc.wantSeeds, got)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_AlsoAdded(t *testing.T) {
+	ctx := context.Background()
+	store := &memCooccurrence{recs: map[string][]domain.Recommendation{"a": {{Track: domain.Track{ID: "b"}, Together: 3}}}}
+	o := NewOrchestrator(nil, &mockRepo{}, nil, WithCooccurrence(store, newMemLyrics(domain.Track{ID: "a"})))
+
+	recs, err := o.AlsoAdded(ctx, "a", 10)
+	if err != nil || len(recs) != 1 || recs[0].Track.ID != "b" {
+		t.Fatalf("unexpected recommendations %+v (%v)", recs, err)
+	}
+	if _, err := o.AlsoAdded(ctx, "missing", 10); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown track, got %v", err)
+	}
+	if _, err := NewOrchestrator(nil, &mockRepo{}, nil).AlsoAdded(ctx, "a", 10); err == nil {
+		t.Fatal("expected error without co-occurrence configured")
+	}
+}
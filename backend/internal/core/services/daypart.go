@@ -0,0 +1,39 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// daypartSchedule picks the daypart for the current time in loc.
+type daypartSchedule struct {
+	presets domain.DaypartPresets
+	loc     *time.Location
+	now     func() time.Time
+}
+
+// WithDayparts fills the energy and valence an intent leaves open from the
+// daypart that the current time in loc falls in. presets must be valid; see
+// domain.DaypartPresets.Validate.
+func WithDayparts(presets domain.DaypartPresets, loc *time.Location) Option {
+	return func(o *Orchestrator) {
+		if loc == nil {
+			loc = time.Local
+		}
+		o.dayparts = &daypartSchedule{presets: presets, loc: loc, now: time.Now}
+	}
+}
+
+// applyDaypart fills in intent from the current daypart. It returns the
+// daypart's name, or "" when nothing was filled in.
+func (o *Orchestrator) applyDaypart(intent *domain.IntentObject) string {
+	if o.dayparts == nil {
+		return ""
+	}
+	d := o.dayparts.presets.At(o.dayparts.now().In(o.dayparts.loc).Hour())
+	if !d.Apply(intent) {
+		return ""
+	}
+	return d.Name
+}
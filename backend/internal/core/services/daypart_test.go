@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestOrchestrator_ProcessIntent_Dayparts(t *testing.T) {
+	catalog := []domain.Track{
+		{ID: "calm", Features: domain.AudioFeatures{Energy: 0.2, Valence: 0.55}},
+		{ID: "mid", Features: domain.AudioFeatures{Energy: 0.6, Valence: 0.55}},
+		{ID: "loud", Features: domain.AudioFeatures{Energy: 0.9, Valence: 0.55}},
+	}
+	open := domain.IntentObject{}
+	open.Entities.Artists = []string{"Artist"}
+	explicit := open
+	explicit.VibeConstraints.Energy = &domain.VibeConstraint{Min: 0.8, Max: 1}
+	explicit.VibeConstraints.Valence = &domain.VibeConstraint{Min: 0, Max: 1}
+
+	tests := []struct {
+		name        string
+		intent      domain.IntentObject
+		hour        int
+		wantDaypart string
+		wantAdded   int
+	}{
+		{name: "morning", intent: open, hour: 7, wantDaypart: "morning", wantAdded: 1},
+		{name: "late night", intent: open, hour: 23, wantDaypart: "night", wantAdded: 1},
+		{name: "explicit constraints win", intent: explicit, hour: 23, wantAdded: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}
+			o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, &mockIntentCompiler{intent: tc.intent},
+				WithDayparts(domain.DefaultDayparts(), time.UTC))
+			o.dayparts.now = func() time.Time { return time.Date(2026, 3, 1, tc.hour, 30, 0, 0, time.UTC) }
+
+			result, err := o.ProcessIntent(context.Background(), "pl-1", "play something")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Daypart != tc.wantDaypart || result.TracksAdded != tc.wantAdded {
+				t.Fatalf("expected daypart %q adding %d, got %+v", tc.wantDaypart, tc.wantAdded, result)
+			}
+		})
+	}
+
+	// The same request picks different tracks in the morning and at night.
+	picks := map[int]float64{}
+	for _, hour := range []int{7, 23} {
+		o := NewOrchestrator(&catalogSpotify{tracks: catalog}, &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}, &mockIntentCompiler{intent: open},
+			WithDayparts(domain.DefaultDayparts(), time.UTC))
+		o.dayparts.now = func() time.Time { return time.Date(2026, 3, 1, hour, 0, 0, 0, time.UTC) }
+		result, err := o.ProcessIntent(context.Background(), "pl-1", "play something")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		picks[hour] = result.Intent.VibeConstraints.Energy.Max
+	}
+	if picks[7] <= picks[23] {
+		t.Fatalf("expected a livelier morning than night, got max energy %v", picks)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// discovery holds the public playlists and the clock trending is measured
+// against.
+type discovery struct {
+	store ports.DiscoveryStore
+	now   func() time.Time
+}
+
+// WithDiscovery lets owners publish playlists for everyone on the instance
+// to discover and copy.
+func WithDiscovery(store ports.DiscoveryStore) Option {
+	return func(o *Orchestrator) {
+		o.discovery = &discovery{store: store, now: time.Now}
+	}
+}
+
+// HasDiscovery returns true if public playlists are available.
+func (o *Orchestrator) HasDiscovery() bool {
+	return o.discovery != nil
+}
+
+// PublishPlaylist makes the playlist public under owner. It returns
+// domain.ErrNotFound for unknown playlists.
+func (o *Orchestrator) PublishPlaylist(ctx context.Context, playlistID, owner string) (domain.PublicPlaylist, error) {
+	if !o.HasDiscovery() {
+		return domain.PublicPlaylist{}, fmt.Errorf("service: discovery not configured")
+	}
+	now := o.discovery.now().UTC()
+	if err := o.discovery.store.Publish(ctx, playlistID, owner, now); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PublicPlaylist{}, err
+		}
+		return domain.PublicPlaylist{}, fmt.Errorf("service: failed to publish playlist: %w", err)
+	}
+	public, err := o.discovery.store.GetPublic(ctx, playlistID, now.Add(-domain.TrendingWindow))
+	if err != nil {
+		return domain.PublicPlaylist{}, fmt.Errorf("service: failed to load public playlist: %w", err)
+	}
+	return public, nil
+}
+
+// UnpublishPlaylist makes the playlist private again. Copies already made
+// are kept.
+func (o *Orchestrator) UnpublishPlaylist(ctx context.Context, playlistID string) error {
+	if !o.HasDiscovery() {
+		return fmt.Errorf("service: discovery not configured")
+	}
+	if err := o.discovery.store.Unpublish(ctx, playlistID); err != nil {
+		return fmt.Errorf("service: failed to unpublish playlist: %w", err)
+	}
+	return nil
+}
+
+// Discover returns up to limit public playlists in the given order.
+func (o *Orchestrator) Discover(ctx context.Context, sort domain.DiscoverSort, limit int) ([]domain.PublicPlaylist, error) {
+	if !o.HasDiscovery() {
+		return nil, fmt.Errorf("service: discovery not configured")
+	}
+	since := o.discovery.now().UTC().Add(-domain.TrendingWindow)
+	playlists, err := o.discovery.store.ListPublic(ctx, sort, since, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSort) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service: failed to list public playlists: %w", err)
+	}
+	return playlists, nil
+}
+
+// CopyPlaylist copies a public playlist's tracks into a new playlist named
+// name, or after the original when name is empty. It returns
+// domain.ErrNotFound unless the playlist is public.
+func (o *Orchestrator) CopyPlaylist(ctx context.Context, playlistID, name string) (domain.Playlist, error) {
+	if !o.HasDiscovery() {
+		return domain.Playlist{}, fmt.Errorf("service: discovery not configured")
+	}
+	now := o.discovery.now().UTC()
+	if _, err := o.discovery.store.GetPublic(ctx, playlistID, now); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Playlist{}, err
+		}
+		return domain.Playlist{}, fmt.Errorf("service: failed to load public playlist: %w", err)
+	}
+	source, err := o.repo.GetByID(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Playlist{}, err
+		}
+		return domain.Playlist{}, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	if name == "" {
+		name = source.Name
+	}
+
+	copied := domain.Playlist{
+		ID:     uuid.New().String(),
+		Name:   name,
+		Tracks: append([]domain.Track{}, source.Tracks...),
+	}
+	if err := o.repo.Save(ctx, copied); err != nil {
+		return domain.Playlist{}, fmt.Errorf("service: failed to persist copied playlist: %w", err)
+	}
+	// The copy exists either way; a lost count only affects the ranking.
+	if err := o.discovery.store.RecordCopy(ctx, playlistID, copied.ID, now); err != nil {
+		o.report(ctx, fmt.Errorf("service: failed to record copy: %w", err),
+			map[string]string{"operation": "copy_playlist", "playlist_id": playlistID})
+	}
+	return copied, nil
+}
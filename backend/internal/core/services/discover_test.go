@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// memDiscovery is an in-memory ports.DiscoveryStore over known playlist IDs.
+type memDiscovery struct {
+	known     map[string]bool
+	public    map[string]domain.PublicPlaylist
+	copies    map[string]string
+	copyErr   error
+	listSince time.Time
+}
+
+func newMemDiscovery(known ...string) *memDiscovery {
+	m := &memDiscovery{known: map[string]bool{}, public: map[string]domain.PublicPlaylist{}, copies: map[string]string{}}
+	for _, id := range known {
+		m.known[id] = true
+	}
+	return m
+}
+
+func (m *memDiscovery) Publish(ctx context.Context, playlistID, owner string, at time.Time) error {
+	if !m.known[playlistID] {
+		return domain.ErrNotFound
+	}
+	if _, ok := m.public[playlistID]; !ok {
+		m.public[playlistID] = domain.PublicPlaylist{ID: playlistID, Owner: owner, PublishedAt: at}
+	}
+	return nil
+}
+
+func (m *memDiscovery) Unpublish(ctx context.Context, playlistID string) error {
+	delete(m.public, playlistID)
+	return nil
+}
+
+func (m *memDiscovery) GetPublic(ctx context.Context, playlistID string, playsSince time.Time) (domain.PublicPlaylist, error) {
+	p, ok := m.public[playlistID]
+	if !ok {
+		return domain.PublicPlaylist{}, domain.ErrNotFound
+	}
+	return p, nil
+}
+
+func (m *memDiscovery) ListPublic(ctx context.Context, sort domain.DiscoverSort, playsSince time.Time, limit int) ([]domain.PublicPlaylist, error) {
+	m.listSince = playsSince
+	var out []domain.PublicPlaylist
+	for _, p := range m.public {
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (m *memDiscovery) RecordCopy(ctx context.Context, sourceID, copyID string, at time.Time) error {
+	if m.copyErr != nil {
+		return m.copyErr
+	}
+	m.copies[copyID] = sourceID
+	return nil
+}
+
+func TestOrchestrator_Discovery(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemDiscovery("pl-1")
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Name: "Road Trip", Tracks: []domain.Track{{ID: "t1"}, {ID: "t2"}}}}
+	o := NewOrchestrator(nil, repo, nil, WithDiscovery(store))
+	o.discovery.now = func() time.Time { return now }
+
+	if _, err := o.PublishPlaylist(ctx, "missing", "alice"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound publishing an unknown playlist, got %v", err)
+	}
+	if _, err := o.CopyPlaylist(ctx, "pl-1", ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound copying a private playlist, got %v", err)
+	}
+
+	public, err := o.PublishPlaylist(ctx, "pl-1", "alice")
+	if err != nil || public.Owner != "alice" || !public.PublishedAt.Equal(now) {
+		t.Fatalf("unexpected public playlist %+v (%v)", public, err)
+	}
+	if list, err := o.Discover(ctx, domain.DiscoverTrending, 10); err != nil || len(list) != 1 {
+		t.Fatalf("unexpected discovery %+v (%v)", list, err)
+	}
+	if !store.listSince.Equal(now.Add(-domain.TrendingWindow)) {
+		t.Fatalf("expected plays counted from %v, got %v", now.Add(-domain.TrendingWindow), store.listSince)
+	}
+
+	copied, err := o.CopyPlaylist(ctx, "pl-1", "")
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if copied.ID == "pl-1" || copied.Name != "Road Trip" || len(copied.Tracks) != 2 {
+		t.Fatalf("unexpected copy %+v", copied)
+	}
+	if repo.saved == nil || repo.saved.ID != copied.ID || store.copies[copied.ID] != "pl-1" {
+		t.Fatalf("expected the copy saved and counted, got %+v and %v", repo.saved, store.copies)
+	}
+	if renamed, err := o.CopyPlaylist(ctx, "pl-1", "Mine"); err != nil || renamed.Name != "Mine" {
+		t.Fatalf("unexpected renamed copy %+v (%v)", renamed, err)
+	}
+
+	if err := o.UnpublishPlaylist(ctx, "pl-1"); err != nil {
+		t.Fatalf("unpublish: %v", err)
+	}
+	if _, err := o.CopyPlaylist(ctx, "pl-1", ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after unpublishing, got %v", err)
+	}
+	if _, err := NewOrchestrator(nil, &mockRepo{}, nil).Discover(ctx, domain.DiscoverRecent, 10); err == nil {
+		t.Fatal("expected error without discovery configured")
+	}
+}
+
+func TestOrchestrator_CopyPlaylist_CountFailure(t *testing.T) {
+	store := newMemDiscovery("pl-1")
+	store.copyErr = errors.New("locked")
+	reporter := &fakeReporter{}
+	o := NewOrchestrator(nil, &mockRepo{}, nil, WithDiscovery(store), WithErrorReporter(reporter))
+	if _, err := o.PublishPlaylist(context.Background(), "pl-1", "alice"); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if _, err := o.CopyPlaylist(context.Background(), "pl-1", ""); err != nil {
+		t.Fatalf("expected the copy to succeed, got %v", err)
+	}
+	if len(reporter.errs) != 1 {
+		t.Fatalf("expected the lost count reported, got %d reports", len(reporter.errs))
+	}
+}
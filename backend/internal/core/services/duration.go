@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// maxFitTracks bounds the candidates fitDuration searches, keeping its
+// table small for long targets.
+const maxFitTracks = 200
+
+// fillDuration picks tracks, in order, until a playlist that is already
+// startMs long reaches the target within its tolerance. Tracks that would
+// overshoot are passed over in favor of later, shorter ones; tracks of
+// unknown length cannot count towards the target and are left out. When
+// picking in order misses the target, as four- and seven-minute tracks miss
+// ten minutes, it falls back to fitDuration. It returns the picks and the
+// resulting total length.
+func fillDuration(tracks []domain.Track, startMs int, target domain.DurationTarget) ([]domain.Track, int) {
+	tolerance := target.Tolerance()
+	total := startMs
+	var picked []domain.Track
+	for _, t := range tracks {
+		if total >= target.DurationMs-tolerance {
+			break
+		}
+		if t.DurationMs <= 0 || total+t.DurationMs > target.DurationMs+tolerance {
+			continue
+		}
+		picked = append(picked, t)
+		total += t.DurationMs
+	}
+	if total >= target.DurationMs-tolerance {
+		return picked, total
+	}
+	if fit, ok := fitDuration(tracks, startMs, target); ok {
+		total = startMs
+		for _, t := range fit {
+			total += t.DurationMs
+		}
+		return fit, total
+	}
+	return picked, total
+}
+
+// fitDuration solves the subset-sum knapsack over track lengths in whole
+// seconds: of the totals within the tolerance, it picks the one closest to
+// the target, reached with the earliest tracks possible. ok is false when no
+// subset of the first maxFitTracks tracks lands within the tolerance.
+func fitDuration(tracks []domain.Track, startMs int, target domain.DurationTarget) (_ []domain.Track, ok bool) {
+	tolerance := target.Tolerance()
+	need := target.DurationMs - startMs
+	highest := (need + tolerance) / 1000
+	if highest <= 0 {
+		return nil, false
+	}
+	tracks = tracks[:min(len(tracks), maxFitTracks)]
+
+	// by[s] is the track that first brought the total to s seconds, or -1
+	// while s is out of reach; earlier tracks get first claim.
+	by := make([]int, highest+1)
+	for s := range by {
+		by[s] = -1
+	}
+	for i, t := range tracks {
+		length := (t.DurationMs + 500) / 1000
+		if t.DurationMs <= 0 || length > highest {
+			continue
+		}
+		// Downwards, so each track is counted at most once per total.
+		for s := highest; s > length; s-- {
+			if by[s] < 0 && by[s-length] >= 0 {
+				by[s] = i
+			}
+		}
+		if by[length] < 0 {
+			by[length] = i
+		}
+	}
+
+	best := -1
+	for s := max((need-tolerance+999)/1000, 1); s <= highest; s++ {
+		if by[s] >= 0 && (best < 0 || abs(s*1000-need) < abs(best*1000-need)) {
+			best = s
+		}
+	}
+	if best < 0 {
+		return nil, false
+	}
+	picks := make([]bool, len(tracks))
+	for s := best; s > 0; {
+		i := by[s]
+		picks[i] = true
+		s -= (tracks[i].DurationMs + 500) / 1000
+	}
+	var fit []domain.Track
+	for i, t := range tracks {
+		if picks[i] {
+			fit = append(fit, t)
+		}
+	}
+	return fit, true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// formatMinutes renders a length as m:ss.
+func formatMinutes(ms int) string {
+	s := ms / 1000
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
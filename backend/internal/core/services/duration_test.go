@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+const minute = 60 * 1000
+
+func TestFillDuration(t *testing.T) {
+	tracks := func(lengths ...int) []domain.Track {
+		out := make([]domain.Track, len(lengths))
+		for i, ms := range lengths {
+			out[i] = domain.Track{ID: string(rune('a' + i)), DurationMs: ms}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		tracks    []domain.Track
+		startMs   int
+		target    domain.DurationTarget
+		wantIDs   string
+		wantTotal int
+	}{
+		{
+			name:      "stops once within tolerance",
+			tracks:    tracks(4*minute, 4*minute, 4*minute, 4*minute),
+			target:    domain.DurationTarget{DurationMs: 10 * minute, ToleranceMs: 2 * minute},
+			wantIDs:   "ab",
+			wantTotal: 8 * minute,
+		},
+		{
+			name:      "skips tracks that overshoot",
+			tracks:    tracks(5*minute, 9*minute, 3*minute),
+			target:    domain.DurationTarget{DurationMs: 8 * minute, ToleranceMs: 30 * 1000},
+			wantIDs:   "ac",
+			wantTotal: 8 * minute,
+		},
+		{
+			name:      "counts the existing playlist",
+			tracks:    tracks(3*minute, 3*minute),
+			startMs:   6 * minute,
+			target:    domain.DurationTarget{DurationMs: 9 * minute, ToleranceMs: 1000},
+			wantIDs:   "a",
+			wantTotal: 9 * minute,
+		},
+		{
+			name:      "already long enough",
+			tracks:    tracks(3 * minute),
+			startMs:   20 * minute,
+			target:    domain.DurationTarget{DurationMs: 10 * minute},
+			wantTotal: 20 * minute,
+		},
+		{
+			name:      "unknown lengths are left out",
+			tracks:    tracks(0, 4*minute),
+			target:    domain.DurationTarget{DurationMs: 4 * minute},
+			wantIDs:   "b",
+			wantTotal: 4 * minute,
+		},
+		{
+			name:      "fits when picking in order misses",
+			tracks:    tracks(7*minute, 7*minute, 5*minute, 5*minute),
+			target:    domain.DurationTarget{DurationMs: 10 * minute, ToleranceMs: 30 * 1000},
+			wantIDs:   "cd",
+			wantTotal: 10 * minute,
+		},
+		{
+			name:      "fits the closest total",
+			tracks:    tracks(8*minute, 5*minute, 9*minute/2, 11*minute/2),
+			target:    domain.DurationTarget{DurationMs: 10 * minute, ToleranceMs: minute},
+			wantIDs:   "cd",
+			wantTotal: 10 * minute,
+		},
+		{
+			name:      "runs out of candidates",
+			tracks:    tracks(3 * minute),
+			target:    domain.DurationTarget{DurationMs: 45 * minute},
+			wantIDs:   "a",
+			wantTotal: 3 * minute,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			picked, total := fillDuration(tc.tracks, tc.startMs, tc.target)
+			ids := ""
+			for _, tr := range picked {
+				ids += tr.ID
+			}
+			if ids != tc.wantIDs || total != tc.wantTotal {
+				t.Fatalf("expected %q totaling %d, got %q totaling %d", tc.wantIDs, tc.wantTotal, ids, total)
+			}
+		})
+	}
+}
+
+// catalogSpotify returns the same top tracks for every artist.
+type catalogSpotify struct {
+	mockSpotify
+	tracks []domain.Track
+}
+
+func (c *catalogSpotify) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	return c.tracks, nil
+}
+
+func TestOrchestrator_ProcessIntentWithDuration(t *testing.T) {
+	var catalog []domain.Track
+	for i := 0; i < 10; i++ {
+		catalog = append(catalog, domain.Track{ID: string(rune('a' + i)), DurationMs: 4 * minute})
+	}
+	intent := domain.IntentObject{}
+	intent.Entities.Artists = []string{"Artist"}
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "old", DurationMs: 5 * minute}}}}
+	o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, &mockIntentCompiler{intent: intent})
+
+	result, err := o.ProcessIntentWithDuration(context.Background(), "pl-1", "fill 25 minutes", domain.DurationTarget{DurationMs: 25 * minute, ToleranceMs: minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 5 minutes already there plus five 4-minute tracks.
+	if result.TracksAdded != 5 || result.DurationMs != 25*minute || result.TargetDurationMs != 25*minute {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if result.TracksEvaluated != len(catalog) {
+		t.Fatalf("expected %d tracks evaluated, got %d", len(catalog), result.TracksEvaluated)
+	}
+}
+
+func TestOrchestrator_ProcessIntent_IntentDuration(t *testing.T) {
+	var catalog []domain.Track
+	for i := 0; i < 20; i++ {
+		catalog = append(catalog, domain.Track{ID: string(rune('a' + i)), DurationMs: 4 * minute})
+	}
+	intent := domain.IntentObject{Duration: &domain.DurationConstraint{Minutes: 45}}
+	intent.Entities.Artists = []string{"Artist"}
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}
+	o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, &mockIntentCompiler{intent: intent})
+
+	result, err := o.ProcessIntent(context.Background(), "pl-1", "a 45-minute running mix")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Eleven 4-minute tracks come within two minutes of 45.
+	if result.TracksAdded != 11 || result.DurationMs != 44*minute || result.TargetDurationMs != 45*minute {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if !strings.Contains(result.Summary, "(44:00 of 45:00)") {
+		t.Fatalf("expected the runtime in the summary, got %q", result.Summary)
+	}
+}
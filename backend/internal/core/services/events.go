@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// WithEvents publishes events that are not recorded in the outbox, such as
+// EventIntentProcessed, to sink. Playlist mutations, including the tracks
+// AddTrackToPlaylist adds, reach it through the outbox relay instead.
+func WithEvents(sink ports.EventSink) Option {
+	return func(o *Orchestrator) {
+		o.events = sink
+	}
+}
+
+// publish sends an event to the configured sink. Delivery is best effort: a
+// failure is logged and does not fail the operation that caused it.
+func (o *Orchestrator) publish(ctx context.Context, eventType, playlistID string, payload any) {
+	if o.events == nil {
+		return
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	event := domain.Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		PlaylistID: playlistID,
+		Payload:    raw,
+		OccurredAt: time.Now().UTC(),
+	}
+	if err := o.events.Publish(ctx, event); err != nil {
+		o.logger.WarnContext(ctx, "failed to publish event", "type", eventType, "playlist_id", playlistID, "error", err)
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type recordingSink struct {
+	events []domain.Event
+}
+
+func (s *recordingSink) Publish(ctx context.Context, event domain.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestOrchestrator_PublishesIntentProcessed(t *testing.T) {
+	intent := domain.IntentObject{IntentType: "add"}
+	intent.Entities.Artists = []string{"Artist"}
+	catalog := []domain.Track{{ID: "a", DurationMs: minute}, {ID: "b", DurationMs: minute}}
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}
+	compiler := &mockIntentCompiler{intent: intent}
+	sink := &recordingSink{}
+	o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, compiler, WithEvents(sink))
+
+	result, err := o.ProcessIntent(context.Background(), "pl-1", "more Artist")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sink.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(sink.events))
+	}
+	event := sink.events[0]
+	if event.Type != domain.EventIntentProcessed || event.PlaylistID != "pl-1" || event.ID == "" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	var payload domain.IntentProcessedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.IntentType != "add" || payload.TracksAdded != result.TracksAdded || payload.TracksEvaluated != result.TracksEvaluated {
+		t.Fatalf("unexpected payload %+v for result %+v", payload, result)
+	}
+
+	compiler.err = errors.New("model offline")
+	if _, err := o.ProcessIntent(context.Background(), "pl-1", "more Artist"); err == nil {
+		t.Fatal("expected the compiler error")
+	}
+	if len(sink.events) != 1 {
+		t.Fatalf("failed intents should not publish, got %d events", len(sink.events))
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// assignment is the experiment arm an intent run is scored with.
+type assignment struct {
+	experiment string
+	variant    string
+	scoring    domain.ScoringConfig
+}
+
+// WithExperiment splits intent runs between the experiment's scoring
+// variants. Runs are assigned per playlist, so a playlist always sees the
+// same variant. exp must be valid; see domain.Experiment.Validate.
+func WithExperiment(exp domain.Experiment) Option {
+	return func(o *Orchestrator) {
+		o.experiment = &exp
+	}
+}
+
+// baselineScoring is the scoring used outside an experiment.
+func (o *Orchestrator) baselineScoring() domain.ScoringConfig {
+	return domain.ScoringConfig{RankByTarget: o.enabled(domain.FlagTargetScoring)}
+}
+
+// assign picks the scoring for an intent run on playlistID.
+func (o *Orchestrator) assign(playlistID string) assignment {
+	if o.experiment == nil {
+		return assignment{scoring: o.baselineScoring()}
+	}
+	v := o.experiment.Assign(playlistID)
+	return assignment{experiment: o.experiment.Name, variant: v.Name, scoring: v.Scoring}
+}
+
+// ExperimentReport aggregates up to limit recorded intent runs, newest
+// first, per experiment variant. A track added by a run counts as kept while
+// it is still in the playlist; runs on deleted playlists are skipped. Runs
+// outside an experiment are reported under empty names as the baseline.
+func (o *Orchestrator) ExperimentReport(ctx context.Context, limit int) (domain.ExperimentReport, error) {
+	if o.runs == nil {
+		return domain.ExperimentReport{}, fmt.Errorf("service: intent run recording not configured")
+	}
+	runs, err := o.runs.ListIntentRuns(ctx, limit)
+	if err != nil {
+		return domain.ExperimentReport{}, fmt.Errorf("service: failed to load intent runs: %w", err)
+	}
+
+	type key struct{ experiment, variant string }
+	reports := map[key]*domain.VariantReport{}
+	current := map[string]map[string]bool{}
+	report := domain.ExperimentReport{Variants: []domain.VariantReport{}}
+	for _, run := range runs {
+		tracks, ok := current[run.PlaylistID]
+		if !ok {
+			playlist, err := o.repo.GetByID(ctx, run.PlaylistID)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return domain.ExperimentReport{}, fmt.Errorf("service: failed to load playlist: %w", err)
+			}
+			if err == nil {
+				tracks = make(map[string]bool, len(playlist.Tracks))
+				for _, t := range playlist.Tracks {
+					tracks[t.ID] = true
+				}
+			}
+			current[run.PlaylistID] = tracks
+		}
+		if tracks == nil {
+			continue
+		}
+
+		k := key{run.Experiment, run.Variant}
+		r := reports[k]
+		if r == nil {
+			r = &domain.VariantReport{Experiment: run.Experiment, Variant: run.Variant}
+			reports[k] = r
+		}
+		report.Runs++
+		r.Runs++
+		r.TracksEvaluated += len(run.Candidates)
+		r.TracksAdded += len(run.Added)
+		for _, id := range run.Added {
+			if tracks[id] {
+				r.TracksKept++
+			}
+		}
+	}
+
+	for _, r := range reports {
+		if r.TracksEvaluated > 0 {
+			r.AddRate = float64(r.TracksAdded) / float64(r.TracksEvaluated)
+		}
+		if r.TracksAdded > 0 {
+			r.AcceptanceRate = float64(r.TracksKept) / float64(r.TracksAdded)
+		}
+		report.Variants = append(report.Variants, *r)
+	}
+	sort.Slice(report.Variants, func(i, j int) bool {
+		a, b := report.Variants[i], report.Variants[j]
+		if a.Experiment != b.Experiment {
+			return a.Experiment < b.Experiment
+		}
+		return a.Variant < b.Variant
+	})
+	return report, nil
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithIntentFallback compiles intents with fallback when the intent
+// compiler fails, so intents still add tracks while it is down. Results
+// compiled this way are marked Degraded.
+func WithIntentFallback(fallback ports.IntentCompiler) Option {
+	return func(o *Orchestrator) {
+		o.fallback = fallback
+	}
+}
+
+// analyzeIntentOrFallback is analyzeIntent, falling back to o.fallback
+// when the compiler fails for any reason but the caller giving up. degraded
+// reports whether it did.
+func (o *Orchestrator) analyzeIntentOrFallback(ctx context.Context, history []domain.IntentTurn, message string, onDelta func(domain.IntentDelta)) (_ domain.IntentObject, degraded bool, _ error) {
+	intent, err := o.analyzeIntent(ctx, history, message, onDelta)
+	if err == nil || o.fallback == nil || ctx.Err() != nil {
+		return intent, false, err
+	}
+	o.logger.WarnContext(ctx, "intent compiler failed; using the fallback", "error", err)
+	o.report(ctx, err, map[string]string{"operation": "analyze_intent"})
+	intent, fallbackErr := o.fallback.AnalyzeIntent(ctx, message)
+	if fallbackErr != nil {
+		return domain.IntentObject{}, false, err
+	}
+	return intent, true, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestOrchestrator_ProcessIntent_Fallback(t *testing.T) {
+	fallbackIntent := domain.IntentObject{IntentType: "CREATE"}
+	fallbackIntent.Entities.Artists = []string{"Artist"}
+
+	tests := []struct {
+		name         string
+		compiler     *mockIntentCompiler
+		fallback     *mockIntentCompiler
+		wantErr      bool
+		wantDegraded bool
+		wantAdded    int
+		wantReports  int
+	}{
+		{
+			name:      "compiler works",
+			compiler:  &mockIntentCompiler{intent: fallbackIntent},
+			fallback:  &mockIntentCompiler{err: errors.New("unused")},
+			wantAdded: 1,
+		},
+		{
+			name:         "compiler down",
+			compiler:     &mockIntentCompiler{err: errors.New("connection refused")},
+			fallback:     &mockIntentCompiler{intent: fallbackIntent},
+			wantDegraded: true,
+			wantAdded:    1,
+			wantReports:  1,
+		},
+		{
+			name:        "fallback fails too",
+			compiler:    &mockIntentCompiler{err: errors.New("connection refused")},
+			fallback:    &mockIntentCompiler{err: errors.New("no rules")},
+			wantErr:     true,
+			wantReports: 2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}
+			spotify := &catalogSpotify{tracks: []domain.Track{{ID: "t1"}}}
+			reporter := &fakeReporter{}
+			o := NewOrchestrator(spotify, repo, tc.compiler, WithIntentFallback(tc.fallback), WithErrorReporter(reporter))
+
+			result, err := o.ProcessIntent(context.Background(), "pl-1", "songs like Artist")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tc.wantErr)
+			}
+			if result.Degraded != tc.wantDegraded || result.TracksAdded != tc.wantAdded {
+				t.Fatalf("expected degraded=%v with %d added, got %v with %d", tc.wantDegraded, tc.wantAdded, result.Degraded, result.TracksAdded)
+			}
+			if tc.fallback.called != (tc.compiler.err != nil) {
+				t.Fatalf("fallback called = %v, want %v", tc.fallback.called, tc.compiler.err != nil)
+			}
+			if len(reporter.errs) != tc.wantReports {
+				t.Fatalf("expected %d reports, got %d", tc.wantReports, len(reporter.errs))
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithFeatureLookup looks up measured features with provider, by ISRC, for
+// stored tracks that have none. tracks resolves stored track IDs to their
+// ISRCs.
+func WithFeatureLookup(provider ports.FeatureProvider, tracks ports.TrackLookup) Option {
+	return func(o *Orchestrator) {
+		o.features = provider
+		o.tracks = tracks
+	}
+}
+
+// LookupFeatures resolves and stores measured audio features for a stored
+// track. ok is false when the lookup is not configured, the track is an
+// episode or has no ISRC, or the provider knows no features for it.
+func (o *Orchestrator) LookupFeatures(ctx context.Context, trackID string) (features domain.AudioFeatures, ok bool) {
+	if o.features == nil {
+		return domain.AudioFeatures{}, false
+	}
+	features, err := o.lookupFeatures(ctx, trackID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			o.report(ctx, err, map[string]string{"operation": "lookup_features", "track_id": trackID})
+		}
+		return domain.AudioFeatures{}, false
+	}
+	return features, true
+}
+
+func (o *Orchestrator) lookupFeatures(ctx context.Context, trackID string) (domain.AudioFeatures, error) {
+	track, err := o.tracks.GetTrack(ctx, trackID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.AudioFeatures{}, err
+		}
+		return domain.AudioFeatures{}, fmt.Errorf("service: failed to load track: %w", err)
+	}
+	if track.IsEpisode() || track.ISRC == "" {
+		return domain.AudioFeatures{}, fmt.Errorf("service: track has no recording to look up: %w", domain.ErrNotFound)
+	}
+
+	features, err := o.features.GetFeatures(ctx, track.ISRC)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.AudioFeatures{}, err
+		}
+		return domain.AudioFeatures{}, fmt.Errorf("service: failed to look up features: %w", err)
+	}
+	if err := o.repo.UpdateTrackFeatures(ctx, trackID, features); err != nil {
+		return domain.AudioFeatures{}, fmt.Errorf("service: failed to save features: %w", err)
+	}
+	return features, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// stubFeatures serves features by ISRC.
+type stubFeatures struct {
+	byISRC map[string]domain.AudioFeatures
+	err    error
+}
+
+func (s stubFeatures) GetFeatures(ctx context.Context, isrc string) (domain.AudioFeatures, error) {
+	if s.err != nil {
+		return domain.AudioFeatures{}, s.err
+	}
+	f, ok := s.byISRC[isrc]
+	if !ok {
+		return domain.AudioFeatures{}, domain.ErrNotFound
+	}
+	return f, nil
+}
+
+// featuresRepo records the features stored for each track.
+type featuresRepo struct {
+	mockRepo
+	stored map[string]domain.AudioFeatures
+}
+
+func (r *featuresRepo) UpdateTrackFeatures(ctx context.Context, trackID string, features domain.AudioFeatures) error {
+	r.stored[trackID] = features
+	return nil
+}
+
+func TestLookupFeatures(t *testing.T) {
+	measured := domain.AudioFeatures{Energy: 0.7, Valence: 0.4, Tempo: 118}
+	tracks := newMemLyrics(
+		domain.Track{ID: "t-known", ISRC: "USRC17607839"},
+		domain.Track{ID: "t-unknown", ISRC: "GBAYE0000001"},
+		domain.Track{ID: "t-no-isrc"},
+		domain.Track{ID: "ep-1", ISRC: "USRC17607839", Type: domain.ItemEpisode},
+	)
+
+	tests := []struct {
+		name        string
+		trackID     string
+		providerErr error
+		wantOK      bool
+		wantReport  bool
+	}{
+		{name: "looks up and stores", trackID: "t-known", wantOK: true},
+		{name: "provider knows none", trackID: "t-unknown"},
+		{name: "no isrc", trackID: "t-no-isrc"},
+		{name: "episodes are skipped", trackID: "ep-1"},
+		{name: "unknown track", trackID: "nope"},
+		{name: "provider failure", trackID: "t-known", providerErr: errors.New("boom"), wantReport: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := stubFeatures{byISRC: map[string]domain.AudioFeatures{"USRC17607839": measured}, err: tc.providerErr}
+			repo := &featuresRepo{stored: map[string]domain.AudioFeatures{}}
+			reporter := &fakeReporter{}
+			svc := NewOrchestrator(nil, repo, nil,
+				WithFeatureLookup(provider, tracks),
+				WithErrorReporter(reporter))
+
+			got, ok := svc.LookupFeatures(context.Background(), tc.trackID)
+			if ok != tc.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tc.wantOK, ok)
+			}
+			if stored, found := repo.stored[tc.trackID]; found != tc.wantOK || (ok && (got != measured || stored != measured)) {
+				t.Fatalf("expected %+v returned and stored, got %+v and %+v", measured, got, repo.stored)
+			}
+			if (len(reporter.errs) > 0) != tc.wantReport {
+				t.Errorf("expected report=%v, got %v", tc.wantReport, reporter.errs)
+			}
+		})
+	}
+
+	if _, ok := NewOrchestrator(nil, nil, nil).LookupFeatures(context.Background(), "t-known"); ok {
+		t.Fatal("expected no features without a provider")
+	}
+}
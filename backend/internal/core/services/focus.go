@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// focusMode detects focus blocks in the user's calendar.
+type focusMode struct {
+	calendar ports.CalendarProvider
+	keywords []string
+	now      func() time.Time
+}
+
+// WithFocus biases intents processed during a focus block towards
+// instrumental, low-energy tracks. Calendar events whose summary contains
+// one of keywords are focus blocks; empty keywords use
+// domain.DefaultFocusKeywords.
+func WithFocus(calendar ports.CalendarProvider, keywords []string) Option {
+	return func(o *Orchestrator) {
+		if len(keywords) == 0 {
+			keywords = domain.DefaultFocusKeywords
+		}
+		o.focus = &focusMode{calendar: calendar, keywords: keywords, now: time.Now}
+	}
+}
+
+// HasFocus returns true if calendar-aware focus mode is available.
+func (o *Orchestrator) HasFocus() bool {
+	return o.focus != nil
+}
+
+// CurrentFocusBlock returns the focus block in progress, if any.
+func (o *Orchestrator) CurrentFocusBlock(ctx context.Context) (domain.CalendarEvent, bool, error) {
+	if !o.HasFocus() {
+		return domain.CalendarEvent{}, false, fmt.Errorf("service: focus mode not configured")
+	}
+	now := o.focus.now()
+	events, err := o.focus.calendar.Events(ctx, now, now.Add(time.Second))
+	if err != nil {
+		return domain.CalendarEvent{}, false, fmt.Errorf("service: failed to load calendar: %w", err)
+	}
+	block, ok := domain.FocusBlock(events, o.focus.keywords, now)
+	return block, ok, nil
+}
+
+// TriggerFocus processes message as an intent for playlistID with the
+// focus bias applied. Unless force is set, it returns domain.ErrNoFocusBlock
+// outside a focus block, so automations can fire it on a schedule.
+func (o *Orchestrator) TriggerFocus(ctx context.Context, playlistID, message string, force bool) (domain.IntentResult, error) {
+	if !o.HasFocus() {
+		return domain.IntentResult{}, fmt.Errorf("service: focus mode not configured")
+	}
+	if !force {
+		_, ok, err := o.CurrentFocusBlock(ctx)
+		if err != nil {
+			o.report(ctx, err, map[string]string{"operation": "trigger_focus", "playlist_id": playlistID})
+			return domain.IntentResult{}, err
+		}
+		if !ok {
+			return domain.IntentResult{}, fmt.Errorf("service: %w", domain.ErrNoFocusBlock)
+		}
+	}
+	result, err := o.processIntent(ctx, playlistID, message, domain.IntentOptions{}, true, nil)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		o.report(ctx, err, map[string]string{"operation": "trigger_focus", "playlist_id": playlistID})
+	}
+	return result, err
+}
+
+// applyFocus fills in intent with the focus bias when forced or during a
+// focus block. It reports whether anything was filled in. Calendar failures
+// are reported and treated as no focus block.
+func (o *Orchestrator) applyFocus(ctx context.Context, intent *domain.IntentObject, force bool) bool {
+	if !force {
+		if !o.HasFocus() {
+			return false
+		}
+		_, ok, err := o.CurrentFocusBlock(ctx)
+		if err != nil {
+			o.report(ctx, err, map[string]string{"operation": "apply_focus"})
+			return false
+		}
+		if !ok {
+			return false
+		}
+	}
+	return domain.ApplyFocus(intent)
+}
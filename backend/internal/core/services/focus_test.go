@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// stubCalendar returns the same events for any window.
+type stubCalendar struct {
+	events []domain.CalendarEvent
+	err    error
+}
+
+func (s *stubCalendar) Events(ctx context.Context, from, to time.Time) ([]domain.CalendarEvent, error) {
+	return s.events, s.err
+}
+
+func TestOrchestrator_Focus(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	block := domain.CalendarEvent{Summary: "Focus time", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}
+	meeting := domain.CalendarEvent{Summary: "1:1", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}
+
+	tests := []struct {
+		name        string
+		calendar    *stubCalendar
+		trigger     bool
+		force       bool
+		wantErr     error
+		wantFocus   bool
+		wantReports int
+	}{
+		{name: "intent during focus block", calendar: &stubCalendar{events: []domain.CalendarEvent{block}}, wantFocus: true},
+		{name: "intent during meeting", calendar: &stubCalendar{events: []domain.CalendarEvent{meeting}}},
+		{name: "intent with calendar down", calendar: &stubCalendar{err: errors.New("timeout")}, wantReports: 1},
+		{name: "trigger during focus block", calendar: &stubCalendar{events: []domain.CalendarEvent{block}}, trigger: true, wantFocus: true},
+		{name: "trigger outside focus block", calendar: &stubCalendar{events: []domain.CalendarEvent{meeting}}, trigger: true, wantErr: domain.ErrNoFocusBlock},
+		{name: "forced trigger", calendar: &stubCalendar{}, trigger: true, force: true, wantFocus: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			intent := domain.IntentObject{}
+			intent.Entities.Artists = []string{"Artist"}
+			reporter := &fakeReporter{}
+			o := NewOrchestrator(&catalogSpotify{}, &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}, &mockIntentCompiler{intent: intent},
+				WithFocus(tc.calendar, nil), WithErrorReporter(reporter))
+			o.focus.now = func() time.Time { return now }
+
+			var result domain.IntentResult
+			var err error
+			if tc.trigger {
+				result, err = o.TriggerFocus(context.Background(), "pl-1", "music to code to", tc.force)
+			} else {
+				result, err = o.ProcessIntent(context.Background(), "pl-1", "music to code to")
+			}
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Focus != tc.wantFocus || len(reporter.errs) != tc.wantReports {
+				t.Fatalf("expected focus=%v with %d reports, got %v with %d", tc.wantFocus, tc.wantReports, result.Focus, len(reporter.errs))
+			}
+			if (result.Intent.VibeConstraints.Instrument != nil) != tc.wantFocus {
+				t.Fatalf("unexpected instrumentalness constraint %+v", result.Intent.VibeConstraints.Instrument)
+			}
+		})
+	}
+
+	if _, err := NewOrchestrator(nil, &mockRepo{}, nil).TriggerFocus(context.Background(), "pl-1", "x", true); err == nil {
+		t.Fatal("expected error without focus mode configured")
+	}
+}
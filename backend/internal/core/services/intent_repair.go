@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// WithIntentValidation rejects intents that fail domain validation, such
+// as vibe constraints outside 0 to 1. Before rejecting one, the compiler
+// is asked up to repairs times to correct it, with the problems listed.
+func WithIntentValidation(repairs int) Option {
+	return func(o *Orchestrator) {
+		o.validateIntents = true
+		o.intentRepairs = max(repairs, 0)
+	}
+}
+
+// repairIntent returns intent if it is valid. Otherwise it asks the
+// compiler to correct it, as a follow-up to the message that produced it,
+// until it is valid or the repairs run out.
+func (o *Orchestrator) repairIntent(ctx context.Context, history []domain.IntentTurn, message string, intent domain.IntentObject, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	err := intent.Validate()
+	for attempt := 0; err != nil && attempt < o.intentRepairs; attempt++ {
+		var invalid domain.InvalidIntentError
+		if !errors.As(err, &invalid) {
+			break
+		}
+		o.logger.InfoContext(ctx, "repairing invalid intent", "attempt", attempt+1, "problems", strings.Join(invalid.Problems, "; "))
+		history = append(history, domain.IntentTurn{Message: message, Intent: intent})
+		message = repairPrompt(invalid)
+		intent, err = o.compileIntent(ctx, history, message, onDelta)
+		if err != nil {
+			return domain.IntentObject{}, err
+		}
+		err = intent.Validate()
+	}
+	if err != nil {
+		return domain.IntentObject{}, fmt.Errorf("service: %w", err)
+	}
+	return intent, nil
+}
+
+// repairPrompt asks the compiler to correct the problems of its last
+// intent.
+func repairPrompt(invalid domain.InvalidIntentError) string {
+	var b strings.Builder
+	b.WriteString("The intent you returned is invalid:\n")
+	for _, p := range invalid.Problems {
+		b.WriteString("- " + p + "\n")
+	}
+	b.WriteString("Return the corrected intent as JSON, changing only what is needed to fix these problems.")
+	return b.String()
+}
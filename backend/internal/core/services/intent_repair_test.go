@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// repairingCompiler returns its intents in turn, recording the messages it
+// was sent.
+type repairingCompiler struct {
+	intents  []domain.IntentObject
+	messages []string
+}
+
+func (c *repairingCompiler) AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error) {
+	c.messages = append(c.messages, message)
+	intent := c.intents[0]
+	if len(c.intents) > 1 {
+		c.intents = c.intents[1:]
+	}
+	return intent, nil
+}
+
+func TestOrchestrator_ProcessIntent_Repair(t *testing.T) {
+	valid := domain.IntentObject{IntentType: domain.IntentCreate}
+	valid.Entities.Artists = []string{"Artist"}
+	invalid := valid
+	invalid.VibeConstraints.Energy = &domain.VibeConstraint{Min: 0, Max: 7}
+
+	tests := []struct {
+		name      string
+		intents   []domain.IntentObject
+		repairs   int
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "valid at once", intents: []domain.IntentObject{valid}, repairs: 2, wantCalls: 1},
+		{name: "repaired", intents: []domain.IntentObject{invalid, valid}, repairs: 2, wantCalls: 2},
+		{name: "never repaired", intents: []domain.IntentObject{invalid}, repairs: 2, wantErr: domain.ErrInvalidIntent, wantCalls: 3},
+		{name: "no repairs", intents: []domain.IntentObject{invalid, valid}, wantErr: domain.ErrInvalidIntent, wantCalls: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			compiler := &repairingCompiler{intents: tc.intents}
+			repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}
+			spotify := &catalogSpotify{tracks: []domain.Track{{ID: "t1"}}}
+			o := NewOrchestrator(spotify, repo, compiler, WithIntentValidation(tc.repairs))
+
+			_, err := o.ProcessIntent(context.Background(), "pl-1", "songs like Artist")
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+			if len(compiler.messages) != tc.wantCalls {
+				t.Fatalf("expected %d compiler calls, got %d", tc.wantCalls, len(compiler.messages))
+			}
+			if tc.wantCalls > 1 {
+				repair := compiler.messages[1]
+				if !strings.Contains(repair, "vibe_constraints.energy.max must be between 0 and 1") || !strings.Contains(repair, "songs like Artist") {
+					t.Fatalf("expected the repair request to carry the problems and the original message, got %q", repair)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithTrackLibrary lets clients browse every stored track, as a library
+// view across playlists.
+func WithTrackLibrary(library ports.TrackLibrary) Option {
+	return func(o *Orchestrator) {
+		o.library = library
+	}
+}
+
+// HasTrackLibrary returns true if the track library is available.
+func (o *Orchestrator) HasTrackLibrary() bool {
+	return o.library != nil
+}
+
+// ListTracks returns the page of the library filter selects. It returns
+// domain.ErrInvalidTrackFilter for unknown features, empty ranges and
+// pages out of bounds.
+func (o *Orchestrator) ListTracks(ctx context.Context, filter domain.TrackFilter) (domain.TrackPage, error) {
+	if !o.HasTrackLibrary() {
+		return domain.TrackPage{}, fmt.Errorf("service: track library not configured")
+	}
+	if err := filter.Validate(); err != nil {
+		return domain.TrackPage{}, err
+	}
+	tracks, total, err := o.library.ListTracks(ctx, filter)
+	if err != nil {
+		return domain.TrackPage{}, fmt.Errorf("service: failed to list tracks: %w", err)
+	}
+	if tracks == nil {
+		tracks = []domain.Track{}
+	}
+	return domain.TrackPage{Tracks: tracks, Total: total, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+// GetTrack returns a stored track, or domain.ErrNotFound for unknown IDs.
+func (o *Orchestrator) GetTrack(ctx context.Context, trackID string) (domain.Track, error) {
+	if !o.HasTrackLibrary() {
+		return domain.Track{}, fmt.Errorf("service: track library not configured")
+	}
+	track, err := o.library.GetTrack(ctx, trackID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Track{}, err
+		}
+		return domain.Track{}, fmt.Errorf("service: failed to load track: %w", err)
+	}
+	return track, nil
+}
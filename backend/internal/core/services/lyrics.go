@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithLyrics serves track lyrics from provider, caching them in store.
+// tracks resolves stored track IDs to the metadata the provider matches on.
+func WithLyrics(provider ports.LyricsProvider, store ports.LyricsStore, tracks ports.TrackLookup) Option {
+	return func(o *Orchestrator) {
+		o.lyrics = provider
+		o.lyricStore = store
+		o.tracks = tracks
+	}
+}
+
+// WithLyricValence estimates the valence of intent candidates that have none
+// from the sentiment of their lyrics, so valence constraints can filter
+// them. It needs WithLyrics and costs one lyrics lookup per candidate.
+func WithLyricValence() Option {
+	return func(o *Orchestrator) {
+		o.lyricValence = true
+	}
+}
+
+// HasLyrics returns true if a lyrics provider is configured.
+func (o *Orchestrator) HasLyrics() bool {
+	return o.lyrics != nil
+}
+
+// GetTrackLyrics returns the lyrics of a stored track, fetching and caching
+// them on first request. It returns domain.ErrNotFound for unknown tracks,
+// episodes and tracks the provider has no lyrics for.
+func (o *Orchestrator) GetTrackLyrics(ctx context.Context, trackID string) (domain.Lyrics, error) {
+	if o.lyrics == nil {
+		return domain.Lyrics{}, fmt.Errorf("service: lyrics provider not configured")
+	}
+	track, err := o.tracks.GetTrack(ctx, trackID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Lyrics{}, err
+		}
+		return domain.Lyrics{}, fmt.Errorf("service: failed to load track: %w", err)
+	}
+	if track.IsEpisode() {
+		return domain.Lyrics{}, fmt.Errorf("service: episodes have no lyrics: %w", domain.ErrNotFound)
+	}
+	return o.trackLyrics(ctx, track)
+}
+
+// LyricValence estimates a stored track's valence from its lyrics. ok is
+// false when lyrics are not configured, unavailable or the track is
+// instrumental.
+func (o *Orchestrator) LyricValence(ctx context.Context, trackID string) (valence float64, ok bool) {
+	if o.lyrics == nil {
+		return 0, false
+	}
+	lyrics, err := o.GetTrackLyrics(ctx, trackID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			o.report(ctx, err, map[string]string{"operation": "lyric_valence", "track_id": trackID})
+		}
+		return 0, false
+	}
+	return lyricValence(lyrics)
+}
+
+func lyricValence(l domain.Lyrics) (float64, bool) {
+	if l.Instrumental || l.Plain == "" {
+		return 0, false
+	}
+	return l.Sentiment, true
+}
+
+// trackLyrics returns the cached lyrics for track, or fetches, scores and
+// caches them.
+func (o *Orchestrator) trackLyrics(ctx context.Context, track domain.Track) (domain.Lyrics, error) {
+	lyrics, err := o.lyricStore.GetLyrics(ctx, track.ID)
+	if err == nil {
+		return lyrics, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return domain.Lyrics{}, fmt.Errorf("service: failed to load lyrics: %w", err)
+	}
+
+	lyrics, err = o.lyrics.GetLyrics(ctx, track)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Lyrics{}, err
+		}
+		return domain.Lyrics{}, fmt.Errorf("service: failed to fetch lyrics: %w", err)
+	}
+	lyrics.TrackID = track.ID
+	lyrics.Sentiment = 0.5
+	if !lyrics.Instrumental {
+		lyrics.Sentiment = lyricSentiment(lyrics.Plain)
+	}
+	if err := o.lyricStore.SaveLyrics(ctx, lyrics); err != nil {
+		// Serving uncached lyrics beats failing the request.
+		o.report(ctx, fmt.Errorf("service: failed to save lyrics: %w", err), map[string]string{"operation": "save_lyrics", "track_id": track.ID})
+	}
+	return lyrics, nil
+}
+
+// applyLyricValence fills in the valence of candidates that have none from
+// their lyrics when the intent constrains valence. Lookup failures leave the
+// candidate unchanged.
+func (o *Orchestrator) applyLyricValence(ctx context.Context, candidates []domain.Track, intent domain.IntentObject) {
+	if !o.lyricValence || o.lyrics == nil || intent.VibeConstraints.Valence == nil {
+		return
+	}
+	for i := range candidates {
+		if candidates[i].Features.Valence != 0 {
+			continue
+		}
+		lyrics, err := o.trackLyrics(ctx, candidates[i])
+		if err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				o.report(ctx, err, map[string]string{"operation": "lyric_valence", "track_id": candidates[i].ID})
+			}
+			continue
+		}
+		if v, ok := lyricValence(lyrics); ok {
+			candidates[i].Features.Valence = v
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// stubLyrics serves lyrics for the track IDs it knows and counts lookups.
+type stubLyrics struct {
+	lyrics map[string]domain.Lyrics
+	calls  int
+}
+
+func (s *stubLyrics) GetLyrics(ctx context.Context, track domain.Track) (domain.Lyrics, error) {
+	s.calls++
+	l, ok := s.lyrics[track.ID]
+	if !ok {
+		return domain.Lyrics{}, domain.ErrNotFound
+	}
+	return l, nil
+}
+
+// memLyrics is an in-memory ports.LyricsStore and ports.TrackLookup.
+type memLyrics struct {
+	tracks map[string]domain.Track
+	saved  map[string]domain.Lyrics
+}
+
+func newMemLyrics(tracks ...domain.Track) *memLyrics {
+	m := &memLyrics{tracks: map[string]domain.Track{}, saved: map[string]domain.Lyrics{}}
+	for _, t := range tracks {
+		m.tracks[t.ID] = t
+	}
+	return m
+}
+
+func (m *memLyrics) SaveLyrics(ctx context.Context, l domain.Lyrics) error {
+	m.saved[l.TrackID] = l
+	return nil
+}
+
+func (m *memLyrics) GetLyrics(ctx context.Context, trackID string) (domain.Lyrics, error) {
+	l, ok := m.saved[trackID]
+	if !ok {
+		return domain.Lyrics{}, domain.ErrNotFound
+	}
+	return l, nil
+}
+
+func (m *memLyrics) GetTrack(ctx context.Context, trackID string) (domain.Track, error) {
+	t, ok := m.tracks[trackID]
+	if !ok {
+		return domain.Track{}, domain.ErrNotFound
+	}
+	return t, nil
+}
+
+func TestLyricSentiment(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		check func(float64) bool
+	}{
+		{name: "empty is neutral", text: "", check: func(s float64) bool { return s == 0.5 }},
+		{name: "no lexicon words is neutral", text: "the quick brown fox", check: func(s float64) bool { return s == 0.5 }},
+		{name: "positive", text: "I love you, happy in the sunshine", check: func(s float64) bool { return s > 0.7 }},
+		{name: "negative", text: "Tears in the rain, lonely and broken", check: func(s float64) bool { return s < 0.3 }},
+		{name: "negation flips", text: "I'm not happy, don't smile", check: func(s float64) bool { return s < 0.5 }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := lyricSentiment(tc.text); !tc.check(got) || got < 0 || got > 1 {
+				t.Fatalf("unexpected sentiment %.3f for %q", got, tc.text)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_GetTrackLyrics(t *testing.T) {
+	store := newMemLyrics(domain.Track{ID: "t1", Title: "Sunny"}, domain.Track{ID: "t2", Title: "Hum"})
+	provider := &stubLyrics{lyrics: map[string]domain.Lyrics{"t1": {Plain: "happy happy joy", Source: "stub"}}}
+	o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, WithLyrics(provider, store, store))
+	ctx := context.Background()
+
+	got, err := o.GetTrackLyrics(ctx, "t1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.TrackID != "t1" || got.Sentiment <= 0.5 {
+		t.Fatalf("unexpected lyrics %+v", got)
+	}
+	if _, err := o.GetTrackLyrics(ctx, "t1"); err != nil || provider.calls != 1 {
+		t.Fatalf("expected cached lyrics, got err=%v after %d provider calls", err, provider.calls)
+	}
+	if _, err := o.GetTrackLyrics(ctx, "t2"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for track without lyrics, got %v", err)
+	}
+	if _, err := o.GetTrackLyrics(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown track, got %v", err)
+	}
+
+	if _, err := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil).GetTrackLyrics(ctx, "t1"); err == nil {
+		t.Fatal("expected error without a lyrics provider")
+	}
+}
+
+func TestOrchestrator_ProcessIntent_LyricValence(t *testing.T) {
+	provider := &stubLyrics{lyrics: map[string]domain.Lyrics{
+		"sad":   {Plain: "tears and rain, lonely and cold"},
+		"happy": {Plain: "sunshine and love, dancing all night"},
+	}}
+	var upbeat domain.IntentObject
+	upbeat.Entities.Artists = []string{"A"}
+	upbeat.VibeConstraints.Valence = &domain.VibeConstraint{Min: 0.6, Max: 1}
+
+	tests := []struct {
+		name      string
+		track     domain.Track
+		opts      []Option
+		wantAdded int
+	}{
+		{name: "disabled leaves valence unknown", track: domain.Track{ID: "happy"}, opts: []Option{WithLyrics(provider, newMemLyrics(), newMemLyrics())}, wantAdded: 0},
+		{name: "positive lyrics pass", track: domain.Track{ID: "happy"}, opts: []Option{WithLyrics(provider, newMemLyrics(), newMemLyrics()), WithLyricValence()}, wantAdded: 1},
+		{name: "negative lyrics fail", track: domain.Track{ID: "sad"}, opts: []Option{WithLyrics(provider, newMemLyrics(), newMemLyrics()), WithLyricValence()}, wantAdded: 0},
+		{name: "known valence wins", track: domain.Track{ID: "sad", Features: domain.AudioFeatures{Valence: 0.9}}, opts: []Option{WithLyrics(provider, newMemLyrics(), newMemLyrics()), WithLyricValence()}, wantAdded: 1},
+		{name: "missing lyrics", track: domain.Track{ID: "other"}, opts: []Option{WithLyrics(provider, newMemLyrics(), newMemLyrics()), WithLyricValence()}, wantAdded: 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewOrchestrator(&mockSpotify{track: tc.track}, &mockRepo{}, &mockIntentCompiler{intent: upbeat}, tc.opts...)
+			result, err := o.ProcessIntent(context.Background(), "pl-1", "something upbeat")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TracksAdded != tc.wantAdded {
+				t.Fatalf("expected %d tracks added, got %d", tc.wantAdded, result.TracksAdded)
+			}
+		})
+	}
+}
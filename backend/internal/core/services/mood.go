@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// moodHistory holds mood check-ins and the clock the trend is taken at.
+type moodHistory struct {
+	store ports.MoodStore
+	now   func() time.Time
+}
+
+// WithMood records mood check-ins and fills the valence and energy an
+// intent leaves open from the recent mood trend.
+func WithMood(store ports.MoodStore) Option {
+	return func(o *Orchestrator) {
+		o.moods = &moodHistory{store: store, now: time.Now}
+	}
+}
+
+// HasMood returns true if mood check-ins are available.
+func (o *Orchestrator) HasMood() bool {
+	return o.moods != nil
+}
+
+// RecordMood records a check-in for owner. It returns domain.ErrInvalidMood
+// for reports that cannot be resolved to valence and energy.
+func (o *Orchestrator) RecordMood(ctx context.Context, owner string, report domain.MoodReport) (domain.MoodCheckIn, error) {
+	if !o.HasMood() {
+		return domain.MoodCheckIn{}, fmt.Errorf("service: mood check-ins not configured")
+	}
+	checkIn, err := report.CheckIn(owner, o.moods.now().UTC())
+	if err != nil {
+		return domain.MoodCheckIn{}, fmt.Errorf("service: %w", err)
+	}
+	if err := o.moods.store.RecordMood(ctx, checkIn); err != nil {
+		err = fmt.Errorf("service: failed to record mood: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "record_mood", "owner": owner})
+		return domain.MoodCheckIn{}, err
+	}
+	return checkIn, nil
+}
+
+// ListMoods returns owner's check-ins since the given time, newest first.
+func (o *Orchestrator) ListMoods(ctx context.Context, owner string, since time.Time) ([]domain.MoodCheckIn, error) {
+	if !o.HasMood() {
+		return nil, fmt.Errorf("service: mood check-ins not configured")
+	}
+	checkIns, err := o.moods.store.ListMoods(ctx, owner, since)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list moods: %w", err)
+	}
+	return checkIns, nil
+}
+
+// CurrentMood returns owner's mood trend, and false when there are no
+// check-ins within domain.MoodWindow.
+func (o *Orchestrator) CurrentMood(ctx context.Context, owner string) (domain.MoodTrend, bool, error) {
+	if !o.HasMood() {
+		return domain.MoodTrend{}, false, fmt.Errorf("service: mood check-ins not configured")
+	}
+	now := o.moods.now()
+	checkIns, err := o.moods.store.ListMoods(ctx, owner, now.Add(-domain.MoodWindow))
+	if err != nil {
+		return domain.MoodTrend{}, false, fmt.Errorf("service: failed to list moods: %w", err)
+	}
+	trend, ok := domain.Trend(checkIns, now)
+	return trend, ok, nil
+}
+
+// applyMood fills in intent from the recent mood trend. It reports whether
+// anything was filled in. Store failures are reported and ignored.
+func (o *Orchestrator) applyMood(ctx context.Context, intent *domain.IntentObject) bool {
+	if !o.HasMood() {
+		return false
+	}
+	trend, ok, err := o.CurrentMood(ctx, domain.DefaultOwner)
+	if err != nil {
+		o.report(ctx, err, map[string]string{"operation": "apply_mood"})
+		return false
+	}
+	return ok && trend.Apply(intent)
+}
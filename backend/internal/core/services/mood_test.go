@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// memMoods is an in-memory ports.MoodStore.
+type memMoods struct {
+	checkIns []domain.MoodCheckIn
+	err      error
+}
+
+func (m *memMoods) RecordMood(ctx context.Context, checkIn domain.MoodCheckIn) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.checkIns = append(m.checkIns, checkIn)
+	return nil
+}
+
+func (m *memMoods) ListMoods(ctx context.Context, owner string, since time.Time) ([]domain.MoodCheckIn, error) {
+	var out []domain.MoodCheckIn
+	for i := len(m.checkIns) - 1; i >= 0; i-- {
+		if c := m.checkIns[i]; c.Owner == owner && !c.RecordedAt.Before(since) {
+			out = append(out, c)
+		}
+	}
+	return out, m.err
+}
+
+func TestOrchestrator_ProcessIntent_Mood(t *testing.T) {
+	now := time.Date(2026, 3, 2, 22, 0, 0, 0, time.UTC)
+	low := domain.MoodCheckIn{Owner: domain.DefaultOwner, Valence: 0.2, Energy: 0.2, RecordedAt: now.Add(-time.Hour)}
+
+	tests := []struct {
+		name        string
+		store       *memMoods
+		explicit    bool
+		wantMood    bool
+		wantReports int
+	}{
+		{name: "recent check-in", store: &memMoods{checkIns: []domain.MoodCheckIn{low}}, wantMood: true},
+		{name: "stale check-in", store: &memMoods{checkIns: []domain.MoodCheckIn{{Owner: domain.DefaultOwner, RecordedAt: now.Add(-72 * time.Hour)}}}},
+		{name: "explicit vibe wins", store: &memMoods{checkIns: []domain.MoodCheckIn{low}}, explicit: true},
+		{name: "store down", store: &memMoods{err: errors.New("disk full")}, wantReports: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			intent := domain.IntentObject{}
+			intent.Entities.Artists = []string{"Artist"}
+			if tc.explicit {
+				intent.VibeConstraints.Valence = &domain.VibeConstraint{Min: 0.8, Max: 1}
+				intent.VibeConstraints.Energy = &domain.VibeConstraint{Min: 0.8, Max: 1}
+			}
+			reporter := &fakeReporter{}
+			o := NewOrchestrator(&catalogSpotify{}, &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}, &mockIntentCompiler{intent: intent},
+				WithMood(tc.store), WithErrorReporter(reporter))
+			o.moods.now = func() time.Time { return now }
+
+			result, err := o.ProcessIntent(context.Background(), "pl-1", "something for right now")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Mood != tc.wantMood || len(reporter.errs) != tc.wantReports {
+				t.Fatalf("expected mood=%v with %d reports, got %v with %d", tc.wantMood, tc.wantReports, result.Mood, len(reporter.errs))
+			}
+			if tc.wantMood && result.Intent.VibeConstraints.Valence.Max > 0.5 {
+				t.Fatalf("expected a low valence range, got %+v", result.Intent.VibeConstraints.Valence)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_RecordMood(t *testing.T) {
+	ctx := context.Background()
+	store := &memMoods{}
+	o := NewOrchestrator(nil, &mockRepo{}, nil, WithMood(store))
+
+	if _, err := o.RecordMood(ctx, domain.DefaultOwner, domain.MoodReport{Emoji: "🦖"}); !errors.Is(err, domain.ErrInvalidMood) {
+		t.Fatalf("expected ErrInvalidMood, got %v", err)
+	}
+	checkIn, err := o.RecordMood(ctx, domain.DefaultOwner, domain.MoodReport{Emoji: "😌"})
+	if err != nil || checkIn.RecordedAt.IsZero() || checkIn.Valence != 0.7 {
+		t.Fatalf("unexpected check-in %+v (%v)", checkIn, err)
+	}
+	trend, ok, err := o.CurrentMood(ctx, domain.DefaultOwner)
+	if err != nil || !ok || trend.CheckIns != 1 || trend.Valence != 0.7 {
+		t.Fatalf("unexpected trend %+v, %v (%v)", trend, ok, err)
+	}
+
+	if _, err := NewOrchestrator(nil, &mockRepo{}, nil).RecordMood(ctx, domain.DefaultOwner, domain.MoodReport{Emoji: "😌"}); err == nil {
+		t.Fatal("expected error without mood check-ins configured")
+	}
+}
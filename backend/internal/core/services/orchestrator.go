@@ -5,35 +5,154 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
 	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Orchestrator coordinates spotify and playlist repository operations.
 type Orchestrator struct {
-	spotify ports.SpotifyProvider
-	repo    ports.PlaylistRepository
-	intent  ports.IntentCompiler
+	spotify  ports.SpotifyProvider
+	repo     ports.PlaylistRepository
+	intent   ports.IntentCompiler
+	fallback ports.IntentCompiler
+	// validateIntents rejects invalid intents after up to intentRepairs
+	// attempts to have the compiler correct them.
+	validateIntents bool
+	intentRepairs   int
+	uow             ports.UnitOfWork
+	reporter        ports.ErrorReporter
+	runs            ports.IntentRunStore
+	flags           ports.FeatureFlags
+	events          ports.EventSink
+	logger          *slog.Logger
+
+	experiment *domain.Experiment
+	recordings ports.RecordingIndex
+
+	lyrics       ports.LyricsProvider
+	lyricStore   ports.LyricsStore
+	tracks       ports.TrackLookup
+	lyricValence bool
+
+	podcasts ports.PodcastProvider
+	albums   ports.AlbumProvider
+	importer ports.PlaylistImporter
+
+	recommender ports.RecommendationProvider
+
+	previews     ports.PreviewResolver
+	previewStore ports.PreviewStore
+	features     ports.FeatureProvider
+
+	playback ports.PlaybackStore
+	player   ports.PlaybackController
+	queue    ports.QueueStore
+
+	dayparts        *daypartSchedule
+	weather         ports.WeatherProvider
+	weatherSettings ports.WeatherSettingsStore
+	focus           *focusMode
+	moods           *moodHistory
+
+	cooccurrence ports.CooccurrenceStore
+	similar      ports.SimilarArtistProvider
+	sessions     ports.IntentSessionStore
+	revisions    ports.RevisionStore
+	discovery    *discovery
+	spotifyAuth  *spotifyAuth
+
+	playlistSettings ports.PlaylistSettingsStore
+	library          ports.TrackLibrary
 }
 
+// Option configures optional Orchestrator dependencies.
+type Option func(*Orchestrator)
+
+// WithUnitOfWork makes multi-step flows such as ProcessIntent atomic.
+// Without it, each repository call commits independently.
+func WithUnitOfWork(uow ports.UnitOfWork) Option {
+	return func(o *Orchestrator) {
+		o.uow = uow
+	}
+}
+
+// WithErrorReporter forwards unexpected failures, with playlist and intent
+// context, to an error tracker.
+func WithErrorReporter(reporter ports.ErrorReporter) Option {
+	return func(o *Orchestrator) {
+		o.reporter = reporter
+	}
+}
+
+// WithIntentRuns records the inputs and outcome of every ProcessIntent call
+// so they can be replayed with ReplayIntentRuns.
+func WithIntentRuns(store ports.IntentRunStore) Option {
+	return func(o *Orchestrator) {
+		o.runs = store
+	}
+}
+
+// WithFeatureFlags enables the experimental behaviors flags turns on.
+// Without it, every flag is off.
+func WithFeatureFlags(flags ports.FeatureFlags) Option {
+	return func(o *Orchestrator) {
+		o.flags = flags
+	}
+}
+
+// WithLogger logs through l instead of the default logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *Orchestrator) {
+		o.logger = l
+	}
+}
+
+// Orchestrator is the production ports.PlaylistService.
+var _ ports.PlaylistService = (*Orchestrator)(nil)
+
 // NewOrchestrator constructs an Orchestrator.
-func NewOrchestrator(spotify ports.SpotifyProvider, repo ports.PlaylistRepository, intent ports.IntentCompiler) *Orchestrator {
-	return &Orchestrator{
+func NewOrchestrator(spotify ports.SpotifyProvider, repo ports.PlaylistRepository, intent ports.IntentCompiler, opts ...Option) *Orchestrator {
+	o := &Orchestrator{
 		spotify: spotify,
 		repo:    repo,
 		intent:  intent,
 	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.logger = logging.Component(o.logger, "service")
+	return o
 }
 
-// IntentResult contains the result of processing an intent, including the parsed
-// intent object and a summary of the playlist population.
-type IntentResult struct {
-	Intent          domain.IntentObject
-	TracksEvaluated int
-	TracksAdded     int
-	Summary         string
+// enabled reports whether the named feature flag is on.
+func (o *Orchestrator) enabled(name string) bool {
+	return o.flags != nil && o.flags.Enabled(name)
+}
+
+// report forwards err to the configured error reporter, if any.
+func (o *Orchestrator) report(ctx context.Context, err error, fields map[string]string) {
+	if o.reporter != nil {
+		o.reporter.Report(ctx, err, fields)
+	}
+}
+
+// atomically runs fn inside the configured unit of work, or directly against
+// the repository when none is configured.
+func (o *Orchestrator) atomically(ctx context.Context, fn func(ctx context.Context, repo ports.PlaylistRepository) error) error {
+	if o.uow == nil {
+		return fn(ctx, o.repo)
+	}
+	return o.uow.Do(ctx, fn)
 }
 
 // ProcessIntent analyzes a user message, fetches matching tracks, filters them
@@ -42,69 +161,164 @@ type IntentResult struct {
 // Note: The caller should pass a detached context (e.g., context.WithoutCancel)
 // if this is called from a background goroutine where client disconnection
 // should not cancel the operation.
-func (o *Orchestrator) ProcessIntent(ctx context.Context, playlistID string, message string) (IntentResult, error) {
+func (o *Orchestrator) ProcessIntent(ctx context.Context, playlistID string, message string) (domain.IntentResult, error) {
+	return o.ProcessIntentWithDuration(ctx, playlistID, message, domain.DurationTarget{})
+}
+
+// ProcessIntentWithDuration is ProcessIntent, but when target.DurationMs is
+// set it adds matching tracks only until the playlist's total length is
+// within the tolerance of the target.
+func (o *Orchestrator) ProcessIntentWithDuration(ctx context.Context, playlistID string, message string, target domain.DurationTarget) (domain.IntentResult, error) {
+	return o.ProcessIntentStream(ctx, playlistID, message, domain.IntentOptions{Duration: target}, nil)
+}
+
+// ProcessIntentStream is ProcessIntentWithDuration with further opts, but
+// when the intent compiler implements ports.IntentStreamer its output is
+// passed to onDelta while the intent is analyzed. A nil onDelta disables
+// streaming.
+func (o *Orchestrator) ProcessIntentStream(ctx context.Context, playlistID string, message string, opts domain.IntentOptions, onDelta func(domain.IntentDelta)) (domain.IntentResult, error) {
+	ctx, span := startSpan(ctx, "Orchestrator.ProcessIntent", playlistID)
+	result, err := o.processIntent(ctx, playlistID, message, opts, false, onDelta)
+	span.SetAttributes(
+		attribute.String("overture.intent_type", result.Intent.IntentType),
+		attribute.Int("overture.tracks_added", result.TracksAdded),
+	)
+	endSpan(span, err)
+	if err != nil && o.intent != nil && !errors.Is(err, domain.ErrNotFound) {
+		o.report(ctx, err, map[string]string{
+			"operation":   "process_intent",
+			"playlist_id": playlistID,
+			"intent_type": result.Intent.IntentType,
+			"artists":     strings.Join(result.Intent.Entities.Artists, ","),
+		})
+	}
+	if err == nil {
+		o.publish(ctx, domain.EventIntentProcessed, playlistID, domain.IntentProcessedPayload{
+			IntentType:      result.Intent.IntentType,
+			TracksEvaluated: result.TracksEvaluated,
+			TracksAdded:     result.TracksAdded,
+			Summary:         result.Summary,
+		})
+	}
+	return result, err
+}
+
+// processIntent implements ProcessIntent. On failure after the intent was
+// analyzed, the returned result still carries the intent for error reports.
+// forceFocus applies the focus bias even outside a focus block; onDelta,
+// if set, receives the compiler's streamed output.
+func (o *Orchestrator) processIntent(ctx context.Context, playlistID string, message string, opts domain.IntentOptions, forceFocus bool, onDelta func(domain.IntentDelta)) (domain.IntentResult, error) {
+	target := opts.Duration
 	if o.intent == nil {
-		return IntentResult{}, fmt.Errorf("service: intent compiler not configured")
+		return domain.IntentResult{}, fmt.Errorf("service: intent compiler not configured")
 	}
 
-	// 1. Analyze intent from message
-	intent, err := o.intent.AnalyzeIntent(ctx, message)
+	// 1. Analyze intent from message, following up on the session's
+	// earlier turns if it continues one.
+	history, err := o.sessionHistory(ctx, opts.SessionID, playlistID)
 	if err != nil {
-		return IntentResult{}, fmt.Errorf("service: failed to analyze intent: %w", err)
+		return domain.IntentResult{}, err
 	}
-
-	// 2. Get existing playlist to check for duplicates
-	playlist, err := o.repo.GetByID(ctx, playlistID)
+	// The compiler reads relative requests such as "more danceable"
+	// against the playlist as it is; a missing playlist fails in step 3.
+	if playlist, err := o.repo.GetByID(ctx, playlistID); err == nil {
+		ctx = ports.WithPlaylistSummary(ctx, playlist.Summary())
+	}
+	intent, degraded, err := o.analyzeIntentOrFallback(ctx, history, message, onDelta)
 	if err != nil {
-		return IntentResult{}, fmt.Errorf("service: failed to load playlist: %w", err)
+		return domain.IntentResult{}, fmt.Errorf("service: failed to analyze intent: %w", err)
 	}
-
-	// Build a set of existing track IDs for deduplication
-	existingTracks := make(map[string]bool)
-	for _, t := range playlist.Tracks {
-		existingTracks[t.ID] = true
+	compiled := intent
+	// A length the request passed wins over one its message names.
+	if target.DurationMs == 0 && intent.Duration != nil {
+		target = intent.Duration.Target()
+	}
+	// More specific context goes first: a focus block, how the user has
+	// been feeling, the weather, then the time of day.
+	focus := o.applyFocus(ctx, &intent, forceFocus)
+	mood := o.applyMood(ctx, &intent)
+	weather := o.applyWeather(ctx, &intent)
+	daypart := o.applyDaypart(&intent)
+
+	// The playlist's settings pick the market tracks are looked up in and
+	// rule some of them out.
+	settings, err := o.settingsFor(ctx, playlistID)
+	if err != nil {
+		return domain.IntentResult{Intent: intent}, err
+	}
+	ctx = inMarket(ctx, settings)
+
+	// 2. Fetch top tracks for each artist. Provider calls happen before the
+	// transaction is opened so a slow network never holds a write lock.
+	similar := o.similarArtists(ctx, intent.Entities.Artists, opts.ExpandSimilar)
+	artists := append(append([]string(nil), intent.Entities.Artists...), similar...)
+	allTracks := o.artistTopTracks(ctx, artists)
+	// Tracks others added alongside these are candidates too.
+	allTracks = append(allTracks, o.cooccurringTracks(ctx, playlistID, allTracks)...)
+
+	o.applyLyricValence(ctx, allTracks, intent)
+
+	// Tracks the playlist's settings rule out are never candidates.
+	allTracks = settings.Filter(allTracks)
+	if target.DurationMs == 0 {
+		target.DurationMs = settings.TargetDurationMs
 	}
 
-	// 3. Fetch top tracks for each artist
-	var allTracks []domain.Track
-	seenTracks := make(map[string]bool) // For deduplication across artists
+	arm := o.assign(playlistID)
 
-	for _, artist := range intent.Entities.Artists {
-		tracks, err := o.spotify.GetArtistTopTracks(ctx, artist)
+	// 3-5. Load, filter and apply atomically so a concurrent change can't
+	// slip between the duplicate check and the insert.
+	var matchingTracks []domain.Track
+	var existing []string
+	var durationMs int
+	err = o.atomically(ctx, func(ctx context.Context, repo ports.PlaylistRepository) error {
+		// 3. Get existing playlist to check for duplicates
+		playlist, err := repo.GetByID(ctx, playlistID)
 		if err != nil {
-			// Log but continue with other artists
-			continue
+			return fmt.Errorf("service: failed to load playlist: %w", err)
 		}
 
-		for _, track := range tracks {
-			// Skip if we've already seen this track from another artist
-			if seenTracks[track.ID] {
-				continue
-			}
-			seenTracks[track.ID] = true
-			allTracks = append(allTracks, track)
+		existing = existing[:0]
+		durationMs = 0
+		for _, t := range playlist.Tracks {
+			existing = append(existing, t.ID)
+			durationMs += t.DurationMs
 		}
-	}
 
-	// 4. Filter tracks based on vibe constraints
-	var matchingTracks []domain.Track
-	for _, track := range allTracks {
-		// Skip if already in playlist
-		if existingTracks[track.ID] {
-			continue
+		// 4. Filter tracks based on vibe constraints
+		matchingTracks = selectTracks(allTracks, existing, intent, arm.scoring)
+		// Other releases of songs the playlist has are duplicates too.
+		matchingTracks = domain.NewSongs(matchingTracks, playlist.Tracks)
+		matchingTracks, err = o.dropKnownRecordings(ctx, matchingTracks, existing)
+		if err != nil {
+			return err
 		}
-
-		// Check against vibe constraints
-		if matchesConstraints(track.Features, intent) {
-			matchingTracks = append(matchingTracks, track)
+		if target.DurationMs > 0 {
+			matchingTracks, _ = fillDuration(matchingTracks, durationMs, target)
 		}
-	}
+		// A playlist at its settings' MaxTracks takes only what fits.
+		matchingTracks = settings.Room(matchingTracks, len(existing))
+		for _, t := range matchingTracks {
+			durationMs += t.DurationMs
+		}
+		// Tracks are picked by score, then ordered as the intent asks.
+		matchingTracks = domain.SequenceTracks(matchingTracks, intent.Sequence.Pattern, sequenceSeed(playlistID, message))
 
-	// 5. Add matching tracks to playlist
-	if len(matchingTracks) > 0 {
-		if err := o.repo.AddTracksToPlaylist(ctx, playlistID, matchingTracks); err != nil {
-			return IntentResult{}, fmt.Errorf("service: failed to add tracks to playlist: %w", err)
+		// 5. Add matching tracks to playlist
+		if len(matchingTracks) > 0 {
+			if err := repo.AddTracksToPlaylist(ctx, playlistID, matchingTracks); err != nil {
+				return fmt.Errorf("service: failed to add tracks to playlist: %w", err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return domain.IntentResult{Intent: intent}, err
+	}
+	o.recordRun(ctx, playlistID, message, intent, allTracks, existing, matchingTracks, arm)
+	// A degraded intent would mislead the compiler's follow-ups.
+	if !degraded {
+		o.recordTurn(ctx, opts.SessionID, playlistID, message, compiled)
 	}
 
 	// 6. Build summary
@@ -118,15 +332,90 @@ func (o *Orchestrator) ProcessIntent(ctx context.Context, playlistID string, mes
 
 	summary := fmt.Sprintf("Found %d tracks, added %d matching your '%s' vibe",
 		len(allTracks), len(matchingTracks), artistNames)
+	if target.DurationMs > 0 {
+		summary += fmt.Sprintf(" (%s of %s)", formatMinutes(durationMs), formatMinutes(target.DurationMs))
+	}
 
-	return IntentResult{
-		Intent:          intent,
-		TracksEvaluated: len(allTracks),
-		TracksAdded:     len(matchingTracks),
-		Summary:         summary,
+	return domain.IntentResult{
+		Intent:           intent,
+		TracksEvaluated:  len(allTracks),
+		TracksAdded:      len(matchingTracks),
+		Summary:          summary,
+		TargetDurationMs: target.DurationMs,
+		DurationMs:       durationMs,
+		Daypart:          daypart,
+		Weather:          weather,
+		Focus:            focus,
+		Mood:             mood,
+		SimilarArtists:   similar,
+		Sequence:         domain.SequencePattern(intent.Sequence.Pattern),
+		SessionID:        opts.SessionID,
+		SessionHistory:   len(history),
+		Degraded:         degraded,
 	}, nil
 }
 
+// sequenceSeed seeds SHUFFLE sequences so the same intent on the same
+// playlist shuffles the same way, which keeps replays comparable.
+func sequenceSeed(playlistID, message string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(playlistID + "\x00" + message))
+	return int64(h.Sum64())
+}
+
+// analyzeIntent compiles message, as a follow-up to history if there is
+// any, into an intent, streaming the compiler's output to onDelta when both
+// are available. With WithIntentValidation, invalid intents are sent back
+// to the compiler for repair.
+func (o *Orchestrator) analyzeIntent(ctx context.Context, history []domain.IntentTurn, message string, onDelta func(domain.IntentDelta)) (_ domain.IntentObject, err error) {
+	ctx, span := tracer.Start(ctx, "Orchestrator.analyzeIntent")
+	defer func() { endSpan(span, err) }()
+	intent, err := o.compileIntent(ctx, history, message, onDelta)
+	if err != nil || !o.validateIntents {
+		return intent, err
+	}
+	return o.repairIntent(ctx, history, message, intent, onDelta)
+}
+
+// compileIntent makes one call to the intent compiler for analyzeIntent.
+func (o *Orchestrator) compileIntent(ctx context.Context, history []domain.IntentTurn, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	if len(history) > 0 {
+		if conv, ok := o.intent.(ports.ConversationalCompiler); ok {
+			return conv.AnalyzeIntentTurn(ctx, history, message, onDelta)
+		}
+		message = historyPrompt(history, message)
+	}
+	if streamer, ok := o.intent.(ports.IntentStreamer); ok && onDelta != nil {
+		return streamer.AnalyzeIntentStream(ctx, message, onDelta)
+	}
+	return o.intent.AnalyzeIntent(ctx, message)
+}
+
+// artistTopTracks fetches the top tracks of each artist, deduplicated
+// across artists. Artists whose lookup fails are skipped.
+func (o *Orchestrator) artistTopTracks(ctx context.Context, artists []string) []domain.Track {
+	var allTracks []domain.Track
+	seenTracks := make(map[string]bool) // For deduplication across artists
+
+	for _, artist := range artists {
+		tracks, err := o.spotify.GetArtistTopTracks(ctx, artist)
+		if err != nil {
+			// Log but continue with other artists
+			continue
+		}
+
+		for _, track := range tracks {
+			// Skip if we've already seen this track from another artist
+			if seenTracks[track.ID] {
+				continue
+			}
+			seenTracks[track.ID] = true
+			allTracks = append(allTracks, track)
+		}
+	}
+	return allTracks
+}
+
 // HasIntentCompiler returns true if an intent compiler is configured.
 func (o *Orchestrator) HasIntentCompiler() bool {
 	return o.intent != nil
@@ -134,7 +423,17 @@ func (o *Orchestrator) HasIntentCompiler() bool {
 
 // AddTrackToPlaylist fetches a track from Spotify, adds it to the local playlist, and saves it.
 // It returns the playlist ID on success.
-func (o *Orchestrator) AddTrackToPlaylist(ctx context.Context, playlistID string, title string, artist string) (string, string, string, error) {
+func (o *Orchestrator) AddTrackToPlaylist(ctx context.Context, playlistID string, title string, artist string) (_, _, _ string, err error) {
+	ctx, span := startSpan(ctx, "Orchestrator.AddTrackToPlaylist", playlistID)
+	defer func() { endSpan(span, err) }()
+
+	// The playlist's settings pick the market the track is looked up in.
+	settings, err := o.settingsFor(ctx, playlistID)
+	if err != nil {
+		return "", "", "", err
+	}
+	ctx = inMarket(ctx, settings)
+
 	// 1. Fetch track metadata from Spotify
 	track, err := o.spotify.GetTrack(ctx, title, artist)
 	if err != nil {
@@ -146,6 +445,25 @@ func (o *Orchestrator) AddTrackToPlaylist(ctx context.Context, playlistID string
 	if err != nil {
 		return "", "", "", fmt.Errorf("service: failed to load playlist: %w", err)
 	}
+	if !settings.Allows(track) {
+		return "", "", "", fmt.Errorf("service: domain rule violation: %w", domain.ErrExplicitTrack)
+	}
+	if len(settings.Room([]domain.Track{track}, len(plVal.Tracks))) == 0 {
+		return "", "", "", fmt.Errorf("service: domain rule violation: %w", domain.ErrPlaylistFull)
+	}
+
+	// Same recording under another ID or ISRC, recognized by fingerprint.
+	existing := make([]string, 0, len(plVal.Tracks))
+	for _, t := range plVal.Tracks {
+		existing = append(existing, t.ID)
+	}
+	kept, err := o.dropKnownRecordings(ctx, []domain.Track{track}, existing)
+	if err != nil {
+		return "", "", "", err
+	}
+	if len(kept) == 0 {
+		return "", "", "", fmt.Errorf("service: domain rule violation: %w", domain.ErrDuplicateRecording)
+	}
 
 	// 3. Mutate the playlist (Pure Domain Logic)
 	pl := &plVal
@@ -153,10 +471,17 @@ func (o *Orchestrator) AddTrackToPlaylist(ctx context.Context, playlistID string
 		return "", "", "", fmt.Errorf("service: domain rule violation: %w", err)
 	}
 
-	// 4. Persist the updated playlist
+	// 4. Persist the updated playlist, unless another change got there
+	// first; the caller reloads and retries.
 	if err := o.repo.Save(ctx, *pl); err != nil {
-		return "", "", "", fmt.Errorf("service: failed to save playlist: %w", err)
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return "", "", "", fmt.Errorf("service: failed to save playlist: %w", err)
+		}
+		err = fmt.Errorf("service: failed to save playlist: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "add_track", "playlist_id": playlistID, "track_id": track.ID})
+		return "", "", "", err
 	}
+	o.mirrorToSpotify(ctx, domain.DefaultOwner, *pl, track)
 
 	// 5. Return the playlist ID so clients can fetch details if needed
 	return playlistID, track.ID, track.PreviewURL, nil
@@ -198,6 +523,21 @@ func (o *Orchestrator) GetPlaylist(ctx context.Context, playlistID string) (doma
 	return pl, nil
 }
 
+// ExportUserData assembles the portable export document with every playlist
+// and its tracks.
+func (o *Orchestrator) ExportUserData(ctx context.Context) (domain.Export, error) {
+	playlists, err := o.repo.ListPlaylists(ctx)
+	if err != nil {
+		return domain.Export{}, fmt.Errorf("service: failed to list playlists: %w", err)
+	}
+	return domain.Export{
+		Format:     domain.ExportFormat,
+		Version:    domain.ExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Playlists:  playlists,
+	}, nil
+}
+
 // GetPlaylistAnalysis loads a playlist and returns its analyzed audio features.
 func (o *Orchestrator) GetPlaylistAnalysis(ctx context.Context, id string) (domain.AudioFeatures, error) {
 	features, err := o.repo.GetPlaylistAudioFeatures(ctx, id)
@@ -211,6 +551,79 @@ func (o *Orchestrator) GetPlaylistAnalysis(ctx context.Context, id string) (doma
 	return features, nil
 }
 
+// GetPlaylistAnalysisDetail loads a playlist and returns the distribution of
+// its audio features.
+func (o *Orchestrator) GetPlaylistAnalysisDetail(ctx context.Context, id string) (domain.PlaylistAnalysis, error) {
+	playlist, err := o.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PlaylistAnalysis{}, err
+		}
+		return domain.PlaylistAnalysis{}, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	return playlist.AnalyzeDetail(), nil
+}
+
+// selectTracks returns the candidates, in order, that are not already in the
+// playlist and pass the intent's vibe check and its era and popularity
+// limits. scoring may additionally drop
+// candidates too far from the constraint targets and reorder the selection
+// by that distance, closest first. Of several releases of one song, by
+// Track.SameSong, only the first selected is kept.
+func selectTracks(candidates []domain.Track, existing []string, intent domain.IntentObject, scoring domain.ScoringConfig) []domain.Track {
+	inPlaylist := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		inPlaylist[id] = true
+	}
+
+	var selected []domain.Track
+	for _, track := range candidates {
+		if inPlaylist[track.ID] {
+			continue
+		}
+		if !matchesConstraints(track.Features, intent) || !matchesCatalogConstraints(track, intent) {
+			continue
+		}
+		if scoring.Threshold > 0 && targetDistance(track.Features, intent, scoring.Weights) > scoring.Threshold {
+			continue
+		}
+		selected = append(selected, track)
+	}
+	if scoring.RankByTarget {
+		sort.SliceStable(selected, func(i, j int) bool {
+			return targetDistance(selected[i].Features, intent, scoring.Weights) < targetDistance(selected[j].Features, intent, scoring.Weights)
+		})
+	}
+	return domain.NewSongs(selected, nil)
+}
+
+// targetDistance sums the weighted distance of each feature from its
+// constraint's Target. Constraints without a target do not contribute.
+func targetDistance(features domain.AudioFeatures, intent domain.IntentObject, weights domain.FeatureWeights) float64 {
+	vc := intent.VibeConstraints
+	var d float64
+	for _, pair := range []struct {
+		value      float64
+		constraint *domain.VibeConstraint
+		weight     float64
+	}{
+		{features.Energy, vc.Energy, weights.Energy},
+		{features.Valence, vc.Valence, weights.Valence},
+		{features.Acousticness, vc.Acoustic, weights.Acousticness},
+		{features.Instrumentalness, vc.Instrument, weights.Instrumentalness},
+	} {
+		if pair.constraint == nil || pair.constraint.Target == 0 {
+			continue
+		}
+		w := pair.weight
+		if w == 0 {
+			w = 1
+		}
+		d += w * math.Abs(pair.value-pair.constraint.Target)
+	}
+	return d
+}
+
 // matchesConstraints checks if a track's audio features satisfy the given vibe constraints.
 // Returns true if all non-nil constraints are satisfied (track passes the "vibe check").
 //
@@ -244,6 +657,18 @@ func matchesConstraints(features domain.AudioFeatures, constraints domain.Intent
 	return true
 }
 
+// matchesCatalogConstraints checks the track's release year and popularity
+// against the intent's Era and Popularity, which are skipped when nil.
+func matchesCatalogConstraints(track domain.Track, intent domain.IntentObject) bool {
+	if intent.Era != nil && !intent.Era.Matches(track.ReleaseYear) {
+		return false
+	}
+	if intent.Popularity != nil && !intent.Popularity.Matches(track.Popularity) {
+		return false
+	}
+	return true
+}
+
 // checkConstraint validates a single audio feature value against a constraint.
 // Returns true if the constraint is nil, has zero bounds, or the value is within range.
 func checkConstraint(value float64, constraint *domain.VibeConstraint) bool {
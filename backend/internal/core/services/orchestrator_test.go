@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
@@ -209,6 +210,16 @@ func (m *mockRepo) GetByID(ctx context.Context, id string) (domain.Playlist, err
 	return domain.Playlist{ID: id, Name: "Test Playlist", Tracks: []domain.Track{}}, nil
 }
 
+func (m *mockRepo) ListPlaylists(ctx context.Context) ([]domain.Playlist, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	if m.playlist.ID != "" {
+		return []domain.Playlist{m.playlist}, nil
+	}
+	return []domain.Playlist{}, nil
+}
+
 func (m *mockRepo) Save(ctx context.Context, p domain.Playlist) error {
 	if m.saveErr != nil {
 		return m.saveErr
@@ -238,6 +249,10 @@ func (m *mockRepo) AddTracksToPlaylist(ctx context.Context, playlistID string, t
 	return nil
 }
 
+func (m *mockRepo) ReorderTracks(ctx context.Context, playlistID string, trackIDs []string) error {
+	return m.saveErr
+}
+
 func TestOrchestrator_CreatePlaylist(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -446,16 +461,39 @@ type mockIntentCompiler struct {
 	intent domain.IntentObject
 	err    error
 	called bool
+	// summary is the playlist summary the last call carried.
+	summary *domain.PlaylistSummary
 }
 
 func (m *mockIntentCompiler) AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error) {
 	m.called = true
+	m.summary = ports.PlaylistSummaryFrom(ctx)
 	if m.err != nil {
 		return domain.IntentObject{}, m.err
 	}
 	return m.intent, nil
 }
 
+func TestOrchestrator_ProcessIntent_PlaylistSummary(t *testing.T) {
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Name: "Run", Tracks: []domain.Track{
+		{ID: "a", Features: domain.AudioFeatures{Energy: 0.6, Danceability: 0.4}},
+		{ID: "b", Features: domain.AudioFeatures{Energy: 0.8, Danceability: 0.6}},
+	}}}
+	compiler := &mockIntentCompiler{intent: domain.IntentObject{IntentType: domain.IntentModify}}
+	o := NewOrchestrator(&catalogSpotify{}, repo, compiler)
+
+	if _, err := o.ProcessIntent(context.Background(), "pl-1", "make this more danceable"); err != nil {
+		t.Fatalf("ProcessIntent: %v", err)
+	}
+	got := compiler.summary
+	if got == nil {
+		t.Fatal("expected the compiler to be told about the playlist")
+	}
+	if got.Name != "Run" || got.TrackCount != 2 || math.Abs(got.Features.Energy-0.7) > 1e-9 || math.Abs(got.Features.Danceability-0.5) > 1e-9 {
+		t.Fatalf("unexpected summary %+v", *got)
+	}
+}
+
 func TestOrchestrator_ProcessIntent(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -716,3 +754,264 @@ func TestMatchesConstraints(t *testing.T) {
 		})
 	}
 }
+
+// mockUnitOfWork records whether repository work was routed through it.
+type mockUnitOfWork struct {
+	repo   ports.PlaylistRepository
+	called bool
+	err    error // error returned by fn, as seen by the unit of work
+}
+
+func (m *mockUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repo ports.PlaylistRepository) error) error {
+	m.called = true
+	m.err = fn(ctx, m.repo)
+	return m.err
+}
+
+func TestOrchestrator_ProcessIntent_UnitOfWork(t *testing.T) {
+	tests := []struct {
+		name    string
+		saveErr error
+		wantErr bool
+	}{
+		{name: "applies tracks inside unit of work", saveErr: nil, wantErr: false},
+		{name: "propagates failure so unit of work rolls back", saveErr: errors.New("disk full"), wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepo{saveErr: tc.saveErr}
+			uow := &mockUnitOfWork{repo: repo}
+			spotify := &mockSpotify{track: domain.Track{ID: "t1", Title: "Song", Artist: "Artist"}}
+			compiler := &mockIntentCompiler{}
+			compiler.intent.Entities.Artists = []string{"Artist"}
+
+			o := NewOrchestrator(spotify, &mockRepo{getErr: errors.New("repo used outside unit of work")}, compiler, WithUnitOfWork(uow))
+			_, err := o.ProcessIntent(context.Background(), "test-playlist-id", "more like this")
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ProcessIntent() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !uow.called {
+				t.Fatal("expected ProcessIntent to use the unit of work")
+			}
+			if (uow.err != nil) != tc.wantErr {
+				t.Fatalf("unit of work saw error %v, wantErr %v", uow.err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_ExportUserData(t *testing.T) {
+	tests := []struct {
+		name          string
+		repo          *mockRepo
+		wantErr       bool
+		wantPlaylists int
+	}{
+		{
+			name:          "exports playlists",
+			repo:          &mockRepo{playlist: domain.Playlist{ID: "pl-1", Name: "Mine"}},
+			wantPlaylists: 1,
+		},
+		{
+			name:          "empty library",
+			repo:          &mockRepo{},
+			wantPlaylists: 0,
+		},
+		{
+			name:    "repository error",
+			repo:    &mockRepo{getErr: errors.New("db down")},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewOrchestrator(&mockSpotify{}, tc.repo, nil)
+			doc, err := o.ExportUserData(context.Background())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ExportUserData() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if doc.Format != domain.ExportFormat || doc.Version != domain.ExportVersion {
+				t.Fatalf("unexpected format %q v%d", doc.Format, doc.Version)
+			}
+			if len(doc.Playlists) != tc.wantPlaylists {
+				t.Fatalf("expected %d playlists, got %d", tc.wantPlaylists, len(doc.Playlists))
+			}
+		})
+	}
+}
+
+type fakeReporter struct {
+	errs   []error
+	fields []map[string]string
+}
+
+func (f *fakeReporter) Report(ctx context.Context, err error, fields map[string]string) {
+	f.errs = append(f.errs, err)
+	f.fields = append(f.fields, fields)
+}
+
+func TestOrchestrator_ErrorReporting(t *testing.T) {
+	tests := []struct {
+		name        string
+		compilerErr error
+		repo        *mockRepo
+		wantReports int
+	}{
+		{name: "success is not reported", repo: &mockRepo{}, wantReports: 0},
+		{name: "compiler failure is reported", compilerErr: errors.New("ollama down"), repo: &mockRepo{}, wantReports: 1},
+		{name: "save failure is reported", repo: &mockRepo{saveErr: errors.New("disk full")}, wantReports: 1},
+		{name: "missing playlist is not reported", repo: &mockRepo{getErr: domain.ErrNotFound}, wantReports: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := &fakeReporter{}
+			spotify := &mockSpotify{track: domain.Track{ID: "t1", Title: "Song", Artist: "Artist"}}
+			compiler := &mockIntentCompiler{err: tc.compilerErr}
+			compiler.intent.Entities.Artists = []string{"Artist"}
+
+			o := NewOrchestrator(spotify, tc.repo, compiler, WithErrorReporter(reporter))
+			_, _ = o.ProcessIntent(context.Background(), "pl-1", "more like this")
+
+			if len(reporter.errs) != tc.wantReports {
+				t.Fatalf("expected %d reports, got %d (%v)", tc.wantReports, len(reporter.errs), reporter.errs)
+			}
+			if tc.wantReports > 0 && reporter.fields[0]["playlist_id"] != "pl-1" {
+				t.Fatalf("expected playlist_id field, got %v", reporter.fields[0])
+			}
+		})
+	}
+}
+
+func TestSelectTracks_EraAndPopularity(t *testing.T) {
+	candidates := []domain.Track{
+		{ID: "hit-95", ReleaseYear: 1995, Popularity: 85},
+		{ID: "cut-97", ReleaseYear: 1997, Popularity: 12},
+		{ID: "cut-04", ReleaseYear: 2004, Popularity: 20},
+		{ID: "unknown", Popularity: 5},
+	}
+
+	tests := []struct {
+		name       string
+		era        *domain.EraConstraint
+		popularity *domain.PopularityConstraint
+		want       []string
+	}{
+		{name: "No limits", want: []string{"hit-95", "cut-97", "cut-04", "unknown"}},
+		{name: "90s only", era: &domain.EraConstraint{Decade: "90s"}, want: []string{"hit-95", "cut-97"}},
+		{name: "Deep cuts only", popularity: &domain.PopularityConstraint{Max: 30}, want: []string{"cut-97", "cut-04", "unknown"}},
+		{name: "90s deep cuts", era: &domain.EraConstraint{MinYear: 1990, MaxYear: 1999}, popularity: &domain.PopularityConstraint{Max: 30}, want: []string{"cut-97"}},
+		{name: "Since 2000", era: &domain.EraConstraint{MinYear: 2000}, want: []string{"cut-04"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			intent := domain.IntentObject{Era: tc.era, Popularity: tc.popularity}
+			got := selectTracks(candidates, nil, intent, domain.ScoringConfig{})
+			ids := make([]string, 0, len(got))
+			for _, tr := range got {
+				ids = append(ids, tr.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("expected %v, got %v", tc.want, ids)
+			}
+		})
+	}
+}
+
+type staticFlags map[string]bool
+
+func (f staticFlags) Enabled(name string) bool { return f[name] }
+
+func TestSelectTracks_TargetScoring(t *testing.T) {
+	var intent domain.IntentObject
+	intent.VibeConstraints.Energy = &domain.VibeConstraint{Target: 0.8, Min: 0.5, Max: 1}
+	candidates := []domain.Track{
+		{ID: "far", Features: domain.AudioFeatures{Energy: 0.5}},
+		{ID: "low", Features: domain.AudioFeatures{Energy: 0.2}},
+		{ID: "near", Features: domain.AudioFeatures{Energy: 0.75}},
+		{ID: "exact", Features: domain.AudioFeatures{Energy: 0.8}},
+	}
+
+	tests := []struct {
+		name  string
+		flags ports.FeatureFlags
+		want  []string
+	}{
+		{name: "Flag unset keeps provider order", flags: nil, want: []string{"far", "near", "exact"}},
+		{name: "Flag off keeps provider order", flags: staticFlags{}, want: []string{"far", "near", "exact"}},
+		{name: "Flag on orders by target distance", flags: staticFlags{domain.FlagTargetScoring: true}, want: []string{"exact", "near", "far"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, WithFeatureFlags(tc.flags))
+			got := selectTracks(candidates, nil, intent, o.baselineScoring())
+			ids := make([]string, 0, len(got))
+			for _, tr := range got {
+				ids = append(ids, tr.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("expected %v, got %v", tc.want, ids)
+			}
+		})
+	}
+}
+
+func TestSelectTracks_OneReleasePerSong(t *testing.T) {
+	var intent domain.IntentObject
+	intent.VibeConstraints.Energy = &domain.VibeConstraint{Target: 0.8, Min: 0.5, Max: 1}
+	candidates := []domain.Track{
+		{ID: "quiet-remaster", Title: "Song (Remastered 2011)", Artist: "Artist A", Features: domain.AudioFeatures{Energy: 0.3}},
+		{ID: "original", Title: "Song", Artist: "Artist A", Features: domain.AudioFeatures{Energy: 0.6}},
+		{ID: "radio-edit", Title: "Song - Radio Edit", Artist: "Artist A", Features: domain.AudioFeatures{Energy: 0.8}},
+		{ID: "other", Title: "Other", Artist: "Artist A", Features: domain.AudioFeatures{Energy: 0.7}},
+	}
+
+	tests := []struct {
+		name    string
+		scoring domain.ScoringConfig
+		want    []string
+	}{
+		// A release the vibe check drops does not keep the others out.
+		{name: "Provider order keeps the first passing release", want: []string{"original", "other"}},
+		{name: "Target scoring keeps the closest release", scoring: domain.ScoringConfig{RankByTarget: true}, want: []string{"radio-edit", "other"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := selectTracks(candidates, nil, intent, tc.scoring)
+			ids := make([]string, 0, len(got))
+			for _, tr := range got {
+				ids = append(ids, tr.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("expected %v, got %v", tc.want, ids)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_ProcessIntent_SkipsOtherReleases(t *testing.T) {
+	intent := domain.IntentObject{}
+	intent.Entities.Artists = []string{"Artist A"}
+	catalog := []domain.Track{
+		{ID: "remaster", Title: "Song (Remastered 2011)", Artist: "Artist A"},
+		{ID: "other", Title: "Other", Artist: "Artist A"},
+	}
+	repo := &recordingRepo{mockRepo: mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "original", Title: "Song", Artist: "Artist A"}}}}}
+	o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, &mockIntentCompiler{intent: intent})
+
+	if _, err := o.ProcessIntent(context.Background(), "pl-1", "anything by Artist A"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.added) != 1 || repo.added[0].ID != "other" {
+		t.Fatalf("expected only the other song to be added, got %+v", repo.added)
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithPlayback shares each playlist's playback state through store.
+func WithPlayback(store ports.PlaybackStore) Option {
+	return func(o *Orchestrator) {
+		o.playback = store
+	}
+}
+
+// HasPlayback returns true if playback state is stored.
+func (o *Orchestrator) HasPlayback() bool {
+	return o.playback != nil
+}
+
+// GetPlayback returns what is playing from a playlist. It returns
+// domain.ErrNotFound when nothing has played from it yet.
+func (o *Orchestrator) GetPlayback(ctx context.Context, playlistID string) (domain.PlaybackState, error) {
+	if o.playback == nil {
+		return domain.PlaybackState{}, fmt.Errorf("service: playback not configured")
+	}
+	state, err := o.playback.GetPlayback(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PlaybackState{}, err
+		}
+		return domain.PlaybackState{}, fmt.Errorf("service: failed to load playback: %w", err)
+	}
+	return state, nil
+}
+
+// UpdatePlayback replaces a playlist's playback state, which is broadcast as
+// an EventPlaybackUpdated event. It returns domain.ErrNotFound for unknown
+// playlists and domain.ErrNotInPlaylist when the track is not part of it.
+func (o *Orchestrator) UpdatePlayback(ctx context.Context, state domain.PlaybackState) (domain.PlaybackState, error) {
+	if o.playback == nil {
+		return domain.PlaybackState{}, fmt.Errorf("service: playback not configured")
+	}
+	if state.TrackID == "" {
+		return domain.PlaybackState{}, fmt.Errorf("service: playback track id cannot be empty")
+	}
+	if state.PositionMs < 0 {
+		return domain.PlaybackState{}, fmt.Errorf("service: playback position cannot be negative")
+	}
+
+	playlist, err := o.repo.GetByID(ctx, state.PlaylistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PlaybackState{}, err
+		}
+		return domain.PlaybackState{}, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	if trackIndex(playlist.Tracks, state.TrackID) < 0 {
+		return domain.PlaybackState{}, fmt.Errorf("service: track %s: %w", state.TrackID, domain.ErrNotInPlaylist)
+	}
+
+	state.UpdatedAt = time.Now().UTC()
+	if err := o.playback.SavePlayback(ctx, state); err != nil {
+		err = fmt.Errorf("service: failed to save playback: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "update_playback", "playlist_id": state.PlaylistID})
+		return domain.PlaybackState{}, err
+	}
+	return state, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// memPlayback is an in-memory ports.PlaybackStore.
+type memPlayback map[string]domain.PlaybackState
+
+func (m memPlayback) SavePlayback(ctx context.Context, state domain.PlaybackState) error {
+	m[state.PlaylistID] = state
+	return nil
+}
+
+func (m memPlayback) GetPlayback(ctx context.Context, playlistID string) (domain.PlaybackState, error) {
+	state, ok := m[playlistID]
+	if !ok {
+		return domain.PlaybackState{}, domain.ErrNotFound
+	}
+	return state, nil
+}
+
+func TestUpdatePlayback(t *testing.T) {
+	playlist := domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "t1"}, {ID: "t2"}}}
+
+	tests := []struct {
+		name    string
+		repo    *mockRepo
+		state   domain.PlaybackState
+		wantErr error
+		wantAny bool
+	}{
+		{name: "stores state", repo: &mockRepo{playlist: playlist}, state: domain.PlaybackState{PlaylistID: "pl-1", TrackID: "t2", PositionMs: 1500, Playing: true, Device: "web"}},
+		{name: "unknown playlist", repo: &mockRepo{getErr: domain.ErrNotFound}, state: domain.PlaybackState{PlaylistID: "nope", TrackID: "t1"}, wantErr: domain.ErrNotFound},
+		{name: "track not in playlist", repo: &mockRepo{playlist: playlist}, state: domain.PlaybackState{PlaylistID: "pl-1", TrackID: "t9"}, wantErr: domain.ErrNotInPlaylist},
+		{name: "missing track", repo: &mockRepo{playlist: playlist}, state: domain.PlaybackState{PlaylistID: "pl-1"}, wantAny: true},
+		{name: "negative position", repo: &mockRepo{playlist: playlist}, state: domain.PlaybackState{PlaylistID: "pl-1", TrackID: "t1", PositionMs: -1}, wantAny: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := memPlayback{}
+			svc := NewOrchestrator(nil, tc.repo, nil, WithPlayback(store))
+
+			got, err := svc.UpdatePlayback(context.Background(), tc.state)
+			if tc.wantErr != nil || tc.wantAny {
+				if err == nil || (tc.wantErr != nil && !errors.Is(err, tc.wantErr)) {
+					t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+				}
+				if len(store) != 0 {
+					t.Fatalf("expected nothing stored, got %+v", store)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.UpdatedAt.IsZero() || got.TrackID != tc.state.TrackID || got.PositionMs != tc.state.PositionMs {
+				t.Fatalf("unexpected state %+v", got)
+			}
+			read, err := svc.GetPlayback(context.Background(), "pl-1")
+			if err != nil || read != got {
+				t.Fatalf("expected %+v to be readable, got %+v (%v)", got, read, err)
+			}
+		})
+	}
+
+	svc := NewOrchestrator(nil, &mockRepo{}, nil, WithPlayback(memPlayback{}))
+	if _, err := svc.GetPlayback(context.Background(), "pl-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before playing, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// playerDevice is the Device recorded in the playback state when playback
+// is started through the controller.
+const playerDevice = "spotify_connect"
+
+// WithPlaybackController enables starting and steering playback on the
+// user's active device.
+func WithPlaybackController(player ports.PlaybackController) Option {
+	return func(o *Orchestrator) {
+		o.player = player
+	}
+}
+
+// HasPlaybackControl returns true if a playback controller is configured.
+func (o *Orchestrator) HasPlaybackControl() bool {
+	return o.player != nil
+}
+
+// PlayPlaylist plays a playlist on the user's active device, from
+// startTrackID or from the top when it is empty, and records the new
+// playback state. It returns domain.ErrNotFound for unknown or empty
+// playlists, domain.ErrNotInPlaylist for a start track the playlist does
+// not contain and domain.ErrNoActiveDevice when there is nothing to play on.
+func (o *Orchestrator) PlayPlaylist(ctx context.Context, playlistID, startTrackID string) (domain.PlaybackState, error) {
+	if o.player == nil {
+		return domain.PlaybackState{}, fmt.Errorf("service: playback controller not configured")
+	}
+	playlist, err := o.repo.GetByID(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PlaybackState{}, err
+		}
+		return domain.PlaybackState{}, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	if len(playlist.Tracks) == 0 {
+		return domain.PlaybackState{}, fmt.Errorf("service: playlist %s has nothing to play: %w", playlistID, domain.ErrNotFound)
+	}
+	offset := 0
+	if startTrackID != "" {
+		offset = trackIndex(playlist.Tracks, startTrackID)
+		if offset < 0 {
+			return domain.PlaybackState{}, fmt.Errorf("service: track %s: %w", startTrackID, domain.ErrNotInPlaylist)
+		}
+	}
+
+	if err := o.player.Play(ctx, playlist.Tracks, offset); err != nil {
+		return domain.PlaybackState{}, o.playerError(ctx, "play", err)
+	}
+
+	state := domain.PlaybackState{
+		PlaylistID: playlistID,
+		TrackID:    playlist.Tracks[offset].ID,
+		Playing:    true,
+		Device:     playerDevice,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	if o.playback != nil {
+		if err := o.playback.SavePlayback(ctx, state); err != nil {
+			// Playback has started; only the shared state is stale.
+			o.report(ctx, fmt.Errorf("service: failed to save playback: %w", err), map[string]string{"operation": "play_playlist", "playlist_id": playlistID})
+		}
+	}
+	return state, nil
+}
+
+// PausePlayback pauses the user's active device.
+func (o *Orchestrator) PausePlayback(ctx context.Context) error {
+	if o.player == nil {
+		return fmt.Errorf("service: playback controller not configured")
+	}
+	return o.playerError(ctx, "pause", o.player.Pause(ctx))
+}
+
+// ResumePlayback resumes the user's active device.
+func (o *Orchestrator) ResumePlayback(ctx context.Context) error {
+	if o.player == nil {
+		return fmt.Errorf("service: playback controller not configured")
+	}
+	return o.playerError(ctx, "resume", o.player.Resume(ctx))
+}
+
+// SkipTrack advances the user's active device to the next item.
+func (o *Orchestrator) SkipTrack(ctx context.Context) error {
+	if o.player == nil {
+		return fmt.Errorf("service: playback controller not configured")
+	}
+	return o.playerError(ctx, "skip", o.player.Skip(ctx))
+}
+
+// QueueTrack queues a track of a playlist to play next on the user's active
+// device. It returns domain.ErrNotFound for unknown playlists and
+// domain.ErrNotInPlaylist for tracks the playlist does not contain.
+func (o *Orchestrator) QueueTrack(ctx context.Context, playlistID, trackID string) error {
+	if o.player == nil {
+		return fmt.Errorf("service: playback controller not configured")
+	}
+	playlist, err := o.repo.GetByID(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	i := trackIndex(playlist.Tracks, trackID)
+	if i < 0 {
+		return fmt.Errorf("service: track %s: %w", trackID, domain.ErrNotInPlaylist)
+	}
+	return o.playerError(ctx, "queue", o.player.Queue(ctx, playlist.Tracks[i]))
+}
+
+// playerError wraps a controller failure, reporting it unless the user
+// simply has no active device.
+func (o *Orchestrator) playerError(ctx context.Context, operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("service: failed to %s: %w", operation, err)
+	if !errors.Is(err, domain.ErrNoActiveDevice) {
+		o.report(ctx, err, map[string]string{"operation": "player_" + operation})
+	}
+	return err
+}
+
+// trackIndex returns the position of trackID in tracks, or -1.
+func trackIndex(tracks []domain.Track, trackID string) int {
+	for i, t := range tracks {
+		if t.ID == trackID {
+			return i
+		}
+	}
+	return -1
+}
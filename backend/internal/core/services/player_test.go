@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// stubPlayer records playback commands.
+type stubPlayer struct {
+	err     error
+	played  []domain.Track
+	offset  int
+	queued  []string
+	actions []string
+}
+
+func (s *stubPlayer) Play(ctx context.Context, tracks []domain.Track, offset int) error {
+	s.actions = append(s.actions, "play")
+	s.played, s.offset = tracks, offset
+	return s.err
+}
+
+func (s *stubPlayer) Pause(ctx context.Context) error {
+	s.actions = append(s.actions, "pause")
+	return s.err
+}
+
+func (s *stubPlayer) Resume(ctx context.Context) error {
+	s.actions = append(s.actions, "resume")
+	return s.err
+}
+
+func (s *stubPlayer) Skip(ctx context.Context) error {
+	s.actions = append(s.actions, "skip")
+	return s.err
+}
+
+func (s *stubPlayer) Queue(ctx context.Context, track domain.Track) error {
+	s.actions = append(s.actions, "queue")
+	s.queued = append(s.queued, track.ID)
+	return s.err
+}
+
+func TestPlayPlaylist(t *testing.T) {
+	playlist := domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "t1"}, {ID: "t2"}, {ID: "t3"}}}
+
+	tests := []struct {
+		name       string
+		repo       *mockRepo
+		start      string
+		playerErr  error
+		wantErr    error
+		wantOffset int
+		wantReport bool
+	}{
+		{name: "from the top", repo: &mockRepo{playlist: playlist}},
+		{name: "from a track", repo: &mockRepo{playlist: playlist}, start: "t2", wantOffset: 1},
+		{name: "start track not in playlist", repo: &mockRepo{playlist: playlist}, start: "t9", wantErr: domain.ErrNotInPlaylist},
+		{name: "unknown playlist", repo: &mockRepo{getErr: domain.ErrNotFound}, wantErr: domain.ErrNotFound},
+		{name: "empty playlist", repo: &mockRepo{}, wantErr: domain.ErrNotFound},
+		{name: "no active device is not reported", repo: &mockRepo{playlist: playlist}, playerErr: domain.ErrNoActiveDevice, wantErr: domain.ErrNoActiveDevice},
+		{name: "provider failure is reported", repo: &mockRepo{playlist: playlist}, playerErr: errors.New("premium required"), wantReport: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			player := &stubPlayer{err: tc.playerErr}
+			store := memPlayback{}
+			reporter := &fakeReporter{}
+			svc := NewOrchestrator(nil, tc.repo, nil, WithPlaybackController(player), WithPlayback(store), WithErrorReporter(reporter))
+
+			state, err := svc.PlayPlaylist(context.Background(), "pl-1", tc.start)
+			if (len(reporter.errs) > 0) != tc.wantReport {
+				t.Errorf("expected report=%v, got %v", tc.wantReport, reporter.errs)
+			}
+			if tc.wantErr != nil || tc.wantReport {
+				if err == nil || (tc.wantErr != nil && !errors.Is(err, tc.wantErr)) {
+					t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+				}
+				if len(store) != 0 {
+					t.Fatalf("expected no playback state, got %+v", store)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(player.played) != 3 || player.offset != tc.wantOffset {
+				t.Fatalf("expected all tracks from %d, got %d from %d", tc.wantOffset, len(player.played), player.offset)
+			}
+			want := playlist.Tracks[tc.wantOffset].ID
+			if state.TrackID != want || !state.Playing || store["pl-1"] != state {
+				t.Fatalf("expected stored state playing %s, got %+v (stored %+v)", want, state, store["pl-1"])
+			}
+		})
+	}
+}
+
+func TestPlayerControls(t *testing.T) {
+	playlist := domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "t1"}, {ID: "t2"}}}
+	player := &stubPlayer{}
+	svc := NewOrchestrator(nil, &mockRepo{playlist: playlist}, nil, WithPlaybackController(player))
+	ctx := context.Background()
+
+	if err := svc.PausePlayback(ctx); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if err := svc.ResumePlayback(ctx); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if err := svc.SkipTrack(ctx); err != nil {
+		t.Fatalf("skip: %v", err)
+	}
+	if err := svc.QueueTrack(ctx, "pl-1", "t2"); err != nil {
+		t.Fatalf("queue: %v", err)
+	}
+	if err := svc.QueueTrack(ctx, "pl-1", "t9"); !errors.Is(err, domain.ErrNotInPlaylist) {
+		t.Fatalf("expected ErrNotInPlaylist, got %v", err)
+	}
+	if got := len(player.actions); got != 4 || player.queued[0] != "t2" {
+		t.Fatalf("unexpected commands %v (queued %v)", player.actions, player.queued)
+	}
+
+	player.err = domain.ErrNoActiveDevice
+	if err := svc.SkipTrack(ctx); !errors.Is(err, domain.ErrNoActiveDevice) {
+		t.Fatalf("expected ErrNoActiveDevice, got %v", err)
+	}
+
+	if err := NewOrchestrator(nil, &mockRepo{}, nil).PausePlayback(ctx); err == nil {
+		t.Fatal("expected an error without a controller")
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// WithPlaylistImport enables creating playlists from Spotify playlists.
+func WithPlaylistImport(importer ports.PlaylistImporter) Option {
+	return func(o *Orchestrator) {
+		o.importer = importer
+	}
+}
+
+// HasPlaylistImport returns true if a playlist importer is configured.
+func (o *Orchestrator) HasPlaylistImport() bool {
+	return o.importer != nil
+}
+
+// ImportPlaylist creates a playlist with the tracks of the Spotify
+// playlist ref names, by URL, URI or ID, in their order, keeping the first
+// release of each song by Track.SameSong. name defaults to the Spotify
+// playlist's. Tracks arrive without audio features; the caller queues their
+// analysis. It returns domain.ErrInvalidPlaylistRef for bad
+// references and domain.ErrNotFound for playlists Spotify does not serve.
+func (o *Orchestrator) ImportPlaylist(ctx context.Context, ref, name string) (domain.PlaylistImport, error) {
+	if !o.HasPlaylistImport() {
+		return domain.PlaylistImport{}, fmt.Errorf("service: playlist import not configured")
+	}
+	sourceID, err := domain.ParseSpotifyPlaylistID(ref)
+	if err != nil {
+		return domain.PlaylistImport{}, err
+	}
+	source, err := o.importer.GetSpotifyPlaylist(ctx, sourceID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PlaylistImport{}, err
+		}
+		return domain.PlaylistImport{}, fmt.Errorf("service: failed to fetch playlist: %w", err)
+	}
+
+	tracks := domain.NewSongs(source.Tracks, nil)
+
+	if name == "" {
+		name = source.Name
+	}
+	if name == "" {
+		name = "Imported playlist"
+	}
+	playlist := domain.Playlist{ID: uuid.New().String(), Name: name, Tracks: tracks}
+	if err := o.repo.Save(ctx, playlist); err != nil {
+		err = fmt.Errorf("service: failed to save imported playlist: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "import_playlist", "source_id": sourceID})
+		return domain.PlaylistImport{}, err
+	}
+	return domain.PlaylistImport{
+		Playlist:       playlist,
+		SourceID:       sourceID,
+		TracksImported: len(tracks),
+		TracksSkipped:  len(source.Tracks) - len(tracks),
+	}, nil
+}
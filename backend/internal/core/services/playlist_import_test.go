@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type stubImporter struct {
+	playlist domain.Playlist
+	err      error
+}
+
+func (s stubImporter) GetSpotifyPlaylist(ctx context.Context, playlistID string) (domain.Playlist, error) {
+	return s.playlist, s.err
+}
+
+func TestOrchestrator_ImportPlaylist(t *testing.T) {
+	source := domain.Playlist{ID: "37i9dQZF1DXcBWIGoYBM5M", Name: "Top Hits", Tracks: []domain.Track{
+		{ID: "t1", ISRC: "usaaa0000001"}, {ID: "t2"}, {ID: "t1"}, {ID: "t3", ISRC: "USAAA0000001"}, {ID: "t4"},
+		{ID: "t5", Title: "Song", Artist: "Artist"}, {ID: "t6", Title: "Song (Remastered 2011)", Artist: "Artist"},
+	}}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		ref         string
+		importName  string
+		wantName    string
+		wantIDs     []string
+		wantSkipped int
+		wantErr     error
+	}{
+		{name: "not configured", ref: source.ID},
+		{name: "invalid ref", opts: []Option{WithPlaylistImport(stubImporter{playlist: source})}, ref: "https://example.com/x", wantErr: domain.ErrInvalidPlaylistRef},
+		{name: "unknown playlist", opts: []Option{WithPlaylistImport(stubImporter{err: domain.ErrNotFound})}, ref: source.ID, wantErr: domain.ErrNotFound},
+		{name: "dedups by ID, ISRC and song", opts: []Option{WithPlaylistImport(stubImporter{playlist: source})}, ref: source.ID, wantName: "Top Hits", wantIDs: []string{"t1", "t2", "t4", "t5"}, wantSkipped: 3},
+		{name: "renames", opts: []Option{WithPlaylistImport(stubImporter{playlist: source})}, ref: "spotify:playlist:" + source.ID, importName: "Mine", wantName: "Mine", wantIDs: []string{"t1", "t2", "t4", "t5"}, wantSkipped: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepo{}
+			svc := NewOrchestrator(&mockSpotify{}, repo, nil, tt.opts...)
+
+			result, err := svc.ImportPlaylist(context.Background(), tt.ref, tt.importName)
+			if tt.wantIDs == nil {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if repo.saved != nil {
+					t.Fatal("expected nothing saved")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repo.saved == nil || repo.saved.ID == source.ID || repo.saved.Name != tt.wantName {
+				t.Fatalf("unexpected saved playlist %+v", repo.saved)
+			}
+			var ids []string
+			for _, tr := range repo.saved.Tracks {
+				ids = append(ids, tr.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Fatalf("expected tracks %v, got %v", tt.wantIDs, ids)
+			}
+			if result.SourceID != source.ID || result.TracksImported != len(tt.wantIDs) || result.TracksSkipped != tt.wantSkipped {
+				t.Fatalf("unexpected result %+v", result)
+			}
+		})
+	}
+}
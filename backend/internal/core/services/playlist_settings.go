@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithPlaylistSettings keeps each playlist's settings in store and enforces
+// them when tracks are added. Without it, every playlist has the default
+// settings.
+func WithPlaylistSettings(store ports.PlaylistSettingsStore) Option {
+	return func(o *Orchestrator) {
+		o.playlistSettings = store
+	}
+}
+
+// HasPlaylistSettings returns true if playlist settings can be changed.
+func (o *Orchestrator) HasPlaylistSettings() bool {
+	return o.playlistSettings != nil
+}
+
+// GetPlaylistSettings returns the playlist's settings, the defaults until
+// they are saved. It returns domain.ErrNotFound for unknown playlists.
+func (o *Orchestrator) GetPlaylistSettings(ctx context.Context, playlistID string) (domain.PlaylistSettings, error) {
+	if _, err := o.repo.GetByID(ctx, playlistID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PlaylistSettings{}, err
+		}
+		return domain.PlaylistSettings{}, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	return o.settingsFor(ctx, playlistID)
+}
+
+// UpdatePlaylistSettings replaces the settings of settings.PlaylistID. It
+// returns domain.ErrNotFound for unknown playlists and
+// domain.ErrInvalidSettings for settings out of range.
+func (o *Orchestrator) UpdatePlaylistSettings(ctx context.Context, settings domain.PlaylistSettings) (domain.PlaylistSettings, error) {
+	if !o.HasPlaylistSettings() {
+		return domain.PlaylistSettings{}, fmt.Errorf("service: playlist settings not configured")
+	}
+	if _, err := o.repo.GetByID(ctx, settings.PlaylistID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PlaylistSettings{}, err
+		}
+		return domain.PlaylistSettings{}, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	if err := settings.Validate(); err != nil {
+		return domain.PlaylistSettings{}, err
+	}
+	settings.UpdatedAt = time.Now().UTC()
+	if err := o.playlistSettings.SavePlaylistSettings(ctx, settings); err != nil {
+		err = fmt.Errorf("service: failed to save playlist settings: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "update_playlist_settings", "playlist_id": settings.PlaylistID})
+		return domain.PlaylistSettings{}, err
+	}
+	return settings, nil
+}
+
+// settingsFor returns the playlist's saved settings or the defaults. A
+// failure to load them fails the caller rather than letting, say, explicit
+// tracks into a family-friendly playlist.
+func (o *Orchestrator) settingsFor(ctx context.Context, playlistID string) (domain.PlaylistSettings, error) {
+	if !o.HasPlaylistSettings() {
+		return domain.DefaultPlaylistSettings(playlistID), nil
+	}
+	settings, err := o.playlistSettings.GetPlaylistSettings(ctx, playlistID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultPlaylistSettings(playlistID), nil
+	}
+	if err != nil {
+		return domain.PlaylistSettings{}, fmt.Errorf("service: failed to load playlist settings: %w", err)
+	}
+	return settings, nil
+}
+
+// inMarket returns ctx asking catalogs for the playlist's DefaultMarket,
+// unless the request already names a market or the settings name none.
+func inMarket(ctx context.Context, settings domain.PlaylistSettings) context.Context {
+	if settings.DefaultMarket == "" || ports.MarketFrom(ctx) != "" {
+		return ctx
+	}
+	return ports.WithMarket(ctx, settings.DefaultMarket)
+}
+
+// inPlaylistMarket is inMarket for flows that otherwise leave the
+// playlist's settings alone. Settings that fail to load leave the
+// configured market in place.
+func (o *Orchestrator) inPlaylistMarket(ctx context.Context, playlistID string) context.Context {
+	settings, err := o.settingsFor(ctx, playlistID)
+	if err != nil {
+		return ctx
+	}
+	return inMarket(ctx, settings)
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// fakePlaylistSettings keeps settings in memory.
+type fakePlaylistSettings map[string]domain.PlaylistSettings
+
+func (f fakePlaylistSettings) GetPlaylistSettings(ctx context.Context, playlistID string) (domain.PlaylistSettings, error) {
+	s, ok := f[playlistID]
+	if !ok {
+		return domain.PlaylistSettings{}, domain.ErrNotFound
+	}
+	return s, nil
+}
+
+func (f fakePlaylistSettings) SavePlaylistSettings(ctx context.Context, settings domain.PlaylistSettings) error {
+	f[settings.PlaylistID] = settings
+	return nil
+}
+
+func TestOrchestrator_PlaylistSettings(t *testing.T) {
+	store := fakePlaylistSettings{}
+	o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, WithPlaylistSettings(store))
+	ctx := context.Background()
+
+	got, err := o.GetPlaylistSettings(ctx, "pl-1")
+	if err != nil || got != domain.DefaultPlaylistSettings("pl-1") {
+		t.Fatalf("expected the defaults, got %+v (%v)", got, err)
+	}
+	saved, err := o.UpdatePlaylistSettings(ctx, domain.PlaylistSettings{PlaylistID: "pl-1"})
+	if err != nil || saved.AllowExplicit || saved.UpdatedAt.IsZero() {
+		t.Fatalf("unexpected saved settings %+v (%v)", saved, err)
+	}
+	if got, err = o.GetPlaylistSettings(ctx, "pl-1"); err != nil || got != saved {
+		t.Fatalf("expected %+v back, got %+v (%v)", saved, got, err)
+	}
+
+	missing := NewOrchestrator(&mockSpotify{}, &mockRepo{getErr: domain.ErrNotFound}, nil, WithPlaylistSettings(store))
+	if _, err := missing.UpdatePlaylistSettings(ctx, domain.PlaylistSettings{PlaylistID: "nope"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown playlist, got %v", err)
+	}
+}
+
+func TestOrchestrator_AddTrackToPlaylist_Explicit(t *testing.T) {
+	explicit := domain.Track{ID: "t1", Title: "Song", Artist: "Artist", Explicit: true}
+	tests := []struct {
+		name     string
+		settings fakePlaylistSettings
+		wantErr  error
+	}{
+		{name: "Allowed by default", settings: fakePlaylistSettings{}},
+		{name: "Family friendly", settings: fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1"}}, wantErr: domain.ErrExplicitTrack},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepo{}
+			o := NewOrchestrator(&mockSpotify{track: explicit}, repo, nil, WithPlaylistSettings(tc.settings))
+
+			_, _, _, err := o.AddTrackToPlaylist(context.Background(), "pl-1", "Song", "Artist")
+			if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if saved := repo.saved != nil; saved != (tc.wantErr == nil) {
+				t.Fatalf("expected saved=%v", tc.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_ProcessIntent_SkipsExplicit(t *testing.T) {
+	intent := domain.IntentObject{}
+	intent.Entities.Artists = []string{"Artist"}
+	catalog := []domain.Track{{ID: "clean"}, {ID: "explicit", Explicit: true}}
+	settings := fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1"}}
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}
+	o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, &mockIntentCompiler{intent: intent}, WithPlaylistSettings(settings))
+
+	result, err := o.ProcessIntent(context.Background(), "pl-1", "anything by Artist")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TracksEvaluated != 1 || result.TracksAdded != 1 {
+		t.Fatalf("expected only the clean track to be considered, got %+v", result)
+	}
+}
+
+func TestOrchestrator_UpdatePlaylistSettings_Invalid(t *testing.T) {
+	o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, WithPlaylistSettings(fakePlaylistSettings{}))
+	for _, s := range []domain.PlaylistSettings{
+		{PlaylistID: "pl-1", MaxTracks: -1},
+		{PlaylistID: "pl-1", TargetDurationMs: domain.MaxPlaylistDurationMs + 1},
+		{PlaylistID: "pl-1", DefaultMarket: "usa"},
+	} {
+		if _, err := o.UpdatePlaylistSettings(context.Background(), s); !errors.Is(err, domain.ErrInvalidSettings) {
+			t.Fatalf("expected ErrInvalidSettings for %+v, got %v", s, err)
+		}
+	}
+}
+
+func TestOrchestrator_AddTrackToPlaylist_Full(t *testing.T) {
+	settings := fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1", AllowExplicit: true, MaxTracks: 1}}
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "t0"}}}}
+	o := NewOrchestrator(&mockSpotify{track: domain.Track{ID: "t1"}}, repo, nil, WithPlaylistSettings(settings))
+
+	if _, _, _, err := o.AddTrackToPlaylist(context.Background(), "pl-1", "Song", "Artist"); !errors.Is(err, domain.ErrPlaylistFull) {
+		t.Fatalf("expected ErrPlaylistFull, got %v", err)
+	}
+	if repo.saved != nil {
+		t.Fatal("expected the playlist not to be saved")
+	}
+}
+
+func TestOrchestrator_ProcessIntent_PlaylistLimits(t *testing.T) {
+	intent := domain.IntentObject{}
+	intent.Entities.Artists = []string{"Artist"}
+	minute := 60 * 1000
+	catalog := []domain.Track{{ID: "a", DurationMs: 4 * minute}, {ID: "b", DurationMs: 4 * minute}, {ID: "c", DurationMs: 4 * minute}}
+	tests := []struct {
+		name       string
+		settings   domain.PlaylistSettings
+		existing   []domain.Track
+		wantAdded  int
+		wantTarget int
+	}{
+		{name: "Room for two", settings: domain.PlaylistSettings{MaxTracks: 2}, wantAdded: 2},
+		{name: "Already full", settings: domain.PlaylistSettings{MaxTracks: 1}, existing: []domain.Track{{ID: "x"}}, wantAdded: 0},
+		{name: "Target duration", settings: domain.PlaylistSettings{TargetDurationMs: 8 * minute}, existing: []domain.Track{{ID: "x", DurationMs: 4 * minute}}, wantAdded: 1, wantTarget: 8 * minute},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.settings.PlaylistID, tc.settings.AllowExplicit = "pl-1", true
+			repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: tc.existing}}
+			settings := fakePlaylistSettings{"pl-1": tc.settings}
+			o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, &mockIntentCompiler{intent: intent}, WithPlaylistSettings(settings))
+
+			result, err := o.ProcessIntent(context.Background(), "pl-1", "anything by Artist")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TracksAdded != tc.wantAdded || result.TargetDurationMs != tc.wantTarget {
+				t.Fatalf("expected %d tracks added toward %d ms, got %+v", tc.wantAdded, tc.wantTarget, result)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_ReorderTracks_Locked(t *testing.T) {
+	settings := fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1", AllowExplicit: true, LockedOrdering: true}}
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "a"}, {ID: "b"}}}}
+	o := NewOrchestrator(&mockSpotify{}, repo, nil, WithPlaylistSettings(settings))
+
+	if _, err := o.ReorderTracks(context.Background(), "pl-1", []string{"b", "a"}); !errors.Is(err, domain.ErrPlaylistLocked) {
+		t.Fatalf("expected ErrPlaylistLocked, got %v", err)
+	}
+}
+
+// marketSpotify records the market each top tracks lookup was made in.
+type marketSpotify struct {
+	catalogSpotify
+	markets []string
+}
+
+func (m *marketSpotify) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	m.markets = append(m.markets, ports.MarketFrom(ctx))
+	return m.catalogSpotify.GetArtistTopTracks(ctx, artistName)
+}
+
+func TestOrchestrator_ProcessIntent_Market(t *testing.T) {
+	intent := domain.IntentObject{}
+	intent.Entities.Artists = []string{"Artist"}
+	tests := []struct {
+		name     string
+		settings fakePlaylistSettings
+		ctx      context.Context
+		want     string
+	}{
+		{name: "Configured market", settings: fakePlaylistSettings{}, ctx: context.Background(), want: ""},
+		{name: "Playlist market", settings: fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1", AllowExplicit: true, DefaultMarket: "GB"}}, ctx: context.Background(), want: "GB"},
+		{name: "Request market wins", settings: fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1", AllowExplicit: true, DefaultMarket: "GB"}}, ctx: ports.WithMarket(context.Background(), "JP"), want: "JP"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spotify := &marketSpotify{}
+			o := NewOrchestrator(spotify, &mockRepo{}, &mockIntentCompiler{intent: intent}, WithPlaylistSettings(tc.settings))
+
+			if _, err := o.ProcessIntent(tc.ctx, "pl-1", "anything by Artist"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(spotify.markets) != 1 || spotify.markets[0] != tc.want {
+				t.Fatalf("expected a lookup in %q, got %q", tc.want, spotify.markets)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_AddAlbumToPlaylist_SkipsExplicit(t *testing.T) {
+	album := domain.Album{ID: "al-1", Tracks: []domain.Track{{ID: "clean"}, {ID: "explicit", Explicit: true}}}
+	settings := fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1"}}
+	repo := &recordingRepo{mockRepo: mockRepo{playlist: domain.Playlist{ID: "pl-1"}}}
+	o := NewOrchestrator(&mockSpotify{}, repo, nil, WithAlbums(stubAlbums{album: album}), WithPlaylistSettings(settings))
+
+	result, err := o.AddAlbumToPlaylist(context.Background(), "pl-1", "Album", "Artist")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.added) != 1 || repo.added[0].ID != "clean" || result.TracksSkipped != 1 {
+		t.Fatalf("expected only the clean track added, got %v (%+v)", repo.added, result)
+	}
+}
+
+func TestOrchestrator_AddEpisodeToPlaylist_SkipsExplicit(t *testing.T) {
+	episodes := []domain.Episode{{ID: "explicit", Explicit: true}, {ID: "clean"}}
+	settings := fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1"}}
+	o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, WithPodcasts(stubPodcasts{episodes: episodes}), WithPlaylistSettings(settings))
+
+	got, err := o.AddEpisodeToPlaylist(context.Background(), "pl-1", "news briefing", false)
+	if err != nil || got.ID != "clean" {
+		t.Fatalf("expected the clean episode, got %q (%v)", got.ID, err)
+	}
+
+	only := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, WithPodcasts(stubPodcasts{episodes: episodes[:1]}), WithPlaylistSettings(settings))
+	if _, err := only.AddEpisodeToPlaylist(context.Background(), "pl-1", "news briefing", false); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound with only an explicit episode, got %v", err)
+	}
+}
+
+func TestOrchestrator_GenerateRunningPlaylist_SkipsExplicit(t *testing.T) {
+	catalog := []domain.Track{
+		{ID: "explicit", Explicit: true, DurationMs: 3 * minute, Features: domain.AudioFeatures{Tempo: 120}},
+		{ID: "clean", DurationMs: 3 * minute, Features: domain.AudioFeatures{Tempo: 120}},
+	}
+	settings := fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1"}}
+	repo := &recordingRepo{mockRepo: mockRepo{playlist: domain.Playlist{ID: "pl-1"}}}
+	o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, nil, WithPlaylistSettings(settings))
+
+	// The warm-up opens at 120 BPM.
+	tmpl := domain.RunningTemplate{Artists: []string{"Artist"}, TargetSPM: 160, DurationMs: 30 * minute}
+	result, err := o.GenerateRunningPlaylist(context.Background(), "pl-1", tmpl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.added) != 1 || repo.added[0].ID != "clean" || result.TracksEvaluated != 1 {
+		t.Fatalf("expected only the clean track considered, got %v (%+v)", repo.added, result)
+	}
+}
+
+func TestOrchestrator_AddAlbumToPlaylist_PlaylistLimits(t *testing.T) {
+	album := domain.Album{ID: "al-1", Tracks: []domain.Track{{ID: "a1"}, {ID: "a2"}, {ID: "a3"}}}
+	tests := []struct {
+		name      string
+		existing  []domain.Track
+		wantAdded int
+		wantErr   error
+	}{
+		{name: "Room for two", existing: []domain.Track{{ID: "x"}}, wantAdded: 2},
+		{name: "Already full", existing: []domain.Track{{ID: "x"}, {ID: "y"}, {ID: "z"}}, wantErr: domain.ErrPlaylistFull},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1", AllowExplicit: true, MaxTracks: 3}}
+			repo := &recordingRepo{mockRepo: mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: tc.existing}}}
+			o := NewOrchestrator(&mockSpotify{}, repo, nil, WithAlbums(stubAlbums{album: album}), WithPlaylistSettings(settings))
+
+			result, err := o.AddAlbumToPlaylist(context.Background(), "pl-1", "Album", "Artist")
+			if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if len(repo.added) != tc.wantAdded || result.TracksAdded != tc.wantAdded {
+				t.Fatalf("expected %d tracks added, got %v (%+v)", tc.wantAdded, repo.added, result)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_AddEpisodeToPlaylist_Full(t *testing.T) {
+	settings := fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1", AllowExplicit: true, MaxTracks: 1}}
+	repo := &recordingRepo{mockRepo: mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "x"}}}}}
+	o := NewOrchestrator(&mockSpotify{}, repo, nil, WithPodcasts(stubPodcasts{episodes: []domain.Episode{{ID: "ep-1"}}}), WithPlaylistSettings(settings))
+
+	if _, err := o.AddEpisodeToPlaylist(context.Background(), "pl-1", "news briefing", false); !errors.Is(err, domain.ErrPlaylistFull) {
+		t.Fatalf("expected ErrPlaylistFull, got %v", err)
+	}
+	if len(repo.added) != 0 {
+		t.Fatalf("expected nothing added, got %v", repo.added)
+	}
+}
+
+func TestOrchestrator_GenerateRunningPlaylist_PlaylistLimits(t *testing.T) {
+	var catalog []domain.Track
+	for i, tempo := range []float64{120, 70, 160, 160, 160, 160} {
+		catalog = append(catalog, domain.Track{ID: fmt.Sprintf("t%d", i), DurationMs: 3 * minute, Features: domain.AudioFeatures{Tempo: tempo}})
+	}
+	settings := fakePlaylistSettings{"pl-1": {PlaylistID: "pl-1", AllowExplicit: true, MaxTracks: 3}}
+	repo := &recordingRepo{mockRepo: mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "x"}}}}}
+	o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, nil, WithPlaylistSettings(settings))
+
+	tmpl := domain.RunningTemplate{Artists: []string{"Artist"}, TargetSPM: 160, DurationMs: 30 * minute}
+	result, err := o.GenerateRunningPlaylist(context.Background(), "pl-1", tmpl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.added) != 2 || result.TracksAdded != 2 || len(result.Tracks) != 2 || result.DurationMs != 6*minute {
+		t.Fatalf("expected the two tracks that fit, got %v (%+v)", repo.added, result)
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// episodeCandidates is how many search results AddEpisodeToPlaylist
+// considers.
+const episodeCandidates = 10
+
+// WithPodcasts enables podcast episode search and mixed playlists.
+func WithPodcasts(provider ports.PodcastProvider) Option {
+	return func(o *Orchestrator) {
+		o.podcasts = provider
+	}
+}
+
+// HasPodcasts returns true if a podcast provider is configured.
+func (o *Orchestrator) HasPodcasts() bool {
+	return o.podcasts != nil
+}
+
+// SearchEpisodes returns up to limit podcast episodes matching query.
+func (o *Orchestrator) SearchEpisodes(ctx context.Context, query string, limit int) ([]domain.Episode, error) {
+	if o.podcasts == nil {
+		return nil, fmt.Errorf("service: podcast provider not configured")
+	}
+	if query == "" {
+		return nil, fmt.Errorf("service: episode query cannot be empty")
+	}
+	episodes, err := o.podcasts.SearchEpisodes(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to search episodes: %w", err)
+	}
+	return episodes, nil
+}
+
+// AddEpisodeToPlaylist adds the best episode matching query that is not
+// already in the playlist: the most relevant one, or the most recently
+// published one when latest is set (as for a daily news briefing). Explicit
+// episodes are passed over when the playlist's settings rule them out, and a
+// playlist at its MaxTracks fails with domain.ErrPlaylistFull. It returns
+// domain.ErrNotFound when the playlist does not exist or no new
+// episode matches.
+func (o *Orchestrator) AddEpisodeToPlaylist(ctx context.Context, playlistID, query string, latest bool) (domain.Episode, error) {
+	settings, err := o.settingsFor(ctx, playlistID)
+	if err != nil {
+		return domain.Episode{}, err
+	}
+	// Search before the transaction so a slow provider never holds a write
+	// lock.
+	episodes, err := o.SearchEpisodes(inMarket(ctx, settings), query, episodeCandidates)
+	if err != nil {
+		return domain.Episode{}, err
+	}
+	if latest {
+		episodes = append([]domain.Episode(nil), episodes...)
+		sort.SliceStable(episodes, func(i, j int) bool {
+			return episodes[i].PublishedAt.After(episodes[j].PublishedAt)
+		})
+	}
+
+	var added domain.Episode
+	err = o.atomically(ctx, func(ctx context.Context, repo ports.PlaylistRepository) error {
+		playlist, err := repo.GetByID(ctx, playlistID)
+		if err != nil {
+			return fmt.Errorf("service: failed to load playlist: %w", err)
+		}
+		inPlaylist := make(map[string]bool, len(playlist.Tracks))
+		for _, t := range playlist.Tracks {
+			inPlaylist[t.ID] = true
+		}
+		for _, ep := range episodes {
+			if inPlaylist[ep.ID] || !settings.Allows(ep.Item()) {
+				continue
+			}
+			if len(settings.Room([]domain.Track{ep.Item()}, len(playlist.Tracks))) == 0 {
+				return fmt.Errorf("service: domain rule violation: %w", domain.ErrPlaylistFull)
+			}
+			if err := repo.AddTracksToPlaylist(ctx, playlistID, []domain.Track{ep.Item()}); err != nil {
+				return fmt.Errorf("service: failed to add episode to playlist: %w", err)
+			}
+			added = ep
+			return nil
+		}
+		return fmt.Errorf("service: no new episode matches %q: %w", query, domain.ErrNotFound)
+	})
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			o.report(ctx, err, map[string]string{"operation": "add_episode", "playlist_id": playlistID})
+		}
+		return domain.Episode{}, err
+	}
+	return added, nil
+}
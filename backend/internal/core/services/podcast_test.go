@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type stubPodcasts struct {
+	episodes []domain.Episode
+	err      error
+}
+
+func (s stubPodcasts) SearchEpisodes(ctx context.Context, query string, limit int) ([]domain.Episode, error) {
+	return s.episodes, s.err
+}
+
+func TestOrchestrator_AddEpisodeToPlaylist(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	episodes := []domain.Episode{
+		{ID: "relevant", Show: "The Daily", PublishedAt: day(1)},
+		{ID: "newest", Show: "The Daily", PublishedAt: day(5)},
+		{ID: "older", Show: "The Daily", PublishedAt: day(3)},
+	}
+	present := domain.Playlist{ID: "pl-1", Name: "Focus", Tracks: []domain.Track{episodes[0].Item()}}
+
+	tests := []struct {
+		name         string
+		opts         []Option
+		repo         *mockRepo
+		latest       bool
+		wantID       string
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{name: "not configured", repo: &mockRepo{}, wantErr: true},
+		{name: "most relevant", opts: []Option{WithPodcasts(stubPodcasts{episodes: episodes})}, repo: &mockRepo{}, wantID: "relevant"},
+		{name: "latest", opts: []Option{WithPodcasts(stubPodcasts{episodes: episodes})}, repo: &mockRepo{}, latest: true, wantID: "newest"},
+		{name: "skips episodes already present", opts: []Option{WithPodcasts(stubPodcasts{episodes: episodes})}, repo: &mockRepo{playlist: present}, wantID: "newest"},
+		{name: "no results", opts: []Option{WithPodcasts(stubPodcasts{})}, repo: &mockRepo{}, wantNotFound: true, wantErr: true},
+		{name: "unknown playlist", opts: []Option{WithPodcasts(stubPodcasts{episodes: episodes})}, repo: &mockRepo{getErr: domain.ErrNotFound}, wantNotFound: true, wantErr: true},
+		{name: "search failure", opts: []Option{WithPodcasts(stubPodcasts{err: errors.New("rate limited")})}, repo: &mockRepo{}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewOrchestrator(&mockSpotify{}, tc.repo, nil, tc.opts...)
+			got, err := o.AddEpisodeToPlaylist(context.Background(), "pl-1", "news briefing", tc.latest)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected err=%v, got %v", tc.wantErr, err)
+			}
+			if errors.Is(err, domain.ErrNotFound) != tc.wantNotFound {
+				t.Fatalf("expected not found=%v, got %v", tc.wantNotFound, err)
+			}
+			if got.ID != tc.wantID {
+				t.Fatalf("expected episode %q, got %q", tc.wantID, got.ID)
+			}
+		})
+	}
+}
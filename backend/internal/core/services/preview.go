@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithPreviewFallback looks up previews with resolver for tracks stored
+// without one, saving what it finds to store. tracks resolves stored track
+// IDs to the metadata the resolver matches on.
+func WithPreviewFallback(resolver ports.PreviewResolver, tracks ports.TrackLookup, store ports.PreviewStore) Option {
+	return func(o *Orchestrator) {
+		o.previews = resolver
+		o.tracks = tracks
+		o.previewStore = store
+	}
+}
+
+// FallbackPreview resolves and stores a preview URL for a stored track that
+// has none. ok is false when the fallback is not configured, the track is
+// an episode or no source has a preview.
+func (o *Orchestrator) FallbackPreview(ctx context.Context, trackID string) (previewURL string, ok bool) {
+	if o.previews == nil {
+		return "", false
+	}
+	previewURL, err := o.fallbackPreview(ctx, trackID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			o.report(ctx, err, map[string]string{"operation": "fallback_preview", "track_id": trackID})
+		}
+		return "", false
+	}
+	return previewURL, true
+}
+
+func (o *Orchestrator) fallbackPreview(ctx context.Context, trackID string) (string, error) {
+	track, err := o.tracks.GetTrack(ctx, trackID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", err
+		}
+		return "", fmt.Errorf("service: failed to load track: %w", err)
+	}
+	if track.PreviewURL != "" {
+		return track.PreviewURL, nil
+	}
+	if track.IsEpisode() {
+		return "", fmt.Errorf("service: episodes have no fallback previews: %w", domain.ErrNotFound)
+	}
+
+	previewURL, err := o.previews.ResolvePreview(ctx, track)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", err
+		}
+		return "", fmt.Errorf("service: failed to resolve preview: %w", err)
+	}
+	if err := o.previewStore.SetPreviewURL(ctx, trackID, previewURL); err != nil {
+		// The analyzer can still use it; it is just looked up again next time.
+		o.report(ctx, fmt.Errorf("service: failed to save preview url: %w", err), map[string]string{"operation": "save_preview_url", "track_id": trackID})
+	}
+	return previewURL, nil
+}
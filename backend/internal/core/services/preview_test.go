@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// stubPreviews resolves previews by track ID and counts lookups.
+type stubPreviews struct {
+	urls  map[string]string
+	err   error
+	calls int
+}
+
+func (s *stubPreviews) ResolvePreview(ctx context.Context, track domain.Track) (string, error) {
+	s.calls++
+	if s.err != nil {
+		return "", s.err
+	}
+	u, ok := s.urls[track.ID]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	return u, nil
+}
+
+// memPreviews is an in-memory ports.PreviewStore.
+type memPreviews map[string]string
+
+func (m memPreviews) SetPreviewURL(ctx context.Context, trackID, previewURL string) error {
+	m[trackID] = previewURL
+	return nil
+}
+
+func TestFallbackPreview(t *testing.T) {
+	tracks := newMemLyrics(
+		domain.Track{ID: "t-none", Title: "Redbone", Artist: "Childish Gambino"},
+		domain.Track{ID: "t-has", Title: "Song", Artist: "Band", PreviewURL: "https://p.scdn.co/has.mp3"},
+		domain.Track{ID: "t-missing", Title: "Obscure", Artist: "Nobody"},
+		domain.Track{ID: "ep-1", Title: "Episode", Type: domain.ItemEpisode},
+	)
+
+	tests := []struct {
+		name       string
+		trackID    string
+		resolveErr error
+		wantURL    string
+		wantOK     bool
+		wantCalls  int
+		wantStored bool
+	}{
+		{name: "resolves and stores", trackID: "t-none", wantURL: "https://cdn.deezer.com/redbone.mp3", wantOK: true, wantCalls: 1, wantStored: true},
+		{name: "existing preview is kept", trackID: "t-has", wantURL: "https://p.scdn.co/has.mp3", wantOK: true},
+		{name: "no source has one", trackID: "t-missing", wantCalls: 1},
+		{name: "episodes are skipped", trackID: "ep-1"},
+		{name: "unknown track", trackID: "nope"},
+		{name: "resolver failure", trackID: "t-none", resolveErr: errors.New("boom"), wantCalls: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resolver := &stubPreviews{urls: map[string]string{"t-none": "https://cdn.deezer.com/redbone.mp3"}, err: tc.resolveErr}
+			store := memPreviews{}
+			reporter := &fakeReporter{}
+			svc := NewOrchestrator(nil, nil, nil,
+				WithPreviewFallback(resolver, tracks, store),
+				WithErrorReporter(reporter))
+
+			got, ok := svc.FallbackPreview(context.Background(), tc.trackID)
+			if got != tc.wantURL || ok != tc.wantOK {
+				t.Fatalf("expected (%q, %v), got (%q, %v)", tc.wantURL, tc.wantOK, got, ok)
+			}
+			if resolver.calls != tc.wantCalls {
+				t.Errorf("expected %d resolver calls, got %d", tc.wantCalls, resolver.calls)
+			}
+			if _, stored := store[tc.trackID]; stored != tc.wantStored {
+				t.Errorf("expected stored=%v, got %v", tc.wantStored, stored)
+			}
+			if wantReport := tc.resolveErr != nil; (len(reporter.errs) > 0) != wantReport {
+				t.Errorf("expected report=%v, got %v", wantReport, reporter.errs)
+			}
+		})
+	}
+
+	if _, ok := NewOrchestrator(nil, nil, nil).FallbackPreview(context.Background(), "t-none"); ok {
+		t.Fatal("expected no preview without a resolver")
+	}
+}
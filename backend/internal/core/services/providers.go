@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+)
+
+// ProviderRegistry looks tracks up in every configured catalog at once and
+// merges what they find by ISRC. It is a ports.SpotifyProvider, so the
+// Orchestrator uses it in place of Spotify alone.
+//
+// The primary provider's tracks win: their IDs are the ones playback,
+// preview analysis and library mirroring understand. Other providers fill
+// in what the primary's copy of the same recording lacks, such as a preview
+// or cover, and stand in with their own tracks when the primary has none.
+// Every track carries the Source of the provider it came from.
+type ProviderRegistry struct {
+	primary   ports.SpotifyProvider
+	providers []namedProvider
+	logger    *slog.Logger
+}
+
+type namedProvider struct {
+	name     string
+	provider ports.CatalogProvider
+}
+
+// NewProviderRegistry returns a registry whose primary provider, recorded
+// as source name, is primary. A nil logger logs through the default one.
+func NewProviderRegistry(name string, primary ports.SpotifyProvider, logger *slog.Logger) *ProviderRegistry {
+	return &ProviderRegistry{
+		primary:   primary,
+		providers: []namedProvider{{name: name, provider: primary}},
+		logger:    logging.Component(logger, "providers"),
+	}
+}
+
+// Register adds a secondary provider recorded as source name. Providers
+// registered first take precedence when the primary has no result.
+func (r *ProviderRegistry) Register(name string, provider ports.CatalogProvider) {
+	r.providers = append(r.providers, namedProvider{name: name, provider: provider})
+}
+
+// Providers returns the source names of the registered providers, primary
+// first.
+func (r *ProviderRegistry) Providers() []string {
+	names := make([]string, len(r.providers))
+	for i, p := range r.providers {
+		names[i] = p.name
+	}
+	return names
+}
+
+// GetTrackByMetadata implements ports.SpotifyProvider through the primary
+// provider alone.
+func (r *ProviderRegistry) GetTrackByMetadata(ctx context.Context, title, artist string) (domain.Track, error) {
+	track, err := r.primary.GetTrackByMetadata(ctx, title, artist)
+	if err != nil {
+		return domain.Track{}, err
+	}
+	return withSource(track, r.providers[0].name), nil
+}
+
+// GetTrack implements ports.SpotifyProvider. It fails with the primary's
+// error only when no provider finds the track.
+func (r *ProviderRegistry) GetTrack(ctx context.Context, title, artist string) (domain.Track, error) {
+	type result struct {
+		track domain.Track
+		err   error
+	}
+	results := make([]result, len(r.providers))
+	r.fanOut(func(i int, p namedProvider) {
+		track, err := p.provider.GetTrack(ctx, title, artist)
+		results[i] = result{withSource(track, p.name), err}
+	})
+
+	var found []domain.Track
+	for i, res := range results {
+		if res.err != nil {
+			if i > 0 {
+				r.logger.DebugContext(ctx, "provider lookup failed", "provider", r.providers[i].name, "error", res.err)
+			}
+			continue
+		}
+		found = append(found, res.track)
+	}
+	if len(found) == 0 {
+		return domain.Track{}, results[0].err
+	}
+	return mergeRecordings(found[0], found[1:]), nil
+}
+
+// GetArtistTopTracks implements ports.SpotifyProvider. The primary's tracks
+// come first, followed by the recordings only other providers know.
+func (r *ProviderRegistry) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	type result struct {
+		tracks []domain.Track
+		err    error
+	}
+	results := make([]result, len(r.providers))
+	r.fanOut(func(i int, p namedProvider) {
+		tracks, err := p.provider.GetArtistTopTracks(ctx, artistName)
+		for j := range tracks {
+			tracks[j] = withSource(tracks[j], p.name)
+		}
+		results[i] = result{tracks, err}
+	})
+
+	var merged []domain.Track
+	byISRC := map[string]int{}
+	byName := map[string]bool{}
+	ok := false
+	for i, res := range results {
+		if res.err != nil {
+			if i > 0 {
+				r.logger.DebugContext(ctx, "provider lookup failed", "provider", r.providers[i].name, "error", res.err)
+			}
+			continue
+		}
+		ok = true
+		for _, t := range res.tracks {
+			isrc := strings.ToUpper(t.ISRC)
+			if j, seen := byISRC[isrc]; seen && isrc != "" {
+				merged[j] = mergeRecordings(merged[j], []domain.Track{t})
+				continue
+			}
+			name := strings.ToLower(t.Title + "\x00" + t.Artist)
+			if byName[name] {
+				continue
+			}
+			byName[name] = true
+			if isrc != "" {
+				byISRC[isrc] = len(merged)
+			}
+			merged = append(merged, t)
+		}
+	}
+	if !ok {
+		return nil, results[0].err
+	}
+	return merged, nil
+}
+
+// fanOut calls fn for every provider concurrently and waits for all.
+func (r *ProviderRegistry) fanOut(fn func(i int, p namedProvider)) {
+	var wg sync.WaitGroup
+	for i, p := range r.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(i, p)
+		}()
+	}
+	wg.Wait()
+}
+
+// withSource records name as the track's source unless the provider
+// already did.
+func withSource(t domain.Track, name string) domain.Track {
+	if t.Source == "" {
+		t.Source = name
+	}
+	return t
+}
+
+// mergeRecordings fills what base lacks from the others with the same
+// ISRC. base keeps its ID and source.
+func mergeRecordings(base domain.Track, others []domain.Track) domain.Track {
+	for _, o := range others {
+		if base.ISRC == "" || !strings.EqualFold(base.ISRC, o.ISRC) {
+			continue
+		}
+		if base.Album == "" {
+			base.Album = o.Album
+		}
+		if base.CoverURL == "" {
+			base.CoverURL = o.CoverURL
+		}
+		if base.PreviewURL == "" {
+			base.PreviewURL = o.PreviewURL
+		}
+		if base.DurationMs == 0 {
+			base.DurationMs = o.DurationMs
+		}
+		if base.Popularity == 0 {
+			base.Popularity = o.Popularity
+		}
+		if base.ReleaseYear == 0 {
+			base.ReleaseYear = o.ReleaseYear
+		}
+		// Either catalog rating the recording explicit is enough.
+		base.Explicit = base.Explicit || o.Explicit
+	}
+	return base
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// stubCatalog is a ports.CatalogProvider with fixed results.
+type stubCatalog struct {
+	track domain.Track
+	top   []domain.Track
+	err   error
+}
+
+func (s *stubCatalog) GetTrack(ctx context.Context, title, artist string) (domain.Track, error) {
+	if s.err != nil {
+		return domain.Track{}, s.err
+	}
+	return s.track, nil
+}
+
+func (s *stubCatalog) GetArtistTopTracks(ctx context.Context, artistName string) ([]domain.Track, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return append([]domain.Track(nil), s.top...), nil
+}
+
+func TestProviderRegistry_GetTrack(t *testing.T) {
+	spotifyTrack := domain.Track{ID: "sp1", Title: "Song", Artist: "Band", ISRC: "USABC1234567"}
+	appleTrack := domain.Track{ID: "apple_music:1", Title: "Song", Artist: "Band", ISRC: "usabc1234567",
+		PreviewURL: "https://apple/preview.m4a", CoverURL: "https://apple/cover.jpg", Source: domain.SourceAppleMusic}
+	otherTrack := domain.Track{ID: "apple_music:2", Title: "Song", Artist: "Band", ISRC: "GBXYZ7654321", PreviewURL: "https://apple/other.m4a"}
+
+	tests := []struct {
+		name        string
+		primary     *mockSpotify
+		secondary   *stubCatalog
+		wantID      string
+		wantSource  string
+		wantPreview string
+		wantErr     error
+	}{
+		{
+			name:        "Merges the same recording by ISRC",
+			primary:     &mockSpotify{track: spotifyTrack},
+			secondary:   &stubCatalog{track: appleTrack},
+			wantID:      "sp1",
+			wantSource:  domain.SourceSpotify,
+			wantPreview: "https://apple/preview.m4a",
+		},
+		{
+			name:       "Keeps different recordings apart",
+			primary:    &mockSpotify{track: spotifyTrack},
+			secondary:  &stubCatalog{track: otherTrack},
+			wantID:     "sp1",
+			wantSource: domain.SourceSpotify,
+		},
+		{
+			name:        "Falls back to a secondary provider",
+			primary:     &mockSpotify{err: domain.ErrNotFound},
+			secondary:   &stubCatalog{track: appleTrack},
+			wantID:      "apple_music:1",
+			wantSource:  domain.SourceAppleMusic,
+			wantPreview: "https://apple/preview.m4a",
+		},
+		{
+			name:      "Returns the primary error when no provider finds it",
+			primary:   &mockSpotify{err: domain.ErrNotFound},
+			secondary: &stubCatalog{err: errors.New("unavailable")},
+			wantErr:   domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewProviderRegistry(domain.SourceSpotify, tt.primary, nil)
+			registry.Register(domain.SourceAppleMusic, tt.secondary)
+
+			got, err := registry.GetTrack(context.Background(), "Song", "Band")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ID != tt.wantID || got.Source != tt.wantSource {
+				t.Errorf("expected %s from %s, got %s from %s", tt.wantID, tt.wantSource, got.ID, got.Source)
+			}
+			if got.PreviewURL != tt.wantPreview {
+				t.Errorf("expected preview %q, got %q", tt.wantPreview, got.PreviewURL)
+			}
+		})
+	}
+}
+
+func TestProviderRegistry_GetArtistTopTracks(t *testing.T) {
+	primary := &mockSpotify{track: domain.Track{ID: "sp1", Title: "Hit", Artist: "Band", ISRC: "USABC0000001"}}
+	registry := NewProviderRegistry(domain.SourceSpotify, primary, nil)
+	registry.Register(domain.SourceAppleMusic, &stubCatalog{top: []domain.Track{
+		{ID: "apple_music:1", Title: "Hit", Artist: "Band", ISRC: "USABC0000001", CoverURL: "https://apple/hit.jpg"},
+		{ID: "apple_music:2", Title: "HIT", Artist: "band"},
+		{ID: "apple_music:3", Title: "Deep Cut", Artist: "Band", ISRC: "USABC0000003"},
+	}})
+
+	got, err := registry.GetArtistTopTracks(context.Background(), "Band")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected the hit once and the deep cut, got %+v", got)
+	}
+	if got[0].ID != "sp1" || got[0].Source != domain.SourceSpotify || got[0].CoverURL != "https://apple/hit.jpg" {
+		t.Errorf("expected the Spotify hit filled in from Apple Music, got %+v", got[0])
+	}
+	if got[1].ID != "apple_music:3" || got[1].Source != domain.SourceAppleMusic {
+		t.Errorf("expected the deep cut from Apple Music, got %+v", got[1])
+	}
+	if names := registry.Providers(); len(names) != 2 || names[0] != domain.SourceSpotify {
+		t.Errorf("expected spotify first, got %v", names)
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithQueue keeps each user's up-next queue in store.
+func WithQueue(store ports.QueueStore) Option {
+	return func(o *Orchestrator) {
+		o.queue = store
+	}
+}
+
+// HasQueue returns true if up-next queues are stored.
+func (o *Orchestrator) HasQueue() bool {
+	return o.queue != nil
+}
+
+// GetQueue returns owner's up-next queue, which is empty until something
+// is queued.
+func (o *Orchestrator) GetQueue(ctx context.Context, owner string) (domain.Queue, error) {
+	if o.queue == nil {
+		return domain.Queue{}, fmt.Errorf("service: queue not configured")
+	}
+	q, err := o.queue.GetQueue(ctx, owner)
+	if err != nil {
+		return domain.Queue{}, fmt.Errorf("service: failed to load queue: %w", err)
+	}
+	return q, nil
+}
+
+// AddToQueue looks up a track by title and artist and queues it for owner,
+// ahead of everything else when next is set. It returns the updated queue.
+func (o *Orchestrator) AddToQueue(ctx context.Context, owner, title, artist string, next bool) (domain.Queue, error) {
+	if o.queue == nil {
+		return domain.Queue{}, fmt.Errorf("service: queue not configured")
+	}
+	track, err := o.spotify.GetTrack(ctx, title, artist)
+	if err != nil {
+		return domain.Queue{}, fmt.Errorf("service: failed to fetch track: %w", err)
+	}
+	return o.EnqueueTracks(ctx, owner, []domain.Track{track}, next)
+}
+
+// EnqueueTracks queues tracks for owner without touching any playlist; it
+// is how radio and suggestions hand tracks over. With next set they play
+// ahead of everything already queued, in the given order.
+func (o *Orchestrator) EnqueueTracks(ctx context.Context, owner string, tracks []domain.Track, next bool) (domain.Queue, error) {
+	if o.queue == nil {
+		return domain.Queue{}, fmt.Errorf("service: queue not configured")
+	}
+	var err error
+	if next {
+		// Each insert goes to the front, so walk backwards to keep order.
+		for i := len(tracks) - 1; i >= 0 && err == nil; i-- {
+			err = o.queue.InsertNextInQueue(ctx, owner, tracks[i])
+		}
+	} else {
+		err = o.queue.AppendToQueue(ctx, owner, tracks)
+	}
+	if err != nil {
+		err = fmt.Errorf("service: failed to update queue: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "enqueue_tracks", "owner": owner})
+		return domain.Queue{}, err
+	}
+	return o.GetQueue(ctx, owner)
+}
+
+// PopQueue removes and returns owner's next queued track. It returns
+// domain.ErrNotFound when the queue is empty.
+func (o *Orchestrator) PopQueue(ctx context.Context, owner string) (domain.Track, error) {
+	if o.queue == nil {
+		return domain.Track{}, fmt.Errorf("service: queue not configured")
+	}
+	track, err := o.queue.PopQueue(ctx, owner)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Track{}, err
+		}
+		err = fmt.Errorf("service: failed to pop queue: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "pop_queue", "owner": owner})
+		return domain.Track{}, err
+	}
+	return track, nil
+}
+
+// ClearQueue empties owner's queue.
+func (o *Orchestrator) ClearQueue(ctx context.Context, owner string) error {
+	if o.queue == nil {
+		return fmt.Errorf("service: queue not configured")
+	}
+	if err := o.queue.ClearQueue(ctx, owner); err != nil {
+		err = fmt.Errorf("service: failed to clear queue: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "clear_queue", "owner": owner})
+		return err
+	}
+	return nil
+}
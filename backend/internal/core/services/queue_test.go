@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// memQueue is an in-memory ports.QueueStore.
+type memQueue map[string]*domain.Queue
+
+func (m memQueue) get(owner string) *domain.Queue {
+	if m[owner] == nil {
+		m[owner] = &domain.Queue{Owner: owner, Tracks: []domain.Track{}}
+	}
+	return m[owner]
+}
+
+func (m memQueue) GetQueue(ctx context.Context, owner string) (domain.Queue, error) {
+	q := *m.get(owner)
+	q.Tracks = append([]domain.Track{}, q.Tracks...)
+	return q, nil
+}
+
+func (m memQueue) AppendToQueue(ctx context.Context, owner string, tracks []domain.Track) error {
+	m.get(owner).Append(tracks...)
+	return nil
+}
+
+func (m memQueue) InsertNextInQueue(ctx context.Context, owner string, track domain.Track) error {
+	m.get(owner).InsertNext(track)
+	return nil
+}
+
+func (m memQueue) PopQueue(ctx context.Context, owner string) (domain.Track, error) {
+	t, ok := m.get(owner).Pop()
+	if !ok {
+		return domain.Track{}, domain.ErrNotFound
+	}
+	return t, nil
+}
+
+func (m memQueue) ClearQueue(ctx context.Context, owner string) error {
+	m.get(owner).Clear()
+	return nil
+}
+
+func queueOrder(q domain.Queue) string {
+	ids := make([]string, len(q.Tracks))
+	for i, t := range q.Tracks {
+		ids[i] = t.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestEnqueueTracks(t *testing.T) {
+	tests := []struct {
+		name string
+		next bool
+		want string
+	}{
+		{name: "appends", want: "q1,t1,t2"},
+		{name: "plays next in order", next: true, want: "t1,t2,q1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepo{}
+			svc := NewOrchestrator(nil, repo, nil, WithQueue(memQueue{}))
+			ctx := context.Background()
+			if _, err := svc.EnqueueTracks(ctx, "default", []domain.Track{{ID: "q1"}}, false); err != nil {
+				t.Fatalf("seed queue: %v", err)
+			}
+
+			q, err := svc.EnqueueTracks(ctx, "default", []domain.Track{{ID: "t1"}, {ID: "t2"}}, tc.next)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := queueOrder(q); got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+			if repo.saved != nil {
+				t.Fatalf("expected playlists untouched, got %+v", repo.saved)
+			}
+		})
+	}
+}
+
+func TestQueueOperations(t *testing.T) {
+	ctx := context.Background()
+	spotify := &mockSpotify{track: domain.Track{ID: "sp-1", Title: "Song"}}
+	svc := NewOrchestrator(spotify, &mockRepo{}, nil, WithQueue(memQueue{}))
+
+	if _, err := svc.PopQueue(ctx, "default"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound from an empty queue, got %v", err)
+	}
+	q, err := svc.AddToQueue(ctx, "default", "Song", "Artist", false)
+	if err != nil {
+		t.Fatalf("add to queue: %v", err)
+	}
+	if queueOrder(q) != "sp-1" || spotify.calledTitle != "Song" || spotify.calledArtist != "Artist" {
+		t.Fatalf("unexpected queue %+v after looking up %q by %q", q, spotify.calledTitle, spotify.calledArtist)
+	}
+	track, err := svc.PopQueue(ctx, "default")
+	if err != nil || track.ID != "sp-1" {
+		t.Fatalf("expected to pop sp-1, got %+v (%v)", track, err)
+	}
+
+	if _, err := svc.EnqueueTracks(ctx, "default", []domain.Track{{ID: "t1"}}, false); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if err := svc.ClearQueue(ctx, "default"); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if q, _ = svc.GetQueue(ctx, "default"); len(q.Tracks) != 0 {
+		t.Fatalf("expected empty queue, got %+v", q.Tracks)
+	}
+
+	spotify.err = errors.New("spotify down")
+	if _, err := svc.AddToQueue(ctx, "default", "Song", "Artist", false); err == nil {
+		t.Fatal("expected lookup failure to be returned")
+	}
+
+	unconfigured := NewOrchestrator(nil, &mockRepo{}, nil)
+	if unconfigured.HasQueue() {
+		t.Fatal("expected queue to be unconfigured")
+	}
+	if _, err := unconfigured.GetQueue(ctx, "default"); err == nil {
+		t.Fatal("expected error without a queue store")
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// recommendationHeadroom is how many more candidates are asked for than
+// returned, so tracks already in the playlist can be dropped.
+const recommendationHeadroom = 2
+
+// WithRecommendations enables recommending tracks for a playlist's vibe.
+func WithRecommendations(provider ports.RecommendationProvider) Option {
+	return func(o *Orchestrator) {
+		o.recommender = provider
+	}
+}
+
+// HasRecommendations returns true if a recommendation provider is
+// configured.
+func (o *Orchestrator) HasRecommendations() bool {
+	return o.recommender != nil
+}
+
+// Recommendations returns up to limit tracks the provider suggests from
+// the playlist's last tracks and average audio features, leaving out those
+// it already has, best scored first. A playlist without Spotify tracks to
+// seed from gets none.
+func (o *Orchestrator) Recommendations(ctx context.Context, playlistID string, limit int) ([]domain.PlaylistRecommendation, error) {
+	if o.recommender == nil {
+		return nil, fmt.Errorf("service: recommendation provider not configured")
+	}
+	playlist, err := o.repo.GetByID(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	ctx = o.inPlaylistMarket(ctx, playlistID)
+	seeds := playlist.RecommendationSeeds()
+	if len(seeds) == 0 {
+		return []domain.PlaylistRecommendation{}, nil
+	}
+	candidates, err := o.recommender.GetRecommendations(ctx, domain.RecommendationRequest{
+		SeedTrackIDs: seeds,
+		Target:       playlist.Analyze(),
+		Limit:        limit * recommendationHeadroom,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to fetch recommendations: %w", err)
+	}
+	return playlist.Recommend(candidates, limit), nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// fakeRecommender returns tracks and records the request it was sent.
+type fakeRecommender struct {
+	tracks []domain.Track
+	err    error
+	req    *domain.RecommendationRequest
+}
+
+func (f *fakeRecommender) GetRecommendations(ctx context.Context, req domain.RecommendationRequest) ([]domain.Track, error) {
+	f.req = &req
+	return f.tracks, f.err
+}
+
+func TestOrchestrator_Recommendations(t *testing.T) {
+	playlist := domain.Playlist{ID: "pl-1", Tracks: []domain.Track{
+		{ID: "a", Title: "A", Artist: "X", Features: domain.AudioFeatures{Energy: 0.4}},
+		{ID: "b", Title: "B", Artist: "X", Features: domain.AudioFeatures{Energy: 0.6}},
+	}}
+	tests := []struct {
+		name      string
+		playlist  domain.Playlist
+		provider  *fakeRecommender
+		wantErr   bool
+		wantIDs   []string
+		wantAsked bool
+	}{
+		{
+			name:     "filters and ranks",
+			playlist: playlist,
+			provider: &fakeRecommender{tracks: []domain.Track{
+				{ID: "far", Title: "Far", Artist: "Y", Features: domain.AudioFeatures{Energy: 1}},
+				{ID: "a", Title: "A", Artist: "X"},
+				{ID: "near", Title: "Near", Artist: "Z", Features: domain.AudioFeatures{Energy: 0.5}},
+			}},
+			wantIDs:   []string{"near", "far"},
+			wantAsked: true,
+		},
+		{
+			name:     "nothing to seed from",
+			playlist: domain.Playlist{ID: "pl-1"},
+			provider: &fakeRecommender{},
+		},
+		{
+			name:      "provider fails",
+			playlist:  playlist,
+			provider:  &fakeRecommender{err: errors.New("unavailable")},
+			wantErr:   true,
+			wantAsked: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewOrchestrator(&catalogSpotify{}, &mockRepo{playlist: tc.playlist}, nil, WithRecommendations(tc.provider))
+
+			recs, err := o.Recommendations(context.Background(), "pl-1", 5)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tc.wantErr)
+			}
+			if (tc.provider.req != nil) != tc.wantAsked {
+				t.Fatalf("expected provider asked = %v, got request %+v", tc.wantAsked, tc.provider.req)
+			}
+			if tc.wantAsked {
+				req := tc.provider.req
+				if len(req.SeedTrackIDs) != 2 || req.SeedTrackIDs[0] != "b" || req.Target.Energy != 0.5 || req.Limit != 5*recommendationHeadroom {
+					t.Fatalf("unexpected request %+v", *req)
+				}
+			}
+			if len(recs) != len(tc.wantIDs) {
+				t.Fatalf("expected %v, got %+v", tc.wantIDs, recs)
+			}
+			for i, id := range tc.wantIDs {
+				if recs[i].Track.ID != id {
+					t.Fatalf("expected %v, got %+v", tc.wantIDs, recs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithRecordings deduplicates tracks by fingerprinted recording in addition
+// to ID and ISRC. Only tracks whose previews have been fingerprinted can be
+// recognized.
+func WithRecordings(index ports.RecordingIndex) Option {
+	return func(o *Orchestrator) {
+		o.recordings = index
+	}
+}
+
+// dropKnownRecordings removes tracks that are the same recording as one in
+// existing or as an earlier track in tracks.
+func (o *Orchestrator) dropKnownRecordings(ctx context.Context, tracks []domain.Track, existing []string) ([]domain.Track, error) {
+	if o.recordings == nil || len(tracks) == 0 {
+		return tracks, nil
+	}
+	ids := append([]string{}, existing...)
+	for _, t := range tracks {
+		ids = append(ids, t.ID)
+	}
+	recordings, err := o.recordings.RecordingIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to look up recordings: %w", err)
+	}
+
+	seen := make(map[string]bool, len(recordings))
+	for _, id := range existing {
+		if rec, ok := recordings[id]; ok {
+			seen[rec] = true
+		}
+	}
+	kept := tracks[:0:0]
+	for _, t := range tracks {
+		rec, ok := recordings[t.ID]
+		if ok && seen[rec] {
+			continue
+		}
+		if ok {
+			seen[rec] = true
+		}
+		kept = append(kept, t)
+	}
+	return kept, nil
+}
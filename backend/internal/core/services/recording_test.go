@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type staticRecordings struct {
+	ids map[string]string
+	err error
+}
+
+func (s staticRecordings) RecordingIDs(ctx context.Context, trackIDs []string) (map[string]string, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	out := map[string]string{}
+	for _, id := range trackIDs {
+		if rec, ok := s.ids[id]; ok {
+			out[id] = rec
+		}
+	}
+	return out, nil
+}
+
+func TestOrchestrator_DropKnownRecordings(t *testing.T) {
+	tracks := []domain.Track{{ID: "remaster"}, {ID: "fresh"}, {ID: "live"}, {ID: "live-2"}, {ID: "unprinted"}}
+	recordings := map[string]string{
+		"original": "rec-a", "remaster": "rec-a",
+		"fresh": "rec-b",
+		"live":  "rec-c", "live-2": "rec-c",
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    []string
+		wantErr bool
+	}{
+		{name: "no index keeps every track", want: []string{"remaster", "fresh", "live", "live-2", "unprinted"}},
+		{
+			name: "drops recordings already present or repeated",
+			opts: []Option{WithRecordings(staticRecordings{ids: recordings})},
+			want: []string{"fresh", "live", "unprinted"},
+		},
+		{name: "lookup failure", opts: []Option{WithRecordings(staticRecordings{err: errors.New("db down")})}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, tc.opts...)
+			got, err := o.dropKnownRecordings(context.Background(), tracks, []string{"original"})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected err=%v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			ids := []string{}
+			for _, tr := range got {
+				ids = append(ids, tr.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, ids)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_AddTrackToPlaylist_DuplicateRecording(t *testing.T) {
+	spotify := &mockSpotify{track: domain.Track{ID: "remaster", ISRC: "NEW-ISRC"}}
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Name: "Mix", Tracks: []domain.Track{{ID: "original", ISRC: "OLD-ISRC"}}}}
+	index := staticRecordings{ids: map[string]string{"original": "rec-a", "remaster": "rec-a"}}
+	o := NewOrchestrator(spotify, repo, nil, WithRecordings(index))
+
+	_, _, _, err := o.AddTrackToPlaylist(context.Background(), "pl-1", "Song", "Artist")
+	if !errors.Is(err, domain.ErrDuplicateRecording) {
+		t.Fatalf("expected ErrDuplicateRecording, got %v", err)
+	}
+	if repo.saved != nil {
+		t.Fatal("expected playlist not to be saved")
+	}
+}
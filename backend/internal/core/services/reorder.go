@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// ReorderTracks rearranges a playlist's tracks in the order of trackIDs and
+// returns the reordered playlist. It returns domain.ErrNotFound for unknown
+// playlists and domain.ErrInvalidOrder unless trackIDs lists each of the
+// playlist's tracks exactly once. Playlists whose settings lock their
+// ordering return domain.ErrPlaylistLocked.
+func (o *Orchestrator) ReorderTracks(ctx context.Context, playlistID string, trackIDs []string) (domain.Playlist, error) {
+	if playlistID == "" {
+		return domain.Playlist{}, fmt.Errorf("service: playlist id cannot be empty")
+	}
+	settings, err := o.settingsFor(ctx, playlistID)
+	if err != nil {
+		return domain.Playlist{}, err
+	}
+	if settings.LockedOrdering {
+		return domain.Playlist{}, fmt.Errorf("service: %w", domain.ErrPlaylistLocked)
+	}
+
+	var playlist domain.Playlist
+	err = o.atomically(ctx, func(ctx context.Context, repo ports.PlaylistRepository) error {
+		var err error
+		playlist, err = repo.GetByID(ctx, playlistID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return err
+			}
+			return fmt.Errorf("service: failed to load playlist: %w", err)
+		}
+		if err := playlist.Reorder(trackIDs); err != nil {
+			return fmt.Errorf("service: %w", err)
+		}
+		if err := repo.ReorderTracks(ctx, playlistID, trackIDs); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return err
+			}
+			err = fmt.Errorf("service: failed to reorder tracks: %w", err)
+			o.report(ctx, err, map[string]string{"operation": "reorder_tracks", "playlist_id": playlistID})
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Playlist{}, err
+	}
+	return playlist, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// recordRun stores a completed intent run when recording is enabled. A
+// failure to record is reported but never fails the run itself.
+func (o *Orchestrator) recordRun(ctx context.Context, playlistID, message string, intent domain.IntentObject, candidates []domain.Track, existing []string, added []domain.Track, arm assignment) {
+	if o.runs == nil {
+		return
+	}
+	run := domain.IntentRun{
+		ID:         uuid.New().String(),
+		PlaylistID: playlistID,
+		Message:    message,
+		Intent:     intent,
+		Candidates: candidates,
+		Existing:   existing,
+		Added:      make([]string, len(added)),
+		Experiment: arm.experiment,
+		Variant:    arm.variant,
+		Scoring:    arm.scoring,
+		CreatedAt:  time.Now().UTC(),
+	}
+	for i, t := range added {
+		run.Added[i] = t.ID
+	}
+	if err := o.runs.SaveIntentRun(context.WithoutCancel(ctx), run); err != nil {
+		o.report(ctx, fmt.Errorf("service: failed to record intent run: %w", err), map[string]string{
+			"operation":   "record_intent_run",
+			"playlist_id": playlistID,
+		})
+	}
+}
+
+// HasIntentRuns returns true if intent runs are being recorded.
+func (o *Orchestrator) HasIntentRuns() bool {
+	return o.runs != nil
+}
+
+// ReplayIntentRuns re-executes up to limit recorded runs, newest first,
+// against the current baseline track selection logic (experiments aside)
+// using the recorded intent and candidates, and reports which tracks would
+// now be added or dropped. It never touches playlists or providers.
+func (o *Orchestrator) ReplayIntentRuns(ctx context.Context, limit int) (domain.ReplayReport, error) {
+	if o.runs == nil {
+		return domain.ReplayReport{}, fmt.Errorf("service: intent run recording not configured")
+	}
+	runs, err := o.runs.ListIntentRuns(ctx, limit)
+	if err != nil {
+		return domain.ReplayReport{}, fmt.Errorf("service: failed to load intent runs: %w", err)
+	}
+
+	report := domain.ReplayReport{Runs: len(runs), Results: make([]domain.ReplayResult, 0, len(runs))}
+	for _, run := range runs {
+		result := replayRun(run, o.baselineScoring())
+		if result.Changed() {
+			report.Changed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+func replayRun(run domain.IntentRun, scoring domain.ScoringConfig) domain.ReplayResult {
+	replayed := selectTracks(run.Candidates, run.Existing, run.Intent, scoring)
+
+	recorded := make(map[string]bool, len(run.Added))
+	for _, id := range run.Added {
+		recorded[id] = true
+	}
+	result := domain.ReplayResult{
+		RunID:      run.ID,
+		PlaylistID: run.PlaylistID,
+		Message:    run.Message,
+		Recorded:   len(run.Added),
+		Replayed:   len(replayed),
+		NewlyAdded: []string{},
+		NoLonger:   []string{},
+	}
+	selected := make(map[string]bool, len(replayed))
+	for _, t := range replayed {
+		selected[t.ID] = true
+		if !recorded[t.ID] {
+			result.NewlyAdded = append(result.NewlyAdded, t.ID)
+		}
+	}
+	for _, id := range run.Added {
+		if !selected[id] {
+			result.NoLonger = append(result.NoLonger, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+type memoryRuns struct {
+	runs []domain.IntentRun
+}
+
+func (m *memoryRuns) SaveIntentRun(ctx context.Context, run domain.IntentRun) error {
+	m.runs = append([]domain.IntentRun{run}, m.runs...)
+	return nil
+}
+
+func (m *memoryRuns) ListIntentRuns(ctx context.Context, limit int) ([]domain.IntentRun, error) {
+	if limit < len(m.runs) {
+		return m.runs[:limit], nil
+	}
+	return m.runs, nil
+}
+
+func TestOrchestrator_ReplayIntentRuns(t *testing.T) {
+	calm := domain.Track{ID: "calm", Features: domain.AudioFeatures{Energy: 0.2}}
+	loud := domain.Track{ID: "loud", Features: domain.AudioFeatures{Energy: 0.9}}
+	lowEnergy := domain.IntentObject{}
+	lowEnergy.VibeConstraints.Energy = &domain.VibeConstraint{Min: 0, Max: 0.5}
+
+	tests := []struct {
+		name         string
+		run          domain.IntentRun
+		wantChanged  int
+		wantNewly    []string
+		wantNoLonger []string
+		wantRecorded int
+		wantReplayed int
+	}{
+		{
+			name:         "unchanged selection",
+			run:          domain.IntentRun{ID: "r1", Intent: lowEnergy, Candidates: []domain.Track{calm, loud}, Added: []string{"calm"}},
+			wantNewly:    []string{},
+			wantNoLonger: []string{},
+			wantRecorded: 1,
+			wantReplayed: 1,
+		},
+		{
+			name:         "current logic filters differently",
+			run:          domain.IntentRun{ID: "r2", Intent: lowEnergy, Candidates: []domain.Track{calm, loud}, Added: []string{"loud"}},
+			wantChanged:  1,
+			wantNewly:    []string{"calm"},
+			wantNoLonger: []string{"loud"},
+			wantRecorded: 1,
+			wantReplayed: 1,
+		},
+		{
+			name:         "existing tracks stay excluded",
+			run:          domain.IntentRun{ID: "r3", Intent: lowEnergy, Candidates: []domain.Track{calm}, Existing: []string{"calm"}},
+			wantNewly:    []string{},
+			wantNoLonger: []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &memoryRuns{runs: []domain.IntentRun{tc.run}}
+			o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, nil, WithIntentRuns(store))
+
+			report, err := o.ReplayIntentRuns(context.Background(), 10)
+			if err != nil {
+				t.Fatalf("ReplayIntentRuns() error = %v", err)
+			}
+			if report.Runs != 1 || report.Changed != tc.wantChanged {
+				t.Fatalf("expected 1 run with %d changed, got %+v", tc.wantChanged, report)
+			}
+			got := report.Results[0]
+			if !reflect.DeepEqual(got.NewlyAdded, tc.wantNewly) {
+				t.Fatalf("NewlyAdded = %v, want %v", got.NewlyAdded, tc.wantNewly)
+			}
+			if !reflect.DeepEqual(got.NoLonger, tc.wantNoLonger) {
+				t.Fatalf("NoLonger = %v, want %v", got.NoLonger, tc.wantNoLonger)
+			}
+			if got.Recorded != tc.wantRecorded || got.Replayed != tc.wantReplayed {
+				t.Fatalf("counts = %d/%d, want %d/%d", got.Recorded, got.Replayed, tc.wantRecorded, tc.wantReplayed)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_ProcessIntent_RecordsRun(t *testing.T) {
+	store := &memoryRuns{}
+	spotify := &mockSpotify{track: domain.Track{ID: "t1", Title: "Song", Artist: "Artist"}}
+	compiler := &mockIntentCompiler{}
+	compiler.intent.Entities.Artists = []string{"Artist"}
+
+	o := NewOrchestrator(spotify, &mockRepo{}, compiler, WithIntentRuns(store))
+	if _, err := o.ProcessIntent(context.Background(), "pl-1", "more like this"); err != nil {
+		t.Fatalf("ProcessIntent() error = %v", err)
+	}
+
+	if len(store.runs) != 1 {
+		t.Fatalf("expected 1 recorded run, got %d", len(store.runs))
+	}
+	run := store.runs[0]
+	if run.PlaylistID != "pl-1" || run.Message != "more like this" || len(run.Candidates) != 1 || len(run.Added) != 1 || run.Added[0] != "t1" {
+		t.Fatalf("unexpected recorded run %+v", run)
+	}
+}
+
+func TestOrchestrator_ProcessIntent_Experiment(t *testing.T) {
+	compiler := &mockIntentCompiler{}
+	compiler.intent.Entities.Artists = []string{"Artist"}
+	compiler.intent.VibeConstraints.Energy = &domain.VibeConstraint{Target: 0.8, Min: 0.1, Max: 1}
+	spotify := &mockSpotify{track: domain.Track{ID: "t1", Features: domain.AudioFeatures{Energy: 0.3}}}
+
+	tests := []struct {
+		name      string
+		scoring   domain.ScoringConfig
+		wantAdded int
+	}{
+		{name: "loose variant keeps distant track", scoring: domain.ScoringConfig{}, wantAdded: 1},
+		{name: "strict variant drops distant track", scoring: domain.ScoringConfig{Threshold: 0.2}, wantAdded: 0},
+		{name: "weights scale the distance", scoring: domain.ScoringConfig{Threshold: 0.2, Weights: domain.FeatureWeights{Energy: 0.1}}, wantAdded: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &memoryRuns{}
+			exp := domain.Experiment{Name: "thresholds", Variants: []domain.Variant{{Name: "only", Weight: 1, Scoring: tc.scoring}}}
+			o := NewOrchestrator(spotify, &mockRepo{}, compiler, WithIntentRuns(store), WithExperiment(exp))
+
+			result, err := o.ProcessIntent(context.Background(), "pl-1", "upbeat")
+			if err != nil {
+				t.Fatalf("ProcessIntent() error = %v", err)
+			}
+			if result.TracksAdded != tc.wantAdded {
+				t.Fatalf("expected %d added, got %d", tc.wantAdded, result.TracksAdded)
+			}
+			run := store.runs[0]
+			if run.Experiment != "thresholds" || run.Variant != "only" || !reflect.DeepEqual(run.Scoring, tc.scoring) {
+				t.Fatalf("unexpected assignment on run %+v", run)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_ExperimentReport(t *testing.T) {
+	store := &memoryRuns{}
+	runs := []domain.IntentRun{
+		{ID: "r1", PlaylistID: "pl-1", Experiment: "x", Variant: "b", Candidates: []domain.Track{{ID: "t1"}, {ID: "t2"}}, Added: []string{"t1", "t2"}},
+		{ID: "r2", PlaylistID: "pl-1", Experiment: "x", Variant: "a", Candidates: []domain.Track{{ID: "t3"}}, Added: []string{"t3"}},
+		{ID: "r3", PlaylistID: "gone", Experiment: "x", Variant: "a", Candidates: []domain.Track{{ID: "t4"}}, Added: []string{"t4"}},
+	}
+	for _, run := range runs {
+		_ = store.SaveIntentRun(context.Background(), run)
+	}
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Tracks: []domain.Track{{ID: "t1"}, {ID: "t3"}}}}
+	o := NewOrchestrator(&mockSpotify{}, &playlistsByID{mockRepo: repo, ids: map[string]bool{"pl-1": true}}, nil, WithIntentRuns(store))
+
+	report, err := o.ExperimentReport(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("ExperimentReport() error = %v", err)
+	}
+	want := domain.ExperimentReport{Runs: 2, Variants: []domain.VariantReport{
+		{Experiment: "x", Variant: "a", Runs: 1, TracksEvaluated: 1, TracksAdded: 1, TracksKept: 1, AddRate: 1, AcceptanceRate: 1},
+		{Experiment: "x", Variant: "b", Runs: 1, TracksEvaluated: 2, TracksAdded: 2, TracksKept: 1, AddRate: 1, AcceptanceRate: 0.5},
+	}}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("expected %+v, got %+v", want, report)
+	}
+}
+
+// playlistsByID serves mockRepo's playlist only for known IDs.
+type playlistsByID struct {
+	*mockRepo
+	ids map[string]bool
+}
+
+func (p *playlistsByID) GetByID(ctx context.Context, id string) (domain.Playlist, error) {
+	if !p.ids[id] {
+		return domain.Playlist{}, domain.ErrNotFound
+	}
+	return p.mockRepo.GetByID(ctx, id)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithRevisions lets users list the revisions the repository records with
+// each change to a playlist and revert to one, undoing for example a bad
+// intent.
+func WithRevisions(store ports.RevisionStore) Option {
+	return func(o *Orchestrator) {
+		o.revisions = store
+	}
+}
+
+// HasRevisions returns true if playlist revisions are available.
+func (o *Orchestrator) HasRevisions() bool {
+	return o.revisions != nil
+}
+
+// ListRevisions returns up to limit of the playlist's revisions, newest
+// first. It returns domain.ErrNotFound for unknown playlists.
+func (o *Orchestrator) ListRevisions(ctx context.Context, playlistID string, limit int) ([]domain.PlaylistRevision, error) {
+	if !o.HasRevisions() {
+		return nil, fmt.Errorf("service: revisions not configured")
+	}
+	if _, err := o.repo.GetByID(ctx, playlistID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	revisions, err := o.revisions.ListRevisions(ctx, playlistID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// RevertPlaylist restores the playlist's tracks to revision rev and
+// returns the playlist as it is now. It returns domain.ErrNotFound for
+// unknown playlists and revisions.
+func (o *Orchestrator) RevertPlaylist(ctx context.Context, playlistID string, rev int) (domain.Playlist, error) {
+	if !o.HasRevisions() {
+		return domain.Playlist{}, fmt.Errorf("service: revisions not configured")
+	}
+	if _, err := o.revisions.RevertPlaylist(ctx, playlistID, rev); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Playlist{}, err
+		}
+		err = fmt.Errorf("service: failed to revert playlist: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "revert_playlist", "playlist_id": playlistID})
+		return domain.Playlist{}, err
+	}
+	playlist, err := o.repo.GetByID(ctx, playlistID)
+	if err != nil {
+		return domain.Playlist{}, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	return playlist, nil
+}
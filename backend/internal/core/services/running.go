@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// GenerateRunningPlaylist adds tracks by the template's artists to a
+// playlist so that its tempo follows the template's cadence plan. It returns
+// domain.ErrInvalidTemplate for bad parameters and domain.ErrNotFound for
+// unknown playlists.
+func (o *Orchestrator) GenerateRunningPlaylist(ctx context.Context, playlistID string, tmpl domain.RunningTemplate) (domain.RunningResult, error) {
+	if err := tmpl.Validate(); err != nil {
+		return domain.RunningResult{}, fmt.Errorf("service: %w", err)
+	}
+
+	// The playlist's settings pick the market and rule some tracks out, as
+	// in ProcessIntent.
+	settings, err := o.settingsFor(ctx, playlistID)
+	if err != nil {
+		return domain.RunningResult{}, err
+	}
+	// Provider calls happen outside the transaction, as in ProcessIntent.
+	candidates := settings.Filter(o.artistTopTracks(inMarket(ctx, settings), tmpl.Artists))
+
+	var result domain.RunningResult
+	err = o.atomically(ctx, func(ctx context.Context, repo ports.PlaylistRepository) error {
+		playlist, err := repo.GetByID(ctx, playlistID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return err
+			}
+			return fmt.Errorf("service: failed to load playlist: %w", err)
+		}
+
+		existing := make([]string, 0, len(playlist.Tracks))
+		inPlaylist := make(map[string]bool, len(playlist.Tracks))
+		for _, t := range playlist.Tracks {
+			existing = append(existing, t.ID)
+			inPlaylist[t.ID] = true
+		}
+		var fresh []domain.Track
+		for _, t := range candidates {
+			if !inPlaylist[t.ID] {
+				fresh = append(fresh, t)
+			}
+		}
+		fresh, err = o.dropKnownRecordings(ctx, fresh, existing)
+		if err != nil {
+			return err
+		}
+
+		tracks, placed, durationMs := sequenceCadence(fresh, tmpl)
+		// A playlist at its settings' MaxTracks takes only what fits, as
+		// with intents.
+		if room := settings.Room(tracks, len(playlist.Tracks)); len(room) < len(tracks) {
+			tracks, placed, durationMs = room, placed[:len(room)], 0
+			for _, t := range tracks {
+				durationMs += t.DurationMs
+			}
+		}
+		if len(tracks) > 0 {
+			if err := repo.AddTracksToPlaylist(ctx, playlistID, tracks); err != nil {
+				return fmt.Errorf("service: failed to add tracks to playlist: %w", err)
+			}
+		}
+		result = domain.RunningResult{
+			TracksEvaluated: len(candidates),
+			TracksAdded:     len(tracks),
+			DurationMs:      durationMs,
+			Tracks:          placed,
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			o.report(ctx, err, map[string]string{"operation": "generate_running", "playlist_id": playlistID})
+		}
+		return domain.RunningResult{}, err
+	}
+	return result, nil
+}
+
+// sequenceCadence lays tracks out along the workout: at each point it picks
+// the unused track whose cadence BPM is closest to the one wanted there,
+// within domain.CadenceTolerance. It stops once the workout is filled to
+// within domain.DefaultDurationTolerance, or when nothing fits the cadence.
+// Tracks without a tempo or length cannot be placed.
+func sequenceCadence(candidates []domain.Track, tmpl domain.RunningTemplate) ([]domain.Track, []domain.CadenceTrack, int) {
+	used := make([]bool, len(candidates))
+	var tracks []domain.Track
+	var placed []domain.CadenceTrack
+	elapsed := 0
+	for elapsed < tmpl.DurationMs-domain.DefaultDurationTolerance {
+		target := tmpl.TargetBPM(elapsed)
+		best, bestBPM, bestDist := -1, 0.0, math.Inf(1)
+		for i, t := range candidates {
+			if used[i] || t.Features.Tempo <= 0 || t.DurationMs <= 0 {
+				continue
+			}
+			if elapsed+t.DurationMs > tmpl.DurationMs+domain.DefaultDurationTolerance {
+				continue
+			}
+			bpm := domain.CadenceBPM(t.Features.Tempo, target)
+			dist := math.Abs(bpm - target)
+			if dist <= domain.CadenceTolerance*target && dist < bestDist {
+				best, bestBPM, bestDist = i, bpm, dist
+			}
+		}
+		if best < 0 {
+			break
+		}
+		t := candidates[best]
+		used[best] = true
+		tracks = append(tracks, t)
+		placed = append(placed, domain.CadenceTrack{
+			TrackID:    t.ID,
+			Title:      t.Title,
+			Artist:     t.Artist,
+			Phase:      tmpl.Phase(elapsed),
+			TargetBPM:  math.Round(target*10) / 10,
+			CadenceBPM: bestBPM,
+		})
+		elapsed += t.DurationMs
+	}
+	return tracks, placed, elapsed
+}
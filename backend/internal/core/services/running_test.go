@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestOrchestrator_GenerateRunningPlaylist(t *testing.T) {
+	tempos := []float64{120, 70, 141, 160, 160, 160, 160, 160, 160, 80, 300, 0}
+	var catalog []domain.Track
+	for i, tempo := range tempos {
+		catalog = append(catalog, domain.Track{
+			ID:         fmt.Sprintf("t%d", i),
+			DurationMs: 3 * minute,
+			Features:   domain.AudioFeatures{Tempo: tempo},
+		})
+	}
+	// 30 minutes at 160 SPM: six-minute ramps from 120.
+	tmpl := domain.RunningTemplate{Artists: []string{"Artist"}, TargetSPM: 160, DurationMs: 30 * minute}
+
+	repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}
+	o := NewOrchestrator(&catalogSpotify{tracks: catalog}, repo, nil)
+	result, err := o.GenerateRunningPlaylist(context.Background(), "pl-1", tmpl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TracksEvaluated != len(catalog) || result.TracksAdded != 10 || result.DurationMs != 30*minute {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	wantPhases := []string{
+		domain.PhaseWarmUp, domain.PhaseWarmUp,
+		domain.PhaseSteady, domain.PhaseSteady, domain.PhaseSteady, domain.PhaseSteady, domain.PhaseSteady, domain.PhaseSteady,
+		domain.PhaseCoolDown, domain.PhaseCoolDown,
+	}
+	for i, placed := range result.Tracks {
+		if placed.Phase != wantPhases[i] {
+			t.Errorf("track %d: expected phase %s, got %s", i, wantPhases[i], placed.Phase)
+		}
+	}
+	first, second, last := result.Tracks[0], result.Tracks[1], result.Tracks[9]
+	if first.TrackID != "t0" || first.TargetBPM != 120 {
+		t.Errorf("expected the warm-up to open with t0 at 120 BPM, got %+v", first)
+	}
+	// The 70 BPM track runs in double time.
+	if second.TrackID != "t1" || second.CadenceBPM != 140 {
+		t.Errorf("expected t1 at 140 cadence BPM, got %+v", second)
+	}
+	if last.TrackID != "t2" || last.TargetBPM != 140 {
+		t.Errorf("expected the cool-down to close with t2 at 140 BPM, got %+v", last)
+	}
+	for _, placed := range result.Tracks {
+		if placed.TrackID == "t10" || placed.TrackID == "t11" {
+			t.Errorf("expected off-cadence and tempo-less tracks left out, got %+v", placed)
+		}
+	}
+}
+
+func TestOrchestrator_GenerateRunningPlaylist_Errors(t *testing.T) {
+	valid := domain.RunningTemplate{Artists: []string{"Artist"}, TargetSPM: 170, DurationMs: 20 * minute}
+	tests := []struct {
+		name    string
+		repo    *mockRepo
+		tmpl    domain.RunningTemplate
+		wantErr error
+	}{
+		{name: "invalid template", repo: &mockRepo{}, tmpl: domain.RunningTemplate{TargetSPM: 170, DurationMs: minute}, wantErr: domain.ErrInvalidTemplate},
+		{name: "unknown playlist", repo: &mockRepo{getErr: domain.ErrNotFound}, tmpl: valid, wantErr: domain.ErrNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewOrchestrator(&catalogSpotify{}, tc.repo, nil)
+			if _, err := o.GenerateRunningPlaylist(context.Background(), "pl-1", tc.tmpl); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Small hand-picked lexicons; enough to tell a love song from a breakup song,
+// not to read irony.
+var (
+	positiveWords = wordSet("love", "happy", "joy", "smile", "sunshine", "sun", "dance", "dancing",
+		"laugh", "alive", "free", "beautiful", "bright", "good", "kiss", "heaven", "sweet",
+		"celebrate", "party", "shine", "hope", "glad", "fun", "together", "best", "golden",
+		"wonderful", "dream", "paradise", "light", "high", "yeah", "baby", "forever", "magic")
+	negativeWords = wordSet("sad", "cry", "crying", "tears", "pain", "hurt", "alone", "lonely",
+		"broken", "die", "dead", "death", "dark", "darkness", "cold", "lost", "hate", "fear",
+		"goodbye", "gone", "empty", "sorrow", "bleed", "grave", "blue", "rain", "war", "kill",
+		"lie", "lies", "wrong", "never", "nothing", "miss", "regret")
+	negators = wordSet("not", "no", "dont", "don't", "can't", "cant", "won't", "wont", "ain't", "aint", "isn't", "never")
+)
+
+func wordSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// lyricSentiment scores text from 0 (negative) to 1 (positive) by counting
+// lexicon hits, flipping a hit that directly follows a negator. Counts are
+// smoothed so a handful of hits does not reach either extreme, and text
+// without hits scores a neutral 0.5.
+func lyricSentiment(text string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	var pos, neg float64
+	for i, w := range words {
+		p, n := positiveWords[w], negativeWords[w]
+		if !p && !n {
+			continue
+		}
+		if i > 0 && negators[words[i-1]] {
+			p, n = n, p
+		}
+		if p {
+			pos++
+		} else {
+			neg++
+		}
+	}
+	return (pos + 1) / (pos + neg + 2)
+}
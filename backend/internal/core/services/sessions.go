@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithIntentSessions keeps the turns of conversational intent sessions in
+// store, so an intent with a domain.IntentOptions.SessionID refines the
+// session's earlier intents.
+func WithIntentSessions(store ports.IntentSessionStore) Option {
+	return func(o *Orchestrator) {
+		o.sessions = store
+	}
+}
+
+// HasIntentSessions returns true if intent sessions are available.
+func (o *Orchestrator) HasIntentSessions() bool {
+	return o.sessions != nil
+}
+
+// sessionHistory returns the recent turns of sessionID, which must be a
+// session on playlistID or a new one. Without a session or store, there is
+// no history.
+func (o *Orchestrator) sessionHistory(ctx context.Context, sessionID, playlistID string) ([]domain.IntentTurn, error) {
+	if sessionID == "" || o.sessions == nil {
+		return nil, nil
+	}
+	session, err := o.sessions.GetIntentSession(ctx, sessionID, domain.MaxSessionTurns)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to load intent session: %w", err)
+	}
+	if session.PlaylistID != playlistID {
+		return nil, fmt.Errorf("service: intent session %s: %w", sessionID, domain.ErrSessionPlaylist)
+	}
+	return session.Turns, nil
+}
+
+// recordTurn appends message and the intent it compiled to to sessionID.
+// The playlist has already changed by then, so a failure is logged rather
+// than returned; the next turn just sees less history.
+func (o *Orchestrator) recordTurn(ctx context.Context, sessionID, playlistID, message string, intent domain.IntentObject) {
+	if sessionID == "" || o.sessions == nil {
+		return
+	}
+	turn := domain.IntentTurn{Message: message, Intent: intent, CreatedAt: time.Now().UTC()}
+	if err := o.sessions.AppendIntentTurn(ctx, sessionID, playlistID, turn); err != nil {
+		o.logger.WarnContext(ctx, "failed to record intent turn", "session_id", sessionID, "playlist_id", playlistID, "error", err)
+	}
+}
+
+// historyPrompt folds history into message for compilers that cannot take
+// it as separate turns.
+func historyPrompt(history []domain.IntentTurn, message string) string {
+	var b strings.Builder
+	b.WriteString("Earlier requests in this conversation, oldest first, and the intents they produced:\n")
+	for _, turn := range history {
+		intent, err := json.Marshal(turn.Intent)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "Request: %s\nIntent: %s\n", turn.Message, intent)
+	}
+	b.WriteString("\nModify the latest intent according to this follow-up request rather than starting afresh: ")
+	b.WriteString(message)
+	return b.String()
+}
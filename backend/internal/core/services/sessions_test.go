@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// stubSessions keeps intent sessions in memory.
+type stubSessions struct {
+	sessions map[string]*domain.IntentSession
+}
+
+func (s *stubSessions) GetIntentSession(ctx context.Context, sessionID string, limit int) (domain.IntentSession, error) {
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return domain.IntentSession{}, domain.ErrNotFound
+	}
+	out := *session
+	out.Turns = out.Turns[max(0, len(out.Turns)-limit):]
+	return out, nil
+}
+
+func (s *stubSessions) AppendIntentTurn(ctx context.Context, sessionID, playlistID string, turn domain.IntentTurn) error {
+	if s.sessions == nil {
+		s.sessions = map[string]*domain.IntentSession{}
+	}
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		session = &domain.IntentSession{ID: sessionID, PlaylistID: playlistID}
+		s.sessions[sessionID] = session
+	}
+	session.Turns = append(session.Turns, turn)
+	return nil
+}
+
+// promptCompiler records the messages it compiles.
+type promptCompiler struct {
+	intent   domain.IntentObject
+	messages []string
+}
+
+func (c *promptCompiler) AnalyzeIntent(ctx context.Context, message string) (domain.IntentObject, error) {
+	c.messages = append(c.messages, message)
+	return c.intent, nil
+}
+
+// turnCompiler is a conversational compiler that records the history it
+// is given.
+type turnCompiler struct {
+	promptCompiler
+	histories [][]domain.IntentTurn
+}
+
+func (c *turnCompiler) AnalyzeIntentTurn(ctx context.Context, history []domain.IntentTurn, message string, onDelta func(domain.IntentDelta)) (domain.IntentObject, error) {
+	c.histories = append(c.histories, history)
+	c.messages = append(c.messages, message)
+	return c.intent, nil
+}
+
+func TestOrchestrator_IntentSessions(t *testing.T) {
+	ctx := context.Background()
+	intent := domain.IntentObject{IntentType: "vibe"}
+	intent.Entities.Genres = []string{"jazz"}
+	opts := domain.IntentOptions{SessionID: "s1"}
+
+	t.Run("Conversational compiler receives history", func(t *testing.T) {
+		store := &stubSessions{}
+		compiler := &turnCompiler{promptCompiler: promptCompiler{intent: intent}}
+		o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, compiler, WithIntentSessions(store))
+
+		first, err := o.ProcessIntentStream(ctx, "p1", "chill jazz", opts, nil)
+		if err != nil {
+			t.Fatalf("first turn: %v", err)
+		}
+		second, err := o.ProcessIntentStream(ctx, "p1", "make it more upbeat", opts, nil)
+		if err != nil {
+			t.Fatalf("second turn: %v", err)
+		}
+
+		if first.SessionHistory != 0 || second.SessionHistory != 1 || second.SessionID != "s1" {
+			t.Errorf("got session history %d then %d (%q), want 0 then 1 for s1", first.SessionHistory, second.SessionHistory, second.SessionID)
+		}
+		if len(compiler.histories) != 1 || len(compiler.histories[0]) != 1 {
+			t.Fatalf("expected only the follow-up to get history, got %+v", compiler.histories)
+		}
+		if turn := compiler.histories[0][0]; turn.Message != "chill jazz" || turn.Intent.Entities.Genres[0] != "jazz" {
+			t.Errorf("expected the first turn as history, got %+v", turn)
+		}
+		if got := len(store.sessions["s1"].Turns); got != 2 {
+			t.Errorf("expected both turns recorded, got %d", got)
+		}
+	})
+
+	t.Run("Other compilers get history in the prompt", func(t *testing.T) {
+		store := &stubSessions{}
+		compiler := &promptCompiler{intent: intent}
+		o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, compiler, WithIntentSessions(store))
+
+		for _, msg := range []string{"chill jazz", "make it more upbeat"} {
+			if _, err := o.ProcessIntentStream(ctx, "p1", msg, opts, nil); err != nil {
+				t.Fatalf("%s: %v", msg, err)
+			}
+		}
+		if compiler.messages[0] != "chill jazz" {
+			t.Errorf("expected the first turn unchanged, got %q", compiler.messages[0])
+		}
+		if got := compiler.messages[1]; !strings.Contains(got, "Request: chill jazz") || !strings.Contains(got, `"jazz"`) || !strings.HasSuffix(got, "make it more upbeat") {
+			t.Errorf("expected the follow-up to carry the earlier turn, got %q", got)
+		}
+	})
+
+	t.Run("Without a session nothing is recorded", func(t *testing.T) {
+		store := &stubSessions{}
+		o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, &promptCompiler{intent: intent}, WithIntentSessions(store))
+
+		if _, err := o.ProcessIntentStream(ctx, "p1", "chill jazz", domain.IntentOptions{}, nil); err != nil {
+			t.Fatalf("process: %v", err)
+		}
+		if len(store.sessions) != 0 {
+			t.Errorf("expected no sessions, got %+v", store.sessions)
+		}
+	})
+
+	t.Run("Session on another playlist", func(t *testing.T) {
+		store := &stubSessions{sessions: map[string]*domain.IntentSession{"s1": {ID: "s1", PlaylistID: "p2"}}}
+		compiler := &promptCompiler{intent: intent}
+		o := NewOrchestrator(&mockSpotify{}, &mockRepo{}, compiler, WithIntentSessions(store))
+
+		_, err := o.ProcessIntentStream(ctx, "p1", "chill jazz", opts, nil)
+		if !errors.Is(err, domain.ErrSessionPlaylist) {
+			t.Fatalf("expected ErrSessionPlaylist, got %v", err)
+		}
+		if len(compiler.messages) != 0 {
+			t.Error("expected the compiler not to be called")
+		}
+	})
+}
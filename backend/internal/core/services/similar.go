@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// SimilarPlaylists returns up to limit stored playlists closest to the
+// given one by average audio features and shared artists, best first. It
+// returns domain.ErrNotFound for unknown playlists.
+//
+// Every playlist is compared, which is fine for a single instance's
+// library; a vector index would take over for larger ones.
+func (o *Orchestrator) SimilarPlaylists(ctx context.Context, playlistID string, limit int) ([]domain.SimilarPlaylist, error) {
+	playlist, err := o.repo.GetByID(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service: failed to load playlist: %w", err)
+	}
+	candidates, err := o.repo.ListPlaylists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list playlists: %w", err)
+	}
+	return domain.SimilarPlaylists(playlist, candidates, limit), nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// libraryRepo serves several playlists by ID.
+type libraryRepo struct {
+	mockRepo
+	playlists []domain.Playlist
+	listErr   error
+}
+
+func (r *libraryRepo) GetByID(ctx context.Context, id string) (domain.Playlist, error) {
+	for _, p := range r.playlists {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return domain.Playlist{}, domain.ErrNotFound
+}
+
+func (r *libraryRepo) ListPlaylists(ctx context.Context) ([]domain.Playlist, error) {
+	return r.playlists, r.listErr
+}
+
+func TestOrchestrator_SimilarPlaylists(t *testing.T) {
+	calm := domain.AudioFeatures{Energy: 0.2, Acousticness: 0.9}
+	loud := domain.AudioFeatures{Energy: 0.9, Danceability: 0.8}
+	repo := &libraryRepo{playlists: []domain.Playlist{
+		{ID: "p", Tracks: []domain.Track{{Artist: "A", Features: calm}}},
+		{ID: "loud", Tracks: []domain.Track{{Artist: "B", Features: loud}}},
+		{ID: "twin", Tracks: []domain.Track{{Artist: "A", Features: calm}}},
+	}}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		id      string
+		listErr error
+		wantIDs []string
+		wantErr error
+	}{
+		{name: "closest first", id: "p", wantIDs: []string{"twin", "loud"}},
+		{name: "unknown playlist", id: "missing", wantErr: domain.ErrNotFound},
+		{name: "list fails", id: "p", listErr: errors.New("locked")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo.listErr = tc.listErr
+			o := NewOrchestrator(nil, repo, nil)
+
+			got, err := o.SimilarPlaylists(ctx, tc.id, 10)
+			if tc.wantErr != nil || tc.listErr != nil {
+				if err == nil || (tc.wantErr != nil && !errors.Is(err, tc.wantErr)) {
+					t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tc.wantIDs) {
+				t.Fatalf("expected %v, got %+v", tc.wantIDs, got)
+			}
+			for i, id := range tc.wantIDs {
+				if got[i].ID != id {
+					t.Fatalf("expected %v, got %+v", tc.wantIDs, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// spotifyAuth holds the Spotify accounts users connected and the clock
+// pending authorizations expire against.
+type spotifyAuth struct {
+	auth  ports.SpotifyAuthorizer
+	store ports.SpotifyAuthStore
+	now   func() time.Time
+}
+
+// WithSpotifyAuth lets users connect their Spotify account, after which
+// tracks added to their playlists are also added to a mirrored playlist in
+// their Spotify library.
+func WithSpotifyAuth(auth ports.SpotifyAuthorizer, store ports.SpotifyAuthStore) Option {
+	return func(o *Orchestrator) {
+		o.spotifyAuth = &spotifyAuth{auth: auth, store: store, now: time.Now}
+	}
+}
+
+// HasSpotifyAuth returns true if users can connect a Spotify account.
+func (o *Orchestrator) HasSpotifyAuth() bool {
+	return o.spotifyAuth != nil
+}
+
+// StartSpotifyLogin begins connecting owner's Spotify account and returns
+// the URL where they grant access.
+func (o *Orchestrator) StartSpotifyLogin(ctx context.Context, owner string) (string, error) {
+	if !o.HasSpotifyAuth() {
+		return "", fmt.Errorf("service: spotify auth not configured")
+	}
+	state := uuid.New().String()
+	authURL, verifier := o.spotifyAuth.auth.AuthCodeURL(state)
+	pending := domain.PendingAuth{
+		State:     state,
+		Owner:     owner,
+		Verifier:  verifier,
+		CreatedAt: o.spotifyAuth.now().UTC(),
+	}
+	if err := o.spotifyAuth.store.SavePendingAuth(ctx, pending); err != nil {
+		return "", fmt.Errorf("service: failed to save pending authorization: %w", err)
+	}
+	return authURL, nil
+}
+
+// CompleteSpotifyLogin exchanges the code Spotify returned with state for a
+// token and stores it for the owner who started the login. It returns
+// domain.ErrInvalidAuthState for unknown, used or expired states.
+func (o *Orchestrator) CompleteSpotifyLogin(ctx context.Context, state, code string) error {
+	if !o.HasSpotifyAuth() {
+		return fmt.Errorf("service: spotify auth not configured")
+	}
+	pending, err := o.spotifyAuth.store.TakePendingAuth(ctx, state)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrInvalidAuthState
+	}
+	if err != nil {
+		return fmt.Errorf("service: failed to load pending authorization: %w", err)
+	}
+	if pending.Expired(o.spotifyAuth.now()) {
+		return domain.ErrInvalidAuthState
+	}
+
+	token, err := o.spotifyAuth.auth.Exchange(ctx, code, pending.Verifier)
+	if err != nil {
+		return fmt.Errorf("service: failed to exchange spotify code: %w", err)
+	}
+	if err := o.spotifyAuth.store.SaveSpotifyToken(ctx, pending.Owner, token); err != nil {
+		return fmt.Errorf("service: failed to save spotify token: %w", err)
+	}
+	return nil
+}
+
+// mirrorToSpotify adds track to the Spotify playlist mirroring playlist in
+// owner's library, creating it with all of playlist's tracks the first
+// time. Owners without a connected account and tracks found in other
+// catalogs are skipped. The playlist is already saved, so failures are
+// reported rather than returned.
+func (o *Orchestrator) mirrorToSpotify(ctx context.Context, owner string, playlist domain.Playlist, track domain.Track) {
+	if !o.HasSpotifyAuth() || !track.OnSpotify() {
+		return
+	}
+	fields := map[string]string{"operation": "mirror_to_spotify", "playlist_id": playlist.ID, "track_id": track.ID}
+	library, err := o.spotifyLibrary(ctx, owner, fields)
+	if errors.Is(err, domain.ErrSpotifyNotConnected) {
+		return
+	}
+	if err != nil {
+		o.report(ctx, err, fields)
+		return
+	}
+
+	spotifyID, err := o.spotifyAuth.store.GetSpotifyPlaylistLink(ctx, playlist.ID)
+	tracks := []domain.Track{track}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		spotifyID, err = library.CreatePlaylist(ctx, playlist.Name)
+		if err != nil {
+			o.report(ctx, fmt.Errorf("service: failed to create spotify playlist: %w", err), fields)
+			return
+		}
+		if err := o.spotifyAuth.store.LinkSpotifyPlaylist(ctx, playlist.ID, spotifyID); err != nil {
+			o.report(ctx, fmt.Errorf("service: failed to link spotify playlist: %w", err), fields)
+			return
+		}
+		tracks = nil
+		for _, t := range playlist.Tracks {
+			if t.OnSpotify() {
+				tracks = append(tracks, t)
+			}
+		}
+	case err != nil:
+		o.report(ctx, fmt.Errorf("service: failed to load spotify playlist link: %w", err), fields)
+		return
+	}
+
+	if err := library.AddTracks(ctx, spotifyID, tracks); err != nil {
+		o.report(ctx, fmt.Errorf("service: failed to add tracks to spotify playlist: %w", err), fields)
+	}
+}
+
+// SyncPlaylistToSpotify pushes the tracks of the playlist to its mirror in
+// owner's Spotify library, in order, creating the mirror when there is none
+// or it was deleted on Spotify. Tracks found in other catalogs are skipped.
+// It returns domain.ErrSpotifyNotConnected when owner has not connected an
+// account and domain.ErrNotFound for unknown playlists. Once the mirror is
+// written to, batches Spotify rejects are reported per track rather than
+// as an error.
+func (o *Orchestrator) SyncPlaylistToSpotify(ctx context.Context, owner, playlistID string) (domain.PlaylistSync, error) {
+	if !o.HasSpotifyAuth() {
+		return domain.PlaylistSync{}, fmt.Errorf("service: spotify auth not configured")
+	}
+	playlist, err := o.repo.GetByID(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.PlaylistSync{}, err
+		}
+		return domain.PlaylistSync{}, fmt.Errorf("service: failed to get playlist: %w", err)
+	}
+	fields := map[string]string{"operation": "sync_to_spotify", "playlist_id": playlistID}
+	library, err := o.spotifyLibrary(ctx, owner, fields)
+	if err != nil {
+		return domain.PlaylistSync{}, err
+	}
+
+	result := domain.PlaylistSync{PlaylistID: playlistID}
+	var tracks []domain.Track
+	for _, t := range playlist.Tracks {
+		if t.OnSpotify() {
+			tracks = append(tracks, t)
+		}
+	}
+
+	result.SpotifyID, err = o.spotifyAuth.store.GetSpotifyPlaylistLink(ctx, playlistID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return domain.PlaylistSync{}, fmt.Errorf("service: failed to load spotify playlist link: %w", err)
+	}
+	var written int
+	var writeErr error
+	if err == nil {
+		result.SnapshotID, written, writeErr = library.ReplaceTracks(ctx, result.SpotifyID, tracks)
+	}
+	if err != nil || (written == 0 && errors.Is(writeErr, domain.ErrNotFound)) {
+		if result.SpotifyID, err = library.CreatePlaylist(ctx, playlist.Name); err != nil {
+			return domain.PlaylistSync{}, fmt.Errorf("service: failed to create spotify playlist: %w", err)
+		}
+		if err := o.spotifyAuth.store.LinkSpotifyPlaylist(ctx, playlistID, result.SpotifyID); err != nil {
+			return domain.PlaylistSync{}, fmt.Errorf("service: failed to link spotify playlist: %w", err)
+		}
+		result.Created = true
+		result.SnapshotID, written, writeErr = library.ReplaceTracks(ctx, result.SpotifyID, tracks)
+	}
+	if written == 0 && writeErr != nil {
+		return domain.PlaylistSync{}, fmt.Errorf("service: failed to write spotify playlist: %w", writeErr)
+	}
+	if writeErr != nil {
+		o.report(ctx, fmt.Errorf("service: failed to write spotify playlist: %w", writeErr), fields)
+	}
+
+	synced := 0
+	for _, t := range playlist.Tracks {
+		ts := domain.TrackSync{TrackID: t.ID, Status: domain.SyncSynced}
+		switch {
+		case !t.OnSpotify():
+			ts.Status = domain.SyncSkipped
+			result.Skipped++
+		case synced < written:
+			synced++
+			result.Synced++
+		default:
+			ts.Status = domain.SyncFailed
+			ts.Error = writeErr.Error()
+			result.Failed++
+		}
+		result.Tracks = append(result.Tracks, ts)
+	}
+
+	result.SyncedAt = o.spotifyAuth.now().UTC()
+	if err := o.spotifyAuth.store.RecordSpotifySync(ctx, playlistID, result.SnapshotID, result.SyncedAt); err != nil {
+		o.report(ctx, fmt.Errorf("service: failed to record spotify sync: %w", err), fields)
+	}
+	return result, nil
+}
+
+// spotifyLibrary returns the library of owner's connected Spotify account,
+// persisting tokens refreshed while using it. It returns
+// domain.ErrSpotifyNotConnected when owner has not connected one.
+func (o *Orchestrator) spotifyLibrary(ctx context.Context, owner string, fields map[string]string) (ports.SpotifyLibrary, error) {
+	token, err := o.spotifyAuth.store.GetSpotifyToken(ctx, owner)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrSpotifyNotConnected
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to load spotify token: %w", err)
+	}
+	return o.spotifyAuth.auth.Library(token, func(refreshed domain.OAuthToken) {
+		if err := o.spotifyAuth.store.SaveSpotifyToken(ctx, owner, refreshed); err != nil {
+			o.report(ctx, fmt.Errorf("service: failed to save refreshed spotify token: %w", err), fields)
+		}
+	}), nil
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// memSpotifyAuth is an in-memory ports.SpotifyAuthStore.
+type memSpotifyAuth struct {
+	pending map[string]domain.PendingAuth
+	tokens  map[string]domain.OAuthToken
+	links   map[string]string
+	synced  map[string]string
+}
+
+func newMemSpotifyAuth() *memSpotifyAuth {
+	return &memSpotifyAuth{pending: map[string]domain.PendingAuth{}, tokens: map[string]domain.OAuthToken{}, links: map[string]string{}}
+}
+
+func (m *memSpotifyAuth) SavePendingAuth(ctx context.Context, pending domain.PendingAuth) error {
+	m.pending[pending.State] = pending
+	return nil
+}
+
+func (m *memSpotifyAuth) TakePendingAuth(ctx context.Context, state string) (domain.PendingAuth, error) {
+	p, ok := m.pending[state]
+	if !ok {
+		return domain.PendingAuth{}, domain.ErrNotFound
+	}
+	delete(m.pending, state)
+	return p, nil
+}
+
+func (m *memSpotifyAuth) SaveSpotifyToken(ctx context.Context, owner string, token domain.OAuthToken) error {
+	m.tokens[owner] = token
+	return nil
+}
+
+func (m *memSpotifyAuth) GetSpotifyToken(ctx context.Context, owner string) (domain.OAuthToken, error) {
+	t, ok := m.tokens[owner]
+	if !ok {
+		return domain.OAuthToken{}, domain.ErrNotFound
+	}
+	return t, nil
+}
+
+func (m *memSpotifyAuth) LinkSpotifyPlaylist(ctx context.Context, playlistID, spotifyID string) error {
+	m.links[playlistID] = spotifyID
+	return nil
+}
+
+func (m *memSpotifyAuth) GetSpotifyPlaylistLink(ctx context.Context, playlistID string) (string, error) {
+	id, ok := m.links[playlistID]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	return id, nil
+}
+
+func (m *memSpotifyAuth) RecordSpotifySync(ctx context.Context, playlistID, snapshotID string, syncedAt time.Time) error {
+	if m.synced == nil {
+		m.synced = map[string]string{}
+	}
+	m.synced[playlistID] = snapshotID
+	return nil
+}
+
+// fakeAuthorizer hands out fixed verifiers and a library that records what
+// it is asked to write, refreshing the token on first use.
+type fakeAuthorizer struct {
+	exchangeErr error
+	addErr      error
+	// gone lists Spotify playlists deleted on Spotify; replaceErr fails
+	// every replace batch after the first.
+	gone       map[string]bool
+	replaceErr error
+
+	exchanged []string
+	created   []string
+	added     map[string][]string
+	replaced  map[string][]string
+}
+
+func (f *fakeAuthorizer) AuthCodeURL(state string) (string, string) {
+	return "https://accounts.example.com/authorize?state=" + state, "verifier-" + state
+}
+
+func (f *fakeAuthorizer) Exchange(ctx context.Context, code, verifier string) (domain.OAuthToken, error) {
+	if f.exchangeErr != nil {
+		return domain.OAuthToken{}, f.exchangeErr
+	}
+	f.exchanged = append(f.exchanged, code+"/"+verifier)
+	return domain.OAuthToken{AccessToken: "access-" + code, RefreshToken: "refresh"}, nil
+}
+
+func (f *fakeAuthorizer) Library(token domain.OAuthToken, onRefresh func(domain.OAuthToken)) ports.SpotifyLibrary {
+	return &fakeLibrary{f: f, token: token, onRefresh: onRefresh}
+}
+
+type fakeLibrary struct {
+	f         *fakeAuthorizer
+	token     domain.OAuthToken
+	onRefresh func(domain.OAuthToken)
+}
+
+func (l *fakeLibrary) refresh() {
+	if l.token.AccessToken != "refreshed" {
+		l.token.AccessToken = "refreshed"
+		l.onRefresh(l.token)
+	}
+}
+
+func (l *fakeLibrary) CreatePlaylist(ctx context.Context, name string) (string, error) {
+	l.refresh()
+	l.f.created = append(l.f.created, name)
+	return "sp-" + name, nil
+}
+
+func (l *fakeLibrary) AddTracks(ctx context.Context, spotifyPlaylistID string, tracks []domain.Track) error {
+	l.refresh()
+	if l.f.addErr != nil {
+		return l.f.addErr
+	}
+	if l.f.added == nil {
+		l.f.added = map[string][]string{}
+	}
+	for _, t := range tracks {
+		l.f.added[spotifyPlaylistID] = append(l.f.added[spotifyPlaylistID], t.ID)
+	}
+	return nil
+}
+
+func (l *fakeLibrary) ReplaceTracks(ctx context.Context, spotifyPlaylistID string, tracks []domain.Track) (string, int, error) {
+	l.refresh()
+	if l.f.gone[spotifyPlaylistID] {
+		return "", 0, domain.ErrNotFound
+	}
+	written := len(tracks)
+	if l.f.replaceErr != nil {
+		written = min(written, 1)
+	}
+	if l.f.replaced == nil {
+		l.f.replaced = map[string][]string{}
+	}
+	l.f.replaced[spotifyPlaylistID] = nil
+	for _, t := range tracks[:written] {
+		l.f.replaced[spotifyPlaylistID] = append(l.f.replaced[spotifyPlaylistID], t.ID)
+	}
+	if written < len(tracks) {
+		return "snap-partial", written, l.f.replaceErr
+	}
+	return "snap-" + spotifyPlaylistID, written, nil
+}
+
+func TestOrchestrator_SpotifyLogin(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemSpotifyAuth()
+	auth := &fakeAuthorizer{}
+	o := NewOrchestrator(nil, &mockRepo{}, nil, WithSpotifyAuth(auth, store))
+	o.spotifyAuth.now = func() time.Time { return now }
+
+	authURL, err := o.StartSpotifyLogin(ctx, "alice")
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+	if len(store.pending) != 1 {
+		t.Fatalf("expected one pending authorization, got %d", len(store.pending))
+	}
+	var pending domain.PendingAuth
+	for _, p := range store.pending {
+		pending = p
+	}
+	if authURL != "https://accounts.example.com/authorize?state="+pending.State || pending.Owner != "alice" || pending.Verifier != "verifier-"+pending.State {
+		t.Fatalf("unexpected login %q with %+v", authURL, pending)
+	}
+
+	if err := o.CompleteSpotifyLogin(ctx, "unknown", "code"); !errors.Is(err, domain.ErrInvalidAuthState) {
+		t.Fatalf("expected ErrInvalidAuthState for an unknown state, got %v", err)
+	}
+	if err := o.CompleteSpotifyLogin(ctx, pending.State, "code"); err != nil {
+		t.Fatalf("complete login: %v", err)
+	}
+	if got := store.tokens["alice"]; got.AccessToken != "access-code" {
+		t.Fatalf("expected alice's token to be saved, got %+v", got)
+	}
+	if len(auth.exchanged) != 1 || auth.exchanged[0] != "code/verifier-"+pending.State {
+		t.Fatalf("expected the code exchanged with the login's verifier, got %v", auth.exchanged)
+	}
+	if err := o.CompleteSpotifyLogin(ctx, pending.State, "code"); !errors.Is(err, domain.ErrInvalidAuthState) {
+		t.Fatalf("expected a state to complete once, got %v", err)
+	}
+
+	// Logins left pending past the TTL can no longer complete.
+	if _, err := o.StartSpotifyLogin(ctx, "bob"); err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+	var state string
+	for s := range store.pending {
+		state = s
+	}
+	o.spotifyAuth.now = func() time.Time { return now.Add(domain.AuthStateTTL + time.Second) }
+	if err := o.CompleteSpotifyLogin(ctx, state, "code"); !errors.Is(err, domain.ErrInvalidAuthState) {
+		t.Fatalf("expected ErrInvalidAuthState for an expired state, got %v", err)
+	}
+
+	unconfigured := NewOrchestrator(nil, &mockRepo{}, nil)
+	if _, err := unconfigured.StartSpotifyLogin(ctx, "alice"); err == nil {
+		t.Fatal("expected an error without spotify auth")
+	}
+}
+
+func TestOrchestrator_AddTrackMirrorsToSpotify(t *testing.T) {
+	ctx := context.Background()
+	existing := domain.Track{ID: "t1", Title: "One", Artist: "A"}
+	added := domain.Track{ID: "t2", Title: "Two", Artist: "B"}
+
+	tests := []struct {
+		name        string
+		connected   bool
+		linked      bool
+		addErr      error
+		wantCreated []string
+		wantAdded   []string
+		wantReports int
+	}{
+		{name: "not connected", connected: false},
+		{name: "first add creates the mirror with every track", connected: true, wantCreated: []string{"Road Trip"}, wantAdded: []string{"t1", "t2"}},
+		{name: "linked playlist gets the new track", connected: true, linked: true, wantAdded: []string{"t2"}},
+		{name: "spotify failure is reported, not returned", connected: true, linked: true, addErr: errors.New("spotify down"), wantReports: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newMemSpotifyAuth()
+			if tc.connected {
+				store.tokens[domain.DefaultOwner] = domain.OAuthToken{AccessToken: "access", RefreshToken: "refresh"}
+			}
+			if tc.linked {
+				store.links["pl-1"] = "sp-Road Trip"
+			}
+			auth := &fakeAuthorizer{addErr: tc.addErr}
+			reporter := &fakeReporter{}
+			repo := &mockRepo{playlist: domain.Playlist{ID: "pl-1", Name: "Road Trip", Tracks: []domain.Track{existing}}}
+			o := NewOrchestrator(&mockSpotify{track: added}, repo, nil, WithSpotifyAuth(auth, store), WithErrorReporter(reporter))
+
+			if _, _, _, err := o.AddTrackToPlaylist(ctx, "pl-1", added.Title, added.Artist); err != nil {
+				t.Fatalf("add track: %v", err)
+			}
+			if len(auth.created) != len(tc.wantCreated) || (len(tc.wantCreated) > 0 && auth.created[0] != tc.wantCreated[0]) {
+				t.Fatalf("created: got %v, want %v", auth.created, tc.wantCreated)
+			}
+			got := auth.added["sp-Road Trip"]
+			if len(got) != len(tc.wantAdded) {
+				t.Fatalf("added: got %v, want %v", got, tc.wantAdded)
+			}
+			for i := range got {
+				if got[i] != tc.wantAdded[i] {
+					t.Fatalf("added: got %v, want %v", got, tc.wantAdded)
+				}
+			}
+			if len(reporter.errs) != tc.wantReports {
+				t.Fatalf("reports: got %v, want %d", reporter.errs, tc.wantReports)
+			}
+			if tc.connected {
+				if store.links["pl-1"] != "sp-Road Trip" {
+					t.Fatalf("expected the mirror to be linked, got %q", store.links["pl-1"])
+				}
+				if store.tokens[domain.DefaultOwner].AccessToken != "refreshed" {
+					t.Fatalf("expected the refreshed token to be saved, got %+v", store.tokens[domain.DefaultOwner])
+				}
+			}
+		})
+	}
+}
+
+func TestOrchestrator_SyncPlaylistToSpotify(t *testing.T) {
+	ctx := context.Background()
+	playlist := domain.Playlist{ID: "pl-1", Name: "Road Trip", Tracks: []domain.Track{
+		{ID: "t1"}, {ID: "applemusic:1", Source: domain.SourceAppleMusic}, {ID: "t2"}, {ID: "t3"},
+	}}
+
+	tests := []struct {
+		name         string
+		connected    bool
+		link         string
+		gone         bool
+		replaceErr   error
+		wantErr      error
+		wantSpotify  string
+		wantCreated  bool
+		wantStatuses []string
+		wantSnapshot string
+		wantReports  int
+	}{
+		{name: "not connected", wantErr: domain.ErrSpotifyNotConnected},
+		{
+			name: "creates the mirror", connected: true,
+			wantSpotify: "sp-Road Trip", wantCreated: true, wantSnapshot: "snap-sp-Road Trip",
+			wantStatuses: []string{domain.SyncSynced, domain.SyncSkipped, domain.SyncSynced, domain.SyncSynced},
+		},
+		{
+			name: "replaces the linked playlist", connected: true, link: "sp-old",
+			wantSpotify: "sp-old", wantSnapshot: "snap-sp-old",
+			wantStatuses: []string{domain.SyncSynced, domain.SyncSkipped, domain.SyncSynced, domain.SyncSynced},
+		},
+		{
+			name: "recreates a mirror deleted on spotify", connected: true, link: "sp-old", gone: true,
+			wantSpotify: "sp-Road Trip", wantCreated: true, wantSnapshot: "snap-sp-Road Trip",
+			wantStatuses: []string{domain.SyncSynced, domain.SyncSkipped, domain.SyncSynced, domain.SyncSynced},
+		},
+		{
+			name: "reports tracks of rejected batches", connected: true, link: "sp-old", replaceErr: errors.New("spotify down"),
+			wantSpotify: "sp-old", wantSnapshot: "snap-partial", wantReports: 1,
+			wantStatuses: []string{domain.SyncSynced, domain.SyncSkipped, domain.SyncFailed, domain.SyncFailed},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newMemSpotifyAuth()
+			if tc.connected {
+				store.tokens[domain.DefaultOwner] = domain.OAuthToken{AccessToken: "access", RefreshToken: "refresh"}
+			}
+			if tc.link != "" {
+				store.links["pl-1"] = tc.link
+			}
+			auth := &fakeAuthorizer{gone: map[string]bool{"sp-old": tc.gone}, replaceErr: tc.replaceErr}
+			reporter := &fakeReporter{}
+			o := NewOrchestrator(nil, &mockRepo{playlist: playlist}, nil, WithSpotifyAuth(auth, store), WithErrorReporter(reporter))
+
+			result, err := o.SyncPlaylistToSpotify(ctx, domain.DefaultOwner, "pl-1")
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sync: %v", err)
+			}
+			if result.SpotifyID != tc.wantSpotify || result.Created != tc.wantCreated || result.SnapshotID != tc.wantSnapshot {
+				t.Fatalf("unexpected result %+v", result)
+			}
+			if len(result.Tracks) != len(tc.wantStatuses) {
+				t.Fatalf("expected %d track results, got %+v", len(tc.wantStatuses), result.Tracks)
+			}
+			for i, ts := range result.Tracks {
+				if ts.TrackID != playlist.Tracks[i].ID || ts.Status != tc.wantStatuses[i] {
+					t.Fatalf("track %d: got %+v, want %s", i, ts, tc.wantStatuses[i])
+				}
+			}
+			if store.links["pl-1"] != tc.wantSpotify || store.synced["pl-1"] != tc.wantSnapshot {
+				t.Fatalf("expected link %q at %q, got %q at %q", tc.wantSpotify, tc.wantSnapshot, store.links["pl-1"], store.synced["pl-1"])
+			}
+			if len(reporter.errs) != tc.wantReports {
+				t.Fatalf("reports: got %v, want %d", reporter.errs, tc.wantReports)
+			}
+		})
+	}
+
+	unconfigured := NewOrchestrator(nil, &mockRepo{playlist: playlist}, nil)
+	if _, err := unconfigured.SyncPlaylistToSpotify(ctx, domain.DefaultOwner, "pl-1"); err == nil {
+		t.Fatal("expected an error without spotify auth")
+	}
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ewilliams-labs/overture/backend/internal/core/services")
+
+// startSpan starts a span for an operation on playlistID. Without a
+// configured tracer provider it only carries the caller's trace context.
+func startSpan(ctx context.Context, name, playlistID string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attribute.String("overture.playlist_id", playlistID)))
+}
+
+// endSpan marks span as failed with err, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
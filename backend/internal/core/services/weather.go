@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// WithWeather biases the vibe constraints an intent leaves open towards the
+// current weather at the user's location, for users who enabled it in their
+// settings.
+func WithWeather(provider ports.WeatherProvider, settings ports.WeatherSettingsStore) Option {
+	return func(o *Orchestrator) {
+		o.weather = provider
+		o.weatherSettings = settings
+	}
+}
+
+// HasWeather returns true if weather-aware generation is available.
+func (o *Orchestrator) HasWeather() bool {
+	return o.weather != nil && o.weatherSettings != nil
+}
+
+// GetWeatherSettings returns owner's weather settings, which are disabled
+// until saved.
+func (o *Orchestrator) GetWeatherSettings(ctx context.Context, owner string) (domain.WeatherSettings, error) {
+	if !o.HasWeather() {
+		return domain.WeatherSettings{}, fmt.Errorf("service: weather not configured")
+	}
+	settings, err := o.weatherSettings.GetWeatherSettings(ctx, owner)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.WeatherSettings{Owner: owner}, nil
+	}
+	if err != nil {
+		return domain.WeatherSettings{}, fmt.Errorf("service: failed to load weather settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateWeatherSettings replaces the settings of settings.Owner. It returns
+// domain.ErrInvalidSettings for out-of-range locations.
+func (o *Orchestrator) UpdateWeatherSettings(ctx context.Context, settings domain.WeatherSettings) (domain.WeatherSettings, error) {
+	if !o.HasWeather() {
+		return domain.WeatherSettings{}, fmt.Errorf("service: weather not configured")
+	}
+	if err := settings.Validate(); err != nil {
+		return domain.WeatherSettings{}, fmt.Errorf("service: %w", err)
+	}
+	settings.UpdatedAt = time.Now().UTC()
+	if err := o.weatherSettings.SaveWeatherSettings(ctx, settings); err != nil {
+		err = fmt.Errorf("service: failed to save weather settings: %w", err)
+		o.report(ctx, err, map[string]string{"operation": "update_weather_settings", "owner": settings.Owner})
+		return domain.WeatherSettings{}, err
+	}
+	return settings, nil
+}
+
+// applyWeather fills in intent from the current weather when the user has
+// enabled it. It returns the condition applied, or "" when nothing was
+// filled in. Weather is a nicety: lookup failures are reported and ignored.
+func (o *Orchestrator) applyWeather(ctx context.Context, intent *domain.IntentObject) string {
+	if !o.HasWeather() {
+		return ""
+	}
+	settings, err := o.weatherSettings.GetWeatherSettings(ctx, domain.DefaultOwner)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			o.report(ctx, fmt.Errorf("service: failed to load weather settings: %w", err), map[string]string{"operation": "apply_weather"})
+		}
+		return ""
+	}
+	if !settings.Enabled {
+		return ""
+	}
+	weather, err := o.weather.CurrentWeather(ctx, settings.Latitude, settings.Longitude)
+	if err != nil {
+		o.report(ctx, fmt.Errorf("service: failed to fetch weather: %w", err), map[string]string{"operation": "apply_weather"})
+		return ""
+	}
+	if !weather.Apply(intent) {
+		return ""
+	}
+	return weather.Condition
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// stubWeather reports the same conditions everywhere.
+type stubWeather struct {
+	weather domain.Weather
+	err     error
+	calls   int
+}
+
+func (s *stubWeather) CurrentWeather(ctx context.Context, latitude, longitude float64) (domain.Weather, error) {
+	s.calls++
+	return s.weather, s.err
+}
+
+// memWeatherSettings is an in-memory ports.WeatherSettingsStore.
+type memWeatherSettings map[string]domain.WeatherSettings
+
+func (m memWeatherSettings) GetWeatherSettings(ctx context.Context, owner string) (domain.WeatherSettings, error) {
+	s, ok := m[owner]
+	if !ok {
+		return domain.WeatherSettings{}, domain.ErrNotFound
+	}
+	return s, nil
+}
+
+func (m memWeatherSettings) SaveWeatherSettings(ctx context.Context, settings domain.WeatherSettings) error {
+	m[settings.Owner] = settings
+	return nil
+}
+
+func TestOrchestrator_ProcessIntent_Weather(t *testing.T) {
+	seattle := domain.WeatherSettings{Owner: domain.DefaultOwner, Enabled: true, Latitude: 47.6, Longitude: -122.3}
+	rain := domain.Weather{Condition: domain.WeatherRain}
+
+	tests := []struct {
+		name        string
+		settings    memWeatherSettings
+		provider    *stubWeather
+		wantWeather string
+		wantCalls   int
+		wantReports int
+	}{
+		{name: "rainy", settings: memWeatherSettings{domain.DefaultOwner: seattle}, provider: &stubWeather{weather: rain}, wantWeather: domain.WeatherRain, wantCalls: 1},
+		{name: "not set up", settings: memWeatherSettings{}, provider: &stubWeather{weather: rain}},
+		{name: "turned off", settings: memWeatherSettings{domain.DefaultOwner: {Owner: domain.DefaultOwner}}, provider: &stubWeather{weather: rain}},
+		{name: "provider down", settings: memWeatherSettings{domain.DefaultOwner: seattle}, provider: &stubWeather{err: errors.New("timeout")}, wantCalls: 1, wantReports: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			intent := domain.IntentObject{}
+			intent.Entities.Artists = []string{"Artist"}
+			reporter := &fakeReporter{}
+			o := NewOrchestrator(&catalogSpotify{}, &mockRepo{playlist: domain.Playlist{ID: "pl-1"}}, &mockIntentCompiler{intent: intent},
+				WithWeather(tc.provider, tc.settings), WithErrorReporter(reporter))
+
+			result, err := o.ProcessIntent(context.Background(), "pl-1", "something for now")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Weather != tc.wantWeather || tc.provider.calls != tc.wantCalls || len(reporter.errs) != tc.wantReports {
+				t.Fatalf("expected weather %q after %d calls and %d reports, got %q after %d and %d",
+					tc.wantWeather, tc.wantCalls, tc.wantReports, result.Weather, tc.provider.calls, len(reporter.errs))
+			}
+			if (result.Intent.VibeConstraints.Acoustic != nil) != (tc.wantWeather != "") {
+				t.Fatalf("unexpected acousticness constraint %+v", result.Intent.VibeConstraints.Acoustic)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_WeatherSettings(t *testing.T) {
+	ctx := context.Background()
+	o := NewOrchestrator(nil, &mockRepo{}, nil, WithWeather(&stubWeather{}, memWeatherSettings{}))
+
+	settings, err := o.GetWeatherSettings(ctx, domain.DefaultOwner)
+	if err != nil || settings.Enabled || settings.Owner != domain.DefaultOwner {
+		t.Fatalf("expected disabled defaults, got %+v (%v)", settings, err)
+	}
+	if _, err := o.UpdateWeatherSettings(ctx, domain.WeatherSettings{Owner: domain.DefaultOwner, Enabled: true}); !errors.Is(err, domain.ErrInvalidSettings) {
+		t.Fatalf("expected ErrInvalidSettings without a location, got %v", err)
+	}
+	saved, err := o.UpdateWeatherSettings(ctx, domain.WeatherSettings{Owner: domain.DefaultOwner, Enabled: true, Latitude: 51.5, Longitude: -0.13})
+	if err != nil || saved.UpdatedAt.IsZero() {
+		t.Fatalf("unexpected result %+v (%v)", saved, err)
+	}
+	if settings, _ = o.GetWeatherSettings(ctx, domain.DefaultOwner); settings != saved {
+		t.Fatalf("expected %+v, got %+v", saved, settings)
+	}
+
+	if _, err := NewOrchestrator(nil, &mockRepo{}, nil).GetWeatherSettings(ctx, domain.DefaultOwner); err == nil {
+		t.Fatal("expected error without weather configured")
+	}
+}
@@ -0,0 +1,225 @@
+// Package fingerprint computes compact acoustic fingerprints of audio clips
+// and compares them, so the same recording can be recognized under
+// different track IDs or ISRCs.
+//
+// The scheme follows Haitsma and Kalker, as popularized by Chromaprint: the
+// audio is reduced to mono at about 5.5 kHz, split into overlapping frames,
+// and each frame yields a 32-bit sub-fingerprint whose bits record whether
+// the energy difference between adjacent frequency bands rose or fell since
+// the previous frame. Re-encoding, resampling and volume changes flip few
+// bits; different recordings agree on about half of them.
+package fingerprint
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"math/cmplx"
+	"sort"
+)
+
+const (
+	// targetRate is the sample rate audio is reduced to before analysis.
+	targetRate = 5512
+	frameSize  = 2048
+	hopSize    = 128
+	bands      = 33
+	minFreq    = 300.0
+	maxFreq    = 2000.0
+
+	// minOverlap is the fewest aligned sub-fingerprints (about 1.5s) worth
+	// comparing.
+	minOverlap = 64
+	// maxOffsetCandidates bounds how many alignments Similarity scores.
+	maxOffsetCandidates = 3
+)
+
+// DefaultThreshold is the Similarity at or above which two fingerprints are
+// treated as the same recording. Unrelated audio scores around 0.5.
+const DefaultThreshold = 0.75
+
+// Compute fingerprints interleaved 16-bit PCM with the given channel count
+// and sample rate. It returns nil when the clip is too short to analyze.
+func Compute(pcm []int16, channels, sampleRate int) []uint32 {
+	if channels < 1 || sampleRate < targetRate {
+		return nil
+	}
+	mono := downsample(pcm, channels, sampleRate)
+	if len(mono) < frameSize+hopSize {
+		return nil
+	}
+
+	window := make([]float64, frameSize)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(frameSize-1))
+	}
+	edges := bandEdges()
+	buf := make([]complex128, frameSize)
+
+	var prev []float64
+	var fp []uint32
+	for start := 0; start+frameSize <= len(mono); start += hopSize {
+		for i := 0; i < frameSize; i++ {
+			buf[i] = complex(mono[start+i]*window[i], 0)
+		}
+		fft(buf)
+		energy := make([]float64, bands)
+		for b := 0; b < bands; b++ {
+			for k := edges[b]; k < edges[b+1]; k++ {
+				m := cmplx.Abs(buf[k])
+				energy[b] += m * m
+			}
+		}
+		if prev != nil {
+			var sub uint32
+			for m := 0; m < bands-1; m++ {
+				if (energy[m]-energy[m+1])-(prev[m]-prev[m+1]) > 0 {
+					sub |= 1 << uint(m)
+				}
+			}
+			fp = append(fp, sub)
+		}
+		prev = energy
+	}
+	return fp
+}
+
+// downsample mixes pcm to mono and box-filters it down to about targetRate.
+func downsample(pcm []int16, channels, sampleRate int) []float64 {
+	frames := len(pcm) / channels
+	step := float64(sampleRate) / targetRate
+	out := make([]float64, 0, int(float64(frames)/step)+1)
+	for pos := 0.0; int(pos+step) <= frames; pos += step {
+		from, to := int(pos), int(pos+step)
+		var sum float64
+		for f := from; f < to; f++ {
+			for c := 0; c < channels; c++ {
+				sum += float64(pcm[f*channels+c])
+			}
+		}
+		out = append(out, sum/float64((to-from)*channels)/32768)
+	}
+	return out
+}
+
+// bandEdges returns FFT bin boundaries for bands log-spaced between minFreq
+// and maxFreq.
+func bandEdges() []int {
+	edges := make([]int, bands+1)
+	ratio := math.Pow(maxFreq/minFreq, 1/float64(bands))
+	for b := 0; b <= bands; b++ {
+		freq := minFreq * math.Pow(ratio, float64(b))
+		edges[b] = int(math.Round(freq * frameSize / targetRate))
+	}
+	return edges
+}
+
+// fft is an in-place iterative radix-2 FFT; len(x) must be a power of two.
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				u, v := x[start+k], x[start+k+size/2]*w
+				x[start+k], x[start+k+size/2] = u+v, u-v
+				w *= step
+			}
+		}
+	}
+}
+
+// Similarity scores how alike two fingerprints are, from 0 to 1, as the
+// fraction of matching bits at their best alignment. Clips may start at
+// different points: candidate alignments come from sub-fingerprints that
+// match exactly. It returns 0 when no alignment overlaps enough to judge.
+func Similarity(a, b []uint32) float64 {
+	if len(a) < minOverlap || len(b) < minOverlap {
+		return 0
+	}
+	positions := make(map[uint32][]int, len(b))
+	for j, v := range b {
+		positions[v] = append(positions[v], j)
+	}
+	votes := map[int]int{}
+	for i, v := range a {
+		for _, j := range positions[v] {
+			votes[j-i]++
+		}
+	}
+
+	offsets := make([]int, 0, len(votes))
+	for off := range votes {
+		offsets = append(offsets, off)
+	}
+	sort.Slice(offsets, func(i, j int) bool {
+		if votes[offsets[i]] != votes[offsets[j]] {
+			return votes[offsets[i]] > votes[offsets[j]]
+		}
+		return offsets[i] < offsets[j]
+	})
+	if len(offsets) > maxOffsetCandidates {
+		offsets = offsets[:maxOffsetCandidates]
+	}
+
+	best := 0.0
+	for _, off := range offsets {
+		if s := alignedSimilarity(a, b, off); s > best {
+			best = s
+		}
+	}
+	return best
+}
+
+// alignedSimilarity compares a[i] with b[i+off] over their overlap.
+func alignedSimilarity(a, b []uint32, off int) float64 {
+	start := 0
+	if off < 0 {
+		start = -off
+	}
+	end := len(a)
+	if len(b)-off < end {
+		end = len(b) - off
+	}
+	if end-start < minOverlap {
+		return 0
+	}
+	diff := 0
+	for i := start; i < end; i++ {
+		diff += bits.OnesCount32(a[i] ^ b[i+off])
+	}
+	return 1 - float64(diff)/float64(32*(end-start))
+}
+
+// Encode serializes a fingerprint for storage.
+func Encode(fp []uint32) []byte {
+	out := make([]byte, 4*len(fp))
+	for i, v := range fp {
+		binary.LittleEndian.PutUint32(out[4*i:], v)
+	}
+	return out
+}
+
+// Decode reverses Encode.
+func Decode(data []byte) ([]uint32, error) {
+	if len(data)%4 != 0 {
+		return nil, errors.New("fingerprint: truncated data")
+	}
+	fp := make([]uint32, len(data)/4)
+	for i := range fp {
+		fp[i] = binary.LittleEndian.Uint32(data[4*i:])
+	}
+	return fp, nil
+}
@@ -0,0 +1,94 @@
+package fingerprint
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// melody renders a stereo clip of shifting tones and noise, reproducible for
+// a given seed.
+func melody(seed int64, seconds float64, rate int) []int16 {
+	rng := rand.New(rand.NewSource(seed))
+	n := int(seconds * float64(rate))
+	pcm := make([]int16, 2*n)
+	var tones [3]float64
+	for i := 0; i < n; i++ {
+		if i%(rate/4) == 0 {
+			for k := range tones {
+				tones[k] = 300 + rng.Float64()*1700
+			}
+		}
+		t := float64(i) / float64(rate)
+		v := 0.0
+		for _, f := range tones {
+			v += math.Sin(2 * math.Pi * f * t)
+		}
+		v = v/4 + (rng.Float64()-0.5)*0.05
+		s := int16(v * 20000)
+		pcm[2*i], pcm[2*i+1] = s, s
+	}
+	return pcm
+}
+
+func TestSimilarity(t *testing.T) {
+	const rate = 44100
+	original := melody(1, 12, rate)
+	base := Compute(original, 2, rate)
+	if len(base) < minOverlap {
+		t.Fatalf("expected a fingerprint, got %d sub-fingerprints", len(base))
+	}
+
+	quieter := make([]int16, len(original))
+	rng := rand.New(rand.NewSource(9))
+	for i, s := range original {
+		quieter[i] = int16(float64(s)*0.6 + rng.NormFloat64()*200)
+	}
+	// Start three seconds in, as a different preview of the same recording.
+	offset := 2 * 3 * rate
+
+	tests := []struct {
+		name    string
+		pcm     []int16
+		rate    int
+		wantMin float64
+		wantMax float64
+	}{
+		{name: "identical", pcm: original, rate: rate, wantMin: 0.999, wantMax: 1},
+		{name: "gain and noise", pcm: quieter, rate: rate, wantMin: DefaultThreshold, wantMax: 1},
+		{name: "different start", pcm: original[offset:], rate: rate, wantMin: DefaultThreshold, wantMax: 1},
+		{name: "different recording", pcm: melody(2, 12, rate), rate: rate, wantMin: 0, wantMax: DefaultThreshold - 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Similarity(base, Compute(tt.pcm, 2, tt.rate))
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Fatalf("expected similarity in [%.2f, %.2f], got %.3f", tt.wantMin, tt.wantMax, got)
+			}
+		})
+	}
+}
+
+func TestCompute_TooShort(t *testing.T) {
+	if fp := Compute(make([]int16, 2*1000), 2, 44100); fp != nil {
+		t.Fatalf("expected nil fingerprint for a short clip, got %d values", len(fp))
+	}
+	if Similarity(nil, []uint32{1, 2, 3}) != 0 {
+		t.Fatal("expected zero similarity without enough overlap")
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	fp := []uint32{0, 1, 0xdeadbeef, math.MaxUint32}
+	got, err := Decode(Encode(fp))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, fp) {
+		t.Fatalf("expected %v, got %v", fp, got)
+	}
+	if _, err := Decode([]byte{1, 2, 3}); err == nil {
+		t.Fatal("expected error for truncated data")
+	}
+}
@@ -0,0 +1,85 @@
+// Package flags gates experimental backend behavior behind named feature
+// flags. Flags are fixed at startup from configuration so risky code paths
+// can ship dark and be enabled per deployment.
+package flags
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+var descriptions = map[string]string{
+	domain.FlagTargetScoring: "Rank intent candidates by distance to vibe targets",
+}
+
+// State describes one flag for inspection.
+type State struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// Set holds the enabled state of every known flag. A nil *Set reports every
+// flag as disabled, so callers need no configuration to stay on the stable
+// path.
+type Set struct {
+	enabled map[string]bool
+}
+
+// New returns a Set with the given flags enabled. Unknown names are an
+// error so a typo cannot silently leave a flag off.
+func New(enabled map[string]bool) (*Set, error) {
+	s := &Set{enabled: make(map[string]bool, len(descriptions))}
+	for name, on := range enabled {
+		if _, ok := descriptions[name]; !ok {
+			return nil, fmt.Errorf("flags: unknown flag %q", name)
+		}
+		s.enabled[name] = on
+	}
+	return s, nil
+}
+
+// Parse reads a comma-separated flag list such as
+// "target_scoring, other_flag=false". A bare name enables the flag.
+func Parse(spec string) (map[string]bool, error) {
+	out := map[string]bool{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, raw, hasValue := strings.Cut(entry, "=")
+		on := true
+		if hasValue {
+			v, err := strconv.ParseBool(strings.TrimSpace(raw))
+			if err != nil {
+				return nil, fmt.Errorf("flags: invalid value for %q: %q", name, raw)
+			}
+			on = v
+		}
+		out[strings.TrimSpace(name)] = on
+	}
+	return out, nil
+}
+
+// Enabled implements ports.FeatureFlags.
+func (s *Set) Enabled(name string) bool {
+	if s == nil {
+		return false
+	}
+	return s.enabled[name]
+}
+
+// States lists every known flag sorted by name.
+func (s *Set) States() []State {
+	states := make([]State, 0, len(descriptions))
+	for name, desc := range descriptions {
+		states = append(states, State{Name: name, Description: desc, Enabled: s.Enabled(name)})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
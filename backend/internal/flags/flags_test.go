@@ -0,0 +1,66 @@
+package flags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{name: "Empty", spec: "", want: map[string]bool{}},
+		{name: "Bare names", spec: "target_scoring, other_flag", want: map[string]bool{"target_scoring": true, "other_flag": true}},
+		{name: "Explicit values", spec: "target_scoring=false,other_flag=1", want: map[string]bool{"target_scoring": false, "other_flag": true}},
+		{name: "Invalid value", spec: "target_scoring=maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected err=%v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	if _, err := New(map[string]bool{"warp_drive": true}); err == nil {
+		t.Fatal("expected error for unknown flag")
+	}
+
+	s, err := New(map[string]bool{"target_scoring": true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !s.Enabled(domain.FlagTargetScoring) {
+		t.Fatalf("unexpected states: %+v", s.States())
+	}
+
+	var unset *Set
+	if unset.Enabled(domain.FlagTargetScoring) {
+		t.Fatal("expected nil set to disable every flag")
+	}
+
+	states := s.States()
+	if len(states) != 1 || states[0].Name != domain.FlagTargetScoring || !states[0].Enabled {
+		t.Fatalf("unexpected states: %+v", states)
+	}
+
+	off, err := New(map[string]bool{"target_scoring": false})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if off.Enabled(domain.FlagTargetScoring) {
+		t.Fatal("expected an explicit false to disable the flag")
+	}
+}
@@ -0,0 +1,9 @@
+// Package golden holds end-to-end tests for the intent pipeline. Each case in
+// testdata replays a recorded LLM reply and recorded Spotify responses
+// through the real adapters and Orchestrator, and compares the outcome with
+// a checked-in golden file. No network access is needed.
+//
+// Regenerate golden files after an intended behavior change with:
+//
+//	go test ./internal/golden -update
+package golden
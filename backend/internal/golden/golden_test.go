@@ -0,0 +1,208 @@
+package golden
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/spotify"
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/sqlite"
+	"github.com/ewilliams-labs/overture/backend/internal/app"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// fixture is the recorded input of one case (testdata/<case>/input.json).
+type fixture struct {
+	Message string `json:"message"`
+	// LLMResponse is the raw message content the intent compiler returned.
+	LLMResponse    string         `json:"llm_response"`
+	ExistingTracks []domain.Track `json:"existing_tracks"`
+	Spotify        struct {
+		Artists []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			// TopTracks are raw Spotify track objects.
+			TopTracks []json.RawMessage `json:"top_tracks"`
+		} `json:"artists"`
+		AudioFeatures []json.RawMessage `json:"audio_features"`
+	} `json:"spotify"`
+}
+
+// outcome is what a case locks down (testdata/<case>/golden.json).
+type outcome struct {
+	Intent          domain.IntentObject `json:"intent"`
+	TracksEvaluated int                 `json:"tracks_evaluated"`
+	TracksAdded     int                 `json:"tracks_added"`
+	Summary         string              `json:"summary"`
+	Playlist        []string            `json:"playlist"`
+}
+
+func TestIntentPipeline(t *testing.T) {
+	cases, err := filepath.Glob(filepath.Join("testdata", "*", "input.json"))
+	if err != nil {
+		t.Fatalf("glob fixtures: %v", err)
+	}
+	if len(cases) == 0 {
+		t.Fatal("no golden cases found in testdata")
+	}
+
+	for _, input := range cases {
+		dir := filepath.Dir(input)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			var fx fixture
+			readJSON(t, input, &fx)
+
+			got := run(t, fx)
+			gotJSON, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatalf("encode outcome: %v", err)
+			}
+			gotJSON = append(gotJSON, '\n')
+
+			goldenPath := filepath.Join(dir, "golden.json")
+			if *update {
+				if err := os.WriteFile(goldenPath, gotJSON, 0o600); err != nil {
+					t.Fatalf("write golden: %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(goldenPath) // #nosec G304 -- test fixture path
+			if err != nil {
+				t.Fatalf("read golden (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(want, gotJSON) {
+				t.Fatalf("outcome differs from %s (run with -update if intended)\n--- want\n%s\n--- got\n%s", goldenPath, want, gotJSON)
+			}
+		})
+	}
+}
+
+// run drives message -> IntentObject -> candidates -> playlist through the
+// real adapters, backed by servers replaying the fixture.
+func run(t *testing.T, fx fixture) outcome {
+	t.Helper()
+	ctx := context.Background()
+
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"message": map[string]string{"role": "assistant", "content": fx.LLMResponse}})
+	}))
+	defer llm.Close()
+	api := httptest.NewServer(spotifyReplay(fx))
+	defer api.Close()
+
+	repo, err := sqlite.NewAdapter(filepath.Join(t.TempDir(), "golden.db"))
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	defer repo.Close()
+
+	cfg := app.DefaultConfig()
+	cfg.OllamaHost = llm.URL
+	cfg.Blob.Dir = t.TempDir()
+	application, err := app.New(cfg,
+		app.WithStore(repo),
+		app.WithSpotify(spotify.NewClientWithBaseURL(api.Client(), api.URL)),
+	)
+	if err != nil {
+		t.Fatalf("new app: %v", err)
+	}
+	defer application.Close()
+	svc := application.Service
+
+	playlist, err := svc.CreatePlaylist(ctx, "Golden")
+	if err != nil {
+		t.Fatalf("create playlist: %v", err)
+	}
+	if len(fx.ExistingTracks) > 0 {
+		if err := repo.AddTracksToPlaylist(ctx, playlist.ID, fx.ExistingTracks); err != nil {
+			t.Fatalf("seed playlist: %v", err)
+		}
+	}
+
+	result, err := svc.ProcessIntent(ctx, playlist.ID, fx.Message)
+	if err != nil {
+		t.Fatalf("ProcessIntent: %v", err)
+	}
+	final, err := svc.GetPlaylist(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("get playlist: %v", err)
+	}
+
+	out := outcome{
+		Intent:          result.Intent,
+		TracksEvaluated: result.TracksEvaluated,
+		TracksAdded:     result.TracksAdded,
+		Summary:         result.Summary,
+		Playlist:        []string{},
+	}
+	for _, tr := range final.Tracks {
+		out.Playlist = append(out.Playlist, tr.ID+" "+tr.Artist+" - "+tr.Title)
+	}
+	return out
+}
+
+// spotifyReplay serves the Spotify endpoints used by GetArtistTopTracks
+// from the fixture. Unknown artists yield an empty search result.
+func spotifyReplay(fx fixture) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		items := []map[string]string{}
+		for _, a := range fx.Spotify.Artists {
+			if strings.EqualFold(a.Name, r.URL.Query().Get("q")) {
+				items = append(items, map[string]string{"id": a.ID, "name": a.Name})
+			}
+		}
+		writeJSON(w, map[string]any{"artists": map[string]any{"items": items}})
+	})
+	mux.HandleFunc("GET /artists/{id}/top-tracks", func(w http.ResponseWriter, r *http.Request) {
+		for _, a := range fx.Spotify.Artists {
+			if a.ID == r.PathValue("id") {
+				writeJSON(w, map[string]any{"tracks": a.TopTracks})
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("GET /audio-features", func(w http.ResponseWriter, r *http.Request) {
+		wanted := map[string]bool{}
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			wanted[id] = true
+		}
+		features := []json.RawMessage{}
+		for _, raw := range fx.Spotify.AudioFeatures {
+			var f struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(raw, &f); err == nil && wanted[f.ID] {
+				features = append(features, raw)
+			}
+		}
+		writeJSON(w, map[string]any{"audio_features": features})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func readJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(path) // #nosec G304 -- test fixture path
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+}
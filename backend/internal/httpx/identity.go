@@ -0,0 +1,68 @@
+package httpx
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Identity is how outbound requests identify Overture to providers, so our
+// traffic can be found in their dashboards and support requests.
+type Identity struct {
+	// Service names the application, e.g. "overture".
+	Service string
+	// Version is the build version, e.g. "1.4.0" or "dev".
+	Version string
+	// Instance identifies the process sending the request.
+	Instance string
+	// Headers are added to every request that does not set them itself.
+	Headers http.Header
+}
+
+// UserAgent renders the identity as a User-Agent, e.g.
+// "overture/1.4.0 (+https://github.com/ewilliams-labs/overture; instance=api-1)".
+func (id Identity) UserAgent() string {
+	var b strings.Builder
+	b.WriteString(id.Service)
+	if id.Version != "" {
+		b.WriteString("/" + id.Version)
+	}
+	b.WriteString(" (+https://github.com/ewilliams-labs/overture")
+	if id.Instance != "" {
+		b.WriteString("; instance=" + id.Instance)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+var identity atomic.Pointer[Identity]
+
+func init() {
+	identity.Store(&Identity{Service: "overture", Version: "dev"})
+}
+
+// SetIdentity sets how every Transport identifies outbound requests. It is
+// configured once at startup, as clients are built throughout the adapters.
+func SetIdentity(id Identity) {
+	if id.Service == "" {
+		id.Service = "overture"
+	}
+	id.Headers = id.Headers.Clone()
+	identity.Store(&id)
+}
+
+// identify returns a copy of req carrying the User-Agent and custom headers,
+// leaving the ones req already sets.
+func identify(req *http.Request) *http.Request {
+	id := identity.Load()
+	req = req.Clone(req.Context())
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", id.UserAgent())
+	}
+	for name, values := range id.Headers {
+		if _, ok := req.Header[name]; !ok {
+			req.Header[name] = append([]string(nil), values...)
+		}
+	}
+	return req
+}
@@ -0,0 +1,83 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIdentity_UserAgent(t *testing.T) {
+	tests := []struct {
+		name string
+		id   Identity
+		want string
+	}{
+		{
+			name: "full",
+			id:   Identity{Service: "overture", Version: "1.4.0", Instance: "api-1"},
+			want: "overture/1.4.0 (+https://github.com/ewilliams-labs/overture; instance=api-1)",
+		},
+		{
+			name: "no version or instance",
+			id:   Identity{Service: "overture"},
+			want: "overture (+https://github.com/ewilliams-labs/overture)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.UserAgent(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransport_Identifies(t *testing.T) {
+	defer SetIdentity(*identity.Load())
+	SetIdentity(Identity{
+		Version:  "1.4.0",
+		Instance: "api-1",
+		Headers:  http.Header{"X-Partner-Id": {"abc123"}, "X-Team": {"music"}},
+	})
+
+	var got []http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Clone())
+		if len(got) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	req.Header.Set("X-Team", "own")
+	resp, err := NewClient("test", 5*time.Second, Policy{MaxAttempts: 2, BaseBackoff: time.Millisecond}).Do(req)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(got) != 2 {
+		t.Fatalf("attempts: got %d, want 2", len(got))
+	}
+	for i, h := range got {
+		if ua := h.Get("User-Agent"); ua != "overture/1.4.0 (+https://github.com/ewilliams-labs/overture; instance=api-1)" {
+			t.Errorf("attempt %d: User-Agent %q", i+1, ua)
+		}
+		if h.Get("X-Partner-Id") != "abc123" {
+			t.Errorf("attempt %d: X-Partner-Id %q, want abc123", i+1, h.Get("X-Partner-Id"))
+		}
+		if h.Get("X-Team") != "own" {
+			t.Errorf("attempt %d: X-Team %q, want the request's own value", i+1, h.Get("X-Team"))
+		}
+	}
+	if req.Header.Get("User-Agent") != "" || req.Header.Get("X-Partner-Id") != "" {
+		t.Error("the caller's request was modified")
+	}
+}
@@ -0,0 +1,17 @@
+package httpx
+
+import "github.com/ewilliams-labs/overture/metrics"
+
+var (
+	requestDuration = metrics.NewHistogramVec(
+		"overture_http_client_request_duration_seconds",
+		"Latency of outbound HTTP requests, retries included, by client, method, host, path template and status.",
+		metrics.DefaultBuckets,
+		"client", "method", "host", "path", "code",
+	)
+	retries = metrics.NewCounterVec(
+		"overture_http_retries_total",
+		"Outbound HTTP attempts that were retried, by client and cause (rate_limited, server_error, network).",
+		"client", "cause",
+	)
+)
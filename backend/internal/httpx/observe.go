@@ -0,0 +1,81 @@
+package httpx
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// observe logs and measures a finished request.
+func (t *Transport) observe(req *http.Request, resp *http.Response, err error, retried int, elapsed time.Duration) {
+	template := t.PathTemplate
+	if template == nil {
+		template = TemplatePath
+	}
+	path := template(req.URL.Path)
+	code := statusCode(resp)
+
+	requestDuration.Observe(elapsed.Seconds(), t.Name, req.Method, req.URL.Host, path, code)
+
+	attrs := []any{"method", req.Method, "host", req.URL.Host, "path", path, "status", code,
+		"duration_ms", elapsed.Milliseconds(), "retries", retried}
+	level := slog.LevelDebug
+	switch {
+	case err != nil:
+		level = slog.LevelWarn
+		attrs = append(attrs, "error", redact(err))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		level = slog.LevelWarn
+	}
+	t.logger().Log(req.Context(), level, "request finished", attrs...)
+}
+
+// TemplatePath replaces the path segments that look like identifiers with
+// {id}, e.g. /v1/tracks/{id}/lyrics. A segment is an identifier when it is
+// a number or is longer than a version tag such as v1 and contains a digit.
+func TemplatePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if isIdentifier(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isIdentifier(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if strings.IndexFunc(segment, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+		return true
+	}
+	return len(segment) > 3 && strings.IndexFunc(segment, unicode.IsDigit) >= 0
+}
+
+// redact drops the URL that net/http puts in request errors, as its query
+// may carry search terms or credentials.
+func redact(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Op + ": " + urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// statusCode labels a request's outcome by HTTP status, or "error" when no
+// response was received.
+func statusCode(resp *http.Response) string {
+	if resp == nil {
+		return "error"
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
@@ -0,0 +1,88 @@
+package httpx
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+)
+
+func TestTemplatePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/api/chat", want: "/api/chat"},
+		{path: "/api/get/123", want: "/api/get/{id}"},
+		{path: "/v1/tracks/4uLU6hMCjMI75M1A2tKUQC", want: "/v1/tracks/{id}"},
+		{path: "/bucket/snapshots/2026-01-02T03-04-05.json", want: "/bucket/snapshots/{id}"},
+		{path: "/private-abc123/basic.ics", want: "/{id}/basic.ics"},
+		{path: "", want: "/"},
+	}
+	for _, tt := range tests {
+		if got := TemplatePath(tt.path); got != tt.want {
+			t.Errorf("TemplatePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	err := &url.Error{Op: "Get", URL: "https://api.example.com/search?q=secret&token=abc", Err: errors.New("connection refused")}
+	got := redact(err)
+	if strings.Contains(got, "secret") || strings.Contains(got, "token") {
+		t.Fatalf("redact leaked the URL: %q", got)
+	}
+	if got != "Get: connection refused" {
+		t.Fatalf("redact = %q, want %q", got, "Get: connection refused")
+	}
+}
+
+func TestTransport_Observe(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.Config{Level: "debug"})
+	if err != nil {
+		t.Fatalf("logger: %v", err)
+	}
+
+	host := strings.TrimPrefix(ts.URL, "http://")
+	before := requestDuration.Count("observed", http.MethodPost, host, "/v1/tracks/{id}", "200")
+
+	client := NewClient("observed", 5*time.Second, NoRetry())
+	client.Transport.(*Transport).Logger = logger
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/v1/tracks/4uLU6hMCjMI75M1A2tKUQC?q=secret", strings.NewReader("private body"))
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer token-value")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := requestDuration.Count("observed", http.MethodPost, host, "/v1/tracks/{id}", "200") - before; got != 1 {
+		t.Errorf("duration observations: got %d, want 1", got)
+	}
+	line := buf.String()
+	for _, want := range []string{"client=observed", "method=POST", "host=" + host, "path=/v1/tracks/{id}", "status=200", "retries=0"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log %q is missing %q", line, want)
+		}
+	}
+	for _, leak := range []string{"secret", "private body", "token-value", "4uLU6hMCjMI75M1A2tKUQC"} {
+		if strings.Contains(line, leak) {
+			t.Errorf("log %q leaks %q", line, leak)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package httpx
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// traced wraps base so every attempt is a client span, e.g.
+// "spotify GET /v1/tracks/{id}", and carries the trace context in its
+// headers. Retries show up as sibling spans.
+func (t *Transport) traced(base http.RoundTripper) http.RoundTripper {
+	template := t.PathTemplate
+	if template == nil {
+		template = TemplatePath
+	}
+	return otelhttp.NewTransport(base, otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return t.Name + " " + r.Method + " " + template(r.URL.Path)
+	}))
+}
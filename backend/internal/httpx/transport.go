@@ -0,0 +1,243 @@
+// Package httpx provides the middleware shared by the outbound HTTP clients
+// of the adapters.
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+)
+
+// Policy controls how a Transport retries failed requests.
+type Policy struct {
+	// MaxAttempts is how many times a request is sent at most, retries
+	// included; 1 disables retries.
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry, doubled for each
+	// one after. A Retry-After header replaces it.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay. A longer Retry-After ends the retries
+	// instead of waiting. Zero means no cap.
+	MaxBackoff time.Duration
+	// Retryable decides whether an attempt should be retried; nil means
+	// Retryable.
+	Retryable func(resp *http.Response, err error) bool
+}
+
+// DefaultPolicy suits provider APIs answering within seconds.
+func DefaultPolicy() Policy {
+	return Policy{MaxAttempts: 3, BaseBackoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second}
+}
+
+// NoRetry sends every request once.
+func NoRetry() Policy {
+	return Policy{MaxAttempts: 1}
+}
+
+// Retryable retries network errors, 429 Too Many Requests and 5xx
+// responses.
+func Retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// Hooks observe and gate the attempts of a Transport. Every hook is
+// optional.
+type Hooks struct {
+	// Wait runs before every attempt, e.g. to take a rate limit token;
+	// an error ends the request with it.
+	Wait func(ctx context.Context) error
+	// Attempt is called after every attempt with its response or error.
+	Attempt func(req *http.Request, resp *http.Response, err error, elapsed time.Duration)
+	// Retry is called before an attempt is retried with the response
+	// being discarded (nil after a network error), why and after how long.
+	Retry func(req *http.Request, resp *http.Response, cause string, delay time.Duration)
+}
+
+// Transport is an http.RoundTripper that retries failed requests with
+// exponential backoff, honoring Retry-After. Requests whose body cannot be
+// replayed (no GetBody) are sent once. After the last attempt the final
+// response is returned as is, so callers see the status that ended it.
+//
+// Requests carry the User-Agent and headers of the identity set with
+// SetIdentity. Every request is logged and measured once, retries included, by method,
+// host, path template, status, duration and retry count. Queries, bodies
+// and headers are never recorded, as they carry search terms and tokens.
+type Transport struct {
+	// Base sends each attempt; nil means http.DefaultTransport.
+	Base http.RoundTripper
+	// Name identifies the client in logs and metrics, e.g. "spotify".
+	Name string
+	// PathTemplate reduces a request path to a low-cardinality label;
+	// nil means TemplatePath.
+	PathTemplate func(path string) string
+	Policy       Policy
+	Hooks        Hooks
+	// Logger receives the request and retry logs; nil means the default
+	// logger.
+	Logger *slog.Logger
+}
+
+// logger returns the logger for t's requests, tagged with its name.
+func (t *Transport) logger() *slog.Logger {
+	l := t.Logger
+	if l == nil {
+		l = logging.Component(nil, "http")
+	}
+	return l.With("client", t.Name)
+}
+
+// NewClient returns a client named name whose requests time out after
+// timeout, retries included, and are retried per policy.
+func NewClient(name string, timeout time.Duration, policy Policy) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: &Transport{Name: name, Policy: policy}}
+}
+
+// Wrap makes client retry per policy, keeping its transport for sending
+// the attempts, and returns the Transport for setting hooks.
+func Wrap(client *http.Client, name string, policy Policy) *Transport {
+	t := &Transport{Base: client.Transport, Name: name, Policy: policy}
+	client.Transport = t
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, retried, err := t.roundTrip(req)
+	t.observe(req, resp, err, retried, time.Since(start))
+	return resp, err
+}
+
+// roundTrip sends req until it succeeds or the policy gives up, returning
+// how many times it was retried.
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, int, error) {
+	req = identify(req)
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	base = t.traced(base)
+	attempts := t.Policy.MaxAttempts
+	if attempts < 1 || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		attempts = 1
+	}
+	retryable := t.Policy.Retryable
+	if retryable == nil {
+		retryable = Retryable
+	}
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		if t.Hooks.Wait != nil {
+			if err := t.Hooks.Wait(ctx); err != nil {
+				return nil, attempt - 1, err
+			}
+		}
+		send := req
+		if attempt > 1 {
+			send = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, attempt - 1, fmt.Errorf("httpx: reset request body: %w", err)
+				}
+				send.Body = body
+			}
+		}
+
+		start := time.Now()
+		resp, err := base.RoundTrip(send)
+		if t.Hooks.Attempt != nil {
+			t.Hooks.Attempt(req, resp, err, time.Since(start))
+		}
+		if attempt >= attempts || !retryable(resp, err) {
+			return resp, attempt - 1, err
+		}
+
+		delay := t.Policy.BaseBackoff << (attempt - 1)
+		capped := t.Policy.MaxBackoff > 0
+		if after := RetryAfter(resp); after > 0 {
+			if capped && after > t.Policy.MaxBackoff {
+				// The server wants more time than we are willing to wait.
+				return resp, attempt - 1, err
+			}
+			delay = after
+		} else if capped && delay > t.Policy.MaxBackoff {
+			delay = t.Policy.MaxBackoff
+		}
+
+		cause := RetryCause(resp, err)
+		retries.Inc(t.Name, cause)
+		if t.Hooks.Retry != nil {
+			t.Hooks.Retry(req, resp, cause, delay)
+		}
+		attrs := []any{"attempt", attempt, "max_attempts", attempts, "cause", cause, "delay", delay}
+		if err != nil {
+			attrs = append(attrs, "error", redact(err))
+		} else {
+			attrs = append(attrs, "status", resp.StatusCode)
+			_ = resp.Body.Close()
+		}
+		t.logger().WarnContext(ctx, "retrying request", attrs...)
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, attempt - 1, err
+		}
+	}
+}
+
+// RetryCause classifies a retryable attempt as "rate_limited",
+// "server_error" or "network".
+func RetryCause(resp *http.Response, err error) string {
+	switch {
+	case err != nil || resp == nil:
+		return "network"
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "rate_limited"
+	default:
+		return "server_error"
+	}
+}
+
+// RetryAfter returns the delay a response's Retry-After header asks for,
+// in seconds or as a date, or zero.
+func RetryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	raw := resp.Header.Get("Retry-After")
+	if raw == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(raw); err == nil {
+		if until := time.Until(when); until > 0 {
+			return until
+		}
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("httpx: request canceled: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,237 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransport_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		retryAfter   string
+		policy       Policy
+		wantStatus   int
+		wantAttempts int
+	}{
+		{
+			name:         "retries server errors until success",
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			policy:       Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+		},
+		{
+			name:         "returns last response once attempts are exhausted",
+			statuses:     []int{http.StatusTooManyRequests},
+			policy:       Policy{MaxAttempts: 2, BaseBackoff: time.Millisecond},
+			wantStatus:   http.StatusTooManyRequests,
+			wantAttempts: 2,
+		},
+		{
+			name:         "does not retry client errors",
+			statuses:     []int{http.StatusNotFound},
+			policy:       Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond},
+			wantStatus:   http.StatusNotFound,
+			wantAttempts: 1,
+		},
+		{
+			name:         "gives up when Retry-After exceeds max backoff",
+			statuses:     []int{http.StatusTooManyRequests},
+			retryAfter:   "60",
+			policy:       Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Second},
+			wantStatus:   http.StatusTooManyRequests,
+			wantAttempts: 1,
+		},
+		{
+			name:     "custom retryable",
+			statuses: []int{http.StatusInternalServerError},
+			policy: Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond, Retryable: func(resp *http.Response, err error) bool {
+				return err != nil
+			}},
+			wantStatus:   http.StatusInternalServerError,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				status := tt.statuses[len(tt.statuses)-1]
+				if attempts <= len(tt.statuses) {
+					status = tt.statuses[attempts-1]
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+			defer ts.Close()
+
+			client := NewClient("test", 5*time.Second, tt.policy)
+			resp, err := client.Get(ts.URL)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status: got %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts: got %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestTransport_ReplaysBody(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := NewClient("test", 5*time.Second, Policy{MaxAttempts: 2, BaseBackoff: time.Millisecond})
+	resp, err := client.Post(ts.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 2 || bodies[0] != "payload" || bodies[1] != "payload" {
+		t.Fatalf("bodies: got %q, want the payload twice", bodies)
+	}
+}
+
+func TestTransport_UnreplayableBodySentOnce(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := NewClient("test", 5*time.Second, Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+	// A bare io.Reader gives the request no GetBody.
+	resp, err := client.Post(ts.URL, "text/plain", io.MultiReader(strings.NewReader("payload")))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+
+	if attempts != 1 {
+		t.Fatalf("attempts: got %d, want 1", attempts)
+	}
+}
+
+func TestTransport_Hooks(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var waits, observed int
+	var causes []string
+	client := &http.Client{}
+	tr := Wrap(client, "hooked", Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+	tr.Hooks = Hooks{
+		Wait:    func(context.Context) error { waits++; return nil },
+		Attempt: func(*http.Request, *http.Response, error, time.Duration) { observed++ },
+		Retry: func(_ *http.Request, _ *http.Response, cause string, _ time.Duration) {
+			causes = append(causes, cause)
+		},
+	}
+	before := retries.Value("hooked", "rate_limited")
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+
+	if waits != 2 || observed != 2 {
+		t.Errorf("hooks: got %d waits and %d attempts, want 2 each", waits, observed)
+	}
+	if len(causes) != 1 || causes[0] != "rate_limited" {
+		t.Errorf("retry causes: got %v, want [rate_limited]", causes)
+	}
+	if got := retries.Value("hooked", "rate_limited") - before; got != 1 {
+		t.Errorf("retries metric: got %v, want 1", got)
+	}
+}
+
+func TestTransport_WaitError(t *testing.T) {
+	errLimited := errors.New("limited")
+	client := &http.Client{}
+	tr := Wrap(client, "test", DefaultPolicy())
+	tr.Hooks.Wait = func(context.Context) error { return errLimited }
+
+	_, err := client.Get("http://127.0.0.1:1")
+	if !errors.Is(err, errLimited) {
+		t.Fatalf("error: got %v, want %v", err, errLimited)
+	}
+}
+
+func TestTransport_CanceledDuringBackoff(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+
+	client := NewClient("test", 5*time.Second, Policy{MaxAttempts: 3, BaseBackoff: time.Minute})
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error: got %v, want deadline exceeded", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{name: "seconds", header: "3", want: 3 * time.Second},
+		{name: "missing", header: "", want: 0},
+		{name: "invalid", header: "soon", want: 0},
+		{name: "past date", header: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			if got := RetryAfter(resp); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Package logging builds the structured loggers used across the backend.
+// Records carry the request ID and trace ID of the context they are logged
+// with, and anything that looks like auth material is redacted before it is
+// written.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config selects the level and encoding of log output.
+type Config struct {
+	// Level is "debug", "info", "warn" or "error". Empty means info.
+	Level string
+	// Format is "text" or "json". Empty means text.
+	Format string
+}
+
+// New returns a logger writing records at cfg.Level or above to w.
+func New(w io.Writer, cfg Config) (*slog.Logger, error) {
+	var level slog.Level
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, fmt.Errorf("logging: invalid level %q", cfg.Level)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+
+	var h slog.Handler
+	switch cfg.Format {
+	case "", "text":
+		h = slog.NewTextHandler(w, opts)
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("logging: invalid format %q (want text or json)", cfg.Format)
+	}
+	return slog.New(contextHandler{h}), nil
+}
+
+// Component returns l, or the default logger when l is nil, tagged with
+// the component that logs through it.
+func Component(l *slog.Logger, name string) *slog.Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return l.With("component", name)
+}
+
+// Discard returns a logger that drops every record, for tests.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// contextHandler adds the request ID and trace ID of the context a record
+// is logged with.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Redacted replaces the values of attributes that hold auth material.
+const Redacted = "[REDACTED]"
+
+// credentials matches auth scheme credentials wherever they appear in a
+// string, such as a header or an error echoing a request.
+var credentials = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+
+// sensitiveKey reports whether an attribute named key holds auth material,
+// e.g. authorization, client_secret, refresh_token or cookie.
+func sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range []string{"authorization", "secret", "password", "cookie", "api_key", "apikey"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return key == "token" || strings.HasSuffix(key, "_token") || strings.HasSuffix(key, "-token")
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKey(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	if a.Value.Kind() == slog.KindString {
+		if s := a.Value.String(); credentials.MatchString(s) {
+			return slog.String(a.Key, credentials.ReplaceAllString(s, "$1 "+Redacted))
+		}
+	}
+	return a
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNew_RedactsAuthMaterial(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, Config{Format: "json"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	l.Info("token refreshed",
+		"client_secret", "s3cret",
+		"refresh_token", "r-123",
+		"Authorization", "Bearer abc.def",
+		"error", "POST /token: 401 with Basic dXNlcjpwYXNz",
+		"token_count", 12,
+		"playlist_id", "pl-1")
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("decode %q: %v", buf.String(), err)
+	}
+	for _, key := range []string{"client_secret", "refresh_token", "Authorization"} {
+		if rec[key] != Redacted {
+			t.Errorf("expected %s redacted, got %v", key, rec[key])
+		}
+	}
+	if got := rec["error"]; got != "POST /token: 401 with Basic "+Redacted {
+		t.Errorf("expected credentials redacted from error, got %v", got)
+	}
+	if rec["token_count"] != float64(12) || rec["playlist_id"] != "pl-1" {
+		t.Errorf("expected other attributes untouched, got %v", rec)
+	}
+	for _, secret := range []string{"s3cret", "r-123", "abc.def", "dXNlcjpwYXNz"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("output leaks %q: %s", secret, buf.String())
+		}
+	}
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, Config{Level: "warn"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	l.Info("dropped")
+	l.Warn("kept")
+	if out := buf.String(); strings.Contains(out, "dropped") || !strings.Contains(out, "kept") {
+		t.Fatalf("expected only warn records, got %q", out)
+	}
+
+	if _, err := New(&buf, Config{Level: "loud"}); err == nil {
+		t.Fatal("expected an invalid level to be rejected")
+	}
+	if _, err := New(&buf, Config{Format: "xml"}); err == nil {
+		t.Fatal("expected an invalid format to be rejected")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, Config{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	var seen string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+		l.InfoContext(r.Context(), "handled")
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/playlists", nil)
+	req.Header.Set(RequestIDHeader, "bff-42")
+	h.ServeHTTP(rec, req)
+	if seen != "bff-42" || rec.Header().Get(RequestIDHeader) != "bff-42" {
+		t.Fatalf("expected the caller's ID to be reused, got %q / %q", seen, rec.Header().Get(RequestIDHeader))
+	}
+	if !strings.Contains(buf.String(), "request_id=bff-42") {
+		t.Fatalf("expected the record to carry the request ID, got %q", buf.String())
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/playlists", nil)
+	req.Header.Set(RequestIDHeader, "bad id\nINFO forged")
+	h.ServeHTTP(rec, req)
+	if seen == "" || strings.ContainsAny(seen, " \n") || rec.Header().Get(RequestIDHeader) != seen {
+		t.Fatalf("expected a fresh ID for a malformed header, got %q", seen)
+	}
+}
+
+func TestNew_TraceID(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, Config{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x0a, 0xf7},
+		SpanID:  trace.SpanID{0xb7},
+	})
+	l.InfoContext(trace.ContextWithSpanContext(context.Background(), sc), "traced")
+	if !strings.Contains(buf.String(), "trace_id="+sc.TraceID().String()) {
+		t.Fatalf("expected the record to carry the trace ID, got %q", buf.String())
+	}
+}
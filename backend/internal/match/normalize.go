@@ -0,0 +1,158 @@
+// Package match compares track titles and artist names as written by
+// different catalogs and users, where "Song (Remastered 2011)" and "song"
+// name the same recording.
+package match
+
+import (
+	"strings"
+	"unicode"
+)
+
+// noiseTokens are words that describe a release or credit rather than the
+// song, dropped by Normalize.
+var noiseTokens = map[string]struct{}{
+	"clean":      {},
+	"deluxe":     {},
+	"edition":    {},
+	"edit":       {},
+	"explicit":   {},
+	"feat":       {},
+	"featuring":  {},
+	"ft":         {},
+	"live":       {},
+	"mix":        {},
+	"mono":       {},
+	"radio":      {},
+	"remaster":   {},
+	"remastered": {},
+	"stereo":     {},
+	"version":    {},
+}
+
+// Normalize lowercases input and drops bracketed segments, punctuation and
+// noise words such as "remastered" or "feat", leaving the words that name
+// the song or artist separated by single spaces.
+func Normalize(input string) string {
+	if input == "" {
+		return ""
+	}
+
+	lower := strings.ToLower(input)
+	filtered := stripBracketedSegments(lower)
+	tokens := strings.Fields(Simplify(filtered))
+
+	cleaned := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if _, drop := noiseTokens[token]; drop {
+			continue
+		}
+		cleaned = append(cleaned, token)
+	}
+
+	return strings.Join(cleaned, " ")
+}
+
+// Simplify lowercases input and replaces every run of characters other
+// than letters and digits with a single space, trimming the ends.
+func Simplify(input string) string {
+	var out strings.Builder
+	lastSpace := true
+	for _, r := range strings.ToLower(input) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			out.WriteRune(r)
+			lastSpace = false
+			continue
+		}
+		if !lastSpace {
+			out.WriteRune(' ')
+			lastSpace = true
+		}
+	}
+
+	return strings.TrimRight(out.String(), " ")
+}
+
+func stripBracketedSegments(input string) string {
+	var out strings.Builder
+	depth := 0
+	for _, r := range input {
+		switch r {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			if depth > 0 {
+				depth--
+			}
+		default:
+			if depth == 0 {
+				out.WriteRune(r)
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// Canonical lowercases input and trims trailing version suffixes such as
+// " - Remastered 2011" or "(Live)", leaving words elsewhere in place so
+// "Live Forever" stays as it is. Unlike Normalize it keeps everything that
+// could tell two songs apart.
+func Canonical(input string) string {
+	if strings.TrimSpace(input) == "" {
+		return ""
+	}
+
+	return Simplify(stripVersionSuffixes(strings.ToLower(input)))
+}
+
+func stripVersionSuffixes(input string) string {
+	trimmed := strings.TrimSpace(input)
+	for {
+		next := trimBracketedSuffix(trimmed)
+		next = trimDashSuffix(next)
+		if next == trimmed {
+			return trimmed
+		}
+		trimmed = strings.TrimSpace(next)
+	}
+}
+
+func trimBracketedSuffix(input string) string {
+	trimmed := strings.TrimSpace(input)
+	for _, pair := range [][2]string{{"(", ")"}, {"[", "]"}} {
+		if !strings.HasSuffix(trimmed, pair[1]) {
+			continue
+		}
+		if idx := strings.LastIndex(trimmed, pair[0]); idx != -1 && idx < len(trimmed)-1 {
+			if hasNoiseToken(trimmed[idx+1 : len(trimmed)-1]) {
+				return strings.TrimSpace(trimmed[:idx])
+			}
+		}
+	}
+
+	return input
+}
+
+func trimDashSuffix(input string) string {
+	trimmed := strings.TrimSpace(input)
+	idx := strings.LastIndex(trimmed, " - ")
+	if idx == -1 {
+		return input
+	}
+
+	if hasNoiseToken(trimmed[idx+3:]) {
+		return strings.TrimSpace(trimmed[:idx])
+	}
+
+	return input
+}
+
+func hasNoiseToken(input string) bool {
+	for _, token := range strings.Fields(Simplify(input)) {
+		if _, ok := noiseTokens[token]; ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,8 +1,8 @@
-package spotify
+package match
 
 import "testing"
 
-func TestNormalizeSearchInput(t *testing.T) {
+func TestNormalize(t *testing.T) {
 	tests := []struct {
 		name  string
 		input string
@@ -28,14 +28,33 @@ func TestNormalizeSearchInput(t *testing.T) {
 			input: "Artist feat. Someone",
 			want:  "artist someone",
 		},
+		{
+			name:  "drops nested brackets",
+			input: "Song [Live (2001)] - Remastered",
+			want:  "song",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := normalizeSearchInput(tt.input)
+			got := Normalize(tt.input)
 			if got != tt.want {
-				t.Fatalf("normalizeSearchInput: got %q, want %q", got, tt.want)
+				t.Fatalf("Normalize: got %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestSimplify(t *testing.T) {
+	tests := map[string]string{
+		"AC/DC":           "ac dc",
+		"  Hello, World!": "hello world",
+		"Beyoncé":         "beyoncé",
+		"":                "",
+	}
+	for input, want := range tests {
+		if got := Simplify(input); got != want {
+			t.Errorf("Simplify(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
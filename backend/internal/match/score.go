@@ -0,0 +1,189 @@
+package match
+
+import "strings"
+
+// Matcher scores search results against the title and artist asked for.
+// The zero value accepts any result; Default holds the thresholds the
+// catalog adapters use.
+type Matcher struct {
+	// MinScore is the least score a result needs to be accepted, between
+	// 0 and 1.
+	MinScore float64
+	// ArtistBonus is added when one of the result's artists is exactly
+	// the one asked for.
+	ArtistBonus float64
+	// TitleBonus is added when the result's title contains the one asked
+	// for.
+	TitleBonus float64
+	// SameArtist is the least Similarity of normalized artist names Same
+	// treats as the same artist.
+	SameArtist float64
+}
+
+// Default is the Matcher used unless a caller configures its own.
+var Default = Matcher{MinScore: 0.5, ArtistBonus: 0.4, TitleBonus: 0.3, SameArtist: 0.9}
+
+// Candidate is a search result: its title and the names of its artists.
+type Candidate struct {
+	Title   string
+	Artists []string
+}
+
+// Result is how well a Candidate matched.
+type Result struct {
+	Score       float64
+	ExactArtist bool
+	TitleMatch  bool
+}
+
+// Score rates candidate against the title and artist asked for: the
+// Similarity of the combined names, raised by the bonuses and capped at 1.
+func (m Matcher) Score(title, artist string, candidate Candidate) Result {
+	res := Result{Score: Score(artist, title, JoinArtists(candidate.Artists), candidate.Title)}
+	if res.ExactArtist = exactArtist(candidate.Artists, artist); res.ExactArtist {
+		res.Score += m.ArtistBonus
+	}
+	if res.TitleMatch = containsTitle(candidate.Title, title); res.TitleMatch {
+		res.Score += m.TitleBonus
+	}
+	res.Score = min(res.Score, 1.0)
+	return res
+}
+
+// Accepts reports whether res scores at least m.MinScore.
+func (m Matcher) Accepts(res Result) bool {
+	return res.Score >= m.MinScore
+}
+
+// Beats reports whether r ranks above other: by score, then by an exact
+// artist, then by a matching title.
+func (r Result) Beats(other Result) bool {
+	if r.Score != other.Score {
+		return r.Score > other.Score
+	}
+	if r.ExactArtist != other.ExactArtist {
+		return r.ExactArtist
+	}
+	return r.TitleMatch && !other.TitleMatch
+}
+
+// Same reports whether two title and artist pairs name the same song: the
+// titles are equal once version suffixes are trimmed, and the normalized
+// artist names are at least m.SameArtist similar.
+func (m Matcher) Same(titleA, artistA, titleB, artistB string) bool {
+	titleA, titleB = Canonical(titleA), Canonical(titleB)
+	artistA, artistB = Normalize(artistA), Normalize(artistB)
+	if titleA == "" || artistA == "" || artistB == "" || titleA != titleB {
+		return false
+	}
+	return Similarity(artistA, artistB) >= m.SameArtist
+}
+
+// Matches reports whether a search result is the song asked for rather
+// than a cover or a different song: it is the Same song, or has the same
+// title and credits the artist asked for among others, so "Song -
+// Remastered" by "Artist & Guest" still matches "Song" by "Artist".
+func (m Matcher) Matches(title, artist, resultTitle, resultArtist string) bool {
+	if m.Same(title, artist, resultTitle, resultArtist) {
+		return true
+	}
+	title = Canonical(title)
+	if title == "" || title != Canonical(resultTitle) {
+		return false
+	}
+	return containsWords(Normalize(resultArtist), Normalize(artist))
+}
+
+// Score returns the Similarity of two artist and title pairs once
+// canonicalized.
+func Score(targetArtist, targetTitle, actualArtist, actualTitle string) float64 {
+	target := Canonical(strings.TrimSpace(targetArtist + " " + targetTitle))
+	actual := Canonical(strings.TrimSpace(actualArtist + " " + actualTitle))
+	if target == "" || actual == "" {
+		return 0
+	}
+
+	return Similarity(target, actual)
+}
+
+// JoinArtists joins artist names for scoring.
+func JoinArtists(names []string) string {
+	return strings.Join(names, " ")
+}
+
+// Similarity is 1 for equal strings, falling towards 0 with their
+// Distance relative to the longer one.
+func Similarity(a, b string) float64 {
+	if a == b {
+		return 1.0
+	}
+	maxLen := max(len([]rune(a)), len([]rune(b)))
+	if maxLen == 0 {
+		return 1.0
+	}
+
+	return 1.0 - float64(Distance(a, b))/float64(maxLen)
+}
+
+// Distance is the Levenshtein distance between a and b in runes.
+func Distance(a, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := 0; j <= len(rb); j++ {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 0
+			if ra[i-1] != rb[j-1] {
+				cost = 1
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		copy(prev, curr)
+	}
+
+	return prev[len(rb)]
+}
+
+func exactArtist(candidates []string, target string) bool {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return false
+	}
+	for _, name := range candidates {
+		if strings.EqualFold(strings.TrimSpace(name), target) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWords reports whether the words of target appear together in
+// text, so "ye" is not found in "kanye west".
+func containsWords(text, target string) bool {
+	if target == "" {
+		return false
+	}
+	return strings.Contains(" "+text+" ", " "+target+" ")
+}
+
+func containsTitle(candidate, target string) bool {
+	ct := strings.ToLower(strings.TrimSpace(candidate))
+	tt := strings.ToLower(strings.TrimSpace(target))
+	if ct == "" || tt == "" {
+		return false
+	}
+	return strings.Contains(ct, tt)
+}
@@ -0,0 +1,219 @@
+package match
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "exact",
+			input: "Hello",
+			want:  "hello",
+		},
+		{
+			name:  "dash suffix",
+			input: "Track - Remastered 2011",
+			want:  "track",
+		},
+		{
+			name:  "bracket suffix",
+			input: "Song (Live)",
+			want:  "song",
+		},
+		{
+			name:  "punctuation",
+			input: "AC/DC",
+			want:  "ac dc",
+		},
+		{
+			name:  "not suffix",
+			input: "Live Forever",
+			want:  "live forever",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Canonical(tt.input)
+			if got != tt.want {
+				t.Fatalf("Canonical(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{
+			name: "kitten sitting",
+			a:    "kitten",
+			b:    "sitting",
+			want: 3,
+		},
+		{
+			name: "empty to word",
+			a:    "",
+			b:    "sound",
+			want: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Distance(tt.a, tt.b)
+			if got != tt.want {
+				t.Fatalf("distance: got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreResult(t *testing.T) {
+	tests := []struct {
+		name          string
+		targetArtist  string
+		targetTitle   string
+		actualArtist  string
+		actualTitle   string
+		want          float64
+		wantTolerance float64
+		min           float64
+		max           float64
+	}{
+		{
+			name:          "exact match",
+			targetArtist:  "Radiohead",
+			targetTitle:   "Creep",
+			actualArtist:  "Radiohead",
+			actualTitle:   "Creep",
+			want:          1.0,
+			wantTolerance: 0.0001,
+		},
+		{
+			name:          "case mismatch",
+			targetArtist:  "RADIOHEAD",
+			targetTitle:   "CREEP",
+			actualArtist:  "radiohead",
+			actualTitle:   "creep",
+			want:          1.0,
+			wantTolerance: 0.0001,
+		},
+		{
+			name:         "major mismatch",
+			targetArtist: "Radiohead",
+			targetTitle:  "Creep",
+			actualArtist: "Taylor Swift",
+			actualTitle:  "Love Story",
+			max:          0.4,
+		},
+		{
+			name:         "suffix mismatch",
+			targetArtist: "Radiohead",
+			targetTitle:  "Creep",
+			actualArtist: "Radiohead",
+			actualTitle:  "Creep - Remastered 2009",
+			min:          0.9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Score(tt.targetArtist, tt.targetTitle, tt.actualArtist, tt.actualTitle)
+			if tt.wantTolerance > 0 {
+				if math.Abs(got-tt.want) > tt.wantTolerance {
+					t.Fatalf("Score() = %0.4f, want %0.4f", got, tt.want)
+				}
+				return
+			}
+			if tt.min > 0 && got < tt.min {
+				t.Fatalf("Score() = %0.4f, want >= %0.4f", got, tt.min)
+			}
+			if tt.max > 0 && got > tt.max {
+				t.Fatalf("Score() = %0.4f, want <= %0.4f", got, tt.max)
+			}
+		})
+	}
+}
+
+func TestMatcher_Score(t *testing.T) {
+	candidates := []Candidate{
+		{Title: "Creep (Acoustic Cover)", Artists: []string{"Someone Else"}},
+		{Title: "Creep", Artists: []string{"Radiohead"}},
+	}
+	best, bestResult := -1, Result{}
+	for i, c := range candidates {
+		res := Default.Score("Creep", "Radiohead", c)
+		if Default.Accepts(res) && (best == -1 || res.Beats(bestResult)) {
+			best, bestResult = i, res
+		}
+	}
+	if best != 1 || !bestResult.ExactArtist || !bestResult.TitleMatch || bestResult.Score != 1 {
+		t.Fatalf("expected the exact match to win, got %d with %+v", best, bestResult)
+	}
+
+	strict := Matcher{MinScore: 0.99}
+	if strict.Accepts(strict.Score("Creep", "Radiohead", candidates[0])) {
+		t.Fatal("expected a cover to fall below a strict threshold")
+	}
+}
+
+func TestMatcher_Matches(t *testing.T) {
+	tests := []struct {
+		name                string
+		title, artist       string
+		resTitle, resArtist string
+		want                bool
+	}{
+		{name: "same", title: "Don't Stop Me Now", artist: "Queen", resTitle: "Don't Stop Me Now", resArtist: "Queen", want: true},
+		{name: "remaster", title: "Don't Stop Me Now", artist: "Queen", resTitle: "Don’t Stop Me Now - Remastered 2011", resArtist: "QUEEN", want: true},
+		{name: "credited with others", title: "Crazy in Love", artist: "Beyoncé", resTitle: "Crazy in Love (feat. JAY-Z)", resArtist: "Beyoncé & JAY-Z", want: true},
+		{name: "artist inside a word", title: "Stronger", artist: "Ye", resTitle: "Stronger", resArtist: "Kanye West", want: false},
+		{name: "cover", title: "Don't Stop Me Now", artist: "Queen", resTitle: "Don't Stop Me Now", resArtist: "The Tribute Band", want: false},
+		{name: "longer title", title: "Forever", artist: "Oasis", resTitle: "Live Forever", resArtist: "Oasis", want: false},
+		{name: "another song", title: "Don't Stop Me Now", artist: "Queen", resTitle: "Bohemian Rhapsody", resArtist: "Queen", want: false},
+		{name: "missing title", title: "Don't Stop Me Now", artist: "Queen", resArtist: "Queen", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Default.Matches(tt.title, tt.artist, tt.resTitle, tt.resArtist); got != tt.want {
+				t.Fatalf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_Same(t *testing.T) {
+	tests := []struct {
+		name           string
+		titleA, titleB string
+		artA, artB     string
+		want           bool
+	}{
+		{name: "remaster", titleA: "Song", artA: "Artist", titleB: "Song (Remastered 2011)", artB: "Artist", want: true},
+		{name: "featured artist", titleA: "Song", artA: "Artist", titleB: "Song (feat. Guest)", artB: "Artist", want: true},
+		{name: "punctuated artist", titleA: "Patience", artA: "Guns N' Roses", titleB: "Patience", artB: "Guns N Roses", want: true},
+		{name: "misspelled artist", titleA: "Hello", artA: "Fleetwood Mac", titleB: "Hello", artB: "Fleetwod Mac", want: true},
+		{name: "different song with a noise word", titleA: "Live Forever", artA: "Oasis", titleB: "Forever", artB: "Oasis", want: false},
+		{name: "numbered parts", titleA: "Part 1", artA: "Artist", titleB: "Part 2", artB: "Artist", want: false},
+		{name: "another artist", titleA: "Song", artA: "Artist A", titleB: "Song", artB: "Artist B", want: false},
+		{name: "missing artist", titleA: "Song", titleB: "Song", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Default.Same(tt.titleA, tt.artA, tt.titleB, tt.artB); got != tt.want {
+				t.Fatalf("Same = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
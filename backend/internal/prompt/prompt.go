@@ -0,0 +1,130 @@
+// Package prompt renders the system prompts of the intent compilers from
+// text/template templates. Defaults are embedded in the binary; a template
+// of the same name in an override directory replaces one, so prompts can
+// be tuned without a rebuild.
+package prompt
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+//go:embed templates/*.tmpl
+var defaults embed.FS
+
+// ext is the file extension of templates; a template's name is its file
+// name without it, e.g. "ollama".
+const ext = ".tmpl"
+
+// DefaultGenres are the genres prompts suggest unless Vars says otherwise.
+var DefaultGenres = []string{
+	"ambient", "blues", "classical", "country", "disco", "electronic", "folk", "funk",
+	"hip hop", "house", "indie", "jazz", "k-pop", "latin", "lo-fi", "metal", "pop",
+	"punk", "r&b", "reggae", "rock", "soul", "techno",
+}
+
+// Vars are the template variables fixed for the process.
+type Vars struct {
+	// Genres are the genre names compilers should prefer.
+	Genres []string
+	// Capabilities describe what besides the intent shapes the result,
+	// e.g. "the weather".
+	Capabilities []string
+}
+
+// Data is what a template is rendered with.
+type Data struct {
+	Vars
+	// Playlist is nil when the request is not about a known playlist.
+	Playlist *domain.PlaylistSummary
+}
+
+// Set holds the templates of every compiler.
+type Set struct {
+	vars      Vars
+	templates map[string]*template.Template
+}
+
+var funcs = template.FuncMap{"join": strings.Join}
+
+// Default returns the embedded templates with DefaultGenres.
+func Default() *Set {
+	s, err := Load("", Vars{Genres: DefaultGenres})
+	if err != nil {
+		panic(err) // the embedded templates are tested to parse
+	}
+	return s
+}
+
+// Load returns the embedded templates, each replaced by the file of the
+// same name in dir if there is one, rendered with vars. An empty dir uses
+// the defaults alone.
+func Load(dir string, vars Vars) (*Set, error) {
+	s := &Set{vars: vars, templates: map[string]*template.Template{}}
+	if err := s.parse(defaults, "templates"); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := s.parse(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// parse adds the templates in dir of fsys, replacing those of the same name.
+func (s *Set) parse(fsys fs.FS, dir string) error {
+	paths, err := fs.Glob(fsys, filepath.ToSlash(filepath.Join(dir, "*"+ext)))
+	if err != nil {
+		return fmt.Errorf("prompt: list templates: %w", err)
+	}
+	for _, path := range paths {
+		text, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return fmt.Errorf("prompt: read %s: %w", path, err)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ext)
+		tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return fmt.Errorf("prompt: parse %s: %w", path, err)
+		}
+		s.templates[name] = tmpl
+	}
+	return nil
+}
+
+// Names returns the template names, sorted.
+func (s *Set) Names() []string {
+	names := make([]string, 0, len(s.templates))
+	for name := range s.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ErrUnknownTemplate is returned by Render for a name with no template.
+var ErrUnknownTemplate = errors.New("prompt: unknown template")
+
+// Render renders the template called name for a request about playlist,
+// which may be nil.
+func (s *Set) Render(name string, playlist *domain.PlaylistSummary) (string, error) {
+	tmpl, ok := s.templates[name]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, Data{Vars: s.vars, Playlist: playlist}); err != nil {
+		return "", fmt.Errorf("prompt: render %s: %w", name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
@@ -0,0 +1,81 @@
+package prompt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+func TestDefault(t *testing.T) {
+	s := Default()
+	if got := strings.Join(s.Names(), ","); got != "anthropic,ollama" {
+		t.Fatalf("expected the anthropic and ollama templates, got %s", got)
+	}
+	for _, name := range s.Names() {
+		got, err := s.Render(name, nil)
+		if err != nil {
+			t.Fatalf("render %s: %v", name, err)
+		}
+		if !strings.HasPrefix(got, "You are the Overture Music Intent Engine.") || !strings.Contains(got, "hip hop, house") {
+			t.Fatalf("%s: unexpected prompt %q", name, got)
+		}
+		if strings.Contains(got, "Playlist:") || strings.Contains(got, "Context:") {
+			t.Fatalf("%s: expected no playlist or capabilities without them, got %q", name, got)
+		}
+	}
+}
+
+func TestSet_Render(t *testing.T) {
+	s, err := Load("", Vars{Capabilities: []string{"the weather", "the time of day"}})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got, err := s.Render("ollama", &domain.PlaylistSummary{Name: "Run", TrackCount: 12, Features: domain.AudioFeatures{Energy: 0.81, Tempo: 164.4}})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{
+		"also takes the weather, the time of day into account",
+		"the playlist 'Run' with 12 tracks averaging energy 0.81",
+		"tempo 164 BPM",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in the prompt, got %q", want, got)
+		}
+	}
+	if strings.Contains(got, "Genres:") {
+		t.Errorf("expected no genre line without genres, got %q", got)
+	}
+
+	if _, err := s.Render("gpt", nil); !errors.Is(err, ErrUnknownTemplate) {
+		t.Fatalf("expected ErrUnknownTemplate, got %v", err)
+	}
+}
+
+func TestLoad_Override(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ollama.tmpl"), []byte("Custom prompt with {{len .Genres}} genres."), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	s, err := Load(dir, Vars{Genres: []string{"jazz", "soul"}})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got, _ := s.Render("ollama", nil); got != "Custom prompt with 2 genres." {
+		t.Fatalf("expected the override, got %q", got)
+	}
+	if got, _ := s.Render("anthropic", nil); !strings.HasPrefix(got, "You are the Overture") {
+		t.Fatalf("expected the default anthropic template, got %q", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "ollama.tmpl"), []byte("{{.Missing"), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if _, err := Load(dir, Vars{}); err == nil {
+		t.Fatal("expected a parse error for a broken override")
+	}
+}
@@ -0,0 +1,110 @@
+// Package tracing sets up OpenTelemetry tracing. Trace context arrives with
+// BFF requests in W3C traceparent headers, is carried through the handler,
+// the orchestrator and queued worker jobs, and is passed on to Spotify,
+// Ollama and the other providers in outbound requests.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/ewilliams-labs/overture/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config selects where spans are exported.
+type Config struct {
+	// Endpoint is the OTLP/HTTP collector base URL, e.g.
+	// http://otel-collector:4318; spans go to its /v1/traces. Empty
+	// disables exporting, but trace context is still passed on.
+	Endpoint string
+	// ServiceName names this process in traces.
+	ServiceName string
+}
+
+// propagator reads and writes W3C traceparent and baggage headers.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Setup installs the global propagator and, when cfg.Endpoint is set, a
+// tracer provider batching spans to it. The returned function flushes and
+// stops the provider.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("tracing: invalid OTLP endpoint %q", cfg.Endpoint)
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(path.Join("/", u.Path, "v1/traces")),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to build resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Handler starts a server span for every request next serves, continuing
+// the caller's trace. Spans are named after the matched route, e.g.
+// "GET /playlists/{id}"; health checks and scrapes are not traced.
+func Handler(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			switch r.URL.Path {
+			case "/health", "/metrics":
+				return false
+			}
+			return true
+		}),
+		// Called again once the mux has set r.Pattern.
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			if r.Pattern == "" {
+				return r.Method
+			}
+			return r.Method + " " + metrics.RouteLabel(r.Pattern)
+		}),
+	)
+}
+
+// TraceParent encodes sc as a W3C traceparent header value, or "" when sc
+// is not valid, so it can be stored with work that runs later.
+func TraceParent(sc trace.SpanContext) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(trace.ContextWithSpanContext(context.Background(), sc), carrier)
+	return carrier.Get("traceparent")
+}
+
+// ParseTraceParent decodes a value from TraceParent. Invalid values give an
+// invalid SpanContext, which starts a new trace.
+func ParseTraceParent(value string) trace.SpanContext {
+	if value == "" {
+		return trace.SpanContext{}
+	}
+	carrier := propagation.MapCarrier{"traceparent": value}
+	return trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+}
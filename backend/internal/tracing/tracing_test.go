@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceParent(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	value := TraceParent(sc)
+	if value != "00-01020300000000000000000000000000-0405060000000000-01" {
+		t.Fatalf("TraceParent = %q", value)
+	}
+	got := ParseTraceParent(value)
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || !got.IsRemote() {
+		t.Errorf("ParseTraceParent = %v, want %v", got, sc)
+	}
+	if TraceParent(trace.SpanContext{}) != "" || ParseTraceParent("garbage").IsValid() {
+		t.Error("invalid span contexts should round-trip as empty")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	if _, err := Setup(t.Context(), Config{}); err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /playlists/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {})
+	h := Handler(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/playlists/abc", nil)
+	req.Header.Set("traceparent", "00-01020300000000000000000000000000-0405060000000000-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1 (health checks are not traced)", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /playlists/{id}" {
+		t.Errorf("span name = %q", span.Name())
+	}
+	if span.Parent().TraceID() != (trace.TraceID{1, 2, 3}) {
+		t.Errorf("span parent = %v, want the caller's trace", span.Parent())
+	}
+}
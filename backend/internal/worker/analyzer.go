@@ -7,55 +7,92 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/fingerprint"
+	"github.com/ewilliams-labs/overture/backend/internal/httpx"
 	"github.com/hajimehoshi/go-mp3"
 )
 
-var previewClient = &http.Client{Timeout: 15 * time.Second}
+var previewClient = httpx.NewClient("preview_audio", 15*time.Second, httpx.DefaultPolicy())
 
-func analyzePreview(url string) (float64, error) {
+// PreviewAnalysis is everything extracted from one decoded preview clip.
+type PreviewAnalysis struct {
+	Features    domain.AudioFeatures
+	Fingerprint []uint32
+}
+
+func analyzePreview(url string) (domain.AudioFeatures, error) {
+	pcm, rate, err := decodePreview(url)
+	if err != nil {
+		return domain.AudioFeatures{}, err
+	}
+	return analyzeSamples(pcm, rate)
+}
+
+// fingerprintPreview decodes the preview once for both its features and its
+// fingerprint.
+func fingerprintPreview(url string) (PreviewAnalysis, error) {
+	pcm, rate, err := decodePreview(url)
+	if err != nil {
+		return PreviewAnalysis{}, err
+	}
+	features, err := analyzeSamples(pcm, rate)
+	if err != nil {
+		return PreviewAnalysis{}, err
+	}
+	// go-mp3 always decodes to interleaved 16-bit stereo.
+	return PreviewAnalysis{Features: features, Fingerprint: fingerprint.Compute(pcm, 2, rate)}, nil
+}
+
+// decodePreview downloads an MP3 preview and returns its interleaved 16-bit
+// stereo samples and sample rate.
+func decodePreview(url string) ([]int16, int, error) {
 	// #nosec G107 -- URL is a validated Spotify preview URL from trusted API response
 	resp, err := previewClient.Get(url)
 	if err != nil {
-		return 0, fmt.Errorf("preview fetch failed: %w", err)
+		return nil, 0, fmt.Errorf("preview fetch failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("preview fetch status %d", resp.StatusCode)
+		return nil, 0, fmt.Errorf("preview fetch status %d", resp.StatusCode)
 	}
 
 	decoder, err := mp3.NewDecoder(resp.Body)
 	if err != nil {
-		return 0, fmt.Errorf("preview decode failed: %w", err)
+		return nil, 0, fmt.Errorf("preview decode failed: %w", err)
 	}
 
 	buf := make([]byte, 4096)
-	var sumSquares float64
-	var count float64
-
+	var pcm []int16
 	for {
 		n, err := decoder.Read(buf)
-		if n > 0 {
-			for i := 0; i+1 < n; i += 2 {
-				sample := int16(buf[i]) | int16(buf[i+1])<<8
-				val := float64(sample)
-				sumSquares += val * val
-				count++
-			}
+		for i := 0; i+1 < n; i += 2 {
+			pcm = append(pcm, int16(buf[i])|int16(buf[i+1])<<8)
 		}
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return 0, fmt.Errorf("preview read failed: %w", err)
+			return nil, 0, fmt.Errorf("preview read failed: %w", err)
 		}
 	}
+	return pcm, decoder.SampleRate(), nil
+}
 
-	if count == 0 {
+// previewEnergy estimates energy as the RMS level of the samples.
+func previewEnergy(pcm []int16) (float64, error) {
+	if len(pcm) == 0 {
 		return 0, fmt.Errorf("preview contains no samples")
 	}
 
-	rms := math.Sqrt(sumSquares / count)
+	var sumSquares float64
+	for _, sample := range pcm {
+		val := float64(sample)
+		sumSquares += val * val
+	}
+
+	rms := math.Sqrt(sumSquares / float64(len(pcm)))
 	energy := rms / 32768.0
 	if energy < 0 {
 		energy = 0
@@ -69,3 +106,7 @@ func analyzePreview(url string) (float64, error) {
 
 // AnalyzePreviewFunc allows tests to override the analyzer implementation.
 var AnalyzePreviewFunc = analyzePreview
+
+// FingerprintPreviewFunc replaces AnalyzePreviewFunc when the pool
+// fingerprints previews; tests may override it.
+var FingerprintPreviewFunc = fingerprintPreview
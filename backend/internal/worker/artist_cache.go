@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+)
+
+// ArtistCacheEviction returns a task that, every interval, drops the
+// artists fetched more than maxAge ago. Lookups refresh entries past their
+// TTL, so an entry that old has not been asked for in a long while; it is
+// only kept around as a fallback for when Spotify fails.
+func ArtistCacheEviction(evictor ports.ArtistEvictor, interval, maxAge time.Duration) Task {
+	logger := logging.Component(nil, "artist_cache")
+	return Task{Name: "artist_cache", Every: interval, Run: func(ctx context.Context) error {
+		return evictArtists(ctx, evictor, maxAge, logger)
+	}}
+}
+
+func evictArtists(ctx context.Context, evictor ports.ArtistEvictor, maxAge time.Duration, logger *slog.Logger) error {
+	n, err := evictor.EvictArtists(ctx, time.Now().Add(-maxAge))
+	if err != nil {
+		return err
+	}
+	logger.Info("artist cache evicted", "artists", n, "max_age", maxAge)
+	return nil
+}
@@ -0,0 +1,230 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+var backfillTracks = metrics.NewCounterVec(
+	"overture_backfill_tracks_total",
+	"Tracks handled by the analysis backfill, by result (features, resolved, unresolved, enqueued, dropped).",
+	"result",
+)
+
+// BackfillReport describes the current or most recent backfill run.
+type BackfillReport struct {
+	Running    bool       `json:"running"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Found is the number of tracks the run picks up; Processed counts
+	// those handled so far.
+	Found     int `json:"found"`
+	Processed int `json:"processed"`
+	// FeaturesResolved counts tracks without features for which the
+	// feature lookup had measured ones, sparing their analysis.
+	FeaturesResolved int `json:"features_resolved"`
+	// PreviewsResolved and Unresolved count tracks without a preview for
+	// which a fallback source did or did not have one.
+	PreviewsResolved int `json:"previews_resolved"`
+	Unresolved       int `json:"unresolved"`
+	// Enqueued tracks were queued for analysis; Dropped ones found the
+	// queue full and are picked up by a later run.
+	Enqueued int `json:"enqueued"`
+	Dropped  int `json:"dropped"`
+	// JobIDs are the enqueued analyses that can be followed at
+	// GET /jobs/{id}; they are only known with a persistent queue.
+	JobIDs []string `json:"job_ids,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Backfiller finds tracks that were stored without a preview or never
+// analyzed, looks up their features or resolves missing previews and
+// queues them for analysis.
+type Backfiller struct {
+	backlog  ports.AnalysisBacklog
+	pool     *Pool
+	limit    int
+	preview  func(ctx context.Context, trackID string) (string, bool)
+	features func(ctx context.Context, trackID string) (domain.AudioFeatures, bool)
+
+	mu     sync.Mutex
+	report BackfillReport
+	logger *slog.Logger
+}
+
+// NewBackfiller creates a backfiller that handles up to limit tracks per
+// run, queuing analysis on pool.
+func NewBackfiller(backlog ports.AnalysisBacklog, pool *Pool, limit int) *Backfiller {
+	if limit < 1 {
+		limit = 100
+	}
+	return &Backfiller{backlog: backlog, pool: pool, limit: limit, logger: logging.Component(nil, "backfill")}
+}
+
+// SetPreviewFallback makes runs look up previews with resolve, which
+// reports false when there is none. Without it, tracks lacking a preview
+// are counted as unresolved.
+func (b *Backfiller) SetPreviewFallback(resolve func(ctx context.Context, trackID string) (string, bool)) {
+	b.preview = resolve
+}
+
+// SetFeatureLookup makes runs look up features for tracks that have none
+// with lookup, which stores what it finds and reports false when there is
+// nothing to store. Tracks it resolves are not analyzed.
+func (b *Backfiller) SetFeatureLookup(lookup func(ctx context.Context, trackID string) (domain.AudioFeatures, bool)) {
+	b.features = lookup
+}
+
+// Status returns the progress of the current or most recent run.
+func (b *Backfiller) Status() BackfillReport {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	report := b.report
+	report.JobIDs = slices.Clone(report.JobIDs)
+	return report
+}
+
+// Start begins a run in the background unless one is in progress. It
+// returns the run's initial status and whether it started one.
+func (b *Backfiller) Start(ctx context.Context) (BackfillReport, bool) {
+	if !b.begin() {
+		return b.Status(), false
+	}
+	report := b.Status()
+	go func() {
+		if err := b.run(context.WithoutCancel(ctx)); err != nil {
+			b.logger.WarnContext(ctx, "backfill failed", "error", err)
+		}
+	}()
+	return report, true
+}
+
+// Run performs a run synchronously, unless one is already in progress.
+func (b *Backfiller) Run(ctx context.Context) (BackfillReport, error) {
+	if !b.begin() {
+		return b.Status(), fmt.Errorf("backfill: already running")
+	}
+	err := b.run(ctx)
+	return b.Status(), err
+}
+
+// begin marks a run as started, reporting false if one already is.
+func (b *Backfiller) begin() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.report.Running {
+		return false
+	}
+	now := time.Now().UTC()
+	b.report = BackfillReport{Running: true, StartedAt: &now}
+	return true
+}
+
+func (b *Backfiller) run(ctx context.Context) (err error) {
+	// Backfills yield to users for shared provider quotas.
+	ctx = ports.WithPriority(ctx, ports.PriorityBackground)
+	defer func() {
+		b.update(func(r *BackfillReport) {
+			now := time.Now().UTC()
+			r.Running = false
+			r.FinishedAt = &now
+			if err != nil {
+				r.Error = err.Error()
+			}
+		})
+	}()
+
+	tracks, err := b.backlog.TracksMissingAnalysis(ctx, b.limit)
+	if err != nil {
+		return fmt.Errorf("backfill: %w", err)
+	}
+	b.update(func(r *BackfillReport) { r.Found = len(tracks) })
+
+	ids := make([]string, len(tracks))
+	for i, t := range tracks {
+		ids[i] = t.ID
+	}
+	if err := b.backlog.MarkBackfillAttempted(ctx, ids, time.Now()); err != nil {
+		return fmt.Errorf("backfill: %w", err)
+	}
+
+	for _, t := range tracks {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("backfill: %w", err)
+		}
+		// Measured features beat analyzing a preview, which only yields
+		// energy; the preview is still resolved for playback.
+		if t.Features == (domain.AudioFeatures{}) && b.features != nil {
+			if features, ok := b.features(ctx, t.ID); ok {
+				backfillTracks.Inc("features")
+				b.update(func(r *BackfillReport) { r.FeaturesResolved++ })
+				t.Features = features
+			}
+		}
+
+		previewURL := t.PreviewURL
+		if previewURL == "" {
+			resolved, ok := "", false
+			if b.preview != nil {
+				resolved, ok = b.preview(ctx, t.ID)
+			}
+			if !ok {
+				backfillTracks.Inc("unresolved")
+				b.update(func(r *BackfillReport) { r.Processed++; r.Unresolved++ })
+				continue
+			}
+			backfillTracks.Inc("resolved")
+			b.update(func(r *BackfillReport) { r.PreviewsResolved++ })
+			previewURL = resolved
+		}
+
+		// Tracks with features from the provider only needed the preview;
+		// analysis would overwrite them with energy alone.
+		if t.Features != (domain.AudioFeatures{}) {
+			b.update(func(r *BackfillReport) { r.Processed++ })
+			continue
+		}
+		if jobID, ok := b.pool.SubmitTracked(Job{TrackID: t.ID, PreviewURL: previewURL}); ok {
+			backfillTracks.Inc("enqueued")
+			b.update(func(r *BackfillReport) {
+				r.Processed++
+				r.Enqueued++
+				if jobID != "" {
+					r.JobIDs = append(r.JobIDs, jobID)
+				}
+			})
+		} else {
+			backfillTracks.Inc("dropped")
+			b.update(func(r *BackfillReport) { r.Processed++; r.Dropped++ })
+		}
+	}
+	return nil
+}
+
+func (b *Backfiller) update(fn func(r *BackfillReport)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fn(&b.report)
+}
+
+// Task runs a backfill every interval.
+func (b *Backfiller) Task(interval time.Duration) Task {
+	return Task{Name: "backfill", Every: interval, Run: func(ctx context.Context) error {
+		report, err := b.Run(ctx)
+		if err != nil {
+			return err
+		}
+		b.logger.Info("backfill finished", "found", report.Found, "features_resolved", report.FeaturesResolved, "previews_resolved", report.PreviewsResolved,
+			"unresolved", report.Unresolved, "enqueued", report.Enqueued, "dropped", report.Dropped)
+		return nil
+	}}
+}
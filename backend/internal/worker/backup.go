@@ -0,0 +1,188 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+const (
+	backupKeyPrefix  = "backups/"
+	backupPrefix     = "overture-"
+	backupSuffix     = ".db"
+	backupTimeLayout = "20060102T150405Z"
+)
+
+var (
+	backupsTotal = metrics.NewCounterVec(
+		"overture_backups_total",
+		"Database snapshots attempted, by result.",
+		"result",
+	)
+	backupLastSuccess = metrics.NewGaugeVec(
+		"overture_backup_last_success_timestamp_seconds",
+		"Unix time of the last successful database snapshot.",
+	)
+)
+
+// Backup describes a snapshot file.
+type Backup struct {
+	Name      string    `json:"name"`
+	SizeBytes int64     `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Backups stores timestamped database snapshots in a blob store and keeps
+// only the newest ones.
+type Backups struct {
+	snap   ports.Snapshotter
+	store  ports.BlobStore
+	retain int
+	mu     sync.Mutex // serializes snapshots and pruning
+	logger *slog.Logger
+}
+
+// NewBackups creates a backup manager storing snapshots under backups/ in
+// store. retain is the number of snapshots kept after each run; values below
+// 1 keep one.
+func NewBackups(snap ports.Snapshotter, store ports.BlobStore, retain int) *Backups {
+	if retain < 1 {
+		retain = 1
+	}
+	return &Backups{snap: snap, store: store, retain: retain, logger: logging.Component(nil, "backup")}
+}
+
+// Create takes a snapshot now and applies the retention policy.
+func (b *Backups) Create(ctx context.Context) (Backup, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	backup, err := b.create(ctx)
+	if err != nil {
+		backupsTotal.Inc("error")
+		return Backup{}, err
+	}
+	backupsTotal.Inc("ok")
+	backupLastSuccess.Set(float64(backup.CreatedAt.Unix()))
+
+	if err := b.prune(ctx); err != nil {
+		b.logger.WarnContext(ctx, "failed to apply retention", "error", err)
+	}
+	return backup, nil
+}
+
+func (b *Backups) create(ctx context.Context) (Backup, error) {
+	now := time.Now().UTC()
+	name := backupPrefix + now.Format(backupTimeLayout) + backupSuffix
+
+	// The backup API writes to a file, so snapshot into a scratch directory
+	// and upload the finished copy. The store only ever sees whole snapshots.
+	scratch, err := os.MkdirTemp("", "overture-backup-")
+	if err != nil {
+		return Backup{}, fmt.Errorf("backup: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	local := filepath.Join(scratch, name)
+	if err := b.snap.Snapshot(ctx, local); err != nil {
+		return Backup{}, fmt.Errorf("backup: %w", err)
+	}
+
+	f, err := os.Open(local) // #nosec G304 -- path is inside our scratch directory
+	if err != nil {
+		return Backup{}, fmt.Errorf("backup: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return Backup{}, fmt.Errorf("backup: %w", err)
+	}
+	if err := b.store.Put(ctx, backupKeyPrefix+name, f, info.Size()); err != nil {
+		return Backup{}, fmt.Errorf("backup: %w", err)
+	}
+	return Backup{Name: name, SizeBytes: info.Size(), CreatedAt: now}, nil
+}
+
+// List returns the existing snapshots, newest first.
+func (b *Backups) List(ctx context.Context) ([]Backup, error) {
+	blobs, err := b.store.List(ctx, backupKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+
+	backups := []Backup{}
+	for _, blob := range blobs {
+		name := strings.TrimPrefix(blob.Key, backupKeyPrefix)
+		if strings.Contains(name, "/") || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+		createdAt, err := time.Parse(backupTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupSuffix))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Name: name, SizeBytes: blob.Size, CreatedAt: createdAt})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+func (b *Backups) prune(ctx context.Context) error {
+	backups, err := b.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, old := range backups[min(b.retain, len(backups)):] {
+		if err := b.store.Delete(ctx, backupKeyPrefix+old.Name); err != nil {
+			return fmt.Errorf("backup: failed to remove %s: %w", old.Name, err)
+		}
+	}
+	return nil
+}
+
+// Expired returns snapshots created before cutoff, always sparing the newest
+// one so an idle deployment never ends up with no backup at all. Unless
+// dryRun is set, the returned snapshots are deleted.
+func (b *Backups) Expired(ctx context.Context, cutoff time.Time, dryRun bool) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	backups, err := b.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expired := []string{}
+	for _, backup := range backups[min(1, len(backups)):] {
+		if !backup.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if !dryRun {
+			if err := b.store.Delete(ctx, backupKeyPrefix+backup.Name); err != nil {
+				return expired, fmt.Errorf("backup: failed to remove %s: %w", backup.Name, err)
+			}
+		}
+		expired = append(expired, backup.Name)
+	}
+	return expired, nil
+}
+
+// Task takes a snapshot every interval.
+func (b *Backups) Task(interval time.Duration) Task {
+	return Task{Name: "backup", Every: interval, Run: func(ctx context.Context) error {
+		backup, err := b.Create(ctx)
+		if err != nil {
+			return err
+		}
+		b.logger.Info("snapshot written", "name", backup.Name, "size_bytes", backup.SizeBytes)
+		return nil
+	}}
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+var cleanupRemoved = metrics.NewCounterVec(
+	"overture_cleanup_removed_total",
+	"Records removed by the cleanup job, by kind.",
+	"kind",
+)
+
+// CleanupReport lists what a cleanup run removed, or would remove in a dry run.
+type CleanupReport struct {
+	DryRun         bool      `json:"dry_run"`
+	Cutoff         time.Time `json:"cutoff"`
+	OrphanedTracks []string  `json:"orphaned_tracks"`
+	// TracksRemoved can differ from len(OrphanedTracks) if a track was
+	// re-linked between listing and deletion.
+	TracksRemoved    int64    `json:"tracks_removed"`
+	ExpiredSnapshots []string `json:"expired_snapshots"`
+}
+
+// Cleaner removes data nothing refers to anymore once it has been
+// unreferenced for longer than the grace period.
+type Cleaner struct {
+	orphans        ports.OrphanCleaner
+	backups        *Backups // optional
+	grace          time.Duration
+	snapshotMaxAge time.Duration
+	logger         *slog.Logger
+}
+
+// NewCleaner creates a cleaner. backups may be nil; snapshots older than
+// snapshotMaxAge are only expired when it is positive.
+func NewCleaner(orphans ports.OrphanCleaner, backups *Backups, grace, snapshotMaxAge time.Duration) *Cleaner {
+	return &Cleaner{orphans: orphans, backups: backups, grace: grace, snapshotMaxAge: snapshotMaxAge, logger: logging.Component(nil, "cleanup")}
+}
+
+// Run performs one cleanup pass. With dryRun it only reports.
+func (c *Cleaner) Run(ctx context.Context, dryRun bool) (CleanupReport, error) {
+	now := time.Now().UTC()
+	report := CleanupReport{
+		DryRun:           dryRun,
+		Cutoff:           now.Add(-c.grace),
+		ExpiredSnapshots: []string{},
+	}
+
+	orphans, err := c.orphans.OrphanedTracks(ctx, report.Cutoff)
+	if err != nil {
+		return report, fmt.Errorf("cleanup: %w", err)
+	}
+	report.OrphanedTracks = orphans
+	if !dryRun && len(orphans) > 0 {
+		n, err := c.orphans.DeleteOrphanedTracks(ctx, report.Cutoff)
+		if err != nil {
+			return report, fmt.Errorf("cleanup: %w", err)
+		}
+		report.TracksRemoved = n
+		cleanupRemoved.Add(float64(n), "track")
+	}
+
+	if c.backups != nil && c.snapshotMaxAge > 0 {
+		expired, err := c.backups.Expired(ctx, now.Add(-c.snapshotMaxAge), dryRun)
+		report.ExpiredSnapshots = expired
+		if !dryRun {
+			cleanupRemoved.Add(float64(len(expired)), "snapshot")
+		}
+		if err != nil {
+			return report, fmt.Errorf("cleanup: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+// Task runs a cleanup pass every interval.
+func (c *Cleaner) Task(interval time.Duration, dryRun bool) Task {
+	return Task{Name: "cleanup", Every: interval, Run: func(ctx context.Context) error {
+		report, err := c.Run(ctx, dryRun)
+		if err != nil {
+			return err
+		}
+		c.logger.Info("cleanup finished", "dry_run", dryRun, "orphaned_tracks", len(report.OrphanedTracks), "expired_snapshots", len(report.ExpiredSnapshots))
+		return nil
+	}}
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+var cooccurrencePairs = metrics.NewGaugeVec(
+	"overture_cooccurrence_pairs",
+	"Track pairs in the most recently built co-occurrence model.",
+)
+
+// CooccurrenceReport describes a co-occurrence model build.
+type CooccurrenceReport struct {
+	Playlists int       `json:"playlists"`
+	Pairs     int       `json:"pairs"`
+	BuiltAt   time.Time `json:"built_at"`
+}
+
+// CooccurrenceBuilder rebuilds the model of which tracks are added to
+// playlists together from every stored playlist.
+type CooccurrenceBuilder struct {
+	store        ports.CooccurrenceStore
+	maxNeighbors int
+	logger       *slog.Logger
+}
+
+// NewCooccurrenceBuilder creates a builder keeping up to maxNeighbors
+// co-occurring tracks per track (default 50).
+func NewCooccurrenceBuilder(store ports.CooccurrenceStore, maxNeighbors int) *CooccurrenceBuilder {
+	if maxNeighbors < 1 {
+		maxNeighbors = 50
+	}
+	return &CooccurrenceBuilder{store: store, maxNeighbors: maxNeighbors, logger: logging.Component(nil, "cooccurrence")}
+}
+
+// Run rebuilds the model once.
+func (b *CooccurrenceBuilder) Run(ctx context.Context) (CooccurrenceReport, error) {
+	memberships, err := b.store.PlaylistMemberships(ctx)
+	if err != nil {
+		return CooccurrenceReport{}, fmt.Errorf("cooccurrence: %w", err)
+	}
+	pairs := domain.BuildCooccurrence(memberships, b.maxNeighbors)
+	if err := b.store.ReplaceCooccurrence(ctx, pairs); err != nil {
+		return CooccurrenceReport{}, fmt.Errorf("cooccurrence: %w", err)
+	}
+	cooccurrencePairs.Set(float64(len(pairs)))
+	return CooccurrenceReport{Playlists: len(memberships), Pairs: len(pairs), BuiltAt: time.Now().UTC()}, nil
+}
+
+// Task rebuilds the model every interval.
+func (b *CooccurrenceBuilder) Task(interval time.Duration) Task {
+	return Task{Name: "cooccurrence", Every: interval, Run: func(ctx context.Context) error {
+		report, err := b.Run(ctx)
+		if err != nil {
+			return err
+		}
+		b.logger.Info("co-occurrence model rebuilt", "playlists", report.Playlists, "pairs", report.Pairs)
+		return nil
+	}}
+}
@@ -0,0 +1,183 @@
+package worker
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// ExportStatus reports the state of a data export.
+type ExportStatus string
+
+const (
+	ExportPending ExportStatus = "pending"
+	ExportReady   ExportStatus = "ready"
+	ExportFailed  ExportStatus = "failed"
+)
+
+// exportKey is the blob key of the current archive and exportStateKey that
+// of its build state. Overture is single-user per deployment, so there is
+// exactly one. Keeping the state beside the archive lets every replica, and
+// the process after a restart, see a build in progress or a failed one.
+const (
+	exportKey      = "exports/me/overture-export.zip"
+	exportStateKey = "exports/me/state.json"
+)
+
+// exportStaleAfter is how long a build may stay pending before it is taken
+// to have died with the process running it.
+const exportStaleAfter = 15 * time.Minute
+
+// exportState is the build state stored at exportStateKey.
+type exportState struct {
+	Status    ExportStatus `json:"status"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Exports builds data-export archives on the worker pool and stores them in a
+// blob store for download.
+type Exports struct {
+	build func(ctx context.Context) (domain.Export, error)
+	store ports.BlobStore
+	pool  *Pool
+
+	// mu serializes Request, so concurrent requests queue one build.
+	mu sync.Mutex
+}
+
+// NewExports creates an export manager. build produces the export document.
+func NewExports(build func(ctx context.Context) (domain.Export, error), store ports.BlobStore, pool *Pool) *Exports {
+	return &Exports{build: build, store: store, pool: pool}
+}
+
+// Request queues a new export unless one is already being generated. It
+// returns the resulting status.
+func (e *Exports) Request(ctx context.Context) (ExportStatus, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if status, err := e.Status(ctx); err == nil && status == ExportPending {
+		return ExportPending, nil
+	}
+	// Recorded before the job is queued, so it cannot overwrite the state
+	// the job leaves.
+	if err := e.setState(ctx, ExportPending); err != nil {
+		return "", err
+	}
+	if !e.pool.Submit(Job{Name: "export", Task: e.run}) {
+		_ = e.setState(ctx, ExportFailed)
+		return "", fmt.Errorf("export: worker queue is full")
+	}
+	return ExportPending, nil
+}
+
+// Status reports whether an export is being generated, has failed, or is
+// available to Open. It returns domain.ErrNotFound if none was requested.
+func (e *Exports) Status(ctx context.Context) (ExportStatus, error) {
+	state, err := e.state(ctx)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return "", err
+	}
+	if err == nil {
+		if state.Status == ExportPending && time.Since(state.UpdatedAt) > exportStaleAfter {
+			return ExportFailed, nil
+		}
+		return state.Status, nil
+	}
+
+	// Archives built before the state was stored have none.
+	rc, err := e.store.Get(ctx, exportKey)
+	if errors.Is(err, domain.ErrNotFound) {
+		return "", domain.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("export: %w", err)
+	}
+	rc.Close()
+	return ExportReady, nil
+}
+
+// Open returns the latest archive.
+func (e *Exports) Open(ctx context.Context) (io.ReadCloser, error) {
+	return e.store.Get(ctx, exportKey)
+}
+
+func (e *Exports) state(ctx context.Context) (exportState, error) {
+	rc, err := e.store.Get(ctx, exportStateKey)
+	if errors.Is(err, domain.ErrNotFound) {
+		return exportState{}, err
+	}
+	if err != nil {
+		return exportState{}, fmt.Errorf("export: %w", err)
+	}
+	defer rc.Close()
+	var state exportState
+	if err := json.NewDecoder(rc).Decode(&state); err != nil {
+		return exportState{}, fmt.Errorf("export: failed to decode state: %w", err)
+	}
+	return state, nil
+}
+
+func (e *Exports) setState(ctx context.Context, status ExportStatus) error {
+	body, err := json.Marshal(exportState{Status: status, UpdatedAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	if err := e.store.Put(ctx, exportStateKey, bytes.NewReader(body), int64(len(body))); err != nil {
+		return fmt.Errorf("export: failed to save state: %w", err)
+	}
+	return nil
+}
+
+func (e *Exports) run(ctx context.Context) error {
+	err := e.generate(ctx)
+	status := ExportReady
+	if err != nil {
+		status = ExportFailed
+	}
+	// The job's context may be done by now; the state must still be saved.
+	if serr := e.setState(context.WithoutCancel(ctx), status); serr != nil {
+		return errors.Join(err, serr)
+	}
+	return err
+}
+
+func (e *Exports) generate(ctx context.Context) error {
+	doc, err := e.build(ctx)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     "overture-export.json",
+		Method:   zip.Deflate,
+		Modified: doc.ExportedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("export: failed to encode document: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	if err := e.store.Put(ctx, exportKey, &buf, int64(buf.Len())); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/adapters/blob"
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+// waitForExport polls until the export is no longer pending.
+func waitForExport(t *testing.T, e *Exports) ExportStatus {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := e.Status(context.Background())
+		if err != nil {
+			t.Fatalf("status: %v", err)
+		}
+		if status != ExportPending {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for export")
+	return ""
+}
+
+func TestExports_RetryAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	pool := NewPool(nil, 1, 10)
+	pool.Start(1)
+	defer pool.Stop()
+
+	var fail atomic.Bool
+	fail.Store(true)
+	build := func(context.Context) (domain.Export, error) {
+		if fail.Load() {
+			return domain.Export{}, errors.New("database is locked")
+		}
+		return domain.Export{Format: domain.ExportFormat, ExportedAt: time.Now()}, nil
+	}
+	e := NewExports(build, store, pool)
+
+	if _, err := e.Status(ctx); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found before a request, got %v", err)
+	}
+	if status, err := e.Request(ctx); err != nil || status != ExportPending {
+		t.Fatalf("expected pending, got %q, %v", status, err)
+	}
+	if status := waitForExport(t, e); status != ExportFailed {
+		t.Fatalf("expected failed, got %q", status)
+	}
+
+	// The state outlives the process that built it.
+	if status, err := NewExports(build, store, pool).Status(ctx); err != nil || status != ExportFailed {
+		t.Fatalf("expected a new instance to see the failure, got %q, %v", status, err)
+	}
+
+	fail.Store(false)
+	if _, err := e.Request(ctx); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if status := waitForExport(t, e); status != ExportReady {
+		t.Fatalf("expected ready after a retry, got %q", status)
+	}
+	archive, err := e.Open(ctx)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	archive.Close()
+}
+
+func TestExports_StalePending(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	e := NewExports(nil, store, nil)
+
+	for _, tt := range []struct {
+		age  time.Duration
+		want ExportStatus
+	}{
+		{age: time.Minute, want: ExportPending},
+		{age: exportStaleAfter + time.Minute, want: ExportFailed},
+	} {
+		body, _ := json.Marshal(exportState{Status: ExportPending, UpdatedAt: time.Now().Add(-tt.age)})
+		if err := store.Put(ctx, exportStateKey, bytes.NewReader(body), int64(len(body))); err != nil {
+			t.Fatalf("put state: %v", err)
+		}
+		if status, err := e.Status(ctx); err != nil || status != tt.want {
+			t.Fatalf("pending for %v: expected %q, got %q, %v", tt.age, tt.want, status, err)
+		}
+	}
+}
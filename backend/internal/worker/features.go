@@ -0,0 +1,181 @@
+package worker
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+)
+
+const (
+	// Onsets are detected on frames of frameSize samples every hopSize
+	// samples, about 23ms frames every 12ms at 44.1kHz.
+	frameSize = 1024
+	hopSize   = 512
+
+	minBPM = 60
+	maxBPM = 200
+	// tempoPrior favors tempos near 120 BPM, an octave's deviation
+	// weighing about 0.6, so a beat is not mistaken for every other beat.
+	tempoPriorBPM    = 120
+	tempoPriorOctave = 1.0
+
+	// brightFrequency is the zero-crossing frequency treated as fully
+	// bright: distorted guitars and cymbals, rather than acoustic
+	// instruments and voice.
+	brightFrequency = 3000
+)
+
+// analyzeSamples derives audio features from a preview's interleaved 16-bit
+// stereo samples at rate Hz:
+//
+//   - Energy is the RMS level.
+//   - Tempo is the beat period found by autocorrelating the onset envelope
+//     (the rise in frame loudness).
+//   - Danceability combines how regular that beat is with how close the
+//     tempo is to a comfortable dancing pace.
+//   - Acousticness is the inverse of brightness, estimated from the
+//     zero-crossing rate.
+//   - Valence leans on tempo and brightness, as faster and brighter music
+//     tends to sound happier.
+//
+// Instrumentalness needs vocal detection and is left at 0.
+func analyzeSamples(pcm []int16, rate int) (domain.AudioFeatures, error) {
+	energy, err := previewEnergy(pcm)
+	if err != nil {
+		return domain.AudioFeatures{}, err
+	}
+	if rate <= 0 {
+		return domain.AudioFeatures{}, fmt.Errorf("invalid sample rate %d", rate)
+	}
+
+	mono := downmix(pcm)
+	tempo, regularity := estimateTempo(onsetEnvelope(mono), float64(rate)/hopSize)
+	brightness := clamp01(zeroCrossingFrequency(mono, rate) / brightFrequency)
+
+	features := domain.AudioFeatures{
+		Energy:       energy,
+		Tempo:        tempo,
+		Acousticness: 1 - brightness,
+	}
+	if tempo > 0 {
+		pace := math.Exp(-0.5 * math.Pow((tempo-tempoPriorBPM)/30, 2))
+		features.Danceability = clamp01(0.6*regularity + 0.4*pace)
+		features.Valence = clamp01(0.5*(tempo-minBPM)/(maxBPM-minBPM) + 0.5*brightness)
+	} else {
+		features.Valence = 0.5 * brightness
+	}
+	return features, nil
+}
+
+// downmix averages interleaved stereo samples into mono in [-1, 1].
+func downmix(pcm []int16) []float64 {
+	mono := make([]float64, len(pcm)/2)
+	for i := range mono {
+		mono[i] = (float64(pcm[2*i]) + float64(pcm[2*i+1])) / 2 / 32768
+	}
+	return mono
+}
+
+// onsetEnvelope returns, per frame, how much louder it is than the frame
+// before; decreases count as 0.
+func onsetEnvelope(mono []float64) []float64 {
+	if len(mono) < frameSize {
+		return nil
+	}
+	frames := (len(mono)-frameSize)/hopSize + 1
+	envelope := make([]float64, frames)
+	prev := 0.0
+	for f := 0; f < frames; f++ {
+		var sum float64
+		for _, s := range mono[f*hopSize : f*hopSize+frameSize] {
+			sum += s * s
+		}
+		loudness := math.Log1p(1000 * sum / frameSize)
+		if f > 0 && loudness > prev {
+			envelope[f] = loudness - prev
+		}
+		prev = loudness
+	}
+	return envelope
+}
+
+// estimateTempo finds the beat period in an onset envelope sampled at
+// frameRate frames per second. It returns the tempo in BPM and the
+// regularity of the beat, the autocorrelation at that period relative to
+// the envelope's variance, or zeros when no beat can be found.
+func estimateTempo(envelope []float64, frameRate float64) (float64, float64) {
+	minLag := int(math.Floor(frameRate * 60 / maxBPM))
+	maxLag := int(math.Ceil(frameRate * 60 / minBPM))
+	if minLag < 1 || len(envelope) < 2*maxLag {
+		return 0, 0
+	}
+
+	var mean float64
+	for _, v := range envelope {
+		mean += v
+	}
+	mean /= float64(len(envelope))
+	centered := make([]float64, len(envelope))
+	for i, v := range envelope {
+		centered[i] = v - mean
+	}
+	autocorr := func(lag int) float64 {
+		var sum float64
+		for i := lag; i < len(centered); i++ {
+			sum += centered[i] * centered[i-lag]
+		}
+		return sum / float64(len(centered)-lag)
+	}
+	variance := autocorr(0)
+	if variance <= 0 {
+		return 0, 0
+	}
+
+	ac := make([]float64, maxLag+2)
+	for lag := minLag - 1; lag <= maxLag+1; lag++ {
+		if lag > 0 {
+			ac[lag] = autocorr(lag)
+		}
+	}
+	// A period between two frames splits its peak across both, so each lag
+	// is scored with its neighbors.
+	best, bestScore := 0, 0.0
+	for lag := minLag; lag <= maxLag; lag++ {
+		bpm := 60 * frameRate / float64(lag)
+		prior := math.Exp(-0.5 * math.Pow(math.Log2(bpm/tempoPriorBPM)/tempoPriorOctave, 2))
+		if score := (ac[lag-1] + ac[lag] + ac[lag+1]) * prior; score > bestScore {
+			best, bestScore = lag, score
+		}
+	}
+	if best == 0 {
+		return 0, 0
+	}
+
+	// Refine the period between frames by fitting a parabola to the peak.
+	period := float64(best)
+	if curve := ac[best-1] - 2*ac[best] + ac[best+1]; curve < 0 {
+		period += 0.5 * (ac[best-1] - ac[best+1]) / curve
+	}
+	return 60 * frameRate / period, clamp01(ac[best] / variance)
+}
+
+// zeroCrossingFrequency estimates the dominant frequency in Hz from how
+// often the signal changes sign.
+func zeroCrossingFrequency(mono []float64, rate int) float64 {
+	if len(mono) < 2 {
+		return 0
+	}
+	crossings := 0
+	for i := 1; i < len(mono); i++ {
+		if (mono[i-1] < 0) != (mono[i] < 0) {
+			crossings++
+		}
+	}
+	seconds := float64(len(mono)) / float64(rate)
+	return float64(crossings) / 2 / seconds
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
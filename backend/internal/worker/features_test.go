@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// clickTrack returns seconds of interleaved stereo samples at rate with a
+// short noise burst every beat at bpm.
+func clickTrack(bpm float64, seconds, rate int) []int16 {
+	rng := rand.New(rand.NewSource(1))
+	period := int(float64(rate) * 60 / bpm)
+	pcm := make([]int16, 2*seconds*rate)
+	for i := 0; i < seconds*rate; i++ {
+		var s int16
+		if i%period < rate/50 {
+			s = int16(rng.Intn(40000) - 20000)
+		}
+		pcm[2*i], pcm[2*i+1] = s, s
+	}
+	return pcm
+}
+
+func TestAnalyzeSamples(t *testing.T) {
+	const rate = 44100
+	tests := []struct {
+		name string
+		bpm  float64
+	}{
+		{name: "dance tempo", bpm: 124},
+		{name: "ballad", bpm: 72},
+		{name: "fast", bpm: 150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			features, err := analyzeSamples(clickTrack(tt.bpm, 30, rate), rate)
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			if math.Abs(features.Tempo-tt.bpm) > 2 {
+				t.Fatalf("expected tempo %.0f, got %.1f", tt.bpm, features.Tempo)
+			}
+			for name, v := range map[string]float64{
+				"energy": features.Energy, "danceability": features.Danceability,
+				"valence": features.Valence, "acousticness": features.Acousticness,
+			} {
+				if v < 0 || v > 1 {
+					t.Fatalf("%s out of range: %v", name, v)
+				}
+			}
+		})
+	}
+
+	fast, _ := analyzeSamples(clickTrack(124, 30, rate), rate)
+	slow, _ := analyzeSamples(clickTrack(72, 30, rate), rate)
+	if fast.Danceability <= slow.Danceability || fast.Valence <= slow.Valence {
+		t.Fatalf("expected 124 BPM to be more danceable and positive than 72 BPM, got %+v and %+v", fast, slow)
+	}
+
+	silence, err := analyzeSamples(make([]int16, 2*rate), rate)
+	if err != nil {
+		t.Fatalf("analyze silence: %v", err)
+	}
+	if silence.Tempo != 0 || silence.Danceability != 0 {
+		t.Fatalf("expected no beat in silence, got %+v", silence)
+	}
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/fingerprint"
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+var fingerprintsTotal = metrics.NewCounterVec(
+	"overture_worker_fingerprints_total",
+	"Preview fingerprints by result (new recording, matched existing recording, or failed).",
+	"result",
+)
+
+// fingerprintPageSize is how many stored fingerprints are compared per query.
+const fingerprintPageSize = 500
+
+// SetFingerprints makes the pool fingerprint each analyzed preview, resolve
+// it to a recording and store it, so the same recording can be recognized
+// under other track IDs. Call before Start.
+func (p *Pool) SetFingerprints(store ports.FingerprintStore) {
+	p.fingerprints = store
+}
+
+// storeFingerprint resolves fp to a recording and saves it for trackID.
+// Failures are logged and reported but do not fail the analysis job.
+func (p *Pool) storeFingerprint(ctx context.Context, trackID string, fp []uint32) {
+	recordingID, err := resolveRecording(ctx, p.fingerprints, trackID, fp)
+	if err == nil {
+		err = p.fingerprints.SaveFingerprint(ctx, domain.TrackFingerprint{TrackID: trackID, RecordingID: recordingID, Fingerprint: fp})
+	}
+	if err != nil {
+		fingerprintsTotal.Inc("failed")
+		p.logger.WarnContext(ctx, "failed to store fingerprint", "track_id", trackID, "error", err)
+		p.report(err, map[string]string{"operation": "store_fingerprint", "track_id": trackID})
+		return
+	}
+	if recordingID == trackID {
+		fingerprintsTotal.Inc("new")
+		return
+	}
+	fingerprintsTotal.Inc("matched")
+	p.logger.InfoContext(ctx, "track is a known recording", "track_id", trackID, "recording_id", recordingID)
+}
+
+// resolveRecording compares fp with every stored fingerprint and returns the
+// recording of the closest match at or above fingerprint.DefaultThreshold,
+// or trackID itself when nothing matches.
+func resolveRecording(ctx context.Context, store ports.FingerprintStore, trackID string, fp []uint32) (string, error) {
+	best, recordingID := 0.0, trackID
+	after := ""
+	for {
+		page, err := store.ListFingerprints(ctx, after, fingerprintPageSize)
+		if err != nil {
+			return "", fmt.Errorf("worker: failed to load fingerprints: %w", err)
+		}
+		for _, stored := range page {
+			if stored.TrackID == trackID {
+				continue
+			}
+			if s := fingerprint.Similarity(fp, stored.Fingerprint); s >= fingerprint.DefaultThreshold && s > best {
+				best, recordingID = s, stored.RecordingID
+			}
+		}
+		if len(page) < fingerprintPageSize {
+			return recordingID, nil
+		}
+		after = page[len(page)-1].TrackID
+	}
+}
@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+var (
+	leaderGauge = metrics.NewGaugeVec(
+		"overture_leader",
+		"Whether this instance currently holds the named leadership lease (1) or not (0).",
+		"lease", "instance",
+	)
+	leaderTransitions = metrics.NewCounterVec(
+		"overture_leader_transitions_total",
+		"Number of times this instance gained or lost a leadership lease.",
+		"lease", "instance", "state",
+	)
+)
+
+// Elector campaigns for a named lease so that only one instance runs
+// singleton components such as the scheduler. Leadership is renewed at a
+// third of the lease TTL, so a crashed leader is replaced within one TTL.
+type Elector struct {
+	locker ports.Locker
+	lease  string
+	owner  string
+	ttl    time.Duration
+	leader atomic.Bool
+	logger *slog.Logger
+}
+
+// NewElector creates an Elector for lease, identified as owner.
+func NewElector(locker ports.Locker, lease, owner string, ttl time.Duration) *Elector {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &Elector{locker: locker, lease: lease, owner: owner, ttl: ttl, logger: logging.Component(nil, "leader")}
+}
+
+// IsLeader reports whether this instance held the lease at the last renewal.
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run campaigns until ctx is canceled, then releases the lease if held.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		e.campaign(ctx)
+		select {
+		case <-ctx.Done():
+			if e.leader.Load() {
+				if err := e.locker.Unlock(context.WithoutCancel(ctx), e.lease, e.owner); err != nil {
+					e.logger.Warn("failed to release lease", "lease", e.lease, "error", err)
+				}
+				e.setLeader(false)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Elector) campaign(ctx context.Context) {
+	ok, err := e.locker.TryLock(ctx, e.lease, e.owner, e.ttl)
+	if err != nil {
+		// Step down rather than risk two leaders while the database is unreachable.
+		e.logger.Warn("failed to renew lease", "lease", e.lease, "error", err)
+		ok = false
+	}
+	e.setLeader(ok)
+}
+
+func (e *Elector) setLeader(ok bool) {
+	if e.leader.Swap(ok) != ok {
+		state := "follower"
+		if ok {
+			state = "leader"
+		}
+		e.logger.Info("leadership changed", "owner", e.owner, "state", state, "lease", e.lease)
+		leaderTransitions.Inc(e.lease, e.owner, state)
+	}
+	value := 0.0
+	if ok {
+		value = 1
+	}
+	leaderGauge.Set(value, e.lease, e.owner)
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+)
+
+const outboxBatchSize = 100
+
+// OutboxRelay drains events recorded in the outbox to an event sink. Events
+// are delivered at least once and in commit order: a failed publish stops the
+// batch and is retried on the next poll.
+type OutboxRelay struct {
+	outbox   ports.Outbox
+	sink     ports.EventSink
+	interval time.Duration
+	isLeader func() bool
+	logger   *slog.Logger
+}
+
+// NewOutboxRelay creates a relay that polls outbox every interval. When
+// isLeader is non-nil the relay only publishes while it returns true, so
+// replicas don't deliver the same event concurrently.
+func NewOutboxRelay(outbox ports.Outbox, sink ports.EventSink, interval time.Duration, isLeader func() bool) *OutboxRelay {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &OutboxRelay{outbox: outbox, sink: sink, interval: interval, isLeader: isLeader, logger: logging.Component(nil, "outbox")}
+}
+
+// Run polls the outbox until ctx is canceled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.isLeader != nil && !r.isLeader() {
+				continue
+			}
+			r.drain(ctx)
+		}
+	}
+}
+
+func (r *OutboxRelay) drain(ctx context.Context) {
+	for {
+		events, err := r.outbox.PendingEvents(ctx, outboxBatchSize)
+		if err != nil {
+			r.logger.Warn("failed to load outbox", "error", err)
+			return
+		}
+		for _, event := range events {
+			if err := r.sink.Publish(ctx, event); err != nil {
+				r.logger.Warn("failed to publish event", "event_id", event.ID, "error", err)
+				return
+			}
+			if err := r.outbox.MarkPublished(ctx, event.ID); err != nil {
+				r.logger.Warn("failed to mark event published", "event_id", event.ID, "error", err)
+				return
+			}
+		}
+		if len(events) < outboxBatchSize {
+			return
+		}
+	}
+}
@@ -3,24 +3,110 @@ package worker
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
 	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/backend/internal/tracing"
+	"github.com/ewilliams-labs/overture/metrics"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ewilliams-labs/overture/backend/internal/worker")
+
+var (
+	queueDepth = metrics.NewGaugeVec(
+		"overture_worker_queue_depth",
+		"Jobs waiting in the worker queue.",
+	)
+	queueCapacity = metrics.NewGaugeVec(
+		"overture_worker_queue_capacity",
+		"Size of the worker queue.",
+	)
+	jobsTotal = metrics.NewCounterVec(
+		"overture_worker_jobs_total",
+		"Worker jobs by kind (analysis or task) and result (ok, failed, skipped, dropped).",
+		"kind", "result",
+	)
+	jobDuration = metrics.NewHistogramVec(
+		"overture_worker_job_duration_seconds",
+		"Time spent processing a worker job.",
+		nil,
+		"kind",
+	)
 )
 
 // Job represents a background task for track processing.
 type Job struct {
 	TrackID    string
 	PreviewURL string
+
+	// Task, when set, runs instead of preview analysis so other subsystems
+	// (such as data exports) share the pool's workers and queue. Name
+	// identifies it in logs.
+	Task func(ctx context.Context) error
+	Name string
+
+	// Trace is the span of the request that submitted the job, e.g.
+	// trace.SpanContextFromContext(r.Context()); the job's span joins its
+	// trace.
+	Trace trace.SpanContext
+
+	// id and attempts identify a job claimed from the persistent queue.
+	id       string
+	attempts int
 }
 
+// jobLeaseTTL bounds how long a claimed job stays locked if the instance
+// processing it dies before releasing the lease.
+const jobLeaseTTL = 2 * time.Minute
+
+const (
+	// jobPollInterval is how often the dispatcher looks for persisted jobs
+	// when it has not been woken by a Submit, such as for retries coming
+	// due or jobs submitted by other instances.
+	jobPollInterval = 5 * time.Second
+	// maxJobAttempts bounds how often a persisted job is tried before it
+	// is marked failed; retries back off from jobRetryBackoff, doubling up
+	// to jobRetryMaxBackoff.
+	maxJobAttempts     = 5
+	jobRetryBackoff    = 30 * time.Second
+	jobRetryMaxBackoff = 30 * time.Minute
+)
+
 // Pool manages background workers for async jobs.
 type Pool struct {
-	repo ports.PlaylistRepository
-	jobs chan Job
-	wg   sync.WaitGroup
+	repo     ports.PlaylistRepository
+	jobs     chan Job
+	wg       sync.WaitGroup
+	locker   ports.Locker
+	owner    string
+	reporter ports.ErrorReporter
+
+	// queue, when set, holds analysis jobs instead of the jobs channel;
+	// the dispatcher hands claimed jobs to the workers through claimed.
+	queue      ports.JobQueue
+	claimed    chan Job
+	wake       chan struct{}
+	stop       chan struct{}
+	dispatcher sync.WaitGroup
+
+	fingerprints ports.FingerprintStore
+	analyze      func(previewURL string) (domain.AudioFeatures, error)
+	events       ports.EventSink
+	valence      func(ctx context.Context, trackID string) (float64, bool)
+	preview      func(ctx context.Context, trackID string) (string, bool)
+	logger       *slog.Logger
 }
 
 // NewPool creates a worker pool with the given worker count and queue size.
@@ -31,58 +117,351 @@ func NewPool(repo ports.PlaylistRepository, workers int, queueSize int) *Pool {
 	if queueSize < 1 {
 		queueSize = 1
 	}
-	return &Pool{repo: repo, jobs: make(chan Job, queueSize)}
+	queueCapacity.Set(float64(queueSize))
+	return &Pool{
+		repo:    repo,
+		jobs:    make(chan Job, queueSize),
+		claimed: make(chan Job),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		logger:  logging.Component(nil, "worker"),
+	}
+}
+
+// SetLogger makes the pool log through l instead of the default logger.
+// Call before Start.
+func (p *Pool) SetLogger(l *slog.Logger) {
+	p.logger = logging.Component(l, "worker")
+}
+
+// SetLocker makes the pool claim each job through a shared lease before
+// processing it, so replicas pointed at the same database never analyze the
+// same track concurrently. owner identifies this instance. Call before Start.
+func (p *Pool) SetLocker(locker ports.Locker, owner string) {
+	p.locker = locker
+	p.owner = owner
+}
+
+// SetJobQueue makes Submit persist analysis jobs in queue, from which a
+// dispatcher claims them as workers free up, so queued analysis survives
+// restarts and is shared between replicas. Failed jobs are retried with
+// backoff. Tasks still use the in-memory queue. owner identifies this
+// instance. Call before Start.
+func (p *Pool) SetJobQueue(queue ports.JobQueue, owner string) {
+	p.queue = queue
+	p.owner = owner
+}
+
+// SetErrorReporter forwards failed tasks and persistence errors to an error
+// tracker. Call before Start.
+func (p *Pool) SetErrorReporter(reporter ports.ErrorReporter) {
+	p.reporter = reporter
+}
+
+func (p *Pool) report(err error, fields map[string]string) {
+	if p.reporter != nil {
+		p.reporter.Report(context.Background(), err, fields)
+	}
+}
+
+// SetValenceEstimator makes analysis jobs set a track's valence from
+// estimate, which reports false when it has no estimate, in place of the
+// rough estimate preview analysis makes from tempo and brightness. Call
+// before Start.
+func (p *Pool) SetValenceEstimator(estimate func(ctx context.Context, trackID string) (float64, bool)) {
+	p.valence = estimate
+}
+
+// SetPreviewFallback makes analysis jobs without a preview URL ask resolve
+// for one, which reports false when there is none, instead of being
+// skipped. Call before Start.
+func (p *Pool) SetPreviewFallback(resolve func(ctx context.Context, trackID string) (string, bool)) {
+	p.preview = resolve
+}
+
+// SetAnalyzer makes analysis jobs take a preview's features from analyze
+// instead of downloading and decoding it, as load tests do with synthetic
+// tracks. Previews are then not fingerprinted. Call before Start.
+func (p *Pool) SetAnalyzer(analyze func(previewURL string) (domain.AudioFeatures, error)) {
+	p.analyze = analyze
+}
+
+// SetEventSink makes analysis jobs publish an EventFeaturesUpdated event to
+// sink when they store a track's features, and persisted jobs an
+// EventAnalysisFailed event when they are given up on. Call before Start.
+func (p *Pool) SetEventSink(sink ports.EventSink) {
+	p.events = sink
 }
 
-// Start launches the worker goroutines.
+// Start launches the worker goroutines, and the dispatcher when the pool
+// has a persistent queue.
 func (p *Pool) Start(workers int) {
 	for i := 0; i < workers; i++ {
 		p.wg.Add(1)
 		go func() {
 			defer p.wg.Done()
-			for job := range p.jobs {
-				p.processJob(job)
+			for {
+				select {
+				case job, ok := <-p.jobs:
+					if !ok {
+						return
+					}
+					p.processJob(job)
+				case job := <-p.claimed:
+					p.processJob(job)
+				}
 			}
 		}()
 	}
+	if p.queue != nil {
+		p.dispatcher.Add(1)
+		go p.dispatch()
+	}
 }
 
-// Stop waits for workers to finish after closing the queue.
+// Stop stops the dispatcher and waits for workers to finish after closing
+// the queue. Persisted jobs that were not finished run after a restart.
 func (p *Pool) Stop() {
+	close(p.stop)
+	p.dispatcher.Wait()
 	close(p.jobs)
 	p.wg.Wait()
 }
 
-// Submit queues a job without blocking.
-func (p *Pool) Submit(job Job) {
+// dispatch claims persisted jobs and hands each to a free worker until Stop.
+func (p *Pool) dispatch() {
+	defer p.dispatcher.Done()
+	ctx := context.Background()
+	for {
+		job, ok, err := p.queue.ClaimJob(ctx, p.owner, jobLeaseTTL)
+		if err != nil {
+			p.logger.Warn("failed to claim job", "error", err)
+		}
+		if ok {
+			select {
+			case p.claimed <- Job{TrackID: job.TrackID, PreviewURL: job.PreviewURL, Trace: tracing.ParseTraceParent(job.TraceParent), id: job.ID, attempts: job.Attempts}:
+				continue
+			case <-p.stop:
+				// The lease lapses and the job is claimed again later.
+				return
+			}
+		}
+		select {
+		case <-p.stop:
+			return
+		case <-p.wake:
+		case <-time.After(jobPollInterval):
+		}
+	}
+}
+
+// Submit queues a job without blocking. It reports false if the queue is
+// full, or the persistent queue failed, and the job was dropped.
+func (p *Pool) Submit(job Job) bool {
+	_, ok := p.SubmitTracked(job)
+	return ok
+}
+
+// SubmitTracked is Submit that also returns the ID of the persisted job, or
+// of the job already queued for the same track, so its progress can be
+// looked up. The ID is empty for tasks and without a persistent queue.
+func (p *Pool) SubmitTracked(job Job) (string, bool) {
+	if p.queue != nil && job.Task == nil {
+		persisted, err := p.queue.EnqueueJob(context.Background(), domain.Job{
+			TrackID:     job.TrackID,
+			PreviewURL:  job.PreviewURL,
+			TraceParent: tracing.TraceParent(job.Trace),
+		})
+		if err != nil {
+			p.logger.Warn("failed to persist job", "track_id", job.TrackID, "error", err)
+			p.report(err, map[string]string{"operation": "enqueue_job", "track_id": job.TrackID})
+			jobsTotal.Inc(job.kind(), "dropped")
+			return "", false
+		}
+		select {
+		case p.wake <- struct{}{}:
+		default:
+		}
+		return persisted.ID, true
+	}
+
 	select {
 	case p.jobs <- job:
+		queueDepth.Set(float64(len(p.jobs)))
+		return "", true
 	default:
-		log.Printf("WARN worker: dropping job for %s", job.TrackID)
+		jobsTotal.Inc(job.kind(), "dropped")
+		if job.Task != nil {
+			p.logger.Warn("queue full, dropping task", "task", job.Name)
+		} else {
+			p.logger.Warn("queue full, dropping job", "track_id", job.TrackID)
+		}
+		return "", false
 	}
 }
 
+// kind labels job metrics.
+func (j Job) kind() string {
+	if j.Task != nil {
+		return "task"
+	}
+	return "analysis"
+}
+
 func (p *Pool) processJob(job Job) {
-	if job.PreviewURL == "" {
-		log.Printf("⚠️ No preview URL for Track %s. Skipping analysis.", job.TrackID)
+	queueDepth.Set(float64(len(p.jobs)))
+	start := time.Now()
+	ctx, span := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), job.Trace), "worker."+job.kind(),
+		trace.WithAttributes(attribute.String("overture.track_id", job.TrackID), attribute.String("overture.task", job.Name)))
+	result, err := p.runJob(ctx, job)
+	span.SetAttributes(attribute.String("overture.result", result))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	jobDuration.Observe(time.Since(start).Seconds(), job.kind())
+	jobsTotal.Inc(job.kind(), result)
+	if job.id != "" {
+		p.settle(ctx, job, err)
+	}
+}
+
+// settle records the outcome of a persisted job: done unless it failed, in
+// which case it is retried with backoff until maxJobAttempts.
+func (p *Pool) settle(ctx context.Context, job Job, cause error) {
+	if cause == nil {
+		if err := p.queue.CompleteJob(ctx, job.id, p.owner); err != nil {
+			p.warnSettle(ctx, job, "failed to complete job", err)
+		}
 		return
 	}
 
-	log.Printf("🎵 Analyzing Track %s...", job.TrackID)
-	energy, err := AnalyzePreviewFunc(job.PreviewURL)
-	if err != nil {
-		log.Printf("WARN worker: analysis failed for %s: %v", job.TrackID, err)
+	var retryAt time.Time
+	if job.attempts < maxJobAttempts {
+		backoff := jobRetryBackoff << (job.attempts - 1)
+		if backoff <= 0 || backoff > jobRetryMaxBackoff {
+			backoff = jobRetryMaxBackoff
+		}
+		retryAt = time.Now().Add(backoff)
+	} else {
+		p.logger.WarnContext(ctx, "giving up on job", "job_id", job.id, "track_id", job.TrackID, "attempts", job.attempts)
+		p.publish(domain.EventAnalysisFailed, domain.AnalysisFailedPayload{TrackID: job.TrackID, Error: cause.Error(), Attempts: job.attempts})
+	}
+	if err := p.queue.FailJob(ctx, job.id, p.owner, cause.Error(), retryAt); err != nil {
+		p.warnSettle(ctx, job, "failed to record job failure", err)
+	}
+}
+
+// warnSettle logs why settling job failed. A lease lost to another
+// instance, which runs the job again and settles it itself, is expected
+// after a stall and only noted.
+func (p *Pool) warnSettle(ctx context.Context, job Job, msg string, err error) {
+	if errors.Is(err, domain.ErrJobLeaseLost) {
+		p.logger.InfoContext(ctx, "job lease lost to another instance", "job_id", job.id, "track_id", job.TrackID)
 		return
 	}
-	log.Printf("✅ Analysis complete: Energy=%.2f", energy)
+	p.logger.WarnContext(ctx, msg, "job_id", job.id, "error", err)
+}
+
+// runJob processes job within ctx, which carries its span, and returns its
+// metrics result, and the cause when the result is failed.
+func (p *Pool) runJob(ctx context.Context, job Job) (string, error) {
+	if job.Task != nil {
+		if err := job.Task(ctx); err != nil {
+			p.logger.WarnContext(ctx, "task failed", "task", job.Name, "error", err)
+			p.report(err, map[string]string{"operation": "task", "task": job.Name})
+			return "failed", err
+		}
+		return "ok", nil
+	}
+
+	if job.PreviewURL == "" && p.preview == nil {
+		p.logger.InfoContext(ctx, "no preview URL, skipping analysis", "track_id", job.TrackID)
+		return "skipped", nil
+	}
+
+	if p.locker != nil {
+		key := "analysis:" + job.TrackID
+		ok, err := p.locker.TryLock(ctx, key, p.owner, jobLeaseTTL)
+		if err != nil {
+			p.logger.WarnContext(ctx, "failed to lock track for analysis", "track_id", job.TrackID, "error", err)
+			return "failed", err
+		}
+		if !ok {
+			p.logger.InfoContext(ctx, "track is being analyzed by another instance, skipping", "track_id", job.TrackID)
+			return "skipped", nil
+		}
+		defer func() {
+			if err := p.locker.Unlock(ctx, key, p.owner); err != nil {
+				p.logger.WarnContext(ctx, "failed to unlock track", "track_id", job.TrackID, "error", err)
+			}
+		}()
+	}
+
+	if job.PreviewURL == "" {
+		previewURL, ok := p.preview(ctx, job.TrackID)
+		if !ok {
+			p.logger.InfoContext(ctx, "no preview URL from any source, skipping analysis", "track_id", job.TrackID)
+			return "skipped", nil
+		}
+		p.logger.DebugContext(ctx, "using fallback preview", "track_id", job.TrackID)
+		job.PreviewURL = previewURL
+	}
+
+	p.logger.DebugContext(ctx, "analyzing track", "track_id", job.TrackID)
+	var analysis PreviewAnalysis
+	var err error
+	switch {
+	case p.analyze != nil:
+		analysis.Features, err = p.analyze(job.PreviewURL)
+	case p.fingerprints != nil:
+		analysis, err = FingerprintPreviewFunc(job.PreviewURL)
+	default:
+		analysis.Features, err = AnalyzePreviewFunc(job.PreviewURL)
+	}
+	if err != nil {
+		p.logger.WarnContext(ctx, "analysis failed", "track_id", job.TrackID, "error", err)
+		return "failed", fmt.Errorf("analysis failed: %w", err)
+	}
+	features := analysis.Features
+	p.logger.DebugContext(ctx, "analysis complete", "track_id", job.TrackID, "energy", features.Energy, "tempo", features.Tempo, "danceability", features.Danceability)
+
+	// Lyrics say more about valence than the audio heuristic does.
+	if p.valence != nil {
+		if v, ok := p.valence(ctx, job.TrackID); ok {
+			features.Valence = v
+		}
+	}
+	if err := p.repo.UpdateTrackFeatures(ctx, job.TrackID, features); err != nil {
+		p.logger.WarnContext(ctx, "failed to update track features", "track_id", job.TrackID, "error", err)
+		p.report(err, map[string]string{"operation": "update_track_features", "track_id": job.TrackID})
+		return "failed", err
+	}
+	p.logger.InfoContext(ctx, "track features updated", "track_id", job.TrackID, "energy", features.Energy, "valence", features.Valence)
+	if len(analysis.Fingerprint) > 0 {
+		p.storeFingerprint(ctx, job.TrackID, analysis.Fingerprint)
+	}
+	p.publish(domain.EventFeaturesUpdated, domain.FeaturesUpdatedPayload{TrackID: job.TrackID, Features: features})
+	return "ok", nil
+}
 
-	features := domain.AudioFeatures{
-		Energy:  energy,
-		Valence: 0,
+// publish sends an event about a track to the event sink, if any.
+func (p *Pool) publish(eventType string, payload any) {
+	if p.events == nil {
+		return
 	}
-	if err := p.repo.UpdateTrackFeatures(context.Background(), job.TrackID, features); err != nil {
-		log.Printf("WARN worker: failed to update track %s: %v", job.TrackID, err)
+	raw, err := json.Marshal(payload)
+	if err != nil {
 		return
 	}
-	log.Printf("💾 Updated Track %s with analyzed features (Energy: %.2f).", job.TrackID, energy)
+	event := domain.Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		Payload:    raw,
+		OccurredAt: time.Now().UTC(),
+	}
+	if err := p.events.Publish(context.Background(), event); err != nil {
+		p.logger.Warn("failed to publish event", "type", eventType, "error", err)
+	}
 }
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/core/domain"
+	"github.com/ewilliams-labs/overture/backend/internal/core/ports"
+)
+
+// featuresRepo records the features stored by analysis jobs.
+type featuresRepo struct {
+	ports.PlaylistRepository
+	stored chan domain.AudioFeatures
+}
+
+func (r *featuresRepo) UpdateTrackFeatures(_ context.Context, _ string, features domain.AudioFeatures) error {
+	r.stored <- features
+	return nil
+}
+
+func TestPool_SetAnalyzer(t *testing.T) {
+	orig := AnalyzePreviewFunc
+	AnalyzePreviewFunc = func(string) (domain.AudioFeatures, error) {
+		return domain.AudioFeatures{}, errors.New("expected the pool's analyzer to be used")
+	}
+	defer func() { AnalyzePreviewFunc = orig }()
+
+	repo := &featuresRepo{stored: make(chan domain.AudioFeatures, 1)}
+	pool := NewPool(repo, 1, 10)
+	var analyzed string
+	pool.SetAnalyzer(func(previewURL string) (domain.AudioFeatures, error) {
+		analyzed = previewURL
+		return domain.AudioFeatures{Energy: 0.8, Tempo: 124}, nil
+	})
+	pool.Start(1)
+	defer pool.Stop()
+
+	if !pool.Submit(Job{TrackID: "t1", PreviewURL: "synthetic://t1"}) {
+		t.Fatal("expected the job to be queued")
+	}
+	select {
+	case features := <-repo.stored:
+		if features.Energy != 0.8 || features.Tempo != 124 {
+			t.Fatalf("expected the analyzer's features, got %+v", features)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for analysis")
+	}
+	if analyzed != "synthetic://t1" {
+		t.Fatalf("expected the preview URL to be analyzed, got %q", analyzed)
+	}
+}
@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ewilliams-labs/overture/backend/internal/logging"
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+var (
+	scheduledRuns = metrics.NewCounterVec(
+		"overture_scheduled_task_runs_total",
+		"Scheduled task runs, by task and result (ok, failed, skipped when another instance leads).",
+		"task", "result",
+	)
+	scheduledDuration = metrics.NewHistogramVec(
+		"overture_scheduled_task_duration_seconds",
+		"How long scheduled task runs took, by task.",
+		[]float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 3600},
+		"task",
+	)
+	scheduledLastSuccess = metrics.NewGaugeVec(
+		"overture_scheduled_task_last_success_timestamp_seconds",
+		"Unix time of each scheduled task's last successful run.",
+		"task",
+	)
+)
+
+// Task is recurring background work run by a Scheduler.
+type Task struct {
+	// Name labels the task's logs and metrics.
+	Name string
+	// Every is the time between runs, the first one interval after the
+	// scheduler starts; zero disables the task.
+	Every time.Duration
+	Run   func(ctx context.Context) error
+}
+
+// Scheduler runs recurring tasks, each on its own interval. Runs of a task
+// never overlap: a run that outlasts the interval delays the next.
+type Scheduler struct {
+	isLeader func() bool
+	logger   *slog.Logger
+
+	mu    sync.Mutex
+	tasks []Task
+}
+
+// NewScheduler creates a scheduler. When isLeader is non-nil, tasks only
+// run while it reports true, so replicas sharing a store don't repeat them.
+func NewScheduler(isLeader func() bool) *Scheduler {
+	return &Scheduler{isLeader: isLeader, logger: logging.Component(nil, "scheduler")}
+}
+
+// Add registers task unless it is disabled. Tasks added after Run starts
+// are not run.
+func (s *Scheduler) Add(task Task) {
+	if task.Every <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = append(s.tasks, task)
+}
+
+// Tasks returns the names of the enabled tasks.
+func (s *Scheduler) Tasks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, len(s.tasks))
+	for i, t := range s.tasks {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Run runs the tasks until ctx is canceled, then waits for runs in
+// progress to return.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	tasks := append([]Task(nil), s.tasks...)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, task)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, task Task) {
+	ticker := time.NewTicker(task.Every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, task)
+		}
+	}
+}
+
+// run runs task once, unless another instance leads, and records the
+// outcome.
+func (s *Scheduler) run(ctx context.Context, task Task) {
+	if s.isLeader != nil && !s.isLeader() {
+		scheduledRuns.Inc(task.Name, "skipped")
+		return
+	}
+	start := time.Now()
+	err := task.Run(ctx)
+	scheduledDuration.Observe(time.Since(start).Seconds(), task.Name)
+	if err != nil {
+		scheduledRuns.Inc(task.Name, "failed")
+		s.logger.Warn("scheduled task failed", "task", task.Name, "error", err)
+		return
+	}
+	scheduledRuns.Inc(task.Name, "ok")
+	scheduledLastSuccess.Set(float64(time.Now().Unix()), task.Name)
+}
@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Add(t *testing.T) {
+	s := NewScheduler(nil)
+	noop := func(context.Context) error { return nil }
+	s.Add(Task{Name: "enabled", Every: time.Minute, Run: noop})
+	s.Add(Task{Name: "disabled", Run: noop})
+
+	if got, want := s.Tasks(), []string{"enabled"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected tasks %v, got %v", want, got)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	var runs atomic.Int32
+	s := NewScheduler(nil)
+	s.Add(Task{Name: "test-repeat", Every: 5 * time.Millisecond, Run: func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if n := runs.Load(); n < 3 {
+		t.Fatalf("expected at least 3 runs, got %d", n)
+	}
+	if ok := scheduledRuns.Value("test-repeat", "ok"); ok < 3 {
+		t.Fatalf("expected at least 3 ok runs recorded, got %v", ok)
+	}
+	if scheduledLastSuccess.Value("test-repeat") == 0 {
+		t.Fatal("expected the last success time to be recorded")
+	}
+}
+
+func TestScheduler_RunOutcomes(t *testing.T) {
+	var leader atomic.Bool
+	s := NewScheduler(leader.Load)
+	var runs int
+	task := Task{Name: "test-outcomes", Every: time.Minute, Run: func(context.Context) error {
+		runs++
+		return errors.New("boom")
+	}}
+
+	s.run(context.Background(), task)
+	if runs != 0 || scheduledRuns.Value("test-outcomes", "skipped") != 1 {
+		t.Fatalf("expected a follower to skip the run, got %d runs", runs)
+	}
+
+	leader.Store(true)
+	s.run(context.Background(), task)
+	if runs != 1 || scheduledRuns.Value("test-outcomes", "failed") != 1 {
+		t.Fatalf("expected the leader's run to be recorded as failed, got %d runs", runs)
+	}
+	if scheduledLastSuccess.Value("test-outcomes") != 0 {
+		t.Fatal("expected no success recorded for a failed run")
+	}
+}
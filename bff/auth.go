@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"golang.org/x/oauth2"
+)
+
+// spotifyScopes covers what the React client does through the BFF: reading
+// the profile and playlists, editing playlists and controlling playback.
+var spotifyScopes = []string{
+	"user-read-private",
+	"playlist-read-private",
+	"playlist-modify-private",
+	"playlist-modify-public",
+	"user-read-playback-state",
+	"user-modify-playback-state",
+}
+
+// spotifyAuth signs browsers in with Spotify (authorization code flow with
+// PKCE) and keeps their tokens in their session.
+type spotifyAuth struct {
+	config   *oauth2.Config
+	sessions *sessions
+	// frontendURL is where the browser lands after logging in.
+	frontendURL string
+}
+
+// newSpotifyAuth configures login from SPOTIFY_CLIENT_ID,
+// SPOTIFY_CLIENT_SECRET and SPOTIFY_REDIRECT_URL, which must be the BFF's
+// /auth/callback as registered with Spotify. It returns nil when no client
+// ID is set.
+func newSpotifyAuth(sessions *sessions) *spotifyAuth {
+	clientID := os.Getenv("SPOTIFY_CLIENT_ID")
+	if clientID == "" {
+		return nil
+	}
+	accounts := getEnv("SPOTIFY_ACCOUNTS_URL", "https://accounts.spotify.com")
+	return &spotifyAuth{
+		config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: os.Getenv("SPOTIFY_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("SPOTIFY_REDIRECT_URL"),
+			Scopes:       spotifyScopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  accounts + "/authorize",
+				TokenURL: accounts + "/api/token", // #nosec G101 -- Public Spotify OAuth endpoint, not a secret
+			},
+		},
+		sessions:    sessions,
+		frontendURL: getEnv("FRONTEND_URL", "/"),
+	}
+}
+
+// routes registers the login endpoints on mux.
+func (a *spotifyAuth) routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /auth/login", a.login)
+	mux.HandleFunc("GET /auth/callback", a.callback)
+	mux.HandleFunc("POST /auth/logout", a.logout)
+	mux.HandleFunc("GET /auth/session", a.status)
+}
+
+// login starts a Spotify login by redirecting to the consent page.
+func (a *spotifyAuth) login(w http.ResponseWriter, r *http.Request) {
+	s, _ := a.sessions.load(r)
+	state, err := newSessionID()
+	if err != nil {
+		http.Error(w, "failed to start login", http.StatusInternalServerError)
+		return
+	}
+	s.State = state
+	s.Verifier = oauth2.GenerateVerifier()
+	if err := a.sessions.issue(w, r, &s); err != nil {
+		slog.ErrorContext(r.Context(), "failed to save session", "error", err)
+		http.Error(w, "failed to start login", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, a.config.AuthCodeURL(state, oauth2.S256ChallengeOption(s.Verifier)), http.StatusFound)
+}
+
+// callback completes a login: it checks the state against the session,
+// exchanges the code and stores the token in a fresh session.
+func (a *spotifyAuth) callback(w http.ResponseWriter, r *http.Request) {
+	s, ok := a.sessions.load(r)
+	query := r.URL.Query()
+	if !ok || s.State == "" || subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(s.State)) != 1 {
+		http.Error(w, "login expired or was started elsewhere", http.StatusBadRequest)
+		return
+	}
+	if reason := query.Get("error"); reason != "" {
+		// The user declined; tell the client without failing the page.
+		http.Redirect(w, r, a.frontendURL+"?login=denied", http.StatusFound)
+		return
+	}
+
+	token, err := a.config.Exchange(r.Context(), query.Get("code"), oauth2.VerifierOption(s.Verifier))
+	if err != nil {
+		slog.WarnContext(r.Context(), "Spotify code exchange failed", "error", err)
+		http.Error(w, "failed to complete login", http.StatusBadGateway)
+		return
+	}
+	s.State, s.Verifier, s.Token = "", "", token
+	if err := a.sessions.rotate(w, r, &s); err != nil {
+		slog.ErrorContext(r.Context(), "failed to save session", "error", err)
+		http.Error(w, "failed to complete login", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, a.frontendURL, http.StatusFound)
+}
+
+// logout ends the session.
+func (a *spotifyAuth) logout(w http.ResponseWriter, r *http.Request) {
+	if err := a.sessions.clear(w, r); err != nil {
+		slog.ErrorContext(r.Context(), "failed to delete session", "error", err)
+		http.Error(w, "failed to log out", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// status reports whether the browser is logged in, without exposing the
+// token itself.
+func (a *spotifyAuth) status(w http.ResponseWriter, r *http.Request) {
+	s, ok := a.sessions.load(r)
+	body := map[string]any{"authenticated": ok && s.Token != nil}
+	if ok && s.Token != nil {
+		body["since"] = s.CreatedAt
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// require lets only logged-in browsers through to next, answering 401
+// otherwise. Each request refreshes the session's token when it has
+// expired, so a login revoked at Spotify ends access to the backend too.
+func (a *spotifyAuth) require(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := a.sessions.load(r)
+		if !ok || s.Token == nil {
+			writeProxyError(w, http.StatusUnauthorized, "login required")
+			return
+		}
+		if _, err := a.token(r.Context(), &s); err != nil {
+			var rerr *oauth2.RetrieveError
+			if errors.As(err, &rerr) {
+				writeProxyError(w, http.StatusUnauthorized, "login expired")
+				return
+			}
+			slog.WarnContext(r.Context(), "failed to refresh Spotify token", "error", err)
+			writeProxyError(w, http.StatusBadGateway, "failed to refresh login")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// token returns the session's Spotify access token, refreshing it when it
+// has expired and saving the refreshed token back to the session.
+func (a *spotifyAuth) token(ctx context.Context, s *session) (*oauth2.Token, error) {
+	if s.Token == nil {
+		return nil, fmt.Errorf("session is not logged in")
+	}
+	token, err := a.config.TokenSource(ctx, s.Token).Token()
+	if err != nil {
+		return nil, fmt.Errorf("refresh spotify token: %w", err)
+	}
+	if token.AccessToken != s.Token.AccessToken {
+		s.Token = token
+		if err := a.sessions.store.save(ctx, *s, a.sessions.ttl); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func newTestAuth(tokenURL string) *spotifyAuth {
+	return &spotifyAuth{
+		config: &oauth2.Config{
+			ClientID: "client",
+			Endpoint: oauth2.Endpoint{AuthURL: "https://accounts.example/authorize", TokenURL: tokenURL},
+		},
+		sessions:    newTestSessions(),
+		frontendURL: "/",
+	}
+}
+
+// loggedIn issues a session holding token and returns a request carrying
+// its cookie.
+func loggedIn(t *testing.T, a *spotifyAuth, s session) *http.Request {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	if err := a.sessions.issue(rec, httptest.NewRequest(http.MethodGet, "/", nil), &s); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	return withCookie(t, rec)
+}
+
+func TestSpotifyAuth_CallbackState(t *testing.T) {
+	a := newTestAuth("http://127.0.0.1:0/token")
+	req := loggedIn(t, a, session{State: "expected", Verifier: "v"})
+
+	tests := []struct {
+		name  string
+		req   *http.Request
+		query string
+	}{
+		{name: "state mismatch", req: req, query: "?state=forged&code=c"},
+		{name: "missing state", req: req, query: "?code=c"},
+		{name: "no session", req: httptest.NewRequest(http.MethodGet, "/", nil), query: "?state=expected&code=c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.req.Clone(context.Background())
+			r.URL.RawQuery = tt.query[1:]
+			rec := httptest.NewRecorder()
+			a.callback(rec, r)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestSpotifyAuth_Require(t *testing.T) {
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	defer tokens.Close()
+	a := newTestAuth(tokens.URL)
+	next := a.require(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{name: "no session", req: httptest.NewRequest(http.MethodGet, "/api/queue", nil), want: http.StatusUnauthorized},
+		{name: "login in progress", req: loggedIn(t, a, session{State: "st"}), want: http.StatusUnauthorized},
+		{
+			name: "logged in",
+			req:  loggedIn(t, a, session{Token: &oauth2.Token{AccessToken: "at", Expiry: time.Now().Add(time.Hour)}}),
+			want: http.StatusNoContent,
+		},
+		{
+			name: "revoked login",
+			req:  loggedIn(t, a, session{Token: &oauth2.Token{AccessToken: "at", RefreshToken: "rt", Expiry: time.Now().Add(-time.Hour)}}),
+			want: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			next.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// unixSocketPrefix marks BACKEND_URL values that point at the backend's unix
+// domain socket, e.g. unix:///run/overture/backend.sock.
+const unixSocketPrefix = "unix://"
+
+// backendTarget knows how to reach the backend, over TCP or a unix socket.
+type backendTarget struct {
+	baseURL   string
+	transport http.RoundTripper
+}
+
+// newBackendTarget parses BACKEND_URL. For unix sockets, requests are sent to
+// a placeholder host and dialed over the socket instead.
+func newBackendTarget(backendURL string) backendTarget {
+	path, ok := strings.CutPrefix(backendURL, unixSocketPrefix)
+	if !ok {
+		return backendTarget{
+			baseURL:   strings.TrimRight(backendURL, "/"),
+			transport: tracedTransport(requestIDTransport{http.DefaultTransport}),
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	}
+	return backendTarget{baseURL: "http://backend", transport: tracedTransport(requestIDTransport{transport})}
+}
+
+// client returns an HTTP client for the backend with the given timeout.
+func (b backendTarget) client(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: b.transport}
+}
+
+// url joins a backend path onto the base URL.
+func (b backendTarget) url(path string) string {
+	return b.baseURL + path
+}
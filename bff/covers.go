@@ -0,0 +1,457 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decode GIF covers
+	"image/jpeg"
+	_ "image/png" // decode PNG covers
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minCoverSize and maxCoverSize bound the requested width and height.
+	minCoverSize = 16
+	maxCoverSize = 1024
+	// maxCoverBytes caps the upstream image download.
+	maxCoverBytes = 10 << 20
+	// maxCoverPixels refuses source images that would take too much memory
+	// to decode.
+	maxCoverPixels = 25_000_000
+	// coverQuality is the JPEG quality resized covers are encoded with.
+	coverQuality = 82
+	// defaultCoverCacheMB bounds the disk cache unless COVER_CACHE_MAX_MB
+	// is set.
+	defaultCoverCacheMB = 512
+	coverTimeout        = 10 * time.Second
+)
+
+// defaultCoverHosts are the image CDNs of the catalogs Overture uses.
+var defaultCoverHosts = []string{
+	"i.scdn.co", "mosaic.scdn.co", "image-cdn-ak.spotifycdn.com", "image-cdn-fa.spotifycdn.com",
+	"is1-ssl.mzstatic.com", "e-cdns-images.dzcdn.net",
+}
+
+// coverSettings configure the cover art proxy.
+type coverSettings struct {
+	// hosts are the only hosts covers are fetched from.
+	hosts map[string]bool
+	// cacheDir keeps up to cacheMaxBytes of resized covers on disk; empty
+	// keeps cacheSize of them in memory.
+	cacheDir      string
+	cacheMaxBytes int64
+	cacheSize     int
+}
+
+// loadCoverSettings reads COVER_HOSTS, COVER_CACHE_DIR, COVER_CACHE_MAX_MB
+// and COVER_CACHE_SIZE.
+func loadCoverSettings() (coverSettings, error) {
+	s := coverSettings{
+		hosts:         make(map[string]bool),
+		cacheDir:      os.Getenv("COVER_CACHE_DIR"),
+		cacheMaxBytes: defaultCoverCacheMB << 20,
+		cacheSize:     500,
+	}
+	hosts := defaultCoverHosts
+	if v := os.Getenv("COVER_HOSTS"); v != "" {
+		hosts = strings.Split(v, ",")
+	}
+	for _, h := range hosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			s.hosts[h] = true
+		}
+	}
+	if v := os.Getenv("COVER_CACHE_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return coverSettings{}, fmt.Errorf("invalid COVER_CACHE_SIZE %q", v)
+		}
+		s.cacheSize = n
+	}
+	if v := os.Getenv("COVER_CACHE_MAX_MB"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return coverSettings{}, fmt.Errorf("invalid COVER_CACHE_MAX_MB %q", v)
+		}
+		s.cacheMaxBytes = int64(n) << 20
+	}
+	return s, nil
+}
+
+// coverCache keeps encoded covers by key.
+type coverCache interface {
+	get(key string) ([]byte, bool)
+	put(key string, data []byte)
+}
+
+// newCoverCache opens the cache named by settings.
+func newCoverCache(settings coverSettings) (coverCache, error) {
+	if settings.cacheDir == "" {
+		return &memoryCoverCache{max: settings.cacheSize, entries: make(map[string]*list.Element), order: list.New()}, nil
+	}
+	if err := os.MkdirAll(settings.cacheDir, 0o750); err != nil {
+		return nil, fmt.Errorf("create COVER_CACHE_DIR: %w", err)
+	}
+	return openDiskCoverCache(settings.cacheDir, settings.cacheMaxBytes)
+}
+
+// memoryCoverCache is an LRU of up to max covers.
+type memoryCoverCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type coverEntry struct {
+	key  string
+	data []byte
+}
+
+func (c *memoryCoverCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*coverEntry).data, true
+}
+
+func (c *memoryCoverCache) put(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*coverEntry).data = data
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&coverEntry{key: key, data: data})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*coverEntry).key)
+	}
+}
+
+// diskCoverCache keeps one file per cover in a directory, evicting the
+// least recently used once they add up to more than max bytes. Callers pick
+// the URL and size of a cover, so without a bound they could fill the disk.
+type diskCoverCache struct {
+	dir     string
+	max     int64
+	mu      sync.Mutex
+	size    int64
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type diskCoverEntry struct {
+	key  string
+	size int64
+}
+
+// openDiskCoverCache indexes the covers already in dir, oldest first by
+// modification time, and trims them to maxBytes.
+func openDiskCoverCache(dir string, maxBytes int64) (*diskCoverCache, error) {
+	c := &diskCoverCache{dir: dir, max: maxBytes, entries: make(map[string]*list.Element), order: list.New()}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read COVER_CACHE_DIR: %w", err)
+	}
+	type cached struct {
+		key     string
+		size    int64
+		modTime time.Time
+	}
+	var found []cached
+	for _, f := range files {
+		key, ok := strings.CutSuffix(f.Name(), ".cover")
+		if !ok || !f.Type().IsRegular() {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		found = append(found, cached{key: key, size: info.Size(), modTime: info.ModTime()})
+	}
+	slices.SortFunc(found, func(a, b cached) int { return a.modTime.Compare(b.modTime) })
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range found {
+		c.entries[f.key] = c.order.PushFront(&diskCoverEntry{key: f.key, size: f.size})
+		c.size += f.size
+	}
+	c.evict()
+	return c, nil
+}
+
+func (c *diskCoverCache) path(key string) string {
+	return filepath.Join(c.dir, key+".cover")
+}
+
+func (c *diskCoverCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	el, ok := c.entries[key]
+	if ok {
+		c.order.MoveToFront(el)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	// Keeps the order across restarts.
+	now := time.Now()
+	_ = os.Chtimes(c.path(key), now, now)
+	return data, true
+}
+
+func (c *diskCoverCache) put(key string, data []byte) {
+	// Written under a temporary name so readers never see half a file.
+	tmp, err := os.CreateTemp(c.dir, key+"-*.tmp")
+	if err != nil {
+		slog.Warn("failed to cache cover", "error", err)
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		slog.Warn("failed to cache cover", "error", err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*diskCoverEntry)
+		c.size += int64(len(data)) - entry.size
+		entry.size = int64(len(data))
+		c.order.MoveToFront(el)
+	} else {
+		c.entries[key] = c.order.PushFront(&diskCoverEntry{key: key, size: int64(len(data))})
+		c.size += int64(len(data))
+	}
+	c.evict()
+}
+
+// evict removes the least recently used covers until the cache fits. The
+// caller holds c.mu.
+func (c *diskCoverCache) evict() {
+	for c.size > c.max && c.order.Len() > 0 {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*diskCoverEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= entry.size
+		if err := os.Remove(c.path(entry.key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("failed to evict cover", "error", err)
+		}
+	}
+}
+
+// coverProxy serves GET /images/cover?url=...&w=...&h=..., fetching the
+// album cover at url from an allowed host and resizing it. With w and h the
+// cover is cropped to fill that box from its center; with one of them it
+// keeps its aspect ratio. Results are WebP for clients that accept it and
+// JPEG otherwise, and cached by url, size and format.
+type coverProxy struct {
+	hosts  map[string]bool
+	cache  coverCache
+	client *http.Client
+}
+
+// newCoverProxy returns the cover proxy configured by settings.
+func newCoverProxy(settings coverSettings) (*coverProxy, error) {
+	cache, err := newCoverCache(settings)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{
+		Timeout: coverTimeout,
+		// A redirect could leave the allowed hosts.
+		CheckRedirect: func(req *http.Request, _ []*http.Request) error {
+			if !settings.hosts[strings.ToLower(req.URL.Hostname())] {
+				return fmt.Errorf("redirect to disallowed host %q", req.URL.Host)
+			}
+			return nil
+		},
+	}
+	return &coverProxy{hosts: settings.hosts, cache: cache, client: client}, nil
+}
+
+func (p *coverProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	src, err := url.Parse(q.Get("url"))
+	if err != nil || src.Scheme != "https" || !p.hosts[strings.ToLower(src.Hostname())] {
+		writeProxyError(w, http.StatusBadRequest, "url must be an https cover URL on an allowed host")
+		return
+	}
+	width, okW := coverSize(q.Get("w"))
+	height, okH := coverSize(q.Get("h"))
+	if !okW || !okH || (width == 0 && height == 0) {
+		writeProxyError(w, http.StatusBadRequest, fmt.Sprintf("w and h must be between %d and %d, and one is required", minCoverSize, maxCoverSize))
+		return
+	}
+
+	format := "jpeg"
+	if strings.Contains(r.Header.Get("Accept"), "image/webp") {
+		format = "webp"
+	}
+	w.Header().Set("Vary", "Accept")
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%s", src.String(), width, height, format)))
+	key := hex.EncodeToString(sum[:])
+	etag := `"` + key[:32] + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	data, ok := p.cache.get(key)
+	if !ok {
+		data, err = p.render(r.Context(), src.String(), width, height, format)
+		if err != nil {
+			slog.WarnContext(r.Context(), "cover failed", "host", src.Host, "error", err)
+			writeProxyError(w, http.StatusBadGateway, "cover unavailable")
+			return
+		}
+		p.cache.put(key, data)
+	}
+
+	w.Header().Set("Content-Type", "image/"+format)
+	w.Header().Set("Cache-Control", "public, max-age=604800, immutable")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	_, _ = w.Write(data)
+}
+
+// coverSize parses a requested dimension; empty is 0.
+func coverSize(v string) (int, bool) {
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil && n >= minCoverSize && n <= maxCoverSize
+}
+
+// render fetches the cover at src and returns it resized, encoded as format
+// ("jpeg" or "webp").
+func (p *coverProxy) render(ctx context.Context, src string, width, height int, format string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("upstream status %d", resp.StatusCode)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxCoverBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxCoverBytes {
+		return nil, errors.New("cover too large")
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decode cover: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxCoverPixels || cfg.Width == 0 || cfg.Height == 0 {
+		return nil, fmt.Errorf("cover of %dx%d pixels refused", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decode cover: %w", err)
+	}
+
+	var buf bytes.Buffer
+	resized := resizeCover(img, width, height)
+	if format == "webp" {
+		err = encodeWebP(&buf, resized)
+	} else {
+		err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: coverQuality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode cover: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resizeCover scales img to width by height, cropping from the center to
+// keep its aspect ratio. A zero dimension follows from the other one.
+// Covers are never enlarged.
+func resizeCover(img image.Image, width, height int) image.Image {
+	b := img.Bounds()
+	crop := b
+	switch {
+	case width == 0:
+		width = max(1, b.Dx()*height/b.Dy())
+	case height == 0:
+		height = max(1, b.Dy()*width/b.Dx())
+	case b.Dx()*height > b.Dy()*width:
+		// Wider than the box: trim the sides.
+		w := b.Dy() * width / height
+		crop = image.Rect(b.Min.X+(b.Dx()-w)/2, b.Min.Y, b.Min.X+(b.Dx()-w)/2+w, b.Max.Y)
+	default:
+		h := b.Dx() * height / width
+		crop = image.Rect(b.Min.X, b.Min.Y+(b.Dy()-h)/2, b.Max.X, b.Min.Y+(b.Dy()-h)/2+h)
+	}
+	if width > crop.Dx() || height > crop.Dy() {
+		width, height = crop.Dx(), crop.Dy()
+	}
+
+	// Each output pixel averages the source pixels it covers.
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := crop.Min.Y + y*crop.Dy()/height
+		y1 := max(y0+1, crop.Min.Y+(y+1)*crop.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := crop.Min.X + x*crop.Dx()/width
+			x1 := max(x0+1, crop.Min.X+(x+1)*crop.Dx()/width)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			out.SetRGBA(x, y, color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(bl / n >> 8), A: uint8(a / n >> 8)})
+		}
+	}
+	return out
+}
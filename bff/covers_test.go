@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+var (
+	coverRed  = color.RGBA{R: 255, A: 255}
+	coverBlue = color.RGBA{B: 255, A: 255}
+)
+
+// newTestCover returns a width x height image that is blue in its middle
+// and red in the outer quarters of its longer side.
+func newTestCover(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := coverBlue
+			if width > height && (x < width/4 || x >= width-width/4) ||
+				height > width && (y < height/4 || y >= height-height/4) {
+				c = coverRed
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestResizeCover(t *testing.T) {
+	tests := []struct {
+		name          string
+		src           image.Image
+		width, height int
+		wantW, wantH  int
+	}{
+		{name: "crops a wide cover", src: newTestCover(400, 200), width: 100, height: 100, wantW: 100, wantH: 100},
+		{name: "crops a tall cover", src: newTestCover(200, 400), width: 50, height: 50, wantW: 50, wantH: 50},
+		{name: "keeps the aspect ratio without h", src: newTestCover(400, 200), width: 100, wantW: 100, wantH: 50},
+		{name: "keeps the aspect ratio without w", src: newTestCover(400, 200), height: 100, wantW: 200, wantH: 100},
+		{name: "never enlarges", src: newTestCover(40, 40), width: 100, height: 100, wantW: 40, wantH: 40},
+		{name: "never enlarges the crop", src: newTestCover(80, 40), width: 100, height: 100, wantW: 40, wantH: 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resizeCover(tt.src, tt.width, tt.height)
+			if b := got.Bounds(); b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+				t.Fatalf("expected %dx%d, got %dx%d", tt.wantW, tt.wantH, b.Dx(), b.Dy())
+			}
+			if tt.width == 0 || tt.height == 0 {
+				return
+			}
+			// The red sides are cropped away.
+			for _, p := range []image.Point{{0, 0}, {tt.wantW - 1, tt.wantH - 1}, {tt.wantW / 2, tt.wantH / 2}} {
+				if c := got.At(p.X, p.Y); c != coverBlue {
+					t.Fatalf("expected blue at %v, got %v", p, c)
+				}
+			}
+		})
+	}
+}
+
+func TestCoverSize(t *testing.T) {
+	tests := []struct {
+		v    string
+		want int
+		ok   bool
+	}{
+		{v: "", want: 0, ok: true},
+		{v: "16", want: 16, ok: true},
+		{v: "1024", want: 1024, ok: true},
+		{v: "15", ok: false},
+		{v: "1025", ok: false},
+		{v: "-300", ok: false},
+		{v: "big", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := coverSize(tt.v)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("coverSize(%q): expected %d, %v, got %d, %v", tt.v, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+// newTestCoverProxy serves covers from an HTTPS server on 127.0.0.1, which
+// is the only allowed host.
+func newTestCoverProxy(t *testing.T, upstream http.HandlerFunc) (*coverProxy, *httptest.Server) {
+	t.Helper()
+	srv := httptest.NewTLSServer(upstream)
+	t.Cleanup(srv.Close)
+	p, err := newCoverProxy(coverSettings{hosts: map[string]bool{"127.0.0.1": true}, cacheSize: 10})
+	if err != nil {
+		t.Fatalf("new cover proxy: %v", err)
+	}
+	p.client.Transport = srv.Client().Transport
+	return p, srv
+}
+
+func getCover(p *coverProxy, src, size, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/images/cover?url="+url.QueryEscape(src)+"&w="+size+"&h="+size, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCoverProxy_RejectsRequests(t *testing.T) {
+	var fetched int
+	p, srv := newTestCoverProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		fetched++
+	})
+
+	tests := []struct {
+		name string
+		src  string
+		size string
+	}{
+		{name: "plain http", src: "http://127.0.0.1/cover.png", size: "100"},
+		{name: "disallowed host", src: "https://evil.example/cover.png", size: "100"},
+		{name: "no url", src: "", size: "100"},
+		{name: "too large", src: srv.URL + "/cover.png", size: "4096"},
+		{name: "no size", src: srv.URL + "/cover.png", size: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := getCover(p, tt.src, tt.size, ""); rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rec.Code)
+			}
+		})
+	}
+	if fetched != 0 {
+		t.Fatalf("expected nothing fetched, got %d requests", fetched)
+	}
+}
+
+func TestCoverProxy_RejectsRedirects(t *testing.T) {
+	p, srv := newTestCoverProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://evil.example/cover.png", http.StatusFound)
+	})
+
+	if rec := getCover(p, srv.URL+"/cover.png", "100", ""); rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d", rec.Code)
+	}
+}
+
+func TestCoverProxy_Formats(t *testing.T) {
+	var fetched int
+	p, srv := newTestCoverProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		fetched++
+		_ = png.Encode(w, newTestCover(400, 200))
+	})
+	src := srv.URL + "/cover.png"
+
+	jpg := getCover(p, src, "100", "image/avif,image/*")
+	if jpg.Code != http.StatusOK || jpg.Header().Get("Content-Type") != "image/jpeg" {
+		t.Fatalf("expected a JPEG, got %d %q", jpg.Code, jpg.Header().Get("Content-Type"))
+	}
+	wp := getCover(p, src, "100", "image/avif,image/webp,image/*")
+	if wp.Code != http.StatusOK || wp.Header().Get("Content-Type") != "image/webp" {
+		t.Fatalf("expected a WebP, got %d %q", wp.Code, wp.Header().Get("Content-Type"))
+	}
+	img, err := webp.Decode(wp.Body)
+	if err != nil {
+		t.Fatalf("decode WebP: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 100 || b.Dy() != 100 {
+		t.Fatalf("expected 100x100, got %dx%d", b.Dx(), b.Dy())
+	}
+	for _, rec := range []*httptest.ResponseRecorder{jpg, wp} {
+		if rec.Header().Get("Vary") != "Accept" {
+			t.Fatalf("expected Vary: Accept, got %q", rec.Header().Get("Vary"))
+		}
+	}
+	if jpg.Header().Get("ETag") == wp.Header().Get("ETag") {
+		t.Fatal("expected the formats to have different ETags")
+	}
+
+	// Both formats are cached.
+	getCover(p, src, "100", "")
+	getCover(p, src, "100", "image/webp")
+	if fetched != 2 {
+		t.Fatalf("expected 2 fetches, got %d", fetched)
+	}
+}
+
+func TestDiskCoverCache_Evicts(t *testing.T) {
+	dir := t.TempDir()
+	c, err := openDiskCoverCache(dir, 25)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	ten := bytes.Repeat([]byte("x"), 10)
+	c.put("a", ten)
+	c.put("b", ten)
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	// a was used more recently than b, so b goes.
+	c.put("c", ten)
+	if _, ok := c.get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.cover")); !os.IsNotExist(err) {
+		t.Fatalf("expected b's file to be removed, got %v", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if data, ok := c.get(key); !ok || !bytes.Equal(data, ten) {
+			t.Fatalf("expected %s to be cached", key)
+		}
+	}
+
+	// Reopening with a smaller bound keeps what fits.
+	c, err = openDiskCoverCache(dir, 15)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.cover"))
+	if len(files) != 1 || c.size != 10 {
+		t.Fatalf("expected one cover of 10 bytes left, got %v and %d bytes", files, c.size)
+	}
+}
+
+func TestMemoryCoverCache_Evicts(t *testing.T) {
+	c, err := newCoverCache(coverSettings{cacheSize: 2})
+	if err != nil {
+		t.Fatalf("new cache: %v", err)
+	}
+	c.put("a", []byte("a"))
+	c.put("b", []byte("b"))
+	c.get("a")
+	c.put("c", []byte("c"))
+	if _, ok := c.get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.get(key); !ok {
+			t.Fatalf("expected %s to be cached", key)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// errBackendNotFound marks backend 404s, which resolve to null.
+var errBackendNotFound = errors.New("not found")
+
+// backendAPI makes the backend REST calls GraphQL queries are resolved
+// from. Objects are kept as decoded JSON maps and fields read by key, so the
+// schema only names what it exposes.
+type backendAPI struct {
+	backend backendTarget
+	client  *http.Client
+	stream  *http.Client
+}
+
+func newBackendAPI(backend backendTarget, timeout time.Duration) *backendAPI {
+	return &backendAPI{backend: backend, client: backend.client(timeout), stream: backend.client(0)}
+}
+
+// get fetches path into a JSON object, or returns errBackendNotFound.
+func (b *backendAPI) get(ctx context.Context, path string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.backend.url(path), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.client.Do(req) // #nosec G107 -- fixed backend host
+	if err != nil {
+		return nil, fmt.Errorf("backend unavailable")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return decodeBackend(resp)
+}
+
+// processIntent runs an intent on the backend and returns its complete
+// event, waiting for the stream to finish. A non-empty sessionID continues
+// that conversational session.
+func (b *backendAPI) processIntent(ctx context.Context, playlistID, message, sessionID string) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, streamTimeout)
+	defer cancel()
+	body, _ := json.Marshal(map[string]string{"message": message, "session_id": sessionID})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.backend.url("/playlists/"+url.PathEscape(playlistID)+"/intent"), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := b.stream.Do(req) // #nosec G107 -- fixed backend host
+	if err != nil {
+		return nil, fmt.Errorf("backend unavailable")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		_, err := decodeBackend(resp)
+		if err == nil {
+			err = fmt.Errorf("backend returned status %d", resp.StatusCode)
+		}
+		return nil, err
+	}
+
+	var result map[string]any
+	err = readEvents(resp.Body, func(ev sseEvent) error {
+		switch ev.name {
+		case "complete":
+			return json.Unmarshal([]byte(ev.data), &result)
+		case "error":
+			var failure struct {
+				Error string `json:"error"`
+			}
+			_ = json.Unmarshal([]byte(ev.data), &failure)
+			return fmt.Errorf("intent failed: %s", failure.Error)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("intent stream ended without a result")
+	}
+	return result, nil
+}
+
+// decodeBackend decodes a backend response, turning error statuses into
+// errors carrying the backend's message.
+func decodeBackend(resp *http.Response) (map[string]any, error) {
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errBackendNotFound
+	}
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid backend response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		if msg, ok := body["error"].(string); ok {
+			return nil, errors.New(msg)
+		}
+		return nil, fmt.Errorf("backend returned status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// orNull resolves a missing backend object to null rather than an error.
+func orNull(obj map[string]any, err error) (any, error) {
+	if errors.Is(err, errBackendNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// prop is a field read from key of a decoded JSON object.
+func prop(t graphql.Output, key string) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+		m, _ := p.Source.(map[string]any)
+		return m[key], nil
+	}}
+}
+
+// path is prop for a nested key, such as data.explanation.
+func path(t graphql.Output, keys ...string) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+		var v any = p.Source
+		for _, key := range keys {
+			m, _ := v.(map[string]any)
+			v = m[key]
+		}
+		return v, nil
+	}}
+}
+
+// newGraphQLSchema builds the schema. Nested fields such as
+// Playlist.analysis each cost one backend call, made only when selected.
+func newGraphQLSchema(api *backendAPI) (graphql.Schema, error) {
+	features := graphql.NewObject(graphql.ObjectConfig{
+		Name: "AudioFeatures",
+		Fields: graphql.Fields{
+			"danceability":     prop(graphql.Float, "danceability"),
+			"energy":           prop(graphql.Float, "energy"),
+			"valence":          prop(graphql.Float, "valence"),
+			"tempo":            prop(graphql.Float, "tempo"),
+			"instrumentalness": prop(graphql.Float, "instrumentalness"),
+			"acousticness":     prop(graphql.Float, "acousticness"),
+		},
+	})
+	track := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Track",
+		Fields: graphql.Fields{
+			"id":         prop(graphql.NewNonNull(graphql.ID), "id"),
+			"title":      prop(graphql.String, "title"),
+			"artist":     prop(graphql.String, "artist"),
+			"album":      prop(graphql.String, "album"),
+			"durationMs": prop(graphql.Int, "duration_ms"),
+			"coverUrl":   prop(graphql.String, "cover_url"),
+			"previewUrl": prop(graphql.String, "preview_url"),
+			"isrc":       prop(graphql.String, "isrc"),
+			"popularity": prop(graphql.Int, "popularity"),
+			"explicit":   prop(graphql.Boolean, "explicit"),
+			"features":   prop(features, "features"),
+		},
+	})
+	job := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Job",
+		Fields: graphql.Fields{
+			"id":        prop(graphql.NewNonNull(graphql.ID), "id"),
+			"trackId":   prop(graphql.String, "track_id"),
+			"state":     prop(graphql.String, "state"),
+			"attempts":  prop(graphql.Int, "attempts"),
+			"lastError": prop(graphql.String, "last_error"),
+			"updatedAt": prop(graphql.String, "updated_at"),
+		},
+	})
+	jobProgress := graphql.NewObject(graphql.ObjectConfig{
+		Name: "JobProgress",
+		Fields: graphql.Fields{
+			"total":   prop(graphql.Int, "total"),
+			"pending": prop(graphql.Int, "pending"),
+			"done":    prop(graphql.Int, "done"),
+			"failed":  prop(graphql.Int, "failed"),
+			"jobs":    prop(graphql.NewList(job), "jobs"),
+		},
+	})
+	playlist := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Playlist",
+		Fields: graphql.Fields{
+			"id":     prop(graphql.NewNonNull(graphql.ID), "id"),
+			"name":   prop(graphql.String, "name"),
+			"tracks": prop(graphql.NewList(track), "tracks"),
+			"analysis": &graphql.Field{
+				Type:        features,
+				Description: "Average audio features of the playlist's tracks.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, _ := p.Source.(map[string]any)["id"].(string)
+					return orNull(api.get(p.Context, "/playlists/"+url.PathEscape(id)+"/analysis"))
+				},
+			},
+			"jobs": &graphql.Field{
+				Type:        jobProgress,
+				Description: "Progress of the tracks' audio analysis; null when jobs are not persisted.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, _ := p.Source.(map[string]any)["id"].(string)
+					return orNull(api.get(p.Context, "/playlists/"+url.PathEscape(id)+"/jobs"))
+				},
+			},
+		},
+	})
+	intentResult := graphql.NewObject(graphql.ObjectConfig{
+		Name: "IntentResult",
+		Fields: graphql.Fields{
+			"intentType":       path(graphql.String, "data", "intent_type"),
+			"explanation":      path(graphql.String, "data", "explanation"),
+			"artists":          path(graphql.NewList(graphql.String), "data", "entities", "artists"),
+			"genres":           path(graphql.NewList(graphql.String), "data", "entities", "genres"),
+			"tracksEvaluated":  prop(graphql.Int, "tracks_evaluated"),
+			"tracksAdded":      prop(graphql.Int, "tracks_added"),
+			"summary":          prop(graphql.String, "summary"),
+			"durationMs":       prop(graphql.Int, "duration_ms"),
+			"targetDurationMs": prop(graphql.Int, "target_duration_ms"),
+			"daypart":          prop(graphql.String, "daypart"),
+			"weather":          prop(graphql.String, "weather"),
+			"focus":            prop(graphql.Boolean, "focus"),
+			"mood":             prop(graphql.Boolean, "mood"),
+			"similarArtists":   prop(graphql.NewList(graphql.String), "similar_artists"),
+			"sequence":         prop(graphql.String, "sequence"),
+			"sessionId":        prop(graphql.String, "session_id"),
+			"sessionHistory":   prop(graphql.Int, "session_history"),
+		},
+	})
+
+	idArg := graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}}
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"playlist": &graphql.Field{
+				Type: playlist,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return orNull(api.get(p.Context, "/playlists/"+url.PathEscape(p.Args["id"].(string))))
+				},
+			},
+			"job": &graphql.Field{
+				Type: job,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return orNull(api.get(p.Context, "/jobs/"+url.PathEscape(p.Args["id"].(string))))
+				},
+			},
+		},
+	})
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"processIntent": &graphql.Field{
+				Type:        intentResult,
+				Description: "Runs an intent against a playlist and returns once it has finished.",
+				Args: graphql.FieldConfigArgument{
+					"playlistId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"message":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"sessionId": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "Continues the conversation with this ID, refining its earlier intents.",
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					sessionID, _ := p.Args["sessionId"].(string)
+					return api.processIntent(p.Context, p.Args["playlistId"].(string), p.Args["message"].(string), sessionID)
+				},
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// graphQLRequest is the standard GraphQL-over-HTTP request body.
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+// isMutation reports whether req runs a mutation. Queries that do not parse
+// are left for graphql.Do to reject.
+func (req graphQLRequest) isMutation() bool {
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return false
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok || req.OperationName != "" && (op.Name == nil || op.Name.Value != req.OperationName) {
+			continue
+		}
+		if op.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+	return false
+}
+
+// graphQLHandler serves POST /graphql.
+func graphQLHandler(schema graphql.Schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxProxyBody)).Decode(&req); err != nil || req.Query == "" {
+			writeProxyError(w, http.StatusBadRequest, "request must be JSON with a query")
+			return
+		}
+		if req.isMutation() {
+			// processIntent waits up to streamTimeout for the intent; the
+			// server's write timeout is for ordinary requests.
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		}
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        r.Context(),
+		})
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	}
+}
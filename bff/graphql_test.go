@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestGraphQL serves the GraphQL handler in front of a fake backend.
+func newTestGraphQL(t *testing.T, backend http.Handler) *httptest.Server {
+	t.Helper()
+	be := httptest.NewServer(backend)
+	t.Cleanup(be.Close)
+	schema, err := newGraphQLSchema(newBackendAPI(newBackendTarget(be.URL), time.Second))
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(graphQLHandler(schema))
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// postGraphQL runs query and decodes the response.
+func postGraphQL(t *testing.T, srv *httptest.Server, query string) map[string]any {
+	t.Helper()
+	body, _ := json.Marshal(graphQLRequest{Query: query})
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	defer resp.Body.Close()
+	var out map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return out
+}
+
+func TestGraphQL_Query(t *testing.T) {
+	backend := http.NewServeMux()
+	backend.HandleFunc("GET /playlists/p1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"p1","name":"Run","tracks":[{"id":"t1","title":"One","duration_ms":1000}]}`)
+	})
+	backend.HandleFunc("GET /playlists/p1/analysis", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"energy":0.8}`)
+	})
+	backend.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":"not found"}`)
+	})
+	srv := newTestGraphQL(t, backend)
+
+	got := postGraphQL(t, srv, `{
+		playlist(id: "p1") { name tracks { title durationMs } analysis { energy } jobs { total } }
+		missing: playlist(id: "p2") { name }
+		job(id: "j1") { state }
+	}`)
+	b, _ := json.Marshal(got)
+	want := `{"data":{"job":null,"missing":null,"playlist":{"analysis":{"energy":0.8},"jobs":null,"name":"Run","tracks":[{"durationMs":1000,"title":"One"}]}}}`
+	if string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+}
+
+func TestGraphQL_MutationOutlastsWriteTimeout(t *testing.T) {
+	backend := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		time.Sleep(300 * time.Millisecond)
+		fmt.Fprint(w, "event: complete\ndata: {\"tracks_added\":2}\n\n")
+	})
+	srv := newTestGraphQL(t, backend)
+
+	got := postGraphQL(t, srv, `mutation { processIntent(playlistId: "p1", message: "mellow") { tracksAdded } }`)
+	b, _ := json.Marshal(got)
+	if want := `{"data":{"processIntent":{"tracksAdded":2}}}`; string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+}
+
+func TestGraphQLRequest_IsMutation(t *testing.T) {
+	tests := []struct {
+		name string
+		req  graphQLRequest
+		want bool
+	}{
+		{name: "query", req: graphQLRequest{Query: `{ job(id: "j1") { state } }`}},
+		{name: "mutation", req: graphQLRequest{Query: `mutation { processIntent(playlistId: "p", message: "m") { summary } }`}, want: true},
+		{
+			name: "named query beside a mutation",
+			req:  graphQLRequest{Query: `query Q { job(id: "j") { state } } mutation M { processIntent(playlistId: "p", message: "m") { summary } }`, OperationName: "Q"},
+		},
+		{name: "unparsable", req: graphQLRequest{Query: `mutation {`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.isMutation(); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
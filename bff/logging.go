@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The BFF logs like the backend's internal/logging package: records carry
+// the request and trace IDs of their context and auth material is
+// redacted. The request ID is passed on to the backend, so one ID finds a
+// request in the logs of both services.
+
+// requestIDHeader carries the request ID to the backend and back to clients.
+const requestIDHeader = "X-Request-ID"
+
+// setupLogging makes the default logger write LOG_LEVEL records (debug,
+// info, warn or error; default info) to stderr as LOG_FORMAT (text or
+// json; default text).
+func setupLogging() error {
+	var level slog.Level
+	if raw := os.Getenv("LOG_LEVEL"); raw != "" {
+		if err := level.UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("invalid LOG_LEVEL %q", raw)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+
+	var h slog.Handler
+	switch format := getEnv("LOG_FORMAT", "text"); format {
+	case "text":
+		h = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT %q (want text or json)", format)
+	}
+	slog.SetDefault(slog.New(contextHandler{h}))
+	return nil
+}
+
+// fatalf logs an error and exits.
+func fatalf(format string, args ...any) {
+	slog.Error(fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+// contextHandler adds the request ID and trace ID of the context a record
+// is logged with.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := requestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// credentials matches auth scheme credentials wherever they appear in a
+// string.
+var credentials = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+
+// redact replaces the values of attributes that hold auth material, such
+// as session cookies and Spotify tokens.
+func redact(_ []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, s := range []string{"authorization", "secret", "password", "cookie", "session", "api_key"} {
+		if strings.Contains(key, s) {
+			return slog.String(a.Key, "[REDACTED]")
+		}
+	}
+	if key == "token" || strings.HasSuffix(key, "_token") {
+		return slog.String(a.Key, "[REDACTED]")
+	}
+	if a.Value.Kind() == slog.KindString {
+		if s := a.Value.String(); credentials.MatchString(s) {
+			return slog.String(a.Key, credentials.ReplaceAllString(s, "$1 [REDACTED]"))
+		}
+	}
+	return a
+}
+
+type requestIDKey struct{}
+
+// requestID returns the request ID ctx carries, or "".
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDs gives every request next serves an ID, reusing a well-formed
+// X-Request-ID from the caller, and echoes it in the response.
+func requestIDs(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID accepts short IDs of letters, digits, '-', '_' and '.', so
+// callers cannot inject arbitrary text into logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// requestIDTransport sends the request ID of a request's context to the
+// backend.
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t requestIDTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if id := requestID(r.Context()); id != "" {
+		r = r.Clone(r.Context())
+		r.Header.Set(requestIDHeader, id)
+	}
+	return t.base.RoundTrip(r)
+}
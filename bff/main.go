@@ -6,52 +6,117 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+
+	"github.com/ewilliams-labs/overture/metrics"
 )
 
 func main() {
 	backendURL := getEnv("BACKEND_URL", "http://backend:8080")
 	port := getEnv("PORT", "3000")
+	metricsAddr := getEnv("METRICS_ADDR", ":9090")
+
+	if err := setupLogging(); err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err)
+		os.Exit(1)
+	}
+	slog.Info("Overture BFF starting", "backend_url", backendURL, "port", port)
+
+	shutdownTracing, err := setupTracing(context.Background())
+	if err != nil {
+		fatalf("invalid tracing configuration: %v", err)
+	}
 
-	log.Println("================================================")
-	log.Println("🎭 Overture BFF starting...")
-	log.Printf("   Backend URL: %s", backendURL)
-	log.Printf("   Listening on: :%s", port)
-	log.Println("================================================")
+	backend := newBackendTarget(backendURL)
 
 	// Verify backend connectivity on startup
-	if err := waitForBackend(backendURL, 30*time.Second); err != nil {
-		log.Printf("⚠️  Backend not reachable: %v (continuing anyway)", err)
+	if err := waitForBackend(backend, 30*time.Second); err != nil {
+		slog.Warn("backend not reachable, continuing anyway", "error", err)
 	} else {
-		log.Println("✅ Backend health check passed")
+		slog.Info("backend health check passed")
 	}
 
 	// Set up routes
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", healthHandler)
 	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
-		readyHandler(w, r, backendURL)
+		readyHandler(w, r, backend)
 	})
 	mux.HandleFunc("/", rootHandler)
 
+	tlsCfg := loadTLSSettings()
+	sessionCfg, err := loadSessionSettings(tlsCfg)
+	if err != nil {
+		fatalf("invalid session configuration: %v", err)
+	}
+	store, err := newSessionStore(sessionCfg)
+	if err != nil {
+		fatalf("failed to open session store: %v", err)
+	}
+	sess := &sessions{store: store, ttl: sessionCfg.ttl, secure: sessionCfg.secure}
+	// With login enabled, only signed-in browsers reach the backend.
+	protect := func(h http.Handler) http.Handler { return h }
+	if auth := newSpotifyAuth(sess); auth != nil {
+		auth.routes(mux)
+		protect = auth.require
+		slog.Info("Spotify login enabled", "session_store", sessionCfg.store)
+	}
+
+	timeout, err := proxyTimeout()
+	if err != nil {
+		fatalf("invalid proxy configuration: %v", err)
+	}
+	api, err := newAPIProxy(backend, timeout)
+	if err != nil {
+		fatalf("invalid proxy configuration: %v", err)
+	}
+	mux.Handle(apiPrefix+"/", protect(api))
+	schema, err := newGraphQLSchema(newBackendAPI(backend, timeout))
+	if err != nil {
+		fatalf("invalid GraphQL schema: %v", err)
+	}
+	mux.Handle("POST /graphql", protect(graphQLHandler(schema)))
+
+	coverCfg, err := loadCoverSettings()
+	if err != nil {
+		fatalf("invalid cover configuration: %v", err)
+	}
+	covers, err := newCoverProxy(coverCfg)
+	if err != nil {
+		fatalf("failed to open cover cache: %v", err)
+	}
+	mux.Handle("GET /images/cover", covers)
+
 	srv := &http.Server{
 		Addr:         ":" + port,
-		Handler:      mux,
+		Handler:      requestIDs(traced(metrics.InstrumentHandler(routeSpans(mux)))),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	serve, err := configureServer(srv, tlsCfg)
+	if err != nil {
+		fatalf("invalid TLS configuration: %v", err)
+	}
+
+	metricsSrv := newMetricsServer(metricsAddr)
+	go func() {
+		slog.Info("serving metrics", "addr", metricsAddr)
+		if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatalf("metrics server error: %v", err)
+		}
+	}()
 
 	// Start server in goroutine
 	go func() {
-		log.Printf("🎭 BFF is running on http://localhost:%s", port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+		slog.Info("BFF is running", "addr", tlsCfg.scheme()+"://localhost:"+port)
+		if err := serve(); err != nil && err != http.ErrServerClosed {
+			fatalf("server error: %v", err)
 		}
 	}()
 
@@ -60,13 +125,19 @@ func main() {
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
-	log.Println("🛑 Shutting down BFF...")
+	slog.Info("shutting down BFF")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Shutdown error: %v", err)
+		fatalf("shutdown failed: %v", err)
+	}
+	if err := metricsSrv.Shutdown(ctx); err != nil {
+		slog.Warn("failed to stop metrics server", "error", err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Warn("failed to flush traces", "error", err)
 	}
-	log.Println("👋 BFF stopped")
+	slog.Info("BFF stopped")
 }
 
 // healthHandler returns the BFF's own health status
@@ -77,11 +148,11 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // readyHandler checks if the BFF can reach the backend
-func readyHandler(w http.ResponseWriter, r *http.Request, backendURL string) {
+func readyHandler(w http.ResponseWriter, r *http.Request, backend backendTarget) {
 	w.Header().Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(backendURL + "/health")
+	client := backend.client(5 * time.Second)
+	resp, err := client.Get(backend.url("/health"))
 	if err != nil {
 		w.WriteHeader(http.StatusServiceUnavailable)
 		fmt.Fprintf(w, `{"status":"not_ready","error":"%s"}`, err.Error())
@@ -106,12 +177,12 @@ func rootHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // waitForBackend polls the backend health endpoint until it responds or times out
-func waitForBackend(backendURL string, timeout time.Duration) error {
-	client := &http.Client{Timeout: 2 * time.Second}
+func waitForBackend(backend backendTarget, timeout time.Duration) error {
+	client := backend.client(2 * time.Second)
 	deadline := time.Now().Add(timeout)
 
 	for time.Now().Before(deadline) {
-		resp, err := client.Get(backendURL + "/health")
+		resp, err := client.Get(backend.url("/health"))
 		if err == nil {
 			_ = resp.Body.Close()
 			if resp.StatusCode == http.StatusOK {
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+// The BFF exports the same request metrics as the backend, through the
+// shared metrics module, so one dashboard covers both services; they differ
+// by the job label Prometheus adds when scraping.
+
+// newMetricsServer serves GET /metrics on addr, METRICS_ADDR, apart from
+// the public listener so browsers cannot scrape it. Keep the port off the
+// internet and let Prometheus reach it over the private network.
+func newMetricsServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+	return &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ewilliams-labs/overture/metrics"
+)
+
+// apiPrefix is where the React client reaches the backend through the BFF;
+// /api/playlists/{id} is forwarded as /playlists/{id}.
+const apiPrefix = "/api"
+
+// maxProxyBody caps request bodies forwarded to the backend.
+const maxProxyBody = 1 << 20
+
+// allowedRoutes are the backend routes the browser may call, as
+// http.ServeMux patterns relative to apiPrefix. Administration, metrics and
+// the backend's own Spotify login are deliberately absent.
+var allowedRoutes = []string{
+	"POST /playlists",
+	"GET /playlists/{id}",
+	"POST /playlists/{id}/tracks",
+	"PUT /playlists/{id}/tracks/order",
+	"GET /playlists/{id}/settings",
+	"PATCH /playlists/{id}/settings",
+	"GET /playlists/{id}/analysis",
+	"GET /playlists/{id}/similar",
+	"GET /playlists/{id}/arc",
+	"GET /playlists/{id}/recommendations",
+	"GET /playlists/{id}/jobs",
+	"GET /playlists/{id}/events",
+	"POST /playlists/{id}/intent",
+	"POST /playlists/{id}/templates/running",
+	"POST /playlists/{id}/albums",
+	"POST /playlists/{id}/episodes",
+	"GET /playlists/{id}/playback",
+	"PUT /playlists/{id}/playback",
+	"POST /playlists/{id}/focus",
+	"PUT /playlists/{id}/public",
+	"DELETE /playlists/{id}/public",
+	"POST /playlists/{id}/copy",
+	"GET /jobs/{id}",
+	"GET /queue",
+	"POST /queue",
+	"POST /queue/pop",
+	"DELETE /queue",
+	"GET /settings/weather",
+	"PUT /settings/weather",
+	"GET /me/mood",
+	"POST /me/mood",
+	"GET /focus",
+	"GET /discover",
+	"GET /episodes",
+	"GET /tracks",
+	"GET /tracks/{id}",
+	"GET /tracks/{id}/lyrics",
+	"GET /tracks/{id}/also-added",
+}
+
+// strippedRequestHeaders never reach the backend: the session cookie and any
+// credentials stay in the BFF.
+var strippedRequestHeaders = []string{"Cookie", "Authorization", "Proxy-Authorization"}
+
+// strippedResponseHeaders never reach the browser, so the backend cannot set
+// cookies on the BFF's origin or advertise its implementation.
+var strippedResponseHeaders = []string{"Set-Cookie", "Server", "X-Powered-By"}
+
+// newAPIProxy forwards allowed routes under apiPrefix to backend. Requests
+// that take longer than timeout are canceled with 504 Gateway Timeout;
+// everything else is 404 Not Found or 405 Method Not Allowed.
+func newAPIProxy(backend backendTarget, timeout time.Duration) (http.Handler, error) {
+	target, err := url.Parse(backend.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BACKEND_URL: %w", err)
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			for _, h := range strippedRequestHeaders {
+				pr.Out.Header.Del(h)
+			}
+		},
+		Transport: backend.transport,
+		ModifyResponse: func(resp *http.Response) error {
+			for _, h := range strippedResponseHeaders {
+				resp.Header.Del(h)
+			}
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			status, msg := http.StatusBadGateway, "backend unavailable"
+			if errors.Is(err, context.DeadlineExceeded) {
+				status, msg = http.StatusGatewayTimeout, "backend timed out"
+			}
+			slog.WarnContext(r.Context(), "proxy failed", "method", r.Method, "path", r.URL.Path, "error", err)
+			writeProxyError(w, status, msg)
+		},
+	}
+
+	forward := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		r.Body = http.MaxBytesReader(w, r.Body, maxProxyBody)
+		proxy.ServeHTTP(w, r.WithContext(ctx))
+	})
+	stream := relayStream(backend)
+	routes := http.NewServeMux()
+	for _, pattern := range allowedRoutes {
+		if streamedRoutes[pattern] {
+			routes.Handle(pattern, metrics.WithRoute(apiPrefix, stream))
+			continue
+		}
+		routes.Handle(pattern, metrics.WithRoute(apiPrefix, forward))
+	}
+	return http.StripPrefix(apiPrefix, routes), nil
+}
+
+// proxyTimeout reads PROXY_TIMEOUT, the longest the BFF waits on a proxied
+// request (default 15s).
+func proxyTimeout() (time.Duration, error) {
+	v := os.Getenv("PROXY_TIMEOUT")
+	if v == "" {
+		return 15 * time.Second, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid PROXY_TIMEOUT %q", v)
+	}
+	return d, nil
+}
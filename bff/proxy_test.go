@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestProxy serves the API proxy in front of backend.
+func newTestProxy(t *testing.T, backend http.HandlerFunc, timeout time.Duration) http.Handler {
+	t.Helper()
+	be := httptest.NewServer(backend)
+	t.Cleanup(be.Close)
+	proxy, err := newAPIProxy(newBackendTarget(be.URL), timeout)
+	if err != nil {
+		t.Fatalf("new proxy: %v", err)
+	}
+	return proxy
+}
+
+func TestAPIProxy_Allowlist(t *testing.T) {
+	var reached []string
+	proxy := newTestProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		reached = append(reached, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}, time.Second)
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{method: http.MethodGet, path: "/api/playlists/p1", want: http.StatusOK},
+		{method: http.MethodGet, path: "/api/admin/backups", want: http.StatusNotFound},
+		{method: http.MethodPost, path: "/api/admin/backfill", want: http.StatusNotFound},
+		{method: http.MethodGet, path: "/api/metrics", want: http.StatusNotFound},
+		{method: http.MethodGet, path: "/api/auth/spotify/login", want: http.StatusNotFound},
+		{method: http.MethodDelete, path: "/api/playlists/p1", want: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/playlists/p1", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			proxy.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+	if len(reached) != 1 || reached[0] != "GET /playlists/p1" {
+		t.Fatalf("expected only the allowed route to reach the backend, got %v", reached)
+	}
+}
+
+func TestAPIProxy_Headers(t *testing.T) {
+	var got http.Header
+	proxy := newTestProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		http.SetCookie(w, &http.Cookie{Name: "backend", Value: "x"})
+		w.Header().Set("Server", "overture-backend")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusOK)
+	}, time.Second)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/playlists/p1", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookie, Value: "secret-session"})
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("Proxy-Authorization", "Basic c2VjcmV0")
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, req)
+
+	for _, h := range strippedRequestHeaders {
+		if v := got.Get(h); v != "" {
+			t.Errorf("expected %s to stay in the BFF, backend got %q", h, v)
+		}
+	}
+	if got.Get("X-Forwarded-For") == "" {
+		t.Error("expected the backend to be told the client's address")
+	}
+	for _, h := range strippedResponseHeaders {
+		if v := rec.Header().Get(h); v != "" {
+			t.Errorf("expected %s to stay in the BFF, browser got %q", h, v)
+		}
+	}
+	if rec.Header().Get("X-Request-Id") != "req-1" {
+		t.Error("expected other response headers to pass through")
+	}
+}
+
+func TestAPIProxy_Timeout(t *testing.T) {
+	proxy := newTestProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}, 20*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/playlists/p1", nil))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix namespaces session keys in a shared Redis.
+const redisKeyPrefix = "overture:bff:session:"
+
+// redisStore keeps sessions in Redis, so they survive restarts and are
+// shared by every BFF replica. Redis expires them.
+type redisStore struct {
+	client *redis.Client
+}
+
+// newRedisStore connects to url, e.g. redis://:password@redis:6379/0.
+func newRedisStore(url string) (*redisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	return &redisStore{client: redis.NewClient(opts)}, nil
+}
+
+func (r *redisStore) get(ctx context.Context, id string) (session, error) {
+	raw, err := r.client.Get(ctx, redisKeyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return session{}, errNoSession
+	}
+	if err != nil {
+		return session{}, fmt.Errorf("redis get session: %w", err)
+	}
+	var s session
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return session{}, fmt.Errorf("decode session: %w", err)
+	}
+	return s, nil
+}
+
+func (r *redisStore) save(ctx context.Context, s session, ttl time.Duration) error {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+	if err := r.client.Set(ctx, redisKeyPrefix+s.ID, raw, ttl).Err(); err != nil {
+		return fmt.Errorf("redis save session: %w", err)
+	}
+	return nil
+}
+
+func (r *redisStore) delete(ctx context.Context, id string) error {
+	if err := r.client.Del(ctx, redisKeyPrefix+id).Err(); err != nil {
+		return fmt.Errorf("redis delete session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// sessionCookie names the cookie that carries the session ID. The cookie
+// holds nothing else; tokens stay on the server.
+const sessionCookie = "overture_session"
+
+// session is a browser's state in the BFF. The Spotify token never leaves
+// the BFF; the React client only ever holds the session cookie.
+type session struct {
+	ID string `json:"-"`
+	// State and Verifier belong to a login in progress.
+	State     string        `json:"state,omitempty"`
+	Verifier  string        `json:"verifier,omitempty"`
+	Token     *oauth2.Token `json:"token,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// errNoSession is returned by sessionStore.get for unknown or expired IDs.
+var errNoSession = errors.New("session not found")
+
+// sessionStore keeps sessions by ID for ttl after they were last saved.
+type sessionStore interface {
+	get(ctx context.Context, id string) (session, error)
+	save(ctx context.Context, s session, ttl time.Duration) error
+	delete(ctx context.Context, id string) error
+}
+
+// sessionSettings configures the session layer from the environment.
+type sessionSettings struct {
+	store    string
+	redisURL string
+	ttl      time.Duration
+	// secure marks cookies Secure. It defaults to on when the BFF
+	// terminates TLS, and must be forced on behind a TLS ingress.
+	secure bool
+}
+
+func loadSessionSettings(tlsCfg tlsSettings) (sessionSettings, error) {
+	s := sessionSettings{
+		store:    getEnv("SESSION_STORE", "memory"),
+		redisURL: os.Getenv("REDIS_URL"),
+		ttl:      7 * 24 * time.Hour,
+		secure:   tlsCfg.enabled() || os.Getenv("SESSION_COOKIE_SECURE") == "true",
+	}
+	if v := os.Getenv("SESSION_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return sessionSettings{}, fmt.Errorf("invalid SESSION_TTL %q", v)
+		}
+		s.ttl = ttl
+	}
+	return s, nil
+}
+
+// newSessionStore opens the store named by settings.
+func newSessionStore(settings sessionSettings) (sessionStore, error) {
+	switch settings.store {
+	case "memory":
+		return newMemoryStore(), nil
+	case "redis":
+		if settings.redisURL == "" {
+			return nil, fmt.Errorf("SESSION_STORE=redis requires REDIS_URL")
+		}
+		return newRedisStore(settings.redisURL)
+	default:
+		return nil, fmt.Errorf("unknown SESSION_STORE %q", settings.store)
+	}
+}
+
+// sessions issues, loads and clears cookie-backed sessions.
+type sessions struct {
+	store  sessionStore
+	ttl    time.Duration
+	secure bool
+}
+
+// load returns the session named by r's cookie.
+func (m *sessions) load(r *http.Request) (session, bool) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil || cookie.Value == "" {
+		return session{}, false
+	}
+	s, err := m.store.get(r.Context(), cookie.Value)
+	if err != nil {
+		if !errors.Is(err, errNoSession) {
+			slog.WarnContext(r.Context(), "failed to load session", "error", err)
+		}
+		return session{}, false
+	}
+	s.ID = cookie.Value
+	return s, true
+}
+
+// issue saves s, assigning it an ID if it has none, and sets its cookie.
+func (m *sessions) issue(w http.ResponseWriter, r *http.Request, s *session) error {
+	if s.ID == "" {
+		id, err := newSessionID()
+		if err != nil {
+			return err
+		}
+		s.ID = id
+		s.CreatedAt = time.Now().UTC()
+	}
+	if err := m.store.save(r.Context(), *s, m.ttl); err != nil {
+		return err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    s.ID,
+		Path:     "/",
+		MaxAge:   int(m.ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// rotate moves s to a new ID, so an ID planted before login is worthless
+// afterwards.
+func (m *sessions) rotate(w http.ResponseWriter, r *http.Request, s *session) error {
+	if s.ID != "" {
+		if err := m.store.delete(r.Context(), s.ID); err != nil {
+			return err
+		}
+	}
+	s.ID = ""
+	return m.issue(w, r, s)
+}
+
+// clear deletes the session named by r's cookie and expires the cookie.
+func (m *sessions) clear(w http.ResponseWriter, r *http.Request) error {
+	if cookie, err := r.Cookie(sessionCookie); err == nil && cookie.Value != "" {
+		if err := m.store.delete(r.Context(), cookie.Value); err != nil {
+			return err
+		}
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// newSessionID returns 256 random bits, URL-safe encoded.
+func newSessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate session id: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// memoryStore keeps sessions in process memory. They are lost on restart
+// and not shared between replicas; use Redis for either.
+type memoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	session session
+	expires time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{sessions: make(map[string]memoryEntry)}
+}
+
+func (m *memoryStore) get(_ context.Context, id string) (session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.sessions[id]
+	if !ok {
+		return session{}, errNoSession
+	}
+	if time.Now().After(entry.expires) {
+		delete(m.sessions, id)
+		return session{}, errNoSession
+	}
+	return entry.session, nil
+}
+
+func (m *memoryStore) save(_ context.Context, s session, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Expired sessions are swept on write so abandoned logins don't pile up.
+	now := time.Now()
+	for id, entry := range m.sessions {
+		if now.After(entry.expires) {
+			delete(m.sessions, id)
+		}
+	}
+	m.sessions[s.ID] = memoryEntry{session: s, expires: now.Add(ttl)}
+	return nil
+}
+
+func (m *memoryStore) delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
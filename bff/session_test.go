@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func newTestSessions() *sessions {
+	return &sessions{store: newMemoryStore(), ttl: time.Hour}
+}
+
+// withCookie returns a request carrying the session cookie rec set.
+func withCookie(t *testing.T, rec *httptest.ResponseRecorder) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range rec.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	return req
+}
+
+func TestSessions_Issue(t *testing.T) {
+	m := newTestSessions()
+	rec := httptest.NewRecorder()
+	s := session{State: "st"}
+	if err := m.issue(rec, httptest.NewRequest(http.MethodGet, "/", nil), &s); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if s.ID == "" || s.CreatedAt.IsZero() {
+		t.Fatalf("expected an ID and creation time, got %+v", s)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != sessionCookie || cookies[0].Value != s.ID {
+		t.Fatalf("expected the session cookie, got %+v", cookies)
+	}
+	if c := cookies[0]; !c.HttpOnly || c.MaxAge != 3600 || c.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("expected an HttpOnly, Lax cookie lasting the TTL, got %+v", c)
+	}
+
+	got, ok := m.load(withCookie(t, rec))
+	if !ok || got.ID != s.ID || got.State != "st" {
+		t.Fatalf("expected to load the issued session, got %+v (%v)", got, ok)
+	}
+}
+
+func TestSessions_Rotate(t *testing.T) {
+	m := newTestSessions()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	s := session{}
+	if err := m.issue(httptest.NewRecorder(), req, &s); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	old := s.ID
+
+	rec := httptest.NewRecorder()
+	s.Token = &oauth2.Token{AccessToken: "at"}
+	if err := m.rotate(rec, req, &s); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if s.ID == old {
+		t.Fatal("expected a new session ID")
+	}
+	if _, err := m.store.get(context.Background(), old); !errors.Is(err, errNoSession) {
+		t.Fatalf("expected the old ID to be gone, got %v", err)
+	}
+	got, ok := m.load(withCookie(t, rec))
+	if !ok || got.Token == nil || got.Token.AccessToken != "at" {
+		t.Fatalf("expected the rotated session to keep its token, got %+v (%v)", got, ok)
+	}
+}
+
+func TestSessions_Clear(t *testing.T) {
+	m := newTestSessions()
+	rec := httptest.NewRecorder()
+	s := session{}
+	if err := m.issue(rec, httptest.NewRequest(http.MethodGet, "/", nil), &s); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	cleared := httptest.NewRecorder()
+	if err := m.clear(cleared, withCookie(t, rec)); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if _, err := m.store.get(context.Background(), s.ID); !errors.Is(err, errNoSession) {
+		t.Fatalf("expected the session to be deleted, got %v", err)
+	}
+	cookies := cleared.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value != "" || cookies[0].MaxAge != -1 {
+		t.Fatalf("expected the cookie to be expired, got %+v", cookies)
+	}
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	store := newMemoryStore()
+	ctx := context.Background()
+	if err := store.save(ctx, session{ID: "stale"}, -time.Second); err != nil {
+		t.Fatalf("save stale: %v", err)
+	}
+	if _, err := store.get(ctx, "stale"); !errors.Is(err, errNoSession) {
+		t.Fatalf("expected an expired session to be gone, got %v", err)
+	}
+
+	if err := store.save(ctx, session{ID: "other"}, -time.Second); err != nil {
+		t.Fatalf("save other: %v", err)
+	}
+	if err := store.save(ctx, session{ID: "fresh"}, time.Hour); err != nil {
+		t.Fatalf("save fresh: %v", err)
+	}
+	if _, ok := store.sessions["other"]; ok {
+		t.Fatal("expected saving to sweep expired sessions")
+	}
+	if _, err := store.get(ctx, "fresh"); err != nil {
+		t.Fatalf("expected the fresh session, got %v", err)
+	}
+}
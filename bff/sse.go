@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// streamTimeout bounds a relayed stream. Intent analysis on a CPU-only
+// Ollama can take minutes, far beyond the proxy timeout. Playlist event
+// feeds are cut at this point too; EventSource reconnects on its own.
+const streamTimeout = 10 * time.Minute
+
+// streamedRoutes are allowed routes that answer with Server-Sent Events and
+// are relayed by relayStream instead of the plain proxy.
+var streamedRoutes = map[string]bool{
+	"POST /playlists/{id}/intent": true,
+	"GET /playlists/{id}/events":  true,
+}
+
+// envelope is the shape of every event the BFF sends to the browser: the
+// event name repeated as Type, so clients reading a single onmessage stream
+// can switch on it, a sequence number, and the backend's payload as-is.
+type envelope struct {
+	Type    string          `json:"type"`
+	Seq     int             `json:"seq"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// sseEvent is one event parsed from the backend's stream.
+type sseEvent struct {
+	name string
+	data string
+}
+
+// relayStream forwards a streaming request to the backend and relays its
+// events to the browser as they arrive, reshaped into envelopes. The
+// backend request is canceled as soon as the browser disconnects.
+func relayStream(backend backendTarget) http.HandlerFunc {
+	client := backend.client(0)
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), streamTimeout)
+		defer cancel()
+
+		out, err := http.NewRequestWithContext(ctx, r.Method, backend.url(r.URL.RequestURI()), http.MaxBytesReader(w, r.Body, maxProxyBody))
+		if err != nil {
+			writeProxyError(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		out.Header.Set("Content-Type", r.Header.Get("Content-Type"))
+		out.Header.Set("Accept", "text/event-stream")
+		out.Header.Set("X-Forwarded-For", clientIP(r))
+
+		resp, err := client.Do(out) // #nosec G107 -- fixed backend host
+		if err != nil {
+			if r.Context().Err() == nil {
+				slog.WarnContext(r.Context(), "stream failed", "path", r.URL.Path, "error", err)
+				writeProxyError(w, http.StatusBadGateway, "backend unavailable")
+			}
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		// Errors before the stream starts (bad input, unknown playlist) are
+		// plain JSON responses; pass them through unchanged.
+		if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+			w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+			w.WriteHeader(resp.StatusCode)
+			_, _ = io.Copy(w, resp.Body)
+			return
+		}
+
+		rc := http.NewResponseController(w)
+		// The server's write timeout is for ordinary requests.
+		_ = rc.SetWriteDeadline(time.Time{})
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		_ = rc.Flush()
+
+		seq := 0
+		err = readEvents(resp.Body, func(ev sseEvent) error {
+			seq++
+			return writeEnvelope(w, rc, reshape(ev, seq))
+		})
+		switch {
+		case r.Context().Err() != nil:
+			// The browser went away; the canceled context has already
+			// stopped the backend request.
+		case err != nil:
+			slog.WarnContext(r.Context(), "stream interrupted", "path", r.URL.Path, "error", err)
+			seq++
+			_ = writeEnvelope(w, rc, envelope{Type: "error", Seq: seq, Payload: json.RawMessage(`{"error":"stream interrupted"}`)})
+		}
+	}
+}
+
+// reshape maps a backend event to the envelope the browser sees. Status
+// events split into heartbeat and progress; complete becomes result.
+func reshape(ev sseEvent, seq int) envelope {
+	env := envelope{Type: ev.name, Seq: seq}
+	if json.Valid([]byte(ev.data)) {
+		env.Payload = json.RawMessage(ev.data)
+	} else if ev.data != "" {
+		env.Payload, _ = json.Marshal(ev.data)
+	}
+	switch ev.name {
+	case "status":
+		var status struct {
+			Status string `json:"status"`
+		}
+		_ = json.Unmarshal([]byte(ev.data), &status)
+		if status.Status == "heartbeat" {
+			env.Type, env.Payload = "heartbeat", nil
+		} else {
+			env.Type = "progress"
+		}
+	case "complete":
+		env.Type = "result"
+	case "":
+		env.Type = "message"
+	}
+	return env
+}
+
+// readEvents parses a Server-Sent Events stream, calling emit for each
+// event. Comments and retry fields are dropped.
+func readEvents(r io.Reader, emit func(sseEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	var ev sseEvent
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 || ev.name != "" {
+				ev.data = strings.Join(data, "\n")
+				if err := emit(ev); err != nil {
+					return err
+				}
+			}
+			ev, data = sseEvent{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.name = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// writeEnvelope sends env to the browser and flushes it immediately.
+func writeEnvelope(w http.ResponseWriter, rc *http.ResponseController, env envelope) error {
+	body, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", env.Seq, env.Type, body); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
+
+// writeProxyError writes a JSON error like the backend's.
+func writeProxyError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error":%q}`, msg)
+}
+
+// clientIP returns the address of the browser connected to the BFF.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []sseEvent
+	}{
+		{
+			name:   "named events",
+			stream: "event: status\ndata: {\"status\":\"analyzing\"}\n\nevent: complete\ndata: {}\n\n",
+			want:   []sseEvent{{name: "status", data: `{"status":"analyzing"}`}, {name: "complete", data: "{}"}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: first\ndata: second\ndata:third\n\n",
+			want:   []sseEvent{{data: "first\nsecond\nthird"}},
+		},
+		{
+			name:   "comments and retry fields",
+			stream: ": keep-alive\nretry: 1000\nevent: status\n: in the middle\ndata: x\n\n:\n\n",
+			want:   []sseEvent{{name: "status", data: "x"}},
+		},
+		{
+			name:   "trailing event without a blank line is incomplete",
+			stream: "event: status\ndata: x\n\nevent: complete\ndata: y\n",
+			want:   []sseEvent{{name: "status", data: "x"}},
+		},
+		{
+			name:   "event without data",
+			stream: "event: done\n\n",
+			want:   []sseEvent{{name: "done"}},
+		},
+		{
+			name:   "blank lines only",
+			stream: "\n\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []sseEvent
+			err := readEvents(strings.NewReader(tt.stream), func(ev sseEvent) error {
+				got = append(got, ev)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReadEvents_StopsOnEmitError(t *testing.T) {
+	errStop := errors.New("browser gone")
+	var calls int
+	err := readEvents(strings.NewReader("data: a\n\ndata: b\n\n"), func(sseEvent) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Fatalf("expected to stop after the first event with %v, got %d calls and %v", errStop, calls, err)
+	}
+}
+
+func TestReshape(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   sseEvent
+		want envelope
+	}{
+		{
+			name: "heartbeat",
+			ev:   sseEvent{name: "status", data: `{"status":"heartbeat"}`},
+			want: envelope{Type: "heartbeat", Seq: 1},
+		},
+		{
+			name: "progress",
+			ev:   sseEvent{name: "status", data: `{"status":"analyzing"}`},
+			want: envelope{Type: "progress", Seq: 1, Payload: []byte(`{"status":"analyzing"}`)},
+		},
+		{
+			name: "complete becomes result",
+			ev:   sseEvent{name: "complete", data: `{"tracks":[]}`},
+			want: envelope{Type: "result", Seq: 1, Payload: []byte(`{"tracks":[]}`)},
+		},
+		{
+			name: "unnamed becomes message",
+			ev:   sseEvent{data: `{"id":"e1"}`},
+			want: envelope{Type: "message", Seq: 1, Payload: []byte(`{"id":"e1"}`)},
+		},
+		{
+			name: "other names pass through",
+			ev:   sseEvent{name: "playlist.saved", data: `{"id":"e1"}`},
+			want: envelope{Type: "playlist.saved", Seq: 1, Payload: []byte(`{"id":"e1"}`)},
+		},
+		{
+			name: "text data is quoted",
+			ev:   sseEvent{name: "error", data: "ollama timed out"},
+			want: envelope{Type: "error", Seq: 1, Payload: []byte(`"ollama timed out"`)},
+		},
+		{
+			name: "no data",
+			ev:   sseEvent{name: "done"},
+			want: envelope{Type: "done", Seq: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reshape(tt.ev, 1)
+			if got.Type != tt.want.Type || got.Seq != tt.want.Seq || string(got.Payload) != string(tt.want.Payload) {
+				t.Fatalf("expected %s %d %s, got %s %d %s", tt.want.Type, tt.want.Seq, tt.want.Payload, got.Type, got.Seq, got.Payload)
+			}
+		})
+	}
+}
+
+func TestRelayStream(t *testing.T) {
+	proxy := newTestProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("expected the backend to be asked for a stream, got Accept %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: status\ndata: {\"status\":\"heartbeat\"}\n\nevent: complete\ndata: {\"ok\":true}\n\n")
+	}, time.Second)
+
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/playlists/p1/intent", strings.NewReader(`{"message":"x"}`)))
+	want := "id: 1\nevent: heartbeat\ndata: {\"type\":\"heartbeat\",\"seq\":1}\n\n" +
+		"id: 2\nevent: result\ndata: {\"type\":\"result\",\"seq\":2,\"payload\":{\"ok\":true}}\n\n"
+	if rec.Header().Get("Content-Type") != "text/event-stream" || rec.Body.String() != want {
+		t.Fatalf("expected %q, got %q (%s)", want, rec.Body.String(), rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestRelayStream_PassesErrorsThrough(t *testing.T) {
+	proxy := newTestProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":"playlist not found"}`)
+	}, time.Second)
+
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/playlists/p1/events", nil))
+	if rec.Code != http.StatusNotFound || rec.Body.String() != `{"error":"playlist not found"}` {
+		t.Fatalf("expected the backend's 404, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRelayStream_CancelsBackendOnDisconnect(t *testing.T) {
+	canceled := make(chan struct{})
+	proxy := newTestProxy(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: status\ndata: {\"status\":\"analyzing\"}\n\n")
+		_ = http.NewResponseController(w).Flush()
+		select {
+		case <-r.Context().Done():
+			close(canceled)
+		case <-time.After(5 * time.Second):
+		}
+	}, time.Second)
+	bff := httptest.NewServer(proxy)
+	defer bff.Close()
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	defer disconnect()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, bff.URL+"/api/playlists/p1/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// Wait for the first event, so the stream is flowing end to end.
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || line != "id: 1\n" {
+		t.Fatalf("expected the first event, got %q, %v", line, err)
+	}
+
+	disconnect()
+	select {
+	case <-canceled:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected the backend request to be canceled")
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// tlsSettings describes how the BFF terminates TLS, if at all.
+// Deployments behind an ingress leave everything empty and serve plain HTTP.
+type tlsSettings struct {
+	certFile         string
+	keyFile          string
+	autocertDomains  []string
+	autocertCacheDir string
+	autocertEmail    string
+	h2c              bool
+}
+
+func loadTLSSettings() tlsSettings {
+	s := tlsSettings{
+		certFile:         os.Getenv("TLS_CERT_FILE"),
+		keyFile:          os.Getenv("TLS_KEY_FILE"),
+		autocertCacheDir: os.Getenv("TLS_AUTOCERT_CACHE_DIR"),
+		autocertEmail:    os.Getenv("TLS_AUTOCERT_EMAIL"),
+		h2c:              os.Getenv("HTTP2_CLEARTEXT") == "true",
+	}
+	for _, domain := range strings.Split(os.Getenv("TLS_AUTOCERT_DOMAINS"), ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			s.autocertDomains = append(s.autocertDomains, domain)
+		}
+	}
+	if s.autocertCacheDir == "" {
+		s.autocertCacheDir = "autocert-cache"
+	}
+	return s
+}
+
+func (s tlsSettings) enabled() bool {
+	return s.certFile != "" || len(s.autocertDomains) > 0
+}
+
+func (s tlsSettings) validate() error {
+	if (s.certFile == "") != (s.keyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if s.certFile != "" && len(s.autocertDomains) > 0 {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_AUTOCERT_DOMAINS are mutually exclusive")
+	}
+	return nil
+}
+
+// configureServer applies the TLS and HTTP/2 settings to srv and returns the
+// function that starts serving. HTTP/2 is negotiated via ALPN when TLS is on;
+// without TLS, h2c is only enabled on request since most proxies expect HTTP/1.1.
+func configureServer(srv *http.Server, s tlsSettings) (func() error, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(s.h2c)
+	srv.Protocols = protocols
+
+	switch {
+	case s.certFile != "":
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		return func() error { return srv.ListenAndServeTLS(s.certFile, s.keyFile) }, nil
+	case len(s.autocertDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(s.autocertDomains...),
+			Cache:      autocert.DirCache(s.autocertCacheDir),
+			Email:      s.autocertEmail,
+		}
+		// manager.TLSConfig advertises h2 and acme-tls/1, so certificates are
+		// obtained through TLS-ALPN-01 without a separate port 80 listener.
+		srv.TLSConfig = manager.TLSConfig()
+		srv.TLSConfig.MinVersion = tls.VersionTLS12
+		return func() error { return srv.ListenAndServeTLS("", "") }, nil
+	default:
+		return srv.ListenAndServe, nil
+	}
+}
+
+func (s tlsSettings) scheme() string {
+	if s.enabled() {
+		return "https"
+	}
+	return "http"
+}
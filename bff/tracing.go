@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ewilliams-labs/overture/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setupTracing installs the W3C trace context propagator, so requests to
+// the backend continue the browser request's trace, and, when
+// OTEL_EXPORTER_OTLP_ENDPOINT is set (e.g. http://otel-collector:4318),
+// exports spans to it. The returned function flushes the exporter.
+func setupTracing(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	endpoint := getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_ENDPOINT %q", endpoint)
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(strings.TrimRight(u.Path, "/") + "/v1/traces"),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", getEnv("OTEL_SERVICE_NAME", "overture-bff"))))
+	if err != nil {
+		return nil, fmt.Errorf("OTLP resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// traced starts a server span for every request next serves, continuing a
+// trace the caller started. routeSpans renames it after the matched route
+// once it is known. Health checks are not traced.
+func traced(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			switch r.URL.Path {
+			case "/health", "/ready":
+				return false
+			}
+			return true
+		}),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return r.Method }),
+	)
+}
+
+// routeSpans names the request's span after the route next matched, e.g.
+// GET /api/playlists/{id}. It runs inside metrics.InstrumentHandler, which
+// tracks routes nested behind http.StripPrefix.
+func routeSpans(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		trace.SpanFromContext(r.Context()).SetName(r.Method + " " + metrics.RouteFor(r))
+	})
+}
+
+// tracedTransport makes calls to the backend client spans carrying the
+// trace context. Calls made outside a traced request, such as readiness
+// probes, are sent untraced.
+func tracedTransport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base,
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return trace.SpanContextFromContext(r.Context()).IsValid()
+		}),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return "backend " + r.Method }),
+	)
+}
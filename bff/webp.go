@@ -0,0 +1,1132 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+)
+
+// This file encodes covers as lossy WebP: a single VP8 key frame in a RIFF
+// container, as specified in RFC 6386. The standard library has no WebP
+// encoder, and covers need little of what VP8 offers, so macroblocks are
+// only predicted as a whole, with one quantizer and the default token
+// probabilities. The output still comes out smaller than JPEG at the same
+// quality.
+
+const (
+	// webpQuantizer is the VP8 quantizer index, from 0 (finest) to 127,
+	// chosen to match the quality of coverQuality.
+	webpQuantizer = 32
+	// webpFilterLevel is the strength of the loop filter decoders apply
+	// to soften block edges, from 0 (off) to 63.
+	webpFilterLevel = 12
+	// maxWebPSize is the largest width or height VP8 can code.
+	maxWebPSize = 16383
+)
+
+// VP8 coefficient planes, which select token probabilities (section 13.3).
+const (
+	planeYAfterY2 = iota
+	planeY2
+	planeUV
+)
+
+// vp8Zigzag is the order coefficients are coded in (section 13).
+var vp8Zigzag = [16]int{0, 1, 4, 8, 5, 2, 3, 6, 9, 12, 13, 10, 7, 11, 14, 15}
+
+// vp8Bands maps a coefficient's position to its probability band.
+var vp8Bands = [17]int{0, 1, 2, 3, 6, 4, 5, 6, 6, 6, 6, 6, 6, 6, 6, 7, 0}
+
+// vp8Cat3456 are the extra-bit probabilities of the large value categories
+// (section 13.2).
+var vp8Cat3456 = [4][]uint8{
+	{173, 148, 140},
+	{176, 155, 140, 135},
+	{180, 157, 141, 134, 130},
+	{254, 254, 243, 230, 196, 177, 153, 140, 133, 130, 129},
+}
+
+// encodeWebP writes img to w as a lossy WebP. Transparency is dropped.
+func encodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	if b.Dx() < 1 || b.Dy() < 1 || b.Dx() > maxWebPSize || b.Dy() > maxWebPSize {
+		return fmt.Errorf("webp: cannot encode a %dx%d image", b.Dx(), b.Dy())
+	}
+	frame := newVP8Encoder(img, webpQuantizer).encode()
+
+	// A RIFF chunk's payload is padded to an even length.
+	pad := len(frame) & 1
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(12+len(frame)+pad))
+	copy(header[8:], "WEBPVP8 ")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(frame)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(frame); err != nil {
+		return err
+	}
+	if pad == 1 {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+// vp8Quant are the dequantization factors of each plane, DC first.
+type vp8Quant struct {
+	y1, y2, uv [2]int32
+}
+
+func newVP8Quant(q int) vp8Quant {
+	y2AC := int32(vp8DequantAC[q]) * 155 / 100
+	if y2AC < 8 {
+		y2AC = 8
+	}
+	return vp8Quant{
+		y1: [2]int32{int32(vp8DequantDC[q]), int32(vp8DequantAC[q])},
+		y2: [2]int32{int32(vp8DequantDC[q]) * 2, y2AC},
+		uv: [2]int32{int32(vp8DequantDC[min(q, 117)]), int32(vp8DequantAC[q])},
+	}
+}
+
+// vp8Encoder encodes one image as a VP8 key frame. It reconstructs every
+// macroblock exactly as a decoder will, since later macroblocks are
+// predicted from earlier ones.
+type vp8Encoder struct {
+	width, height int
+	mbw, mbh      int
+	q             int
+	quant         vp8Quant
+	// src are the source planes and rec the reconstructed ones, padded to
+	// whole macroblocks.
+	src, rec [3][]uint8
+	// nzLeft and nzAbove record which blocks beside the current macroblock
+	// had coefficients: four luma, two of each chroma and the Y2 block.
+	nzLeft  [9]uint8
+	nzAbove [][9]uint8
+	modes   boolEncoder
+	tokens  boolEncoder
+}
+
+func newVP8Encoder(img image.Image, q int) *vp8Encoder {
+	b := img.Bounds()
+	e := &vp8Encoder{
+		width:  b.Dx(),
+		height: b.Dy(),
+		mbw:    (b.Dx() + 15) / 16,
+		mbh:    (b.Dy() + 15) / 16,
+		q:      q,
+		quant:  newVP8Quant(q),
+		modes:  newBoolEncoder(),
+		tokens: newBoolEncoder(),
+	}
+	e.nzAbove = make([][9]uint8, e.mbw)
+	ys, cs := e.stride(0), e.stride(1)
+	for i := range e.src {
+		n := ys * e.mbh * 16
+		if i > 0 {
+			n = cs * e.mbh * 8
+		}
+		e.src[i] = make([]uint8, n)
+		e.rec[i] = make([]uint8, n)
+	}
+
+	// Pixels past the image repeat its last row and column. Conversion
+	// to BT.601 YCbCr follows libwebp, chroma averaging 2x2 pixels.
+	rgb := func(x, y int) (int, int, int) {
+		r, g, bl, _ := img.At(b.Min.X+min(x, e.width-1), b.Min.Y+min(y, e.height-1)).RGBA()
+		return int(r >> 8), int(g >> 8), int(bl >> 8)
+	}
+	for y := 0; y < e.mbh*16; y++ {
+		for x := 0; x < e.mbw*16; x++ {
+			r, g, bl := rgb(x, y)
+			e.src[0][y*ys+x] = uint8((16839*r + 33059*g + 6420*bl + 1<<15 + 16<<16) >> 16)
+		}
+	}
+	for y := 0; y < e.mbh*8; y++ {
+		for x := 0; x < e.mbw*8; x++ {
+			var r, g, bl int
+			for j := 0; j < 2; j++ {
+				for i := 0; i < 2; i++ {
+					pr, pg, pb := rgb(2*x+i, 2*y+j)
+					r, g, bl = r+pr, g+pg, bl+pb
+				}
+			}
+			e.src[1][y*cs+x] = clipUV(-9719*r - 19081*g + 28800*bl)
+			e.src[2][y*cs+x] = clipUV(28800*r - 24116*g - 4684*bl)
+		}
+	}
+	return e
+}
+
+// clipUV scales a chroma sum of four pixels to 8 bits.
+func clipUV(v int) uint8 {
+	v = (v + 1<<17 + 128<<18) >> 18
+	return uint8(max(0, min(255, v)))
+}
+
+// stride is the row length of plane p.
+func (e *vp8Encoder) stride(p int) int {
+	if p == 0 {
+		return e.mbw * 16
+	}
+	return e.mbw * 8
+}
+
+// encode returns the VP8 frame.
+func (e *vp8Encoder) encode() []byte {
+	e.writeHeader()
+	for mby := 0; mby < e.mbh; mby++ {
+		e.nzLeft = [9]uint8{}
+		for mbx := 0; mbx < e.mbw; mbx++ {
+			e.encodeMacroblock(mbx, mby)
+		}
+	}
+	first := e.modes.flush()
+	tokens := e.tokens.flush()
+
+	frame := make([]byte, 0, 10+len(first)+len(tokens))
+	// Frame tag: a shown key frame of version 0 and its first partition's
+	// size, then the key frame start code and dimensions.
+	tag := uint32(len(first))<<5 | 1<<4
+	frame = append(frame, byte(tag), byte(tag>>8), byte(tag>>16), 0x9d, 0x01, 0x2a)
+	frame = binary.LittleEndian.AppendUint16(frame, uint16(e.width))
+	frame = binary.LittleEndian.AppendUint16(frame, uint16(e.height))
+	frame = append(frame, first...)
+	return append(frame, tokens...)
+}
+
+// writeHeader writes the frame header (section 9) to the first partition.
+func (e *vp8Encoder) writeHeader() {
+	m := &e.modes
+	m.flag(false) // color space
+	m.flag(false) // clamping type
+	m.flag(false) // segmentation
+	m.flag(false) // normal loop filter
+	m.literal(6, webpFilterLevel)
+	m.literal(3, 0) // sharpness
+	m.flag(false)   // loop filter deltas
+	m.literal(2, 0) // one token partition
+	m.literal(7, e.q)
+	for i := 0; i < 5; i++ {
+		m.flag(false) // quantizer deltas
+	}
+	m.flag(false) // refresh entropy probabilities
+	for i := range vp8TokenProbUpdate {
+		for j := range vp8TokenProbUpdate[i] {
+			for k := range vp8TokenProbUpdate[i][j] {
+				for _, p := range vp8TokenProbUpdate[i][j][k] {
+					m.put(false, p) // keep the default token probabilities
+				}
+			}
+		}
+	}
+	m.flag(false) // no macroblock skipping
+}
+
+// Intra prediction modes of whole macroblocks (section 12.2).
+const (
+	predDC = iota
+	predV
+	predH
+	predTM
+)
+
+// encodeMacroblock codes the macroblock at mbx, mby: its modes to the first
+// partition and its coefficients to the token partition.
+func (e *vp8Encoder) encodeMacroblock(mbx, mby int) {
+	above := &e.nzAbove[mbx]
+
+	// Luma is predicted as a whole, and each 4x4 block's DC goes to the
+	// Y2 block, which is coded first.
+	lumaMode, lumaPred := e.choosePrediction(mbx, mby, 0)
+	e.modes.put(true, 145)
+	switch lumaMode {
+	case predDC, predV:
+		e.modes.put(false, 156)
+		e.modes.put(lumaMode == predV, 163)
+	default:
+		e.modes.put(true, 156)
+		e.modes.put(lumaMode == predTM, 128)
+	}
+	var blocks [16][16]int32
+	e.transform(0, mbx*16, mby*16, 16, lumaPred[0], blocks[:])
+	var dc, y2 [16]int32
+	for i := range blocks {
+		dc[i] = blocks[i][0]
+	}
+	fwht4(&dc, &y2)
+	quantizeBlock(&y2, e.quant.y2, 0)
+	nz := e.writeBlock(&y2, planeY2, 0, e.nzLeft[8]+above[8])
+	e.nzLeft[8], above[8] = nz, nz
+	dequantizeBlock(&y2, e.quant.y2)
+	iwht4(&y2, &dc)
+	for by := 0; by < 4; by++ {
+		for bx := 0; bx < 4; bx++ {
+			blk := &blocks[by*4+bx]
+			quantizeBlock(blk, e.quant.y1, 1)
+			nz := e.writeBlock(blk, planeYAfterY2, 1, e.nzLeft[by]+above[bx])
+			e.nzLeft[by], above[bx] = nz, nz
+			dequantizeBlock(blk, e.quant.y1)
+			blk[0] = dc[by*4+bx]
+		}
+	}
+	e.reconstruct(0, mbx*16, mby*16, 16, lumaPred[0], blocks[:])
+
+	// Chroma: both 8x8 planes share a mode and have four blocks each.
+	chromaMode, chromaPred := e.choosePrediction(mbx, mby, 1, 2)
+	e.modes.put(chromaMode != predDC, 142)
+	if chromaMode != predDC {
+		e.modes.put(chromaMode != predV, 114)
+		if chromaMode != predV {
+			e.modes.put(chromaMode == predTM, 183)
+		}
+	}
+	for p := 1; p <= 2; p++ {
+		var blocks [4][16]int32
+		e.transform(p, mbx*8, mby*8, 8, chromaPred[p-1], blocks[:])
+		off := 4 + 2*(p-1)
+		for by := 0; by < 2; by++ {
+			for bx := 0; bx < 2; bx++ {
+				blk := &blocks[by*2+bx]
+				quantizeBlock(blk, e.quant.uv, 0)
+				nz := e.writeBlock(blk, planeUV, 0, e.nzLeft[off+by]+above[off+bx])
+				e.nzLeft[off+by], above[off+bx] = nz, nz
+				dequantizeBlock(blk, e.quant.uv)
+			}
+		}
+		e.reconstruct(p, mbx*8, mby*8, 8, chromaPred[p-1], blocks[:])
+	}
+}
+
+// choosePrediction returns the mode that best predicts the macroblock at
+// mbx, mby in planes, and the prediction of each plane. Only modes whose
+// neighbors are inside the frame are tried, since decoders disagree on
+// what lies outside it.
+func (e *vp8Encoder) choosePrediction(mbx, mby int, planes ...int) (int, [][]int32) {
+	left, top := mbx > 0, mby > 0
+	bestMode, bestCost := -1, 0
+	var best [][]int32
+	for mode := predDC; mode <= predTM; mode++ {
+		if (mode == predV || mode == predTM) && !top || (mode == predH || mode == predTM) && !left {
+			continue
+		}
+		preds := make([][]int32, len(planes))
+		cost := 0
+		for i, p := range planes {
+			size := 16
+			if p > 0 {
+				size = 8
+			}
+			x, y := mbx*size, mby*size
+			preds[i] = e.predict(p, x, y, size, mode)
+			src, stride := e.src[p], e.stride(p)
+			for j := 0; j < size; j++ {
+				for k := 0; k < size; k++ {
+					d := int(src[(y+j)*stride+x+k]) - int(preds[i][j*size+k])
+					cost += max(d, -d)
+				}
+			}
+		}
+		if bestMode < 0 || cost < bestCost {
+			bestMode, bestCost, best = mode, cost, preds
+		}
+	}
+	return bestMode, best
+}
+
+// predict returns the prediction in mode of the size x size region of plane
+// p at x, y, made from the reconstructed row above and column to the left.
+func (e *vp8Encoder) predict(p, x, y, size, mode int) []int32 {
+	rec, stride := e.rec[p], e.stride(p)
+	topAt := func(i int) int32 { return int32(rec[(y-1)*stride+x+i]) }
+	leftAt := func(j int) int32 { return int32(rec[(y+j)*stride+x-1]) }
+	pred := make([]int32, size*size)
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			var v int32
+			switch mode {
+			case predV:
+				v = topAt(i)
+			case predH:
+				v = leftAt(j)
+			case predTM:
+				v = max(0, min(255, leftAt(j)+topAt(i)-int32(rec[(y-1)*stride+x-1])))
+			}
+			pred[j*size+i] = v
+		}
+	}
+	if mode != predDC {
+		return pred
+	}
+
+	sum, n := 0, 0
+	if y > 0 {
+		for i := 0; i < size; i++ {
+			sum += int(topAt(i))
+		}
+		n += size
+	}
+	if x > 0 {
+		for j := 0; j < size; j++ {
+			sum += int(leftAt(j))
+		}
+		n += size
+	}
+	dc := int32(128)
+	if n > 0 {
+		dc = int32((sum + n/2) / n)
+	}
+	for i := range pred {
+		pred[i] = dc
+	}
+	return pred
+}
+
+// transform returns the DCT of each 4x4 block of the residual between the
+// size x size region of plane p at x, y and its prediction, in raster order.
+func (e *vp8Encoder) transform(p, x, y, size int, pred []int32, blocks [][16]int32) {
+	src, stride := e.src[p], e.stride(p)
+	for by := 0; by < size/4; by++ {
+		for bx := 0; bx < size/4; bx++ {
+			var res [16]int32
+			for j := 0; j < 4; j++ {
+				for i := 0; i < 4; i++ {
+					py, px := by*4+j, bx*4+i
+					res[j*4+i] = int32(src[(y+py)*stride+x+px]) - pred[py*size+px]
+				}
+			}
+			fdct4(&res, &blocks[by*(size/4)+bx])
+		}
+	}
+}
+
+// reconstruct adds the dequantized residual blocks to the prediction of the
+// size x size region of plane p at x, y, as a decoder does.
+func (e *vp8Encoder) reconstruct(p, x, y, size int, pred []int32, blocks [][16]int32) {
+	rec, stride := e.rec[p], e.stride(p)
+	for by := 0; by < size/4; by++ {
+		for bx := 0; bx < size/4; bx++ {
+			var out [16]int32
+			idct4(&blocks[by*(size/4)+bx], &out)
+			for j := 0; j < 4; j++ {
+				for i := 0; i < 4; i++ {
+					py, px := by*4+j, bx*4+i
+					v := pred[py*size+px] + out[j*4+i]
+					rec[(y+py)*stride+x+px] = uint8(max(0, min(255, v)))
+				}
+			}
+		}
+	}
+}
+
+// quantizeBlock quantizes the coefficients of blk from index first on. DC
+// rounds to nearest; AC rounds down a little more, which drops noise that
+// would cost bits.
+func quantizeBlock(blk *[16]int32, q [2]int32, first int) {
+	for i := first; i < 16; i++ {
+		f := q[min(i, 1)]
+		bias := f / 2
+		if i > 0 {
+			bias = f * 3 / 8
+		}
+		v := blk[i]
+		neg := v < 0
+		if neg {
+			v = -v
+		}
+		v = min((v+bias)/f, 2048)
+		if neg {
+			v = -v
+		}
+		blk[i] = v
+	}
+}
+
+func dequantizeBlock(blk *[16]int32, q [2]int32) {
+	for i := range blk {
+		blk[i] *= q[min(i, 1)]
+	}
+}
+
+// writeBlock codes the quantized coefficients of blk from index first on
+// (section 13) and reports whether any was non-zero. ctx counts the
+// neighboring blocks that had coefficients.
+func (e *vp8Encoder) writeBlock(blk *[16]int32, plane, first int, ctx uint8) uint8 {
+	t := &e.tokens
+	last := -1
+	for n := first; n < 16; n++ {
+		if blk[vp8Zigzag[n]] != 0 {
+			last = n
+		}
+	}
+	probs := &vp8DefaultTokenProb[plane]
+	p := &probs[vp8Bands[first]][ctx]
+	if last < 0 {
+		t.put(false, p[0]) // end of block
+		return 0
+	}
+	t.put(true, p[0])
+	for n := first; n < 16; {
+		v := blk[vp8Zigzag[n]]
+		n++
+		if v == 0 {
+			t.put(false, p[1])
+			// A zero is never followed by the end of the block.
+			p = &probs[vp8Bands[n]][0]
+			continue
+		}
+		t.put(true, p[1])
+		abs := v
+		if abs < 0 {
+			abs = -abs
+		}
+		writeTokenValue(t, p, abs)
+		t.flag(v < 0)
+		if abs == 1 {
+			p = &probs[vp8Bands[n]][1]
+		} else {
+			p = &probs[vp8Bands[n]][2]
+		}
+		if n == 16 {
+			break
+		}
+		if n > last {
+			t.put(false, p[0])
+			break
+		}
+		t.put(true, p[0])
+	}
+	return 1
+}
+
+// writeTokenValue codes a non-zero coefficient's magnitude v with the token
+// tree (section 13.2).
+func writeTokenValue(t *boolEncoder, p *[11]uint8, v int32) {
+	if v == 1 {
+		t.put(false, p[2])
+		return
+	}
+	t.put(true, p[2])
+	switch {
+	case v <= 4:
+		t.put(false, p[3])
+		if v == 2 {
+			t.put(false, p[4])
+			return
+		}
+		t.put(true, p[4])
+		t.put(v == 4, p[5])
+	case v <= 10:
+		t.put(true, p[3])
+		t.put(false, p[6])
+		if v <= 6 {
+			t.put(false, p[7])
+			t.put(v == 6, 159)
+			return
+		}
+		t.put(true, p[7])
+		t.put((v-7)&2 != 0, 165)
+		t.put((v-7)&1 != 0, 145)
+	default:
+		t.put(true, p[3])
+		t.put(true, p[6])
+		cat := 3
+		switch {
+		case v < 19:
+			cat = 0
+		case v < 35:
+			cat = 1
+		case v < 67:
+			cat = 2
+		}
+		t.put(cat >= 2, p[8])
+		t.put(cat&1 == 1, p[9+cat/2])
+		extra := v - 3 - 8<<cat
+		probs := vp8Cat3456[cat]
+		for i, prob := range probs {
+			t.put(extra>>(len(probs)-1-i)&1 == 1, prob)
+		}
+	}
+}
+
+// fdct4 is the forward DCT of a 4x4 residual, as in libvpx.
+func fdct4(in, out *[16]int32) {
+	var tmp [16]int32
+	for i := 0; i < 4; i++ {
+		r := in[i*4 : i*4+4]
+		a := (r[0] + r[3]) * 8
+		b := (r[1] + r[2]) * 8
+		c := (r[1] - r[2]) * 8
+		d := (r[0] - r[3]) * 8
+		tmp[i*4+0] = a + b
+		tmp[i*4+2] = a - b
+		tmp[i*4+1] = (c*2217 + d*5352 + 14500) >> 12
+		tmp[i*4+3] = (d*2217 - c*5352 + 7500) >> 12
+	}
+	for i := 0; i < 4; i++ {
+		a := tmp[i] + tmp[12+i]
+		b := tmp[4+i] + tmp[8+i]
+		c := tmp[4+i] - tmp[8+i]
+		d := tmp[i] - tmp[12+i]
+		out[i] = (a + b + 7) >> 4
+		out[8+i] = (a - b + 7) >> 4
+		out[4+i] = (c*2217+d*5352+12000)>>16 + b2i(d != 0)
+		out[12+i] = (d*2217 - c*5352 + 51000) >> 16
+	}
+}
+
+// idct4 is the inverse DCT (section 14.3). It must match decoders exactly.
+func idct4(in, out *[16]int32) {
+	const (
+		c1 = 85627 // 65536 * cos(pi/8) * sqrt(2)
+		c2 = 35468 // 65536 * sin(pi/8) * sqrt(2)
+	)
+	var m [4][4]int32
+	for i := 0; i < 4; i++ {
+		a := in[i] + in[8+i]
+		b := in[i] - in[8+i]
+		c := (in[4+i]*c2)>>16 - (in[12+i]*c1)>>16
+		d := (in[4+i]*c1)>>16 + (in[12+i]*c2)>>16
+		m[i] = [4]int32{a + d, b + c, b - c, a - d}
+	}
+	for j := 0; j < 4; j++ {
+		dc := m[0][j] + 4
+		a := dc + m[2][j]
+		b := dc - m[2][j]
+		c := (m[1][j]*c2)>>16 - (m[3][j]*c1)>>16
+		d := (m[1][j]*c1)>>16 + (m[3][j]*c2)>>16
+		for i, v := range [4]int32{a + d, b + c, b - c, a - d} {
+			out[j*4+i] = v >> 3
+		}
+	}
+}
+
+// fwht4 is the forward Walsh-Hadamard transform of the 16 luma DCs, as in
+// libvpx.
+func fwht4(in, out *[16]int32) {
+	var tmp [16]int32
+	for i := 0; i < 4; i++ {
+		r := in[i*4 : i*4+4]
+		a := (r[0] + r[2]) * 4
+		d := (r[1] + r[3]) * 4
+		c := (r[1] - r[3]) * 4
+		b := (r[0] - r[2]) * 4
+		tmp[i*4+0] = a + d + b2i(a != 0)
+		tmp[i*4+1] = b + c
+		tmp[i*4+2] = b - c
+		tmp[i*4+3] = a - d
+	}
+	for i := 0; i < 4; i++ {
+		a := tmp[i] + tmp[8+i]
+		d := tmp[4+i] + tmp[12+i]
+		c := tmp[4+i] - tmp[12+i]
+		b := tmp[i] - tmp[8+i]
+		for k, v := range [4]int32{a + d, b + c, b - c, a - d} {
+			if v < 0 {
+				v++
+			}
+			out[4*k+i] = (v + 3) >> 3
+		}
+	}
+}
+
+// iwht4 is the inverse Walsh-Hadamard transform (section 14.3). It must
+// match decoders exactly.
+func iwht4(in, out *[16]int32) {
+	var m [16]int32
+	for i := 0; i < 4; i++ {
+		a0 := in[i] + in[12+i]
+		a1 := in[4+i] + in[8+i]
+		a2 := in[4+i] - in[8+i]
+		a3 := in[i] - in[12+i]
+		m[i] = a0 + a1
+		m[8+i] = a0 - a1
+		m[4+i] = a3 + a2
+		m[12+i] = a3 - a2
+	}
+	for i := 0; i < 4; i++ {
+		dc := m[i*4] + 3
+		a0 := dc + m[i*4+3]
+		a1 := m[i*4+1] + m[i*4+2]
+		a2 := m[i*4+1] - m[i*4+2]
+		a3 := dc - m[i*4+3]
+		out[i*4+0] = (a0 + a1) >> 3
+		out[i*4+1] = (a3 + a2) >> 3
+		out[i*4+2] = (a0 - a1) >> 3
+		out[i*4+3] = (a3 - a2) >> 3
+	}
+}
+
+func b2i(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// boolEncoder is VP8's boolean entropy coder (section 7.3).
+type boolEncoder struct {
+	buf      []byte
+	rng      uint32
+	bottom   uint32
+	bitCount int
+}
+
+func newBoolEncoder() boolEncoder {
+	return boolEncoder{rng: 255, bitCount: 24}
+}
+
+// put codes bit, which is false with probability prob/256.
+func (e *boolEncoder) put(bit bool, prob uint8) {
+	split := 1 + (e.rng-1)*uint32(prob)>>8
+	if bit {
+		e.bottom += split
+		e.rng -= split
+	} else {
+		e.rng = split
+	}
+	for e.rng < 128 {
+		e.rng <<= 1
+		if e.bottom&(1<<31) != 0 {
+			e.carry()
+		}
+		e.bottom <<= 1
+		e.bitCount--
+		if e.bitCount == 0 {
+			e.buf = append(e.buf, byte(e.bottom>>24))
+			e.bottom &= 1<<24 - 1
+			e.bitCount = 8
+		}
+	}
+}
+
+// flag codes an evenly likely bit.
+func (e *boolEncoder) flag(bit bool) {
+	e.put(bit, 128)
+}
+
+// literal codes the n low bits of v, most significant first.
+func (e *boolEncoder) literal(n, v int) {
+	for i := n - 1; i >= 0; i-- {
+		e.flag(v>>i&1 == 1)
+	}
+}
+
+// carry propagates a carry into the bytes already written.
+func (e *boolEncoder) carry() {
+	i := len(e.buf) - 1
+	for ; i >= 0 && e.buf[i] == 255; i-- {
+		e.buf[i] = 0
+	}
+	if i >= 0 {
+		e.buf[i]++
+	}
+}
+
+// flush writes out the coder's remaining state and returns the bytes.
+func (e *boolEncoder) flush() []byte {
+	c := e.bitCount
+	v := e.bottom
+	if v&(1<<(32-c)) != 0 {
+		e.carry()
+	}
+	v <<= c & 7
+	for c >>= 3; c > 0; c-- {
+		v <<= 8
+	}
+	for i := 0; i < 4; i++ {
+		e.buf = append(e.buf, byte(v>>24))
+		v <<= 8
+	}
+	return e.buf
+}
+
+// vp8TokenProbUpdate are the probabilities that each token probability is
+// updated in the frame header (section 13.4).
+var vp8TokenProbUpdate = [4][8][3][11]uint8{
+	{
+		{
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{176, 246, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{223, 241, 252, 255, 255, 255, 255, 255, 255, 255, 255},
+			{249, 253, 253, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 244, 252, 255, 255, 255, 255, 255, 255, 255, 255},
+			{234, 254, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{253, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 246, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{239, 253, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{254, 255, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 248, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{251, 255, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 253, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{251, 254, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{254, 255, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 254, 253, 255, 254, 255, 255, 255, 255, 255, 255},
+			{250, 255, 254, 255, 254, 255, 255, 255, 255, 255, 255},
+			{254, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+	},
+	{
+		{
+			{217, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{225, 252, 241, 253, 255, 255, 254, 255, 255, 255, 255},
+			{234, 250, 241, 250, 253, 255, 253, 254, 255, 255, 255},
+		},
+		{
+			{255, 254, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{223, 254, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{238, 253, 254, 254, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 248, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{249, 254, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 253, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{247, 254, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 253, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{252, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 254, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{253, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 254, 253, 255, 255, 255, 255, 255, 255, 255, 255},
+			{250, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{254, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+	},
+	{
+		{
+			{186, 251, 250, 255, 255, 255, 255, 255, 255, 255, 255},
+			{234, 251, 244, 254, 255, 255, 255, 255, 255, 255, 255},
+			{251, 251, 243, 253, 254, 255, 254, 255, 255, 255, 255},
+		},
+		{
+			{255, 253, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{236, 253, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{251, 253, 253, 254, 254, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 254, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{254, 254, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 254, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{254, 254, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{254, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{254, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+	},
+	{
+		{
+			{248, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{250, 254, 252, 254, 255, 255, 255, 255, 255, 255, 255},
+			{248, 254, 249, 253, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 253, 253, 255, 255, 255, 255, 255, 255, 255, 255},
+			{246, 253, 253, 255, 255, 255, 255, 255, 255, 255, 255},
+			{252, 254, 251, 254, 254, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 254, 252, 255, 255, 255, 255, 255, 255, 255, 255},
+			{248, 254, 253, 255, 255, 255, 255, 255, 255, 255, 255},
+			{253, 255, 254, 254, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 251, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{245, 251, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{253, 253, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 251, 253, 255, 255, 255, 255, 255, 255, 255, 255},
+			{252, 253, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 254, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 252, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{249, 255, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 254, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 255, 253, 255, 255, 255, 255, 255, 255, 255, 255},
+			{250, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+		{
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{254, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		},
+	},
+}
+
+// vp8DefaultTokenProb are the token probabilities of a key frame (section
+// 13.5).
+var vp8DefaultTokenProb = [4][8][3][11]uint8{
+	{
+		{
+			{128, 128, 128, 128, 128, 128, 128, 128, 128, 128, 128},
+			{128, 128, 128, 128, 128, 128, 128, 128, 128, 128, 128},
+			{128, 128, 128, 128, 128, 128, 128, 128, 128, 128, 128},
+		},
+		{
+			{253, 136, 254, 255, 228, 219, 128, 128, 128, 128, 128},
+			{189, 129, 242, 255, 227, 213, 255, 219, 128, 128, 128},
+			{106, 126, 227, 252, 214, 209, 255, 255, 128, 128, 128},
+		},
+		{
+			{1, 98, 248, 255, 236, 226, 255, 255, 128, 128, 128},
+			{181, 133, 238, 254, 221, 234, 255, 154, 128, 128, 128},
+			{78, 134, 202, 247, 198, 180, 255, 219, 128, 128, 128},
+		},
+		{
+			{1, 185, 249, 255, 243, 255, 128, 128, 128, 128, 128},
+			{184, 150, 247, 255, 236, 224, 128, 128, 128, 128, 128},
+			{77, 110, 216, 255, 236, 230, 128, 128, 128, 128, 128},
+		},
+		{
+			{1, 101, 251, 255, 241, 255, 128, 128, 128, 128, 128},
+			{170, 139, 241, 252, 236, 209, 255, 255, 128, 128, 128},
+			{37, 116, 196, 243, 228, 255, 255, 255, 128, 128, 128},
+		},
+		{
+			{1, 204, 254, 255, 245, 255, 128, 128, 128, 128, 128},
+			{207, 160, 250, 255, 238, 128, 128, 128, 128, 128, 128},
+			{102, 103, 231, 255, 211, 171, 128, 128, 128, 128, 128},
+		},
+		{
+			{1, 152, 252, 255, 240, 255, 128, 128, 128, 128, 128},
+			{177, 135, 243, 255, 234, 225, 128, 128, 128, 128, 128},
+			{80, 129, 211, 255, 194, 224, 128, 128, 128, 128, 128},
+		},
+		{
+			{1, 1, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+			{246, 1, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+			{255, 128, 128, 128, 128, 128, 128, 128, 128, 128, 128},
+		},
+	},
+	{
+		{
+			{198, 35, 237, 223, 193, 187, 162, 160, 145, 155, 62},
+			{131, 45, 198, 221, 172, 176, 220, 157, 252, 221, 1},
+			{68, 47, 146, 208, 149, 167, 221, 162, 255, 223, 128},
+		},
+		{
+			{1, 149, 241, 255, 221, 224, 255, 255, 128, 128, 128},
+			{184, 141, 234, 253, 222, 220, 255, 199, 128, 128, 128},
+			{81, 99, 181, 242, 176, 190, 249, 202, 255, 255, 128},
+		},
+		{
+			{1, 129, 232, 253, 214, 197, 242, 196, 255, 255, 128},
+			{99, 121, 210, 250, 201, 198, 255, 202, 128, 128, 128},
+			{23, 91, 163, 242, 170, 187, 247, 210, 255, 255, 128},
+		},
+		{
+			{1, 200, 246, 255, 234, 255, 128, 128, 128, 128, 128},
+			{109, 178, 241, 255, 231, 245, 255, 255, 128, 128, 128},
+			{44, 130, 201, 253, 205, 192, 255, 255, 128, 128, 128},
+		},
+		{
+			{1, 132, 239, 251, 219, 209, 255, 165, 128, 128, 128},
+			{94, 136, 225, 251, 218, 190, 255, 255, 128, 128, 128},
+			{22, 100, 174, 245, 186, 161, 255, 199, 128, 128, 128},
+		},
+		{
+			{1, 182, 249, 255, 232, 235, 128, 128, 128, 128, 128},
+			{124, 143, 241, 255, 227, 234, 128, 128, 128, 128, 128},
+			{35, 77, 181, 251, 193, 211, 255, 205, 128, 128, 128},
+		},
+		{
+			{1, 157, 247, 255, 236, 231, 255, 255, 128, 128, 128},
+			{121, 141, 235, 255, 225, 227, 255, 255, 128, 128, 128},
+			{45, 99, 188, 251, 195, 217, 255, 224, 128, 128, 128},
+		},
+		{
+			{1, 1, 251, 255, 213, 255, 128, 128, 128, 128, 128},
+			{203, 1, 248, 255, 255, 128, 128, 128, 128, 128, 128},
+			{137, 1, 177, 255, 224, 255, 128, 128, 128, 128, 128},
+		},
+	},
+	{
+		{
+			{253, 9, 248, 251, 207, 208, 255, 192, 128, 128, 128},
+			{175, 13, 224, 243, 193, 185, 249, 198, 255, 255, 128},
+			{73, 17, 171, 221, 161, 179, 236, 167, 255, 234, 128},
+		},
+		{
+			{1, 95, 247, 253, 212, 183, 255, 255, 128, 128, 128},
+			{239, 90, 244, 250, 211, 209, 255, 255, 128, 128, 128},
+			{155, 77, 195, 248, 188, 195, 255, 255, 128, 128, 128},
+		},
+		{
+			{1, 24, 239, 251, 218, 219, 255, 205, 128, 128, 128},
+			{201, 51, 219, 255, 196, 186, 128, 128, 128, 128, 128},
+			{69, 46, 190, 239, 201, 218, 255, 228, 128, 128, 128},
+		},
+		{
+			{1, 191, 251, 255, 255, 128, 128, 128, 128, 128, 128},
+			{223, 165, 249, 255, 213, 255, 128, 128, 128, 128, 128},
+			{141, 124, 248, 255, 255, 128, 128, 128, 128, 128, 128},
+		},
+		{
+			{1, 16, 248, 255, 255, 128, 128, 128, 128, 128, 128},
+			{190, 36, 230, 255, 236, 255, 128, 128, 128, 128, 128},
+			{149, 1, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+		},
+		{
+			{1, 226, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+			{247, 192, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+			{240, 128, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+		},
+		{
+			{1, 134, 252, 255, 255, 128, 128, 128, 128, 128, 128},
+			{213, 62, 250, 255, 255, 128, 128, 128, 128, 128, 128},
+			{55, 93, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+		},
+		{
+			{128, 128, 128, 128, 128, 128, 128, 128, 128, 128, 128},
+			{128, 128, 128, 128, 128, 128, 128, 128, 128, 128, 128},
+			{128, 128, 128, 128, 128, 128, 128, 128, 128, 128, 128},
+		},
+	},
+	{
+		{
+			{202, 24, 213, 235, 186, 191, 220, 160, 240, 175, 255},
+			{126, 38, 182, 232, 169, 184, 228, 174, 255, 187, 128},
+			{61, 46, 138, 219, 151, 178, 240, 170, 255, 216, 128},
+		},
+		{
+			{1, 112, 230, 250, 199, 191, 247, 159, 255, 255, 128},
+			{166, 109, 228, 252, 211, 215, 255, 174, 128, 128, 128},
+			{39, 77, 162, 232, 172, 180, 245, 178, 255, 255, 128},
+		},
+		{
+			{1, 52, 220, 246, 198, 199, 249, 220, 255, 255, 128},
+			{124, 74, 191, 243, 183, 193, 250, 221, 255, 255, 128},
+			{24, 71, 130, 219, 154, 170, 243, 182, 255, 255, 128},
+		},
+		{
+			{1, 182, 225, 249, 219, 240, 255, 224, 128, 128, 128},
+			{149, 150, 226, 252, 216, 205, 255, 171, 128, 128, 128},
+			{28, 108, 170, 242, 183, 194, 254, 223, 255, 255, 128},
+		},
+		{
+			{1, 81, 230, 252, 204, 203, 255, 192, 128, 128, 128},
+			{123, 102, 209, 247, 188, 196, 255, 233, 128, 128, 128},
+			{20, 95, 153, 243, 164, 173, 255, 203, 128, 128, 128},
+		},
+		{
+			{1, 222, 248, 255, 216, 213, 128, 128, 128, 128, 128},
+			{168, 175, 246, 252, 235, 205, 255, 255, 128, 128, 128},
+			{47, 116, 215, 255, 211, 212, 255, 255, 128, 128, 128},
+		},
+		{
+			{1, 121, 236, 253, 212, 214, 255, 255, 128, 128, 128},
+			{141, 84, 213, 252, 201, 202, 255, 219, 128, 128, 128},
+			{42, 80, 160, 240, 162, 185, 255, 205, 128, 128, 128},
+		},
+		{
+			{1, 1, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+			{244, 1, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+			{238, 1, 255, 128, 128, 128, 128, 128, 128, 128, 128},
+		},
+	},
+}
+
+// vp8DequantDC and vp8DequantAC map a quantizer index to the DC and AC
+// dequantization factors (section 14.1).
+var vp8DequantDC = [128]uint16{
+	4, 5, 6, 7, 8, 9, 10, 10,
+	11, 12, 13, 14, 15, 16, 17, 17,
+	18, 19, 20, 20, 21, 21, 22, 22,
+	23, 23, 24, 25, 25, 26, 27, 28,
+	29, 30, 31, 32, 33, 34, 35, 36,
+	37, 37, 38, 39, 40, 41, 42, 43,
+	44, 45, 46, 46, 47, 48, 49, 50,
+	51, 52, 53, 54, 55, 56, 57, 58,
+	59, 60, 61, 62, 63, 64, 65, 66,
+	67, 68, 69, 70, 71, 72, 73, 74,
+	75, 76, 76, 77, 78, 79, 80, 81,
+	82, 83, 84, 85, 86, 87, 88, 89,
+	91, 93, 95, 96, 98, 100, 101, 102,
+	104, 106, 108, 110, 112, 114, 116, 118,
+	122, 124, 126, 128, 130, 132, 134, 136,
+	138, 140, 143, 145, 148, 151, 154, 157,
+}
+
+var vp8DequantAC = [128]uint16{
+	4, 5, 6, 7, 8, 9, 10, 11,
+	12, 13, 14, 15, 16, 17, 18, 19,
+	20, 21, 22, 23, 24, 25, 26, 27,
+	28, 29, 30, 31, 32, 33, 34, 35,
+	36, 37, 38, 39, 40, 41, 42, 43,
+	44, 45, 46, 47, 48, 49, 50, 51,
+	52, 53, 54, 55, 56, 57, 58, 60,
+	62, 64, 66, 68, 70, 72, 74, 76,
+	78, 80, 82, 84, 86, 88, 90, 92,
+	94, 96, 98, 100, 102, 104, 106, 108,
+	110, 112, 114, 116, 119, 122, 125, 128,
+	131, 134, 137, 140, 143, 146, 149, 152,
+	155, 158, 161, 164, 167, 170, 173, 177,
+	181, 185, 189, 193, 197, 201, 205, 209,
+	213, 217, 221, 225, 229, 234, 239, 245,
+	249, 254, 259, 264, 269, 274, 279, 284,
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func TestEncodeWebP(t *testing.T) {
+	// Sizes that are and are not whole macroblocks.
+	for _, size := range []image.Point{{1, 1}, {17, 33}, {64, 48}, {300, 300}} {
+		img := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+		for y := 0; y < size.Y; y++ {
+			for x := 0; x < size.X; x++ {
+				img.SetRGBA(x, y, color.RGBA{
+					R: uint8(x * 255 / size.X),
+					G: uint8(y * 255 / size.Y),
+					B: uint8(128 + 100*math.Sin(float64(x*y)/500)),
+					A: 255,
+				})
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := encodeWebP(&buf, img); err != nil {
+			t.Fatalf("%v: encode: %v", size, err)
+		}
+		if buf.Len()%2 != 0 {
+			t.Fatalf("%v: expected an even RIFF length, got %d", size, buf.Len())
+		}
+		got, err := webp.Decode(&buf)
+		if err != nil {
+			t.Fatalf("%v: decode: %v", size, err)
+		}
+		if b := got.Bounds(); b.Dx() != size.X || b.Dy() != size.Y {
+			t.Fatalf("%v: expected %dx%d, got %dx%d", size, size.X, size.Y, b.Dx(), b.Dy())
+		}
+
+		// Compare luma with the encoder's own input, since decoders differ
+		// in how they convert it back to RGB.
+		want := newVP8Encoder(img, webpQuantizer)
+		ycc := got.(*image.YCbCr)
+		var sse float64
+		for y := 0; y < size.Y; y++ {
+			for x := 0; x < size.X; x++ {
+				d := float64(want.src[0][y*want.stride(0)+x]) - float64(ycc.Y[ycc.YOffset(x, y)])
+				sse += d * d
+			}
+		}
+		if sse == 0 {
+			continue
+		}
+		if psnr := 10 * math.Log10(255*255*float64(size.X*size.Y)/sse); psnr < 35 {
+			t.Fatalf("%v: expected a PSNR of at least 35 dB, got %.1f", size, psnr)
+		}
+	}
+}
+
+func TestEncodeWebP_RejectsSize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, maxWebPSize+1, 1))
+	if err := encodeWebP(&bytes.Buffer{}, img); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestBoolEncoder_Carry(t *testing.T) {
+	// Long runs of unlikely bits force carries through written bytes.
+	e := newBoolEncoder()
+	bits := make([]bool, 5000)
+	for i := range bits {
+		bits[i] = i%7 != 0
+	}
+	for _, b := range bits {
+		e.put(b, 250)
+	}
+	data := e.flush()
+
+	// Decode as RFC 6386 section 7.3 does.
+	value, rng, bitCount, pos := uint32(0), uint32(255), 0, 0
+	for ; pos < 2; pos++ {
+		value = value<<8 | uint32(data[pos])
+	}
+	for i, want := range bits {
+		split := 1 + (rng-1)*250>>8
+		bigSplit := split << 8
+		got := value >= bigSplit
+		if got {
+			rng -= split
+			value -= bigSplit
+		} else {
+			rng = split
+		}
+		for rng < 128 {
+			value <<= 1
+			rng <<= 1
+			if bitCount++; bitCount == 8 {
+				bitCount = 0
+				if pos < len(data) {
+					value |= uint32(data[pos])
+				}
+				pos++
+			}
+		}
+		if got != want {
+			t.Fatalf("bit %d: expected %v, got %v", i, want, got)
+		}
+	}
+}
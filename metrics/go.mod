@@ -0,0 +1,3 @@
+module github.com/ewilliams-labs/overture/metrics
+
+go 1.25.7
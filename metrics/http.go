@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	httpRequestDuration = NewHistogramVec(
+		"overture_http_request_duration_seconds",
+		"Latency of served HTTP requests, by method, route pattern and status. Streams are measured until they end.",
+		DefaultBuckets,
+		"method", "route", "code",
+	)
+	httpInFlight = NewGaugeVec(
+		"overture_http_requests_in_flight",
+		"HTTP requests being served.",
+	)
+)
+
+// routeKey holds the *string WithRoute sets when the request it sees is a
+// copy, as behind http.StripPrefix, whose pattern never reaches the outer
+// request.
+type routeKey struct{}
+
+// InstrumentHandler records the latency and status of every request next
+// serves. Requests are labeled with the route pattern the ServeMux matched,
+// e.g. /playlists/{id}, or "unmatched", so raw paths never become labels.
+func InstrumentHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		httpInFlight.Add(1)
+		defer httpInFlight.Add(-1)
+
+		r = r.WithContext(context.WithValue(r.Context(), routeKey{}, new(string)))
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			httpRequestDuration.Observe(time.Since(start).Seconds(), r.Method, RouteFor(r), strconv.Itoa(sw.status))
+		}()
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// WithRoute labels requests to next with prefix and the pattern matched by
+// the mux serving next, for muxes mounted behind http.StripPrefix. Wrap the
+// handlers registered on that mux, not the mux itself.
+func WithRoute(prefix string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route, ok := r.Context().Value(routeKey{}).(*string); ok {
+			*route = prefix + RouteLabel(r.Pattern)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RouteFor returns the route label of a request served through
+// InstrumentHandler: the one set by WithRoute, or else the pattern the mux
+// matched. The mux sets r.Pattern while routing; nested muxes overwrite it
+// with the innermost match.
+func RouteFor(r *http.Request) string {
+	if route, ok := r.Context().Value(routeKey{}).(*string); ok && *route != "" {
+		return *route
+	}
+	return RouteLabel(r.Pattern)
+}
+
+// RouteLabel reduces a ServeMux pattern such as "GET /playlists/{id}" to its
+// path. An empty pattern, as left by a 404, is "unmatched".
+func RouteLabel(pattern string) string {
+	if pattern == "" {
+		return "unmatched"
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+// statusWriter remembers the status code written through it. Unwrap lets
+// http.ResponseController reach the underlying writer to flush streams.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInstrumentHandler(t *testing.T) {
+	inner := http.NewServeMux()
+	inner.HandleFunc("GET /playlists/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush through the instrumented writer: %v", err)
+		}
+	})
+	inner.HandleFunc("POST /playlists", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	outer := http.NewServeMux()
+	outer.Handle("/", inner)
+	h := InstrumentHandler(outer)
+
+	tests := []struct {
+		method, path        string
+		wantRoute, wantCode string
+	}{
+		{http.MethodGet, "/playlists/abc", "/playlists/{id}", "200"},
+		{http.MethodPost, "/playlists", "/playlists", "201"},
+		{http.MethodGet, "/nope/123", "unmatched", "404"},
+	}
+	for _, tt := range tests {
+		before := httpRequestDuration.Count(tt.method, tt.wantRoute, tt.wantCode)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+		if got := httpRequestDuration.Count(tt.method, tt.wantRoute, tt.wantCode) - before; got != 1 {
+			t.Errorf("%s %s: observed %d requests for route %q code %s, want 1", tt.method, tt.path, got, tt.wantRoute, tt.wantCode)
+		}
+	}
+	if got := httpInFlight.Value(); got != 0 {
+		t.Errorf("in flight = %v after requests finished, want 0", got)
+	}
+}
+
+func TestInstrumentHandler_WithRoute(t *testing.T) {
+	api := http.NewServeMux()
+	api.Handle("GET /playlists/{id}", WithRoute("/api", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	outer := http.NewServeMux()
+	outer.Handle("/api/", http.StripPrefix("/api", api))
+	var route string
+	h := InstrumentHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outer.ServeHTTP(w, r)
+		route = RouteFor(r)
+	}))
+
+	before := httpRequestDuration.Count(http.MethodGet, "/api/playlists/{id}", "200")
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/playlists/abc", nil))
+	if got := httpRequestDuration.Count(http.MethodGet, "/api/playlists/{id}", "200") - before; got != 1 {
+		t.Errorf("observed %d requests for the nested route, want 1", got)
+	}
+	if route != "/api/playlists/{id}" {
+		t.Errorf("RouteFor = %q, want /api/playlists/{id}", route)
+	}
+}
@@ -0,0 +1,305 @@
+// Package metrics provides a small in-process metrics registry rendered in
+// the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// collector is implemented by every metric family in a Registry.
+type collector interface {
+	describe() (name, help, kind string)
+	write(w io.Writer)
+}
+
+// Registry holds metric families and renders them for scraping.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]struct{}
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]struct{})}
+}
+
+// Default is the process-wide registry used by the package-level constructors.
+var Default = NewRegistry()
+
+func (r *Registry) register(c collector) {
+	name, _, _ := c.describe()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.names[name]; dup {
+		panic("metrics: duplicate metric " + name)
+	}
+	r.names[name] = struct{}{}
+	r.collectors = append(r.collectors, c)
+}
+
+// Render writes every registered family in registration order.
+func (r *Registry) Render(w io.Writer) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	for _, c := range collectors {
+		name, help, kind := c.describe()
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		c.write(w)
+	}
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Render(w)
+	})
+}
+
+// Handler serves the Default registry.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// family stores one value per label combination.
+type family[T any] struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*T
+	keys   map[string][]string
+}
+
+func newFamily[T any](name, help string, labels []string) family[T] {
+	return family[T]{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: make(map[string]*T),
+		keys:   make(map[string][]string),
+	}
+}
+
+// get returns the series for values, creating it with init on first use.
+// Callers must hold f.mu.
+func (f *family[T]) get(values []string, init func() *T) *T {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = init()
+		f.series[key] = s
+		f.keys[key] = append([]string(nil), values...)
+	}
+	return s
+}
+
+// sortedKeys returns series keys in a stable order. Callers must hold f.mu.
+func (f *family[T]) sortedKeys() []string {
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *family[T]) labelString(values []string, extra ...string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, v := range values {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", f.labels[i], v))
+	}
+	pairs = append(pairs, extra...)
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// CounterVec is a monotonically increasing value partitioned by labels.
+type CounterVec struct {
+	family[float64]
+}
+
+// NewCounterVec registers a counter family in the Default registry.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return Default.NewCounterVec(name, help, labels...)
+}
+
+// NewCounterVec registers a counter family in r.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{newFamily[float64](name, help, labels)}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the series identified by values.
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds delta (which must be non-negative) to the series.
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.get(values, func() *float64 { return new(float64) }) += delta
+}
+
+// Value returns the current value of a series, mainly for tests.
+func (c *CounterVec) Value(values ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return *c.get(values, func() *float64 { return new(float64) })
+}
+
+func (c *CounterVec) describe() (string, string, string) { return c.name, c.help, "counter" }
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range c.sortedKeys() {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelString(c.keys[k]), formatFloat(*c.series[k]))
+	}
+}
+
+// GaugeVec is a value that can go up and down, partitioned by labels.
+type GaugeVec struct {
+	family[float64]
+}
+
+// NewGaugeVec registers a gauge family in the Default registry.
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return Default.NewGaugeVec(name, help, labels...)
+}
+
+// NewGaugeVec registers a gauge family in r.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{newFamily[float64](name, help, labels)}
+	r.register(g)
+	return g
+}
+
+// Set replaces the value of the series identified by values.
+func (g *GaugeVec) Set(v float64, values ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	*g.get(values, func() *float64 { return new(float64) }) = v
+}
+
+// Add adjusts the series by delta, which may be negative.
+func (g *GaugeVec) Add(delta float64, values ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	*g.get(values, func() *float64 { return new(float64) }) += delta
+}
+
+// Value returns the current value of a series, mainly for tests.
+func (g *GaugeVec) Value(values ...string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return *g.get(values, func() *float64 { return new(float64) })
+}
+
+func (g *GaugeVec) describe() (string, string, string) { return g.name, g.help, "gauge" }
+
+func (g *GaugeVec) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, k := range g.sortedKeys() {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelString(g.keys[k]), formatFloat(*g.series[k]))
+	}
+}
+
+// DefaultBuckets suit request latencies measured in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// HistogramVec samples observations into cumulative buckets, partitioned by labels.
+type HistogramVec struct {
+	family[histogram]
+	buckets []float64
+}
+
+// NewHistogramVec registers a histogram family in the Default registry.
+// A nil buckets slice uses DefaultBuckets.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return Default.NewHistogramVec(name, help, buckets, labels...)
+}
+
+// NewHistogramVec registers a histogram family in r.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	h := &HistogramVec{family: newFamily[histogram](name, help, labels), buckets: buckets}
+	r.register(h)
+	return h
+}
+
+// Observe records v in the series identified by values.
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.get(values, func() *histogram { return &histogram{counts: make([]uint64, len(h.buckets))} })
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.counts[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+// Count returns the number of observations in a series, mainly for tests.
+func (h *HistogramVec) Count(values ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.get(values, func() *histogram { return &histogram{counts: make([]uint64, len(h.buckets))} }).count
+}
+
+func (h *HistogramVec) describe() (string, string, string) { return h.name, h.help, "histogram" }
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, k := range h.sortedKeys() {
+		s := h.series[k]
+		values := h.keys[k]
+		for i, upper := range h.buckets {
+			le := fmt.Sprintf("le=%q", formatFloat(upper))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(values, le), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(values, `le="+Inf"`), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelString(values), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelString(values), s.count)
+	}
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Render(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(r *Registry)
+		want   []string
+	}{
+		{
+			name: "counter with labels",
+			record: func(r *Registry) {
+				c := r.NewCounterVec("test_requests_total", "Requests.", "route")
+				c.Inc("/health")
+				c.Add(2, "/health")
+			},
+			want: []string{
+				"# TYPE test_requests_total counter",
+				`test_requests_total{route="/health"} 3`,
+			},
+		},
+		{
+			name: "gauge without labels",
+			record: func(r *Registry) {
+				g := r.NewGaugeVec("test_queue_depth", "Depth.")
+				g.Set(5)
+				g.Add(-2)
+			},
+			want: []string{
+				"# TYPE test_queue_depth gauge",
+				"test_queue_depth 3",
+			},
+		},
+		{
+			name: "histogram buckets are cumulative",
+			record: func(r *Registry) {
+				h := r.NewHistogramVec("test_latency_seconds", "Latency.", []float64{0.1, 1}, "op")
+				h.Observe(0.05, "get")
+				h.Observe(0.5, "get")
+				h.Observe(5, "get")
+			},
+			want: []string{
+				"# TYPE test_latency_seconds histogram",
+				`test_latency_seconds_bucket{op="get",le="0.1"} 1`,
+				`test_latency_seconds_bucket{op="get",le="1"} 2`,
+				`test_latency_seconds_bucket{op="get",le="+Inf"} 3`,
+				`test_latency_seconds_sum{op="get"} 5.55`,
+				`test_latency_seconds_count{op="get"} 3`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			tt.record(r)
+
+			var buf bytes.Buffer
+			r.Render(&buf)
+			for _, line := range tt.want {
+				if !strings.Contains(buf.String(), line+"\n") {
+					t.Errorf("output missing %q:\n%s", line, buf.String())
+				}
+			}
+		})
+	}
+}